@@ -0,0 +1,590 @@
+package gno
+
+// Bytecode compilation of function bodies.
+//
+// Walking the AST costs several machine ops per node, even for simple
+// arithmetic and loops. Functions whose bodies are pure computations over
+// integers and booleans are lowered on first call into a compact register
+// bytecode, which is run by a single loop in execBytecode().
+//
+// A function is eligible if its parameters, results, and locals are all of
+// type int, int64, or bool, and its body only uses constants, names local to
+// the function, unary and binary operators (except shifts), assignments,
+// inc/dec, if/else, blocks, three-clause for loops with unlabeled
+// break/continue, and return. In particular it calls no functions and
+// refers to no package or captured variables, so running it has no effect
+// other than its results. All other functions are interpreted as before.
+//
+// Since an eligible function has no side effects, execution can fall back
+// to the interpreter at any point; this is done for integer division by
+// zero, so that it fails exactly as it would have when interpreted.
+//
+// NOTE: each bytecode instruction is charged one cpu cycle, in place of the
+// cycles of the ops it replaces. Whether a function is compiled depends only
+// on its source, so execution remains deterministic.
+
+type bcOp uint8
+
+const (
+	bcConst     bcOp = iota // r[a] = consts[b]
+	bcMove                  // r[a] = r[b]
+	bcAdd                   // r[a] = r[b] + r[c]
+	bcSub                   // r[a] = r[b] - r[c]
+	bcMul                   // r[a] = r[b] * r[c]
+	bcQuo                   // r[a] = r[b] / r[c]
+	bcRem                   // r[a] = r[b] % r[c]
+	bcBand                  // r[a] = r[b] & r[c]
+	bcBor                   // r[a] = r[b] | r[c]
+	bcXor                   // r[a] = r[b] ^ r[c]
+	bcBandn                 // r[a] = r[b] &^ r[c]
+	bcEql                   // r[a] = r[b] == r[c]
+	bcNeq                   // r[a] = r[b] != r[c]
+	bcLss                   // r[a] = r[b] < r[c]
+	bcLeq                   // r[a] = r[b] <= r[c]
+	bcGtr                   // r[a] = r[b] > r[c]
+	bcGeq                   // r[a] = r[b] >= r[c]
+	bcNeg                   // r[a] = -r[b]
+	bcNot                   // r[a] = !r[b]
+	bcCompl                 // r[a] = ^r[b]
+	bcInc                   // r[a]++
+	bcDec                   // r[a]--
+	bcJump                  // goto a
+	bcJumpIf                // if r[b] { goto a }
+	bcJumpIfNot             // if !r[b] { goto a }
+	bcReturn                // return
+)
+
+type bcInst struct {
+	op      bcOp
+	a, b, c int
+}
+
+// The compiled form of a function body. Registers are int64s, with bools
+// represented as 0 or 1. Parameters are in the first registers, followed
+// by results.
+type bytecode struct {
+	code    []bcInst
+	consts  []int64
+	numRegs int
+	params  []Type
+	results []Type
+}
+
+// Returns the bytecode for the body of fv, compiling it on first use, or
+// nil if fv is native or not eligible for compilation, or if bytecode is
+// disabled (as when counting coverage or debugging).
+func (m *Machine) getBytecode(fv *FuncValue) *bytecode {
+	if fv.nativeBody != nil || m.NoBytecode || m.Coverage != nil || m.Debugger != nil {
+		return nil
+	}
+	source := fv.GetSource(m.Store)
+	if bc, ok := source.GetAttribute(ATTR_BYTECODE).(*bytecode); ok {
+		return bc
+	} else if source.HasAttribute(ATTR_BYTECODE) {
+		return nil // not eligible.
+	}
+	bc := compileBytecode(source, fv.GetType(m.Store))
+	if bc == nil {
+		source.SetAttribute(ATTR_BYTECODE, nil)
+	} else {
+		source.SetAttribute(ATTR_BYTECODE, bc)
+	}
+	return bc
+}
+
+// Runs bc with the parameters in the last block, and pushes its results
+// onto the value stack. Returns false without pushing anything if the
+// body must be interpreted instead.
+func (m *Machine) execBytecode(bc *bytecode) bool {
+	b := m.LastBlock()
+	r := make([]int64, bc.numRegs)
+	for i, pt := range bc.params {
+		r[i] = bcGet(&b.Values[i], pt)
+	}
+	code, consts := bc.code, bc.consts
+	cycles := int64(0)
+	for pc := 0; ; pc++ {
+		in := code[pc]
+		cycles++
+		switch in.op {
+		case bcConst:
+			r[in.a] = consts[in.b]
+		case bcMove:
+			r[in.a] = r[in.b]
+		case bcAdd:
+			r[in.a] = r[in.b] + r[in.c]
+		case bcSub:
+			r[in.a] = r[in.b] - r[in.c]
+		case bcMul:
+			r[in.a] = r[in.b] * r[in.c]
+		case bcQuo:
+			if r[in.c] == 0 {
+				m.incrCPU(cycles)
+				return false
+			}
+			r[in.a] = r[in.b] / r[in.c]
+		case bcRem:
+			if r[in.c] == 0 {
+				m.incrCPU(cycles)
+				return false
+			}
+			r[in.a] = r[in.b] % r[in.c]
+		case bcBand:
+			r[in.a] = r[in.b] & r[in.c]
+		case bcBor:
+			r[in.a] = r[in.b] | r[in.c]
+		case bcXor:
+			r[in.a] = r[in.b] ^ r[in.c]
+		case bcBandn:
+			r[in.a] = r[in.b] &^ r[in.c]
+		case bcEql:
+			r[in.a] = bcBool(r[in.b] == r[in.c])
+		case bcNeq:
+			r[in.a] = bcBool(r[in.b] != r[in.c])
+		case bcLss:
+			r[in.a] = bcBool(r[in.b] < r[in.c])
+		case bcLeq:
+			r[in.a] = bcBool(r[in.b] <= r[in.c])
+		case bcGtr:
+			r[in.a] = bcBool(r[in.b] > r[in.c])
+		case bcGeq:
+			r[in.a] = bcBool(r[in.b] >= r[in.c])
+		case bcNeg:
+			r[in.a] = -r[in.b]
+		case bcNot:
+			r[in.a] = 1 - r[in.b]
+		case bcCompl:
+			r[in.a] = ^r[in.b]
+		case bcInc:
+			r[in.a]++
+		case bcDec:
+			r[in.a]--
+		case bcJump:
+			if in.a <= pc {
+				// charge loops as they go, so that
+				// MaxCycles and gas limits can halt them.
+				m.incrCPU(cycles)
+				cycles = 0
+			}
+			pc = in.a - 1
+		case bcJumpIf:
+			if r[in.b] != 0 {
+				pc = in.a - 1
+			}
+		case bcJumpIfNot:
+			if r[in.b] == 0 {
+				pc = in.a - 1
+			}
+		case bcReturn:
+			m.incrCPU(cycles)
+			numParams := len(bc.params)
+			for i, rt := range bc.results {
+				tv := TypedValue{T: rt}
+				bcSet(&tv, r[numParams+i])
+				m.PushValue(tv)
+			}
+			return true
+		default:
+			panic("should not happen")
+		}
+	}
+}
+
+func bcBool(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func bcGet(tv *TypedValue, t Type) int64 {
+	switch t {
+	case IntType:
+		return int64(tv.GetInt())
+	case Int64Type:
+		return tv.GetInt64()
+	case BoolType, UntypedBoolType:
+		return bcBool(tv.GetBool())
+	default:
+		panic("should not happen")
+	}
+}
+
+func bcSet(tv *TypedValue, n int64) {
+	switch tv.T {
+	case IntType:
+		tv.SetInt(int(n))
+	case Int64Type:
+		tv.SetInt64(n)
+	case BoolType:
+		tv.SetBool(n != 0)
+	default:
+		panic("should not happen")
+	}
+}
+
+func isBytecodeType(t Type) bool {
+	switch t {
+	case IntType, Int64Type, BoolType:
+		return true
+	default:
+		return false
+	}
+}
+
+//----------------------------------------
+// bcCompiler
+
+// Returned (via panic) when a function is not eligible.
+type bcIneligible struct{}
+
+type bcLoop struct {
+	breaks    []int // jumps to patch to the end of the loop
+	continues []int // jumps to patch to the post statement
+}
+
+type bcCompiler struct {
+	bc     *bytecode
+	scopes [][]int // register of each name, per block
+	loops  []*bcLoop
+}
+
+// Compiles the body of the function declared by source, or returns nil if
+// it is not eligible.
+func compileBytecode(source BlockNode, ft *FuncType) (bc *bytecode) {
+	switch source.(type) {
+	case *FuncDecl, *FuncLitExpr:
+	default:
+		return nil
+	}
+	if ft.HasVarg() {
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bcIneligible); ok {
+				bc = nil
+				return
+			}
+			panic(r)
+		}
+	}()
+	c := &bcCompiler{bc: &bytecode{}}
+	for _, pt := range ft.Params {
+		c.checkType(pt.Type)
+		c.bc.params = append(c.bc.params, pt.Type)
+	}
+	for _, rt := range ft.Results {
+		c.checkType(rt.Type)
+		c.bc.results = append(c.bc.results, rt.Type)
+	}
+	// params and results are the first names of the function block.
+	c.pushScope(source, nil)
+	c.compileBody(source.GetBody())
+	c.emit(bcReturn, 0, 0, 0)
+	return c.bc
+}
+
+func (c *bcCompiler) fail() {
+	panic(bcIneligible{})
+}
+
+func (c *bcCompiler) checkType(t Type) {
+	if !isBytecodeType(t) {
+		c.fail()
+	}
+}
+
+func (c *bcCompiler) newReg() int {
+	c.bc.numRegs++
+	return c.bc.numRegs - 1
+}
+
+func (c *bcCompiler) emit(op bcOp, a, b, cc int) int {
+	c.bc.code = append(c.bc.code, bcInst{op: op, a: a, b: b, c: cc})
+	return len(c.bc.code) - 1
+}
+
+func (c *bcCompiler) pc() int {
+	return len(c.bc.code)
+}
+
+func (c *bcCompiler) patch(jump int, target int) {
+	c.bc.code[jump].a = target
+}
+
+// Allocates registers for the names of bn. The first names alias the
+// registers of inherit, for if-case blocks which share the block of their
+// if statement at runtime.
+func (c *bcCompiler) pushScope(bn BlockNode, inherit []int) {
+	sb := bn.GetStaticBlock()
+	regs := make([]int, sb.NumNames)
+	for i := range regs {
+		if i < len(inherit) {
+			regs[i] = inherit[i]
+			continue
+		}
+		// locals defined from comparisons are untyped bools.
+		if t := sb.Types[i]; t != UntypedBoolType {
+			c.checkType(t)
+		}
+		regs[i] = c.newReg()
+	}
+	c.scopes = append(c.scopes, regs)
+}
+
+func (c *bcCompiler) popScope() []int {
+	regs := c.scopes[len(c.scopes)-1]
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	return regs
+}
+
+// Returns the register for nx, which must name a local of the function.
+func (c *bcCompiler) nameReg(nx *NameExpr) int {
+	if nx.Path.Type != VPBlock {
+		c.fail()
+	}
+	depth := int(nx.Path.Depth)
+	if depth < 1 || len(c.scopes) < depth {
+		c.fail() // blank, or not local.
+	}
+	regs := c.scopes[len(c.scopes)-depth]
+	if len(regs) <= int(nx.Path.Index) {
+		c.fail()
+	}
+	return regs[nx.Path.Index]
+}
+
+func (c *bcCompiler) compileBody(body Body) {
+	for _, s := range body {
+		c.compileStmt(s)
+	}
+}
+
+func (c *bcCompiler) compileStmt(s Stmt) {
+	switch s := s.(type) {
+	case *AssignStmt:
+		c.compileAssign(s)
+	case *IncDecStmt:
+		nx, ok := s.X.(*NameExpr)
+		if !ok {
+			c.fail()
+		}
+		reg := c.nameReg(nx)
+		if s.Op == INC {
+			c.emit(bcInc, reg, 0, 0)
+		} else {
+			c.emit(bcDec, reg, 0, 0)
+		}
+	case *BlockStmt:
+		c.pushScope(s, nil)
+		c.compileBody(s.Body)
+		c.popScope()
+	case *IfStmt:
+		c.pushScope(s, nil)
+		if s.Init != nil {
+			c.compileStmt(s.Init)
+		}
+		cond := c.compileExpr(s.Cond)
+		toElse := c.emit(bcJumpIfNot, 0, cond, 0)
+		// if-case blocks replace the if block.
+		ifRegs := c.popScope()
+		c.pushScope(&s.Then, ifRegs)
+		c.compileBody(s.Then.Body)
+		c.popScope()
+		toEnd := c.emit(bcJump, 0, 0, 0)
+		c.patch(toElse, c.pc())
+		c.pushScope(&s.Else, ifRegs)
+		c.compileBody(s.Else.Body)
+		c.popScope()
+		c.patch(toEnd, c.pc())
+	case *ForStmt:
+		if s.GetLabel() != "" {
+			c.fail()
+		}
+		c.pushScope(s, nil)
+		if s.Init != nil {
+			c.compileStmt(s.Init)
+		}
+		loop := &bcLoop{}
+		c.loops = append(c.loops, loop)
+		start := c.pc()
+		if s.Cond != nil {
+			cond := c.compileExpr(s.Cond)
+			loop.breaks = append(loop.breaks, c.emit(bcJumpIfNot, 0, cond, 0))
+		}
+		c.compileBody(s.Body)
+		post := c.pc()
+		if s.Post != nil {
+			c.compileStmt(s.Post)
+		}
+		c.emit(bcJump, start, 0, 0)
+		end := c.pc()
+		for _, j := range loop.breaks {
+			c.patch(j, end)
+		}
+		for _, j := range loop.continues {
+			c.patch(j, post)
+		}
+		c.loops = c.loops[:len(c.loops)-1]
+		c.popScope()
+	case *BranchStmt:
+		if s.Label != "" || len(c.loops) == 0 {
+			c.fail()
+		}
+		loop := c.loops[len(c.loops)-1]
+		switch s.Op {
+		case BREAK:
+			loop.breaks = append(loop.breaks, c.emit(bcJump, 0, 0, 0))
+		case CONTINUE:
+			loop.continues = append(loop.continues, c.emit(bcJump, 0, 0, 0))
+		default:
+			c.fail()
+		}
+	case *ReturnStmt:
+		if len(s.Results) != 0 {
+			if len(s.Results) != len(c.bc.results) {
+				c.fail() // e.g. return f()
+			}
+			regs := c.compileExprs(s.Results)
+			numParams := len(c.bc.params)
+			for i, reg := range regs {
+				c.emit(bcMove, numParams+i, reg, 0)
+			}
+		}
+		c.emit(bcReturn, 0, 0, 0)
+	default:
+		c.fail()
+	}
+}
+
+func (c *bcCompiler) compileAssign(s *AssignStmt) {
+	switch s.Op {
+	case DEFINE, ASSIGN:
+		if len(s.Lhs) != len(s.Rhs) {
+			c.fail()
+		}
+		// evaluate all right hand sides first, for a, b = b, a.
+		regs := c.compileExprs(s.Rhs)
+		for i, lx := range s.Lhs {
+			nx, ok := lx.(*NameExpr)
+			if !ok {
+				c.fail()
+			}
+			if nx.Name == "_" {
+				continue
+			}
+			c.emit(bcMove, c.nameReg(nx), regs[i], 0)
+		}
+	default:
+		op, ok := bcAssignOps[s.Op]
+		if !ok || len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+			c.fail()
+		}
+		nx, ok := s.Lhs[0].(*NameExpr)
+		if !ok {
+			c.fail()
+		}
+		reg := c.nameReg(nx)
+		c.emit(op, reg, reg, c.compileExpr(s.Rhs[0]))
+	}
+}
+
+// Compiles each of xs into a register not shared with any name.
+func (c *bcCompiler) compileExprs(xs []Expr) []int {
+	regs := make([]int, len(xs))
+	for i, x := range xs {
+		reg := c.compileExpr(x)
+		if _, ok := x.(*NameExpr); ok {
+			tmp := c.newReg()
+			c.emit(bcMove, tmp, reg, 0)
+			reg = tmp
+		}
+		regs[i] = reg
+	}
+	return regs
+}
+
+var bcBinaryOps = map[Word]bcOp{
+	ADD: bcAdd, SUB: bcSub, MUL: bcMul, QUO: bcQuo, REM: bcRem,
+	BAND: bcBand, BOR: bcBor, XOR: bcXor, BAND_NOT: bcBandn,
+	EQL: bcEql, NEQ: bcNeq, LSS: bcLss, LEQ: bcLeq, GTR: bcGtr, GEQ: bcGeq,
+}
+
+var bcAssignOps = map[Word]bcOp{
+	ADD_ASSIGN: bcAdd, SUB_ASSIGN: bcSub, MUL_ASSIGN: bcMul,
+	QUO_ASSIGN: bcQuo, REM_ASSIGN: bcRem, BAND_ASSIGN: bcBand,
+	BOR_ASSIGN: bcBor, XOR_ASSIGN: bcXor, BAND_NOT_ASSIGN: bcBandn,
+}
+
+// Returns the register holding the value of x. Names evaluate to their
+// own register, which must not be written to.
+func (c *bcCompiler) compileExpr(x Expr) int {
+	switch x := x.(type) {
+	case *NameExpr:
+		return c.nameReg(x)
+	case *ConstExpr:
+		if !isBytecodeType(x.T) && x.T != UntypedBoolType {
+			c.fail()
+		}
+		reg := c.newReg()
+		c.bc.consts = append(c.bc.consts, bcGet(&x.TypedValue, x.T))
+		c.emit(bcConst, reg, len(c.bc.consts)-1, 0)
+		return reg
+	case *UnaryExpr:
+		xreg := c.compileExpr(x.X)
+		switch x.Op {
+		case ADD:
+			reg := c.newReg()
+			c.emit(bcMove, reg, xreg, 0)
+			return reg
+		case SUB:
+			reg := c.newReg()
+			c.emit(bcNeg, reg, xreg, 0)
+			return reg
+		case NOT:
+			reg := c.newReg()
+			c.emit(bcNot, reg, xreg, 0)
+			return reg
+		case XOR:
+			reg := c.newReg()
+			c.emit(bcCompl, reg, xreg, 0)
+			return reg
+		}
+	case *CallExpr:
+		// conversions between int, int64, and bool do not change
+		// registers, and are inserted by the preprocessor for
+		// comparisons.
+		ctx, ok := x.Func.(*constTypeExpr)
+		if !ok || !isBytecodeType(ctx.Type) || len(x.Args) != 1 || x.Varg {
+			c.fail()
+		}
+		return c.compileExpr(x.Args[0])
+	case *BinaryExpr:
+		switch x.Op {
+		case LAND, LOR:
+			reg := c.newReg()
+			c.emit(bcMove, reg, c.compileExpr(x.Left), 0)
+			var skip int
+			if x.Op == LAND {
+				skip = c.emit(bcJumpIfNot, 0, reg, 0)
+			} else {
+				skip = c.emit(bcJumpIf, 0, reg, 0)
+			}
+			c.emit(bcMove, reg, c.compileExpr(x.Right), 0)
+			c.patch(skip, c.pc())
+			return reg
+		}
+		op, ok := bcBinaryOps[x.Op]
+		if !ok {
+			c.fail()
+		}
+		lreg := c.compileExpr(x.Left)
+		rreg := c.compileExpr(x.Right)
+		reg := c.newReg()
+		c.emit(op, reg, lreg, rreg)
+		return reg
+	}
+	c.fail()
+	panic("should not happen")
+}
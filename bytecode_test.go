@@ -0,0 +1,194 @@
+package gno
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jaekwon/testify/assert"
+)
+
+const bytecodeSource = `package test
+
+func fib(n int) int {
+	a, b := 0, 1
+	for i := 0; i < n; i++ {
+		a, b = b, a+b
+	}
+	return a
+}
+
+func collatz(n int64) (steps int64) {
+	for n != 1 {
+		if n%2 == 0 {
+			n /= 2
+		} else {
+			n = 3*n + 1
+		}
+		steps++
+	}
+	return
+}
+
+func isPrime(n int) bool {
+	if n < 2 {
+		return false
+	}
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func sumPrimes(n int) int {
+	sum := 0
+	for i := 0; i < n; i++ {
+		ok := i >= 2
+		for d := 2; ok && d*d <= i; d++ {
+			if i%d == 0 {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		sum += i
+	}
+	return sum
+}
+
+func bits(x int) int {
+	y := x &^ 0xff
+	{
+		z := ^y | 0x0f
+		y = -(z ^ x) & 0x7fff
+	}
+	return +y
+}
+
+func div(a, b int) int {
+	return a / b
+}
+
+func greet(n int) string {
+	return "hello"
+}
+`
+
+func newBytecodeMachine(noBytecode bool) *Machine {
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:    "test",
+		Output:     new(bytes.Buffer),
+		NoBytecode: noBytecode,
+	})
+	n := MustParseFile("main.go", bytecodeSource)
+	m.RunFiles(n)
+	return m
+}
+
+func TestBytecodeEligible(t *testing.T) {
+	m := newBytecodeMachine(false)
+	for name, eligible := range map[Name]bool{
+		"fib":       true,
+		"collatz":   true,
+		"isPrime":   true,
+		"sumPrimes": true,
+		"bits":      true,
+		"div":       true,
+		"greet":     false,
+	} {
+		fv := m.Eval(Nx(name))[0].V.(*FuncValue)
+		assert.Equal(t, m.getBytecode(fv) != nil, eligible, string(name))
+	}
+}
+
+// Compiled and interpreted functions must agree on all results.
+func TestBytecodeResults(t *testing.T) {
+	cm := newBytecodeMachine(false)
+	im := newBytecodeMachine(true)
+	for _, call := range [][]string{
+		{"fib", "0"}, {"fib", "1"}, {"fib", "50"}, {"fib", "100"},
+		{"collatz", "1"}, {"collatz", "27"}, {"collatz", "837799"},
+		{"isPrime", "1"}, {"isPrime", "97"}, {"isPrime", "7919"}, {"isPrime", "7917"},
+		{"sumPrimes", "1000"},
+		{"bits", "0"}, {"bits", "12345"}, {"bits", "-99999"},
+		{"div", "7", "-2"}, {"div", "-9223372036854775807", "3"},
+		{"greet", "1"},
+	} {
+		args := make([]interface{}, len(call)-1)
+		for i, arg := range call[1:] {
+			args[i] = arg
+		}
+		cres := cm.Eval(Call(call[0], args...))
+		ires := im.Eval(Call(call[0], args...))
+		assert.Equal(t, cres[0].String(), ires[0].String(), call)
+	}
+}
+
+func TestBytecodeDivideByZero(t *testing.T) {
+	m := newBytecodeMachine(false)
+	assert.Panics(t, func() {
+		m.Eval(Call("div", "1", "0"))
+	})
+}
+
+func TestBytecodeMaxCycles(t *testing.T) {
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:   "test",
+		MaxCycles: 10000,
+	})
+	n := MustParseFile("main.go", `package test
+func loop() int {
+	i := 0
+	for {
+		i++
+	}
+	return i
+}`)
+	m.RunFiles(n)
+	assert.PanicsWithValue(t, func() {
+		m.Eval(Call("loop"))
+	}, "CPU cycle overrun")
+}
+
+//----------------------------------------
+// Benchmarks
+//
+// Each benchmark runs the same call with function bodies compiled to
+// bytecode, and interpreted.
+
+func benchmarkBytecode(b *testing.B, fn string, arg string) {
+	for _, mode := range []struct {
+		name       string
+		noBytecode bool
+	}{
+		{"bytecode", false},
+		{"interpreted", true},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			m := newBytecodeMachine(mode.noBytecode)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Eval(Call(fn, arg))
+			}
+		})
+	}
+}
+
+func BenchmarkBytecodeFib(b *testing.B) {
+	benchmarkBytecode(b, "fib", "90")
+}
+
+func BenchmarkBytecodeCollatz(b *testing.B) {
+	benchmarkBytecode(b, "collatz", "837799")
+}
+
+func BenchmarkBytecodeIsPrime(b *testing.B) {
+	benchmarkBytecode(b, "isPrime", "1000003")
+}
+
+func BenchmarkBytecodeSumPrimes(b *testing.B) {
+	benchmarkBytecode(b, "sumPrimes", "10000")
+}
@@ -0,0 +1,50 @@
+package gno
+
+// CallEvent describes a completed call into a realm, either from outside
+// of any realm (i.e. the entry call of a transaction) or from another realm.
+// Calls between functions of the same realm are not traced.
+type CallEvent struct {
+	Caller string // realm path of caller, or "" if none
+	Realm  string // realm path of callee
+	Func   Name   // name of function or method
+	Depth  int    // number of enclosing calls
+	Cycles int64  // cycles used by call
+}
+
+// IsCrossRealm returns true if the call was made from another realm.
+func (ce CallEvent) IsCrossRealm() bool {
+	return ce.Caller != "" && ce.Caller != ce.Realm
+}
+
+// CallTracer is called by the machine upon the return of each traced call,
+// so inner calls are traced before outer calls.
+type CallTracer func(CallEvent)
+
+// Called upon return from fr, before restoring the caller's package and
+// realm.
+func (m *Machine) traceCall(fr Frame) {
+	rlm := m.Realm
+	if rlm == nil || fr.Func == nil {
+		return
+	}
+	depth := 0
+	for i := range m.Frames {
+		if m.Frames[i].Func != nil || m.Frames[i].GoFunc != nil {
+			depth++
+		}
+	}
+	if depth > 0 && rlm == fr.LastRealm {
+		return // internal call
+	}
+	caller := ""
+	if depth > 0 && fr.LastRealm != nil {
+		caller = fr.LastRealm.Path
+	}
+	m.CallTracer(CallEvent{
+		Caller: caller,
+		Realm:  rlm.Path,
+		Func:   fr.Func.Name,
+		Depth:  depth,
+		Cycles: m.Cycles - fr.NumCycles,
+	})
+}
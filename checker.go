@@ -0,0 +1,176 @@
+package gno
+
+import (
+	"crypto/sha256"
+	"fmt"
+	goscanner "go/scanner"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Diagnostic is an error found in a file of a package, e.g. by an editor.
+type Diagnostic struct {
+	File   string // name of the file in the package.
+	Line   int    // starting at 1.
+	Column int    // in bytes starting at 1, or 0 if only the line is known.
+	Offset int    // in bytes of the line and column in the file.
+	Msg    string
+}
+
+func (d Diagnostic) String() string {
+	if d.Column == 0 {
+		return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Msg)
+}
+
+// Checker parses and type-checks packages against the packages of a store,
+// for long-lived clients such as language servers.  Imported packages are
+// loaded into the store once, and the diagnostics of a package are kept
+// until its files change, so that checking a package again after an edit
+// only checks that package.
+//
+// The store resolves imports, e.g. from stdlibs and local directories with
+// its package getter; packages from other sources, e.g. from chain, may be
+// run into it before checking.
+type Checker struct {
+	store   Store
+	checked map[string]checkedPackage // by package path.
+}
+
+type checkedPackage struct {
+	hash  [sha256.Size]byte // of the checked files.
+	diags []Diagnostic
+}
+
+func NewChecker(store Store) *Checker {
+	return &Checker{
+		store:   store,
+		checked: make(map[string]checkedPackage),
+	}
+}
+
+// ParseFile parses the file named name, and returns its syntax errors.
+func (c *Checker) ParseFile(name string, body string) []Diagnostic {
+	_, err := ParseFile(name, body)
+	if err == nil {
+		return nil
+	}
+	return fileDiagnostics(name, body, err)
+}
+
+// Check parses and type-checks the files of memPkg, except test files, and
+// returns their errors, sorted by file and position.  Only syntax errors
+// are returned if any, and otherwise the first type error.
+func (c *Checker) Check(memPkg *std.MemPackage) []Diagnostic {
+	files := checkedFiles(memPkg)
+	if len(files.Files) == 0 {
+		return []Diagnostic{}
+	}
+	hash := sha256.Sum256(amino.MustMarshal(files))
+	if cp, ok := c.checked[memPkg.Path]; ok && cp.hash == hash {
+		return cp.diags
+	}
+	diags := []Diagnostic{}
+	for _, mfile := range files.Files {
+		diags = append(diags, c.ParseFile(mfile.Name, mfile.Body)...)
+	}
+	if len(diags) == 0 {
+		if err := TypeCheckMemPackage(files, c.store); err != nil {
+			diags = append(diags, packageDiagnostic(files, err))
+		}
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		if diags[i].File != diags[j].File {
+			return diags[i].File < diags[j].File
+		}
+		return diags[i].Offset < diags[j].Offset
+	})
+	c.checked[memPkg.Path] = checkedPackage{hash: hash, diags: diags}
+	return diags
+}
+
+// Forget drops the diagnostics kept for the package at pkgPath, e.g. when
+// the packages it imports changed.
+func (c *Checker) Forget(pkgPath string) {
+	delete(c.checked, pkgPath)
+}
+
+// Returns memPkg with only the .gno files which are not test files.
+func checkedFiles(memPkg *std.MemPackage) *std.MemPackage {
+	files := &std.MemPackage{Name: memPkg.Name, Path: memPkg.Path}
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		files.Files = append(files.Files, mfile)
+	}
+	return files
+}
+
+// Returns the diagnostics of err, from parsing the file named name.  Go
+// syntax errors have positions; other errors, e.g. of unsupported syntax,
+// are reported at the start of the file.
+func fileDiagnostics(name string, body string, err error) []Diagnostic {
+	errs, ok := err.(goscanner.ErrorList)
+	if !ok {
+		return []Diagnostic{{File: name, Line: 1, Msg: err.Error()}}
+	}
+	diags := make([]Diagnostic, 0, len(errs))
+	for _, e := range errs {
+		diags = append(diags, Diagnostic{
+			File:   name,
+			Line:   e.Pos.Line,
+			Column: e.Pos.Column,
+			Offset: e.Pos.Offset,
+			Msg:    e.Msg,
+		})
+	}
+	return diags
+}
+
+// Matches the location prepended by the preprocessor to its errors, e.g.
+// "gno.land/r/foo/foo.gno:12: msg", or "gno.land/r/foo/foo.gno:12#1: msg",
+// or by file checks, e.g. "foo.gno:12: msg".
+var reLocationError = regexp.MustCompile(`^([^:]*):(\d+)(?:#\d+)?: (.*)$`)
+
+// Returns the diagnostic of err, from type-checking memPkg, at the line of
+// its location if any, or else at the start of the first file.
+func packageDiagnostic(memPkg *std.MemPackage, err error) Diagnostic {
+	msg := err.Error()
+	if match := reLocationError.FindStringSubmatch(msg); match != nil {
+		for _, mfile := range memPkg.Files {
+			if match[1] != memPkg.Path+"/"+mfile.Name && match[1] != mfile.Name {
+				continue
+			}
+			line, _ := strconv.Atoi(match[2])
+			return Diagnostic{
+				File:   mfile.Name,
+				Line:   line,
+				Offset: lineOffset(mfile.Body, line),
+				Msg:    match[3],
+			}
+		}
+	}
+	return Diagnostic{File: memPkg.Files[0].Name, Line: 1, Msg: msg}
+}
+
+// Returns the offset in bytes of the start of line in body, starting at 1.
+func lineOffset(body string, line int) int {
+	offset := 0
+	for i := 1; i < line; i++ {
+		j := strings.IndexByte(body[offset:], '\n')
+		if j < 0 {
+			return len(body)
+		}
+		offset += j + 1
+	}
+	return offset
+}
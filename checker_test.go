@@ -0,0 +1,53 @@
+package gno
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	c := NewChecker(NewStore(nil, nil, nil))
+	memPkg := &std.MemPackage{
+		Name: "hello",
+		Path: "gno.land/r/hello",
+		Files: []*std.MemFile{
+			{Name: "a.gno", Body: "package hello\n\nfunc F() int {\n\treturn \"x\"\n}\n"},
+			{Name: "a_test.gno", Body: "package hello\n\nfunc TestF( {\n"},
+		},
+	}
+	diags := c.Check(memPkg)
+	assert.Equal(t, diags, []Diagnostic{{
+		File:   "a.gno",
+		Line:   4,
+		Offset: 30,
+		Msg:    "cannot convert StringKind to IntKind",
+	}})
+	assert.Equal(t, diags[0].String(), "a.gno:4: cannot convert StringKind to IntKind")
+
+	// syntax errors of all files, with their columns.
+	memPkg.Files = []*std.MemFile{
+		{Name: "a.gno", Body: "package hello\n\nfunc F( {\n"},
+		{Name: "b.gno", Body: "package hello\n\nvar x = \n"},
+	}
+	diags = c.Check(memPkg)
+	assert.Equal(t, len(diags), 2)
+	assert.Equal(t, diags[0].String(), "a.gno:3:9: expected ')', found '{'")
+	assert.Equal(t, diags[0].Offset, 23)
+	assert.Equal(t, diags[1].File, "b.gno")
+
+	memPkg.Files = []*std.MemFile{
+		{Name: "a.gno", Body: "package hello\n\nfunc F() int { return G() }\n"},
+		{Name: "b.gno", Body: "package hello\n\nfunc G() int { return 1 }\n"},
+	}
+	assert.Equal(t, c.Check(memPkg), []Diagnostic{})
+}
+
+func TestCheckerParseFile(t *testing.T) {
+	c := NewChecker(NewStore(nil, nil, nil))
+	assert.Nil(t, c.ParseFile("a.gno", "package hello\n"))
+	diags := c.ParseFile("a.gno", "package hello\n\nfunc F() {\n")
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Line, 3)
+}
@@ -17,8 +17,12 @@ import (
 	"github.com/gnolang/gno/pkgs/crypto/hd"
 	"github.com/gnolang/gno/pkgs/crypto/merkle"
 	"github.com/gnolang/gno/pkgs/crypto/multisig"
+	"github.com/gnolang/gno/pkgs/crypto/webauthn"
 	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/authz"
 	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/sdk/ibc"
+	"github.com/gnolang/gno/pkgs/sdk/valset"
 	"github.com/gnolang/gno/pkgs/sdk/vm"
 	"github.com/gnolang/gno/pkgs/std"
 )
@@ -36,10 +40,14 @@ func main() {
 		blockchain.Package,
 		hd.Package,
 		multisig.Package,
+		webauthn.Package,
 		std.Package,
 		sdk.Package,
 		bank.Package,
 		vm.Package,
+		valset.Package,
+		authz.Package,
+		ibc.Package,
 		gno.Package,
 	}
 	for _, pkg := range pkgs {
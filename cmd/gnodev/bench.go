@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/sdk/vm"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// Benchmarks are run like Go's: a benchmark BenchmarkXxx(b *testing.B) runs
+// the code measured b.N times, and is run with increasing b.N until it runs
+// for --benchtime, or exactly N times with --benchtime Nx.  Besides the wall
+// time, the VM ops run, the bytes allocated and the gas consumed per
+// iteration are reported, with the default gas parameters of the vm module;
+// store access is not metered.
+
+const maxBenchN = 1e9
+
+// The measures of a run of a benchmark.
+type benchResult struct {
+	N       int
+	Elapsed time.Duration
+	Ops     int64
+	Bytes   int64
+	Gas     int64
+}
+
+func (res benchResult) String() string {
+	n := int64(res.N)
+	return fmt.Sprintf("%8d\t%10d ns/op\t%10d ops/op\t%10d B/op\t%10d gas/op",
+		res.N, res.Elapsed.Nanoseconds()/n, res.Ops/n, res.Bytes/n, res.Gas/n)
+}
+
+func runBenchmark(cmd *command.Command, m *gno.Machine, snap *gno.MachineSnapshot, name string, opts testOptions) error {
+	benchTime, benchN, err := parseBenchTime(opts.BenchTime)
+	if err != nil {
+		return err
+	}
+	n := 1
+	if benchN > 0 {
+		n = benchN
+	}
+	for {
+		res, rep, err := runBenchmarkN(m, snap, name, n)
+		if err != nil {
+			return err
+		}
+		if rep.Failed {
+			cmd.ErrPrintfln("--- FAIL: %s", name)
+			if rep.Output != "" {
+				cmd.ErrPrintfln("output: %s", rep.Output)
+			}
+			return errors.New("failed: %q", name)
+		}
+		if benchN > 0 || res.Elapsed >= benchTime || n >= maxBenchN {
+			cmd.ErrPrintfln("%s\t%s", name, res)
+			if rep.Output != "" && opts.Verbose {
+				cmd.ErrPrintfln("output: %s", rep.Output)
+			}
+			return nil
+		}
+		n = nextBenchN(n, res.Elapsed, benchTime)
+	}
+}
+
+// Runs the benchmark with b.N set to n, from snap, and returns its
+// measures and report.
+func runBenchmarkN(m *gno.Machine, snap *gno.MachineSnapshot, name string, n int) (res benchResult, rep report, err error) {
+	m.Restore(snap)
+	m.Alloc = gno.NewAllocator(math.MaxInt64)
+	m.OpCounts = new([256]int64)
+	m.GasMeter = store.NewInfiniteGasMeter()
+	m.GasConfig = vm.DefaultParams().MachineGasConfig()
+	defer func() {
+		m.Alloc, m.OpCounts, m.GasMeter = nil, nil, nil
+	}()
+
+	startedAt := time.Now()
+	ret := m.Eval(gno.Call("runbenchmark", fmt.Sprintf("%q", name), fmt.Sprintf("%d", n)))[0]
+	res.Elapsed = time.Since(startedAt)
+	res.N = n
+	for _, count := range m.OpCounts {
+		res.Ops += count
+	}
+	_, res.Bytes = m.Alloc.Status()
+	res.Gas = m.GasMeter.GasConsumed()
+	rep, err = parseReport(ret)
+	return res, rep, err
+}
+
+// Returns the next b.N to run a benchmark which ran n times in elapsed,
+// to run it for benchTime, as Go does.
+func nextBenchN(n int, elapsed time.Duration, benchTime time.Duration) int {
+	next := int64(benchTime) * int64(n)
+	if ns := elapsed.Nanoseconds(); ns > 0 {
+		next /= ns
+	}
+	// run 20% more, grow at least by one, and at most 100 times.
+	next += next / 5
+	if max := 100 * int64(n); next > max {
+		next = max
+	}
+	if next < int64(n)+1 {
+		next = int64(n) + 1
+	}
+	if next > maxBenchN {
+		next = maxBenchN
+	}
+	return int(next)
+}
+
+// Parses the --benchtime flag, a duration, or a number of iterations
+// with the form Nx.
+func parseBenchTime(s string) (d time.Duration, n int, err error) {
+	if strings.HasSuffix(s, "x") {
+		n, err = strconv.Atoi(strings.TrimSuffix(s, "x"))
+		if err != nil || n <= 0 {
+			return 0, 0, errors.New("invalid --benchtime %q", s)
+		}
+		return 0, n, nil
+	}
+	d, err = time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, 0, errors.New("invalid --benchtime %q", s)
+	}
+	return d, 0, nil
+}
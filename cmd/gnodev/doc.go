@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+type docOptions struct {
+	JSON   bool   `flag:"json" help:"print the documentation as JSON"`
+	Remote string `flag:"remote" help:"address of a gnoland node, to document the package at this path on chain"`
+}
+
+var DefaultDocOptions = docOptions{
+	JSON:   false,
+	Remote: "",
+}
+
+func docApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(docOptions)
+	if len(args) < 1 || len(args) > 2 {
+		cmd.ErrPrintfln("Usage: doc [doc flags] <package> [<symbol>]")
+		return errors.New("invalid args")
+	}
+
+	var memPkg *std.MemPackage
+	var err error
+	if opts.Remote != "" {
+		cli := client.NewHTTP(opts.Remote, "/websocket")
+		memPkg, err = queryMemPackage(cli, args[0])
+		if err != nil {
+			return fmt.Errorf("query package: %w", err)
+		}
+	} else {
+		info, err := os.Stat(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid package path: %w", err)
+		}
+		if !info.IsDir() {
+			return errors.New("%s is not a directory", args[0])
+		}
+		memPkg = gno.ReadMemPackage(args[0], args[0])
+	}
+
+	pd, err := gno.NewPackageDoc(memPkg)
+	if err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		spd := pd.Symbol(args[1])
+		if spd == nil {
+			return errors.New("no symbol %s in package %s", args[1], memPkg.Path)
+		}
+		pd = spd
+	}
+
+	if opts.JSON {
+		bz, err := json.MarshalIndent(pd, "", "  ")
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(bz))
+		return nil
+	}
+	return pd.WriteText(cmd.Out)
+}
+
+// Returns the package at pkgPath on the node of cli, through the vm/qfile
+// query.
+func queryMemPackage(cli client.Client, pkgPath string) (*std.MemPackage, error) {
+	query := func(path string) (string, error) {
+		qres, err := cli.ABCIQuery("vm/qfile", []byte(path))
+		if err != nil {
+			return "", err
+		}
+		if qres.Response.Error != nil {
+			return "", qres.Response.Error
+		}
+		return string(qres.Response.Data), nil
+	}
+	list, err := query(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	memPkg := &std.MemPackage{Path: pkgPath}
+	for _, name := range strings.Split(list, "\n") {
+		if name == "" {
+			continue
+		}
+		body, err := query(pkgPath + "/" + name)
+		if err != nil {
+			return nil, err
+		}
+		memPkg.Files = append(memPkg.Files, &std.MemFile{Name: name, Body: body})
+		if memPkg.Name == "" && strings.HasSuffix(name, ".gno") &&
+			!strings.HasSuffix(name, "_test.gno") && !strings.HasSuffix(name, "_filetest.gno") {
+			f, err := parser.ParseFile(token.NewFileSet(), name, body, parser.PackageClauseOnly)
+			if err != nil {
+				return nil, err
+			}
+			memPkg.Name = f.Name.Name
+		}
+	}
+	return memPkg, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+type fmtOptions struct {
+	List  bool `flag:"list" help:"list the files whose formatting differs, and fail if any (unless writing)"`
+	Write bool `flag:"write" help:"write the result to the source files instead of stdout"`
+}
+
+var DefaultFmtOptions = fmtOptions{
+	List:  false,
+	Write: false,
+}
+
+func fmtApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(fmtOptions)
+	if len(args) < 1 {
+		cmd.ErrPrintfln("Usage: fmt [fmt flags] [packages or files]")
+		return errors.New("invalid args")
+	}
+
+	paths, err := gnoFilesFromArgs(args)
+	if err != nil {
+		return fmt.Errorf("list files from args: %w", err)
+	}
+
+	unformatted := 0
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		res, err := gno.FormatSource(path, src)
+		if err != nil {
+			return err
+		}
+		changed := !bytes.Equal(src, res)
+		if changed {
+			unformatted++
+		}
+		if opts.List && changed {
+			cmd.Println(path)
+		}
+		if opts.Write && changed {
+			if err := os.WriteFile(path, res, 0o644); err != nil {
+				return err
+			}
+		}
+		if !opts.List && !opts.Write {
+			cmd.Out.Write(res)
+		}
+	}
+	if opts.List && !opts.Write && unformatted > 0 {
+		return fmt.Errorf("%d unformatted files", unformatted)
+	}
+
+	return nil
+}
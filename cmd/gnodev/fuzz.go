@@ -0,0 +1,547 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// Fuzz targets are run like tests: a target FuzzXxx(f *testing.F) adds seed
+// inputs and sets the function to fuzz, which is then called with each seed
+// input and each input of the corpus under testdata/fuzz/FuzzXxx. With
+// --fuzz, targets matching it are also called with inputs generated by
+// mutating those, deterministically from --fuzz-seed. The first generated
+// input that fails is written to the corpus, so that it is replayed by
+// later runs.
+
+const fuzzCorpusHeader = "go test fuzz v1"
+
+// A fuzz input, of the Go types of the fuzz function parameters.
+type fuzzInput struct {
+	name   string
+	values []interface{}
+}
+
+func runFuzzTarget(cmd *command.Command, m *gno.Machine, name string, pkgPath string, opts testOptions) error {
+	startedAt := time.Now()
+	if opts.Verbose {
+		cmd.ErrPrintfln("=== RUN   %s", name)
+	}
+	fail := func(err error) error {
+		cmd.ErrPrintfln("--- FAIL: %s (%s)", name, fmtDuration(time.Since(startedAt)))
+		return err
+	}
+
+	ftv := m.Eval(gno.Call("runfuzztarget", fmt.Sprintf("%q", name)))[0]
+	f := func() *gno.ConstExpr { return &gno.ConstExpr{TypedValue: ftv} }
+	rep, err := parseReport(m.Eval(gno.Call(gno.Sel(f(), "Report")))[0])
+	if err != nil {
+		return fail(err)
+	}
+	if rep.Failed {
+		if rep.Output != "" {
+			cmd.ErrPrintfln("output: %s", rep.Output)
+		}
+		return fail(errors.New("failed: %q", name))
+	}
+	fn := m.Eval(gno.Call(gno.Sel(f(), "Func")))[0]
+	types, err := fuzzParamTypes(fn.T)
+	if err != nil {
+		return fail(errors.New("%s: %v", name, err))
+	}
+
+	// collect seed and corpus inputs.
+	var inputs []fuzzInput
+	numSeeds := m.Eval(gno.Call(gno.Sel(f(), "NumSeeds")))[0].GetInt()
+	for i := 0; i < numSeeds; i++ {
+		seed := m.Eval(gno.Call(gno.Sel(f(), "Seed"), fmt.Sprintf("%d", i)))[0]
+		values, err := fuzzValuesFromGno(m.Store, seed, types)
+		if err != nil {
+			return fail(errors.New("%s: seed#%d: %v", name, i, err))
+		}
+		inputs = append(inputs, fuzzInput{name: fmt.Sprintf("seed#%d", i), values: values})
+	}
+	corpusDir := filepath.Join(pkgPath, "testdata", "fuzz", name)
+	corpus, err := readFuzzCorpus(corpusDir, types)
+	if err != nil {
+		return fail(errors.New("%s: %v", name, err))
+	}
+	inputs = append(inputs, corpus...)
+
+	filter := splitRegexp(opts.Run)
+	for _, input := range inputs {
+		if !shouldRun(filter, name+"/"+input.name) {
+			continue
+		}
+		rep, err := runFuzzInput(m, ftv, fn, input, types)
+		if err != nil {
+			return fail(err)
+		}
+		if rep.Failed {
+			cmd.ErrPrintfln("--- FAIL: %s", rep.Name)
+			if rep.Output != "" {
+				cmd.ErrPrintfln("output: %s", rep.Output)
+			}
+			return fail(errors.New("failed: %q", rep.Name))
+		}
+	}
+
+	if opts.Fuzz != "" {
+		match, err := regexp.MatchString(opts.Fuzz, name)
+		if err != nil {
+			return fail(errors.Wrap(err, "invalid --fuzz pattern"))
+		}
+		if match {
+			if opts.Verbose {
+				cmd.ErrPrintfln("fuzz: %s: %d inputs from seed %d", name, opts.FuzzIters, opts.FuzzSeed)
+			}
+			rnd := rand.New(rand.NewSource(opts.FuzzSeed))
+			for i := 0; i < opts.FuzzIters; i++ {
+				input := fuzzInput{name: fmt.Sprintf("fuzz#%d", i)}
+				if len(inputs) > 0 {
+					base := inputs[rnd.Intn(len(inputs))].values
+					input.values = mutateFuzzValues(rnd, base)
+				} else {
+					input.values = mutateFuzzValues(rnd, zeroFuzzValues(types))
+				}
+				rep, err := runFuzzInput(m, ftv, fn, input, types)
+				if err != nil {
+					return fail(err)
+				}
+				if rep.Failed {
+					cmd.ErrPrintfln("--- FAIL: %s", rep.Name)
+					if rep.Output != "" {
+						cmd.ErrPrintfln("output: %s", rep.Output)
+					}
+					file, err := writeFuzzCorpusFile(corpusDir, input.values)
+					if err != nil {
+						return fail(err)
+					}
+					cmd.ErrPrintfln("Failing input written to %s", file)
+					cmd.ErrPrintfln("To re-run:\ngnodev test %s --run %s/%s", pkgPath, name, filepath.Base(file))
+					return fail(errors.New("failed: %q", rep.Name))
+				}
+			}
+		}
+	}
+
+	if opts.Verbose {
+		cmd.ErrPrintfln("--- PASS: %s (%s)", name, fmtDuration(time.Since(startedAt)))
+	}
+	return nil
+}
+
+// Calls the fuzz function fn with input, and returns the report.
+func runFuzzInput(m *gno.Machine, f, fn gno.TypedValue, input fuzzInput, types []gno.Type) (report, error) {
+	t := m.Eval(gno.Call(gno.Sel(&gno.ConstExpr{TypedValue: f}, "NewInput"), fmt.Sprintf("%q", input.name)))[0]
+	args := []interface{}{&gno.ConstExpr{TypedValue: t}}
+	for i, v := range input.values {
+		args = append(args, &gno.ConstExpr{TypedValue: fuzzValueToGno(m.Alloc, v, types[i])})
+	}
+	call := gno.Fn(nil, nil, gno.Ss(gno.S(gno.Call(&gno.ConstExpr{TypedValue: fn}, args...))))
+	ret := m.Eval(gno.Call(gno.Sel(&gno.ConstExpr{TypedValue: f}, "RunInput"), &gno.ConstExpr{TypedValue: t}, call))[0]
+	return parseReport(ret)
+}
+
+func parseReport(ret gno.TypedValue) (rep report, err error) {
+	err = json.Unmarshal([]byte(ret.GetString()), &rep)
+	return
+}
+
+// Returns the types of the parameters after the *testing.T of the fuzz
+// function type t, which must all be supported.
+func fuzzParamTypes(t gno.Type) ([]gno.Type, error) {
+	if t == nil {
+		return nil, errors.New("F.Fuzz was not called")
+	}
+	ft, ok := t.(*gno.FuncType)
+	if !ok || len(ft.Params) == 0 || len(ft.Results) != 0 ||
+		ft.Params[0].Type.String() != "*testing.T" {
+		return nil, errors.New("fuzz function must be of the form func(*testing.T, ...), got %s", t.String())
+	}
+	types := make([]gno.Type, 0, len(ft.Params)-1)
+	for _, p := range ft.Params[1:] {
+		if fuzzTypeName(p.Type) == "" {
+			return nil, errors.New("unsupported fuzz function parameter type %s", p.Type.String())
+		}
+		types = append(types, p.Type)
+	}
+	return types, nil
+}
+
+// Returns the name of the fuzz type t in corpus files, or "" if t is not
+// supported.
+func fuzzTypeName(t gno.Type) string {
+	switch t {
+	case gno.BoolType, gno.StringType,
+		gno.IntType, gno.Int8Type, gno.Int16Type, gno.Int32Type, gno.Int64Type,
+		gno.UintType, gno.Uint8Type, gno.Uint16Type, gno.Uint32Type, gno.Uint64Type,
+		gno.Float32Type, gno.Float64Type:
+		return t.String()
+	}
+	if st, ok := t.(*gno.SliceType); ok && st.Elt == gno.Uint8Type {
+		return "[]byte"
+	}
+	return ""
+}
+
+func zeroFuzzValues(types []gno.Type) []interface{} {
+	values := make([]interface{}, len(types))
+	for i, t := range types {
+		values[i] = fuzzValueFromGno(nil, gno.TypedValue{T: t})
+	}
+	return values
+}
+
+// Converts the elements of the []interface{} seed to values of types.
+func fuzzValuesFromGno(store gno.Store, seed gno.TypedValue, types []gno.Type) ([]interface{}, error) {
+	if n := seed.GetLength(); n != len(types) {
+		return nil, errors.New("got %d values, expected %d", n, len(types))
+	}
+	values := make([]interface{}, len(types))
+	for i, t := range types {
+		tv := seed.GetPointerAtIndexInt(store, i).Deref()
+		if tv.T == nil || tv.T.TypeID() != t.TypeID() {
+			return nil, errors.New("value %d: got type %v, expected %s", i, tv.T, t.String())
+		}
+		values[i] = fuzzValueFromGno(store, tv)
+	}
+	return values, nil
+}
+
+func fuzzValueFromGno(store gno.Store, tv gno.TypedValue) interface{} {
+	switch tv.T {
+	case gno.BoolType:
+		return tv.GetBool()
+	case gno.StringType:
+		if tv.V == nil {
+			return ""
+		}
+		return tv.GetString()
+	case gno.IntType:
+		return tv.GetInt()
+	case gno.Int8Type:
+		return tv.GetInt8()
+	case gno.Int16Type:
+		return tv.GetInt16()
+	case gno.Int32Type:
+		return tv.GetInt32()
+	case gno.Int64Type:
+		return tv.GetInt64()
+	case gno.UintType:
+		return tv.GetUint()
+	case gno.Uint8Type:
+		return tv.GetUint8()
+	case gno.Uint16Type:
+		return tv.GetUint16()
+	case gno.Uint32Type:
+		return tv.GetUint32()
+	case gno.Uint64Type:
+		return tv.GetUint64()
+	case gno.Float32Type:
+		return tv.GetFloat32()
+	case gno.Float64Type:
+		return tv.GetFloat64()
+	default: // []byte
+		if tv.V == nil {
+			return []byte{}
+		}
+		bz := make([]byte, tv.GetLength())
+		for i := range bz {
+			etv := tv.GetPointerAtIndexInt(store, i).Deref()
+			bz[i] = etv.GetUint8()
+		}
+		return bz
+	}
+}
+
+func fuzzValueToGno(alloc *gno.Allocator, v interface{}, t gno.Type) gno.TypedValue {
+	tv := gno.TypedValue{T: t}
+	switch v := v.(type) {
+	case bool:
+		tv.SetBool(v)
+	case string:
+		tv.V = alloc.NewString(v)
+	case int:
+		tv.SetInt(v)
+	case int8:
+		tv.SetInt8(v)
+	case int16:
+		tv.SetInt16(v)
+	case int32:
+		tv.SetInt32(v)
+	case int64:
+		tv.SetInt64(v)
+	case uint:
+		tv.SetUint(v)
+	case uint8:
+		tv.SetUint8(v)
+	case uint16:
+		tv.SetUint16(v)
+	case uint32:
+		tv.SetUint32(v)
+	case uint64:
+		tv.SetUint64(v)
+	case float32:
+		tv.SetFloat32(v)
+	case float64:
+		tv.SetFloat64(v)
+	case []byte:
+		tv.V = alloc.NewSliceFromData(v)
+	default:
+		panic(fmt.Sprintf("unexpected fuzz value type %T", v))
+	}
+	return tv
+}
+
+// Returns a copy of values with one or more values mutated.
+func mutateFuzzValues(rnd *rand.Rand, values []interface{}) []interface{} {
+	mutated := append([]interface{}(nil), values...)
+	if len(mutated) == 0 {
+		return mutated
+	}
+	for n := 1 + rnd.Intn(len(mutated)); n > 0; n-- {
+		i := rnd.Intn(len(mutated))
+		mutated[i] = mutateFuzzValue(rnd, mutated[i])
+	}
+	return mutated
+}
+
+func mutateFuzzValue(rnd *rand.Rand, v interface{}) interface{} {
+	switch v := v.(type) {
+	case bool:
+		return !v
+	case string:
+		return string(mutateFuzzBytes(rnd, []byte(v)))
+	case []byte:
+		return mutateFuzzBytes(rnd, v)
+	case float32:
+		return float32(mutateFuzzFloat(rnd, float64(v)))
+	case float64:
+		return mutateFuzzFloat(rnd, v)
+	case int:
+		return int(mutateFuzzInt(rnd, int64(v)))
+	case int8:
+		return int8(mutateFuzzInt(rnd, int64(v)))
+	case int16:
+		return int16(mutateFuzzInt(rnd, int64(v)))
+	case int32:
+		return int32(mutateFuzzInt(rnd, int64(v)))
+	case int64:
+		return mutateFuzzInt(rnd, v)
+	case uint:
+		return uint(mutateFuzzInt(rnd, int64(v)))
+	case uint8:
+		return uint8(mutateFuzzInt(rnd, int64(v)))
+	case uint16:
+		return uint16(mutateFuzzInt(rnd, int64(v)))
+	case uint32:
+		return uint32(mutateFuzzInt(rnd, int64(v)))
+	case uint64:
+		return uint64(mutateFuzzInt(rnd, int64(v)))
+	default:
+		panic(fmt.Sprintf("unexpected fuzz value type %T", v))
+	}
+}
+
+// Returns a copy of bz with a random byte inserted, removed or replaced.
+func mutateFuzzBytes(rnd *rand.Rand, bz []byte) []byte {
+	mutated := append([]byte(nil), bz...)
+	switch op := rnd.Intn(3); {
+	case op == 0 || len(mutated) == 0:
+		i := rnd.Intn(len(mutated) + 1)
+		mutated = append(mutated[:i], append([]byte{byte(rnd.Intn(256))}, mutated[i:]...)...)
+	case op == 1:
+		i := rnd.Intn(len(mutated))
+		mutated = append(mutated[:i], mutated[i+1:]...)
+	default:
+		mutated[rnd.Intn(len(mutated))] = byte(rnd.Intn(256))
+	}
+	return mutated
+}
+
+// Returns n changed by a small delta, or an interesting value.
+// The result may overflow the type of n, which is then truncated.
+func mutateFuzzInt(rnd *rand.Rand, n int64) int64 {
+	switch rnd.Intn(4) {
+	case 0:
+		return n + int64(rnd.Intn(33)) - 16
+	case 1:
+		return n ^ (1 << uint(rnd.Intn(64)))
+	case 2:
+		return []int64{0, 1, -1, math.MaxInt8, math.MinInt8, math.MaxUint8,
+			math.MaxInt16, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64}[rnd.Intn(11)]
+	default:
+		return int64(rnd.Uint64())
+	}
+}
+
+func mutateFuzzFloat(rnd *rand.Rand, f float64) float64 {
+	switch rnd.Intn(3) {
+	case 0:
+		return f + rnd.NormFloat64()
+	case 1:
+		return f * rnd.NormFloat64()
+	default:
+		return []float64{0, -1, math.Inf(1), math.Inf(-1), math.NaN(), math.MaxFloat64,
+			math.SmallestNonzeroFloat64}[rnd.Intn(7)]
+	}
+}
+
+//----------------------------------------
+// Corpus files, in the format of Go's fuzzing corpus:
+//
+//	go test fuzz v1
+//	string("hello")
+//	int(42)
+
+func readFuzzCorpus(dir string, types []gno.Type) ([]fuzzInput, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var inputs []fuzzInput
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name())
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		values, err := decodeFuzzCorpus(string(bz), types)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		inputs = append(inputs, fuzzInput{name: entry.Name(), values: values})
+	}
+	return inputs, nil
+}
+
+// Writes values to a corpus file in dir named after their hash, and
+// returns the path of the file.
+func writeFuzzCorpusFile(dir string, values []interface{}) (string, error) {
+	data := encodeFuzzCorpus(values)
+	sum := sha256.Sum256([]byte(data))
+	file := filepath.Join(dir, hex.EncodeToString(sum[:])[:16])
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return file, os.WriteFile(file, []byte(data), 0o644)
+}
+
+func encodeFuzzCorpus(values []interface{}) string {
+	lines := []string{fuzzCorpusHeader}
+	for _, v := range values {
+		var tname, lit string
+		switch v := v.(type) {
+		case string:
+			tname, lit = "string", strconv.Quote(v)
+		case []byte:
+			tname, lit = "[]byte", strconv.Quote(string(v))
+		case float32:
+			tname, lit = "float32", strconv.FormatFloat(float64(v), 'g', -1, 32)
+		case float64:
+			tname, lit = "float64", strconv.FormatFloat(v, 'g', -1, 64)
+		default:
+			tname, lit = fmt.Sprintf("%T", v), fmt.Sprintf("%v", v)
+		}
+		lines = append(lines, fmt.Sprintf("%s(%s)", tname, lit))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func decodeFuzzCorpus(data string, types []gno.Type) ([]interface{}, error) {
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+	if lines[0] != fuzzCorpusHeader {
+		return nil, errors.New("expected header %q", fuzzCorpusHeader)
+	}
+	lines = lines[1:]
+	if len(lines) != len(types) {
+		return nil, errors.New("got %d values, expected %d", len(lines), len(types))
+	}
+	values := make([]interface{}, len(types))
+	for i, line := range lines {
+		tname := fuzzTypeName(types[i])
+		if !strings.HasPrefix(line, tname+"(") || !strings.HasSuffix(line, ")") {
+			return nil, errors.New("value %d: expected %s(...), got %q", i, tname, line)
+		}
+		v, err := parseFuzzValue(line[len(tname)+1:len(line)-1], types[i])
+		if err != nil {
+			return nil, errors.New("value %d: %v", i, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func parseFuzzValue(lit string, t gno.Type) (interface{}, error) {
+	switch t {
+	case gno.BoolType:
+		return strconv.ParseBool(lit)
+	case gno.StringType:
+		return strconv.Unquote(lit)
+	case gno.Float32Type:
+		f, err := strconv.ParseFloat(lit, 32)
+		return float32(f), err
+	case gno.Float64Type:
+		return strconv.ParseFloat(lit, 64)
+	case gno.IntType, gno.Int8Type, gno.Int16Type, gno.Int32Type, gno.Int64Type:
+		n, err := strconv.ParseInt(lit, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return fuzzIntValue(t, n), nil
+	case gno.UintType, gno.Uint8Type, gno.Uint16Type, gno.Uint32Type, gno.Uint64Type:
+		n, err := strconv.ParseUint(lit, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return fuzzIntValue(t, int64(n)), nil
+	default: // []byte
+		s, err := strconv.Unquote(lit)
+		return []byte(s), err
+	}
+}
+
+// Returns n as a value of the integer type t, truncated if necessary.
+func fuzzIntValue(t gno.Type, n int64) interface{} {
+	switch t {
+	case gno.IntType:
+		return int(n)
+	case gno.Int8Type:
+		return int8(n)
+	case gno.Int16Type:
+		return int16(n)
+	case gno.Int32Type:
+		return int32(n)
+	case gno.Int64Type:
+		return n
+	case gno.UintType:
+		return uint(n)
+	case gno.Uint8Type:
+		return uint8(n)
+	case gno.Uint16Type:
+		return uint16(n)
+	case gno.Uint32Type:
+		return uint32(n)
+	default:
+		return uint64(n)
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+type lintOptions struct {
+	Verbose bool `flag:"verbose" help:"verbose"`
+	Realm   bool `flag:"realm" help:"apply the realm checks to all packages, not only to those under gno.land/r/"`
+}
+
+var DefaultLintOptions = lintOptions{
+	Verbose: false,
+	Realm:   false,
+}
+
+func lintApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(lintOptions)
+	if len(args) < 1 {
+		cmd.ErrPrintfln("Usage: lint [lint flags] [packages]")
+		return errors.New("invalid args")
+	}
+
+	pkgPaths, err := gnoPackagesFromArgs(args)
+	if err != nil {
+		return fmt.Errorf("list packages from args: %w", err)
+	}
+
+	issueCount := 0
+	for _, pkgPath := range pkgPaths {
+		if opts.Verbose {
+			cmd.ErrPrintfln("%s", pkgPath)
+		}
+		issues, err := lintPkg(pkgPath, opts)
+		if err != nil {
+			return fmt.Errorf("%s: lint pkg: %w", pkgPath, err)
+		}
+		for _, issue := range issues {
+			cmd.ErrPrintfln("%s", issue)
+		}
+		issueCount += len(issues)
+	}
+	if issueCount > 0 {
+		return fmt.Errorf("%d lint issues", issueCount)
+	}
+
+	return nil
+}
+
+type lintIssue struct {
+	pos  token.Position
+	code string // name of the check.
+	msg  string
+}
+
+func (issue lintIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", issue.pos, issue.msg, issue.code)
+}
+
+// A package being linted.
+type linter struct {
+	fset   *token.FileSet
+	files  []*ast.File
+	issues []lintIssue
+}
+
+func (l *linter) report(node ast.Node, code string, format string, args ...interface{}) {
+	l.issues = append(l.issues, lintIssue{
+		pos:  l.fset.Position(node.Pos()),
+		code: code,
+		msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lintPkg lints the non-test files of the package (or the file) at
+// pkgPath. Realm checks apply to realms, or to all packages with
+// opts.Realm.
+func lintPkg(pkgPath string, opts lintOptions) ([]lintIssue, error) {
+	var fnames []string
+	if info, err := os.Stat(pkgPath); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		fnames, err = filepath.Glob(filepath.Join(pkgPath, "*.gno"))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		fnames = []string{pkgPath}
+	}
+
+	l := &linter{fset: token.NewFileSet()}
+	for _, fname := range fnames {
+		if strings.HasSuffix(fname, "_test.gno") || strings.HasSuffix(fname, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(l.fset, fname, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		l.files = append(l.files, f)
+	}
+
+	l.lintUnusedGlobals()
+	l.lintNondeterminism()
+	l.lintMapRanges()
+	l.lintImportedStateMutation()
+	l.lintRecover()
+	if opts.Realm || isRealmPath(pkgPath) {
+		l.lintExportedState()
+		l.lintExportedFuncs()
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		pi, pj := l.issues[i].pos, l.issues[j].pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+	return l.issues, nil
+}
+
+func isRealmPath(pkgPath string) bool {
+	return strings.Contains(filepath.ToSlash(pkgPath), "gno.land/r/")
+}
+
+// Reports unexported package-level variables and constants that are
+// never referred to.
+func (l *linter) lintUnusedGlobals() {
+	decls := make(map[string]*ast.Ident)
+	declared := make(map[*ast.Ident]bool)
+	for _, f := range l.files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || (gd.Tok != token.VAR && gd.Tok != token.CONST) {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					declared[name] = true
+					if name.Name != "_" && !name.IsExported() {
+						decls[name.Name] = name
+					}
+				}
+			}
+		}
+	}
+	used := make(map[string]bool)
+	for _, f := range l.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				ast.Inspect(n.X, func(n ast.Node) bool {
+					if id, ok := n.(*ast.Ident); ok && !declared[id] {
+						used[id.Name] = true
+					}
+					return true
+				})
+				return false // n.Sel is not a global.
+			case *ast.Ident:
+				if !declared[n] {
+					used[n.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	for name, id := range decls {
+		if !used[name] {
+			l.report(id, "unused-global", "%s is unused", name)
+		}
+	}
+}
+
+// Reports constructs whose behavior is not deterministic, which on-chain
+// code must not use: goroutines, channels, select, and floats, whose
+// results may differ across platforms.
+func (l *linter) lintNondeterminism() {
+	for _, f := range l.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.GoStmt:
+				l.report(n, "nondeterminism", "goroutines are not deterministic")
+			case *ast.SelectStmt:
+				l.report(n, "nondeterminism", "select is not deterministic")
+			case *ast.ChanType:
+				l.report(n, "nondeterminism", "channels are not deterministic")
+			case *ast.Ident:
+				switch n.Name {
+				case "float32", "float64", "complex64", "complex128":
+					l.report(n, "nondeterminism", "%s arithmetic may differ across platforms", n.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Reports loops over maps, whose cost grows with the map, so that they
+// may exceed the gas limit once the map is large.
+func (l *linter) lintMapRanges() {
+	// names of variables and struct fields declared as maps.
+	maps := make(map[string]bool)
+	isMap := func(x ast.Expr) bool {
+		switch x := x.(type) {
+		case *ast.MapType:
+			return true
+		case *ast.CompositeLit:
+			_, ok := x.Type.(*ast.MapType)
+			return ok
+		case *ast.CallExpr:
+			if id, ok := x.Fun.(*ast.Ident); ok && id.Name == "make" && len(x.Args) > 0 {
+				_, ok := x.Args[0].(*ast.MapType)
+				return ok
+			}
+		}
+		return false
+	}
+	for _, f := range l.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					if isMap(n.Type) || (i < len(n.Values) && isMap(n.Values[i])) {
+						maps[name.Name] = true
+					}
+				}
+			case *ast.AssignStmt:
+				if n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+					for i, lhs := range n.Lhs {
+						if id, ok := lhs.(*ast.Ident); ok && isMap(n.Rhs[i]) {
+							maps[id.Name] = true
+						}
+					}
+				}
+			case *ast.Field:
+				if isMap(n.Type) {
+					for _, name := range n.Names {
+						maps[name.Name] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	for _, f := range l.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			rs, ok := n.(*ast.RangeStmt)
+			if !ok {
+				return true
+			}
+			var name string
+			switch x := rs.X.(type) {
+			case *ast.Ident:
+				name = x.Name
+			case *ast.SelectorExpr:
+				name = x.Sel.Name
+			}
+			if maps[name] {
+				l.report(rs, "map-range", "unbounded loop over map %s; its gas cost grows with the map", types.ExprString(rs.X))
+			}
+			return true
+		})
+	}
+}
+
+// Reports assignments to the variables of imported packages.
+func (l *linter) lintImportedStateMutation() {
+	for _, f := range l.files {
+		imports := make(map[string]string) // by name.
+		for _, spec := range f.Imports {
+			ipath, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(ipath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = ipath
+		}
+		check := func(x ast.Expr) {
+			for {
+				switch xx := x.(type) {
+				case *ast.SelectorExpr:
+					if id, ok := xx.X.(*ast.Ident); ok {
+						if ipath, ok := imports[id.Name]; ok {
+							l.report(x, "imported-state", "assignment to %s, the state of imported package %s", types.ExprString(x), ipath)
+							return
+						}
+					}
+					x = xx.X
+				case *ast.IndexExpr:
+					x = xx.X
+				case *ast.StarExpr:
+					x = xx.X
+				case *ast.ParenExpr:
+					x = xx.X
+				default:
+					return
+				}
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if n.Tok != token.DEFINE {
+					for _, lhs := range n.Lhs {
+						check(lhs)
+					}
+				}
+			case *ast.IncDecStmt:
+				check(n.X)
+			}
+			return true
+		})
+	}
+}
+
+// Reports calls to recover() that have no effect, outside deferred
+// functions, and recovered panics that are ignored, which persist the
+// state changes made before the panic.
+func (l *linter) lintRecover() {
+	isRecover := func(x ast.Expr) bool {
+		call, ok := x.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		id, ok := call.Fun.(*ast.Ident)
+		return ok && id.Name == "recover" && len(call.Args) == 0
+	}
+	for _, f := range l.files {
+		deferred := make(map[*ast.FuncLit]bool)
+		var stack []ast.Node
+		ast.Inspect(f, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+			stack = append(stack, n)
+			switch n := n.(type) {
+			case *ast.DeferStmt:
+				if fl, ok := n.Call.Fun.(*ast.FuncLit); ok {
+					deferred[fl] = true
+				}
+			case *ast.ExprStmt:
+				if isRecover(n.X) {
+					l.report(n, "recover", "result of recover() is ignored; state changes before the panic are kept")
+				}
+			case *ast.CallExpr:
+				if !isRecover(n) {
+					break
+				}
+				// find the enclosing function.
+				for i := len(stack) - 2; i >= 0; i-- {
+					switch fn := stack[i].(type) {
+					case *ast.FuncLit:
+						if !deferred[fn] {
+							l.report(n, "recover", "recover() has no effect outside of a deferred function")
+						}
+						return true
+					case *ast.FuncDecl:
+						l.report(n, "recover", "recover() has no effect outside of a deferred function")
+						return true
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Reports exported package-level variables of realms, which should be
+// accessed through functions.
+func (l *linter) lintExportedState() {
+	for _, f := range l.files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					if name.IsExported() {
+						l.report(name, "exported-state", "exported variable %s; realm state should be accessed through functions", name.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Reports exported functions of realms with parameters that cannot be
+// passed in a call transaction.
+func (l *linter) lintExportedFuncs() {
+	for _, f := range l.files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || !fd.Name.IsExported() {
+				continue
+			}
+			for _, field := range fd.Type.Params.List {
+				if _, ok := field.Type.(*ast.Ellipsis); ok {
+					l.report(field, "exported-func", "exported function %s has variadic parameters, which calls do not support", fd.Name.Name)
+				} else if !isCallArgType(field.Type) {
+					l.report(field, "exported-func", "exported function %s has a parameter of type %s, which calls cannot pass", fd.Name.Name, types.ExprString(field.Type))
+				}
+			}
+		}
+	}
+}
+
+// Returns whether the type expression x may be the type of an argument of
+// a call transaction: a boolean, string or integer, or a byte array or
+// slice. Named types are assumed to be.
+func isCallArgType(x ast.Expr) bool {
+	switch x := x.(type) {
+	case *ast.Ident:
+		switch x.Name {
+		case "float32", "float64", "complex64", "complex128", "error", "any":
+			return false
+		}
+		return true
+	case *ast.SelectorExpr:
+		return true
+	case *ast.ArrayType:
+		id, ok := x.Elt.(*ast.Ident)
+		return ok && (id.Name == "byte" || id.Name == "uint8")
+	case *ast.ParenExpr:
+		return isCallArgType(x.X)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/tests"
+)
+
+type lspOptions struct {
+	Verbose bool   `flag:"verbose" help:"log the messages received to stderr"`
+	RootDir string `flag:"root-dir" help:"clone location of github.com/gnolang/gno (gnodev tries to guess it)"`
+	Remote  string `flag:"remote" help:"address of a gnoland node, to import the packages not found locally"`
+}
+
+var DefaultLspOptions = lspOptions{
+	Verbose: false,
+	RootDir: "",
+	Remote:  "",
+}
+
+// Serves the language server protocol over stdin and stdout, for editors.
+// Only diagnostics are supported for now: the package of each open file is
+// parsed and type-checked as it is edited.
+func lspApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(lspOptions)
+	if len(args) > 0 {
+		cmd.ErrPrintfln("Usage: lsp [lsp flags]")
+		return errors.New("invalid args")
+	}
+
+	if opts.RootDir == "" {
+		opts.RootDir = guessRootDir()
+	}
+
+	// the preprocessor prints to stdout on errors, which is reserved for
+	// the protocol.
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	return newLspServer(opts, os.Stderr).serve(os.Stdin, stdout)
+}
+
+// lspServer holds the state of a session: the files open in the editor,
+// and the checker of their packages, whose store keeps the packages they
+// import.
+type lspServer struct {
+	checker *gno.Checker
+	store   gno.Store
+	rootDir string
+	remote  string
+	stderr  io.Writer
+	verbose bool
+
+	docs   map[string]string // bodies of the open files, by path.
+	loaded map[string]bool   // packages imported from remote or replaced.
+}
+
+func newLspServer(opts lspOptions, stderr io.Writer) *lspServer {
+	store := tests.TestStore(opts.RootDir, "", new(bytes.Buffer), stderr, stderr, tests.ImportModeStdlibsOnly)
+	return &lspServer{
+		checker: gno.NewChecker(store),
+		store:   store,
+		rootDir: opts.RootDir,
+		remote:  opts.Remote,
+		stderr:  stderr,
+		verbose: opts.Verbose,
+		docs:    make(map[string]string),
+		loaded:  make(map[string]bool),
+	}
+}
+
+// A JSON-RPC request, notification or response.
+type lspMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *lspError       `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const lspMethodNotFound = -32601
+
+type lspTextDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type lspTextDocumentParams struct {
+	TextDocument   lspTextDocument `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspPublishDiagnosticsParams struct {
+	URI         string          `json:"uri"`
+	Diagnostics []lspDiagnostic `json:"diagnostics"`
+}
+
+// Reads and handles messages from in until the exit notification, and
+// writes the responses and notifications to out.
+func (s *lspServer) serve(in io.Reader, out io.Writer) error {
+	r := bufio.NewReader(in)
+	for {
+		msg, err := readLspMessage(r)
+		if err != nil {
+			return err
+		}
+		if s.verbose {
+			fmt.Fprintf(s.stderr, "lsp: %s\n", msg.Method)
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		replies, err := s.handle(msg)
+		if err != nil {
+			return err
+		}
+		for _, reply := range replies {
+			if err := writeLspMessage(out, reply); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Returns the messages to send in reply to msg: its response if it is a
+// request, and diagnostics.
+func (s *lspServer) handle(msg lspMessage) ([]lspMessage, error) {
+	var params lspTextDocumentParams
+	switch msg.Method {
+	case "initialize":
+		return []lspMessage{{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Result:  json.RawMessage(`{"capabilities":{"textDocumentSync":1},"serverInfo":{"name":"gnodev"}}`),
+		}}, nil
+	case "shutdown":
+		return []lspMessage{{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(`null`)}}, nil
+	case "textDocument/didOpen", "textDocument/didChange", "textDocument/didSave", "textDocument/didClose":
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+	default:
+		if msg.ID != nil { // unsupported request.
+			return []lspMessage{{
+				JSONRPC: "2.0",
+				ID:      msg.ID,
+				Error:   &lspError{Code: lspMethodNotFound, Message: "method not found: " + msg.Method},
+			}}, nil
+		}
+		return nil, nil // unsupported notification.
+	}
+
+	u, err := url.Parse(params.TextDocument.URI)
+	if err != nil || u.Scheme != "file" {
+		return nil, nil // not a local file.
+	}
+	path := filepath.FromSlash(u.Path)
+	switch msg.Method {
+	case "textDocument/didOpen":
+		s.docs[path] = params.TextDocument.Text
+	case "textDocument/didChange":
+		// full text sync: the last change is the new body.
+		if n := len(params.ContentChanges); n > 0 {
+			s.docs[path] = params.ContentChanges[n-1].Text
+		}
+	case "textDocument/didClose":
+		delete(s.docs, path)
+	}
+	if !strings.HasSuffix(path, ".gno") {
+		return nil, nil
+	}
+	return s.checkDir(filepath.Dir(path))
+}
+
+// Checks the package in dir, with the bodies of its open files, and
+// returns the diagnostics of each of its files, to clear those fixed.
+func (s *lspServer) checkDir(dir string) ([]lspMessage, error) {
+	memPkg, mf, err := s.readPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(memPkg.Files) == 0 {
+		return nil, nil
+	}
+	var diags []gno.Diagnostic
+	if err := s.loadImports(dir, memPkg, mf); err != nil {
+		diags = []gno.Diagnostic{{File: memPkg.Files[0].Name, Line: 1, Msg: err.Error()}}
+	} else {
+		diags = s.checker.Check(memPkg)
+	}
+
+	byFile := make(map[string][]lspDiagnostic)
+	for _, mfile := range memPkg.Files {
+		byFile[mfile.Name] = []lspDiagnostic{}
+	}
+	for _, d := range diags {
+		pos := lspPosition{Line: d.Line - 1}
+		if d.Column > 0 {
+			pos.Character = d.Column - 1
+		}
+		byFile[d.File] = append(byFile[d.File], lspDiagnostic{
+			Range:    lspRange{Start: pos, End: pos},
+			Severity: 1, // error.
+			Source:   "gno",
+			Message:  d.Msg,
+		})
+	}
+	names := make([]string, 0, len(byFile))
+	for name := range byFile {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]lspMessage, 0, len(names))
+	for _, name := range names {
+		uri := url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, name))}
+		params, err := json.Marshal(lspPublishDiagnosticsParams{URI: uri.String(), Diagnostics: byFile[name]})
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, lspMessage{
+			JSONRPC: "2.0",
+			Method:  "textDocument/publishDiagnostics",
+			Params:  params,
+		})
+	}
+	return msgs, nil
+}
+
+// Returns the package in dir, with the bodies of its open files, and its
+// gno.mod file if any.  Its path is that of its gno.mod module, or else
+// its path in the examples of rootDir, or else dir.
+func (s *lspServer) readPackage(dir string) (*std.MemPackage, *gno.ModFile, error) {
+	fnames, err := filepath.Glob(filepath.Join(dir, "*.gno"))
+	if err != nil {
+		return nil, nil, err
+	}
+	for path := range s.docs {
+		if filepath.Dir(path) == dir && strings.HasSuffix(path, ".gno") && !containsString(fnames, path) {
+			fnames = append(fnames, path) // not saved yet.
+		}
+	}
+	sort.Strings(fnames)
+	memPkg := &std.MemPackage{Path: dir}
+	for _, fname := range fnames {
+		body, ok := s.docs[fname]
+		if !ok {
+			bz, err := ioutil.ReadFile(fname)
+			if err != nil {
+				return nil, nil, err
+			}
+			body = string(bz)
+		}
+		name := filepath.Base(fname)
+		memPkg.Files = append(memPkg.Files, &std.MemFile{Name: name, Body: body})
+		if memPkg.Name == "" && !strings.HasSuffix(name, "_test.gno") && !strings.HasSuffix(name, "_filetest.gno") {
+			// may be left empty, if no package clause parses.
+			if f, err := parser.ParseFile(token.NewFileSet(), name, body, parser.PackageClauseOnly); err == nil {
+				memPkg.Name = f.Name.Name
+			}
+		}
+	}
+
+	var mf *gno.ModFile
+	if bz, err := ioutil.ReadFile(filepath.Join(dir, gno.ModFileName)); err == nil {
+		mf, err = gno.ParseModFile(string(bz))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	examplesDir := filepath.Join(s.rootDir, "examples")
+	if mf != nil && mf.Module != "" {
+		memPkg.Path = mf.Module
+	} else if rel, err := filepath.Rel(examplesDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		memPkg.Path = filepath.ToSlash(rel)
+	}
+	return memPkg, mf, nil
+}
+
+// Loads the packages imported by memPkg which are replaced by its gno.mod
+// file, from their directories, or else which are not found locally, from
+// the remote node if any.  Packages are loaded once per session.
+func (s *lspServer) loadImports(dir string, memPkg *std.MemPackage, mf *gno.ModFile) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	imports, err := gno.MemPackageImports(memPkg)
+	if err != nil {
+		return nil // reported by the checker.
+	}
+	for _, path := range imports {
+		if mf != nil {
+			if mr, ok := mf.Replace(path); ok {
+				if s.loaded[path] {
+					continue
+				}
+				rdir := mr.Dir
+				if !filepath.IsAbs(rdir) {
+					rdir = filepath.Join(dir, rdir)
+				}
+				m2 := gno.NewMachineWithOptions(gno.MachineOptions{
+					PkgPath: "test",
+					Output:  s.stderr,
+					Store:   s.store,
+				})
+				m2.RunMemPackage(gno.ReadMemPackage(rdir, path), true)
+				s.loaded[path] = true
+				continue
+			}
+		}
+		if s.remote != "" {
+			cli := client.NewHTTP(s.remote, "/websocket")
+			if err := loadRemotePackage(s.store, s.stderr, cli, s.rootDir, s.loaded, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Reads a message with its base protocol header from r.
+func readLspMessage(r *bufio.Reader) (msg lspMessage, err error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return msg, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break // end of header.
+		}
+		if value := strings.TrimPrefix(line, "Content-Length:"); value != line {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return msg, fmt.Errorf("invalid header %q", line)
+			}
+		}
+	}
+	if length < 0 {
+		return msg, errors.New("missing Content-Length header")
+	}
+	bz := make([]byte, length)
+	if _, err := io.ReadFull(r, bz); err != nil {
+		return msg, err
+	}
+	err = json.Unmarshal(bz, &msg)
+	return msg, err
+}
+
+// Writes msg with its base protocol header to w.
+func writeLspMessage(w io.Writer, msg lspMessage) error {
+	bz, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(bz), bz)
+	return err
+}
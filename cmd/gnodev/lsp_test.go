@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLsp(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "a.gno"), []byte("package hello\n\nimport \"gno.land/p/avl\"\n\nvar tree = avl.NewTree(\"a\", 1)\n"), 0o644)
+	require.NoError(t, err)
+	uri := "file://" + filepath.ToSlash(filepath.Join(dir, "b.gno"))
+
+	in := new(bytes.Buffer)
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":%q,"text":"package hello\n\nfunc F() int {\n\treturn tree\n}\n"}}}`, uri),
+		fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":%q},"contentChanges":[{"text":"package hello\n\nfunc F() int {\n\treturn tree.Size()\n}\n"}]}}`, uri),
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	} {
+		fmt.Fprintf(in, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+	out := new(bytes.Buffer)
+	s := newLspServer(lspOptions{RootDir: "../.."}, new(bytes.Buffer))
+	require.NoError(t, s.serve(in, out))
+
+	var replies []string
+	r := bufio.NewReader(out)
+	for r.Buffered() > 0 || out.Len() > 0 {
+		msg, err := readLspMessage(r)
+		require.NoError(t, err)
+		bz, err := json.Marshal(msg)
+		require.NoError(t, err)
+		replies = append(replies, string(bz))
+	}
+	aURI := "file://" + filepath.ToSlash(filepath.Join(dir, "a.gno"))
+	require.Equal(t, []string{
+		`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"textDocumentSync":1},"serverInfo":{"name":"gnodev"}}}`,
+		// didOpen: a type error in the unsaved file.
+		fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":%q,"diagnostics":[]}}`, aURI),
+		fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":%q,"diagnostics":[{"range":{"start":{"line":3,"character":0},"end":{"line":3,"character":0}},"severity":1,"source":"gno","message":"cannot use *gno.land/p/avl.Tree as int"}]}}`, uri),
+		// didChange: fixed.
+		fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":%q,"diagnostics":[]}}`, aURI),
+		fmt.Sprintf(`{"jsonrpc":"2.0","method":"textDocument/publishDiagnostics","params":{"uri":%q,"diagnostics":[]}}`, uri),
+		`{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found: textDocument/hover"}}`,
+		`{"jsonrpc":"2.0","id":3,"result":null}`,
+	}, replies)
+}
@@ -29,8 +29,14 @@ var mainApps AppList = []AppItem{
 	{precompileApp, "precompile", "precompile .gno to .go", DefaultPrecompileOptions},
 	{testApp, "test", "test a gno package", DefaultTestOptions},
 	{replApp, "repl", "start a GnoVM REPL", DefaultReplOptions},
+	{runApp, "run", "run gno files", DefaultRunOptions},
+	{docApp, "doc", "show documentation of a gno package or symbol", DefaultDocOptions},
+	{lintApp, "lint", "check gno packages for issues of on-chain code", DefaultLintOptions},
+	{fmtApp, "fmt", "format gno source files", DefaultFmtOptions},
+	{modApp, "mod", "check gno.mod and list pinned imports of a package", DefaultModOptions},
+	{lspApp, "lsp", "serve the language server protocol over stdio, for editors", DefaultLspOptions},
+	{traceApp, "trace", "translate the locations of precompiled .go files in stdin to .gno", DefaultTraceOptions},
 
-	// fmt -- gofmt
 	// clean
 	// graph
 	// vendor -- download deps from the chain in vendor/
@@ -38,7 +44,6 @@ var mainApps AppList = []AppItem{
 	// run -- call render(), or maybe create a new main?
 	// publish/release
 	// generate
-	// doc -- godoc
 	// "vm" -- starts an in-memory chain that can be interacted with?
 	// bug -- start a bug report
 	// version -- show gnodev, golang versions
@@ -30,6 +30,12 @@ func TestMain(t *testing.T) {
 		{args: []string{"test"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: test [test flags] [packages]\n"},
 		{args: []string{"build"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: build [build flags] [packages]\n"},
 		{args: []string{"precompile"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: precompile [precompile flags] [packages]\n"},
+		{args: []string{"lint"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: lint [lint flags] [packages]\n"},
+		{args: []string{"run"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: run [flags] <file.gno> [<file.gno>...]\n"},
+		{args: []string{"fmt"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: fmt [fmt flags] [packages or files]\n"},
+		{args: []string{"mod"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: mod [mod flags] [tidy|download] <package>\n"},
+		{args: []string{"doc"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: doc [doc flags] <package> [<symbol>]\n"},
+		{args: []string{"trace", "foo"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: trace < <go output>\n"},
 		// {args: []string{"repl"}},
 
 		// --help
@@ -37,6 +43,11 @@ func TestMain(t *testing.T) {
 		{args: []string{"test", "--help"}, stdoutShouldContain: "# testOptions options\n-"},
 		{args: []string{"precompile", "--help"}, stdoutShouldContain: "# precompileOptions options\n-"},
 		{args: []string{"repl", "--help"}, stdoutShouldContain: "# replOptions options\n-"},
+		{args: []string{"run", "--help"}, stdoutShouldContain: "# runOptions options\n-"},
+		{args: []string{"lint", "--help"}, stdoutShouldContain: "# lintOptions options\n-"},
+		{args: []string{"fmt", "--help"}, stdoutShouldContain: "# fmtOptions options\n-"},
+		{args: []string{"mod", "--help"}, stdoutShouldContain: "# modOptions options\n-"},
+		{args: []string{"doc", "--help"}, stdoutShouldContain: "# docOptions options\n-"},
 
 		// custom
 		{args: []string{"test", "../../examples/gno.land/p/rand"}, stderrShouldContain: "ok      ./../../examples/gno.land/p/rand \t"},
@@ -53,6 +64,28 @@ func TestMain(t *testing.T) {
 		{args: []string{"test", "../../tests/integ/valid2", "--verbose"}, stderrShouldContain: "ok "},
 		{args: []string{"test", "../../tests/integ/failing1", "--verbose"}, errShouldBe: "FAIL: 1 go test errors", stderrShouldContain: "FAIL: TestAlwaysFailing"},
 		{args: []string{"test", "../../tests/integ/failing2", "--verbose"}, stderrShouldBe: "=== RUN   file/failing_filetest.gno\n", recoverShouldBe: "fail on ../../tests/integ/failing2/failing_filetest.gno: got unexpected error: beep boop"}, // FIXME: should fail
+		{args: []string{"lint", "../../tests/integ/valid1"}},
+		{args: []string{"lint", "../../tests/integ/lint1"}, errShouldBe: "7 lint issues", stderrShouldContain: "lint.gno:24:2: unbounded loop over map counts"},
+		{args: []string{"lint", "../../tests/integ/lint1", "--realm"}, errShouldBe: "11 lint issues", stderrShouldContain: "exported function SetRatio has variadic parameters"},
+		{args: []string{"fmt", "../../tests/integ/valid1", "--list"}},
+		{args: []string{"fmt", "../../tests/integ/unformatted1", "--list"}, errShouldBe: "1 unformatted files", stdoutShouldBe: "../../tests/integ/unformatted1/add.gno\n"},
+		{args: []string{"fmt", "../../tests/integ/unformatted1"}, stdoutShouldContain: "func Add(a, b int) int {\n\treturn a + b\n}\n"},
+		{args: []string{"mod", "../../tests/integ/mod1"}, stdoutShouldBe: "gno.land/p/avl\ngno.land/r/users v2\n"},
+		{args: []string{"mod", "../../tests/integ/mod2"}, stdoutShouldBe: "gno.land/p/avl => ../../../examples/gno.land/p/avl\ngno.land/r/users v2\n"},
+		{args: []string{"mod", "tidy", "../../tests/integ/mod2"}, errShouldBe: "remote not specified"},
+		{args: []string{"mod", "vendor", "../../tests/integ/mod2"}, errShouldBe: "invalid args", stderrShouldBe: "Usage: mod [mod flags] [tidy|download] <package>\n"},
+		{args: []string{"doc", "../../examples/gno.land/p/avl"}, stdoutShouldContain: "func NewTree(key string, value interface{}) *Tree\n"},
+		{args: []string{"doc", "../../examples/gno.land/p/avl", "Tree.Get"}, stdoutShouldContain: "func (tree *Tree) Get(key string) (index int, value interface{}, exists bool)\n"},
+		{args: []string{"doc", "../../examples/gno.land/p/avl", "Nope"}, errShouldBe: "no symbol Nope in package ../../examples/gno.land/p/avl"},
+		{args: []string{"doc", "../../examples/gno.land/r/users", "--json"}, stdoutShouldContain: `"name": "users"`},
+		{args: []string{"test", "../../tests/integ/fuzz1", "--verbose"}, stderrShouldContain: "--- PASS: FuzzReverse"},
+		{args: []string{"test", "../../tests/integ/fuzz1", "--fuzz", "Reverse", "--fuzz-iters", "100"}, stderrShouldContain: "ok "},
+		{args: []string{"test", "../../tests/integ/fuzz-failing"}, errShouldBe: "FAIL: 1 go test errors", stderrShouldContain: "--- FAIL: FuzzParse/582528ddfad69eb5"},
+		{args: []string{"test", "../../tests/integ/fuzz-failing", "--run", "FuzzParse/seed"}, stderrShouldContain: "ok "},
+		{args: []string{"test", "../../tests/integ/bench1"}, stderrShouldContain: "ok "},
+		{args: []string{"test", "../../tests/integ/bench1", "--bench", "Join$", "--benchtime", "10x"}, stderrShouldContain: "BenchmarkJoin\t      10\t"},
+		{args: []string{"test", "../../tests/integ/bench1", "--bench", "Join", "--benchtime", "10x"}, errShouldBe: "FAIL: 1 go test errors", stderrShouldContain: "--- FAIL: BenchmarkJoinFailing"},
+		{args: []string{"test", "../../tests/integ/bench1", "--bench", "Join", "--benchtime", "10"}, errShouldBe: "FAIL: 1 go test errors", stderrShouldContain: "FAIL "},
 
 		// test opts
 		{args: []string{"test", "../../examples/gno.land/p/ufmt"}, stderrShouldContain: "ok      ./../../examples/gno.land/p/ufmt"},
@@ -66,6 +99,7 @@ func TestMain(t *testing.T) {
 		{args: []string{"test", "../../examples/gno.land/p/ufmt", "--verbose", "--run", "Sprintf/"}, stderrShouldContain: "ok      ./../../examples/gno.land/p/ufmt"},
 		{args: []string{"test", "../../examples/gno.land/p/ufmt", "--verbose", "--run", "Sprintf/.*"}, stderrShouldContain: "ok      ./../../examples/gno.land/p/ufmt"},
 		{args: []string{"test", "../../examples/gno.land/p/ufmt", "--verbose", "--run", "Sprintf/hello"}, stderrShouldContain: "ok      ./../../examples/gno.land/p/ufmt"},
+		{args: []string{"test", "../../examples/gno.land/p/ufmt", "--cover"}, stderrShouldContain: "% of statements"},
 	}
 
 	for _, test := range tc {
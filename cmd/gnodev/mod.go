@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+type modOptions struct {
+	Verbose bool   `flag:"verbose" help:"verbose"`
+	Remote  string `flag:"remote" help:"address of a gnoland node, to resolve on-chain imports (required by tidy and download)"`
+	Cache   string `flag:"cache" help:"directory of downloaded packages (default <user cache dir>/gno/mod)"`
+}
+
+var DefaultModOptions = modOptions{
+	Verbose: false,
+	Remote:  "",
+	Cache:   "",
+}
+
+// Checks the gno.mod file of a package, and prints its on-chain imports
+// with the versions they are pinned to, or their replacements; or with
+// tidy, pins the imports which are not to their latest version on chain;
+// or with download, downloads the pinned imports and their dependencies
+// from chain, and prints their directories.
+func modApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(modOptions)
+	subcmd := ""
+	if len(args) == 2 {
+		subcmd, args = args[0], args[1:]
+	}
+	if len(args) != 1 || (subcmd != "" && subcmd != "tidy" && subcmd != "download") {
+		cmd.ErrPrintfln("Usage: mod [mod flags] [tidy|download] <package>")
+		return errors.New("invalid args")
+	}
+	if subcmd != "" && opts.Remote == "" {
+		return errors.New("remote not specified")
+	}
+
+	dir := args[0]
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("invalid package path: %w", err)
+	}
+	if !info.IsDir() {
+		return errors.New("%s is not a directory", dir)
+	}
+	memPkg := gno.ReadMemPackage(dir, dir)
+	mf, err := gno.ReadModFile(memPkg)
+	if err != nil {
+		return err
+	}
+	if mf == nil {
+		mf = &gno.ModFile{}
+		if opts.Verbose {
+			cmd.ErrPrintfln("%s: no %s file", dir, gno.ModFileName)
+		}
+	}
+	imports, err := gno.MemPackageImports(memPkg)
+	if err != nil {
+		return err
+	}
+	var onchain []string
+	for _, path := range imports {
+		if strings.HasPrefix(path, "gno.land/") { // not stdlib.
+			onchain = append(onchain, path)
+		}
+	}
+
+	switch subcmd {
+	case "tidy":
+		return modTidy(cmd, dir, mf, onchain, opts)
+	case "download":
+		return modDownload(cmd, dir, mf, onchain, opts)
+	}
+
+	imported := make(map[string]bool)
+	for _, path := range onchain {
+		imported[path] = true
+		if mr, ok := mf.Replace(path); ok {
+			cmd.Println(mr.String())
+		} else if mr, ok := mf.Require(path); ok {
+			cmd.Println(mr.String())
+		} else {
+			cmd.Println(path)
+		}
+	}
+	for _, mr := range mf.Requires {
+		if !imported[mr.Path] {
+			return errors.New("%s: %s is required but not imported", gno.ModFileName, mr.Path)
+		}
+	}
+
+	return nil
+}
+
+// Pins the on-chain imports which are neither pinned nor replaced to their
+// latest version, drops the requirements of packages not imported, and
+// writes the gno.mod file of the package in dir.
+func modTidy(cmd *command.Command, dir string, mf *gno.ModFile, onchain []string, opts modOptions) error {
+	cli := client.NewHTTP(opts.Remote, "/websocket")
+	requires := []gno.ModRequire{}
+	for _, path := range onchain {
+		if mr, ok := mf.Require(path); ok {
+			requires = append(requires, mr)
+			continue
+		}
+		if _, ok := mf.Replace(path); ok {
+			continue // may not be on chain yet.
+		}
+		versions, err := queryVersions(cli, path, 0)
+		if err != nil {
+			return fmt.Errorf("query versions of %s: %w", path, err)
+		}
+		mr := gno.ModRequire{Path: path, Version: len(versions)}
+		if opts.Verbose {
+			cmd.ErrPrintfln("%s: require %s", dir, mr)
+		}
+		requires = append(requires, mr)
+	}
+	for _, mr := range mf.Requires {
+		if opts.Verbose && !containsString(onchain, mr.Path) {
+			cmd.ErrPrintfln("%s: drop %s", dir, mr)
+		}
+	}
+	mf.Requires = requires
+	return ioutil.WriteFile(filepath.Join(dir, gno.ModFileName), []byte(mf.String()), 0o644)
+}
+
+// Downloads the versions of the on-chain imports pinned by mf, or their
+// first version if not pinned, as on chain, and their own on-chain
+// dependencies, into the cache; and prints the directory of each import,
+// i.e. its replacement or its download.
+func modDownload(cmd *command.Command, dir string, mf *gno.ModFile, onchain []string, opts modOptions) error {
+	cli := client.NewHTTP(opts.Remote, "/websocket")
+	cache := opts.Cache
+	if cache == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			return err
+		}
+		cache = filepath.Join(userCache, "gno", "mod")
+	}
+	var queue []string // resolved paths to download.
+	for _, path := range onchain {
+		if mr, ok := mf.Replace(path); ok {
+			rdir := mr.Dir
+			if !filepath.IsAbs(rdir) {
+				rdir = filepath.Join(dir, rdir)
+			}
+			cmd.Printfln("%s => %s", path, rdir)
+			continue
+		}
+		resolved := path
+		if mr, ok := mf.Require(path); ok {
+			// at the pinned height, if any.
+			versions, err := queryVersions(cli, path, mr.Height)
+			if err != nil {
+				return fmt.Errorf("query versions of %s: %w", path, err)
+			}
+			switch {
+			case mr.Version > len(versions):
+				return errors.New("version v%d of %s not found", mr.Version, path)
+			case mr.Version > 0:
+				resolved = versions[mr.Version-1]
+			default:
+				resolved = versions[len(versions)-1]
+			}
+		}
+		cmd.Printfln("%s => %s", path, filepath.Join(cache, filepath.FromSlash(resolved)))
+		queue = append(queue, resolved)
+	}
+	downloaded := make(map[string]bool)
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if downloaded[path] {
+			continue
+		}
+		downloaded[path] = true
+		memPkg, err := downloadPackage(cli, cache, path)
+		if err != nil {
+			return fmt.Errorf("download %s: %w", path, err)
+		}
+		if opts.Verbose {
+			cmd.ErrPrintfln("downloaded %s", path)
+		}
+		// on chain, imports are already resolved.
+		imports, err := gno.MemPackageImports(memPkg)
+		if err != nil {
+			return err
+		}
+		for _, imp := range imports {
+			if strings.HasPrefix(imp, "gno.land/") {
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the package at pkgPath from the cache, or else from chain, in
+// which case it is written to the cache.  Since added packages never
+// change, cached packages need not be refreshed.
+func downloadPackage(cli client.Client, cache string, pkgPath string) (*std.MemPackage, error) {
+	pkgDir := filepath.Join(cache, filepath.FromSlash(pkgPath))
+	if matches, _ := filepath.Glob(filepath.Join(pkgDir, "*.gno")); len(matches) > 0 {
+		return gno.ReadMemPackage(pkgDir, pkgPath), nil
+	}
+	memPkg, err := queryMemPackage(cli, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		return nil, err
+	}
+	for _, mfile := range memPkg.Files {
+		err := ioutil.WriteFile(filepath.Join(pkgDir, mfile.Name), []byte(mfile.Body), 0o644)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return memPkg, nil
+}
+
+// Returns the versions of the package at pkgPath on chain at height, or at
+// the latest height if zero, starting with pkgPath itself, through the
+// vm/qversions query.
+func queryVersions(cli client.Client, pkgPath string, height int64) ([]string, error) {
+	qres, err := cli.ABCIQueryWithOptions("vm/qversions", []byte(pkgPath),
+		client.ABCIQueryOptions{Height: height})
+	if err != nil {
+		return nil, err
+	}
+	if qres.Response.Error != nil {
+		return nil, qres.Response.Error
+	}
+	return strings.Split(string(qres.Response.Data), "\n"), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -88,7 +88,7 @@ func precompileFile(srcPath string, opts precompileOptions) error {
 	}
 
 	// preprocess.
-	transformed, err := gno.Precompile(string(source), tags, srcPath)
+	transformed, sm, err := gno.PrecompileWithSourceMap(string(source), tags, srcPath)
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
@@ -101,6 +101,14 @@ func precompileFile(srcPath string, opts precompileOptions) error {
 		return fmt.Errorf("write .go file: %w", err)
 	}
 
+	// write source map, to translate go errors and traces back to .gno.
+	if sm != nil {
+		err = gno.WriteSourceMap(targetPath, sm)
+		if err != nil {
+			return fmt.Errorf("write source map: %w", err)
+		}
+	}
+
 	// check .go fmt.
 	if shouldCheckFmt {
 		err = gno.PrecompileVerifyFile(targetPath, gofmt)
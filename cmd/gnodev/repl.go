@@ -1,33 +1,47 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"golang.org/x/term"
 
 	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
 	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/errors"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/stdlibs"
 	"github.com/gnolang/gno/tests"
 )
 
 type replOptions struct {
 	Verbose bool   `flag:"verbose" help:"verbose"`
 	RootDir string `flag:"root-dir" help:"clone location of github.com/gnolang/gno (gnodev tries to guess it)"`
+	Debug   bool   `flag:"debug" help:"step through each input in the interactive debugger"`
+	Session string `flag:"session" help:"file to restore the session from, and to save it to"`
+	Remote  string `flag:"remote" help:"address of a gnoland node, to import the packages not found locally"`
 	// Run string `flag:"run" help:"test name filtering pattern"`
 	// Timeout time.Duration `flag:"timeout" help:"max execution time"`
 	// VM Options
 	// A flag about if we should download the production realms
 	// UseNativeLibs bool // experimental, but could be useful for advanced developer needs
 	// AutoImport bool
-	// ImportPkgs...
 }
 
 var DefaultReplOptions = replOptions{
 	Verbose: false,
 	RootDir: "",
+	Session: "",
+	Remote:  "",
 }
 
 func replApp(cmd *command.Command, args []string, iopts interface{}) error {
@@ -41,56 +55,364 @@ func replApp(cmd *command.Command, args []string, iopts interface{}) error {
 		opts.RootDir = guessRootDir()
 	}
 
-	return runRepl(opts.RootDir, opts.Verbose)
+	return runRepl(opts)
 }
 
-func runRepl(rootDir string, verbose bool) error {
-	stdin := os.Stdin
-	stdout := os.Stdout
-	stderr := os.Stderr
+func runRepl(opts replOptions) error {
+	r := newRepl(opts, os.Stdin, os.Stdout, os.Stderr)
 
-	// init store and machine
-	testStore := tests.TestStore(rootDir, "", stdin, stdout, stderr, tests.ImportModeStdlibsOnly)
-	if verbose {
-		testStore.SetLogStoreOps(true)
+	// restore the session.
+	var session io.Writer
+	if opts.Session != "" {
+		n, err := r.restoreSession(opts.Session)
+		if err != nil {
+			return fmt.Errorf("restore session: %w", err)
+		}
+		if n > 0 {
+			fmt.Fprintf(os.Stderr, "restored %d inputs from %s\n", n, opts.Session)
+		}
+		f, err := os.OpenFile(opts.Session, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("open session: %w", err)
+		}
+		defer f.Close()
+		session = f
 	}
-	m := gno.NewMachineWithOptions(gno.MachineOptions{
-		PkgPath: "test",
-		Store:   testStore,
-	})
 
-	// init termui
-	rw := struct {
-		io.Reader
-		io.Writer
-	}{os.Stdin, os.Stderr}
-	t := term.NewTerminal(rw, "")
+	// init termui, or read lines if stdin is not a terminal.
+	var readLine func() (string, error)
+	if term.IsTerminal(0) {
+		rw := struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stderr}
+		t := term.NewTerminal(rw, "")
+		readLine = func() (string, error) {
+			t.SetPrompt(fmt.Sprintf("gno:%d> ", r.i+1))
+			oldState, err := term.MakeRaw(0)
+			if err != nil {
+				return "", err
+			}
+			defer term.Restore(0, oldState)
+			return t.ReadLine()
+		}
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		readLine = func() (string, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return "", err
+				}
+				return "", io.EOF
+			}
+			return scanner.Text(), nil
+		}
+	}
 
 	// main loop
-	for i := 1; ; i++ {
+	for {
 		// parse line and execute
-		t.SetPrompt(fmt.Sprintf("gno:%d> ", i))
-		oldState, err := term.MakeRaw(0)
-		input, err := t.ReadLine()
+		input, err := readLine()
 		if err != nil {
-			term.Restore(0, oldState)
 			if err == io.EOF {
 				return nil
 			}
 			return fmt.Errorf("term error: %w", err)
 		}
-		term.Restore(0, oldState)
 
-		funcName := fmt.Sprintf("repl_%d", i)
-		src := "package test\nfunc " + funcName + "() {\n" + input + "\n}"
-		// FIXME: support ";" as line separator?
-		// FIXME: gofmt as linter + formatter
-		// FIXME: support multiline when unclosed parenthesis, etc
+		if err := r.handleInput(input); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			continue
+		}
+		if session != nil && isReplSessionInput(input) {
+			fmt.Fprintln(session, input)
+		}
+	}
+}
+
+// Returns true if input changes the state of the session, and should be
+// saved.
+func isReplSessionInput(input string) bool {
+	input = strings.TrimSpace(input)
+	return input != "" && input != "/context" && input != "/help"
+}
+
+// repl holds the state of a session: a machine running inputs in the
+// package "test", the imports declared so far, and the std context of
+// calls, which may be changed with commands.
+type repl struct {
+	m       *gno.Machine
+	store   gno.Store
+	snap    *gno.StoreSnapshot // before the first input, for /reset.
+	ctx     stdlibs.ExecContext
+	rootDir string
+	remote  string
+	stderr  io.Writer
+	debug   bool
 
-		n := gno.MustParseFile(funcName+".gno", src)
-		m.RunFiles(n)
-		m.RunStatement(gno.S(gno.Call(gno.X(funcName))))
+	i       int             // number of inputs run.
+	imports []string        // import declarations.
+	loaded  map[string]bool // packages imported from remote.
+}
+
+func newRepl(opts replOptions, stdin io.Reader, stdout, stderr io.Writer) *repl {
+	// init store and machine
+	testStore := tests.TestStore(opts.RootDir, "", stdin, stdout, stderr, tests.ImportModeStdlibsOnly)
+	if opts.Verbose {
+		testStore.SetLogStoreOps(true)
+	}
+	// the default std context of tests, with the test banker.
+	ctx := tests.TestMachine(testStore, stdout, "test").Context.(stdlibs.ExecContext)
+	snap := testStore.Snapshot()
+	m := gno.NewMachineWithOptions(gno.MachineOptions{
+		PkgPath: "test",
+		Output:  stdout,
+		Store:   testStore,
+		Context: ctx,
+	})
+	if opts.Debug {
+		m.Debugger = gno.NewDebugger(stdin, stderr)
 	}
+	return &repl{
+		m:       m,
+		store:   testStore,
+		snap:    snap,
+		ctx:     ctx,
+		rootDir: opts.RootDir,
+		remote:  opts.Remote,
+		stderr:  stderr,
+		debug:   opts.Debug,
+		loaded:  make(map[string]bool),
+	}
+}
+
+// Resets the session to its initial state, without rebuilding the store: a
+// new package "test", in the store restored to its snapshot taken before
+// the first input, and the default std context.
+func (r *repl) reset() {
+	r.store.Restore(r.snap)
+	m := gno.NewMachineWithOptions(gno.MachineOptions{
+		PkgPath: "test",
+		Output:  r.m.Output,
+		Store:   r.store,
+		Context: r.ctx,
+	})
+	m.Debugger = r.m.Debugger
+	r.m = m
+	r.i = 0
+	r.imports = nil
+	r.loaded = make(map[string]bool)
+}
+
+const replHelp = `inputs are statements, or declarations of imports, functions, types,
+variables and constants. commands:
+  /caller <address>   set the caller address of std.GetOrigCaller()
+  /send <coins>       set the coins sent, of std.GetOrigSend()
+  /height <height>    set the block height of std.GetHeight()
+  /context            print the std context
+  /reset              reset the session to its initial state
+  /help               print this help
+`
+
+var replDefineRe = regexp.MustCompile(`^([a-zA-Z_]\w*(?:\s*,\s*[a-zA-Z_]\w*)*)\s*:=\s*(.+)$`)
+
+// Runs an input, and returns an error if it fails.
+func (r *repl) handleInput(input string) (err error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil
+	}
+	if strings.HasPrefix(input, "/") {
+		return r.handleCommand(input)
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			if rec == gno.ErrDebuggerQuit {
+				err = errors.New("debugger: quit")
+			} else {
+				err = errors.New("error: %v", rec)
+			}
+		}
+	}()
 
+	r.i++
+	fname := fmt.Sprintf("repl_%d", r.i)
+	// FIXME: support ";" as line separator?
+	// FIXME: support multiline when unclosed parenthesis, etc
+	// short variable declarations define package variables, which are
+	// kept for later inputs.
+	if m := replDefineRe.FindStringSubmatch(input); m != nil {
+		input = "var " + m[1] + " = " + m[2]
+	}
+	var src string
+	decl := false
+	switch strings.SplitN(input, " ", 2)[0] {
+	case "import":
+		if err := r.loadImports(input); err != nil {
+			return err
+		}
+		src = "package test\n" + input + "\n"
+		decl = true
+	case "func", "type", "var", "const":
+		src = "package test\n" + strings.Join(r.imports, "\n") + "\n" + input + "\n"
+		decl = true
+	default:
+		src = "package test\n" + strings.Join(r.imports, "\n") + "\n" +
+			"func " + fname + "() {\n" + input + "\n}"
+	}
+
+	n := gno.MustParseFile(fname+".gno", src)
+	if r.debug {
+		r.m.Debugger.AddMemPackage(&std.MemPackage{
+			Name:  "test",
+			Path:  "test",
+			Files: []*std.MemFile{{Name: fname + ".gno", Body: src}},
+		})
+		r.m.Debugger.Step()
+	}
+	r.m.RunFiles(n)
+	if strings.HasPrefix(input, "import") {
+		r.imports = append(r.imports, input)
+	}
+	if !decl {
+		r.m.RunStatement(gno.S(gno.Call(gno.X(fname))))
+	}
 	return nil
 }
+
+func (r *repl) handleCommand(input string) error {
+	fields := strings.Fields(input)
+	cmd, args := fields[0], fields[1:]
+	ctx := r.m.Context.(stdlibs.ExecContext)
+	switch cmd {
+	case "/caller":
+		if len(args) != 1 {
+			return errors.New("usage: /caller <address>")
+		}
+		if _, err := crypto.AddressFromBech32(args[0]); err != nil {
+			return errors.New("invalid address %s: %v", args[0], err)
+		}
+		ctx.OrigCaller = crypto.Bech32Address(args[0])
+	case "/send":
+		if len(args) != 1 {
+			return errors.New("usage: /send <coins>")
+		}
+		coins, err := std.ParseCoins(args[0])
+		if err != nil {
+			return errors.New("invalid coins %s: %v", args[0], err)
+		}
+		ctx.OrigSend = coins
+		ctx.OrigSendSpent = new(std.Coins)
+	case "/height":
+		if len(args) != 1 {
+			return errors.New("usage: /height <height>")
+		}
+		height, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || height < 0 {
+			return errors.New("invalid height %s", args[0])
+		}
+		ctx.Height = height
+	case "/context":
+		fmt.Fprintf(r.stderr, "caller: %s\nsend: %s\nheight: %d\n",
+			ctx.OrigCaller, ctx.OrigSend, ctx.Height)
+	case "/help":
+		fmt.Fprint(r.stderr, replHelp)
+	case "/reset":
+		r.reset()
+		return nil
+	default:
+		return errors.New("unknown command %s; type /help for commands", cmd)
+	}
+	r.m.Context = ctx
+	return nil
+}
+
+// Loads the packages imported by the import declaration input, which are
+// not found locally, from the remote node if any.
+func (r *repl) loadImports(input string) error {
+	memPkg := &std.MemPackage{
+		Name:  "test",
+		Path:  "test",
+		Files: []*std.MemFile{{Name: "import.gno", Body: "package test\n" + input + "\n"}},
+	}
+	paths, err := gno.MemPackageImports(memPkg)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := r.loadRemote(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Loads the package at pkgPath and its dependencies from the remote node,
+// unless it is not an on-chain package or it is found locally.  The
+// package is initialized locally: its state on chain is not imported.
+func (r *repl) loadRemote(pkgPath string) error {
+	if r.remote == "" {
+		return nil
+	}
+	return loadRemotePackage(r.store, r.m.Output, client.NewHTTP(r.remote, "/websocket"), r.rootDir, r.loaded, pkgPath)
+}
+
+// Loads the package at pkgPath and its dependencies from cli into store,
+// unless it is not an on-chain package, it is found locally in rootDir, or
+// it is already loaded, and marks them as loaded.
+func loadRemotePackage(store gno.Store, output io.Writer, cli client.Client, rootDir string, loaded map[string]bool, pkgPath string) error {
+	if !strings.HasPrefix(pkgPath, "gno.land/") || loaded[pkgPath] {
+		return nil
+	}
+	if osm.DirExists(filepath.Join(rootDir, "examples", pkgPath)) {
+		return nil
+	}
+	memPkg, err := queryMemPackage(cli, pkgPath)
+	if err != nil {
+		return fmt.Errorf("import %s: %w", pkgPath, err)
+	}
+	// load dependencies first.
+	imports, err := gno.MemPackageImports(memPkg)
+	if err != nil {
+		return err
+	}
+	for _, path := range imports {
+		if err := loadRemotePackage(store, output, cli, rootDir, loaded, path); err != nil {
+			return err
+		}
+	}
+	m2 := gno.NewMachineWithOptions(gno.MachineOptions{
+		PkgPath: "test",
+		Output:  output,
+		Store:   store,
+	})
+	m2.RunMemPackage(memPkg, true)
+	loaded[pkgPath] = true
+	return nil
+}
+
+// Runs the inputs saved in the session file at path, if it exists, without
+// output, and returns their number.
+func (r *repl) restoreSession(path string) (int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	output := r.m.Output
+	r.m.Output = io.Discard
+	defer func() { r.m.Output = output }()
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		input := scanner.Text()
+		if err := r.handleInput(input); err != nil {
+			return n, fmt.Errorf("%s: %q: %w", path, input, err)
+		}
+		n++
+	}
+	return n, scanner.Err()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepl(t *testing.T) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	r := newRepl(replOptions{RootDir: "../.."}, new(bytes.Buffer), stdout, stderr)
+
+	for _, input := range []string{
+		`import "std"`,
+		`import "gno.land/p/avl"`,
+		`tree := avl.NewTree("a", 1)`,
+		`func Double(x int) int { return 2 * x }`,
+		`println(tree.Size(), Double(21))`,
+		`/caller g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5`,
+		`/send 100ugnot`,
+		`/height 42`,
+		`println(std.GetOrigCaller(), std.GetOrigSend(), std.GetHeight())`,
+	} {
+		require.NoError(t, r.handleInput(input), input)
+	}
+	require.Equal(t, "1 42\ng1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5 100ugnot 42\n", stdout.String())
+
+	// errors do not end the session.
+	require.Error(t, r.handleInput(`println(undefined)`))
+	require.Error(t, r.handleInput(`/caller invalid`))
+	require.Error(t, r.handleInput(`/unknown`))
+	stdout.Reset()
+	require.NoError(t, r.handleInput(`println(Double(tree.Size()))`))
+	require.Equal(t, "2\n", stdout.String())
+}
+
+func TestReplRestoreSession(t *testing.T) {
+	session := filepath.Join(t.TempDir(), "session.gno")
+	err := os.WriteFile(session, []byte("x := 20\n/height 22\nprintln(\"not printed\")\n"), 0o644)
+	require.NoError(t, err)
+
+	stdout := new(bytes.Buffer)
+	r := newRepl(replOptions{RootDir: "../.."}, new(bytes.Buffer), stdout, new(bytes.Buffer))
+	n, err := r.restoreSession(session)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.NoError(t, r.handleInput(`import "std"`))
+	require.NoError(t, r.handleInput(`println(int64(x) + std.GetHeight())`))
+	require.Equal(t, "42\n", stdout.String())
+}
+
+func TestReplReset(t *testing.T) {
+	stdout := new(bytes.Buffer)
+	r := newRepl(replOptions{RootDir: "../.."}, new(bytes.Buffer), stdout, new(bytes.Buffer))
+	for _, input := range []string{
+		`import "gno.land/p/avl"`,
+		`tree := avl.NewTree("a", 1)`,
+		`func Double(x int) int { return 2 * x }`,
+		`/height 42`,
+		`/reset`,
+	} {
+		require.NoError(t, r.handleInput(input), input)
+	}
+	require.Equal(t, 0, r.i)
+
+	// the declarations and imports are gone, and may be declared again.
+	require.Error(t, r.handleInput(`println(Double(21))`))
+	require.Error(t, r.handleInput(`println(tree.Size())`))
+	for _, input := range []string{
+		`import "std"`,
+		`import "gno.land/p/avl"`,
+		`tree := avl.NewTree("b", 2)`,
+		`println(tree.Size(), std.GetHeight())`,
+	} {
+		require.NoError(t, r.handleInput(input), input)
+	}
+	require.Equal(t, "1 123\n", stdout.String())
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/tests"
+)
+
+type runOptions struct {
+	Verbose bool   `flag:"verbose" help:"verbose"`
+	RootDir string `flag:"root-dir" help:"clone location of github.com/gnolang/gno (gnodev tries to guess it)"`
+	Debug   bool   `flag:"debug" help:"run in the interactive debugger"`
+}
+
+var DefaultRunOptions = runOptions{
+	Verbose: false,
+	RootDir: "",
+}
+
+func runApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(runOptions)
+	if len(args) == 0 {
+		cmd.ErrPrintfln("Usage: run [flags] <file.gno> [<file.gno>...]")
+		return errors.New("invalid args")
+	}
+
+	if opts.RootDir == "" {
+		opts.RootDir = guessRootDir()
+	}
+
+	// read files into a main package.
+	memPkg := &std.MemPackage{Name: "main", Path: "main"}
+	for _, fname := range args {
+		bz, err := os.ReadFile(fname)
+		if err != nil {
+			return err
+		}
+		memPkg.Files = append(memPkg.Files, &std.MemFile{
+			Name: filepath.Base(fname),
+			Body: string(bz),
+		})
+	}
+
+	stdin := os.Stdin
+	stdout := os.Stdout
+	stderr := os.Stderr
+
+	testStore := tests.TestStore(opts.RootDir, "", stdin, stdout, stderr, tests.ImportModeStdlibsOnly)
+	if opts.Verbose {
+		testStore.SetLogStoreOps(true)
+	}
+	m := gno.NewMachineWithOptions(gno.MachineOptions{
+		PkgPath: "main",
+		Output:  stdout,
+		Store:   testStore,
+	})
+	if opts.Debug {
+		m.Debugger = gno.NewDebugger(stdin, stderr)
+		m.Debugger.AddMemPackage(memPkg)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if r == gno.ErrDebuggerQuit {
+				return
+			}
+			panic(r)
+		}
+	}()
+	m.RunMemPackage(memPkg, false)
+	// not m.RunMain(), which prints the machine on panics, as when quitting
+	// the debugger.
+	m.RunStatement(gno.S(gno.Call(gno.X("main"))))
+	return nil
+}
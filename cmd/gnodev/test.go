@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"text/template"
@@ -26,6 +27,14 @@ type testOptions struct {
 	Verbose bool   `flag:"verbose" help:"verbose"`
 	RootDir string `flag:"root-dir" help:"clone location of github.com/gnolang/gno (gnodev tries to guess it)"`
 	Run     string `flag:"run" help:"test name filtering pattern"`
+	Cover   bool   `flag:"cover" help:"report the coverage of packages by their unit tests (filetests are not counted)"`
+	// CoverProfile implies Cover.
+	CoverProfile string `flag:"coverprofile" help:"write a coverage profile to this file, in the format of go tool cover"`
+	Fuzz         string `flag:"fuzz" help:"fuzz the fuzz targets matching this pattern, with generated inputs"`
+	FuzzIters    int    `flag:"fuzz-iters" help:"number of inputs to generate per fuzz target"`
+	FuzzSeed     int64  `flag:"fuzz-seed" help:"seed of the generated inputs, for deterministic fuzzing"`
+	Bench        string `flag:"bench" help:"run the benchmarks matching this pattern"`
+	BenchTime    string `flag:"benchtime" help:"run each benchmark for this duration, or this many times with the form Nx"`
 	// Timeout time.Duration `flag:"timeout" help:"max execution time"`
 	// VM Options
 	// A flag about if we should download the production realms
@@ -33,8 +42,11 @@ type testOptions struct {
 }
 
 var DefaultTestOptions = testOptions{
-	Verbose: false,
-	RootDir: "",
+	Verbose:   false,
+	RootDir:   "",
+	FuzzIters: 1000,
+	FuzzSeed:  1,
+	BenchTime: "1s",
 }
 
 func testApp(cmd *command.Command, args []string, iopts interface{}) error {
@@ -54,6 +66,11 @@ func testApp(cmd *command.Command, args []string, iopts interface{}) error {
 		return fmt.Errorf("list packages from args: %w", err)
 	}
 
+	var cov *gno.Coverage
+	if opts.Cover || opts.CoverProfile != "" {
+		cov = gno.NewCoverage()
+	}
+
 	errCount := 0
 	for _, pkgPath := range pkgPaths {
 		unittestFiles, err := filepath.Glob(filepath.Join(pkgPath, "*_test.gno"))
@@ -73,7 +90,7 @@ func testApp(cmd *command.Command, args []string, iopts interface{}) error {
 		sort.Strings(filetestFiles)
 
 		startedAt := time.Now()
-		err = gnoTestPkg(cmd, pkgPath, unittestFiles, filetestFiles, opts)
+		err = gnoTestPkg(cmd, pkgPath, unittestFiles, filetestFiles, cov, opts)
 		duration := time.Since(startedAt)
 		dstr := fmtDuration(duration)
 
@@ -83,10 +100,21 @@ func testApp(cmd *command.Command, args []string, iopts interface{}) error {
 			cmd.ErrPrintfln("FAIL    %s \t%s", pkgPath, dstr)
 			cmd.ErrPrintfln("FAIL")
 			errCount++
+		} else if cov != nil {
+			cmd.ErrPrintfln("ok      %s \t%s\tcoverage: %.1f%% of statements", pkgPath, dstr, cov.Percent(pkgPath))
+			if opts.Verbose {
+				cov.WriteFuncReport(cmd.ErrBuf, pkgPath)
+				cmd.ErrBuf.Flush()
+			}
 		} else {
 			cmd.ErrPrintfln("ok      %s \t%s", pkgPath, dstr)
 		}
 	}
+	if opts.CoverProfile != "" {
+		if err := writeCoverProfile(cov, opts.CoverProfile); err != nil {
+			return fmt.Errorf("write coverage profile: %w", err)
+		}
+	}
 	if errCount > 0 {
 		cmd.ErrPrintfln("FAIL")
 		return fmt.Errorf("FAIL: %d go test errors", errCount)
@@ -95,7 +123,7 @@ func testApp(cmd *command.Command, args []string, iopts interface{}) error {
 	return nil
 }
 
-func gnoTestPkg(cmd *command.Command, pkgPath string, unittestFiles, filetestFiles []string, opts testOptions) error {
+func gnoTestPkg(cmd *command.Command, pkgPath string, unittestFiles, filetestFiles []string, cov *gno.Coverage, opts testOptions) error {
 	verbose := opts.Verbose
 	rootDir := opts.RootDir
 	runFlag := opts.Run
@@ -116,6 +144,9 @@ func gnoTestPkg(cmd *command.Command, pkgPath string, unittestFiles, filetestFil
 			stdout = os.Stdout
 		}
 		memPkg := gno.ReadMemPackage(pkgPath, pkgPath)
+		if cov != nil {
+			cov.AddMemPackage(memPkg)
+		}
 
 		// tfiles, ifiles := gno.ParseMemPackageTests(memPkg)
 		tfiles, ifiles := parseMemPackageTests(memPkg)
@@ -123,8 +154,11 @@ func gnoTestPkg(cmd *command.Command, pkgPath string, unittestFiles, filetestFil
 		// run test files in pkg
 		{
 			m := tests.TestMachine(testStore, stdout, "main")
-			m.RunMemPackage(memPkg, true)
-			err := runTestFiles(cmd, testStore, m, tfiles, memPkg.Name, verbose, runFlag)
+			m.Coverage = cov
+			// the package is saved by runTestFiles, with its test files.
+			m.RunMemPackage(memPkg, false)
+			testStore.AddMemPackage(memPkg)
+			err := runTestFiles(cmd, testStore, m, tfiles, memPkg.Name, pkgPath, opts)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -135,8 +169,9 @@ func gnoTestPkg(cmd *command.Command, pkgPath string, unittestFiles, filetestFil
 			testPkgName := getPkgNameFromFileset(ifiles)
 			if testPkgName != "" {
 				m := tests.TestMachine(testStore, stdout, testPkgName)
+				m.Coverage = cov
 				m.RunMemPackage(memPkg, true)
-				err := runTestFiles(cmd, testStore, m, ifiles, testPkgName, verbose, runFlag)
+				err := runTestFiles(cmd, testStore, m, ifiles, testPkgName, pkgPath, opts)
 				if err != nil {
 					errs = multierr.Append(errs, err)
 				}
@@ -190,7 +225,22 @@ func gnoTestPkg(cmd *command.Command, pkgPath string, unittestFiles, filetestFil
 	return errs
 }
 
-func runTestFiles(cmd *command.Command, testStore gno.Store, m *gno.Machine, files *gno.FileSet, pkgName string, verbose bool, runFlag string) error {
+func writeCoverProfile(cov *gno.Coverage, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := cov.WriteProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runTestFiles(cmd *command.Command, testStore gno.Store, m *gno.Machine, files *gno.FileSet, pkgName string, pkgPath string, opts testOptions) error {
+	verbose := opts.Verbose
+	runFlag := opts.Run
+
 	var errs error
 
 	testFuncs := &testFuncs{
@@ -208,6 +258,8 @@ func runTestFiles(cmd *command.Command, testStore gno.Store, m *gno.Machine, fil
 	m.RunFiles(files.Files...)
 	n := gno.MustParseFile("testmain.go", testmain)
 	m.RunFiles(n)
+	// each test runs from the state of the package once initialized.
+	snap := m.Snapshot()
 
 	for _, test := range testFuncs.Tests {
 		if verbose {
@@ -217,6 +269,7 @@ func runTestFiles(cmd *command.Command, testStore gno.Store, m *gno.Machine, fil
 		testFuncStr := fmt.Sprintf("%q", test.Name)
 
 		startedAt := time.Now()
+		m.Restore(snap)
 		eval := m.Eval(gno.Call("runtest", testFuncStr))
 		duration := time.Since(startedAt)
 		dstr := fmtDuration(duration)
@@ -261,6 +314,34 @@ func runTestFiles(cmd *command.Command, testStore gno.Store, m *gno.Machine, fil
 		}
 	}
 
+	filter := splitRegexp(runFlag)
+	for _, target := range testFuncs.FuzzTargets {
+		if !shouldRun(filter, target.Name) {
+			continue
+		}
+		m.Restore(snap)
+		err := runFuzzTarget(cmd, m, target.Name, pkgPath, opts)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
+	if opts.Bench != "" {
+		for _, benchmark := range testFuncs.Benchmarks {
+			match, err := regexp.MatchString(opts.Bench, benchmark.Name)
+			if err != nil {
+				return errors.Wrap(err, "invalid --bench pattern")
+			}
+			if !match {
+				continue
+			}
+			err = runBenchmark(cmd, m, snap, benchmark.Name, opts)
+			if err != nil {
+				errs = multierr.Append(errs, err)
+			}
+		}
+	}
+
 	return errs
 }
 
@@ -287,6 +368,18 @@ var tests = []testing.InternalTest{
 {{end}}
 }
 
+var fuzzTargets = []testing.InternalFuzzTarget{
+{{range .FuzzTargets}}
+    {"{{.Name}}", {{.Name}}},
+{{end}}
+}
+
+var benchmarks = []testing.InternalBenchmark{
+{{range .Benchmarks}}
+    {"{{.Name}}", {{.Name}}},
+{{end}}
+}
+
 func runtest(name string) (report string) {
 	for _, test := range tests {
 		if test.Name == name {
@@ -296,10 +389,32 @@ func runtest(name string) (report string) {
 	panic("no such test: " + name)
 	return ""
 }
+
+func runfuzztarget(name string) *testing.F {
+	for _, target := range fuzzTargets {
+		if target.Name == name {
+			return testing.RunFuzzTarget({{.Verbose}}, target)
+		}
+	}
+	panic("no such fuzz target: " + name)
+	return nil
+}
+
+func runbenchmark(name string, n int) (report string) {
+	for _, benchmark := range benchmarks {
+		if benchmark.Name == name {
+			return testing.RunBenchmark({{.Verbose}}, benchmark, n)
+		}
+	}
+	panic("no such benchmark: " + name)
+	return ""
+}
 `))
 
 type testFuncs struct {
 	Tests       []testFunc
+	FuzzTargets []testFunc
+	Benchmarks  []testFunc
 	PackageName string
 	Verbose     bool
 	RunFlag     string
@@ -336,6 +451,18 @@ func loadTestFuncs(pkgName string, t *testFuncs, tfiles *gno.FileSet) *testFuncs
 						Name:    fname,
 					}
 					t.Tests = append(t.Tests, tf)
+				} else if strings.HasPrefix(fname, "Fuzz") {
+					tf := testFunc{
+						Package: pkgName,
+						Name:    fname,
+					}
+					t.FuzzTargets = append(t.FuzzTargets, tf)
+				} else if strings.HasPrefix(fname, "Benchmark") {
+					tf := testFunc{
+						Package: pkgName,
+						Name:    fname,
+					}
+					t.Benchmarks = append(t.Benchmarks, tf)
 				}
 			}
 		}
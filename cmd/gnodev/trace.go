@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+type traceOptions struct{}
+
+var DefaultTraceOptions = traceOptions{}
+
+// Copies stdin to stdout, with the locations in precompiled .go files
+// translated to .gno with their source maps, e.g. for the panics and stack
+// traces of `go test` on precompiled packages:
+//
+//	go test -tags=gno ./examples/... 2>&1 | gnodev trace
+func traceApp(cmd *command.Command, args []string, iopts interface{}) error {
+	if len(args) != 0 {
+		cmd.ErrPrintfln("Usage: trace < <go output>")
+		return errors.New("invalid args")
+	}
+
+	sms := gno.NewSourceMaps()
+	for {
+		line, err := cmd.InBuf.ReadString('\n')
+		if line != "" {
+			cmd.Printf("%s", sms.Translate(line))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
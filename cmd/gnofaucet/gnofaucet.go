@@ -1,38 +1,24 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
 	"os"
-	"strings"
+	"path/filepath"
 	"time"
 
-	"github.com/gnolang/gno/gnoland"
-	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/gnoland/faucet"
 	rpcclient "github.com/gnolang/gno/pkgs/bft/rpc/client"
 	"github.com/gnolang/gno/pkgs/command"
 	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/crypto/keys"
 	"github.com/gnolang/gno/pkgs/crypto/keys/client"
+	dbm "github.com/gnolang/gno/pkgs/db"
 	"github.com/gnolang/gno/pkgs/errors"
-	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/log"
 	"github.com/gnolang/gno/pkgs/std"
 )
 
-// url & struct for verify captcha
-const siteVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
-
-type SiteVerifyResponse struct {
-	Success     bool      `json:"success"`
-	Score       float64   `json:"score"`
-	Action      string    `json:"action"`
-	ChallengeTS time.Time `json:"challenge_ts"`
-	Hostname    string    `json:"hostname"`
-	ErrorCodes  []string  `json:"error-codes"`
-}
-
 type (
 	AppItem = command.AppItem
 	AppList = command.AppList
@@ -40,6 +26,7 @@ type (
 
 var mainApps AppList = []AppItem{
 	{serveApp, "serve", "serve faucet", DefaultServeOptions},
+	{signerApp, "signer", "serve remote signer of faucet", DefaultSignerOptions},
 }
 
 func runMain(cmd *command.Command, exec string, args []string) error {
@@ -80,41 +67,53 @@ func main() {
 // serveApp
 
 type serveOptions struct {
-	client.BaseOptions        // home, ...
-	ChainID            string `flag:"chain-id" help:"chain id"`
-	GasWanted          int64  `flag:"gas-wanted" help:"gas requested for tx"`
-	GasFee             string `flag:"gas-fee" help:"gas payment fee"`
-	Memo               string `flag:"memo" help:"any descriptive text"`
-	TestTo             string `flag:"test-to" help:"test addr (optional)"`
-	Send               string `flag:"send" help:"send coins"`
-	CaptchaSecret      string `flag:"captcha-secret" help:"recaptcha secret key (if empty, captcha are disabled)"`
-	IsBehindProxy      bool   `flag:"is-behind-proxy" help:"use X-Forwarded-For IP for throttling."`
+	client.BaseOptions          // home, ...
+	ChainID            string   `flag:"chain-id" help:"chain id"`
+	GasWanted          int64    `flag:"gas-wanted" help:"gas requested for tx, per payout"`
+	GasFee             string   `flag:"gas-fee" help:"gas payment fee"`
+	Memo               string   `flag:"memo" help:"any descriptive text"`
+	TestTo             string   `flag:"test-to" help:"test addr (optional)"`
+	Send               string   `flag:"send" help:"send coins"`
+	Captcha            string   `flag:"captcha" help:"captcha provider: recaptcha, hcaptcha or turnstile"`
+	CaptchaSecret      string   `flag:"captcha-secret" help:"captcha secret key (if empty, captcha are disabled)"`
+	IsBehindProxy      bool     `flag:"is-behind-proxy" help:"use X-Forwarded-For IP for throttling."`
+	AddrDailyLimit     int      `flag:"addr-daily-limit" help:"max requests per address per day (0 for no limit)"`
+	IPDailyLimit       int      `flag:"ip-daily-limit" help:"max requests per ip per day (0 for no limit)"`
+	QuotaDir           string   `flag:"quota-dir" help:"directory of the daily quotas (default <home>/faucet)"`
+	BatchSize          int      `flag:"batch-size" help:"max payouts per multisend tx"`
+	BatchInterval      string   `flag:"batch-interval" help:"max wait for more payouts of a multisend tx, e.g. 5s"`
+	RemoteSigner       string   `flag:"remote-signer" help:"URL of a remote signer of the funds, instead of <keyname>"`
+	MultisigSigners    []string `flag:"multisig-signers" help:"local keys signing for the multisig <keyname>"`
+	Listen             string   `flag:"listen" help:"listening address"`
 }
 
 var DefaultServeOptions = serveOptions{
-	BaseOptions:   client.DefaultBaseOptions,
-	ChainID:       "", // must override
-	GasWanted:     50000,
-	GasFee:        "1000000ugnot",
-	Memo:          "",
-	TestTo:        "",
-	Send:          "1000000ugnot",
-	CaptchaSecret: "",
-	IsBehindProxy: false,
+	BaseOptions:     client.DefaultBaseOptions,
+	ChainID:         "", // must override
+	GasWanted:       50000,
+	GasFee:          "1000000ugnot",
+	Memo:            "",
+	TestTo:          "",
+	Send:            "1000000ugnot",
+	Captcha:         "recaptcha",
+	CaptchaSecret:   "",
+	IsBehindProxy:   false,
+	AddrDailyLimit:  5,
+	IPDailyLimit:    20,
+	QuotaDir:        "",
+	BatchSize:       20,
+	BatchInterval:   "5s",
+	RemoteSigner:    "",
+	MultisigSigners: nil,
+	Listen:          ":5050",
 }
 
 func serveApp(cmd *command.Command, args []string, iopts interface{}) error {
 	opts := iopts.(serveOptions)
-	if len(args) != 1 {
+	if len(args) != 1 && opts.RemoteSigner == "" {
 		cmd.ErrPrintfln("Usage: serve <keyname>")
 		return errors.New("invalid args")
 	}
-	if opts.ChainID == "" {
-		return errors.New("chain-id not specified")
-	}
-	if opts.GasWanted == 0 {
-		return errors.New("gas-wanted not specified")
-	}
 	if opts.GasFee == "" {
 		return errors.New("gas-fee not specified")
 	}
@@ -125,289 +124,163 @@ func serveApp(cmd *command.Command, args []string, iopts interface{}) error {
 	}
 	cli := rpcclient.NewHTTP(remote, "/websocket")
 
-	// XXX XXX
-	// Read supply account pubkey.
-	name := args[0]
-	kb, err := keys.NewKeyBaseFromDir(opts.Home)
+	// Parse config.
+	config := faucet.DefaultConfig()
+	config.ChainID = opts.ChainID
+	config.GasWanted = opts.GasWanted
+	config.Memo = opts.Memo
+	config.BatchSize = opts.BatchSize
+	config.BehindProxy = opts.IsBehindProxy
+	gasFee, err := std.ParseCoin(opts.GasFee)
 	if err != nil {
-		return err
+		return errors.Wrap(err, "parsing gas fee coin")
 	}
-	info, err := kb.GetByName(name)
+	config.GasFee = gasFee
+	send, err := std.ParseCoins(opts.Send)
 	if err != nil {
-		return err
-	}
-	fromAddr := info.GetAddress()
-	// pub := info.GetPubKey()
-
-	// query for initial number and sequence.
-	path := fmt.Sprintf("auth/accounts/%s", fromAddr.String())
-	data := []byte(nil)
-	opts2 := rpcclient.ABCIQueryOptions{
-		// Height: height, XXX
-		// Prove: false, XXX
+		return errors.Wrap(err, "parsing send coins")
 	}
-	qres, err := cli.ABCIQueryWithOptions(
-		path, data, opts2)
+	config.Send = send
+	batchInterval, err := time.ParseDuration(opts.BatchInterval)
 	if err != nil {
-		return errors.Wrap(err, "querying")
+		return errors.Wrap(err, "parsing batch interval")
 	}
-	if qres.Response.Error != nil {
-		fmt.Printf("Log: %s\n",
-			qres.Response.Log)
-		return qres.Response.Error
+	config.BatchInterval = batchInterval
+	if err := config.ValidateBasic(); err != nil {
+		return err
 	}
-	resdata := qres.Response.Data
-	var acc gnoland.GnoAccount
-	amino.MustUnmarshalJSON(resdata, &acc)
-	accountNumber := acc.BaseAccount.AccountNumber
-	sequence := acc.BaseAccount.Sequence
 
-	// Get password for supply account.
-	// Test by signing a dummy message;
-	const dummy = "test"
-	var pass string
-	if opts.Quiet {
-		pass, err = cmd.GetPassword("")
+	// Open backend of funds.
+	var backend faucet.Backend
+	if opts.RemoteSigner != "" {
+		backend, err = faucet.NewRemoteBackend(opts.RemoteSigner)
 	} else {
-		pass, err = cmd.GetPassword("Enter password.")
-	}
-	if err != nil {
-		return err
+		backend, err = newKeybaseBackend(cmd, opts.BaseOptions, args[0], opts.MultisigSigners)
 	}
-	_, _, err = kb.Sign(name, pass, []byte(dummy))
 	if err != nil {
 		return err
 	}
 
-	// Parse send amount.
-	send, err := std.ParseCoins(opts.Send)
-	if err != nil {
-		return errors.Wrap(err, "parsing send coins")
-	}
-
 	// Parse test-to address. If present, send and quit.
 	if opts.TestTo != "" {
 		testToAddr, err := crypto.AddressFromBech32(opts.TestTo)
 		if err != nil {
 			return err
 		}
-		err = sendAmountTo(cmd, cli, name, pass, testToAddr, accountNumber, sequence, send, opts)
+		f := faucet.NewFaucet(cli, backend, config)
+		if err := f.Start(); err != nil {
+			return err
+		}
+		defer f.Stop()
+		err = f.Pay(testToAddr, send)
+		if err == nil {
+			cmd.Println("OK!")
+		}
 		return err
 	}
 
-	// Start throttled faucet.
-	st := NewSubnetThrottler()
+	// Middlewares.
+	st := faucet.NewSubnetThrottler()
 	st.Start()
-
-	// handle route using handler function
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		host := ""
-		if !opts.IsBehindProxy {
-			addr := r.RemoteAddr
-			host_, _, err := net.SplitHostPort(addr)
-			if err != nil {
-				return
-			}
-			host = host_
-		} else if xff, found := r.Header["X-Forwarded-For"]; found && len(xff) > 0 {
-			host = xff[0]
-		}
-
-		// if can't identify the IP, everyone is in the same pool.
-		// if host using ipv6 loopback addr, make it ipv4
-		if host == "" || host == "::1" || host == "0:0:0:0:0:0:0:1" {
-			host = "127.0.0.1"
-		}
-		ip := net.ParseIP(host)
-		if ip == nil {
-			fmt.Println("no ip found")
-			w.Write([]byte("no ip found"))
-			return
-		}
-
-		allowed, reason := st.Request(ip)
-		if !allowed {
-			msg := fmt.Sprintf("abuse protection system (%s)", reason)
-			fmt.Println(ip, msg)
-			w.Write([]byte(msg))
-			return
-		}
-
-		r.ParseForm()
-
-		// only when command line argument 'captcha-secret' has entered > captcha are enabled.
-		// veryify captcha
-		if opts.CaptchaSecret != "" {
-			passedMsg := r.Form["g-recaptcha-response"]
-			if passedMsg == nil {
-				fmt.Println(ip, "no 'captcha' request")
-				w.Write([]byte("check captcha request"))
-				return
-			}
-
-			capMsg := strings.TrimSpace(passedMsg[0])
-
-			if err := checkRecaptcha(opts.CaptchaSecret, capMsg); err != nil {
-				fmt.Printf("%s recaptcha failed; %v\n", ip, err)
-				w.Write([]byte("Unauthorized"))
-				return
-			}
-
-		}
-
-		passedAddr := r.Form["toaddr"]
-		if passedAddr == nil {
-			fmt.Println(ip, "no address found")
-			w.Write([]byte("no address found"))
-			return
+	mws := []faucet.Middleware{faucet.Throttle(st)}
+	// only when command line argument 'captcha-secret' has entered > captcha are enabled.
+	if opts.CaptchaSecret != "" {
+		provider, ok := faucet.CaptchaProviders[opts.Captcha]
+		if !ok {
+			return errors.New("unknown captcha provider %q", opts.Captcha)
 		}
+		mws = append(mws, faucet.Captcha(provider, opts.CaptchaSecret))
+	}
+	quotaDir := opts.QuotaDir
+	if quotaDir == "" {
+		quotaDir = filepath.Join(opts.Home, "faucet")
+	}
+	db := dbm.NewDB("quotas", dbm.GoLevelDBBackend, quotaDir)
+	defer db.Close()
+	mws = append(mws, faucet.Quota(faucet.NewQuotaStore(db, opts.AddrDailyLimit, opts.IPDailyLimit)))
 
-		toAddrStr := strings.TrimSpace(passedAddr[0])
+	// Start faucet.
+	f := faucet.NewFaucet(cli, backend, config, mws...)
+	f.SetLogger(log.NewTMLogger(log.NewSyncWriter(os.Stdout)))
+	if err := f.Start(); err != nil {
+		return err
+	}
+	defer f.Stop()
 
-		// OK.
-		toAddr, err := crypto.AddressFromBech32(toAddrStr)
-		if err != nil {
-			fmt.Println(ip, "invalid address format", err)
-			w.Write([]byte("invalid address format"))
-			return
-		}
-		err = sendAmountTo(cmd, cli, name, pass, toAddr, accountNumber, sequence, send, opts)
-		if err != nil {
-			fmt.Println(ip, "faucet failed", err)
-			w.Write([]byte("faucet failed"))
-			return
-		} else {
-			sequence += 1
-			fmt.Println(ip, "faucet success")
-			w.Write([]byte("faucet success"))
-		}
-	})
+	// handle route using handler function
+	http.Handle("/", f)
+	http.Handle("/metrics", f.Metrics)
 
 	// listen to port
-	http.ListenAndServe(":5050", nil)
-
-	return nil
+	return http.ListenAndServe(opts.Listen, nil)
 }
 
-func sendAmountTo(cmd *command.Command, cli rpcclient.Client, name, pass string, toAddr crypto.Address, accountNumber, sequence uint64, send std.Coins, opts serveOptions) error {
-	// Read supply account pubkey.
+// Returns the backend of the key name of the keybase of opts, or if the key
+// is a multisig, of its signers.
+func newKeybaseBackend(cmd *command.Command, opts client.BaseOptions, name string, signers []string) (faucet.Backend, error) {
 	kb, err := keys.NewKeyBaseFromDir(opts.Home)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	info, err := kb.GetByName(name)
-	if err != nil {
-		return err
+	if len(signers) == 0 {
+		return newKeybaseSigner(cmd, opts, kb, name)
 	}
-	fromAddr := info.GetAddress()
-	pub := info.GetPubKey()
-
-	// parse gas wanted & fee.
-	gaswanted := opts.GasWanted
-	gasfee, err := std.ParseCoin(opts.GasFee)
+	info, err := kb.GetByName(name)
 	if err != nil {
-		return errors.Wrap(err, "parsing gas fee coin")
+		return nil, err
 	}
-
-	// construct msg & tx and marshal.
-	msg := bank.MsgSend{
-		FromAddress: fromAddr,
-		ToAddress:   toAddr,
-		Amount:      send,
-	}
-	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       opts.Memo,
-	}
-	// fill tx signatures.
-	signers := tx.GetSigners()
-	if tx.Signatures == nil {
-		for range signers {
-			tx.Signatures = append(tx.Signatures, std.Signature{
-				PubKey:    nil, // zero signature
-				Signature: nil, // zero signature
-			})
+	backends := make([]faucet.Backend, len(signers))
+	for i, signer := range signers {
+		backends[i], err = newKeybaseSigner(cmd, opts, kb, signer)
+		if err != nil {
+			return nil, err
 		}
 	}
-	err = tx.ValidateBasic()
-	if err != nil {
-		return err
-	}
-	// fmt.Println("will sign:", string(amino.MustMarshalJSON(tx)))
+	return faucet.NewMultisigBackend(info.GetPubKey(), backends...)
+}
 
-	// get sign-bytes and make signature.
-	chainID := opts.ChainID
-	signbz := tx.GetSignBytes(chainID, accountNumber, sequence)
-	sig, _, err := kb.Sign(name, pass, signbz)
+// Returns the backend of the key name of kb, after asking its password.
+func newKeybaseSigner(cmd *command.Command, opts client.BaseOptions, kb keys.Keybase, name string) (faucet.Backend, error) {
+	var pass string
+	var err error
+	if opts.Quiet {
+		pass, err = cmd.GetPassword("")
+	} else {
+		pass, err = cmd.GetPassword(fmt.Sprintf("Enter password of %s.", name))
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return faucet.NewKeybaseBackend(kb, name, pass)
+}
 
-	found := false
-	for i := range tx.Signatures {
-		// override signature for matching slot.
-		if signers[i] == fromAddr {
-			found = true
-			tx.Signatures[i] = std.Signature{
-				PubKey:    pub,
-				Signature: sig,
-			}
-		}
-	}
-	if !found {
-		return errors.New("addr %v (%s) not in signer set",
-			fromAddr, name)
-	}
-	fmt.Println("will deliver:", string(amino.MustMarshalJSON(tx)))
+//----------------------------------------
+// signerApp
 
-	// construct tx serialized bytes.
-	txbz := amino.MustMarshal(tx)
+type signerOptions struct {
+	client.BaseOptions        // home, ...
+	Listen             string `flag:"listen" help:"listening address, which should only be reachable by the faucet"`
+}
 
-	// broadcast tx bytes.
-	bres, err := cli.BroadcastTxCommit(txbz)
-	if err != nil {
-		return errors.Wrap(err, "broadcasting bytes")
-	}
-	if bres.CheckTx.IsErr() {
-		return errors.New("transaction failed %#v\nlog %s", bres, bres.CheckTx.Log)
-	} else if bres.DeliverTx.IsErr() {
-		return errors.New("transaction failed %#v\nlog %s", bres, bres.DeliverTx.Log)
-	} else {
-		cmd.Println(string(bres.DeliverTx.Data))
-		cmd.Println("OK!")
-		cmd.Println("GAS WANTED:", bres.DeliverTx.GasWanted)
-		cmd.Println("GAS USED:  ", bres.DeliverTx.GasUsed)
-	}
-	return nil
+var DefaultSignerOptions = signerOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	Listen:      "127.0.0.1:5051",
 }
 
-func checkRecaptcha(secret, response string) error {
-	req, err := http.NewRequest(http.MethodPost, siteVerifyURL, nil)
+func signerApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(signerOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: signer <keyname>")
+		return errors.New("invalid args")
+	}
+	kb, err := keys.NewKeyBaseFromDir(opts.Home)
 	if err != nil {
 		return err
 	}
-
-	q := req.URL.Query()
-	q.Add("secret", secret)
-	q.Add("response", response)
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := http.DefaultClient.Do(req) // 200 OK
+	backend, err := newKeybaseSigner(cmd, opts.BaseOptions, kb, args[0])
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	var body SiteVerifyResponse
-	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
-		return errors.New("fail, decode response")
-	}
-
-	if !body.Success {
-		return errors.New("unsuccessful recaptcha verify request")
-	}
-
-	return nil
+	cmd.Printfln("Signing for %s on %s", backend.PubKey().Address(), opts.Listen)
+	return http.ListenAndServe(opts.Listen, faucet.RemoteSignerHandler(backend))
 }
@@ -4,15 +4,18 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gnolang/gno"
 	"github.com/gnolang/gno/pkgs/amino"
 	"github.com/gnolang/gno/pkgs/command"
 	"github.com/gnolang/gno/pkgs/crypto"
-	"github.com/gnolang/gno/pkgs/crypto/keys"
 	"github.com/gnolang/gno/pkgs/crypto/keys/client"
 	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/sdk/authz"
 	"github.com/gnolang/gno/pkgs/sdk/bank"
+	_ "github.com/gnolang/gno/pkgs/sdk/valset" // to sign valset txs.
 	"github.com/gnolang/gno/pkgs/sdk/vm"
 	"github.com/gnolang/gno/pkgs/std"
 )
@@ -27,7 +30,7 @@ func main() {
 	if err != nil {
 		cmd.ErrPrintfln("%s", err.Error())
 		cmd.ErrPrintfln("%#v", err)
-		return // exit
+		os.Exit(1)
 	}
 }
 
@@ -37,6 +40,11 @@ var makeTxApps client.AppList = []client.AppItem{
 		"addpkg", "upload new package",
 		defaultMakeAddPackageTxOptions,
 	},
+	{
+		makeUpgradePackageTxApp,
+		"upgradepkg", "upload new version of realm",
+		defaultMakeUpgradePackageTxOptions,
+	},
 	{
 		makeCallTxApp,
 		"call", "call public function",
@@ -47,6 +55,41 @@ var makeTxApps client.AppList = []client.AppItem{
 		"send", "send coins",
 		defaultMakeSendTxOptions,
 	},
+	{
+		makeGrantTxApp,
+		"grant", "grant a key to sign txs in place of account",
+		defaultMakeGrantTxOptions,
+	},
+	{
+		makeRevokeTxApp,
+		"revoke", "revoke a grant",
+		defaultMakeRevokeTxOptions,
+	},
+	{
+		makeSetVerifierTxApp,
+		"setverifier", "set verification realm of account",
+		defaultMakeSetVerifierTxOptions,
+	},
+	{
+		makePausePackageTxApp,
+		"pausepkg", "pause or unpause calls of realm",
+		defaultMakePausePackageTxOptions,
+	},
+	{
+		makeRegisterNamespaceTxApp,
+		"registerns", "register namespace of packages",
+		defaultMakeRegisterNamespaceTxOptions,
+	},
+	{
+		makeApproveTxApp,
+		"approve", "allow a realm to transfer coins of account",
+		defaultMakeApproveTxOptions,
+	},
+	{
+		makeMultiTxApp,
+		"multi", "compose the msgs of several txs in one tx",
+		defaultMakeMultiTxOptions,
+	},
 }
 
 func makeTxApp(cmd *command.Command, args []string, iopts interface{}) error {
@@ -72,14 +115,30 @@ func makeTxApp(cmd *command.Command, args []string, iopts interface{}) error {
 }
 
 type SignBroadcastOptions struct {
-	GasWanted int64  `flag:"gas-wanted" help:"gas requested for tx"`
-	GasFee    string `flag:"gas-fee" help:"gas payment fee"`
-	Memo      string `flag:"memo" help:"any descriptive text"`
+	client.KeySourceOptions // key-env, key-fd
+
+	GasWanted int64    `flag:"gas-wanted" help:"gas requested for tx"`
+	GasFee    string   `flag:"gas-fee" help:"gas payment fee"`
+	Memo      string   `flag:"memo" help:"any descriptive text"`
+	Metadata  []string `flag:"metadata" help:"key=value metadata appended to the memo"`
 
 	Broadcast bool   `flag:"broadcast" help:"sign and broadcast"`
 	ChainID   string `flag:"chainid" help:"chainid to sign for (only useful if --broadcast)"`
 }
 
+// Returns the memo of the memo and metadata options.
+func (opts SignBroadcastOptions) memo() (string, error) {
+	metadata := make(map[string]string)
+	for _, kv := range opts.Metadata {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return "", errors.New("invalid metadata %q, expected key=value", kv)
+		}
+		metadata[parts[0]] = parts[1]
+	}
+	return client.FormatMemo(opts.Memo, metadata), nil
+}
+
 //----------------------------------------
 // makeAddPackageTx
 
@@ -113,7 +172,7 @@ func makeAddPackageTxApp(cmd *command.Command, args []string, iopts interface{})
 
 	// read account pubkey.
 	nameOrBech32 := args[0]
-	kb, err := keys.NewKeyBaseFromDir(opts.Home)
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
 	if err != nil {
 		return err
 	}
@@ -151,11 +210,113 @@ func makeAddPackageTxApp(cmd *command.Command, args []string, iopts interface{})
 		Package: memPkg,
 		Deposit: deposit,
 	}
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       memo,
+	}
+
+	if opts.Broadcast {
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+//----------------------------------------
+// makeUpgradePackageTx
+
+type makeUpgradePackageTxOptions struct {
+	client.BaseOptions          // home,...
+	SignBroadcastOptions        // gas-wanted, gas-fee, memo, ...
+	PkgPath              string `flag:"pkgpath" help:"original realm path (required)"`
+	NewPkgPath           string `flag:"newpkgpath" help:"new versioned realm path (required)"`
+	PkgDir               string `flag:"pkgdir" help:"path to package files (required)"`
+	Deposit              string `flag:"deposit" help:"deposit coins"`
+}
+
+var defaultMakeUpgradePackageTxOptions = makeUpgradePackageTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	PkgPath:     "", // must override
+	NewPkgPath:  "", // must override
+	PkgDir:      "", // must override
+	Deposit:     "",
+}
+
+func makeUpgradePackageTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makeUpgradePackageTxOptions)
+	if opts.PkgPath == "" {
+		return errors.New("pkgpath not specified")
+	}
+	if opts.NewPkgPath == "" {
+		return errors.New("newpkgpath not specified")
+	}
+	if opts.PkgDir == "" {
+		return errors.New("pkgdir not specified")
+	}
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: upgradepkg <keyname or address>")
+		return errors.New("invalid args")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	creator := info.GetAddress()
+
+	// parse deposit.
+	deposit, err := std.ParseCoins(opts.Deposit)
+	if err != nil {
+		panic(err)
+	}
+
+	// open files in directory as MemPackage.
+	memPkg := gno.ReadMemPackage(opts.PkgDir, opts.NewPkgPath)
+
+	// precompile and validate syntax
+	err = gno.PrecompileAndCheckMempkg(memPkg)
+	if err != nil {
+		panic(err)
+	}
+
+	// parse gas wanted & fee.
+	gaswanted := opts.GasWanted
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		panic(err)
+	}
+	// construct msg & tx and marshal.
+	msg := vm.MsgUpgradePackage{
+		Creator: creator,
+		PkgPath: opts.PkgPath,
+		Package: memPkg,
+		Deposit: deposit,
+	}
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
 	tx := std.Tx{
 		Msgs:       []std.Msg{msg},
 		Fee:        std.NewFee(gaswanted, gasfee),
 		Signatures: nil,
-		Memo:       opts.Memo,
+		Memo:       memo,
 	}
 
 	if opts.Broadcast {
@@ -213,7 +374,7 @@ func makeCallTxApp(cmd *command.Command, args []string, iopts interface{}) error
 
 	// read account pubkey.
 	nameOrBech32 := args[0]
-	kb, err := keys.NewKeyBaseFromDir(opts.Home)
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
 	if err != nil {
 		return err
 	}
@@ -245,11 +406,15 @@ func makeCallTxApp(cmd *command.Command, args []string, iopts interface{}) error
 		Func:    fnc,
 		Args:    opts.Args,
 	}
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
 	tx := std.Tx{
 		Msgs:       []std.Msg{msg},
 		Fee:        std.NewFee(gaswanted, gasfee),
 		Signatures: nil,
-		Memo:       opts.Memo,
+		Memo:       memo,
 	}
 
 	if opts.Broadcast {
@@ -266,7 +431,7 @@ func makeCallTxApp(cmd *command.Command, args []string, iopts interface{}) error
 func signAndBroadcast(cmd *command.Command, args []string, tx std.Tx, baseopts client.BaseOptions, txopts SignBroadcastOptions) error {
 	// query account
 	nameOrBech32 := args[0]
-	kb, err := keys.NewKeyBaseFromDir(baseopts.Home)
+	kb, err := txopts.Keybase(baseopts.Home, nameOrBech32)
 	if err != nil {
 		return err
 	}
@@ -301,13 +466,17 @@ func signAndBroadcast(cmd *command.Command, args []string, tx std.Tx, baseopts c
 		TxJson:        amino.MustMarshalJSON(tx),
 	}
 	sopts.Home = baseopts.Home
-	if baseopts.Quiet {
-		sopts.Pass, err = cmd.GetPassword("")
-	} else {
-		sopts.Pass, err = cmd.GetPassword("Enter password.")
-	}
-	if err != nil {
-		return err
+	sopts.KeySourceOptions = txopts.KeySourceOptions
+	// the key of a key source is unencrypted.
+	if !txopts.KeySourceOptions.IsSet() {
+		if baseopts.Quiet {
+			sopts.Pass, err = cmd.GetPassword("")
+		} else {
+			sopts.Pass, err = cmd.GetPassword("Enter password.")
+		}
+		if err != nil {
+			return err
+		}
 	}
 
 	signedTx, err := client.SignHandler(sopts)
@@ -334,6 +503,7 @@ func signAndBroadcast(cmd *command.Command, args []string, tx std.Tx, baseopts c
 	cmd.Println("OK!")
 	cmd.Println("GAS WANTED:", bres.DeliverTx.GasWanted)
 	cmd.Println("GAS USED:  ", bres.DeliverTx.GasUsed)
+	cmd.Printf("TX HASH:    %X\n", bres.TxHash)
 
 	return nil
 }
@@ -375,7 +545,7 @@ func makeSendTxApp(cmd *command.Command, args []string, iopts interface{}) error
 
 	// read account pubkey.
 	nameOrBech32 := args[0]
-	kb, err := keys.NewKeyBaseFromDir(opts.Home)
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
 	if err != nil {
 		return err
 	}
@@ -411,11 +581,195 @@ func makeSendTxApp(cmd *command.Command, args []string, iopts interface{}) error
 		ToAddress:   toAddr,
 		Amount:      send,
 	}
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       memo,
+	}
+
+	if opts.Broadcast {
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+//----------------------------------------
+// makeGrantTxApp
+
+type makeGrantTxOptions struct {
+	client.BaseOptions            // home,...
+	SignBroadcastOptions          // gas-wanted, gas-fee, memo, ...
+	Grantee              string   `flag:"grantee" help:"address of the granted key"`
+	MsgTypes             []string `flag:"msg-types" help:"granted msg types, e.g. vm/exec"`
+	PkgPaths             []string `flag:"pkgpaths" help:"granted packages of vm msgs (default all)"`
+	SpendLimit           string   `flag:"spend-limit" help:"max coins spent in fees and sends (default none)"`
+	Expires              string   `flag:"expires" help:"duration of the grant, e.g. 24h (default no expiry)"`
+}
+
+var defaultMakeGrantTxOptions = makeGrantTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	Grantee:     "",  // must override
+	MsgTypes:    nil, // must override
+}
+
+func makeGrantTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makeGrantTxOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: grant <keyname or address>")
+		return errors.New("invalid args")
+	}
+	if opts.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if opts.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+	if opts.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+	if len(opts.MsgTypes) == 0 {
+		return errors.New("msg-types must be specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	granter := info.GetAddress()
+
+	// Parse grantee address.
+	grantee, err := crypto.AddressFromBech32(opts.Grantee)
+	if err != nil {
+		return err
+	}
+
+	// Parse spend limit and expiration.
+	spendLimit, err := std.ParseCoins(opts.SpendLimit)
+	if err != nil {
+		return errors.Wrap(err, "parsing spend limit coins")
+	}
+	var expiration time.Time
+	if opts.Expires != "" {
+		expires, err := time.ParseDuration(opts.Expires)
+		if err != nil {
+			return errors.Wrap(err, "parsing expires")
+		}
+		expiration = time.Now().Add(expires).UTC()
+	}
+
+	// parse gas wanted & fee.
+	gaswanted := opts.GasWanted
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	msg := authz.NewMsgGrant(granter, grantee, opts.MsgTypes, opts.PkgPaths, spendLimit, expiration)
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       memo,
+	}
+
+	if opts.Broadcast {
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+//----------------------------------------
+// makeRevokeTxApp
+
+type makeRevokeTxOptions struct {
+	client.BaseOptions          // home,...
+	SignBroadcastOptions        // gas-wanted, gas-fee, memo, ...
+	Grantee              string `flag:"grantee" help:"address of the granted key"`
+}
+
+var defaultMakeRevokeTxOptions = makeRevokeTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	Grantee:     "", // must override
+}
+
+func makeRevokeTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makeRevokeTxOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: revoke <keyname or address>")
+		return errors.New("invalid args")
+	}
+	if opts.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if opts.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+	if opts.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	granter := info.GetAddress()
+
+	// Parse grantee address.
+	grantee, err := crypto.AddressFromBech32(opts.Grantee)
+	if err != nil {
+		return err
+	}
+
+	// parse gas wanted & fee.
+	gaswanted := opts.GasWanted
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	msg := authz.NewMsgRevoke(granter, grantee)
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
 	tx := std.Tx{
 		Msgs:       []std.Msg{msg},
 		Fee:        std.NewFee(gaswanted, gasfee),
 		Signatures: nil,
-		Memo:       opts.Memo,
+		Memo:       memo,
 	}
 
 	if opts.Broadcast {
@@ -428,3 +782,398 @@ func makeSendTxApp(cmd *command.Command, args []string, iopts interface{}) error
 	}
 	return nil
 }
+
+//----------------------------------------
+// makeSetVerifierTxApp
+
+type makeSetVerifierTxOptions struct {
+	client.BaseOptions          // home,...
+	SignBroadcastOptions        // gas-wanted, gas-fee, memo, ...
+	PkgPath              string `flag:"pkgpath" help:"verification realm, declaring VerifyTx(key std.Address) bool (default unset)"`
+}
+
+var defaultMakeSetVerifierTxOptions = makeSetVerifierTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	PkgPath:     "", // unsets the verification realm
+}
+
+func makeSetVerifierTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makeSetVerifierTxOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: setverifier <keyname or address>")
+		return errors.New("invalid args")
+	}
+	if opts.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if opts.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	caller := info.GetAddress()
+
+	// parse gas wanted & fee.
+	gaswanted := opts.GasWanted
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	msg := vm.NewMsgSetVerifier(caller, opts.PkgPath)
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       memo,
+	}
+
+	if opts.Broadcast {
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+//----------------------------------------
+// makePausePackageTxApp
+
+type makePausePackageTxOptions struct {
+	client.BaseOptions          // home,...
+	SignBroadcastOptions        // gas-wanted, gas-fee, memo, ...
+	PkgPath              string `flag:"pkgpath" help:"realm to pause (required)"`
+	Unpause              bool   `flag:"unpause" help:"unpause the realm instead"`
+}
+
+var defaultMakePausePackageTxOptions = makePausePackageTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	PkgPath:     "", // must override
+	Unpause:     false,
+}
+
+func makePausePackageTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makePausePackageTxOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: pausepkg <keyname or address>")
+		return errors.New("invalid args")
+	}
+	if opts.PkgPath == "" {
+		return errors.New("pkgpath not specified")
+	}
+	if opts.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if opts.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	caller := info.GetAddress()
+
+	// parse gas wanted & fee.
+	gaswanted := opts.GasWanted
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	msg := vm.NewMsgPausePackage(caller, opts.PkgPath, !opts.Unpause)
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       memo,
+	}
+
+	if opts.Broadcast {
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+//----------------------------------------
+// makeApproveTxApp
+
+type makeApproveTxOptions struct {
+	client.BaseOptions          // home,...
+	SignBroadcastOptions        // gas-wanted, gas-fee, memo, ...
+	Spender              string `flag:"spender" help:"spender address, e.g. of a realm (required)"`
+	Amount               string `flag:"amount" help:"allowance, replacing any previous one"`
+}
+
+var defaultMakeApproveTxOptions = makeApproveTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	Spender:     "", // must override
+	Amount:      "",
+}
+
+func makeApproveTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makeApproveTxOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: approve <keyname or address>")
+		return errors.New("invalid args")
+	}
+	if opts.Spender == "" {
+		return errors.New("spender not specified")
+	}
+	if opts.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if opts.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	owner := info.GetAddress()
+
+	// parse spender address and amount.
+	spender, err := crypto.AddressFromBech32(opts.Spender)
+	if err != nil {
+		return err
+	}
+	amount, err := std.ParseCoins(opts.Amount)
+	if err != nil {
+		return errors.Wrap(err, "parsing amount coins")
+	}
+
+	// parse gas wanted & fee.
+	gaswanted := opts.GasWanted
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	msg := vm.NewMsgApprove(owner, spender, amount)
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       memo,
+	}
+
+	if opts.Broadcast {
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+//----------------------------------------
+// makeRegisterNamespaceTxApp
+
+type makeRegisterNamespaceTxOptions struct {
+	client.BaseOptions          // home,...
+	SignBroadcastOptions        // gas-wanted, gas-fee, memo, ...
+	Namespace            string `flag:"namespace" help:"namespace, e.g. alice of gno.land/r/alice (required)"`
+}
+
+var defaultMakeRegisterNamespaceTxOptions = makeRegisterNamespaceTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	Namespace:   "", // must override
+}
+
+func makeRegisterNamespaceTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makeRegisterNamespaceTxOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: registerns <keyname or address>")
+		return errors.New("invalid args")
+	}
+	if opts.Namespace == "" {
+		return errors.New("namespace not specified")
+	}
+	if opts.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if opts.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	owner := info.GetAddress()
+
+	// parse gas wanted & fee.
+	gaswanted := opts.GasWanted
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+
+	// construct msg & tx and marshal.
+	msg := vm.NewMsgRegisterNamespace(owner, opts.Namespace)
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       []std.Msg{msg},
+		Fee:        std.NewFee(gaswanted, gasfee),
+		Signatures: nil,
+		Memo:       memo,
+	}
+
+	if opts.Broadcast {
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
+
+//----------------------------------------
+// makeMultiTxApp
+
+type makeMultiTxOptions struct {
+	client.BaseOptions            // home,...
+	SignBroadcastOptions          // gas-wanted, gas-fee, memo, ...
+	Txs                  []string `flag:"txs" help:"files of the txs made by maketx, whose msgs to compose in order (required)"`
+}
+
+var defaultMakeMultiTxOptions = makeMultiTxOptions{
+	BaseOptions: client.DefaultBaseOptions,
+	Txs:         nil, // must override
+}
+
+// The msgs of the txs, e.g. of a send and a call made by maketx without
+// --broadcast, are composed in one tx, with a single fee, which is
+// executed atomically.
+func makeMultiTxApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(makeMultiTxOptions)
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: multi <keyname or address>")
+		return errors.New("invalid args")
+	}
+	if opts.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if opts.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+	if len(opts.Txs) == 0 {
+		return errors.New("txs must be specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := opts.Keybase(opts.Home, nameOrBech32)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+
+	// read the msgs of the txs.
+	b := client.NewTxBuilder(opts.ChainID)
+	for _, file := range opts.Txs {
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return errors.Wrap(err, "reading tx file")
+		}
+		var part std.Tx
+		err = amino.UnmarshalJSON(bz, &part)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling tx file %s", file)
+		}
+		b.AddMsgs(part.Msgs...)
+	}
+
+	// parse gas wanted & fee.
+	gasfee, err := std.ParseCoin(opts.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+	b.SetGasWanted(opts.GasWanted)
+	b.SetGasFee(gasfee)
+
+	// construct tx and marshal.
+	memo, err := opts.memo()
+	if err != nil {
+		return err
+	}
+	b.SetMemo(memo)
+	tx := b.Tx()
+	tx.Signatures = nil
+
+	if opts.Broadcast {
+		// the tx is signed by the key only.
+		signers := b.Signers()
+		if len(signers) != 1 || signers[0] != info.GetAddress() {
+			return errors.New("msgs of other signers than %s, sign the tx with each signer instead", info.GetAddress())
+		}
+		err := signAndBroadcast(cmd, args, tx, opts.BaseOptions, opts.SignBroadcastOptions)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
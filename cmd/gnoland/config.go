@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gnolang/gno/pkgs/bft/config"
+	osm "github.com/gnolang/gno/pkgs/os"
+)
+
+// prefix of the environment variables which override config options.
+const configEnvPrefix = "GNOLAND"
+
+const configUsage = `usage: gnoland config <command> [--root-dir <dir>] [<args>]
+
+commands:
+  init [--force]       write the default config file
+  get [<key>]          print the value of an option, or of all options
+  set <key> <value>    set an option in the config file
+  validate             check the config file, and the environment
+
+Options are named by their keys in the config file, with those of sections
+prefixed by the section, e.g. consensus.timeout_commit.  They are overridden
+by those of the profile, if set (local, lan or wan), then by environment
+variables GNOLAND_<KEY>, e.g. GNOLAND_CONSENSUS_TIMEOUT_COMMIT.  Changes to
+the config file are applied when the node is restarted.
+`
+
+// Runs the config command of args, on the config file of the node.
+func runConfig(args []string) error {
+	return runConfigCommand(args, os.Stdout)
+}
+
+func runConfigCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(configUsage)
+	}
+	cmd := args[0]
+	fs := flag.NewFlagSet("gnoland config "+cmd, flag.ContinueOnError)
+	rootDir := fs.String("root-dir", "testdir", "data directory of the node")
+	force := fs.Bool("force", false, "overwrite an existing config file with init")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	args = fs.Args()
+	path := config.ConfigFilePath(*rootDir)
+
+	switch cmd {
+	case "init":
+		if len(args) != 0 {
+			return errors.New("usage: gnoland config init [--force]")
+		}
+		if osm.FileExists(path) && !*force {
+			return fmt.Errorf("config file %s already exists, use --force to overwrite it", path)
+		}
+		cfg := config.DefaultConfig()
+		nodeConfigOptions(cfg)
+		cfg.SetRootDir(*rootDir)
+		cfg.EnsureDirs()
+		config.WriteConfigFile(path, cfg)
+		fmt.Fprintf(out, "Wrote %s.\n", path)
+	case "get":
+		if len(args) > 1 {
+			return errors.New("usage: gnoland config get [<key>]")
+		}
+		cfg, err := loadConfig(*rootDir, true)
+		if err != nil {
+			return err
+		}
+		keys := args
+		if len(keys) == 0 {
+			keys = cfg.Keys()
+		}
+		for _, key := range keys {
+			value, err := cfg.Get(key)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				fmt.Fprintf(out, "%s = %s\n", key, value)
+			} else {
+				fmt.Fprintln(out, value)
+			}
+		}
+	case "set":
+		if len(args) != 2 {
+			return errors.New("usage: gnoland config set <key> <value>")
+		}
+		// environment overrides are not written.
+		cfg, err := loadConfig(*rootDir, false)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := cfg.ValidateBasic(); err != nil {
+			return err
+		}
+		config.WriteConfigFile(path, cfg)
+		value, _ := cfg.Get(args[0])
+		fmt.Fprintf(out, "Set %s = %s in %s.\n", args[0], value, path)
+	case "validate":
+		if len(args) != 0 {
+			return errors.New("usage: gnoland config validate")
+		}
+		cfg, err := loadConfig(*rootDir, true)
+		if err != nil {
+			return err
+		}
+		if err := cfg.ValidateBasic(); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Config file %s is valid.\n", path)
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", cmd, configUsage)
+	}
+	return nil
+}
+
+// Reads the config file of the node at rootDir, with the options of its
+// profile and of the environment if env is true, as run by the node.
+func loadConfig(rootDir string, env bool) (*config.Config, error) {
+	cfg, err := config.ReadConfigFile(config.ConfigFilePath(rootDir))
+	if err != nil {
+		return nil, err
+	}
+	cfg.SetRootDir(rootDir)
+	if env {
+		if err := cfg.ApplyProfile(); err != nil {
+			return nil, err
+		}
+		if _, err := cfg.ApplyEnv(configEnvPrefix); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
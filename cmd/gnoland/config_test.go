@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/bft/config"
+)
+
+func TestConfigCommands(t *testing.T) {
+	rootDir := t.TempDir()
+	run := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		// flags come after the command.
+		args = append([]string{args[0], "--root-dir", rootDir}, args[1:]...)
+		err := runConfigCommand(args, out)
+		return out.String(), err
+	}
+
+	_, err := run("get", "moniker")
+	require.Error(t, err) // no config file.
+	_, err = run("init")
+	require.NoError(t, err)
+	_, err = run("init")
+	require.Error(t, err)
+	_, err = run("init", "--force")
+	require.NoError(t, err)
+
+	out, err := run("get", "consensus.create_empty_blocks")
+	require.NoError(t, err)
+	require.Equal(t, "false\n", out)
+
+	_, err = run("set", "consensus.timeout_commit", "3s")
+	require.NoError(t, err)
+	out, err = run("get", "consensus.timeout_commit")
+	require.NoError(t, err)
+	require.Equal(t, "3s\n", out)
+	_, err = run("set", "consensus.timeout_commit", "-3s")
+	require.Error(t, err) // invalid.
+	_, err = run("set", "consensus.timeout_comit", "3s")
+	require.Error(t, err)
+
+	// environment variables override the config file.
+	t.Setenv("GNOLAND_CONSENSUS_TIMEOUT_COMMIT", "5s")
+	out, err = run("get", "consensus.timeout_commit")
+	require.NoError(t, err)
+	require.Equal(t, "5s\n", out)
+	_, err = run("validate")
+	require.NoError(t, err)
+	t.Setenv("GNOLAND_CONSENSUS_TIMEOUT_COMMIT", "soon")
+	_, err = run("validate")
+	require.Error(t, err)
+	os.Unsetenv("GNOLAND_CONSENSUS_TIMEOUT_COMMIT")
+
+	// the profile overrides the config file.
+	_, err = run("set", "profile", "wan")
+	require.NoError(t, err)
+	out, err = run("get", "consensus.timeout_commit")
+	require.NoError(t, err)
+	require.Equal(t, "5s\n", out)
+	_, err = run("set", "profile", "moon")
+	require.Error(t, err)
+	_, err = run("set", "profile", "")
+	require.NoError(t, err)
+
+	// unknown keys of the config file are errors.
+	f, err := os.OpenFile(config.ConfigFilePath(rootDir), os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("\nfoo = \"bar\"\n")
+	require.NoError(t, err)
+	f.Close()
+	_, err = run("validate")
+	require.Error(t, err)
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/gnoland"
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/bft/node"
+	"github.com/gnolang/gno/pkgs/bft/privval"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/log"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// interval of polling of the files of watched packages.
+const devWatchInterval = 500 * time.Millisecond
+
+const devHelp = `commands:
+  reload   restart the chain and replay its txs, with packages re-deployed
+  reset    restart the chain without replaying txs
+  help     print this help
+`
+
+// Runs a single-validator chain for development, whose blocks are
+// committed as soon as txs arrive.  The packages of --dev-pkgs are added at
+// genesis, and re-deployed when their files change: as packages cannot be
+// replaced on chain, the chain is restarted from a new genesis which adds
+// the packages, and replays the txs of the previous chain.  The chain can
+// also be reset by commands read from stdin.
+func runDev(logger log.Logger) error {
+	var dirs []string
+	for _, dir := range strings.Split(flags.devPkgs, ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if err := crypto.SetBech32Prefix(flags.bech32Prefix); err != nil {
+		return err
+	}
+	dn := &devNode{rootDir: flags.devRootDir, dirs: dirs, logger: logger}
+	if err := dn.start(nil); err != nil {
+		return err
+	}
+	osm.TrapSignal(func() {
+		dn.stop()
+	})
+
+	changes := make(chan string)
+	go watchDevPackages(dirs, changes)
+	commands := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			commands <- strings.TrimSpace(scanner.Text())
+		}
+	}()
+	fmt.Fprint(os.Stderr, devHelp)
+
+	for {
+		var err error
+		select {
+		case file := <-changes:
+			fmt.Fprintf(os.Stderr, "%s changed, reloading...\n", file)
+			err = dn.restart(true)
+		case cmd := <-commands:
+			switch cmd {
+			case "":
+			case "reload":
+				err = dn.restart(true)
+			case "reset":
+				err = dn.restart(false)
+			case "help":
+				fmt.Fprint(os.Stderr, devHelp)
+			default:
+				fmt.Fprintf(os.Stderr, "unknown command %q\n%s", cmd, devHelp)
+			}
+		}
+		if err != nil {
+			// e.g. a package that does not parse; wait for the next change.
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+	}
+}
+
+// devNode is the node of the dev chain, which is restarted on changes.
+type devNode struct {
+	rootDir string
+	dirs    []string // of the packages to deploy.
+	logger  log.Logger
+
+	mtx      sync.Mutex
+	node     *node.Node
+	replayed []std.Tx // at the genesis of node.
+}
+
+// Starts a new chain, which adds the packages and runs txs at genesis.
+func (dn *devNode) start(txs []std.Tx) (err error) {
+	dn.mtx.Lock()
+	defer dn.mtx.Unlock()
+
+	// packages may panic while being read.
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("%v", r)
+		}
+	}()
+	pkgs, err := readDevPackages(dn.dirs)
+	if err != nil {
+		return err
+	}
+	// examples first, as the packages may import them.
+	pkgs = append(examplePackagesExcept(pkgs), pkgs...)
+
+	if err := os.RemoveAll(dn.rootDir); err != nil {
+		return err
+	}
+	cfg := config.LoadOrMakeConfigWithOptions(dn.rootDir, func(cfg *config.Config) {
+		// commit blocks as soon as txs arrive.
+		cfg.Consensus.CreateEmptyBlocks = false
+		cfg.Consensus.CreateEmptyBlocksInterval = 0
+		cfg.Consensus.TimeoutPropose = 100 * time.Millisecond
+		cfg.Consensus.TimeoutCommit = 0
+		cfg.Consensus.SkipTimeoutCommit = true
+	})
+	if _, err := cfg.ApplyEnv(configEnvPrefix); err != nil {
+		return err
+	}
+	priv := privval.LoadOrGenFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile())
+	genDoc := makeGenesisDoc(priv.GetPubKey(), pkgs, txs)
+	writeGenesisFile(genDoc, filepath.Join(dn.rootDir, cfg.Genesis))
+
+	// failing txs are skipped, e.g. those replayed after changes.
+	gnoApp, err := gnoland.NewApp(dn.rootDir, true, dn.logger, sdk.SetRecentTxWindow(flags.recentTxWindow))
+	if err != nil {
+		return fmt.Errorf("error in creating new app: %w", err)
+	}
+	cfg.LocalApp = gnoApp
+	gnoNode, err := node.DefaultNewNode(cfg, dn.logger)
+	if err != nil {
+		return fmt.Errorf("error in creating node: %w", err)
+	}
+	if err := gnoNode.Start(); err != nil {
+		return fmt.Errorf("error in start node: %w", err)
+	}
+	dn.node = gnoNode
+	dn.replayed = txs
+	fmt.Fprintf(os.Stderr, "Dev node started, with %d packages and %d replayed txs.\n", len(pkgs), len(txs))
+	return nil
+}
+
+func (dn *devNode) stop() {
+	dn.mtx.Lock()
+	defer dn.mtx.Unlock()
+	if dn.node != nil && dn.node.IsRunning() {
+		_ = dn.node.Stop()
+	}
+	dn.node = nil
+}
+
+// Restarts the chain, replaying the txs of the previous chain if replay is
+// true.
+func (dn *devNode) restart(replay bool) error {
+	var txs []std.Tx
+	if replay {
+		var err error
+		txs, err = dn.committedTxs()
+		if err != nil {
+			return err
+		}
+	}
+	dn.stop()
+	return dn.start(txs)
+}
+
+// Returns the txs of the current chain: those replayed at genesis, and
+// those of its blocks.
+func (dn *devNode) committedTxs() ([]std.Tx, error) {
+	dn.mtx.Lock()
+	defer dn.mtx.Unlock()
+	if dn.node == nil {
+		return nil, nil
+	}
+	txs := append([]std.Tx(nil), dn.replayed...)
+	bs := dn.node.BlockStore()
+	for height := int64(1); height <= bs.Height(); height++ {
+		block := bs.LoadBlock(height)
+		for _, bz := range block.Data.Txs {
+			var tx std.Tx
+			if err := amino.Unmarshal(bz, &tx); err != nil {
+				return nil, fmt.Errorf("decoding tx of block %d: %w", height, err)
+			}
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+// Reads the packages in dirs.  The path of a package is the module of its
+// gno.mod file if any, or else inferred from its directory, as in
+// examples/gno.land/r/foo.
+func readDevPackages(dirs []string) ([]*std.MemPackage, error) {
+	pkgs := []*std.MemPackage{}
+	for _, dir := range dirs {
+		pkgPath, err := devPackagePath(dir)
+		if err != nil {
+			return nil, err
+		}
+		memPkg := gno.ReadMemPackage(dir, pkgPath)
+		if err := memPkg.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", dir, err)
+		}
+		pkgs = append(pkgs, memPkg)
+	}
+	return pkgs, nil
+}
+
+func devPackagePath(dir string) (string, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, gno.ModFileName))
+	if err == nil {
+		mf, err := gno.ParseModFile(string(bz))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", dir, err)
+		}
+		if mf.Module != "" {
+			return mf.Module, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	abs = filepath.ToSlash(abs)
+	i := strings.LastIndex(abs, "/gno.land/")
+	if i < 0 {
+		return "", fmt.Errorf("%s: cannot infer the package path, add a %s file with a module directive", dir, gno.ModFileName)
+	}
+	return abs[i+1:], nil
+}
+
+// Returns the example packages added at genesis, except those of pkgs.
+func examplePackagesExcept(pkgs []*std.MemPackage) []*std.MemPackage {
+	paths := make(map[string]bool)
+	for _, memPkg := range pkgs {
+		paths[memPkg.Path] = true
+	}
+	res := []*std.MemPackage{}
+	for _, memPkg := range examplePackages() {
+		if !paths[memPkg.Path] {
+			res = append(res, memPkg)
+		}
+	}
+	return res
+}
+
+// Sends the name of a changed file of the packages in dirs on changes,
+// by polling their modification times.
+func watchDevPackages(dirs []string, changes chan<- string) {
+	last := snapshotDevPackages(dirs)
+	for {
+		time.Sleep(devWatchInterval)
+		snapshot := snapshotDevPackages(dirs)
+		if changed := diffDevSnapshots(last, snapshot); changed != "" {
+			changes <- changed
+		}
+		last = snapshot
+	}
+}
+
+// Returns the modification times of the files of the packages in dirs.
+func snapshotDevPackages(dirs []string) map[string]time.Time {
+	snapshot := make(map[string]time.Time)
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // e.g. removed meanwhile.
+			}
+			if d.IsDir() {
+				if path != dir {
+					return filepath.SkipDir // packages are not nested.
+				}
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				snapshot[path] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return snapshot
+}
+
+// Returns the name of a file added, changed, or removed between the
+// snapshots, or "" if none.
+func diffDevSnapshots(last, snapshot map[string]time.Time) string {
+	for path, mtime := range snapshot {
+		if lmtime, ok := last[path]; !ok || !lmtime.Equal(mtime) {
+			return path
+		}
+	}
+	for path := range last {
+		if _, ok := snapshot[path]; !ok {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevPackagePath(t *testing.T) {
+	tmp := t.TempDir()
+
+	dir := filepath.Join(tmp, "gno.land", "r", "foo")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	path, err := devPackagePath(dir)
+	require.NoError(t, err)
+	require.Equal(t, "gno.land/r/foo", path)
+
+	dir = filepath.Join(tmp, "bar")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	_, err = devPackagePath(dir)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gno.mod"), []byte("module gno.land/r/bar\n"), 0o644))
+	path, err = devPackagePath(dir)
+	require.NoError(t, err)
+	require.Equal(t, "gno.land/r/bar", path)
+}
+
+func TestDiffDevSnapshots(t *testing.T) {
+	now := time.Now()
+	last := map[string]time.Time{"a.gno": now, "b.gno": now}
+
+	require.Equal(t, "", diffDevSnapshots(last, map[string]time.Time{"a.gno": now, "b.gno": now}))
+	require.Equal(t, "b.gno", diffDevSnapshots(last, map[string]time.Time{"a.gno": now, "b.gno": now.Add(time.Second)}))
+	require.Equal(t, "b.gno", diffDevSnapshots(last, map[string]time.Time{"a.gno": now}))
+	require.Equal(t, "c.gno", diffDevSnapshots(last, map[string]time.Time{"a.gno": now, "b.gno": now, "c.gno": now}))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gnolang/gno/gnoland"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/bft/node"
+	sm "github.com/gnolang/gno/pkgs/bft/state"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/log"
+	"github.com/gnolang/gno/pkgs/sdk"
+	vmm "github.com/gnolang/gno/pkgs/sdk/vm"
+)
+
+// Writes to --export-file a genesis file whose app state is the committed
+// state of the node at --export-root-dir at --export-height, and whose
+// validators are those of the next height, e.g. to reset a testnet or fork
+// a network with its accounts and realms.
+//
+// NOTE: the new chain starts at height 1, so that block heights recorded in
+// the state, e.g. of added packages, refer to the exported chain.
+func runExport(logger log.Logger) error {
+	rootDir := flags.exportRootDir
+	cfg := config.LoadOrMakeConfigWithOptions(rootDir, func(cfg *config.Config) {})
+	if err := setBech32Prefix(cfg.GenesisFile()); err != nil {
+		return err
+	}
+	genDoc, err := bft.GenesisDocFromFile(cfg.GenesisFile())
+	if err != nil {
+		return fmt.Errorf("error in loading genesis: %w", err)
+	}
+	stateDB, err := node.DefaultDBProvider(&node.DBContext{ID: "state", Config: cfg})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	state := sm.LoadState(stateDB)
+	height := flags.exportHeight
+	if height == 0 {
+		height = state.LastBlockHeight
+	}
+	if height < 1 || height > state.LastBlockHeight {
+		return fmt.Errorf("invalid height %d, last height is %d", height, state.LastBlockHeight)
+	}
+	vals, err := sm.LoadValidators(stateDB, height+1)
+	if err != nil {
+		return fmt.Errorf("error in loading validators: %w", err)
+	}
+	params, err := sm.LoadConsensusParams(stateDB, height+1)
+	if err != nil {
+		return fmt.Errorf("error in loading consensus params: %w", err)
+	}
+
+	app, err := gnoland.NewApp(rootDir, flags.skipFailingGenesisTxs, logger)
+	if err != nil {
+		return fmt.Errorf("error in creating app: %w", err)
+	}
+	if flags.exportRealm != "" {
+		return exportRealm(app.(*sdk.BaseApp), height)
+	}
+	stores, err := app.(*sdk.BaseApp).ExportStores(height)
+	if err != nil {
+		// e.g. the state of the node at height was pruned.
+		return fmt.Errorf("error in exporting state at height %d: %w", height, err)
+	}
+	modules, err := app.(*sdk.BaseApp).ExportGenesis(height)
+	if err != nil {
+		return fmt.Errorf("error in exporting modules at height %d: %w", height, err)
+	}
+
+	gen := &bft.GenesisDoc{}
+	gen.GenesisTime = time.Now()
+	gen.ChainID = genDoc.ChainID
+	if flags.exportChainID != "" {
+		gen.ChainID = flags.exportChainID
+	}
+	gen.ConsensusParams = params
+	names := make(map[string]string)
+	for _, val := range genDoc.Validators {
+		names[val.Address.String()] = val.Name
+	}
+	for _, val := range vals.Validators {
+		gen.Validators = append(gen.Validators, bft.GenesisValidator{
+			Address: val.Address,
+			PubKey:  val.PubKey,
+			Power:   val.VotingPower,
+			Name:    names[val.Address.String()],
+		})
+	}
+	genState := gnoland.GnoGenesisState{
+		Balances: []string{},
+		Txs:      nil,
+	}
+	for name, kvs := range stores {
+		genState.Stores = append(genState.Stores, gnoland.GenesisStore{Name: name, Pairs: kvs})
+	}
+	sort.Slice(genState.Stores, func(i, j int) bool {
+		return genState.Stores[i].Name < genState.Stores[j].Name
+	})
+	for name, state := range modules {
+		genState.Modules = append(genState.Modules, gnoland.GenesisModule{Name: name, State: state})
+	}
+	sort.Slice(genState.Modules, func(i, j int) bool {
+		return genState.Modules[i].Name < genState.Modules[j].Name
+	})
+	gen.AppState = genState
+
+	if err := gen.ValidateAndComplete(); err != nil {
+		return fmt.Errorf("error in exported genesis: %w", err)
+	}
+	if err := gen.SaveAs(flags.exportFile); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported the state at height %d of chain %s to %s.\n", height, genDoc.ChainID, flags.exportFile)
+	return nil
+}
+
+// Writes to --export-file the state of the realm --export-realm at height,
+// which may be imported into another chain with --genesis-realms.
+func exportRealm(app *sdk.BaseApp, height int64) error {
+	res := app.Query(abci.RequestQuery{
+		Path:   "vm/" + vmm.QueryRealm,
+		Data:   []byte(flags.exportRealm),
+		Height: height,
+	})
+	if res.IsErr() {
+		return fmt.Errorf("error in exporting realm %s at height %d: %s", flags.exportRealm, height, res.Log)
+	}
+	if err := os.WriteFile(flags.exportFile, res.Data, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported the state at height %d of realm %s to %s.\n", height, flags.exportRealm, flags.exportFile)
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	osm "github.com/gnolang/gno/pkgs/os"
+)
+
+const initUsage = `usage: gnoland init --network <chainid> [--root-dir <dir>] [--genesis-hash <hex>]
+
+Creates the data directory of a node joining the network of a chain of the
+chain registry (see $GNO_CHAINS), e.g. test3: its genesis file is fetched from
+the genesis URL of the chain, or else from its RPC endpoints, and verified
+against the genesis hash pinned in the registry, or given by --genesis-hash.
+The config file is written with the profile and seeds of the network.
+`
+
+// The timeout of the download of a genesis file.
+const genesisDownloadTimeout = 5 * time.Minute
+
+// Runs the init command of args.
+func runInit(args []string) error {
+	return runInitCommand(args, os.Stdout)
+}
+
+func runInitCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("gnoland init", flag.ContinueOnError)
+	rootDir := fs.String("root-dir", "testdir", "data directory of the node")
+	network := fs.String("network", "", "chainid of the network to join, in the chain registry")
+	genesisHash := fs.String("genesis-hash", "", "hex hash of the genesis, if not pinned in the chain registry")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *network == "" || fs.NArg() != 0 {
+		return errors.New(initUsage)
+	}
+
+	reg, err := client.DefaultRegistry()
+	if err != nil {
+		return err
+	}
+	ci, ok := reg.Chain(*network)
+	if !ok {
+		return fmt.Errorf("unknown network %q, see $%s", *network, client.RegistryEnv)
+	}
+	if *genesisHash != "" {
+		ci.GenesisHash = strings.ToLower(*genesisHash)
+		if err := ci.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	if ci.GenesisHash == "" {
+		return fmt.Errorf("no genesis hash pinned for network %q, use --genesis-hash", *network)
+	}
+
+	// don't overwrite the files of a node.
+	cfgPath := config.ConfigFilePath(*rootDir)
+	genesisPath := filepath.Join(*rootDir, config.DefaultConfig().Genesis)
+	for _, path := range []string{cfgPath, genesisPath} {
+		if osm.FileExists(path) {
+			return fmt.Errorf("%s already exists", path)
+		}
+	}
+
+	// fetch and verify the genesis.
+	if ci.Bech32Prefix != "" {
+		if err := crypto.SetBech32Prefix(ci.Bech32Prefix); err != nil {
+			return err
+		}
+	}
+	genDoc, err := fetchGenesis(reg, ci)
+	if err != nil {
+		return err
+	}
+	if err := ci.VerifyGenesisDoc(genDoc); err != nil {
+		return err
+	}
+
+	// write the config of the network, and the genesis.
+	cfg := config.DefaultConfig()
+	nodeConfigOptions(cfg)
+	cfg.Profile = ci.Profile
+	cfg.P2P.Seeds = strings.Join(ci.Seeds, ",")
+	if err := cfg.ValidateBasic(); err != nil {
+		return err
+	}
+	cfg.SetRootDir(*rootDir)
+	cfg.EnsureDirs()
+	config.WriteConfigFile(cfgPath, cfg)
+	fmt.Fprintf(out, "Wrote %s.\n", cfgPath)
+	if err := genDoc.SaveAs(genesisPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote %s, of hash %s.\n", genesisPath, ci.GenesisHash)
+	return nil
+}
+
+// Returns the genesis of the chain of ci, downloaded from its genesis URL,
+// or else from its RPC endpoints.
+func fetchGenesis(reg *client.Registry, ci client.ChainInfo) (*bft.GenesisDoc, error) {
+	if ci.GenesisURL == "" {
+		c, err := reg.Dial(ci.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		res, err := c.Genesis()
+		if err != nil {
+			return nil, fmt.Errorf("fetching genesis: %w", err)
+		}
+		return res.Genesis, nil
+	}
+
+	httpClient := &http.Client{Timeout: genesisDownloadTimeout}
+	res, err := httpClient.Get(ci.GenesisURL)
+	if err != nil {
+		return nil, fmt.Errorf("downloading genesis: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading genesis: %s", res.Status)
+	}
+	bz, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("downloading genesis: %w", err)
+	}
+	genDoc, err := bft.GenesisDocFromJSON(bz)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading genesis: %w", err)
+	}
+	return genDoc, nil
+}
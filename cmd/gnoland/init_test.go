@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+)
+
+func TestInitCommand(t *testing.T) {
+	// the genesis of the network, served by a web server.
+	genDoc := &bft.GenesisDoc{
+		ChainID: "testnet",
+		Validators: []bft.GenesisValidator{
+			{PubKey: ed25519.GenPrivKey().PubKey(), Power: 10, Name: "val"},
+		},
+	}
+	require.NoError(t, genDoc.ValidateAndComplete())
+	genesisJSON := amino.MustMarshalJSON(genDoc)
+	hash, err := client.GenesisHash(genDoc)
+	require.NoError(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(genesisJSON)
+	}))
+	defer srv.Close()
+
+	// the registry of the network.
+	registry := filepath.Join(t.TempDir(), "chains.json")
+	writeRegistry := func(genesisHash string) {
+		reg := fmt.Sprintf(`{"chains": [{"chain_id": "testnet", "rpc": ["tcp://127.0.0.1:1"], "genesis_url": %q,
+			"genesis_hash": %q, "seeds": ["%s@1.2.3.4:26656"], "profile": "wan"}]}`,
+			srv.URL, genesisHash, ed25519.GenPrivKey().PubKey().Address().ID())
+		require.NoError(t, os.WriteFile(registry, []byte(reg), 0o644))
+	}
+	t.Setenv(client.RegistryEnv, registry)
+
+	rootDir := t.TempDir()
+	genesisPath := filepath.Join(rootDir, config.DefaultConfig().Genesis)
+	run := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		err := runInitCommand(append(args, "--root-dir", rootDir), out)
+		return out.String(), err
+	}
+
+	_, err = run()
+	require.Error(t, err) // no network.
+	_, err = run("--network", "unknown")
+	require.Error(t, err)
+
+	// the hash must be pinned.
+	writeRegistry("")
+	_, err = run("--network", "testnet")
+	require.Error(t, err)
+	_, err = run("--network", "testnet", "--genesis-hash", hex.EncodeToString(make([]byte, 32)))
+	require.Error(t, err) // mismatch.
+	require.NoFileExists(t, genesisPath)
+	_, err = run("--network", "testnet", "--genesis-hash", hex.EncodeToString(hash))
+	require.NoError(t, err)
+
+	// the genesis and config of the network are written.
+	genDoc2, err := bft.GenesisDocFromFile(genesisPath)
+	require.NoError(t, err)
+	hash2, err := client.GenesisHash(genDoc2)
+	require.NoError(t, err)
+	require.Equal(t, hash, hash2)
+	cfg, err := config.ReadConfigFile(config.ConfigFilePath(rootDir))
+	require.NoError(t, err)
+	require.Equal(t, "wan", cfg.Profile)
+	require.Contains(t, cfg.P2P.Seeds, "@1.2.3.4:26656")
+
+	// the files of a node are not overwritten.
+	writeRegistry(hex.EncodeToString(hash))
+	_, err = run("--network", "testnet")
+	require.Error(t, err)
+	os.RemoveAll(rootDir)
+	out, err := run("--network", "testnet")
+	require.NoError(t, err)
+	require.Contains(t, out, hex.EncodeToString(hash))
+}
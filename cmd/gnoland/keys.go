@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/privval"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/p2p"
+	"github.com/gnolang/gno/pkgs/sdk/valset"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+const keysUsage = `usage: gnoland keys <command> [--root-dir <dir>] [<args>]
+
+commands:
+  show                       print the node ID, and the validator keys
+  rotate-node-key            replace the p2p node key, used once the node is restarted
+  prepare-validator-key --height <height> --caller <address>
+                             generate the next validator key, and print the
+                             tx announcing its rotation at height
+
+The validator key is rotated in two phases.  prepare-validator-key writes the
+next key next to the current one, and the tx which announces the rotation,
+signed by the current key.  Once the tx is signed by the caller, e.g. with
+"gnokey sign", and committed at least 2 blocks before the height, the chain
+replaces the validator at the height, and the running node switches to the
+next key.  If the tx is not committed in time, the node keeps the current
+key, and the rotation can be prepared again with --force.
+`
+
+// Runs the keys command of args, on the keys of the node.
+func runKeys(args []string) error {
+	return runKeysCommand(args, os.Stdout)
+}
+
+func runKeysCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(keysUsage)
+	}
+	cmd := args[0]
+	fs := flag.NewFlagSet("gnoland keys "+cmd, flag.ContinueOnError)
+	rootDir := fs.String("root-dir", "testdir", "data directory of the node")
+	height := fs.Int64("height", 0, "height from which the next validator key is used")
+	caller := fs.String("caller", "", "address of the account paying for the rotation tx")
+	chainID := fs.String("chainid", "", "chainid of the rotation tx, or empty for that of the genesis")
+	gasWanted := fs.Int64("gas-wanted", 2000000, "gas requested for the rotation tx")
+	gasFee := fs.String("gas-fee", "1000000ugnot", "gas payment fee of the rotation tx")
+	force := fs.Bool("force", false, "replace the next validator key of a previous prepare-validator-key")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %v\n\n%s", fs.Args(), keysUsage)
+	}
+	cfg, err := loadConfig(*rootDir, true)
+	if err != nil {
+		return err
+	}
+	if err := setBech32Prefix(cfg.GenesisFile()); err != nil {
+		return err
+	}
+	keyFile := cfg.PrivValidatorKeyFile()
+	nextKeyFile := privval.NextKeyFilePath(keyFile)
+
+	switch cmd {
+	case "show":
+		nodeKey, err := p2p.LoadNodeKey(cfg.NodeKeyFile())
+		if err != nil {
+			return err
+		}
+		pv := privval.LoadFilePVEmptyState(keyFile, cfg.PrivValidatorStateFile())
+		fmt.Fprintf(out, "node id:           %s\n", nodeKey.ID())
+		fmt.Fprintf(out, "validator address: %s\n", pv.GetAddress())
+		fmt.Fprintf(out, "validator pubkey:  %s\n", crypto.PubKeyToBech32(pv.GetPubKey()))
+		if pv.NextKey != nil {
+			fmt.Fprintf(out, "next address:      %s (from height %d)\n", pv.NextKey.Address, pv.NextKey.Height)
+			fmt.Fprintf(out, "next pubkey:       %s\n", crypto.PubKeyToBech32(pv.NextKey.PubKey))
+		}
+	case "rotate-node-key":
+		prev, next, err := p2p.RotateNodeKey(cfg.NodeKeyFile())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Rotated the node key from %s to %s, restart the node to use it.\n", prev.ID(), next.ID())
+		fmt.Fprintf(out, "Peers with the node in their persistent or private peers must be updated.\n")
+	case "prepare-validator-key":
+		if *height <= 0 {
+			return errors.New("--height must be specified")
+		}
+		callerAddr, err := crypto.AddressFromBech32(*caller)
+		if err != nil {
+			return fmt.Errorf("invalid --caller: %w", err)
+		}
+		fee, err := std.ParseCoin(*gasFee)
+		if err != nil {
+			return fmt.Errorf("invalid --gas-fee: %w", err)
+		}
+		if osm.FileExists(nextKeyFile) && !*force {
+			return fmt.Errorf("next validator key %s already exists, use --force to replace it", nextKeyFile)
+		}
+		if *chainID == "" {
+			genDoc, err := bft.GenesisDocFromFile(cfg.GenesisFile())
+			if err != nil {
+				return fmt.Errorf("error in loading genesis: %w", err)
+			}
+			*chainID = genDoc.ChainID
+		}
+		pv := privval.LoadFilePVEmptyState(keyFile, cfg.PrivValidatorStateFile())
+		nextKey := privval.GenFilePVNextKey(keyFile, *height)
+
+		msg := valset.NewMsgRotateKey(callerAddr, pv.GetAddress(), nextKey.PubKey, *height)
+		msg.Signature, err = pv.Key.PrivKey.Sign(msg.ValidatorSignBytes(*chainID))
+		if err != nil {
+			return err
+		}
+		tx := std.Tx{
+			Msgs: []std.Msg{msg},
+			Fee:  std.NewFee(*gasWanted, fee),
+		}
+		nextKey.Save()
+		fmt.Fprintln(out, string(amino.MustMarshalJSON(tx)))
+		fmt.Fprintf(os.Stderr, "Wrote the next validator key %s to %s.\n", nextKey.Address, nextKeyFile)
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", cmd, keysUsage)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/bft/privval"
+	"github.com/gnolang/gno/pkgs/p2p"
+	"github.com/gnolang/gno/pkgs/sdk/valset"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+func TestKeysCommands(t *testing.T) {
+	rootDir := t.TempDir()
+	run := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		// flags come after the command.
+		args = append([]string{args[0], "--root-dir", rootDir}, args[1:]...)
+		err := runKeysCommand(args, out)
+		return out.String(), err
+	}
+	_, err := run("show")
+	require.Error(t, err) // no config file.
+
+	cfg := config.LoadOrMakeConfigWithOptions(rootDir, nodeConfigOptions)
+	nodeKey, err := p2p.LoadOrGenNodeKey(cfg.NodeKeyFile())
+	require.NoError(t, err)
+	pv := privval.LoadOrGenFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile())
+	out, err := run("show")
+	require.NoError(t, err)
+	require.Contains(t, out, string(nodeKey.ID()))
+	require.Contains(t, out, pv.GetAddress().String())
+
+	out, err = run("rotate-node-key")
+	require.NoError(t, err)
+	require.Contains(t, out, string(nodeKey.ID()))
+	nodeKey2, err := p2p.LoadNodeKey(cfg.NodeKeyFile())
+	require.NoError(t, err)
+	require.NotEqual(t, nodeKey.ID(), nodeKey2.ID())
+
+	caller := "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"
+	_, err = run("prepare-validator-key", "--caller", caller)
+	require.Error(t, err) // no height.
+	out, err = run("prepare-validator-key", "--height", "100", "--caller", caller, "--chainid", "test")
+	require.NoError(t, err)
+	var tx std.Tx
+	require.NoError(t, amino.UnmarshalJSON([]byte(out), &tx))
+	msg := tx.Msgs[0].(valset.MsgRotateKey)
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, pv.GetAddress(), msg.Validator)
+	require.True(t, pv.GetPubKey().VerifyBytes(msg.ValidatorSignBytes("test"), msg.Signature))
+
+	pv = privval.LoadFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile())
+	require.NotNil(t, pv.NextKey)
+	require.Equal(t, msg.NewPubKey, pv.NextKey.PubKey)
+	require.Equal(t, int64(100), pv.NextKey.Height)
+
+	_, err = run("prepare-validator-key", "--height", "100", "--caller", caller, "--chainid", "test")
+	require.Error(t, err)
+	_, err = run("prepare-validator-key", "--height", "100", "--caller", caller, "--chainid", "test", "--force")
+	require.NoError(t, err)
+}
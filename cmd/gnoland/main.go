@@ -19,8 +19,10 @@ import (
 	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/log"
 	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/sdk"
 	vmm "github.com/gnolang/gno/pkgs/sdk/vm"
 	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
 )
 
 func main() {
@@ -33,30 +35,91 @@ func main() {
 }
 
 var flags struct {
+	rootDir               string
 	skipFailingGenesisTxs bool
 	skipStart             bool
 	genesisBalancesFile   string
 	genesisTxsFile        string
+	genesisRealms         string
 	chainID               string
+	bech32Prefix          string
 	genesisRemote         string
+	dev                   bool
+	devRootDir            string
+	devPkgs               string
+	replay                bool
+	replayRootDir         string
+	replayFrom            int64
+	replayTo              int64
+	replayDiff            int
+	recentTxWindow        int64
+	keepRecent            int64
+	invCheckPeriod        int64
+	haltOnInvariant       bool
+	export                bool
+	exportRootDir         string
+	exportHeight          int64
+	exportChainID         string
+	exportFile            string
+	exportRealm           string
 }
 
 func runMain(args []string) error {
+	if len(args) > 0 && args[0] == "init" {
+		return runInit(args[1:])
+	}
+	if len(args) > 0 && args[0] == "config" {
+		return runConfig(args[1:])
+	}
+	if len(args) > 0 && args[0] == "keys" {
+		return runKeys(args[1:])
+	}
+	if len(args) > 0 && args[0] == "peers" {
+		return runPeers(args[1:])
+	}
+	if len(args) > 0 && args[0] == "testnet" {
+		return runTestnet(args[1:])
+	}
 	fs := flag.NewFlagSet("gnoland", flag.ExitOnError)
 	fs.BoolVar(&flags.skipFailingGenesisTxs, "skip-failing-genesis-txs", false, "don't panic when replaying invalid genesis txs")
 	fs.BoolVar(&flags.skipStart, "skip-start", false, "quit after initialization, don't start the node")
-	fs.StringVar(&flags.genesisBalancesFile, "genesis-balances-file", "./gnoland/genesis/genesis_balances.txt", "initial distribution file")
-	fs.StringVar(&flags.genesisTxsFile, "genesis-txs-file", "./gnoland/genesis/genesis_txs.txt", "initial txs to replay")
-	fs.StringVar(&flags.chainID, "chainid", "dev", "chainid")
-	fs.StringVar(&flags.genesisRemote, "genesis-remote", "localhost:26657", "replacement for '%%REMOTE%%' in genesis")
+	fs.StringVar(&flags.rootDir, "root-dir", "testdir", "data directory of the node")
+	genesisFlags(fs)
+	fs.Int64Var(&flags.recentTxWindow, "recent-tx-window", 0, "reject txs with the canonical hash of a tx delivered in this number of last blocks, 0 to disable")
+	fs.Int64Var(&flags.keepRecent, "keep-recent", 100, "number of heights before the last one whose state is kept for queries, older states are pruned")
+	fs.Int64Var(&flags.invCheckPeriod, "inv-check-period", 0, "check the invariants of the modules every this number of blocks, 0 to disable")
+	fs.BoolVar(&flags.haltOnInvariant, "halt-on-broken-invariant", false, "halt the node, before committing the block, when an invariant is broken, instead of logging it")
+	fs.BoolVar(&flags.dev, "dev", false, "run a single-node chain for development, see --dev-pkgs")
+	fs.StringVar(&flags.devRootDir, "dev-root-dir", "testdir-dev", "data directory of --dev, cleared on start")
+	fs.StringVar(&flags.devPkgs, "dev-pkgs", "", "comma separated package directories to deploy and watch with --dev")
+	fs.BoolVar(&flags.replay, "replay", false, "replay the blocks of a node against a fresh state and compare app hashes, see --replay-*")
+	fs.StringVar(&flags.replayRootDir, "replay-root-dir", "testdir", "data directory of the node to replay, which must be stopped")
+	fs.Int64Var(&flags.replayFrom, "replay-from", 1, "first height to compare with --replay")
+	fs.Int64Var(&flags.replayTo, "replay-to", 0, "last height to replay with --replay, or 0 for the last block")
+	fs.IntVar(&flags.replayDiff, "replay-diff", 0, "on a mismatch, print up to this number of differences per store with --replay")
+	fs.BoolVar(&flags.export, "export", false, "write a genesis file with the committed state of a node, see --export-*")
+	fs.StringVar(&flags.exportRootDir, "export-root-dir", "testdir", "data directory of the node to export, which must be stopped")
+	fs.Int64Var(&flags.exportHeight, "export-height", 0, "height of the state to export with --export, or 0 for the last block")
+	fs.StringVar(&flags.exportChainID, "export-chainid", "", "chainid of the exported genesis, or empty for that of the node")
+	fs.StringVar(&flags.exportFile, "export-file", "genesis-export.json", "genesis file to write with --export")
+	fs.StringVar(&flags.exportRealm, "export-realm", "", "with --export, write the state of the realm with this path to --export-file instead of a genesis file")
 	fs.Parse(args)
 
 	logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout))
-	rootDir := "testdir"
-	cfg := config.LoadOrMakeConfigWithOptions(rootDir, func(cfg *config.Config) {
-		cfg.Consensus.CreateEmptyBlocks = false
-		cfg.Consensus.CreateEmptyBlocksInterval = 60 * time.Second
-	})
+	if flags.dev {
+		return runDev(logger)
+	}
+	if flags.replay {
+		return runReplay(logger)
+	}
+	if flags.export {
+		return runExport(logger)
+	}
+	rootDir := flags.rootDir
+	cfg := config.LoadOrMakeConfigWithOptions(rootDir, nodeConfigOptions)
+	if _, err := cfg.ApplyEnv(configEnvPrefix); err != nil {
+		return err
+	}
 
 	// create priv validator first.
 	// need it to generate genesis.json
@@ -66,13 +129,20 @@ func runMain(args []string) error {
 
 	// write genesis file if missing.
 	genesisFilePath := filepath.Join(rootDir, cfg.Genesis)
+	if err := setBech32Prefix(genesisFilePath); err != nil {
+		return err
+	}
 	if !osm.FileExists(genesisFilePath) {
-		genDoc := makeGenesisDoc(priv.GetPubKey())
+		genDoc := makeGenesisDoc(priv.GetPubKey(), examplePackages(), nil)
 		writeGenesisFile(genDoc, genesisFilePath)
 	}
 
 	// create application and node.
-	gnoApp, err := gnoland.NewApp(rootDir, flags.skipFailingGenesisTxs, logger)
+	gnoApp, err := gnoland.NewApp(rootDir, flags.skipFailingGenesisTxs, logger,
+		sdk.SetRecentTxWindow(flags.recentTxWindow),
+		sdk.SetPruningOptions(store.NewPruningOptions(flags.keepRecent, 0)),
+		sdk.SetInvariantCheckPeriod(flags.invCheckPeriod),
+		sdk.SetHaltOnBrokenInvariant(flags.haltOnInvariant))
 	if err != nil {
 		return fmt.Errorf("error in creating new app: %w", err)
 	}
@@ -101,11 +171,74 @@ func runMain(args []string) error {
 	select {} // run forever
 }
 
-// Makes a local test genesis doc with local privValidator.
-func makeGenesisDoc(pvPub crypto.PubKey) *bft.GenesisDoc {
+// Sets the bech32 prefix of the addresses of the chain: that of the genesis
+// file if it exists, else that of --bech32-prefix for a new chain.
+func setBech32Prefix(genesisFile string) error {
+	prefix := flags.bech32Prefix
+	if osm.FileExists(genesisFile) {
+		jsonBlob, err := os.ReadFile(genesisFile)
+		if err != nil {
+			return err
+		}
+		prefix, err = bft.GenesisBech32PrefixFromJSON(jsonBlob)
+		if err != nil {
+			return fmt.Errorf("error in reading genesis: %w", err)
+		}
+	}
+	if prefix == "" {
+		prefix = crypto.Bech32AddrPrefix
+	}
+	return crypto.SetBech32Prefix(prefix)
+}
+
+// Adds the flags of the genesis of a new chain to fs.
+func genesisFlags(fs *flag.FlagSet) {
+	fs.StringVar(&flags.genesisBalancesFile, "genesis-balances-file", "./gnoland/genesis/genesis_balances.txt", "initial distribution file")
+	fs.StringVar(&flags.genesisTxsFile, "genesis-txs-file", "./gnoland/genesis/genesis_txs.txt", "initial txs to replay")
+	fs.StringVar(&flags.genesisRealms, "genesis-realms", "", "comma separated realm state files to import after the initial txs, see --export-realm")
+	fs.StringVar(&flags.chainID, "chainid", "dev", "chainid")
+	fs.StringVar(&flags.bech32Prefix, "bech32-prefix", crypto.Bech32AddrPrefix, "bech32 prefix of the addresses of a new chain")
+	fs.StringVar(&flags.genesisRemote, "genesis-remote", "localhost:26657", "replacement for '%%REMOTE%%' in genesis")
+}
+
+// Sets the defaults of the config of the node.
+func nodeConfigOptions(cfg *config.Config) {
+	cfg.Consensus.CreateEmptyBlocks = false
+	cfg.Consensus.CreateEmptyBlocksInterval = 60 * time.Second
+	cfg.Mempool.SystemLaneGas = 1000000 // a tenth of the max gas of blocks
+}
+
+// Returns the example packages added at genesis.
+func examplePackages() []*std.MemPackage {
+	pkgs := []*std.MemPackage{}
+	for _, path := range []string{
+		"p/ufmt",
+		"p/avl",
+		"p/grc/exts",
+		"p/grc/grc20",
+		"p/grc/grc721",
+		"p/maths",
+		"r/users",
+		"r/foo20",
+		"r/boards",
+		"r/banktest",
+	} {
+		// open files in directory as MemPackage.
+		memPkg := gno.ReadMemPackage(filepath.Join(".", "examples", "gno.land", path), "gno.land/"+path)
+		pkgs = append(pkgs, memPkg)
+	}
+	return pkgs
+}
+
+// Makes a local test genesis doc with local privValidator, which adds pkgs
+// and runs the genesis txs, followed by extraTxs.
+func makeGenesisDoc(pvPub crypto.PubKey, pkgs []*std.MemPackage, extraTxs []std.Tx) *bft.GenesisDoc {
 	gen := &bft.GenesisDoc{}
 	gen.GenesisTime = time.Now()
 	gen.ChainID = flags.chainID
+	if prefix := crypto.Bech32Prefix(); prefix != crypto.Bech32AddrPrefix {
+		gen.Bech32Prefix = prefix
+	}
 	gen.ConsensusParams = abci.ConsensusParams{
 		Block: &abci.BlockParams{
 			// TODO: update limits.
@@ -128,23 +261,10 @@ func makeGenesisDoc(pvPub crypto.PubKey) *bft.GenesisDoc {
 	balances := loadGenesisBalances(flags.genesisBalancesFile)
 	// debug: for _, balance := range balances { fmt.Println(balance) }
 
-	// Load initial packages from examples.
+	// Load initial packages.
 	test1 := crypto.MustAddressFromString("g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5")
 	txs := []std.Tx{}
-	for _, path := range []string{
-		"p/ufmt",
-		"p/avl",
-		"p/grc/exts",
-		"p/grc/grc20",
-		"p/grc/grc721",
-		"p/maths",
-		"r/users",
-		"r/foo20",
-		"r/boards",
-		"r/banktest",
-	} {
-		// open files in directory as MemPackage.
-		memPkg := gno.ReadMemPackage(filepath.Join(".", "examples", "gno.land", path), "gno.land/"+path)
+	for _, memPkg := range pkgs {
 		var tx std.Tx
 		tx.Msgs = []std.Msg{
 			vmm.MsgAddPackage{
@@ -161,11 +281,16 @@ func makeGenesisDoc(pvPub crypto.PubKey) *bft.GenesisDoc {
 	// load genesis txs from file.
 	genesisTxs := loadGenesisTxs(flags.genesisTxsFile)
 	txs = append(txs, genesisTxs...)
+	txs = append(txs, extraTxs...)
+
+	// load realm states from files.
+	realms := loadGenesisRealms(flags.genesisRealms)
 
 	// construct genesis AppState.
 	gen.AppState = gnoland.GnoGenesisState{
 		Balances: balances,
 		Txs:      txs,
+		Realms:   realms,
 	}
 	return gen
 }
@@ -197,6 +322,21 @@ func loadGenesisTxs(path string) []std.Tx {
 	return txs
 }
 
+// Returns the realm states of the comma separated files of paths, as
+// written by --export-realm.
+func loadGenesisRealms(paths string) []gno.RealmState {
+	if paths == "" {
+		return nil
+	}
+	realms := []gno.RealmState{}
+	for _, path := range strings.Split(paths, ",") {
+		var state gno.RealmState
+		amino.MustUnmarshalJSON(osm.MustReadFile(path), &state)
+		realms = append(realms, state)
+	}
+	return realms
+}
+
 func loadGenesisBalances(path string) []string {
 	// each balance is in the form: g1xxxxxxxxxxxxxxxx=100000ugnot
 	balances := []string{}
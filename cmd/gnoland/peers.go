@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/p2p"
+)
+
+const peersUsage = `usage: gnoland peers <command> [--root-dir <dir>] [--remote <addr>] [<args>]
+
+commands:
+  list                       print the known peers, by decreasing score, and the bans
+  add <id>@<ip>:<port>       add the address of a peer
+  remove <id>                remove the address of a peer
+  ban <id|ip|cidr>           ban a peer ID, an IP or an IP range, e.g. 10.0.0.0/8
+  unban <id|ip|cidr>         lift a ban
+  export [<file>]            print the address book as JSON, or write it to file
+  import <file>              merge the peers and bans of an exported address book
+
+The score of a peer is its number of successful dials, minus the failed ones.
+Without --remote, the commands edit the address book file of the node, which
+must not be running.  With --remote, they edit the address book of the running
+node through its RPC, whose unsafe routes must be enabled; the peers banned are
+then disconnected.
+`
+
+// The address book of the node, in its file or through the RPC.
+type addrBookEditor interface {
+	AddrBook() (*ctypes.ResultAddrBook, error)
+	AddrBookAdd(addr string) (*ctypes.ResultAddrBook, error)
+	AddrBookRemove(id string) (*ctypes.ResultAddrBook, error)
+	AddrBookBan(target string) (*ctypes.ResultAddrBook, error)
+	AddrBookUnban(target string) (*ctypes.ResultAddrBook, error)
+	AddrBookImport(book p2p.AddrBookJSON) (*ctypes.ResultAddrBook, error)
+}
+
+// Runs the peers command of args, on the address book of the node.
+func runPeers(args []string) error {
+	return runPeersCommand(args, os.Stdout)
+}
+
+func runPeersCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(peersUsage)
+	}
+	cmd := args[0]
+	fs := flag.NewFlagSet("gnoland peers "+cmd, flag.ContinueOnError)
+	rootDir := fs.String("root-dir", "testdir", "data directory of the node")
+	remote := fs.String("remote", "", "RPC address of the running node, e.g. localhost:26657")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	nargs := map[string]int{"list": 0, "add": 1, "remove": 1, "ban": 1, "unban": 1, "export": -1, "import": 1}
+	n, ok := nargs[cmd]
+	if !ok {
+		return fmt.Errorf("unknown command %q\n\n%s", cmd, peersUsage)
+	}
+	if (n >= 0 && fs.NArg() != n) || (n < 0 && fs.NArg() > 1) {
+		return fmt.Errorf("wrong number of arguments %v\n\n%s", fs.Args(), peersUsage)
+	}
+	arg := fs.Arg(0)
+
+	var editor addrBookEditor
+	if *remote != "" {
+		editor = client.NewHTTP(*remote, "/websocket")
+	} else {
+		cfg, err := loadConfig(*rootDir, true)
+		if err != nil {
+			return err
+		}
+		if cfg.P2P.AddrBookFile() == "" {
+			return errors.New("no address book, p2p.addr_book_file is empty")
+		}
+		book, err := p2p.NewAddrBook(cfg.P2P.AddrBookFile())
+		if err != nil {
+			return err
+		}
+		editor = fileAddrBook{book}
+	}
+
+	var res *ctypes.ResultAddrBook
+	var err error
+	switch cmd {
+	case "list":
+		res, err = editor.AddrBook()
+	case "add":
+		res, err = editor.AddrBookAdd(arg)
+	case "remove":
+		res, err = editor.AddrBookRemove(arg)
+	case "ban":
+		res, err = editor.AddrBookBan(arg)
+	case "unban":
+		res, err = editor.AddrBookUnban(arg)
+	case "export":
+		res, err = editor.AddrBook()
+		if err != nil {
+			return err
+		}
+		bz, err := amino.MarshalJSONIndent(res.Book, "", "  ")
+		if err != nil {
+			return err
+		}
+		if arg == "" {
+			fmt.Fprintln(out, string(bz))
+			return nil
+		}
+		if err := osm.WriteFileAtomic(arg, bz, 0o600); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Exported %d peers to %s.\n", len(res.Book.Addrs), arg)
+		return nil
+	case "import":
+		bz, err := osm.ReadFile(arg)
+		if err != nil {
+			return err
+		}
+		var book p2p.AddrBookJSON
+		if err := amino.UnmarshalJSON(bz, &book); err != nil {
+			return fmt.Errorf("error reading address book %s: %w", arg, err)
+		}
+		res, err = editor.AddrBookImport(book)
+		if err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	printAddrBook(out, res.Book)
+	return nil
+}
+
+// Prints the peers of book, with their scores, and its bans.
+func printAddrBook(out io.Writer, book p2p.AddrBookJSON) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tSCORE\tFAILED DIALS\tLAST SUCCESS")
+	for _, ka := range book.Addrs {
+		lastSuccess := "never"
+		if !ka.LastSuccess.IsZero() {
+			lastSuccess = ka.LastSuccess.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", ka.Addr, ka.Score, ka.Attempts, lastSuccess)
+	}
+	w.Flush()
+	if len(book.BannedIDs) > 0 || len(book.BannedIPs) > 0 {
+		banned := make([]string, 0, len(book.BannedIDs)+len(book.BannedIPs))
+		for _, id := range book.BannedIDs {
+			banned = append(banned, string(id))
+		}
+		banned = append(banned, book.BannedIPs...)
+		fmt.Fprintf(out, "banned: %s\n", strings.Join(banned, ", "))
+	}
+}
+
+// The address book file, edited like through the RPC.
+type fileAddrBook struct {
+	book *p2p.AddrBook
+}
+
+func (f fileAddrBook) result(err error) (*ctypes.ResultAddrBook, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultAddrBook{Book: f.book.Export()}, nil
+}
+
+func (f fileAddrBook) AddrBook() (*ctypes.ResultAddrBook, error) {
+	return f.result(nil)
+}
+
+func (f fileAddrBook) AddrBookAdd(addr string) (*ctypes.ResultAddrBook, error) {
+	na, err := p2p.NewNetAddressFromString(addr)
+	if err != nil {
+		return nil, err
+	}
+	return f.result(f.book.AddAddress(na))
+}
+
+func (f fileAddrBook) AddrBookRemove(id string) (*ctypes.ResultAddrBook, error) {
+	return f.result(f.book.RemoveAddress(p2p.ID(id)))
+}
+
+func (f fileAddrBook) AddrBookBan(target string) (*ctypes.ResultAddrBook, error) {
+	return f.result(f.book.Ban(target))
+}
+
+func (f fileAddrBook) AddrBookUnban(target string) (*ctypes.ResultAddrBook, error) {
+	return f.result(f.book.Unban(target))
+}
+
+func (f fileAddrBook) AddrBookImport(book p2p.AddrBookJSON) (*ctypes.ResultAddrBook, error) {
+	return f.result(f.book.Import(book))
+}
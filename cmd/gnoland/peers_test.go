@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+	"github.com/gnolang/gno/pkgs/p2p"
+)
+
+func TestPeersCommands(t *testing.T) {
+	rootDir := t.TempDir()
+	run := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		// flags come after the command.
+		args = append([]string{args[0], "--root-dir", rootDir}, args[1:]...)
+		err := runPeersCommand(args, out)
+		return out.String(), err
+	}
+	_, err := run("list")
+	require.Error(t, err) // no config file.
+
+	cfg := config.LoadOrMakeConfigWithOptions(rootDir, nodeConfigOptions)
+	id := ed25519.GenPrivKey().PubKey().Address().ID()
+	addr := fmt.Sprintf("%s@1.2.3.4:26656", id)
+	out, err := run("add", addr)
+	require.NoError(t, err)
+	require.Contains(t, out, addr)
+	_, err = run("add", "nonsense")
+	require.Error(t, err)
+
+	out, err = run("ban", "10.0.0.0/8")
+	require.NoError(t, err)
+	require.Contains(t, out, "banned: 10.0.0.0/8")
+	_, err = run("ban")
+	require.Error(t, err) // no target.
+
+	exported := filepath.Join(t.TempDir(), "exported.json")
+	_, err = run("export", exported)
+	require.NoError(t, err)
+
+	_, err = run("remove", string(id))
+	require.NoError(t, err)
+	_, err = run("unban", "10.0.0.0/8")
+	require.NoError(t, err)
+	out, err = run("list")
+	require.NoError(t, err)
+	require.NotContains(t, out, addr)
+	require.NotContains(t, out, "banned")
+
+	out, err = run("import", exported)
+	require.NoError(t, err)
+	require.Contains(t, out, addr)
+	require.Contains(t, out, "banned: 10.0.0.0/8")
+
+	// saved to the file of the node.
+	book, err := p2p.NewAddrBook(cfg.P2P.AddrBookFile())
+	require.NoError(t, err)
+	require.Len(t, book.Export().Addrs, 1)
+	require.Equal(t, []string{"10.0.0.0/8"}, book.Export().BannedIPs)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gnolang/gno/gnoland"
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/bft/node"
+	"github.com/gnolang/gno/pkgs/bft/replay"
+	"github.com/gnolang/gno/pkgs/bft/store"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/log"
+	"github.com/gnolang/gno/pkgs/sdk"
+)
+
+// Replays the blocks of the node at --replay-root-dir against an app with a
+// fresh state, and prints the app hash of each compared height.  On a
+// mismatch, the states of the replayed and original apps at that height
+// are diffed if --replay-diff is set.
+func runReplay(logger log.Logger) error {
+	rootDir := flags.replayRootDir
+	cfg := config.LoadOrMakeConfigWithOptions(rootDir, func(cfg *config.Config) {})
+	if err := setBech32Prefix(cfg.GenesisFile()); err != nil {
+		return err
+	}
+	genDoc, err := bft.GenesisDocFromFile(cfg.GenesisFile())
+	if err != nil {
+		return fmt.Errorf("error in loading genesis: %w", err)
+	}
+	blockStoreDB, err := node.DefaultDBProvider(&node.DBContext{ID: "blockstore", Config: cfg})
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	stateDB, err := node.DefaultDBProvider(&node.DBContext{ID: "state", Config: cfg})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	replayDir, err := os.MkdirTemp("", "gnoland-replay")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(replayDir)
+	replayApp, err := gnoland.NewApp(replayDir, flags.skipFailingGenesisTxs, logger)
+	if err != nil {
+		return fmt.Errorf("error in creating replay app: %w", err)
+	}
+
+	rp := replay.NewReplayer(genDoc, store.NewBlockStore(blockStoreDB), stateDB, logger)
+	results, err := rp.Replay(replayApp, flags.replayFrom, flags.replayTo)
+	for _, res := range results {
+		fmt.Fprintln(os.Stderr, res.String())
+	}
+	if err != nil {
+		return err
+	}
+	last := results[len(results)-1]
+	if !last.Mismatch() {
+		fmt.Fprintf(os.Stderr, "Replayed %d heights, all app hashes match.\n", len(results))
+		return nil
+	}
+	if flags.replayDiff > 0 {
+		if err := printReplayDiff(replayApp.(*sdk.BaseApp), rootDir, last.Height, logger); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("app hash mismatch at height %d", last.Height)
+}
+
+// Prints the differences between the state of the replayed app and that of
+// the app of the node at rootDir, at height.
+func printReplayDiff(replayApp *sdk.BaseApp, rootDir string, height int64, logger log.Logger) error {
+	app, err := gnoland.NewApp(rootDir, flags.skipFailingGenesisTxs, logger)
+	if err != nil {
+		return fmt.Errorf("error in creating app: %w", err)
+	}
+	diffs, err := app.(*sdk.BaseApp).DiffStores(replayApp, height, flags.replayDiff)
+	if err != nil {
+		// e.g. the state of the node at height was pruned.
+		return fmt.Errorf("error in diffing states at height %d: %w", height, err)
+	}
+	for name, sdiffs := range diffs {
+		fmt.Fprintf(os.Stderr, "store %s:\n", name)
+		for _, diff := range sdiffs {
+			fmt.Fprintf(os.Stderr, "  %X: recorded %X, replayed %X\n", diff.Key, diff.ValueA, diff.ValueB)
+		}
+	}
+	return nil
+}
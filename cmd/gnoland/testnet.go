@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/bft/config"
+	"github.com/gnolang/gno/pkgs/bft/privval"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/p2p"
+)
+
+const testnetUsage = `usage: gnoland testnet [--v <validators>] [--o <dir>] [<flags>]
+
+Writes the data directories of the validators of a local network to
+<dir>/node0, <dir>/node1, ..., with the same genesis, and with each node
+listening on its own ports and connected to the others as persistent peers.
+The nodes are started with "gnoland --root-dir <dir>/node<i>" from the root
+of the repository, or with "docker compose up" in <dir> with --docker-compose.
+
+flags:
+`
+
+// Runs the testnet command of args, which writes the data directories of
+// the nodes of a local network.
+func runTestnet(args []string) error {
+	return runTestnetCommand(args, os.Stdout)
+}
+
+func runTestnetCommand(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("gnoland testnet", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), testnetUsage)
+		fs.PrintDefaults()
+	}
+	numValidators := fs.Int("v", 4, "number of validators")
+	outputDir := fs.String("o", "./mytestnet", "directory of the data directories of the nodes")
+	startingPort := fs.Int("starting-port", 26656, "p2p port of node0, whose rpc port is the next one; the ports of node<i> are 10*i higher")
+	dockerCompose := fs.Bool("docker-compose", false, "write a docker-compose.yml, with nodes connected by their hostnames")
+	hostnamePrefix := fs.String("hostname-prefix", "node", "prefix of the hostnames of the nodes with --docker-compose")
+	genesisFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	if err := crypto.SetBech32Prefix(flags.bech32Prefix); err != nil {
+		return err
+	}
+	if *numValidators < 1 {
+		return fmt.Errorf("invalid number of validators %d", *numValidators)
+	}
+	if osm.FileExists(*outputDir) {
+		return fmt.Errorf("output directory %s already exists", *outputDir)
+	}
+
+	// write the keys and config of each node.
+	n := *numValidators
+	cfgs := make([]*config.Config, n)
+	peers := make([]string, n)
+	vals := make([]bft.GenesisValidator, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("node%d", i)
+		p2pPort := *startingPort + 10*i
+		rpcPort := p2pPort + 1
+		host, rpcHost := "127.0.0.1", "127.0.0.1"
+		if *dockerCompose {
+			host, rpcHost = fmt.Sprintf("%s%d", *hostnamePrefix, i), "0.0.0.0"
+		}
+
+		cfg := config.DefaultConfig()
+		nodeConfigOptions(cfg)
+		cfg.SetRootDir(filepath.Join(*outputDir, name))
+		cfg.EnsureDirs()
+		cfg.Moniker = name
+		cfg.P2P.ListenAddress = fmt.Sprintf("tcp://0.0.0.0:%d", p2pPort)
+		// nodes share an ip, at least locally.
+		cfg.P2P.AllowDuplicateIP = true
+		cfg.RPC.ListenAddress = fmt.Sprintf("tcp://%s:%d", rpcHost, rpcPort)
+		cfgs[i] = cfg
+
+		nodeKey, err := p2p.LoadOrGenNodeKey(cfg.NodeKeyFile())
+		if err != nil {
+			return err
+		}
+		pv := privval.LoadOrGenFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile())
+		peers[i] = fmt.Sprintf("%s@%s:%d", nodeKey.ID(), host, p2pPort)
+		vals[i] = bft.GenesisValidator{
+			Address: pv.GetAddress(),
+			PubKey:  pv.GetPubKey(),
+			Power:   10,
+			Name:    name,
+		}
+	}
+
+	// write the same genesis, and the peers, to each node.
+	gen := makeGenesisDoc(vals[0].PubKey, examplePackages(), nil)
+	gen.Validators = vals
+	for i, cfg := range cfgs {
+		others := []string{}
+		for j, peer := range peers {
+			if j != i {
+				others = append(others, peer)
+			}
+		}
+		cfg.P2P.PersistentPeers = strings.Join(others, ",")
+		config.WriteConfigFile(config.ConfigFilePath(cfg.RootDir), cfg)
+		writeGenesisFile(gen, cfg.GenesisFile())
+	}
+
+	if *dockerCompose {
+		if err := writeTestnetDockerCompose(*outputDir, cfgs, *hostnamePrefix); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Wrote the %d validators of chain %s to %s:\n", n, gen.ChainID, *outputDir)
+	for i, cfg := range cfgs {
+		fmt.Fprintf(out, "  %s  rpc %s  peer %s\n", cfg.RootDir, cfg.RPC.ListenAddress, peers[i])
+	}
+	return nil
+}
+
+// Writes the docker-compose.yml of the nodes of cfgs to dir, which run the
+// image of the repository, built from the working directory.
+func writeTestnetDockerCompose(dir string, cfgs []*config.Config, hostnamePrefix string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	context, err := filepath.Rel(absDir, wd)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("version: \"3.7\"\nservices:\n")
+	for i, cfg := range cfgs {
+		name := filepath.Base(cfg.RootDir)
+		hostname := fmt.Sprintf("%s%d", hostnamePrefix, i)
+		p2pPort := strings.TrimPrefix(cfg.P2P.ListenAddress, "tcp://0.0.0.0:")
+		rpcPort := strings.TrimPrefix(cfg.RPC.ListenAddress, "tcp://0.0.0.0:")
+		fmt.Fprintf(&sb, "  %s:\n", hostname)
+		fmt.Fprintf(&sb, "    container_name: gnoland-%s\n", hostname)
+		fmt.Fprintf(&sb, "    hostname: %s\n", hostname)
+		fmt.Fprintf(&sb, "    image: gnoland-testnet\n")
+		fmt.Fprintf(&sb, "    build:\n      context: %s\n      dockerfile: Dockerfile\n", filepath.ToSlash(context))
+		fmt.Fprintf(&sb, "    command: [ \"gnoland\", \"--root-dir\", \"/opt/gno/testnet/%s\" ]\n", name)
+		fmt.Fprintf(&sb, "    volumes:\n      - \"./%s:/opt/gno/testnet/%s\"\n", name, name)
+		fmt.Fprintf(&sb, "    ports:\n      - \"%s:%s\"\n      - \"%s:%s\"\n", p2pPort, p2pPort, rpcPort, rpcPort)
+		fmt.Fprintf(&sb, "    networks:\n      - gnotestnet\n")
+		fmt.Fprintf(&sb, "    restart: on-failure\n")
+	}
+	sb.WriteString("\nnetworks:\n  gnotestnet: {}\n")
+	return os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte(sb.String()), 0o644)
+}
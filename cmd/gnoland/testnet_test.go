@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/bft/config"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+)
+
+func TestTestnetCommand(t *testing.T) {
+	// the genesis is made from files of the repository.
+	if _, err := os.Stat("examples"); err != nil {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(filepath.Join("..", "..")))
+		t.Cleanup(func() { os.Chdir(wd) })
+	}
+	dir := filepath.Join(t.TempDir(), "mytestnet")
+	out := new(bytes.Buffer)
+	err := runTestnetCommand([]string{"--v", "3", "--o", dir, "--chainid", "tn", "--docker-compose"}, out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "Wrote the 3 validators of chain tn")
+
+	var gen *bft.GenesisDoc
+	for i, name := range []string{"node0", "node1", "node2"} {
+		cfg, err := config.ReadConfigFile(filepath.Join(dir, name, "config", "config.toml"))
+		require.NoError(t, err)
+		cfg.SetRootDir(filepath.Join(dir, name))
+		require.Equal(t, name, cfg.Moniker)
+		peers := strings.Split(cfg.P2P.PersistentPeers, ",")
+		require.Len(t, peers, 2)
+		for _, peer := range peers {
+			require.NotContains(t, peer, "@"+name+":")
+		}
+
+		gen2, err := bft.GenesisDocFromFile(cfg.GenesisFile())
+		require.NoError(t, err)
+		require.Len(t, gen2.Validators, 3)
+		require.Equal(t, name, gen2.Validators[i].Name)
+		if gen != nil {
+			require.Equal(t, gen.Validators, gen2.Validators)
+		}
+		gen = gen2
+	}
+	compose, err := os.ReadFile(filepath.Join(dir, "docker-compose.yml"))
+	require.NoError(t, err)
+	require.Contains(t, string(compose), "\"26677:26677\"")
+
+	// the output directory is not overwritten.
+	err = runTestnetCommand([]string{"--o", dir}, out)
+	require.Error(t, err)
+}
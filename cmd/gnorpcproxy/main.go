@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/bft/rpc/balancer"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/log"
+)
+
+type proxyOptions struct {
+	Listen         string `flag:"listen" help:"listening address"`
+	Remotes        string `flag:"remotes" help:"comma-separated RPC nodes, e.g. tcp://node1:26657,tcp://node2:26657"`
+	HealthInterval string `flag:"health-interval" help:"interval of the health checks of the nodes, e.g. 5s"`
+}
+
+var defaultProxyOptions = proxyOptions{
+	Listen:         ":26657",
+	Remotes:        "", // must override
+	HealthInterval: "5s",
+}
+
+func main() {
+	cmd := command.NewStdCommand()
+	args := os.Args[1:]
+
+	// show help message.
+	if len(args) > 0 && (args[0] == "help" || args[0] == "--help") {
+		cmd.Println("gnorpcproxy - load-balance RPC requests across gno nodes")
+		cmd.Println("usage: gnorpcproxy --remotes <node1>,<node2> [--listen <addr>] [--health-interval <duration>]")
+		return
+	}
+
+	err := cmd.Run(proxyApp, args, defaultProxyOptions)
+	if err != nil {
+		cmd.ErrPrintfln("%s", err.Error())
+		os.Exit(1)
+	}
+}
+
+func proxyApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(proxyOptions)
+	if opts.Remotes == "" {
+		return errors.New("missing remotes")
+	}
+	healthInterval, err := time.ParseDuration(opts.HealthInterval)
+	if err != nil {
+		return errors.Wrap(err, "parsing health interval")
+	}
+
+	b, err := balancer.NewBalancer(strings.Split(opts.Remotes, ","), healthInterval)
+	if err != nil {
+		return err
+	}
+	b.SetLogger(log.NewTMLogger(log.NewSyncWriter(os.Stdout)))
+	if err := b.Start(); err != nil {
+		return err
+	}
+	defer b.Stop()
+
+	cmd.Printfln("Listening on %s", opts.Listen)
+	return http.ListenAndServe(opts.Listen, b)
+}
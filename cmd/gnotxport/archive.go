@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/log"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/txarchive"
+)
+
+type txArchiveOptions struct {
+	Remote   string `flag:"remote" help:"Remote RPC addr:port"`
+	OutFile  string `flag:"out" help:"Archive file path, resumed if it exists"`
+	Interval string `flag:"interval" help:"Interval of polling for new blocks, e.g. 5s"`
+}
+
+var defaultTxArchiveOptions = txArchiveOptions{
+	Remote:   "localhost:26657",
+	OutFile:  "txarchive.jsonl",
+	Interval: "5s",
+}
+
+func txArchiveApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(txArchiveOptions)
+	interval, err := time.ParseDuration(opts.Interval)
+	if err != nil {
+		return errors.Wrap(err, "parsing interval")
+	}
+	c := client.NewHTTP(opts.Remote, "/websocket")
+	a, err := txarchive.NewArchiver(c, opts.OutFile, interval)
+	if err != nil {
+		return err
+	}
+	a.SetLogger(log.NewTMLogger(log.NewSyncWriter(os.Stdout)))
+	if err := a.Start(); err != nil {
+		return err
+	}
+	osm.TrapSignal(func() {
+		a.Stop()
+	})
+	select {} // run forever
+}
+
+type txReplayOptions struct {
+	Remote string `flag:"remote" help:"Remote RPC addr:port"`
+	InFile string `flag:"in" help:"Archive file path"`
+}
+
+var defaultTxReplayOptions = txReplayOptions{
+	Remote: "localhost:26657",
+	InFile: "txarchive.jsonl",
+}
+
+func txReplayApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(txReplayOptions)
+	c := client.NewHTTP(opts.Remote, "/websocket")
+	in, err := os.Open(opts.InFile)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	replayed, mismatches, err := txarchive.Replay(c, txarchive.NewReader(in))
+	cmd.Printfln("replayed %d txs", replayed)
+	for _, rec := range mismatches {
+		cmd.Printfln("tx %d of height %d: originally failed %v, not on replay", rec.Index, rec.Height, rec.Failed)
+	}
+	return err
+}
+
+type txGenesisOptions struct {
+	InFile  string `flag:"in" help:"Archive file path"`
+	OutFile string `flag:"out" help:"Genesis txs file path, for gnoland --genesis-txs-file"`
+}
+
+var defaultTxGenesisOptions = txGenesisOptions{
+	InFile:  "txarchive.jsonl",
+	OutFile: "genesis_txs.txt",
+}
+
+func txGenesisApp(cmd *command.Command, args []string, iopts interface{}) error {
+	opts := iopts.(txGenesisOptions)
+	in, err := os.Open(opts.InFile)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	txs, err := txarchive.GenesisTxs(txarchive.NewReader(in))
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(opts.OutFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w := bufio.NewWriter(out)
+	for _, tx := range txs {
+		w.Write(amino.MustMarshalJSON(tx))
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	cmd.Printfln("wrote %d genesis txs to %s", len(txs), opts.OutFile)
+	return nil
+}
@@ -15,6 +15,9 @@ type (
 var mainApps AppList = []AppItem{
 	{txExportApp, "export", "export txs from node", defaultTxExportOptions},
 	{txImportApp, "import", "import txs to node", defaultTxImportOptions},
+	{txArchiveApp, "archive", "archive committed txs of node", defaultTxArchiveOptions},
+	{txReplayApp, "replay", "replay archived txs to node", defaultTxReplayOptions},
+	{txGenesisApp, "genesis", "write archived txs as genesis txs", defaultTxGenesisOptions},
 }
 
 func main() {
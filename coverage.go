@@ -0,0 +1,233 @@
+package gno
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Coverage counts the statements executed by machines, for the files of
+// the packages added to it. Statements are counted per line: a line is
+// covered if any statement starting on it was executed.
+//
+// NOTE: function bodies are not compiled to bytecode while coverage is
+// recorded, as bytecode does not execute statements one by one.
+type Coverage struct {
+	files map[string]*coverageFile // by package path and file name.
+}
+
+type coverageFile struct {
+	pkgPath string
+	name    string
+	lines   []string       // source lines.
+	stmts   map[int]int    // number of statements by line.
+	counts  map[int]int64  // executions by line.
+	funcs   []coverageFunc // in order of declaration.
+}
+
+type coverageFunc struct {
+	name  string
+	line  int
+	lines []int // lines with statements.
+}
+
+func NewCoverage() *Coverage {
+	return &Coverage{
+		files: make(map[string]*coverageFile),
+	}
+}
+
+// AddMemPackage adds the non-test files of memPkg, whose statements are
+// then counted by machines with this coverage.
+func (c *Coverage) AddMemPackage(memPkg *std.MemPackage) {
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		fn, err := ParseFile(mfile.Name, mfile.Body)
+		if err != nil {
+			panic(fmt.Sprintf("parsing file %s: %v", mfile.Name, err))
+		}
+		cf := &coverageFile{
+			pkgPath: memPkg.Path,
+			name:    mfile.Name,
+			lines:   strings.Split(mfile.Body, "\n"),
+			stmts:   make(map[int]int),
+			counts:  make(map[int]int64),
+		}
+		for _, decl := range fn.Decls {
+			if fd, ok := decl.(*FuncDecl); ok {
+				cf.addFunc(fd)
+			}
+		}
+		c.files[memPkg.Path+"/"+mfile.Name] = cf
+	}
+}
+
+func (cf *coverageFile) addFunc(fd *FuncDecl) {
+	name := string(fd.Name)
+	if fd.IsMethod {
+		name = fmt.Sprintf("%s.%s", fd.Recv.Type.String(), fd.Name)
+	}
+	cfn := coverageFunc{name: name, line: fd.GetLine()}
+	seen := make(map[int]bool)
+	Transcribe(fd, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		switch n.(type) {
+		case *IfCaseStmt, *SwitchClauseStmt, *SelectCaseStmt, *EmptyStmt:
+			// not executed as statements.
+		case Stmt:
+			line := n.GetLine()
+			if line == 0 {
+				break
+			}
+			cf.stmts[line]++
+			if !seen[line] {
+				seen[line] = true
+				cfn.lines = append(cfn.lines, line)
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+	cf.funcs = append(cf.funcs, cfn)
+}
+
+// Counts the execution of statement s in the last block of m.
+func (c *Coverage) recordStmt(m *Machine, s Stmt) {
+	line := s.GetLine()
+	if line == 0 {
+		return // e.g. injected return statement.
+	}
+	b := m.LastBlock()
+	if b.Source == nil {
+		return
+	}
+	loc := b.Source.GetLocation()
+	cf, ok := c.files[loc.PkgPath+"/"+loc.File]
+	if !ok {
+		return
+	}
+	if _, ok := cf.stmts[line]; ok {
+		cf.counts[line]++
+	}
+}
+
+// Returns the files in order of package path and name.
+func (c *Coverage) sortedFiles() []*coverageFile {
+	cfs := make([]*coverageFile, 0, len(c.files))
+	for _, cf := range c.files {
+		cfs = append(cfs, cf)
+	}
+	sort.Slice(cfs, func(i, j int) bool {
+		if cfs[i].pkgPath != cfs[j].pkgPath {
+			return cfs[i].pkgPath < cfs[j].pkgPath
+		}
+		return cfs[i].name < cfs[j].name
+	})
+	return cfs
+}
+
+// Returns the number of covered and total statements on lines.
+func (cf *coverageFile) tally(lines []int) (covered, total int) {
+	for _, line := range lines {
+		total += cf.stmts[line]
+		if cf.counts[line] > 0 {
+			covered += cf.stmts[line]
+		}
+	}
+	return
+}
+
+func (cf *coverageFile) sortedLines() []int {
+	lines := make([]int, 0, len(cf.stmts))
+	for line := range cf.stmts {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+func (cf *coverageFile) path() string {
+	return path.Join(cf.pkgPath, cf.name)
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(covered) / float64(total)
+}
+
+// Percent returns the percentage of statements of package pkgPath that
+// were executed.
+func (c *Coverage) Percent(pkgPath string) float64 {
+	covered, total := 0, 0
+	for _, cf := range c.files {
+		if cf.pkgPath == pkgPath {
+			cov, tot := cf.tally(cf.sortedLines())
+			covered += cov
+			total += tot
+		}
+	}
+	return percent(covered, total)
+}
+
+// WriteProfile writes the counts in the format of Go's coverprofile, as
+// read by go tool cover. Each line with statements is a block spanning
+// that line.
+func (c *Coverage) WriteProfile(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "mode: count"); err != nil {
+		return err
+	}
+	for _, cf := range c.sortedFiles() {
+		for _, line := range cf.sortedLines() {
+			src := ""
+			if line <= len(cf.lines) {
+				src = cf.lines[line-1]
+			}
+			start := len(src) - len(strings.TrimLeft(src, " \t")) + 1
+			_, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d %d %d\n",
+				cf.path(), line, start, line, len(src)+1,
+				cf.stmts[line], cf.counts[line])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteFuncReport writes the percentage of statements covered for each
+// function and file of package pkgPath, or of all packages if pkgPath is
+// empty, like go tool cover -func.
+func (c *Coverage) WriteFuncReport(w io.Writer, pkgPath string) error {
+	var lines []string
+	covered, total := 0, 0
+	for _, cf := range c.sortedFiles() {
+		if pkgPath != "" && cf.pkgPath != pkgPath {
+			continue
+		}
+		for _, cfn := range cf.funcs {
+			cov, tot := cf.tally(cfn.lines)
+			lines = append(lines, fmt.Sprintf("%s:%d:\t%s\t%.1f%%",
+				cf.path(), cfn.line, cfn.name, percent(cov, tot)))
+		}
+		cov, tot := cf.tally(cf.sortedLines())
+		lines = append(lines, fmt.Sprintf("%s:\t(file)\t%.1f%%",
+			cf.path(), percent(cov, tot)))
+		covered += cov
+		total += tot
+	}
+	lines = append(lines, fmt.Sprintf("total:\t(statements)\t%.1f%%",
+		percent(covered, total)))
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
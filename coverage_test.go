@@ -0,0 +1,69 @@
+package gno
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+)
+
+const coverageSource = `package cov
+
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func Unused() {
+	println("unused")
+}
+`
+
+func TestCoverage(t *testing.T) {
+	memPkg := &std.MemPackage{
+		Name: "cov",
+		Path: "gno.land/p/cov",
+		Files: []*std.MemFile{
+			{Name: "cov.gno", Body: coverageSource},
+		},
+	}
+	cov := NewCoverage()
+	cov.AddMemPackage(memPkg)
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:  "gno.land/p/cov",
+		Output:   new(bytes.Buffer),
+		Coverage: cov,
+	})
+	m.RunMemPackage(memPkg, false)
+	m.Eval(Call("Abs", "3"))
+	m.Eval(Call("Abs", "4"))
+
+	// Abs is counted although eligible for bytecode; neither its negative
+	// branch nor Unused were run.
+	assert.Equal(t, cov.Percent("gno.land/p/cov"), 50.0)
+
+	var profile bytes.Buffer
+	assert.Nil(t, cov.WriteProfile(&profile))
+	assert.Equal(t, profile.String(), strings.Join([]string{
+		"mode: count",
+		"gno.land/p/cov/cov.gno:4.2,4.12 1 2",
+		"gno.land/p/cov/cov.gno:5.3,5.12 1 0",
+		"gno.land/p/cov/cov.gno:7.2,7.10 1 2",
+		"gno.land/p/cov/cov.gno:11.2,11.19 1 0",
+		"",
+	}, "\n"))
+
+	var report bytes.Buffer
+	assert.Nil(t, cov.WriteFuncReport(&report, ""))
+	assert.Equal(t, report.String(), strings.Join([]string{
+		"gno.land/p/cov/cov.gno:3:\tAbs\t66.7%",
+		"gno.land/p/cov/cov.gno:10:\tUnused\t0.0%",
+		"gno.land/p/cov/cov.gno:\t(file)\t50.0%",
+		"total:\t(statements)\t50.0%",
+		"",
+	}, "\n"))
+}
@@ -0,0 +1,339 @@
+package gno
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// ErrDebuggerQuit is panicked by a machine when the user quits its
+// debugger.
+var ErrDebuggerQuit = errors.New("debugger: quit")
+
+type debugMode int
+
+const (
+	debugContinue debugMode = iota // stop at breakpoints.
+	debugStep                      // stop at the next statement.
+	debugNext                      // stop at the next statement of the frame.
+)
+
+// Debugger is an interactive debugger for a machine, which reads commands
+// from in and writes to out. The machine stops before running statements
+// at breakpoints (by file and line), or when stepping; see debugHelp for
+// commands. A new debugger stops at the first statement.
+type Debugger struct {
+	in          *bufio.Reader
+	out         io.Writer
+	mode        debugMode
+	breakpoints []Location // by File and Line.
+	sources     map[string][]string
+
+	// location and depth of the last stop, which are skipped until another
+	// statement is run.
+	lastLoc   Location
+	lastDepth int
+	skipping  bool
+}
+
+func NewDebugger(in io.Reader, out io.Writer) *Debugger {
+	return &Debugger{
+		in:      bufio.NewReader(in),
+		out:     out,
+		mode:    debugStep,
+		sources: make(map[string][]string),
+	}
+}
+
+// AddMemPackage adds the source files of memPkg, to be listed when
+// stopped.
+func (d *Debugger) AddMemPackage(memPkg *std.MemPackage) {
+	for _, mfile := range memPkg.Files {
+		d.sources[memPkg.Path+"/"+mfile.Name] = strings.Split(mfile.Body, "\n")
+	}
+}
+
+// Step makes the machine stop at the next statement.
+func (d *Debugger) Step() {
+	d.mode = debugStep
+}
+
+const debugHelp = `commands:
+  break|b [file:]line   set a breakpoint
+  clear [file:]line     clear a breakpoint
+  breakpoints|bp        list breakpoints
+  continue|c            run until a breakpoint
+  step|s                run until the next statement
+  next|n                run until the next statement of this frame
+  stack|bt              print the stack frames
+  locals|l              print the local variables
+  print|p name          print a variable
+  list                  print the source around the current line
+  quit|q                abort the machine
+`
+
+// Called before the machine executes statement s.
+func (d *Debugger) onStmt(m *Machine, s Stmt) {
+	line := s.GetLine()
+	if line == 0 {
+		return // e.g. injected return statement.
+	}
+	b := m.LastBlock()
+	if b.Source == nil {
+		return
+	}
+	loc := b.Source.GetLocation()
+	loc.Line = line
+	depth := numCallFrames(m)
+	if d.skipping {
+		if loc == d.lastLoc && depth == d.lastDepth {
+			return
+		}
+		d.skipping = false
+	}
+	stop := false
+	switch d.mode {
+	case debugStep:
+		stop = true
+	case debugNext:
+		stop = depth <= d.lastDepth
+	}
+	if !stop && d.isBreakpoint(loc) {
+		stop = true
+		fmt.Fprintf(d.out, "breakpoint at %s:%d\n", loc.File, loc.Line)
+	}
+	if !stop {
+		return
+	}
+	d.lastLoc, d.lastDepth, d.skipping = loc, depth, true
+	d.printLocation(m, loc)
+	d.prompt(m, loc)
+}
+
+// Reads and runs commands until the machine is resumed.
+func (d *Debugger) prompt(m *Machine, loc Location) {
+	for {
+		fmt.Fprint(d.out, "(gnodebug) ")
+		input, err := d.in.ReadString('\n')
+		if err != nil && input == "" {
+			// no more input: run to completion.
+			d.mode = debugContinue
+			d.breakpoints = nil
+			return
+		}
+		fields := strings.Fields(input)
+		if len(fields) == 0 {
+			continue
+		}
+		cmd, args := fields[0], fields[1:]
+		switch cmd {
+		case "break", "b":
+			if bp, ok := d.parseBreakpoint(args, loc); ok {
+				if !d.isBreakpoint(bp) {
+					d.breakpoints = append(d.breakpoints, bp)
+				}
+				fmt.Fprintf(d.out, "breakpoint set at %s:%d\n", bp.File, bp.Line)
+			}
+		case "clear":
+			if bp, ok := d.parseBreakpoint(args, loc); ok {
+				for i, bp2 := range d.breakpoints {
+					if bp2 == bp {
+						d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+						break
+					}
+				}
+			}
+		case "breakpoints", "bp":
+			for _, bp := range d.breakpoints {
+				fmt.Fprintf(d.out, "%s:%d\n", bp.File, bp.Line)
+			}
+		case "continue", "c":
+			d.mode = debugContinue
+			return
+		case "step", "s":
+			d.mode = debugStep
+			return
+		case "next", "n":
+			d.mode = debugNext
+			return
+		case "stack", "bt":
+			d.printStack(m, loc)
+		case "locals", "l":
+			d.printLocals(m)
+		case "print", "p":
+			if len(args) != 1 {
+				fmt.Fprintln(d.out, "usage: print name")
+				continue
+			}
+			if tv, ok := lookupDebugName(m, Name(args[0])); ok {
+				fmt.Fprintf(d.out, "%s = %s\n", args[0], tv.String())
+			} else {
+				fmt.Fprintf(d.out, "undefined: %s\n", args[0])
+			}
+		case "list":
+			d.printSource(loc, 5)
+		case "quit", "q":
+			panic(ErrDebuggerQuit)
+		case "help", "h":
+			fmt.Fprint(d.out, debugHelp)
+		default:
+			fmt.Fprintf(d.out, "unknown command %q; type help for commands\n", cmd)
+		}
+	}
+}
+
+func (d *Debugger) parseBreakpoint(args []string, loc Location) (Location, bool) {
+	if len(args) != 1 {
+		fmt.Fprintln(d.out, "usage: break [file:]line")
+		return Location{}, false
+	}
+	file, sline := loc.File, args[0]
+	if i := strings.LastIndex(sline, ":"); i >= 0 {
+		file, sline = sline[:i], sline[i+1:]
+	}
+	line, err := strconv.Atoi(sline)
+	if err != nil || line <= 0 {
+		fmt.Fprintf(d.out, "invalid line %q\n", sline)
+		return Location{}, false
+	}
+	return Location{File: file, Line: line}, true
+}
+
+func (d *Debugger) isBreakpoint(loc Location) bool {
+	for _, bp := range d.breakpoints {
+		if bp.File == loc.File && bp.Line == loc.Line {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Debugger) printLocation(m *Machine, loc Location) {
+	fname := "?"
+	if fr := lastCallFrame(m); fr != nil {
+		fname = debugFuncName(fr.Func)
+	}
+	fmt.Fprintf(d.out, "> %s() %s:%d\n", fname, loc.File, loc.Line)
+	d.printSource(loc, 0)
+}
+
+// Prints the source lines around loc, within radius.
+func (d *Debugger) printSource(loc Location, radius int) {
+	lines, ok := d.sources[loc.PkgPath+"/"+loc.File]
+	if !ok {
+		return
+	}
+	for l := loc.Line - radius; l <= loc.Line+radius; l++ {
+		if l < 1 || l > len(lines) {
+			continue
+		}
+		marker := " "
+		if l == loc.Line {
+			marker = "=>"
+		}
+		fmt.Fprintf(d.out, "%2s %4d: %s\n", marker, l, lines[l-1])
+	}
+}
+
+// Prints the call frames, innermost first, with the location of their
+// current statement or call.
+func (d *Debugger) printStack(m *Machine, loc Location) {
+	i := 0
+	for fi := len(m.Frames) - 1; fi >= 0; fi-- {
+		fr := &m.Frames[fi]
+		if fr.Func == nil {
+			continue
+		}
+		fmt.Fprintf(d.out, "#%d %s() %s:%d\n", i, debugFuncName(fr.Func), loc.File, loc.Line)
+		i++
+		// the location of the call, in the caller.
+		if fr.NumBlocks > 0 {
+			loc = m.Blocks[fr.NumBlocks-1].Source.GetLocation()
+			loc.Line = fr.Source.GetLine()
+		}
+	}
+}
+
+// Prints the variables of the blocks of the current function.
+func (d *Debugger) printLocals(m *Machine) {
+	var lines []string
+	seen := make(map[Name]bool)
+	for b := m.LastBlock(); b != nil; b = b.GetParent(m.Store) {
+		if b.Source == nil {
+			break
+		}
+		src := b.GetSource(m.Store)
+		if _, ok := src.(*PackageNode); ok {
+			break
+		}
+		if _, ok := src.(*FileNode); ok {
+			break
+		}
+		for i, n := range src.GetBlockNames() {
+			if i >= len(b.Values) || seen[n] || isHiddenDebugName(n) {
+				continue
+			}
+			seen[n] = true
+			lines = append(lines, fmt.Sprintf("%s = %s", n, b.Values[i].String()))
+		}
+		if _, ok := src.(*FuncDecl); ok {
+			break // outer blocks are not local.
+		}
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		fmt.Fprintln(d.out, line)
+	}
+}
+
+// Returns the value of the variable named n, in the current or an outer
+// block.
+func lookupDebugName(m *Machine, n Name) (TypedValue, bool) {
+	for b := m.LastBlock(); b != nil; b = b.GetParent(m.Store) {
+		if b.Source == nil {
+			continue
+		}
+		for i, bn := range b.GetSource(m.Store).GetBlockNames() {
+			if bn == n && i < len(b.Values) {
+				return b.Values[i], true
+			}
+		}
+	}
+	return TypedValue{}, false
+}
+
+func isHiddenDebugName(n Name) bool {
+	return n == "" || n == "_" || strings.HasPrefix(string(n), ".")
+}
+
+func debugFuncName(fv *FuncValue) string {
+	if fv.PkgPath == "" {
+		return string(fv.Name)
+	}
+	return fmt.Sprintf("%s.%s", fv.PkgPath, fv.Name)
+}
+
+func numCallFrames(m *Machine) int {
+	n := 0
+	for _, fr := range m.Frames {
+		if fr.Func != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func lastCallFrame(m *Machine) *Frame {
+	for fi := len(m.Frames) - 1; fi >= 0; fi-- {
+		if m.Frames[fi].Func != nil {
+			return &m.Frames[fi]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package gno
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+)
+
+const debuggerSource = `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func main() {
+	total := 0
+	for i := 0; i < 3; i++ {
+		total += fib(i)
+	}
+	println(total)
+}
+`
+
+// Returns the output of the debugger with commands, and a function to
+// run main with it.
+func newDebuggerRun(commands ...string) (*bytes.Buffer, func()) {
+	memPkg := &std.MemPackage{
+		Name:  "main",
+		Path:  "main",
+		Files: []*std.MemFile{{Name: "main.gno", Body: debuggerSource}},
+	}
+	in := strings.NewReader(strings.Join(commands, "\n") + "\n")
+	out := new(bytes.Buffer)
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:  "main",
+		Output:   new(bytes.Buffer),
+		Debugger: NewDebugger(in, out),
+	})
+	m.Debugger.AddMemPackage(memPkg)
+	return out, func() {
+		m.RunMemPackage(memPkg, false)
+		m.RunStatement(S(Call(X("main"))))
+	}
+}
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	out, run := newDebuggerRun("break 5", "continue", "stack", "locals", "clear 5", "continue")
+	run()
+	assert.Equal(t, out.String(), strings.Join([]string{
+		"> main.main() main.gno:11",
+		"=>   11: \ttotal := 0",
+		"(gnodebug) breakpoint set at main.gno:5",
+		"(gnodebug) breakpoint at main.gno:5",
+		"> main.fib() main.gno:5",
+		"=>    5: \t\treturn n",
+		"(gnodebug) #0 main.fib() main.gno:5",
+		"#1 main.main() main.gno:13",
+		"(gnodebug) n = (0 int)",
+		"(gnodebug) (gnodebug) ",
+	}, "\n"))
+}
+
+func TestDebuggerStepping(t *testing.T) {
+	out, run := newDebuggerRun("next", "next", "next", "print total", "step", "step", "print n", "quit")
+	assert.PanicsWithValue(t, run, ErrDebuggerQuit)
+	assert.Equal(t, out.String(), strings.Join([]string{
+		"> main.main() main.gno:11",
+		"=>   11: \ttotal := 0",
+		"(gnodebug) > main.main() main.gno:12",
+		"=>   12: \tfor i := 0; i < 3; i++ {",
+		"(gnodebug) > main.main() main.gno:13",
+		"=>   13: \t\ttotal += fib(i)",
+		"(gnodebug) > main.main() main.gno:12",
+		"=>   12: \tfor i := 0; i < 3; i++ {",
+		"(gnodebug) total = (0 int)",
+		"(gnodebug) > main.main() main.gno:13",
+		"=>   13: \t\ttotal += fib(i)",
+		"(gnodebug) > main.fib() main.gno:4",
+		"=>    4: \tif n < 2 {",
+		"(gnodebug) n = (1 int)",
+		"(gnodebug) ",
+	}, "\n"))
+}
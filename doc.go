@@ -0,0 +1,272 @@
+package gno
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// PackageDoc is the documentation of the exported symbols of a package,
+// as extracted from its doc comments and declarations.
+type PackageDoc struct {
+	Name   string     `json:"name"`
+	Path   string     `json:"path"`
+	Doc    string     `json:"doc"`
+	Consts []ValueDoc `json:"consts,omitempty"`
+	Vars   []ValueDoc `json:"vars,omitempty"`
+	Funcs  []FuncDoc  `json:"funcs,omitempty"`
+	Types  []TypeDoc  `json:"types,omitempty"`
+}
+
+// ValueDoc documents a declaration of constants or variables.
+type ValueDoc struct {
+	Names []string `json:"names"`
+	Decl  string   `json:"decl"`
+	Doc   string   `json:"doc"`
+}
+
+// FuncDoc documents a function or method.
+type FuncDoc struct {
+	Name      string `json:"name"`
+	Recv      string `json:"recv,omitempty"` // receiver type, for methods.
+	Signature string `json:"signature"`
+	Doc       string `json:"doc"`
+}
+
+// TypeDoc documents a type, with the constants, variables and functions
+// (constructors) of that type, and its methods.
+type TypeDoc struct {
+	Name    string     `json:"name"`
+	Decl    string     `json:"decl"`
+	Doc     string     `json:"doc"`
+	Consts  []ValueDoc `json:"consts,omitempty"`
+	Vars    []ValueDoc `json:"vars,omitempty"`
+	Funcs   []FuncDoc  `json:"funcs,omitempty"`
+	Methods []FuncDoc  `json:"methods,omitempty"`
+}
+
+// NewPackageDoc extracts the documentation of the non-test files of
+// memPkg.
+func NewPackageDoc(memPkg *std.MemPackage) (*PackageDoc, error) {
+	fset := token.NewFileSet()
+	files := make(map[string]*ast.File)
+	pkgName := ""
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, mfile.Name, mfile.Body, parser.ParseComments)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing file "+mfile.Name)
+		}
+		files[mfile.Name] = f
+		pkgName = f.Name.Name
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no gno files in package %s", memPkg.Path)
+	}
+	// NOTE: not doc.NewFromFiles(), which requires .go file names.
+	dpkg := doc.New(&ast.Package{Name: pkgName, Files: files}, memPkg.Path, 0)
+	pd := &PackageDoc{
+		Name:   dpkg.Name,
+		Path:   memPkg.Path,
+		Doc:    dpkg.Doc,
+		Consts: valueDocs(fset, dpkg.Consts),
+		Vars:   valueDocs(fset, dpkg.Vars),
+		Funcs:  funcDocs(fset, dpkg.Funcs),
+	}
+	for _, dtype := range dpkg.Types {
+		pd.Types = append(pd.Types, TypeDoc{
+			Name:    dtype.Name,
+			Decl:    formatDocNode(fset, dtype.Decl),
+			Doc:     dtype.Doc,
+			Consts:  valueDocs(fset, dtype.Consts),
+			Vars:    valueDocs(fset, dtype.Vars),
+			Funcs:   funcDocs(fset, dtype.Funcs),
+			Methods: funcDocs(fset, dtype.Methods),
+		})
+	}
+	return pd, nil
+}
+
+func valueDocs(fset *token.FileSet, values []*doc.Value) []ValueDoc {
+	var vds []ValueDoc
+	for _, value := range values {
+		vds = append(vds, ValueDoc{
+			Names: value.Names,
+			Decl:  formatDocNode(fset, value.Decl),
+			Doc:   value.Doc,
+		})
+	}
+	return vds
+}
+
+func funcDocs(fset *token.FileSet, funcs []*doc.Func) []FuncDoc {
+	var fds []FuncDoc
+	for _, fn := range funcs {
+		decl := *fn.Decl
+		decl.Body = nil // signature only.
+		decl.Doc = nil
+		fds = append(fds, FuncDoc{
+			Name:      fn.Name,
+			Recv:      fn.Recv,
+			Signature: formatDocNode(fset, &decl),
+			Doc:       fn.Doc,
+		})
+	}
+	return fds
+}
+
+func formatDocNode(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, fset, node); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+// Symbol returns the documentation of the symbol named sym, in the form
+// of a package documentation with only that symbol, or nil if there is no
+// such symbol. Methods are named Type.Method.
+func (pd *PackageDoc) Symbol(sym string) *PackageDoc {
+	res := &PackageDoc{Name: pd.Name, Path: pd.Path}
+	if i := strings.Index(sym, "."); i >= 0 {
+		tname, mname := sym[:i], sym[i+1:]
+		for _, td := range pd.Types {
+			if td.Name != tname {
+				continue
+			}
+			for _, md := range td.Methods {
+				if md.Name == mname {
+					res.Types = []TypeDoc{{Name: td.Name, Decl: td.Decl, Methods: []FuncDoc{md}}}
+					return res
+				}
+			}
+		}
+		return nil
+	}
+	for _, vd := range pd.Consts {
+		if containsString(vd.Names, sym) {
+			res.Consts = []ValueDoc{vd}
+			return res
+		}
+	}
+	for _, vd := range pd.Vars {
+		if containsString(vd.Names, sym) {
+			res.Vars = []ValueDoc{vd}
+			return res
+		}
+	}
+	for _, fd := range pd.Funcs {
+		if fd.Name == sym {
+			res.Funcs = []FuncDoc{fd}
+			return res
+		}
+	}
+	for _, td := range pd.Types {
+		if td.Name == sym {
+			res.Types = []TypeDoc{td}
+			return res
+		}
+		for _, fd := range td.Funcs {
+			if fd.Name == sym {
+				res.Funcs = []FuncDoc{fd}
+				return res
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, s2 := range ss {
+		if s2 == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the documentation as text, in the style of go doc.
+func (pd *PackageDoc) WriteText(w io.Writer) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %s // import %q\n\n", pd.Name, pd.Path)
+	writeDocComment(&buf, pd.Doc, "")
+	writeValueDocs(&buf, "CONSTANTS", pd.Consts)
+	writeValueDocs(&buf, "VARIABLES", pd.Vars)
+	if len(pd.Funcs) > 0 {
+		buf.WriteString("FUNCTIONS\n\n")
+		for _, fd := range pd.Funcs {
+			writeFuncDoc(&buf, fd)
+		}
+	}
+	if len(pd.Types) > 0 {
+		buf.WriteString("TYPES\n\n")
+		for _, td := range pd.Types {
+			buf.WriteString(td.Decl + "\n")
+			writeDocComment(&buf, td.Doc, "    ")
+			if td.Doc == "" {
+				buf.WriteString("\n")
+			}
+			for _, vd := range append(td.Consts, td.Vars...) {
+				buf.WriteString(vd.Decl + "\n")
+				writeDocComment(&buf, vd.Doc, "    ")
+			}
+			for _, fd := range append(td.Funcs, td.Methods...) {
+				writeFuncDoc(&buf, fd)
+			}
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeValueDocs(buf *bytes.Buffer, title string, vds []ValueDoc) {
+	if len(vds) == 0 {
+		return
+	}
+	buf.WriteString(title + "\n\n")
+	for _, vd := range vds {
+		buf.WriteString(vd.Decl + "\n")
+		writeDocComment(buf, vd.Doc, "    ")
+		if vd.Doc == "" {
+			buf.WriteString("\n")
+		}
+	}
+}
+
+func writeFuncDoc(buf *bytes.Buffer, fd FuncDoc) {
+	buf.WriteString(fd.Signature + "\n")
+	writeDocComment(buf, fd.Doc, "    ")
+	if fd.Doc == "" {
+		buf.WriteString("\n")
+	}
+}
+
+// Writes the lines of the doc comment text with indent, followed by an
+// empty line, or nothing if text is empty.
+func writeDocComment(buf *bytes.Buffer, text string, indent string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if line == "" {
+			buf.WriteString("\n")
+		} else {
+			buf.WriteString(indent + line + "\n")
+		}
+	}
+	buf.WriteString("\n")
+}
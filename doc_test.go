@@ -0,0 +1,89 @@
+package gno
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+)
+
+const docSource = `// Package shapes computes areas.
+package shapes
+
+// Pi is an approximation of pi.
+const Pi = 3
+
+// Count is the number of shapes made.
+var Count int
+
+// Square is a square.
+type Square struct {
+	Side int
+}
+
+// NewSquare returns a square with side s.
+func NewSquare(s int) *Square {
+	Count++
+	return &Square{Side: s}
+}
+
+// Area returns the area of sq.
+func (sq *Square) Area() int {
+	return sq.Side * sq.Side
+}
+
+// Double returns twice x.
+func Double(x int) int {
+	return 2 * x
+}
+
+func unexported() {}
+`
+
+func TestPackageDoc(t *testing.T) {
+	memPkg := &std.MemPackage{
+		Name: "shapes",
+		Path: "gno.land/p/shapes",
+		Files: []*std.MemFile{
+			{Name: "shapes.gno", Body: docSource},
+			{Name: "shapes_test.gno", Body: "package shapes\n\nfunc TestX() {}\n"},
+		},
+	}
+	pd, err := NewPackageDoc(memPkg)
+	assert.NoError(t, err)
+	assert.Equal(t, pd.Name, "shapes")
+	assert.Equal(t, pd.Doc, "Package shapes computes areas.\n")
+	assert.Equal(t, len(pd.Consts), 1)
+	assert.Equal(t, len(pd.Vars), 1)
+	assert.Equal(t, len(pd.Funcs), 1)
+	assert.Equal(t, pd.Funcs[0].Signature, "func Double(x int) int")
+	assert.Equal(t, len(pd.Types), 1)
+	assert.Equal(t, pd.Types[0].Funcs[0].Name, "NewSquare")
+	assert.Equal(t, pd.Types[0].Methods[0].Doc, "Area returns the area of sq.\n")
+
+	spd := pd.Symbol("Square.Area")
+	assert.NotNil(t, spd)
+	assert.Equal(t, spd.Types[0].Methods[0].Signature, "func (sq *Square) Area() int")
+	spd = pd.Symbol("NewSquare")
+	assert.NotNil(t, spd)
+	assert.Equal(t, spd.Funcs[0].Name, "NewSquare")
+	assert.Nil(t, pd.Symbol("unexported"))
+	assert.Nil(t, pd.Symbol("Square.Perimeter"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, pd.Symbol("Pi").WriteText(&buf))
+	assert.Equal(t, buf.String(), `package shapes // import "gno.land/p/shapes"
+
+CONSTANTS
+
+const Pi = 3
+    Pi is an approximation of pi.
+
+`)
+}
+
+func TestPackageDocNoFiles(t *testing.T) {
+	_, err := NewPackageDoc(&std.MemPackage{Name: "empty", Path: "gno.land/p/empty"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package gno
+
+import (
+	"fmt"
+)
+
+// Floating-point arithmetic may differ across platforms, e.g. with fused
+// multiply-adds, which would break consensus between nodes of a chain.
+// Chains may thus reject packages which use floating-point types, with
+// CheckNoFloats.  Untyped constants are exact, and may be used.
+
+// CheckNoFloats is a FileCheck which returns an error at the first use in
+// fn of a floating-point type, or of a type composed of one, e.g. in a
+// declaration, conversion, constant or call result.
+func CheckNoFloats(fn *FileNode) (err error) {
+	Transcribe(fn, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		var t Type
+		switch cn := n.(type) {
+		case *constTypeExpr:
+			t = cn.Type
+		case *ConstExpr:
+			if tv, ok := cn.V.(TypeValue); ok {
+				t = tv.Type
+			} else {
+				t = cn.T
+			}
+		case Expr:
+			t, _ = cn.GetAttribute(ATTR_TYPEOF_VALUE).(Type)
+		}
+		if t != nil && hasFloatType(t, make(map[Type]bool)) {
+			// nodes made by the preprocessor, and declarations, have
+			// no line.
+			line := firstLine(n)
+			for i := len(ns) - 1; line == 0 && i >= 0; i-- {
+				if _, ok := ns[i].(*FileNode); !ok {
+					line = firstLine(ns[i])
+				}
+			}
+			err = fmt.Errorf("%s:%d: floating-point type %s not allowed",
+				fn.Name, line, t.String())
+			return n, TRANS_EXIT
+		}
+		return n, TRANS_CONTINUE
+	})
+	return err
+}
+
+// Returns the line of n, or else of the first node in n with a line.
+func firstLine(n Node) (line int) {
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		line = n.GetLine()
+		if line == 0 {
+			switch cx := n.(type) {
+			case *constTypeExpr:
+				if cx.Source != nil {
+					line = firstLine(cx.Source)
+				}
+			case *ConstExpr:
+				if cx.Source != nil {
+					line = firstLine(cx.Source)
+				}
+			}
+		}
+		if line > 0 {
+			return n, TRANS_EXIT
+		}
+		return n, TRANS_CONTINUE
+	})
+	return line
+}
+
+// Returns true if t is a floating-point type, or is composed of one.  The
+// methods of types are not considered.
+func hasFloatType(t Type, seen map[Type]bool) bool {
+	if t == nil || seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch ct := t.(type) {
+	case PrimitiveType:
+		return ct == Float32Type || ct == Float64Type
+	case *DeclaredType:
+		return hasFloatType(ct.Base, seen)
+	case *PointerType:
+		return hasFloatType(ct.Elt, seen)
+	case *ArrayType:
+		return hasFloatType(ct.Elt, seen)
+	case *SliceType:
+		return hasFloatType(ct.Elt, seen)
+	case *ChanType:
+		return hasFloatType(ct.Elt, seen)
+	case *MapType:
+		return hasFloatType(ct.Key, seen) || hasFloatType(ct.Value, seen)
+	case *StructType:
+		return hasFloatField(ct.Fields, seen)
+	case *FuncType:
+		return hasFloatField(ct.Params, seen) || hasFloatField(ct.Results, seen)
+	case *tupleType:
+		for _, et := range ct.Elts {
+			if hasFloatType(et, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasFloatField(fields []FieldType, seen map[Type]bool) bool {
+	for _, ft := range fields {
+		if hasFloatType(ft.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package gno
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+)
+
+func TestCheckNoFloats(t *testing.T) {
+	cases := []struct {
+		body string
+		err  string
+	}{
+		{"var x = 1.5", "a.gno:3: floating-point type float64 not allowed"},
+		{"func F(n int) float64 { return 0 }", "a.gno:3: floating-point type float64 not allowed"},
+		{"type S struct {\n\tf float32\n}", "a.gno:3: floating-point type gno.land/r/hello.S not allowed"},
+		{"var m map[string][]float64", "a.gno:3: floating-point type float64 not allowed"},
+		{"func F(n int) int {\n\tx := float64(n) / 2\n\treturn int(x)\n}", "a.gno:4: floating-point type float64 not allowed"},
+		{"type T []*float32\n\nfunc F(t T) {}", "a.gno:3: floating-point type gno.land/r/hello.T not allowed"},
+		// untyped constants are exact.
+		{"const c = 1.5\n\nvar n = int(c * 2)", ""},
+		{"var n = 10 / 3", ""},
+		{"type S struct{ n int }\n\nfunc (s S) Get() int { return s.n }", ""},
+	}
+	for _, c := range cases {
+		memPkg := &std.MemPackage{
+			Name:  "hello",
+			Path:  "gno.land/r/hello",
+			Files: []*std.MemFile{{Name: "a.gno", Body: "package hello\n\n" + c.body + "\n"}},
+		}
+		err := TypeCheckMemPackage(memPkg, NewStore(nil, nil, nil), CheckNoFloats)
+		if c.err == "" {
+			assert.NoError(t, err, c.body)
+		} else if assert.Error(t, err, c.body) {
+			assert.Equal(t, err.Error(), c.err, c.body)
+		}
+		// allowed without the check.
+		assert.NoError(t, TypeCheckMemPackage(memPkg, NewStore(nil, nil, nil)), c.body)
+	}
+}
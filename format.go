@@ -0,0 +1,99 @@
+package gno
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// FormatSource formats the Gno source src of the file named fname, in the
+// style of gofmt. The syntax of Gno is that of Go, but the directive
+// comments of filetests (e.g. "// Output:") that end a _filetest.gno file
+// are kept verbatim, as their trailing whitespace may be significant.
+func FormatSource(fname string, src []byte) ([]byte, error) {
+	head, tail := src, []byte(nil)
+	if strings.HasSuffix(fname, "_filetest.gno") {
+		if i := filetestDirectivesIndex(src); i >= 0 {
+			head, tail = src[:i], src[i:]
+		}
+	}
+	res, err := format.Source(head)
+	if err != nil {
+		return nil, errors.Wrap(err, "formatting file "+fname)
+	}
+	if tail != nil {
+		// keep an empty line before the directives, as gofmt does.
+		if bytes.HasSuffix(bytes.TrimRight(head, " \t"), []byte("\n\n")) {
+			res = append(res, '\n')
+		}
+		res = append(res, tail...)
+	}
+	return res, nil
+}
+
+// Returns the offset of the first line of the directive comments of a
+// filetest, or -1 if there are none.
+func filetestDirectivesIndex(src []byte) int {
+	// only match directive comments outside of declarations.
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return -1
+	}
+	end := 0
+	if len(f.Decls) > 0 {
+		end = fset.Position(f.Decls[len(f.Decls)-1].End()).Offset
+	}
+	for _, cg := range f.Comments {
+		pos := fset.Position(cg.Pos()).Offset
+		if pos < end {
+			continue
+		}
+		text := cg.Text()
+		for _, directive := range []string{"Output:\n", "Error:\n", "Realm:\n"} {
+			if strings.HasPrefix(text, directive) {
+				return pos
+			}
+		}
+	}
+	return -1
+}
+
+// UnformattedFiles returns the names of the .gno files of memPkg which are
+// not formatted as by FormatSource, or an error if a file cannot be
+// parsed.
+func UnformattedFiles(memPkg *std.MemPackage) ([]string, error) {
+	var names []string
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") {
+			continue
+		}
+		res, err := FormatSource(mfile.Name, []byte(mfile.Body))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(res, []byte(mfile.Body)) {
+			names = append(names, mfile.Name)
+		}
+	}
+	return names, nil
+}
+
+// CheckFormatMemPackage returns an error if any .gno file of memPkg is not
+// formatted.
+func CheckFormatMemPackage(memPkg *std.MemPackage) error {
+	names, err := UnformattedFiles(memPkg)
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return errors.New("unformatted files in package %s: %s",
+			memPkg.Path, strings.Join(names, ", "))
+	}
+	return nil
+}
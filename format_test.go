@@ -0,0 +1,41 @@
+package gno
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+)
+
+func TestFormatSource(t *testing.T) {
+	res, err := FormatSource("add.gno", []byte("package add\nfunc  Add(a,b int) int { return a+b }\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(res), "package add\n\nfunc Add(a, b int) int { return a + b }\n")
+
+	_, err = FormatSource("bad.gno", []byte("package bad\nfunc {"))
+	assert.Error(t, err)
+
+	// the directives of filetests are kept verbatim.
+	src := "package main\nfunc main() {\n  println(\"a \")\n}\n\n// Output:\n// a \n"
+	res, err = FormatSource("a_filetest.gno", []byte(src))
+	assert.NoError(t, err)
+	assert.Equal(t, string(res), "package main\n\nfunc main() {\n\tprintln(\"a \")\n}\n\n// Output:\n// a \n")
+}
+
+func TestCheckFormatMemPackage(t *testing.T) {
+	memPkg := &std.MemPackage{
+		Name: "add",
+		Path: "gno.land/p/add",
+		Files: []*std.MemFile{
+			{Name: "add.gno", Body: "package add\n\nfunc Add(a, b int) int { return a + b }\n"},
+			{Name: "README.md", Body: "# add  \n"},
+		},
+	}
+	assert.NoError(t, CheckFormatMemPackage(memPkg))
+
+	memPkg.Files = append(memPkg.Files, &std.MemFile{Name: "sub.gno", Body: "package add\nfunc Sub(a, b int) int { return a - b }\n"})
+	names, err := UnformattedFiles(memPkg)
+	assert.NoError(t, err)
+	assert.Equal(t, names, []string{"sub.gno"})
+	assert.Error(t, CheckFormatMemPackage(memPkg))
+}
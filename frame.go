@@ -24,6 +24,7 @@ type Frame struct {
 	Defers      []Defer       // deferred calls
 	LastPackage *PackageValue // previous package context
 	LastRealm   *Realm        // previous realm context
+	NumCycles   int64         // cycles at start of call
 }
 
 func (fr Frame) String() string {
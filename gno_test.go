@@ -362,3 +362,36 @@ func TestCallFieldLHS(t *testing.T) {
 	assert.Equal(t, x.X, 2)
 	assert.Equal(t, y, 3)
 }
+
+// infinite recursion and deep nesting panic deterministically.
+func TestMachineLimits(t *testing.T) {
+	c := `package test
+func recurse(i int) int {
+	return recurse(i+1)
+}
+func sum(xs ...int) int {
+	return 0
+}`
+	newMachine := func(opts MachineOptions) *Machine {
+		opts.PkgPath = "test"
+		m := NewMachineWithOptions(opts)
+		m.RunFiles(MustParseFile("main.go", c))
+		return m
+	}
+	assert.PanicsWithValue(t, func() {
+		m := newMachine(MachineOptions{MaxFrames: 100})
+		m.Eval(Call("recurse", "0"))
+	}, "frame limit exceeded")
+	assert.PanicsWithValue(t, func() {
+		m := newMachine(MachineOptions{MaxValues: 100})
+		m.Eval(Call("recurse", "0"))
+	}, "value limit exceeded")
+	x := Call("sum")
+	for i := 0; i < 200; i++ {
+		x = Call("sum", x)
+	}
+	assert.PanicsWithValue(t, func() {
+		m := newMachine(MachineOptions{MaxExprs: 100})
+		m.Eval(x)
+	}, "expression limit exceeded")
+}
@@ -12,7 +12,10 @@ import (
 	"github.com/gnolang/gno/pkgs/log"
 	"github.com/gnolang/gno/pkgs/sdk"
 	"github.com/gnolang/gno/pkgs/sdk/auth"
+	"github.com/gnolang/gno/pkgs/sdk/authz"
 	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/sdk/ibc"
+	"github.com/gnolang/gno/pkgs/sdk/valset"
 	"github.com/gnolang/gno/pkgs/sdk/vm"
 	"github.com/gnolang/gno/pkgs/std"
 	"github.com/gnolang/gno/pkgs/store"
@@ -20,17 +23,38 @@ import (
 	"github.com/gnolang/gno/pkgs/store/iavl"
 )
 
-// NewApp creates the GnoLand application.
-func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger) (abci.Application, error) {
+// NewApp creates the GnoLand application, with options of its BaseApp.
+func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger, options ...func(*sdk.BaseApp)) (abci.Application, error) {
 	// Get main DB.
 	db := dbm.NewDB("gnolang", dbm.GoLevelDBBackend, filepath.Join(rootDir, "data"))
 
+	app, err := newApp(db, skipFailingGenesisTxs, logger, options...)
+	if err != nil {
+		return nil, err
+	}
+	return app.BaseApp, nil
+}
+
+// gnoApp is the GnoLand application, with its keepers.
+type gnoApp struct {
+	*sdk.BaseApp
+
+	acctKpr   auth.AccountKeeper
+	bankKpr   bank.BankKeeper
+	vmKpr     *vm.VMKeeper
+	valsetKpr valset.ValsetKeeper
+	authzKpr  authz.AuthzKeeper
+	ibcKpr    ibc.IBCKeeper
+}
+
+// Creates the GnoLand application on db.
+func newApp(db dbm.DB, skipFailingGenesisTxs bool, logger log.Logger, options ...func(*sdk.BaseApp)) (*gnoApp, error) {
 	// Capabilities keys.
 	mainKey := store.NewStoreKey("main")
 	baseKey := store.NewStoreKey("base")
 
 	// Create BaseApp.
-	baseApp := sdk.NewBaseApp("gnoland", logger, db, baseKey, mainKey)
+	baseApp := sdk.NewBaseApp("gnoland", logger, db, baseKey, mainKey, options...)
 	baseApp.SetAppVersion("dev")
 
 	// Set mounts for BaseApp's MultiStore.
@@ -41,13 +65,19 @@ func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger) (abci
 	acctKpr := auth.NewAccountKeeper(mainKey, ProtoGnoAccount)
 	bankKpr := bank.NewBankKeeper(acctKpr)
 	vmKpr := vm.NewVMKeeper(baseKey, mainKey, acctKpr, bankKpr, "./stdlibs")
+	valsetKpr := valset.NewValsetKeeper(mainKey)
+	authzKpr := authz.NewAuthzKeeper(mainKey)
+	ibcKpr := ibc.NewIBCKeeper(mainKey)
 
 	// Set InitChainer
-	baseApp.SetInitChainer(InitChainer(baseApp, acctKpr, bankKpr, skipFailingGenesisTxs))
+	baseApp.SetInitChainer(InitChainer(baseApp, acctKpr, bankKpr, vmKpr, valsetKpr, skipFailingGenesisTxs))
 
 	// Set AnteHandler
 	authOptions := auth.AnteOptions{
 		VerifyGenesisSignatures: false, // for development
+		AuthorizeSigner:         authzKpr.AuthorizeSigner,
+		VerifySigner:            vmKpr.VerifySigner,
+		CheckTx:                 vmKpr.CheckTx,
 	}
 	authAnteHandler := auth.NewAnteHandler(
 		acctKpr, bankKpr, auth.DefaultSigVerificationGasConsumer, authOptions)
@@ -59,19 +89,38 @@ func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger) (abci
 			// Override auth params.
 			ctx = ctx.WithValue(
 				auth.AuthParamsContextKey{}, auth.DefaultParams())
+			// Override vm params.
+			ctx = ctx.WithValue(
+				vm.VMParamsContextKey{}, vm.DefaultParams())
 			// Continue on with default auth ante handler.
 			newCtx, res, abort = authAnteHandler(ctx, tx, simulate)
 			return
 		},
 	)
 
-	// Set EndBlocker
-	baseApp.SetEndBlocker(EndBlocker(vmKpr))
+	// Set TxHasher
+	baseApp.SetTxHasher(auth.NewTxHasher())
+
+	// Set SystemTxFilter
+	baseApp.SetSystemTxFilter(isSystemTx)
+
+	// Set GasRefunder
+	baseApp.SetGasRefunder(auth.NewGasRefunder(acctKpr, bankKpr))
+
+	// Register the invariants of the modules, checked at the end of
+	// blocks with sdk.SetInvariantCheckPeriod.
+	bank.RegisterInvariants(baseApp, acctKpr)
 
-	// Set a handler Route.
-	baseApp.Router().AddRoute("auth", auth.NewHandler(acctKpr))
-	baseApp.Router().AddRoute("bank", bank.NewHandler(bankKpr))
-	baseApp.Router().AddRoute("vm", vm.NewHandler(vmKpr))
+	// Set the modules, which route their messages and queries, and begin
+	// and end blocks.
+	baseApp.SetModuleManager(sdk.NewModuleManager(
+		auth.NewModule(acctKpr),
+		bank.NewModule(bankKpr),
+		vm.NewModule(vmKpr),
+		valset.NewModule(valsetKpr),
+		authz.NewModule(authzKpr),
+		ibc.NewModule(ibcKpr),
+	))
 
 	// Load latest version.
 	if err := baseApp.LoadLatestVersion(); err != nil {
@@ -81,19 +130,54 @@ func NewApp(rootDir string, skipFailingGenesisTxs bool, logger log.Logger) (abci
 	// Initialize the VMKeeper.
 	vmKpr.Initialize(baseApp.GetCacheMultiStore())
 
-	return baseApp, nil
+	return &gnoApp{
+		BaseApp:   baseApp,
+		acctKpr:   acctKpr,
+		bankKpr:   bankKpr,
+		vmKpr:     vmKpr,
+		valsetKpr: valsetKpr,
+		authzKpr:  authzKpr,
+		ibcKpr:    ibcKpr,
+	}, nil
 }
 
 // InitChainer returns a function that can initialize the chain with genesis.
-func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank.BankKeeperI, skipFailingGenesisTxs bool) func(sdk.Context, abci.RequestInitChain) abci.ResponseInitChain {
+func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank.BankKeeperI, vmKpr vm.VMKeeperI, valsetKpr valset.ValsetKeeperI, skipFailingGenesisTxs bool) func(sdk.Context, abci.RequestInitChain) abci.ResponseInitChain {
 	return func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
 		// Get genesis state.
 		genState := req.AppState.(GnoGenesisState)
+		// Import the state of stores exported from another chain.
+		if len(genState.Stores) > 0 {
+			stores := make(map[string][]std.KVPair)
+			for _, gs := range genState.Stores {
+				stores[gs.Name] = gs.Pairs
+			}
+			if err := baseApp.ImportStores(ctx, stores); err != nil {
+				panic(err)
+			}
+			vmKpr.LoadPackages(ctx)
+		}
+		// Set the validators, e.g. to rotate their keys.
+		valsetKpr.InitValidators(ctx, req.Validators)
+		// Initialize the modules with their states.
+		if len(genState.Modules) > 0 {
+			modules := make(map[string]interface{})
+			for _, gm := range genState.Modules {
+				modules[gm.Name] = gm.State
+			}
+			if err := baseApp.InitGenesis(ctx, modules); err != nil {
+				panic(err)
+			}
+		}
 		// Parse and set genesis state balances.
 		for _, bal := range genState.Balances {
 			addr, coins := parseBalance(bal)
-			acc := acctKpr.NewAccountWithAddress(ctx, addr)
-			acctKpr.SetAccount(ctx, acc)
+			// keep the accounts of the imported state, e.g. their
+			// account numbers and sequences.
+			if acctKpr.GetAccount(ctx, addr) == nil {
+				acc := acctKpr.NewAccountWithAddress(ctx, addr)
+				acctKpr.SetAccount(ctx, acc)
+			}
 			err := bankKpr.SetCoins(ctx, addr, coins)
 			if err != nil {
 				panic(err)
@@ -113,6 +197,12 @@ func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank
 				fmt.Println("SUCCESS:", string(amino.MustMarshalJSON(tx)))
 			}
 		}
+		// Import the state of realms exported from another chain.
+		for i := range genState.Realms {
+			if err := vmKpr.ImportRealm(ctx, &genState.Realms[i]); err != nil {
+				panic(err)
+			}
+		}
 		// Done!
 		return abci.ResponseInitChain{
 			Validators: req.Validators,
@@ -120,6 +210,21 @@ func InitChainer(baseApp *sdk.BaseApp, acctKpr auth.AccountKeeperI, bankKpr bank
 	}
 }
 
+// Returns true if tx is a system tx, in the system lane of the mempool: if
+// its msgs all rotate the keys of validators or relay IBC packets and
+// clients, so that neither the validators nor the bridges of the chain are
+// delayed by the other txs.
+func isSystemTx(tx std.Tx) bool {
+	for _, msg := range tx.GetMsgs() {
+		switch msg.Route() {
+		case valset.RouterKey, ibc.RouterKey:
+		default:
+			return false
+		}
+	}
+	return len(tx.GetMsgs()) > 0
+}
+
 func parseBalance(bal string) (crypto.Address, std.Coins) {
 	parts := strings.Split(bal, "=")
 	if len(parts) != 2 {
@@ -135,10 +240,3 @@ func parseBalance(bal string) (crypto.Address, std.Coins) {
 	}
 	return addr, coins
 }
-
-// XXX not used yet.
-func EndBlocker(vmk vm.VMKeeperI) func(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
-	return func(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
-		return abci.ResponseEndBlock{}
-	}
-}
@@ -0,0 +1,166 @@
+package gnoland
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/log"
+	"github.com/gnolang/gno/pkgs/sdk/authz"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/sdk/valset"
+	"github.com/gnolang/gno/pkgs/sdk/vm"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Initializes app with genState, and commits its first block.
+func initTestApp(t *testing.T, app *gnoApp, genState GnoGenesisState) {
+	t.Helper()
+
+	consParams := bft.DefaultConsensusParams()
+	app.InitChain(abci.RequestInitChain{
+		ChainID:         "test",
+		ConsensusParams: &consParams,
+		AppState:        genState,
+	})
+	app.BeginBlock(abci.RequestBeginBlock{
+		Header: &bft.Header{ChainID: "test", Height: 1},
+	})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+}
+
+// The states of modules exported from a chain start a new chain with them.
+func TestAppGenesisModules(t *testing.T) {
+	chdirRepository(t)
+
+	grant := authz.Grant{
+		Granter:    crypto.AddressFromPreimage([]byte("granter")),
+		Grantee:    crypto.AddressFromPreimage([]byte("grantee")),
+		MsgTypes:   []string{"vm/exec"},
+		SpendLimit: std.MustParseCoins("1000ugnot"),
+		Expiration: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	app, err := newApp(dbm.NewMemDB(), false, log.NewNopLogger())
+	require.NoError(t, err)
+	initTestApp(t, app, GnoGenesisState{
+		Balances: []string{},
+		Modules: []GenesisModule{
+			{Name: authz.ModuleName, State: authz.GenesisState{Grants: []authz.Grant{grant}}},
+		},
+	})
+	modules, err := app.ExportGenesis(1)
+	require.NoError(t, err)
+	require.Equal(t, authz.GenesisState{Grants: []authz.Grant{grant}}, modules[authz.ModuleName])
+
+	// Start a new chain from the exported genesis, through its JSON.
+	genState := GnoGenesisState{Balances: []string{}}
+	for name, state := range modules {
+		genState.Modules = append(genState.Modules, GenesisModule{Name: name, State: state})
+	}
+	var genState2 GnoGenesisState
+	require.NoError(t, amino.UnmarshalJSON(amino.MustMarshalJSON(genState), &genState2))
+	app2, err := newApp(dbm.NewMemDB(), false, log.NewNopLogger())
+	require.NoError(t, err)
+	initTestApp(t, app2, genState2)
+	modules2, err := app2.ExportGenesis(1)
+	require.NoError(t, err)
+	require.Equal(t, modules, modules2)
+	got, ok := app2.authzKpr.GetGrant(app2.NewCommittedContext(), grant.Granter, grant.Grantee)
+	require.True(t, ok)
+	require.Equal(t, grant, got)
+
+	// The states of unknown modules are rejected.
+	app3, err := newApp(dbm.NewMemDB(), false, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Panics(t, func() {
+		initTestApp(t, app3, GnoGenesisState{
+			Balances: []string{},
+			Modules:  []GenesisModule{{Name: "unknown", State: authz.GenesisState{}}},
+		})
+	})
+}
+
+// The configuration of the vm keeper is set by its state in genesis, and
+// kept on restart.
+func TestAppVMGenesis(t *testing.T) {
+	chdirRepository(t)
+
+	vmState := vm.GenesisState{
+		DeployWhitelist:  []crypto.Address{crypto.AddressFromPreimage([]byte("deployer"))},
+		DeployFee:        std.MustParseCoin("1000ugnot"),
+		DeployFeePerByte: std.MustParseCoin("10ugnot"),
+		NamespaceFee:     std.MustParseCoin("5000ugnot"),
+		RejectFloats:     true,
+		RequireFormatted: true,
+		StoragePrice:     std.MustParseCoin("1ugnot"),
+		ReservedDenoms:   []string{"ugnot", "uatom"},
+		UpgradeAuthority: crypto.AddressFromPreimage([]byte("governance")),
+		PackageLimits:    &vm.PackageLimits{MaxSize: 1024, MaxFiles: 10},
+	}
+	memPkg := &std.MemPackage{
+		Name:  "test",
+		Path:  "gno.land/r/test",
+		Files: []*std.MemFile{{Name: "test.gno", Body: "package test\n"}},
+	}
+	// Through the JSON of the genesis file.
+	var genState GnoGenesisState
+	require.NoError(t, amino.UnmarshalJSON(amino.MustMarshalJSON(GnoGenesisState{
+		Balances: []string{},
+		Modules:  []GenesisModule{{Name: vm.ModuleName, State: vmState}},
+	}), &genState))
+	db := dbm.NewMemDB()
+	app, err := newApp(db, false, log.NewNopLogger())
+	require.NoError(t, err)
+	initTestApp(t, app, genState)
+	modules, err := app.ExportGenesis(1)
+	require.NoError(t, err)
+	require.Equal(t, vmState, modules[vm.ModuleName])
+	fee, err := app.vmKpr.DeployFee(memPkg)
+	require.NoError(t, err)
+	require.Equal(t, std.MustParseCoins("1130ugnot"), fee)
+
+	// The configuration is loaded again on restart.
+	app2, err := newApp(db, false, log.NewNopLogger())
+	require.NoError(t, err)
+	modules2, err := app2.ExportGenesis(1)
+	require.NoError(t, err)
+	require.Equal(t, vmState, modules2[vm.ModuleName])
+	fee, err = app2.vmKpr.DeployFee(memPkg)
+	require.NoError(t, err)
+	require.Equal(t, std.MustParseCoins("1130ugnot"), fee)
+}
+
+// The txs rotating the keys of validators are in the system lane of the
+// mempool, unlike those with other msgs.
+func TestAppSystemTxs(t *testing.T) {
+	chdirRepository(t)
+
+	app, err := newApp(dbm.NewMemDB(), false, log.NewNopLogger())
+	require.NoError(t, err)
+	initTestApp(t, app, GnoGenesisState{Balances: []string{}})
+
+	caller := crypto.AddressFromPreimage([]byte("caller"))
+	validator := crypto.AddressFromPreimage([]byte("validator"))
+	rotate := valset.NewMsgRotateKey(caller, validator, ed25519.GenPrivKey().PubKey(), 1)
+	send := bank.NewMsgSend(caller, validator, std.MustParseCoins("1ugnot"))
+	for _, tc := range []struct {
+		msgs   []std.Msg
+		system bool
+	}{
+		{[]std.Msg{rotate}, true},
+		{[]std.Msg{send}, false},
+		{[]std.Msg{rotate, send}, false},
+	} {
+		tx := std.NewTx(tc.msgs, std.NewFee(100000, std.MustParseCoin("1ugnot")), nil, "")
+		res := app.CheckTx(abci.RequestCheckTx{Tx: amino.MustMarshal(tx)})
+		require.Equal(t, tc.system, res.System, tc.msgs)
+	}
+}
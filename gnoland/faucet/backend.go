@@ -0,0 +1,204 @@
+package faucet
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/keys"
+	"github.com/gnolang/gno/pkgs/crypto/multisig"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// A Backend holds the funds of a faucet, at the address of its public key,
+// and signs the transactions paying them out.
+type Backend interface {
+	PubKey() crypto.PubKey
+	Sign(signBytes []byte) ([]byte, error)
+}
+
+//----------------------------------------
+// KeybaseBackend
+
+// KeybaseBackend signs with a key of a local keybase.
+type KeybaseBackend struct {
+	kb     keys.Keybase
+	name   string
+	pass   string
+	pubKey crypto.PubKey
+}
+
+var _ Backend = (*KeybaseBackend)(nil)
+
+// NewKeybaseBackend returns the backend of the key name of kb, after
+// checking pass by signing a dummy message.
+func NewKeybaseBackend(kb keys.Keybase, name, pass string) (*KeybaseBackend, error) {
+	const dummy = "test"
+	_, pubKey, err := kb.Sign(name, pass, []byte(dummy))
+	if err != nil {
+		return nil, err
+	}
+	return &KeybaseBackend{
+		kb:     kb,
+		name:   name,
+		pass:   pass,
+		pubKey: pubKey,
+	}, nil
+}
+
+func (kbb *KeybaseBackend) PubKey() crypto.PubKey {
+	return kbb.pubKey
+}
+
+func (kbb *KeybaseBackend) Sign(signBytes []byte) ([]byte, error) {
+	sig, _, err := kbb.kb.Sign(kbb.name, kbb.pass, signBytes)
+	return sig, err
+}
+
+//----------------------------------------
+// MultisigBackend
+
+// MultisigBackend signs with a threshold multisig key, from the signatures
+// of the backends of enough of its keys.
+type MultisigBackend struct {
+	pubKey  multisig.PubKeyMultisigThreshold
+	signers []Backend
+}
+
+var _ Backend = (*MultisigBackend)(nil)
+
+// NewMultisigBackend returns the backend of the multisig pubKey, whose
+// signers must be backends of at least its threshold of keys.
+func NewMultisigBackend(pubKey crypto.PubKey, signers ...Backend) (*MultisigBackend, error) {
+	mpk, ok := pubKey.(multisig.PubKeyMultisigThreshold)
+	if !ok {
+		return nil, errors.New("expected multisig pubkey, got %T", pubKey)
+	}
+	if len(signers) < int(mpk.K) {
+		return nil, errors.New("expected at least %d signers, got %d", mpk.K, len(signers))
+	}
+	for _, signer := range signers {
+		found := false
+		for _, pk := range mpk.PubKeys {
+			if pk.Equals(signer.PubKey()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("signer %s not in multisig", signer.PubKey().Address())
+		}
+	}
+	return &MultisigBackend{
+		pubKey:  mpk,
+		signers: signers,
+	}, nil
+}
+
+func (msb *MultisigBackend) PubKey() crypto.PubKey {
+	return msb.pubKey
+}
+
+func (msb *MultisigBackend) Sign(signBytes []byte) ([]byte, error) {
+	mSig := multisig.NewMultisig(len(msb.pubKey.PubKeys))
+	for _, signer := range msb.signers[:msb.pubKey.K] {
+		sig, err := signer.Sign(signBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "signing with %s", signer.PubKey().Address())
+		}
+		err = mSig.AddSignatureFromPubKey(sig, signer.PubKey(), msb.pubKey.PubKeys)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mSig.Marshal(), nil
+}
+
+//----------------------------------------
+// RemoteBackend
+
+// RemoteBackend signs with a remote signer, served by RemoteSignerHandler,
+// which should only be reachable by the faucet.
+type RemoteBackend struct {
+	url    string
+	pubKey crypto.PubKey
+}
+
+var _ Backend = (*RemoteBackend)(nil)
+
+// NewRemoteBackend returns the backend of the remote signer at url, after
+// querying its pubkey.
+func NewRemoteBackend(url string) (*RemoteBackend, error) {
+	resp, err := http.Get(url + "/pubkey")
+	if err != nil {
+		return nil, errors.Wrap(err, "querying remote signer")
+	}
+	bz, err := readRemoteResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	var pubKey crypto.PubKey
+	if err := amino.UnmarshalJSON(bz, &pubKey); err != nil {
+		return nil, errors.Wrap(err, "decoding pubkey of remote signer")
+	}
+	return &RemoteBackend{
+		url:    url,
+		pubKey: pubKey,
+	}, nil
+}
+
+func (rb *RemoteBackend) PubKey() crypto.PubKey {
+	return rb.pubKey
+}
+
+func (rb *RemoteBackend) Sign(signBytes []byte) ([]byte, error) {
+	resp, err := http.Post(rb.url+"/sign", "application/octet-stream", bytes.NewReader(signBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "requesting remote signature")
+	}
+	return readRemoteResponse(resp)
+}
+
+func readRemoteResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("remote signer: %s: %s", resp.Status, bz)
+	}
+	return bz, nil
+}
+
+// RemoteSignerHandler serves the signatures of backend to a RemoteBackend:
+// GET /pubkey returns the amino JSON of its pubkey, and POST /sign returns
+// the signature of the request body.
+func RemoteSignerHandler(backend Backend) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pubkey", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(amino.MustMarshalJSONAny(backend.PubKey()))
+	})
+	mux.HandleFunc("/sign", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+			return
+		}
+		signBytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sig, err := backend.Sign(signBytes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(sig)
+	})
+	return mux
+}
@@ -0,0 +1,79 @@
+package faucet
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/keys"
+	"github.com/gnolang/gno/pkgs/crypto/multisig"
+)
+
+const (
+	mn1 = `lounge napkin all odor tilt dove win inject sleep jazz uncover traffic hint require cargo arm rocket round scan bread report squirrel step lake`
+	mn2 = `lecture salt about avocado smooth height escape general arch head barrel clutch dismiss supply doctor project cat truck fruit abuse gorilla symbol portion glare`
+)
+
+// Returns the backends of two keys of an in-memory keybase.
+func newTestBackends(t *testing.T) (*KeybaseBackend, *KeybaseBackend) {
+	t.Helper()
+
+	kb := keys.NewInMemory()
+	_, err := kb.CreateAccount("k1", mn1, "", "pass1", 0, 0)
+	require.NoError(t, err)
+	_, err = kb.CreateAccount("k2", mn2, "", "pass2", 0, 0)
+	require.NoError(t, err)
+
+	_, err = NewKeybaseBackend(kb, "k1", "wrong")
+	require.Error(t, err)
+	b1, err := NewKeybaseBackend(kb, "k1", "pass1")
+	require.NoError(t, err)
+	b2, err := NewKeybaseBackend(kb, "k2", "pass2")
+	require.NoError(t, err)
+	return b1, b2
+}
+
+func TestKeybaseBackend(t *testing.T) {
+	b1, _ := newTestBackends(t)
+	msg := []byte("sign bytes")
+	sig, err := b1.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, b1.PubKey().VerifyBytes(msg, sig))
+}
+
+func TestMultisigBackend(t *testing.T) {
+	b1, b2 := newTestBackends(t)
+	pubKey := multisig.NewPubKeyMultisigThreshold(2, []crypto.PubKey{b1.PubKey(), b2.PubKey()})
+
+	_, err := NewMultisigBackend(pubKey, b1)
+	require.Error(t, err)
+	_, err = NewMultisigBackend(b1.PubKey(), b1, b2)
+	require.Error(t, err)
+
+	msb, err := NewMultisigBackend(pubKey, b2, b1)
+	require.NoError(t, err)
+	require.Equal(t, pubKey.Address(), msb.PubKey().Address())
+	msg := []byte("sign bytes")
+	sig, err := msb.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifyBytes(msg, sig))
+}
+
+func TestRemoteBackend(t *testing.T) {
+	b1, _ := newTestBackends(t)
+	srv := httptest.NewServer(RemoteSignerHandler(b1))
+	defer srv.Close()
+
+	rb, err := NewRemoteBackend(srv.URL)
+	require.NoError(t, err)
+	require.True(t, b1.PubKey().Equals(rb.PubKey()))
+	msg := []byte("sign bytes")
+	sig, err := rb.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, b1.PubKey().VerifyBytes(msg, sig))
+
+	_, err = NewRemoteBackend(srv.URL + "/nope")
+	require.Error(t, err)
+}
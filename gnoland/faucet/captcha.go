@@ -0,0 +1,69 @@
+package faucet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// struct for verify captcha
+type SiteVerifyResponse struct {
+	Success     bool      `json:"success"`
+	Score       float64   `json:"score"`
+	Action      string    `json:"action"`
+	ChallengeTS time.Time `json:"challenge_ts"`
+	Hostname    string    `json:"hostname"`
+	ErrorCodes  []string  `json:"error-codes"`
+}
+
+// CaptchaProvider is a captcha service, verifying the responses of the
+// widget of its site key.  The services share the siteverify API of
+// reCAPTCHA, with their own URL and form field.
+type CaptchaProvider struct {
+	VerifyURL string
+	Field     string // form field of the widget response
+}
+
+var CaptchaProviders = map[string]CaptchaProvider{
+	"recaptcha": {
+		VerifyURL: "https://www.google.com/recaptcha/api/siteverify",
+		Field:     "g-recaptcha-response",
+	},
+	"hcaptcha": {
+		VerifyURL: "https://hcaptcha.com/siteverify",
+		Field:     "h-captcha-response",
+	},
+	"turnstile": {
+		VerifyURL: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+		Field:     "cf-turnstile-response",
+	},
+}
+
+// Verify checks the response of a widget, solved from remoteIP.
+func (cp CaptchaProvider) Verify(secret, response, remoteIP string) error {
+	form := url.Values{}
+	form.Add("secret", secret)
+	form.Add("response", response)
+	if remoteIP != "" {
+		form.Add("remoteip", remoteIP)
+	}
+	resp, err := http.PostForm(cp.VerifyURL, form) // 200 OK
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body SiteVerifyResponse
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return errors.New("fail, decode response")
+	}
+
+	if !body.Success {
+		return errors.New("unsuccessful captcha verify request %v", body.ErrorCodes)
+	}
+
+	return nil
+}
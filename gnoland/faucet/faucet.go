@@ -0,0 +1,330 @@
+// Package faucet implements a faucet of a gno.land chain, to be embedded in
+// a server.  The funds of a faucet are held by a Backend, e.g. a local
+// keybase, and its claims are validated by a chain of Middlewares, e.g. to
+// protect it from abuse.  Claims are paid out in batches, by multisend
+// transactions.
+package faucet
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/gnoland"
+	"github.com/gnolang/gno/pkgs/amino"
+	rpcclient "github.com/gnolang/gno/pkgs/bft/rpc/client"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/keys/client"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/service"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Config of a faucet.
+type Config struct {
+	ChainID       string
+	GasWanted     int64 // per payout of a tx
+	GasFee        std.Coin
+	Memo          string
+	Send          std.Coins // per claim
+	BatchSize     int       // max payouts per tx
+	BatchInterval time.Duration
+	BehindProxy   bool // use X-Forwarded-For IP of claims
+}
+
+func DefaultConfig() Config {
+	return Config{
+		ChainID:       "", // must override
+		GasWanted:     50000,
+		GasFee:        std.NewCoin("ugnot", 1000000),
+		Memo:          "",
+		Send:          std.NewCoins(std.NewCoin("ugnot", 1000000)),
+		BatchSize:     20,
+		BatchInterval: 5 * time.Second,
+		BehindProxy:   false,
+	}
+}
+
+func (cfg Config) ValidateBasic() error {
+	if cfg.ChainID == "" {
+		return errors.New("chain-id not specified")
+	}
+	if cfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if !cfg.Send.IsValid() || cfg.Send.IsZero() {
+		return errors.New("invalid send coins %q", cfg.Send)
+	}
+	if cfg.BatchSize <= 0 {
+		return errors.New("batch-size must be positive")
+	}
+	return nil
+}
+
+// Faucet pays out the claims of its HTTP requests, validated by its
+// middlewares, with the funds of its backend.
+type Faucet struct {
+	service.BaseService
+
+	cli     rpcclient.Client
+	backend Backend
+	config  Config
+	handler ClaimHandler
+	queue   *PayoutQueue
+	Metrics *Metrics
+
+	// of the funds, only used by the queue.
+	accountNumber uint64
+	sequence      uint64
+}
+
+// NewFaucet returns a faucet broadcasting to cli, whose claims are
+// validated by mws, the first being the outermost.
+func NewFaucet(cli rpcclient.Client, backend Backend, config Config, mws ...Middleware) *Faucet {
+	f := &Faucet{
+		cli:     cli,
+		backend: backend,
+		config:  config,
+		Metrics: NewMetrics(),
+	}
+	f.handler = f.pay
+	for i := len(mws) - 1; i >= 0; i-- {
+		f.handler = mws[i](f.handler)
+	}
+	f.queue = NewPayoutQueue(config.BatchSize, config.BatchInterval, f.sendBatch)
+	f.BaseService = *service.NewBaseService(nil, "Faucet", f)
+	return f
+}
+
+func (f *Faucet) OnStart() error {
+	f.BaseService.OnStart()
+	if err := f.config.ValidateBasic(); err != nil {
+		return err
+	}
+	if err := f.querySequence(); err != nil {
+		return err
+	}
+	return f.queue.Start()
+}
+
+func (f *Faucet) OnStop() {
+	f.BaseService.OnStop()
+	f.queue.Stop()
+}
+
+// Address returns the address of the funds of the faucet.
+func (f *Faucet) Address() crypto.Address {
+	return f.backend.PubKey().Address()
+}
+
+// Pay sends coins to addr, in the next batch, bypassing the middlewares.
+func (f *Faucet) Pay(addr crypto.Address, coins std.Coins) error {
+	return f.queue.Pay(addr, coins)
+}
+
+// ServeHTTP handles a claim for the address of the form value toaddr.  The
+// claim is for the Send coins of the config, or only those of the form
+// values denom, if any.
+func (f *Faucet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ip := f.remoteIP(r)
+	if ip == nil {
+		f.Logger.Info("no ip found", "addr", r.RemoteAddr)
+		w.Write([]byte("no ip found"))
+		return
+	}
+
+	r.ParseForm()
+	passedAddr := r.Form["toaddr"]
+	if passedAddr == nil {
+		f.Logger.Info("no address found", "ip", ip)
+		w.Write([]byte("no address found"))
+		return
+	}
+	toAddr, err := crypto.AddressFromBech32(strings.TrimSpace(passedAddr[0]))
+	if err != nil {
+		f.Logger.Info("invalid address format", "ip", ip, "err", err)
+		w.Write([]byte("invalid address format"))
+		return
+	}
+
+	// Claim only the requested denominations of send, if any.
+	coins := f.config.Send
+	if denoms := r.Form["denom"]; len(denoms) > 0 {
+		coins = std.Coins{}
+		for _, denom := range denoms {
+			if amount := f.config.Send.AmountOf(denom); amount > 0 {
+				coins = coins.Add(std.Coins{std.NewCoin(denom, amount)})
+			}
+		}
+		if coins.IsZero() {
+			f.Logger.Info("no denom found", "ip", ip, "denoms", denoms)
+			w.Write([]byte("no denom found"))
+			return
+		}
+	}
+
+	claim := &Claim{
+		Request: r,
+		IP:      ip,
+		Address: toAddr,
+		Coins:   coins,
+	}
+	err = f.handler(claim)
+	if err != nil {
+		if perr, ok := err.(payoutError); ok {
+			f.Metrics.addClaim(resultFailed)
+			f.Logger.Error("faucet failed", "ip", ip, "addr", toAddr, "err", perr.cause)
+		} else {
+			f.Metrics.addClaim(resultRejected)
+			f.Logger.Info("claim rejected", "ip", ip, "addr", toAddr, "err", err)
+		}
+		w.Write([]byte(err.Error()))
+		return
+	}
+	f.Metrics.addClaim(resultSuccess)
+	f.Logger.Info("faucet success", "ip", ip, "addr", toAddr, "coins", claim.Coins)
+	w.Write([]byte("faucet success"))
+}
+
+// Returns the IP of the claimant of r.
+func (f *Faucet) remoteIP(r *http.Request) net.IP {
+	host := ""
+	if !f.config.BehindProxy {
+		host_, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return nil
+		}
+		host = host_
+	} else if xff, found := r.Header["X-Forwarded-For"]; found && len(xff) > 0 {
+		host = xff[0]
+	}
+
+	// if can't identify the IP, everyone is in the same pool.
+	// if host using ipv6 loopback addr, make it ipv4
+	if host == "" || host == "::1" || host == "0:0:0:0:0:0:0:1" {
+		host = "127.0.0.1"
+	}
+	return net.ParseIP(host)
+}
+
+// payoutError is the error of a failed payout, whose cause is not shown to
+// the claimant.
+type payoutError struct {
+	cause error
+}
+
+func (payoutError) Error() string {
+	return "faucet failed"
+}
+
+// The innermost ClaimHandler.
+func (f *Faucet) pay(claim *Claim) error {
+	if err := f.queue.Pay(claim.Address, claim.Coins); err != nil {
+		return payoutError{err}
+	}
+	return nil
+}
+
+//----------------------------------------
+// Transactions
+
+// Pays out outputs in a multisend tx.
+func (f *Faucet) sendBatch(outputs []bank.Output) error {
+	total := std.Coins{}
+	for _, output := range outputs {
+		total = total.Add(output.Coins)
+	}
+	msg := bank.NewMsgMultiSend([]bank.Input{bank.NewInput(f.Address(), total)}, outputs)
+	b := client.NewTxBuilder(f.config.ChainID)
+	b.SetMsgs(msg)
+	b.SetGasWanted(f.config.GasWanted * int64(len(outputs)))
+	b.SetGasFee(f.config.GasFee)
+	b.SetMemo(f.config.Memo)
+	err := f.broadcastTx(b)
+	if err != nil {
+		f.Metrics.addTx(resultFailed, len(outputs), total)
+		// the sequence is incremented by failed deliveries.
+		if err2 := f.querySequence(); err2 != nil {
+			f.Logger.Error("querying sequence", "err", err2)
+		}
+		return err
+	}
+	f.Metrics.addTx(resultSuccess, len(outputs), total)
+	f.sequence += 1
+	return nil
+}
+
+// Queries the account number and sequence of the funds.
+func (f *Faucet) querySequence() error {
+	path := fmt.Sprintf("auth/accounts/%s", f.Address().String())
+	data := []byte(nil)
+	opts2 := rpcclient.ABCIQueryOptions{
+		// Height: height, XXX
+		// Prove: false, XXX
+	}
+	qres, err := f.cli.ABCIQueryWithOptions(
+		path, data, opts2)
+	if err != nil {
+		return errors.Wrap(err, "querying")
+	}
+	if qres.Response.Error != nil {
+		return errors.Wrap(qres.Response.Error, "querying: %s", qres.Response.Log)
+	}
+	resdata := qres.Response.Data
+	var acc gnoland.GnoAccount
+	amino.MustUnmarshalJSON(resdata, &acc)
+	f.accountNumber = acc.BaseAccount.AccountNumber
+	f.sequence = acc.BaseAccount.Sequence
+	return nil
+}
+
+// Signs the tx of b with the backend, and broadcasts it.
+func (f *Faucet) broadcastTx(b *client.TxBuilder) error {
+	pubKey := f.backend.PubKey()
+	signers := b.Signers()
+	if len(signers) != 1 || signers[0] != pubKey.Address() {
+		return errors.New("addr %v not the only signer", pubKey.Address())
+	}
+	b.SetSignerInfo(client.SignerInfo{
+		Address:       pubKey.Address(),
+		AccountNumber: f.accountNumber,
+		Sequence:      f.sequence,
+	})
+
+	// get sign-bytes and make signature.
+	signbz, err := b.SignBytes(pubKey.Address())
+	if err != nil {
+		return err
+	}
+	sig, err := f.backend.Sign(signbz)
+	if err != nil {
+		return err
+	}
+	if err := b.AddSignature(pubKey, sig); err != nil {
+		return err
+	}
+	f.Logger.Debug("will deliver", "tx", string(amino.MustMarshalJSON(b.Tx())))
+
+	// construct tx serialized bytes.
+	txbz, err := b.Marshal()
+	if err != nil {
+		return err
+	}
+
+	// broadcast tx bytes.
+	bres, err := f.cli.BroadcastTxCommit(txbz)
+	if err != nil {
+		return errors.Wrap(err, "broadcasting bytes")
+	}
+	if bres.CheckTx.IsErr() {
+		return errors.New("transaction failed %#v\nlog %s", bres, bres.CheckTx.Log)
+	} else if bres.DeliverTx.IsErr() {
+		return errors.New("transaction failed %#v\nlog %s", bres, bres.DeliverTx.Log)
+	}
+	f.Logger.Info("delivered", "hash", fmt.Sprintf("%X", bres.TxHash),
+		"gas_wanted", bres.DeliverTx.GasWanted, "gas_used", bres.DeliverTx.GasUsed)
+	return nil
+}
@@ -0,0 +1,82 @@
+package faucet
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// results of claims and transactions.
+const (
+	resultSuccess  = "success"
+	resultRejected = "rejected" // by a middleware
+	resultFailed   = "failed"
+)
+
+// Metrics counts the claims and payouts of a faucet, and serves them in
+// the text format of Prometheus.
+type Metrics struct {
+	mtx     sync.Mutex
+	claims  map[string]uint64 // result -> count
+	txs     map[string]uint64 // result -> count
+	payouts uint64
+	paid    std.Coins
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		claims: make(map[string]uint64),
+		txs:    make(map[string]uint64),
+		paid:   std.Coins{},
+	}
+}
+
+func (m *Metrics) addClaim(result string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.claims[result]++
+}
+
+func (m *Metrics) addTx(result string, payouts int, paid std.Coins) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.txs[result]++
+	if result == resultSuccess {
+		m.payouts += uint64(payouts)
+		m.paid = m.paid.Add(paid)
+	}
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writeCounters(w, "faucet_claims_total", "Claims, by result.", "result", m.claims)
+	writeCounters(w, "faucet_txs_total", "Payout transactions, by result.", "result", m.txs)
+	fmt.Fprintf(w, "# HELP faucet_payouts_total Claims paid out.\n")
+	fmt.Fprintf(w, "# TYPE faucet_payouts_total counter\n")
+	fmt.Fprintf(w, "faucet_payouts_total %d\n", m.payouts)
+	paid := make(map[string]uint64, len(m.paid))
+	for _, coin := range m.paid {
+		paid[coin.Denom] = uint64(coin.Amount)
+	}
+	writeCounters(w, "faucet_paid_total", "Coins paid out, by denom.", "denom", paid)
+}
+
+// Writes the counter name, with a value for each label value.
+func writeCounters(w http.ResponseWriter, name, help, label string, values map[string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{%s=%q} %d\n", name, label, key, values[key])
+	}
+}
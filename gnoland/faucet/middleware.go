@@ -0,0 +1,79 @@
+package faucet
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Claim is a request for coins of the faucet.
+type Claim struct {
+	Request *http.Request
+	IP      net.IP
+	Address crypto.Address // of the claimant
+	Coins   std.Coins      // to be sent
+}
+
+// A ClaimHandler pays out a claim, or returns an error, shown to the
+// claimant, if it is rejected or its payout fails.
+type ClaimHandler func(claim *Claim) error
+
+// A Middleware validates claims before they are handled by next, e.g. to
+// protect the faucet from abuse.  A middleware may modify the claim, e.g.
+// its coins.
+type Middleware func(next ClaimHandler) ClaimHandler
+
+// Throttle rejects the claims of subnets with too many recent claims.
+func Throttle(st *SubnetThrottler) Middleware {
+	return func(next ClaimHandler) ClaimHandler {
+		return func(claim *Claim) error {
+			allowed, reason := st.Request(claim.IP)
+			if !allowed {
+				return errors.New("abuse protection system (%s)", reason)
+			}
+			return next(claim)
+		}
+	}
+}
+
+// Captcha rejects the claims without a valid captcha response, verified
+// with secret by provider.
+func Captcha(provider CaptchaProvider, secret string) Middleware {
+	return func(next ClaimHandler) ClaimHandler {
+		return func(claim *Claim) error {
+			passedMsg := claim.Request.Form[provider.Field]
+			if passedMsg == nil {
+				return errors.New("check captcha request")
+			}
+			capMsg := strings.TrimSpace(passedMsg[0])
+			if err := provider.Verify(secret, capMsg, claim.IP.String()); err != nil {
+				return errors.New("Unauthorized")
+			}
+			return next(claim)
+		}
+	}
+}
+
+// Quota rejects the claims of addresses or IPs which reached their daily
+// limit of qs.  The claims whose payout fails are not counted.
+func Quota(qs *QuotaStore) Middleware {
+	return func(next ClaimHandler) ClaimHandler {
+		return func(claim *Claim) error {
+			now := time.Now()
+			allowed, reason := qs.Reserve(claim.Address, claim.IP.String(), now)
+			if !allowed {
+				return errors.New("quota exceeded (%s)", reason)
+			}
+			err := next(claim)
+			if err != nil {
+				qs.Release(claim.Address, claim.IP.String(), now)
+			}
+			return err
+		}
+	}
+}
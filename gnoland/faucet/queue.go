@@ -0,0 +1,92 @@
+package faucet
+
+import (
+	"time"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/service"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+type payout struct {
+	output bank.Output
+	done   chan error
+}
+
+// PayoutQueue batches the payouts requested within an interval, up to a
+// batch size, into a single multisend transaction.
+type PayoutQueue struct {
+	service.BaseService
+	payouts   chan payout
+	batchSize int
+	interval  time.Duration
+	send      func(outputs []bank.Output) error
+}
+
+// NewPayoutQueue returns a queue which pays out batches of outputs with
+// send.
+func NewPayoutQueue(batchSize int, interval time.Duration, send func(outputs []bank.Output) error) *PayoutQueue {
+	pq := &PayoutQueue{
+		payouts:   make(chan payout, batchSize),
+		batchSize: batchSize,
+		interval:  interval,
+		send:      send,
+	}
+	pq.BaseService = *service.NewBaseService(nil, "PayoutQueue", pq)
+	return pq
+}
+
+func (pq *PayoutQueue) OnStart() error {
+	pq.BaseService.OnStart()
+	go pq.routineBatch()
+	return nil
+}
+
+// Pay queues coins to be sent to addr, and waits for the result of the
+// transaction of its batch.
+func (pq *PayoutQueue) Pay(addr crypto.Address, coins std.Coins) error {
+	p := payout{
+		output: bank.NewOutput(addr, coins),
+		done:   make(chan error, 1),
+	}
+	pq.payouts <- p
+	return <-p.done
+}
+
+func (pq *PayoutQueue) routineBatch() {
+	for {
+		var batch []payout
+		select {
+		case <-pq.Quit():
+			return
+		case p := <-pq.payouts:
+			batch = append(batch, p)
+		}
+		// collect payouts until the batch is full, or the interval has
+		// elapsed since its first payout.
+		timer := time.NewTimer(pq.interval)
+	COLLECT:
+		for len(batch) < pq.batchSize {
+			select {
+			case <-pq.Quit():
+				timer.Stop()
+				return
+			case p := <-pq.payouts:
+				batch = append(batch, p)
+			case <-timer.C:
+				break COLLECT
+			}
+		}
+		timer.Stop()
+
+		outputs := make([]bank.Output, len(batch))
+		for i, p := range batch {
+			outputs[i] = p.output
+		}
+		err := pq.send(outputs)
+		for _, p := range batch {
+			p.done <- err
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package faucet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	dbm "github.com/gnolang/gno/pkgs/db"
+)
+
+// QuotaStore counts the requests of each address and IP per UTC day, in a
+// database that persists across restarts of the faucet.
+type QuotaStore struct {
+	mtx       sync.Mutex
+	db        dbm.DB
+	addrLimit int // 0 for no limit
+	ipLimit   int // 0 for no limit
+}
+
+func NewQuotaStore(db dbm.DB, addrLimit, ipLimit int) *QuotaStore {
+	return &QuotaStore{
+		db:        db,
+		addrLimit: addrLimit,
+		ipLimit:   ipLimit,
+	}
+}
+
+// Reserve counts a request of addr from ip at now, unless either has
+// reached its daily limit, in which case the reason is returned.
+func (qs *QuotaStore) Reserve(addr crypto.Address, ip string, now time.Time) (allowed bool, reason string) {
+	qs.mtx.Lock()
+	defer qs.mtx.Unlock()
+
+	day := quotaDay(now)
+	addrKey, ipKey := quotaAddrKey(addr), quotaIPKey(ip)
+	addrCount, ipCount := qs.count(addrKey, day), qs.count(ipKey, day)
+	if qs.addrLimit > 0 && addrCount >= qs.addrLimit {
+		return false, fmt.Sprintf("address reached its daily limit of %d", qs.addrLimit)
+	}
+	if qs.ipLimit > 0 && ipCount >= qs.ipLimit {
+		return false, fmt.Sprintf("ip reached its daily limit of %d", qs.ipLimit)
+	}
+	qs.set(addrKey, day, addrCount+1)
+	qs.set(ipKey, day, ipCount+1)
+	return true, ""
+}
+
+// Release uncounts a request reserved at now, e.g. if its payout failed.
+func (qs *QuotaStore) Release(addr crypto.Address, ip string, now time.Time) {
+	qs.mtx.Lock()
+	defer qs.mtx.Unlock()
+
+	day := quotaDay(now)
+	for _, key := range [][]byte{quotaAddrKey(addr), quotaIPKey(ip)} {
+		if count := qs.count(key, day); count > 0 {
+			qs.set(key, day, count-1)
+		}
+	}
+}
+
+// Returns the count of key on day, which is reset each day.
+func (qs *QuotaStore) count(key []byte, day string) int {
+	bz := qs.db.Get(key)
+	if bz == nil {
+		return 0
+	}
+	parts := strings.SplitN(string(bz), " ", 2)
+	if len(parts) != 2 || parts[0] != day {
+		return 0
+	}
+	count, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+func (qs *QuotaStore) set(key []byte, day string, count int) {
+	qs.db.SetSync(key, []byte(fmt.Sprintf("%s %d", day, count)))
+}
+
+func quotaDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func quotaAddrKey(addr crypto.Address) []byte {
+	return []byte("addr/" + addr.String())
+}
+
+func quotaIPKey(ip string) []byte {
+	return []byte("ip/" + ip)
+}
@@ -0,0 +1,43 @@
+package faucet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	dbm "github.com/gnolang/gno/pkgs/db"
+)
+
+func TestQuotaStore(t *testing.T) {
+	addr1 := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	day1 := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	qs := NewQuotaStore(dbm.NewMemDB(), 2, 3)
+
+	allowed, _ := qs.Reserve(addr1, "1.2.3.4", day1)
+	require.True(t, allowed)
+	allowed, _ = qs.Reserve(addr1, "1.2.3.4", day1)
+	require.True(t, allowed)
+	allowed, reason := qs.Reserve(addr1, "5.6.7.8", day1)
+	require.False(t, allowed)
+	require.Contains(t, reason, "address")
+
+	// a released request is not counted.
+	qs.Release(addr1, "1.2.3.4", day1)
+	allowed, _ = qs.Reserve(addr1, "1.2.3.4", day1)
+	require.True(t, allowed)
+
+	// ip limit.
+	allowed, _ = qs.Reserve(addr2, "1.2.3.4", day1)
+	require.True(t, allowed)
+	allowed, reason = qs.Reserve(addr2, "1.2.3.4", day1)
+	require.False(t, allowed)
+	require.Contains(t, reason, "ip")
+
+	// quotas are reset each day.
+	allowed, _ = qs.Reserve(addr1, "1.2.3.4", day2)
+	require.True(t, allowed)
+}
@@ -0,0 +1,127 @@
+package gnoland
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/log"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/sdk/simulation"
+	"github.com/gnolang/gno/pkgs/sdk/valset"
+	"github.com/gnolang/gno/pkgs/sdk/vm"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// A failed simulation is reproduced with the seed of its error, e.g.
+//
+//	go test ./gnoland -run TestSimulation -simulation.seed 42 -v
+var (
+	simulationSeed   = flag.Int64("simulation.seed", 1, "seed of the simulation")
+	simulationBlocks = flag.Int("simulation.blocks", 30, "number of blocks of the simulation")
+)
+
+const simulationRealm = "gno.land/r/simcounter"
+
+const simulationRealmFile = `package simcounter
+
+var count int
+
+func Incr(n int) int {
+	count += n
+	return count
+}
+`
+
+var simulationCoins = std.NewCoins(std.NewCoin("ugnot", 100000000))
+
+// Returns the app of a simulation, whose accounts start with
+// simulationCoins, and whose first account creates simulationRealm at
+// genesis.
+func newSimulationApp(t *testing.T, params simulation.Params) simulation.App {
+	t.Helper()
+
+	app, err := newApp(dbm.NewMemDB(), false, log.NewNopLogger())
+	require.NoError(t, err)
+
+	supply := std.Coins{}
+	for i := 0; i < params.NumAccounts; i++ {
+		supply = supply.Add(simulationCoins)
+	}
+	invariants := simulation.NewInvariantRegistry()
+	bank.RegisterInvariants(invariants, app.acctKpr)
+	bank.RegisterSupplyInvariant(invariants, app.acctKpr, supply)
+
+	return simulation.App{
+		BaseApp:       app.BaseApp,
+		AccountKeeper: app.acctKpr,
+		AppState: func(r *rand.Rand, accs []simulation.Account) interface{} {
+			balances := make([]string, len(accs))
+			for i, acc := range accs {
+				balances[i] = fmt.Sprintf("%s=%s", acc.Address, simulationCoins)
+			}
+			tx := std.Tx{
+				Msgs: []std.Msg{vm.NewMsgAddPackage(accs[0].Address, simulationRealm, []*std.MemFile{
+					{Name: "simcounter.gno", Body: simulationRealmFile},
+				})},
+				Fee:        params.Fee,
+				Signatures: make([]std.Signature, 1),
+			}
+			return GnoGenesisState{
+				Balances: balances,
+				Txs:      []std.Tx{tx},
+			}
+		},
+		Operations: simulation.WeightedOperations{
+			{Weight: 10, Op: bank.SimulateMsgSend(app.bankKpr)},
+			{Weight: 5, Op: vm.SimulateMsgCall(app.bankKpr, simulationRealm, "Incr", func(r *rand.Rand) []string {
+				return []string{strconv.Itoa(r.Intn(100))}
+			})},
+			{Weight: 1, Op: valset.SimulateMsgRotateKey()},
+		},
+		Invariants: invariants,
+	}
+}
+
+// Runs the VMKeeper with the stdlibs of the repository.
+func chdirRepository(t *testing.T) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(filepath.Join("..")))
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestSimulation(t *testing.T) {
+	chdirRepository(t)
+	params := simulation.DefaultParams(*simulationSeed)
+	params.NumBlocks = *simulationBlocks
+	out := new(bytes.Buffer)
+	res, err := simulation.Simulate(out, newSimulationApp(t, params), params)
+	t.Log(out.String())
+	require.NoError(t, err)
+	require.NotZero(t, res.Ops["bank/send"])
+	require.NotZero(t, res.Ops["vm/exec"])
+}
+
+// The simulation of the app is reproduced by its seed.
+func TestSimulationSeed(t *testing.T) {
+	chdirRepository(t)
+	params := simulation.DefaultParams(*simulationSeed)
+	params.NumBlocks = 10
+	res, err := simulation.Simulate(new(bytes.Buffer), newSimulationApp(t, params), params)
+	require.NoError(t, err)
+	res2, err := simulation.Simulate(new(bytes.Buffer), newSimulationApp(t, params), params)
+	require.NoError(t, err)
+	require.Equal(t, res.AppHash, res2.AppHash)
+	require.Equal(t, res.Ops, res2.Ops)
+}
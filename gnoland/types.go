@@ -1,6 +1,7 @@
 package gnoland
 
 import (
+	"github.com/gnolang/gno"
 	"github.com/gnolang/gno/pkgs/std"
 )
 
@@ -12,7 +13,27 @@ func ProtoGnoAccount() std.Account {
 	return &GnoAccount{}
 }
 
+// GnoGenesisState is the state of the app at genesis: the state of the
+// stores exported from another chain if any, followed by the states of
+// modules, balances and txs, and then the states of realms exported from
+// another chain, which replace those of the realms added by the txs.
 type GnoGenesisState struct {
-	Balances []string `json:"balances"`
-	Txs      []std.Tx `json:"txs"`
+	Balances []string         `json:"balances"`
+	Txs      []std.Tx         `json:"txs"`
+	Stores   []GenesisStore   `json:"stores,omitempty"`
+	Modules  []GenesisModule  `json:"modules,omitempty"`
+	Realms   []gno.RealmState `json:"realms,omitempty"`
+}
+
+// GenesisModule is the state of a module of the app, by name, e.g. an
+// authz.GenesisState.
+type GenesisModule struct {
+	Name  string      `json:"name"`
+	State interface{} `json:"state"`
+}
+
+// GenesisStore is the state of a store of the app, by name.
+type GenesisStore struct {
+	Name  string       `json:"name"`
+	Pairs []std.KVPair `json:"pairs"`
 }
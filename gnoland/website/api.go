@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/sdk/vm"
+	"github.com/gorilla/mux"
+	"github.com/gotuna/gotuna"
+)
+
+// apiRealmResult is the response of /api/realm/REALM.
+type apiRealmResult struct {
+	Realm  string   `json:"realm"`
+	Func   string   `json:"func"`
+	Args   []string `json:"args"`
+	Result string   `json:"result"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// Serves the result of a function of a realm as JSON, e.g.
+//
+//	/api/realm/boards?func=Render&args=gnolang
+//	/api/realm/boards?func=GetBoardIDFromName&args=gnolang
+//
+// Render() is served like /r/REALM:QUERY, from the render cache.  Other
+// functions are evaluated with vm/qeval, and their result is the printed
+// value of the call, e.g. `(1 gno.land/r/boards.BoardID)`.
+func handlerAPIRealm(app gotuna.App) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		rlmpath := "gno.land/r/" + vars["rlmname"]
+		query := r.URL.Query()
+		funcName := query.Get("func")
+		if funcName == "" {
+			funcName = "Render"
+		}
+		args := query["args"]
+		if args == nil {
+			args = []string{}
+		}
+
+		if funcName == "Render" {
+			if len(args) > 1 {
+				writeAPIError(w, http.StatusBadRequest, fmt.Errorf("Render takes 1 argument, got %d", len(args)))
+				return
+			}
+			querystr := ""
+			if len(args) == 1 {
+				querystr = args[0]
+			}
+			res, err := renders.render(rlmpath, querystr)
+			if err != nil {
+				writeAPIError(w, http.StatusInternalServerError, err)
+				return
+			}
+			writeAPIResult(w, apiRealmResult{rlmpath, funcName, args, string(res)})
+			return
+		}
+
+		// Look up the function, to quote its arguments.
+		res, err := makeRequest("vm/qfuncs", []byte(rlmpath))
+		if err != nil {
+			writeAPIError(w, http.StatusNotFound, err)
+			return
+		}
+		var fsigs vm.FunctionSignatures
+		amino.MustUnmarshalJSON(res.Data, &fsigs)
+		var fsig *vm.FunctionSignature
+		for i := range fsigs {
+			if fsigs[i].FuncName == funcName {
+				fsig = &fsigs[i]
+				break
+			}
+		}
+		if fsig == nil {
+			writeAPIError(w, http.StatusNotFound, fmt.Errorf("function %q not declared in %s", funcName, rlmpath))
+			return
+		}
+		if len(args) != len(fsig.Params) {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("%s takes %d argument(s), got %d", funcName, len(fsig.Params), len(args)))
+			return
+		}
+		exprs := make([]string, len(args))
+		for i, arg := range args {
+			exprs[i] = argExpr(fsig.Params[i].Type, arg)
+		}
+		expr := fmt.Sprintf("%s(%s)", funcName, strings.Join(exprs, ", "))
+		res, err = makeRequest("vm/qeval", []byte(rlmpath+"\n"+expr))
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeAPIResult(w, apiRealmResult{rlmpath, funcName, args, string(res.Data)})
+	})
+}
+
+var reNumber = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Returns the expression of an argument of type typ.  Numbers and
+// booleans are literals, unless typ is string, and anything else is a
+// quoted string.
+func argExpr(typ string, arg string) string {
+	if typ != "string" {
+		if arg == "true" || arg == "false" {
+			return arg
+		}
+		if reNumber.MatchString(arg) {
+			return arg
+		}
+	}
+	return strconv.Quote(arg)
+}
+
+func writeAPIResult(w http.ResponseWriter, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiError{err.Error()})
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	"github.com/gnolang/gno/pkgs/sdk/vm"
+	"github.com/gorilla/websocket"
+)
+
+// renderCache caches the results of the Render() of realms, by realm path
+// and query string.  The entries of a realm are invalidated when a block
+// calls into the realm, at which point the new results are pushed to the
+// subscribers of the realm.
+type renderCache struct {
+	mtx     sync.Mutex
+	entries map[string]map[string][]byte        // rlmpath -> querystr -> result
+	gens    map[string]uint64                   // rlmpath -> generation
+	subs    map[string]map[string][]*subscriber // rlmpath -> querystr -> subscribers
+	enabled bool
+}
+
+func newRenderCache(enabled bool) *renderCache {
+	return &renderCache{
+		entries: make(map[string]map[string][]byte),
+		gens:    make(map[string]uint64),
+		subs:    make(map[string]map[string][]*subscriber),
+		enabled: enabled,
+	}
+}
+
+// render returns the result of Render(querystr) of the realm at rlmpath,
+// from the cache if possible.  Errors are not cached.
+func (rc *renderCache) render(rlmpath, querystr string) ([]byte, error) {
+	rc.mtx.Lock()
+	if res, ok := rc.entries[rlmpath][querystr]; ok {
+		rc.mtx.Unlock()
+		return res, nil
+	}
+	gen := rc.gens[rlmpath]
+	rc.mtx.Unlock()
+
+	qpath := "vm/qrender"
+	data := []byte(fmt.Sprintf("%s\n%s", rlmpath, querystr))
+	res, err := makeRequest(qpath, data)
+	if err != nil {
+		return nil, err
+	}
+	if !rc.enabled {
+		return res.Data, nil
+	}
+
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	// Do not cache a result rendered before an invalidation.
+	if rc.gens[rlmpath] == gen {
+		if rc.entries[rlmpath] == nil {
+			rc.entries[rlmpath] = make(map[string][]byte)
+		}
+		rc.entries[rlmpath][querystr] = res.Data
+	}
+	return res.Data, nil
+}
+
+// invalidate drops the cached results of the realm at rlmpath, and pushes
+// its new results to its subscribers.
+func (rc *renderCache) invalidate(rlmpath string) {
+	rc.mtx.Lock()
+	delete(rc.entries, rlmpath)
+	rc.gens[rlmpath]++
+	querystrs := make([]string, 0, len(rc.subs[rlmpath]))
+	for querystr := range rc.subs[rlmpath] {
+		querystrs = append(querystrs, querystr)
+	}
+	rc.mtx.Unlock()
+
+	for _, querystr := range querystrs {
+		res, err := rc.render(rlmpath, querystr)
+		if err != nil {
+			fmt.Printf("Error rendering %s:%s: %v\n", rlmpath, querystr, err)
+			continue
+		}
+		rc.mtx.Lock()
+		for _, sub := range rc.subs[rlmpath][querystr] {
+			sub.push(res)
+		}
+		rc.mtx.Unlock()
+	}
+}
+
+//----------------------------------------
+// Watcher
+
+// watchRealms polls the remote node every interval for new blocks, and
+// invalidates the realms called by their transactions.  It starts from the
+// latest block, and never returns.
+func (rc *renderCache) watchRealms(interval time.Duration) {
+	cli := client.NewHTTP(flags.remoteAddr, "/websocket")
+	var last int64
+	started := false
+	for ; ; time.Sleep(interval) {
+		status, err := cli.Status()
+		if err != nil {
+			fmt.Printf("Error watching realms: %v\n", err)
+			continue
+		}
+		latest := status.SyncInfo.LatestBlockHeight
+		if !started || latest < last {
+			// First poll, or the chain was reset.
+			started = true
+			last = latest
+			rc.invalidateAll()
+			continue
+		}
+		for last < latest {
+			height := last + 1
+			res, err := cli.BlockResults(&height)
+			if err != nil {
+				fmt.Printf("Error watching realms at height %d: %v\n", height, err)
+				break
+			}
+			for rlmpath := range calledRealms(res.Results.DeliverTxs) {
+				rc.invalidate(rlmpath)
+			}
+			last = height
+		}
+	}
+}
+
+// invalidateAll invalidates all realms with cached results or subscribers.
+func (rc *renderCache) invalidateAll() {
+	rc.mtx.Lock()
+	rlmpaths := make(map[string]struct{})
+	for rlmpath := range rc.entries {
+		rlmpaths[rlmpath] = struct{}{}
+	}
+	for rlmpath := range rc.subs {
+		rlmpaths[rlmpath] = struct{}{}
+	}
+	rc.mtx.Unlock()
+
+	for rlmpath := range rlmpaths {
+		rc.invalidate(rlmpath)
+	}
+}
+
+// calledRealms returns the set of realm paths called by the transactions of
+// a block, from their vm.RealmCallEvent events.
+func calledRealms(dtxs []abci.ResponseDeliverTx) map[string]struct{} {
+	rlmpaths := make(map[string]struct{})
+	for _, dtx := range dtxs {
+		for _, ev := range dtx.Events {
+			if rce, ok := ev.(vm.RealmCallEvent); ok {
+				rlmpaths[rce.PkgPath] = struct{}{}
+			}
+		}
+	}
+	return rlmpaths
+}
+
+//----------------------------------------
+// Subscribers
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// subscriber is a websocket connection, to which the results of the
+// Render() of a realm are pushed.
+type subscriber struct {
+	conn *websocket.Conn
+	send chan []byte // latest result not yet written
+}
+
+// push sends res to sub, replacing any result not yet written.
+func (sub *subscriber) push(res []byte) {
+	for {
+		select {
+		case sub.send <- res:
+			return
+		default:
+			select {
+			case <-sub.send:
+			default:
+			}
+		}
+	}
+}
+
+// writeLoop writes the pushed results to the connection, until it is
+// closed.
+func (sub *subscriber) writeLoop() {
+	for res := range sub.send {
+		sub.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := sub.conn.WriteMessage(websocket.TextMessage, res); err != nil {
+			return
+		}
+	}
+}
+
+// subscribe serves conn the results of Render(querystr) of the realm at
+// rlmpath, until the connection is closed by the browser.
+func (rc *renderCache) subscribe(conn *websocket.Conn, rlmpath, querystr string) {
+	sub := &subscriber{
+		conn: conn,
+		send: make(chan []byte, 1),
+	}
+	rc.mtx.Lock()
+	if rc.subs[rlmpath] == nil {
+		rc.subs[rlmpath] = make(map[string][]*subscriber)
+	}
+	rc.subs[rlmpath][querystr] = append(rc.subs[rlmpath][querystr], sub)
+	rc.mtx.Unlock()
+
+	go sub.writeLoop()
+	// Discard messages from the browser, to detect the closing of the
+	// connection.
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	rc.mtx.Lock()
+	subs := rc.subs[rlmpath][querystr]
+	for i, s := range subs {
+		if s == sub {
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) == 0 {
+		delete(rc.subs[rlmpath], querystr)
+		if len(rc.subs[rlmpath]) == 0 {
+			delete(rc.subs, rlmpath)
+		}
+	} else {
+		rc.subs[rlmpath][querystr] = subs
+	}
+	rc.mtx.Unlock()
+	close(sub.send)
+	conn.Close()
+}
@@ -37,9 +37,14 @@ var flags struct {
 	helpChainID     string
 	helpRemote      string
 	homeContentFile string
+	renderCache     bool
+	renderPoll      time.Duration
 }
 
-var startedAt time.Time
+var (
+	startedAt time.Time
+	renders   *renderCache
+)
 
 func init() {
 	flag.StringVar(&flags.remoteAddr, "remote", "127.0.0.1:26657", "remote gnoland node address")
@@ -50,12 +55,20 @@ func init() {
 	flag.StringVar(&flags.homeContentFile, "home-content", "./gnoland/website/HOME.md", "home content filepath")
 	flag.StringVar(&flags.helpChainID, "help-chainid", "dev", "help page's chainid")
 	flag.StringVar(&flags.helpRemote, "help-remote", "127.0.0.1:26657", "help page's remote addr")
+	flag.BoolVar(&flags.renderCache, "render-cache", true, "cache realm renders until the realm is called")
+	flag.DurationVar(&flags.renderPoll, "render-poll", time.Second, "interval of polling for new blocks, to invalidate renders and push them to browsers (0 disables live-reload)")
 	startedAt = time.Now()
 }
 
 func main() {
 	flag.Parse()
 
+	// Without polling, cached renders would never be invalidated.
+	renders = newRenderCache(flags.renderCache && flags.renderPoll > 0)
+	if flags.renderPoll > 0 {
+		go renders.watchRealms(flags.renderPoll)
+	}
+
 	app := gotuna.App{
 		ViewFiles: os.DirFS(flags.viewsDir),
 		Router:    gotuna.NewMuxRouter(),
@@ -71,6 +84,9 @@ func main() {
 	app.Router.Handle("/r/{rlmname:[a-z][a-z0-9_]*}:{querystr:.*}", handlerRealmRender(app))
 	app.Router.Handle("/r/{rlmname:[a-z][a-z0-9_]*}/{filename:.*}", handlerRealmFile(app))
 	app.Router.Handle("/p/{filepath:.*}", handlerPackageFile(app))
+	app.Router.Handle("/api/realm/{rlmname:[a-z][a-z0-9_]*}", handlerAPIRealm(app))
+	app.Router.Handle("/ws/r/{rlmname:[a-z][a-z0-9_]*}", handlerRealmRenderWS(app))
+	app.Router.Handle("/ws/r/{rlmname:[a-z][a-z0-9_]*}:{querystr:.*}", handlerRealmRenderWS(app))
 	app.Router.Handle("/static/{path:.+}", handlerStaticFile(app))
 	app.Router.Handle("/favicon.ico", handlerFavicon(app))
 	app.Router.Handle("/status.json", handlerStatusJSON(app))
@@ -232,13 +248,11 @@ func handleRealmRender(app gotuna.App, w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/r/"+rlmname, http.StatusFound)
 		return
 	}
-	qpath := "vm/qrender"
-	data := []byte(fmt.Sprintf("%s\n%s", rlmpath, querystr))
-	res, err := makeRequest(qpath, data)
+	contents, err := renders.render(rlmpath, querystr)
 	if err != nil {
 		// XXX hack
 		if strings.Contains(err.Error(), "Render not declared") {
-			res.Data = []byte("realm package has no Render() function")
+			contents = []byte("realm package has no Render() function")
 		} else {
 			writeError(w, err)
 			return
@@ -260,10 +274,30 @@ func handleRealmRender(app gotuna.App, w http.ResponseWriter, r *http.Request) {
 	tmpl.Set("RealmPath", rlmpath)
 	tmpl.Set("Query", string(querystr))
 	tmpl.Set("PathLinks", pathLinks)
-	tmpl.Set("Contents", string(res.Data))
+	tmpl.Set("Contents", string(contents))
+	tmpl.Set("LiveReload", flags.renderPoll > 0)
 	tmpl.Render(w, r, "realm_render.html", "header.html")
 }
 
+// Pushes the renders of /r/REALM:QUERY to the browser over a websocket,
+// each time the realm is called.
+func handlerRealmRenderWS(app gotuna.App) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if flags.renderPoll <= 0 {
+			handleNotFound(app, r.URL.Path, w, r)
+			return
+		}
+		vars := mux.Vars(r)
+		rlmpath := "gno.land/r/" + vars["rlmname"]
+		querystr := vars["querystr"]
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return // error already written by Upgrade.
+		}
+		renders.subscribe(conn, rlmpath, querystr)
+	})
+}
+
 func handlerRealmFile(app gotuna.App) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
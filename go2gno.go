@@ -318,6 +318,20 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 		return &DeferStmt{
 			Call: *cx,
 		}
+	case *ast.GoStmt:
+		cx := toExpr(fs, gon.Call).(*CallExpr)
+		return &GoStmt{
+			Call: *cx,
+		}
+	case *ast.SelectStmt:
+		return &SelectStmt{
+			Cases: toSelectCases(fs, gon.Body.List),
+		}
+	case *ast.SendStmt:
+		return &SendStmt{
+			Chan:  toExpr(fs, gon.Chan),
+			Value: toExpr(fs, gon.Value),
+		}
 	case *ast.ExprStmt:
 		if cx, ok := gon.X.(*ast.CallExpr); ok {
 			if ix, ok := cx.Fun.(*ast.Ident); ok && ix.Name == "panic" {
@@ -779,6 +793,20 @@ func toClauses(fs *token.FileSet, csz []ast.Stmt) []SwitchClauseStmt {
 	return res
 }
 
+func toSelectCases(fs *token.FileSet, csz []ast.Stmt) []SelectCaseStmt {
+	res := make([]SelectCaseStmt, 0, len(csz))
+	for _, cs := range csz {
+		cc := cs.(*ast.CommClause)
+		scase := SelectCaseStmt{
+			Comm: toSimp(fs, cc.Comm),
+			Body: toStmts(fs, cc.Body),
+		}
+		setLoc(fs, cc.Pos(), &scase)
+		res = append(res, scase)
+	}
+	return res
+}
+
 func toSwitchClauseStmt(fs *token.FileSet, cc *ast.CaseClause) SwitchClauseStmt {
 	return SwitchClauseStmt{
 		Cases: toExprs(fs, cc.List),
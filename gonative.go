@@ -3,6 +3,7 @@ package gno
 import (
 	"fmt"
 	"reflect"
+	"sort"
 )
 
 // NOTE
@@ -654,12 +655,26 @@ func go2GnoValueUpdate(alloc *Allocator, rlm *Realm, lvl int, tv *TypedValue, rv
 			// Continue
 			head = head.Next
 		}
-		// Add remaining items from rv2 to map.
+		// Add remaining items from rv2 to map, sorted by map key
+		// since Go map iteration order is random.
+		type mapItem struct {
+			kmk MapKey
+			ktv TypedValue
+			vrv reflect.Value
+		}
+		items := make([]mapItem, 0, rv2.Len())
 		rv2i := rv2.MapRange()
 		for rv2i.Next() {
-			k, v := rv2i.Key(), rv2i.Value()
-			ktv := go2GnoValue(alloc, k)
-			vtv := go2GnoValue(alloc, v)
+			ktv := go2GnoValue(alloc, rv2i.Key())
+			kmk := ktv.ComputeMapKey(nil, false)
+			items = append(items, mapItem{kmk, ktv, rv2i.Value()})
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].kmk < items[j].kmk
+		})
+		for _, item := range items {
+			ktv := item.ktv
+			vtv := go2GnoValue(alloc, item.vrv)
 			ptr := mv.GetPointerForKey(alloc, nil, &ktv)
 			if debug {
 				if !ptr.TV.IsUndefined() {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gnolang/gno/pkgs/errors"
 	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
 )
 
 //----------------------------------------
@@ -38,6 +39,20 @@ type Machine struct {
 	CheckTypes bool // not yet used
 	ReadOnly   bool
 	MaxCycles  int64
+	MaxFrames  int         // limits call (and loop) depth
+	MaxValues  int         // limits value stack size
+	MaxExprs   int         // limits expression nesting
+	OpCounts   *[256]int64 // if not nil, counts of each op run
+	CallTracer CallTracer  // if not nil, traces realm calls
+	RealmGuard RealmGuard  // if not nil, guards realm entries
+	GasMeter   store.GasMeter
+	GasConfig  GasConfig
+	NoBytecode bool        // if true, interpret all function bodies
+	Coverage   *Coverage   // if not nil, counts statements run
+	Debugger   *Debugger   // if not nil, debugs statements run
+	FileChecks []FileCheck // if not nil, checks preprocessed files
+	allocBytes int64       // allocated bytes already charged for gas
+	panicStack Stacktrace  // of the last panic; see PanicStacktrace
 
 	Output  io.Writer
 	Store   Store
@@ -65,9 +80,21 @@ type MachineOptions struct {
 	Output        io.Writer
 	Store         Store
 	Context       interface{}
-	Alloc         *Allocator // or see MaxAllocBytes.
-	MaxAllocBytes int64      // or 0 for no limit.
-	MaxCycles     int64      // or 0 for no limit.
+	Alloc         *Allocator     // or see MaxAllocBytes.
+	MaxAllocBytes int64          // or 0 for no limit.
+	MaxCycles     int64          // or 0 for no limit.
+	MaxFrames     int            // or 0 for no limit.
+	MaxValues     int            // or 0 for no limit.
+	MaxExprs      int            // or 0 for no limit.
+	OpCounts      *[256]int64    // or nil to not count ops.
+	CallTracer    CallTracer     // or nil to not trace calls.
+	RealmGuard    RealmGuard     // or nil to not guard realm entries.
+	GasMeter      store.GasMeter // or nil to not meter gas.
+	GasConfig     GasConfig      // if GasMeter is set.
+	NoBytecode    bool           // if true, interpret all function bodies.
+	Coverage      *Coverage      // or nil to not count statements.
+	Debugger      *Debugger      // or nil to not debug.
+	FileChecks    []FileCheck    // or nil to not check files.
 }
 
 func NewMachineWithOptions(opts MachineOptions) *Machine {
@@ -109,10 +136,26 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 		CheckTypes: checkTypes,
 		ReadOnly:   readOnly,
 		MaxCycles:  maxCycles,
+		MaxFrames:  opts.MaxFrames,
+		MaxValues:  opts.MaxValues,
+		MaxExprs:   opts.MaxExprs,
+		OpCounts:   opts.OpCounts,
+		CallTracer: opts.CallTracer,
+		RealmGuard: opts.RealmGuard,
+		GasMeter:   opts.GasMeter,
+		GasConfig:  opts.GasConfig,
+		NoBytecode: opts.NoBytecode,
+		Coverage:   opts.Coverage,
+		Debugger:   opts.Debugger,
+		FileChecks: opts.FileChecks,
 		Output:     output,
 		Store:      store,
 		Context:    context,
 	}
+	if alloc != nil {
+		// only charge gas for allocations by this machine.
+		_, mm.allocBytes = alloc.Status()
+	}
 	if pv != nil {
 		mm.SetActivePackage(pv)
 	}
@@ -379,6 +422,11 @@ func (m *Machine) runFiles(fns ...*FileNode) {
 		if debug {
 			debug.Printf("PREPROCESSED FILE: %v\n", fn)
 		}
+		for _, check := range m.FileChecks {
+			if err := check(fn); err != nil {
+				panic(err)
+			}
+		}
 		// After preprocessing, save blocknodes to store.
 		SaveBlockNodes(m.Store, fn)
 		// Make block for fn.
@@ -510,6 +558,68 @@ func (m *Machine) savePackageValuesAndTypes() {
 	}
 }
 
+// MachineSnapshot is the state of a machine and of its store at the time of
+// Snapshot(), to which it may be restored any number of times with
+// Restore().
+type MachineSnapshot struct {
+	store      *StoreSnapshot
+	pkgPath    string
+	context    interface{}
+	cycles     int64
+	allocBytes int64
+}
+
+// Snapshot saves the active package unless it is already, and returns a
+// snapshot of the machine and its store (see Store.Snapshot()).  Values
+// declared in a package after it is saved, e.g. by test files, are not
+// saved: such a package must not be saved before its snapshot.
+//
+// NOTE: the context is copied shallowly, e.g. the state of its banker is
+// not part of the snapshot.
+func (m *Machine) Snapshot() *MachineSnapshot {
+	if err := m.CheckEmpty(); err != nil {
+		panic(errors.Wrap(err, "snapshot when machine not empty"))
+	}
+	snap := &MachineSnapshot{
+		context:    m.Context,
+		cycles:     m.Cycles,
+		allocBytes: m.allocBytes,
+	}
+	if m.Package != nil {
+		if !m.Package.GetIsReal() {
+			m.savePackageValuesAndTypes()
+		}
+		snap.pkgPath = m.Package.PkgPath
+	}
+	snap.store = m.Store.Snapshot()
+	m.Restore(snap)
+	return snap
+}
+
+// Restore restores the machine and its store to snap, which must be a
+// snapshot of them.  The active package is gotten again from the store, and
+// the stacks of the machine are cleared, e.g. after a panic.
+func (m *Machine) Restore(snap *MachineSnapshot) {
+	m.Store.Restore(snap.store)
+	m.NumOps = 0
+	m.NumValues = 0
+	m.Exprs = m.Exprs[:0]
+	m.Stmts = m.Stmts[:0]
+	m.Blocks = m.Blocks[:0]
+	m.Frames = m.Frames[:0]
+	m.Exception = nil
+	m.panicStack = nil
+	m.NumResults = 0
+	m.Cycles = snap.cycles
+	m.allocBytes = snap.allocBytes
+	m.Context = snap.context
+	m.Package = nil
+	m.Realm = nil
+	if snap.pkgPath != "" {
+		m.SetActivePackage(m.Store.GetPackage(snap.pkgPath, false))
+	}
+}
+
 func (m *Machine) RunFunc(fn Name) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -718,6 +828,7 @@ const (
 	OpPopFrameAndReset    Op = 0x15 // pop frame and reset.
 	OpPanic1              Op = 0x16 // pop exception and pop call frames.
 	OpPanic2              Op = 0x17 // pop call frames.
+	OpCallBytecode        Op = 0x18 // call body is bytecode
 
 	/* Unary & binary operators */
 	OpUpos  Op = 0x20 // + (unary)
@@ -824,6 +935,40 @@ func (m *Machine) incrCPU(cycles int64) {
 	if m.MaxCycles != 0 && m.Cycles > m.MaxCycles {
 		panic("CPU cycle overrun")
 	}
+	if m.GasMeter != nil {
+		m.consumeGas(cycles)
+	}
+}
+
+// GasConfig defines the gas costs of machine execution.  Store access is
+// metered separately, by the underlying store of the machine's Store.
+type GasConfig struct {
+	CPUCycle  int64 // per "cpu" cycle
+	AllocByte int64 // per byte allocated
+}
+
+// ConsumeGas charges gas for work not accounted for by cpu cycles or
+// allocations, such as native function calls. It does nothing if the
+// machine has no gas meter.
+func (m *Machine) ConsumeGas(amount int64, descriptor string) {
+	if m.GasMeter != nil {
+		m.GasMeter.ConsumeGas(amount, descriptor)
+	}
+}
+
+// Consumes gas for cycles, and for any allocations since the last call.
+// Allocations are charged after the op that made them, but before the next
+// op runs.  Panics with store.OutOfGasException if out of gas, which aborts
+// execution.
+func (m *Machine) consumeGas(cycles int64) {
+	m.GasMeter.ConsumeGas(cycles*m.GasConfig.CPUCycle, "CPUCycles")
+	if m.Alloc != nil {
+		_, bytes := m.Alloc.Status()
+		if bytes > m.allocBytes {
+			m.GasMeter.ConsumeGas((bytes-m.allocBytes)*m.GasConfig.AllocByte, "AllocBytes")
+		}
+		m.allocBytes = bytes
+	}
 }
 
 const (
@@ -852,6 +997,7 @@ const (
 	OpCPUPopFrameAndReset    = 1
 	OpCPUPanic1              = 1
 	OpCPUPanic2              = 1
+	OpCPUCallBytecode        = 1
 
 	/* Unary & binary operators */
 	OpCPUUpos  = 1
@@ -956,6 +1102,9 @@ const (
 func (m *Machine) Run() {
 	for {
 		op := m.PopOp()
+		if m.OpCounts != nil {
+			m.OpCounts[op]++
+		}
 		// TODO: this can be optimized manually, even into tiers.
 		switch op {
 		/* Control operators */
@@ -995,6 +1144,9 @@ func (m *Machine) Run() {
 		case OpPanic2:
 			m.incrCPU(OpCPUPanic2)
 			m.doOpPanic2()
+		case OpCallBytecode:
+			m.incrCPU(OpCPUCallBytecode)
+			m.doOpCallBytecode()
 		case OpCallDeferNativeBody:
 			m.incrCPU(OpCPUCallDeferNativeBody)
 			m.doOpCallDeferNativeBody()
@@ -1392,6 +1544,9 @@ func (m *Machine) PushExpr(x Expr) {
 	if debug {
 		m.Printf("+x %v\n", x)
 	}
+	if m.MaxExprs != 0 && len(m.Exprs) == m.MaxExprs {
+		panic("expression limit exceeded")
+	}
 	m.Exprs = append(m.Exprs, x)
 }
 
@@ -1419,6 +1574,9 @@ func (m *Machine) PushValue(tv TypedValue) {
 	if debug {
 		m.Printf("+v %v\n", tv)
 	}
+	if m.MaxValues != 0 && m.NumValues == m.MaxValues {
+		panic("value limit exceeded")
+	}
 	if len(m.Values) == m.NumValues {
 		// TODO tune. also see PushOp().
 		newValues := make([]TypedValue, len(m.Values)*2)
@@ -1524,6 +1682,7 @@ func (m *Machine) PushFrameBasic(s Stmt) {
 	if debug {
 		m.Printf("+F %#v\n", fr)
 	}
+	m.checkFrameLimit()
 	m.Frames = append(m.Frames, fr)
 }
 
@@ -1546,6 +1705,7 @@ func (m *Machine) PushFrameCall(cx *CallExpr, fv *FuncValue, recv TypedValue) {
 		Defers:      nil,
 		LastPackage: m.Package,
 		LastRealm:   m.Realm,
+		NumCycles:   m.Cycles,
 	}
 	if debug {
 		if m.Package == nil {
@@ -1555,6 +1715,7 @@ func (m *Machine) PushFrameCall(cx *CallExpr, fv *FuncValue, recv TypedValue) {
 	if debug {
 		m.Printf("+F %#v\n", fr)
 	}
+	m.checkFrameLimit()
 	m.Frames = append(m.Frames, fr)
 	pv := fv.GetPackage(m.Store)
 	if pv == nil {
@@ -1563,6 +1724,13 @@ func (m *Machine) PushFrameCall(cx *CallExpr, fv *FuncValue, recv TypedValue) {
 	m.Package = pv
 	rlm := pv.GetRealm()
 	if rlm != nil && m.Realm != rlm {
+		if m.RealmGuard != nil {
+			from := ""
+			if m.Realm != nil {
+				from = m.Realm.Path
+			}
+			m.RealmGuard(from, rlm.Path)
+		}
 		m.Realm = rlm // enter new realm
 	}
 }
@@ -1587,10 +1755,19 @@ func (m *Machine) PushFrameGoNative(cx *CallExpr, fv *NativeValue) {
 	if debug {
 		m.Printf("+F %#v\n", fr)
 	}
+	m.checkFrameLimit()
 	m.Frames = append(m.Frames, fr)
 	// keep m.Package the same.
 }
 
+// Panics if pushing another frame would exceed the frame limit.  Since the
+// machine does not recurse natively on calls, this is what bounds recursion.
+func (m *Machine) checkFrameLimit() {
+	if m.MaxFrames != 0 && len(m.Frames) == m.MaxFrames {
+		panic("frame limit exceeded")
+	}
+}
+
 func (m *Machine) PopFrame() Frame {
 	numFrames := len(m.Frames)
 	f := m.Frames[numFrames-1]
@@ -1638,6 +1815,9 @@ func (m *Machine) PopFrameAndReturn() {
 		m.Values[fr.NumValues+i] = res
 	}
 	m.NumValues = fr.NumValues + numRes
+	if m.CallTracer != nil {
+		m.traceCall(fr)
+	}
 	m.Package = fr.LastPackage
 	m.Realm = fr.LastRealm
 }
@@ -1798,6 +1978,7 @@ func (m *Machine) CheckEmpty() error {
 func (m *Machine) Panic(ex TypedValue) {
 	// TODO: chain exceptions if preexisting unrecovered exception.
 	m.Exception = &ex
+	m.panicStack = m.Stacktrace()
 	m.PopUntilLastCallFrame()
 	m.PushOp(OpPanic2)
 	m.PushOp(OpReturnCallDefers)
@@ -0,0 +1,292 @@
+package gno
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// ModFileName is the name of the file of a package which pins the versions
+// of its on-chain dependencies.
+const ModFileName = "gno.mod"
+
+// ModFile is a parsed gno.mod file, e.g.:
+//
+//	module gno.land/r/foo
+//
+//	require gno.land/r/bar v2
+//	require (
+//		gno.land/p/baz @1234
+//	)
+//
+//	replace gno.land/p/baz => ../baz
+//
+// A dependency is pinned either to a version, where v1 is the originally
+// added package and vN its upgrade at path/vN, or to the latest version
+// added at or before a block height.  A dependency may be replaced by the
+// package in a local directory, relative to that of the gno.mod file, e.g.
+// while developing both; replacements only apply locally, and are ignored
+// on chain.
+type ModFile struct {
+	Module   string
+	Requires []ModRequire
+	Replaces []ModReplace
+}
+
+// ModRequire pins the version of the dependency at Path. Exactly one of
+// Version and Height is set.
+type ModRequire struct {
+	Path    string
+	Version int   // e.g. 2 for v2.
+	Height  int64 // e.g. 1234 for @1234.
+}
+
+func (mr ModRequire) String() string {
+	if mr.Version > 0 {
+		return fmt.Sprintf("%s v%d", mr.Path, mr.Version)
+	}
+	return fmt.Sprintf("%s @%d", mr.Path, mr.Height)
+}
+
+// ModReplace replaces the dependency at Path by the package in Dir.
+type ModReplace struct {
+	Path string
+	Dir  string
+}
+
+func (mr ModReplace) String() string {
+	return fmt.Sprintf("%s => %s", mr.Path, mr.Dir)
+}
+
+// ParseModFile parses the body of a gno.mod file.
+func ParseModFile(body string) (*ModFile, error) {
+	mf := &ModFile{}
+	seen := make(map[string]bool)
+	replaced := make(map[string]bool)
+	block := "" // directive of the current block, if any.
+	for i, line := range strings.Split(body, "\n") {
+		lineno := i + 1
+		if j := strings.Index(line, "//"); j >= 0 {
+			line = line[:j]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		directive := block
+		if block != "" {
+			if len(fields) == 1 && fields[0] == ")" {
+				block = ""
+				continue
+			}
+		} else {
+			directive = fields[0]
+			switch directive {
+			case "module":
+				if len(fields) != 2 || mf.Module != "" {
+					return nil, errors.New("%s:%d: invalid module directive", ModFileName, lineno)
+				}
+				mf.Module = fields[1]
+				continue
+			case "require", "replace":
+				if len(fields) == 2 && fields[1] == "(" {
+					block = directive
+					continue
+				}
+				fields = fields[1:]
+			default:
+				return nil, errors.New("%s:%d: unknown directive %s", ModFileName, lineno, fields[0])
+			}
+		}
+		if directive == "replace" {
+			mr, err := parseModReplace(fields)
+			if err != nil {
+				return nil, errors.New("%s:%d: %v", ModFileName, lineno, err)
+			}
+			if replaced[mr.Path] {
+				return nil, errors.New("%s:%d: duplicate replacement %s", ModFileName, lineno, mr.Path)
+			}
+			replaced[mr.Path] = true
+			mf.Replaces = append(mf.Replaces, mr)
+			continue
+		}
+		mr, err := parseModRequire(fields)
+		if err != nil {
+			return nil, errors.New("%s:%d: %v", ModFileName, lineno, err)
+		}
+		if seen[mr.Path] {
+			return nil, errors.New("%s:%d: duplicate requirement %s", ModFileName, lineno, mr.Path)
+		}
+		seen[mr.Path] = true
+		mf.Requires = append(mf.Requires, mr)
+	}
+	if block != "" {
+		return nil, errors.New("%s: unterminated %s block", ModFileName, block)
+	}
+	return mf, nil
+}
+
+func parseModRequire(fields []string) (ModRequire, error) {
+	if len(fields) != 2 {
+		return ModRequire{}, errors.New("invalid requirement, expected <path> v<version> or <path> @<height>")
+	}
+	mr := ModRequire{Path: fields[0]}
+	pin := fields[1]
+	switch {
+	case strings.HasPrefix(pin, "v"):
+		version, err := strconv.Atoi(pin[1:])
+		if err != nil || version < 1 {
+			return ModRequire{}, errors.New("invalid version %s", pin)
+		}
+		mr.Version = version
+	case strings.HasPrefix(pin, "@"):
+		height, err := strconv.ParseInt(pin[1:], 10, 64)
+		if err != nil || height < 0 {
+			return ModRequire{}, errors.New("invalid height %s", pin)
+		}
+		mr.Height = height
+	default:
+		return ModRequire{}, errors.New("invalid pin %s, expected v<version> or @<height>", pin)
+	}
+	return mr, nil
+}
+
+func parseModReplace(fields []string) (ModReplace, error) {
+	if len(fields) != 3 || fields[1] != "=>" {
+		return ModReplace{}, errors.New("invalid replacement, expected <path> => <dir>")
+	}
+	return ModReplace{Path: fields[0], Dir: fields[2]}, nil
+}
+
+// Require returns the requirement for the dependency at path, if any.
+func (mf *ModFile) Require(path string) (ModRequire, bool) {
+	for _, mr := range mf.Requires {
+		if mr.Path == path {
+			return mr, true
+		}
+	}
+	return ModRequire{}, false
+}
+
+// Replace returns the replacement of the dependency at path, if any.
+func (mf *ModFile) Replace(path string) (ModReplace, bool) {
+	for _, mr := range mf.Replaces {
+		if mr.Path == path {
+			return mr, true
+		}
+	}
+	return ModReplace{}, false
+}
+
+// String returns mf as the body of a gno.mod file, with one directive per
+// requirement and replacement, in order.
+func (mf *ModFile) String() string {
+	var sb strings.Builder
+	if mf.Module != "" {
+		fmt.Fprintf(&sb, "module %s\n", mf.Module)
+	}
+	for i, mr := range mf.Requires {
+		if i == 0 && sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "require %s\n", mr)
+	}
+	for i, mr := range mf.Replaces {
+		if i == 0 && sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "replace %s\n", mr)
+	}
+	return sb.String()
+}
+
+// ReadModFile returns the parsed gno.mod file of memPkg, or nil if it has
+// none.
+func ReadModFile(memPkg *std.MemPackage) (*ModFile, error) {
+	for _, mfile := range memPkg.Files {
+		if mfile.Name == ModFileName {
+			return ParseModFile(mfile.Body)
+		}
+	}
+	return nil, nil
+}
+
+// MemPackageImports returns the sorted import paths of the non-test .gno
+// files of memPkg.
+func MemPackageImports(memPkg *std.MemPackage) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	err := forEachImport(memPkg, func(mfile *std.MemFile, path string, start, end int) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// RewriteImports replaces the import paths of the non-test .gno files of
+// memPkg by those in rewrites, if any, leaving the rest of the source as
+// is.
+func RewriteImports(memPkg *std.MemPackage, rewrites map[string]string) error {
+	type edit struct {
+		start, end int
+		path       string
+	}
+	edits := make(map[*std.MemFile][]edit)
+	err := forEachImport(memPkg, func(mfile *std.MemFile, path string, start, end int) {
+		if newPath, ok := rewrites[path]; ok && newPath != path {
+			edits[mfile] = append(edits[mfile], edit{start, end, newPath})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for mfile, fedits := range edits {
+		// apply from the end, so that offsets remain valid.
+		body := mfile.Body
+		for i := len(fedits) - 1; i >= 0; i-- {
+			e := fedits[i]
+			body = body[:e.start] + strconv.Quote(e.path) + body[e.end:]
+		}
+		mfile.Body = body
+	}
+	return nil
+}
+
+// Calls fn with each import path of the non-test .gno files of memPkg,
+// and the offsets of its literal in the file.
+func forEachImport(memPkg *std.MemPackage, fn func(mfile *std.MemFile, path string, start, end int)) error {
+	for _, mfile := range memPkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, mfile.Name, mfile.Body, parser.ImportsOnly)
+		if err != nil {
+			return errors.Wrap(err, "parsing file "+mfile.Name)
+		}
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return errors.Wrap(err, "parsing file "+mfile.Name)
+			}
+			start := fset.Position(imp.Path.Pos()).Offset
+			end := fset.Position(imp.Path.End()).Offset
+			fn(mfile, path, start, end)
+		}
+	}
+	return nil
+}
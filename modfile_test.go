@@ -0,0 +1,100 @@
+package gno
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+)
+
+func TestParseModFile(t *testing.T) {
+	mf, err := ParseModFile(`// comment
+module gno.land/r/foo
+
+require gno.land/r/bar v2
+require (
+	gno.land/p/baz @1234 // pinned by height
+	gno.land/p/qux v1
+)
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, mf.Module, "gno.land/r/foo")
+	assert.Equal(t, mf.Requires, []ModRequire{
+		{Path: "gno.land/r/bar", Version: 2},
+		{Path: "gno.land/p/baz", Height: 1234},
+		{Path: "gno.land/p/qux", Version: 1},
+	})
+	mr, ok := mf.Require("gno.land/p/baz")
+	assert.True(t, ok)
+	assert.Equal(t, mr.String(), "gno.land/p/baz @1234")
+	_, ok = mf.Require("gno.land/p/none")
+	assert.False(t, ok)
+
+	for _, body := range []string{
+		"module a\nmodule b\n",
+		"require gno.land/r/bar\n",
+		"require gno.land/r/bar v0\n",
+		"require gno.land/r/bar latest\n",
+		"require gno.land/r/bar v1\nrequire gno.land/r/bar v2\n",
+		"require (\ngno.land/r/bar v1\n",
+		"replace gno.land/r/bar v1\n",
+		"replace gno.land/r/bar => ../a\nreplace gno.land/r/bar => ../b\n",
+		"replace (\ngno.land/r/bar => ../bar\n",
+	} {
+		_, err := ParseModFile(body)
+		assert.Error(t, err, body)
+	}
+}
+
+func TestModFileReplace(t *testing.T) {
+	body := `module gno.land/r/foo
+
+require gno.land/r/bar v2
+require gno.land/p/baz @1234
+
+replace gno.land/r/bar => ../bar
+replace gno.land/p/qux => /tmp/qux
+`
+	mf, err := ParseModFile(`module gno.land/r/foo
+require gno.land/r/bar v2
+require gno.land/p/baz @1234
+replace gno.land/r/bar => ../bar
+replace (
+	gno.land/p/qux => /tmp/qux // absolute
+)
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, mf.Replaces, []ModReplace{
+		{Path: "gno.land/r/bar", Dir: "../bar"},
+		{Path: "gno.land/p/qux", Dir: "/tmp/qux"},
+	})
+	mr, ok := mf.Replace("gno.land/r/bar")
+	assert.True(t, ok)
+	assert.Equal(t, mr.String(), "gno.land/r/bar => ../bar")
+	_, ok = mf.Replace("gno.land/p/baz")
+	assert.False(t, ok)
+	assert.Equal(t, mf.String(), body)
+
+	mf2, err := ParseModFile(mf.String())
+	assert.NoError(t, err)
+	assert.Equal(t, mf2, mf)
+}
+
+func TestRewriteImports(t *testing.T) {
+	memPkg := &std.MemPackage{
+		Name: "foo",
+		Path: "gno.land/r/foo",
+		Files: []*std.MemFile{
+			{Name: "foo.gno", Body: "package foo\n\nimport (\n\t\"std\"\n\t\"gno.land/r/bar\" // bar\n)\n\nvar _ = bar.X\nvar _ = std.Address(\"\")\n"},
+			{Name: "foo_test.gno", Body: "package foo\n\nimport \"gno.land/r/bar\"\n"},
+		},
+	}
+	imports, err := MemPackageImports(memPkg)
+	assert.NoError(t, err)
+	assert.Equal(t, imports, []string{"gno.land/r/bar", "std"})
+
+	err = RewriteImports(memPkg, map[string]string{"gno.land/r/bar": "gno.land/r/bar/v2"})
+	assert.NoError(t, err)
+	assert.Equal(t, memPkg.Files[0].Body, "package foo\n\nimport (\n\t\"std\"\n\t\"gno.land/r/bar/v2\" // bar\n)\n\nvar _ = bar.X\nvar _ = std.Address(\"\")\n")
+	assert.Equal(t, memPkg.Files[1].Body, "package foo\n\nimport \"gno.land/r/bar\"\n")
+}
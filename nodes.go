@@ -2011,6 +2011,7 @@ const (
 	ATTR_IOTA         GnoAttribute = "ATTR_IOTA"
 	ATTR_LOCATIONED   GnoAttribute = "ATTR_LOCATIONED"
 	ATTR_INJECTED     GnoAttribute = "ATTR_INJECTED"
+	ATTR_BYTECODE     GnoAttribute = "ATTR_BYTECODE"
 )
 
 // TODO: consider length restrictions.
@@ -378,6 +378,9 @@ func (n SelectStmt) String() string {
 }
 
 func (n SelectCaseStmt) String() string {
+	if n.Comm == nil {
+		return fmt.Sprintf("default: %s", n.Body.String())
+	}
 	return fmt.Sprintf("case %v: %s", n.Comm.String(), n.Body.String())
 }
 
@@ -0,0 +1,112 @@
+package gno
+
+import (
+	"bytes"
+	"container/list"
+	"sync"
+)
+
+const objectCacheSize = 64 * 1024 // TODO parameterize.
+
+// objectCache is an LRU cache of decoded objects keyed by object ID, which
+// unlike defaultStore.cacheObjects is kept across transactions and blocks,
+// and shared with forks of the store.
+//
+// Cached objects are never handed out; each get returns a fresh copy, so
+// that objects modified by a transaction (whether committed or not) do not
+// affect the cache.  Entries are removed when their object is set or
+// deleted, and each entry records the hash it was loaded with, which must
+// match the hash in the backend for the entry to be used.  This way stale
+// entries are never returned, e.g. after a failed transaction or a write by
+// a fork.
+type objectCache struct {
+	mtx      sync.Mutex
+	capacity int
+	ll       *list.List // of *objectCacheEntry, most recent first.
+	entries  map[ObjectID]*list.Element
+	hits     int64
+	misses   int64
+}
+
+type objectCacheEntry struct {
+	oid  ObjectID
+	hash []byte
+	oo   Object // with refs, as decoded.
+}
+
+func newObjectCache(capacity int) *objectCache {
+	return &objectCache{
+		capacity: capacity,
+		ll:       list.New(),
+		entries:  make(map[ObjectID]*list.Element),
+	}
+}
+
+// Returns a copy of the object with id oid, if cached with hash.
+// The copy is as if freshly decoded; it has no hash, and its types are
+// not yet filled.
+func (oc *objectCache) get(oid ObjectID, hash []byte) Object {
+	oc.mtx.Lock()
+	defer oc.mtx.Unlock()
+	elem, ok := oc.entries[oid]
+	if !ok {
+		oc.misses++
+		return nil
+	}
+	entry := elem.Value.(*objectCacheEntry)
+	if !bytes.Equal(entry.hash, hash) {
+		oc.ll.Remove(elem)
+		delete(oc.entries, oid)
+		oc.misses++
+		return nil
+	}
+	oc.ll.MoveToFront(elem)
+	oc.hits++
+	return copyValueWithRefs(nil, entry.oo).(Object)
+}
+
+// Caches oo, which must be as decoded, with refs to its children and
+// unfilled types.  The cache keeps its own copy.
+func (oc *objectCache) add(oid ObjectID, hash []byte, oo Object) {
+	if _, ok := oo.(*PackageValue); ok {
+		return // packages are loaded through GetPackage().
+	}
+	cc := copyValueWithRefs(nil, oo).(Object)
+	oc.mtx.Lock()
+	defer oc.mtx.Unlock()
+	entry := &objectCacheEntry{oid: oid, hash: cp(hash), oo: cc}
+	if elem, ok := oc.entries[oid]; ok {
+		elem.Value = entry
+		oc.ll.MoveToFront(elem)
+		return
+	}
+	oc.entries[oid] = oc.ll.PushFront(entry)
+	for oc.ll.Len() > oc.capacity {
+		last := oc.ll.Back()
+		oc.ll.Remove(last)
+		delete(oc.entries, last.Value.(*objectCacheEntry).oid)
+	}
+}
+
+func (oc *objectCache) remove(oid ObjectID) {
+	oc.mtx.Lock()
+	defer oc.mtx.Unlock()
+	if elem, ok := oc.entries[oid]; ok {
+		oc.ll.Remove(elem)
+		delete(oc.entries, oid)
+	}
+}
+
+func (oc *objectCache) clear() {
+	oc.mtx.Lock()
+	defer oc.mtx.Unlock()
+	oc.ll.Init()
+	oc.entries = make(map[ObjectID]*list.Element)
+}
+
+// Returns the number of cache hits and misses so far.
+func (oc *objectCache) stats() (hits, misses int64) {
+	oc.mtx.Lock()
+	defer oc.mtx.Unlock()
+	return oc.hits, oc.misses
+}
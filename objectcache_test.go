@@ -0,0 +1,49 @@
+package gno
+
+import (
+	"testing"
+
+	"github.com/jaekwon/testify/assert"
+)
+
+func newCacheTestObject(n int) *StructValue {
+	sv := &StructValue{Fields: []TypedValue{{T: IntType}}}
+	sv.Fields[0].SetInt(n)
+	sv.ID = ObjectID{NewTime: uint64(n)}
+	return sv
+}
+
+func TestObjectCache(t *testing.T) {
+	oc := newObjectCache(2)
+	o1, o2, o3 := newCacheTestObject(1), newCacheTestObject(2), newCacheTestObject(3)
+	h1, h2, h3 := []byte("h1"), []byte("h2"), []byte("h3")
+	oc.add(o1.ID, h1, o1)
+	oc.add(o2.ID, h2, o2)
+
+	// gets return copies.
+	c1 := oc.get(o1.ID, h1).(*StructValue)
+	assert.Equal(t, c1.Fields[0].GetInt(), 1)
+	assert.Equal(t, c1.ID, o1.ID)
+	c1.Fields[0].SetInt(100)
+	assert.Equal(t, oc.get(o1.ID, h1).(*StructValue).Fields[0].GetInt(), 1)
+	o1.Fields[0].SetInt(200)
+	assert.Equal(t, oc.get(o1.ID, h1).(*StructValue).Fields[0].GetInt(), 1)
+
+	// the least recently used object is evicted.
+	oc.add(o3.ID, h3, o3)
+	assert.Nil(t, oc.get(o2.ID, h2))
+	assert.NotNil(t, oc.get(o1.ID, h1))
+	assert.NotNil(t, oc.get(o3.ID, h3))
+
+	// an object with a different hash is evicted.
+	assert.Nil(t, oc.get(o1.ID, h2))
+	assert.Nil(t, oc.get(o1.ID, h1))
+
+	// removed objects are not returned.
+	oc.remove(o3.ID)
+	assert.Nil(t, oc.get(o3.ID, h3))
+
+	hits, misses := oc.stats()
+	assert.Equal(t, hits, int64(5))
+	assert.Equal(t, misses, int64(4))
+}
@@ -57,32 +57,13 @@ func (m *Machine) doOpCall() {
 	clo := fr.Func.GetClosure(m.Store)
 	b := m.Alloc.NewBlock(fr.Func.GetSource(m.Store), clo)
 	m.PushBlock(b)
-	if fv.nativeBody == nil {
-		fbody := fv.GetBodyFromSource(m.Store)
-		if len(ft.Results) == 0 {
-			// Push final empty *ReturnStmt;
-			// TODO: transform in preprocessor instead to return only
-			// when necessary.
-			// NOTE: m.PushOp(OpReturn) doesn't handle defers.
-			m.PushStmt(gReturnStmt)
-			m.PushOp(OpExec)
-		} else {
-			// Initialize return variables with default value.
-			numParams := len(ft.Params)
-			for i, rt := range ft.Results {
-				ptr := b.GetPointerToInt(nil, numParams+i)
-				dtv := defaultTypedValue(m.Alloc, rt.Type)
-				ptr.Assign2(m.Alloc, nil, nil, dtv, false)
-			}
-		}
-		// Exec body.
-		b.bodyStmt = bodyStmt{
-			Body:          fbody,
-			BodyLen:       len(fbody),
-			NextBodyIndex: -2,
-		}
-		m.PushOp(OpBody)
-		m.PushStmt(b.GetBodyStmt())
+	if bc := m.getBytecode(fv); bc != nil {
+		// Like native functions, results are pushed onto
+		// the value stack. See bytecode.go.
+		m.PushOp(OpReturn)
+		m.PushOp(OpCallBytecode)
+	} else if fv.nativeBody == nil {
+		m.pushFuncBody(fv, ft, b)
 	} else {
 		// No return exprs and no defers, safe to skip OpEval.
 		// NOTE: m.PushOp(OpReturn) doesn't handle defers.
@@ -158,6 +139,47 @@ func (m *Machine) doOpCall() {
 	}
 }
 
+// Pushes ops to interpret the body of fv, in block b.
+func (m *Machine) pushFuncBody(fv *FuncValue, ft *FuncType, b *Block) {
+	fbody := fv.GetBodyFromSource(m.Store)
+	if len(ft.Results) == 0 {
+		// Push final empty *ReturnStmt;
+		// TODO: transform in preprocessor instead to return only
+		// when necessary.
+		// NOTE: m.PushOp(OpReturn) doesn't handle defers.
+		m.PushStmt(gReturnStmt)
+		m.PushOp(OpExec)
+	} else {
+		// Initialize return variables with default value.
+		numParams := len(ft.Params)
+		for i, rt := range ft.Results {
+			ptr := b.GetPointerToInt(nil, numParams+i)
+			dtv := defaultTypedValue(m.Alloc, rt.Type)
+			ptr.Assign2(m.Alloc, nil, nil, dtv, false)
+		}
+	}
+	// Exec body.
+	b.bodyStmt = bodyStmt{
+		Body:          fbody,
+		BodyLen:       len(fbody),
+		NextBodyIndex: -2,
+	}
+	m.PushOp(OpBody)
+	m.PushStmt(b.GetBodyStmt())
+}
+
+// Runs the bytecode of the called function, or falls back to
+// interpreting it. Parameters have already been assigned.
+func (m *Machine) doOpCallBytecode() {
+	fr := m.LastFrame()
+	fv := fr.Func
+	if m.execBytecode(m.getBytecode(fv)) {
+		return
+	}
+	m.PopOp() // OpReturn
+	m.pushFuncBody(fv, fv.GetType(m.Store), m.LastBlock())
+}
+
 func (m *Machine) doOpCallNativeBody() {
 	m.LastFrame().Func.nativeBody(m)
 }
@@ -393,7 +393,7 @@ func (m *Machine) doOpExec(op Op) {
 				s = next // switch on bs.Active
 				goto EXEC_SWITCH
 			} else if bs.NextBodyIndex == bs.BodyLen {
-				nnext := bs.NextItem.Next
+				nnext := bs.NextItem.NextValid()
 				if nnext == nil {
 					// done with range.
 					m.PopFrameAndReset()
@@ -431,6 +431,12 @@ EXEC_SWITCH:
 	if debug {
 		debug.Printf("EXEC: %v\n", s)
 	}
+	if m.Coverage != nil {
+		m.Coverage.recordStmt(m, s)
+	}
+	if m.Debugger != nil {
+		m.Debugger.onStmt(m, s)
+	}
 	switch cs := s.(type) {
 	case *AssignStmt:
 		switch cs.Op {
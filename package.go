@@ -36,6 +36,8 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	// Realm/Object
 	ObjectID{},
 	&ObjectInfo{},
+	Realm{},
+	RealmState{},
 
 	//----------------------------------------
 	// Hash/Image
@@ -161,6 +161,11 @@ func GetTypeURL(o interface{}) string {
 	return gcdc.GetTypeURL(o)
 }
 
+// Stats returns the sizes of the registry of the global codec.
+func Stats() CodecStats {
+	return gcdc.Stats()
+}
+
 //----------------------------------------
 // Typ3
 
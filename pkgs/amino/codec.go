@@ -471,6 +471,25 @@ func (cdc *Codec) GetPackages() pkg.PackageSet {
 	return cdc.packages
 }
 
+// CodecStats are the sizes of the registry of a codec.
+type CodecStats struct {
+	Packages  int `json:"packages"`   // registered packages
+	Types     int `json:"types"`      // registered types
+	TypeInfos int `json:"type_infos"` // type infos, of registered types or not
+}
+
+// Stats returns the sizes of the registry of the codec.
+func (cdc *Codec) Stats() CodecStats {
+	cdc.mtx.RLock()
+	defer cdc.mtx.RUnlock()
+
+	return CodecStats{
+		Packages:  len(cdc.packages),
+		Types:     len(cdc.fullnameToTypeInfo),
+		TypeInfos: len(cdc.typeInfos),
+	}
+}
+
 // This is used primarily for gengo.
 // XXX TODO: make this safe so modifications don't affect runtime codec,
 // and ensure that it stays safe.
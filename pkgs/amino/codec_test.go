@@ -223,4 +223,16 @@ func TestCodecSeal(t *testing.T) {
 	assert.Panics(t, func() { cdc.RegisterPackage(tests.Package) })
 }
 
+func TestCodecStats(t *testing.T) {
+	cdc := amino.NewCodec()
+	stats := cdc.Stats()
+
+	cdc.RegisterPackage(tests.Package)
+	stats2 := cdc.Stats()
+	assert.Equal(t, len(cdc.GetPackages()), stats2.Packages)
+	assert.True(t, stats2.Packages > stats.Packages)
+	assert.Equal(t, stats.Types+len(tests.Package.Types), stats2.Types)
+	assert.True(t, stats2.TypeInfos >= stats2.Types)
+}
+
 // XXX Test registering duplicate names or concrete types not in a package.
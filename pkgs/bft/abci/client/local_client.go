@@ -216,7 +216,9 @@ func (app *localClient) EndBlockSync(req abci.RequestEndBlock) (abci.ResponseEnd
 //-------------------------------------------------------
 
 func (app *localClient) completeRequest(req abci.Request, res abci.Response) *ReqRes {
-	app.Callback(req, res)
+	if app.Callback != nil {
+		app.Callback(req, res)
+	}
 	return newLocalReqRes(req, res)
 }
 
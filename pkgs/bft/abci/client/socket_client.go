@@ -0,0 +1,619 @@
+package abcicli
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"time"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/errors"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/service"
+)
+
+var _ Client = (*socketClient)(nil)
+
+// maxResponseSize is the maximum size of an encoded response.
+const maxResponseSize = 64 * 1024 * 1024 // 64MB
+
+// SocketOptions are the options of the socket client.
+type SocketOptions struct {
+	// Time a request may take, from its queueing to its response, or 0
+	// for no limit.  A request timing out fails, and if it was sent, the
+	// connection to the app (which is deemed stuck) is closed and redialed.
+	RequestTimeout time.Duration
+
+	// Maximum number of requests queued or in flight.  Further requests
+	// block until others complete, or they time out.
+	MaxPendingRequests int
+
+	// Interval between the attempts to (re)dial the app.
+	DialRetryInterval time.Duration
+
+	// Called once the app is redialed, before the requests are resumed,
+	// e.g. to replay the blocks the app lost on a restart.  On error, the
+	// connection is closed and redialed.
+	OnReconnect func() error
+}
+
+// DefaultSocketOptions returns the default options of the socket client.
+func DefaultSocketOptions() SocketOptions {
+	return SocketOptions{
+		RequestTimeout:     30 * time.Second,
+		MaxPendingRequests: 256,
+		DialRetryInterval:  dialRetryIntervalSeconds * time.Second,
+	}
+}
+
+// A request queued or sent, until its response.
+type pendingReq struct {
+	reqRes   *ReqRes
+	deadline time.Time // zero for none
+	sent     bool
+}
+
+// A connection to the app, whose routines stop once done is closed.
+type socketConn struct {
+	conn net.Conn
+	done chan struct{}
+}
+
+// socketClient is the client of an app served over a socket, e.g. by
+// abci/server.SocketServer.  The requests are sent in order, and the
+// responses of the app, in the same order, are matched to them.
+//
+// On an error of the connection, or a request timing out after being sent,
+// all pending requests fail with a ResponseException (since the app may
+// have processed them or not), and the app is redialed; the requests queued
+// meanwhile are sent once reconnected.
+type socketClient struct {
+	service.BaseService
+
+	addr        string
+	mustConnect bool
+	opts        SocketOptions
+
+	slots chan struct{} // one per pending request, for backpressure
+	wake  chan struct{} // signals the requests to send
+
+	mtx     sync.Mutex
+	err     error
+	conn    *socketConn // nil if disconnected
+	pending []*pendingReq
+	stopped bool
+	resCb   Callback // called on all requests, if set.
+}
+
+// NewSocketClient creates a new socket client of the app at addr, e.g.
+// "tcp://127.0.0.1:26658" or "unix:///tmp/app.sock".  If mustConnect is
+// true, the client fails to start if the app can't be dialed; otherwise it
+// keeps dialing it.
+func NewSocketClient(addr string, mustConnect bool, opts SocketOptions) *socketClient {
+	if opts.MaxPendingRequests <= 0 {
+		opts.MaxPendingRequests = DefaultSocketOptions().MaxPendingRequests
+	}
+	if opts.DialRetryInterval <= 0 {
+		opts.DialRetryInterval = DefaultSocketOptions().DialRetryInterval
+	}
+	cli := &socketClient{
+		addr:        addr,
+		mustConnect: mustConnect,
+		opts:        opts,
+		slots:       make(chan struct{}, opts.MaxPendingRequests),
+		wake:        make(chan struct{}, 1),
+	}
+	cli.BaseService = *service.NewBaseService(nil, "socketClient", cli)
+	return cli
+}
+
+func (cli *socketClient) OnStart() error {
+	conn, err := osm.Connect(cli.addr)
+	if err != nil {
+		if cli.mustConnect {
+			return errors.Wrap(err, "error dialing ABCI app %s", cli.addr)
+		}
+		cli.Logger.Error("Error dialing ABCI app, retrying", "addr", cli.addr, "err", err)
+		cli.setErr(err)
+		go cli.reconnectRoutine()
+	} else {
+		cli.startConn(conn)
+	}
+	if cli.opts.RequestTimeout > 0 {
+		go cli.timeoutRoutine()
+	}
+	return nil
+}
+
+func (cli *socketClient) OnStop() {
+	cli.mtx.Lock()
+	cli.stopped = true
+	if cli.conn != nil {
+		cli.closeConn(cli.conn)
+	}
+	failed := cli.pending
+	cli.pending = nil
+	cli.mtx.Unlock()
+
+	cli.failRequests(failed, errors.New("ABCI client stopped"))
+}
+
+func (cli *socketClient) SetResponseCallback(resCb Callback) {
+	cli.mtx.Lock()
+	cli.resCb = resCb
+	cli.mtx.Unlock()
+}
+
+// Error returns the last error of the connection, or nil once reconnected.
+func (cli *socketClient) Error() error {
+	cli.mtx.Lock()
+	defer cli.mtx.Unlock()
+	return cli.err
+}
+
+func (cli *socketClient) setErr(err error) {
+	cli.mtx.Lock()
+	cli.err = err
+	cli.mtx.Unlock()
+}
+
+//----------------------------------------
+// connection
+
+func (cli *socketClient) startConn(conn net.Conn) {
+	c := &socketConn{
+		conn: conn,
+		done: make(chan struct{}),
+	}
+	cli.mtx.Lock()
+	if cli.stopped {
+		cli.mtx.Unlock()
+		conn.Close()
+		return
+	}
+	cli.conn = c
+	cli.err = nil
+	cli.mtx.Unlock()
+
+	go cli.sendRoutine(c)
+	go cli.recvRoutine(c)
+	cli.signalWake()
+}
+
+// CONTRACT: cli.mtx is locked.
+func (cli *socketClient) closeConn(c *socketConn) {
+	cli.conn = nil
+	close(c.done)
+	if err := c.conn.Close(); err != nil {
+		cli.Logger.Error("Error closing connection", "err", err)
+	}
+}
+
+// Closes c on err, fails the pending requests, and redials the app.  Does
+// nothing if c was already closed.
+func (cli *socketClient) connFailed(c *socketConn, err error) {
+	cli.mtx.Lock()
+	if cli.conn != c {
+		cli.mtx.Unlock()
+		return
+	}
+	cli.Logger.Error("ABCI connection failed, reconnecting", "addr", cli.addr, "err", err)
+	cli.closeConn(c)
+	cli.err = err
+	failed := cli.pending
+	cli.pending = nil
+	cli.mtx.Unlock()
+
+	cli.failRequests(failed, err)
+	go cli.reconnectRoutine()
+}
+
+func (cli *socketClient) reconnectRoutine() {
+	for {
+		select {
+		case <-cli.Quit():
+			return
+		case <-time.After(cli.opts.DialRetryInterval):
+		}
+		conn, err := osm.Connect(cli.addr)
+		if err != nil {
+			cli.Logger.Error("Error dialing ABCI app, retrying", "addr", cli.addr, "err", err)
+			cli.setErr(err)
+			continue
+		}
+		if cli.opts.OnReconnect != nil {
+			if err := cli.opts.OnReconnect(); err != nil {
+				cli.Logger.Error("Error on reconnection to ABCI app, retrying", "addr", cli.addr, "err", err)
+				cli.setErr(errors.Wrap(err, "error on reconnection"))
+				conn.Close()
+				continue
+			}
+		}
+		cli.Logger.Info("Reconnected to ABCI app", "addr", cli.addr)
+		cli.startConn(conn)
+		return
+	}
+}
+
+func (cli *socketClient) signalWake() {
+	select {
+	case cli.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Sends the requests not sent yet on c, whenever woken.
+func (cli *socketClient) sendRoutine(c *socketConn) {
+	w := bufio.NewWriter(c.conn)
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-cli.wake:
+		}
+		cli.mtx.Lock()
+		if cli.conn != c {
+			cli.mtx.Unlock()
+			cli.signalWake() // for the new connection.
+			return
+		}
+		var reqs []abci.Request
+		for _, pr := range cli.pending {
+			if !pr.sent {
+				pr.sent = true
+				reqs = append(reqs, pr.reqRes.Request)
+			}
+		}
+		cli.mtx.Unlock()
+
+		for _, req := range reqs {
+			if _, err := amino.MarshalAnySizedWriter(w, req); err != nil {
+				cli.connFailed(c, errors.Wrap(err, "error writing request"))
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			cli.connFailed(c, errors.Wrap(err, "error flushing requests"))
+			return
+		}
+	}
+}
+
+// Receives the responses on c, and completes their requests.
+func (cli *socketClient) recvRoutine(c *socketConn) {
+	r := bufio.NewReader(c.conn)
+	for {
+		var res abci.Response
+		if _, err := amino.UnmarshalSizedReader(r, &res, maxResponseSize); err != nil {
+			cli.connFailed(c, errors.Wrap(err, "error reading response"))
+			return
+		}
+
+		cli.mtx.Lock()
+		if cli.conn != c {
+			cli.mtx.Unlock()
+			return
+		}
+		if len(cli.pending) == 0 || !cli.pending[0].sent {
+			cli.mtx.Unlock()
+			cli.connFailed(c, errors.New("unexpected response %T", res))
+			return
+		}
+		pr := cli.pending[0]
+		if !resMatchesReq(pr.reqRes.Request, res) {
+			cli.mtx.Unlock()
+			cli.connFailed(c, errors.New("unexpected response %T to request %T", res, pr.reqRes.Request))
+			return
+		}
+		cli.pending[0] = nil
+		cli.pending = cli.pending[1:]
+		cli.mtx.Unlock()
+
+		<-cli.slots
+		cli.completeRequest(pr.reqRes, res)
+	}
+}
+
+// Fails the requests timing out.  If one was sent, the app is deemed stuck,
+// and the connection is closed.
+func (cli *socketClient) timeoutRoutine() {
+	interval := cli.opts.RequestTimeout / 10
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cli.Quit():
+			return
+		case now := <-ticker.C:
+			cli.mtx.Lock()
+			var expired []*pendingReq
+			var stuck bool
+			pending := cli.pending[:0]
+			for _, pr := range cli.pending {
+				if now.Before(pr.deadline) {
+					pending = append(pending, pr)
+				} else if pr.sent {
+					stuck = true
+					pending = append(pending, pr)
+				} else {
+					expired = append(expired, pr)
+				}
+			}
+			for i := len(pending); i < len(cli.pending); i++ {
+				cli.pending[i] = nil
+			}
+			cli.pending = pending
+			c := cli.conn
+			cli.mtx.Unlock()
+
+			cli.failRequests(expired, errors.New("ABCI request timed out after %v", cli.opts.RequestTimeout))
+			if stuck && c != nil {
+				cli.connFailed(c, errors.New("ABCI request timed out after %v", cli.opts.RequestTimeout))
+			}
+		}
+	}
+}
+
+//----------------------------------------
+// requests
+
+// Queues req to be sent, once one of the slots of the pending requests is
+// free.  If none frees up before the timeout, or the client is stopped, the
+// request fails.
+func (cli *socketClient) queueRequest(req abci.Request) *ReqRes {
+	reqRes := NewReqRes(req)
+	pr := &pendingReq{reqRes: reqRes}
+
+	var timeout <-chan time.Time
+	if cli.opts.RequestTimeout > 0 {
+		pr.deadline = time.Now().Add(cli.opts.RequestTimeout)
+		timer := time.NewTimer(cli.opts.RequestTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case cli.slots <- struct{}{}:
+	case <-timeout:
+		cli.completeRequest(reqRes, exceptionResponse(errors.New(
+			"ABCI request timed out: %d requests pending", cap(cli.slots))))
+		return reqRes
+	case <-cli.Quit():
+		cli.completeRequest(reqRes, exceptionResponse(errors.New("ABCI client stopped")))
+		return reqRes
+	}
+
+	cli.mtx.Lock()
+	if cli.stopped {
+		cli.mtx.Unlock()
+		cli.failRequests([]*pendingReq{pr}, errors.New("ABCI client stopped"))
+		return reqRes
+	}
+	cli.pending = append(cli.pending, pr)
+	cli.mtx.Unlock()
+
+	cli.signalWake()
+	return reqRes
+}
+
+// Fails the pending requests prs with err.
+func (cli *socketClient) failRequests(prs []*pendingReq, err error) {
+	for _, pr := range prs {
+		<-cli.slots
+		cli.completeRequest(pr.reqRes, exceptionResponse(err))
+	}
+}
+
+// Sets the response of reqRes, and calls its callbacks.
+func (cli *socketClient) completeRequest(reqRes *ReqRes, res abci.Response) {
+	reqRes.SetResponse(res)
+
+	cli.mtx.Lock()
+	resCb := cli.resCb
+	cli.mtx.Unlock()
+	if resCb != nil {
+		resCb(reqRes.Request, res)
+	}
+	if cb := reqRes.GetCallback(); cb != nil {
+		cb(res)
+	}
+}
+
+func exceptionResponse(err error) abci.ResponseException {
+	return abci.ResponseException{
+		ResponseBase: abci.ResponseBase{
+			Error: abci.StringError(err.Error()),
+		},
+	}
+}
+
+// Returns true if res is a response to req.  An exception may be the
+// response to any request.
+func resMatchesReq(req abci.Request, res abci.Response) bool {
+	switch res.(type) {
+	case abci.ResponseException:
+		return true
+	case abci.ResponseEcho:
+		_, ok := req.(abci.RequestEcho)
+		return ok
+	case abci.ResponseFlush:
+		_, ok := req.(abci.RequestFlush)
+		return ok
+	case abci.ResponseInfo:
+		_, ok := req.(abci.RequestInfo)
+		return ok
+	case abci.ResponseSetOption:
+		_, ok := req.(abci.RequestSetOption)
+		return ok
+	case abci.ResponseDeliverTx:
+		_, ok := req.(abci.RequestDeliverTx)
+		return ok
+	case abci.ResponseCheckTx:
+		_, ok := req.(abci.RequestCheckTx)
+		return ok
+	case abci.ResponseQuery:
+		_, ok := req.(abci.RequestQuery)
+		return ok
+	case abci.ResponseCommit:
+		_, ok := req.(abci.RequestCommit)
+		return ok
+	case abci.ResponseInitChain:
+		_, ok := req.(abci.RequestInitChain)
+		return ok
+	case abci.ResponseBeginBlock:
+		_, ok := req.(abci.RequestBeginBlock)
+		return ok
+	case abci.ResponseEndBlock:
+		_, ok := req.(abci.RequestEndBlock)
+		return ok
+	default:
+		return false
+	}
+}
+
+//----------------------------------------
+
+func (cli *socketClient) FlushAsync() *ReqRes {
+	return cli.queueRequest(abci.RequestFlush{})
+}
+
+func (cli *socketClient) EchoAsync(msg string) *ReqRes {
+	return cli.queueRequest(abci.RequestEcho{Message: msg})
+}
+
+func (cli *socketClient) InfoAsync(req abci.RequestInfo) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+func (cli *socketClient) SetOptionAsync(req abci.RequestSetOption) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+func (cli *socketClient) DeliverTxAsync(req abci.RequestDeliverTx) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+func (cli *socketClient) CheckTxAsync(req abci.RequestCheckTx) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+func (cli *socketClient) QueryAsync(req abci.RequestQuery) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+func (cli *socketClient) CommitAsync() *ReqRes {
+	return cli.queueRequest(abci.RequestCommit{})
+}
+
+func (cli *socketClient) InitChainAsync(req abci.RequestInitChain) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+func (cli *socketClient) BeginBlockAsync(req abci.RequestBeginBlock) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+func (cli *socketClient) EndBlockAsync(req abci.RequestEndBlock) *ReqRes {
+	return cli.queueRequest(req)
+}
+
+//----------------------------------------
+
+// Queues req and waits for its response.  Returns the error of an
+// exception.
+func (cli *socketClient) syncRequest(req abci.Request) (abci.Response, error) {
+	reqRes := cli.queueRequest(req)
+	reqRes.Wait()
+	if res, ok := reqRes.Response.(abci.ResponseException); ok {
+		return nil, res.Error
+	}
+	return reqRes.Response, nil
+}
+
+func (cli *socketClient) FlushSync() error {
+	_, err := cli.syncRequest(abci.RequestFlush{})
+	return err
+}
+
+func (cli *socketClient) EchoSync(msg string) (abci.ResponseEcho, error) {
+	res, err := cli.syncRequest(abci.RequestEcho{Message: msg})
+	if err != nil {
+		return abci.ResponseEcho{}, err
+	}
+	return res.(abci.ResponseEcho), nil
+}
+
+func (cli *socketClient) InfoSync(req abci.RequestInfo) (abci.ResponseInfo, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseInfo{}, err
+	}
+	return res.(abci.ResponseInfo), nil
+}
+
+func (cli *socketClient) SetOptionSync(req abci.RequestSetOption) (abci.ResponseSetOption, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseSetOption{}, err
+	}
+	return res.(abci.ResponseSetOption), nil
+}
+
+func (cli *socketClient) DeliverTxSync(req abci.RequestDeliverTx) (abci.ResponseDeliverTx, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseDeliverTx{}, err
+	}
+	return res.(abci.ResponseDeliverTx), nil
+}
+
+func (cli *socketClient) CheckTxSync(req abci.RequestCheckTx) (abci.ResponseCheckTx, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseCheckTx{}, err
+	}
+	return res.(abci.ResponseCheckTx), nil
+}
+
+func (cli *socketClient) QuerySync(req abci.RequestQuery) (abci.ResponseQuery, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseQuery{}, err
+	}
+	return res.(abci.ResponseQuery), nil
+}
+
+func (cli *socketClient) CommitSync() (abci.ResponseCommit, error) {
+	res, err := cli.syncRequest(abci.RequestCommit{})
+	if err != nil {
+		return abci.ResponseCommit{}, err
+	}
+	return res.(abci.ResponseCommit), nil
+}
+
+func (cli *socketClient) InitChainSync(req abci.RequestInitChain) (abci.ResponseInitChain, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseInitChain{}, err
+	}
+	return res.(abci.ResponseInitChain), nil
+}
+
+func (cli *socketClient) BeginBlockSync(req abci.RequestBeginBlock) (abci.ResponseBeginBlock, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseBeginBlock{}, err
+	}
+	return res.(abci.ResponseBeginBlock), nil
+}
+
+func (cli *socketClient) EndBlockSync(req abci.RequestEndBlock) (abci.ResponseEndBlock, error) {
+	res, err := cli.syncRequest(req)
+	if err != nil {
+		return abci.ResponseEndBlock{}, err
+	}
+	return res.(abci.ResponseEndBlock), nil
+}
@@ -0,0 +1,197 @@
+package abcicli_test
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/gnolang/gno/pkgs/bft/abci/client"
+	"github.com/gnolang/gno/pkgs/bft/abci/example/kvstore"
+	"github.com/gnolang/gno/pkgs/bft/abci/server"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+)
+
+// stuckApp blocks on queries until unblocked.
+type stuckApp struct {
+	*kvstore.KVStoreApplication
+	unblock chan struct{}
+}
+
+func (app *stuckApp) Query(req abci.RequestQuery) abci.ResponseQuery {
+	<-app.unblock
+	return app.KVStoreApplication.Query(req)
+}
+
+func startServer(t *testing.T, addr string, app abci.Application) *server.SocketServer {
+	t.Helper()
+
+	srv := server.NewSocketServer(addr, app)
+	require.NoError(t, srv.Start())
+	t.Cleanup(func() {
+		if srv.IsRunning() {
+			srv.Stop()
+		}
+	})
+	return srv
+}
+
+func startClient(t *testing.T, addr string, opts abcicli.SocketOptions) abcicli.Client {
+	t.Helper()
+
+	cli := abcicli.NewSocketClient(addr, true, opts)
+	require.NoError(t, cli.Start())
+	t.Cleanup(func() { cli.Stop() })
+	return cli
+}
+
+func testAddr(t *testing.T) string {
+	t.Helper()
+
+	return "unix://" + filepath.Join(t.TempDir(), "app.sock")
+}
+
+func TestSocketClient(t *testing.T) {
+	addr := testAddr(t)
+	startServer(t, addr, kvstore.NewKVStoreApplication())
+	cli := startClient(t, addr, abcicli.DefaultSocketOptions())
+
+	resEcho, err := cli.EchoSync("hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", resEcho.Message)
+
+	// async requests are answered in order.
+	var called int32
+	cli.SetResponseCallback(func(req abci.Request, res abci.Response) {
+		atomic.AddInt32(&called, 1)
+	})
+	reqRes1 := cli.DeliverTxAsync(abci.RequestDeliverTx{Tx: []byte("abc=def")})
+	reqRes2 := cli.DeliverTxAsync(abci.RequestDeliverTx{Tx: []byte("ghi=jkl")})
+	require.NoError(t, cli.FlushSync())
+	reqRes1.Wait()
+	reqRes2.Wait()
+	assert.True(t, reqRes1.Response.(abci.ResponseDeliverTx).IsOK())
+	assert.True(t, reqRes2.Response.(abci.ResponseDeliverTx).IsOK())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&called))
+
+	resQuery, err := cli.QuerySync(abci.RequestQuery{Path: "/store", Data: []byte("abc")})
+	require.NoError(t, err)
+	assert.Equal(t, "def", string(resQuery.Value))
+	assert.NoError(t, cli.Error())
+}
+
+func TestSocketClientTimeout(t *testing.T) {
+	addr := testAddr(t)
+	app := &stuckApp{kvstore.NewKVStoreApplication(), make(chan struct{})}
+	startServer(t, addr, app)
+	opts := abcicli.DefaultSocketOptions()
+	opts.RequestTimeout = 200 * time.Millisecond
+	opts.DialRetryInterval = 50 * time.Millisecond
+	cli := startClient(t, addr, opts)
+
+	// the stuck query times out, and the connection is closed.
+	start := time.Now()
+	_, err := cli.QuerySync(abci.RequestQuery{Path: "/store", Data: []byte("abc")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.Less(t, int64(time.Since(start)), int64(2*time.Second))
+	assert.Error(t, cli.Error())
+
+	// once the app is unstuck, the client reconnects.
+	close(app.unblock)
+	require.Eventually(t, func() bool {
+		_, err := cli.EchoSync("hello")
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NoError(t, cli.Error())
+}
+
+func TestSocketClientBackpressure(t *testing.T) {
+	addr := testAddr(t)
+	app := &stuckApp{kvstore.NewKVStoreApplication(), make(chan struct{})}
+	startServer(t, addr, app)
+	opts := abcicli.DefaultSocketOptions()
+	opts.RequestTimeout = 5 * time.Second
+	opts.MaxPendingRequests = 2
+	cli := startClient(t, addr, opts)
+
+	reqRes1 := cli.QueryAsync(abci.RequestQuery{Path: "/store"})
+	reqRes2 := cli.EchoAsync("hello")
+
+	// the third request blocks until one of the others completes.
+	queued := make(chan *abcicli.ReqRes)
+	go func() {
+		queued <- cli.EchoAsync("world")
+	}()
+	select {
+	case <-queued:
+		t.Fatal("request queued with all slots taken")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(app.unblock)
+	var reqRes3 *abcicli.ReqRes
+	select {
+	case reqRes3 = <-queued:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not queued once a slot was free")
+	}
+	for _, reqRes := range []*abcicli.ReqRes{reqRes1, reqRes2, reqRes3} {
+		reqRes.Wait()
+		_, isErr := reqRes.Response.(abci.ResponseException)
+		assert.False(t, isErr, reqRes.Response)
+	}
+	assert.Equal(t, "world", reqRes3.Response.(abci.ResponseEcho).Message)
+}
+
+func TestSocketClientBackpressureTimeout(t *testing.T) {
+	addr := testAddr(t)
+	app := &stuckApp{kvstore.NewKVStoreApplication(), make(chan struct{})}
+	defer close(app.unblock)
+	startServer(t, addr, app)
+	opts := abcicli.DefaultSocketOptions()
+	opts.RequestTimeout = 200 * time.Millisecond
+	opts.MaxPendingRequests = 1
+	cli := startClient(t, addr, opts)
+
+	cli.QueryAsync(abci.RequestQuery{Path: "/store"})
+	_, err := cli.EchoSync("hello")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 requests pending")
+}
+
+func TestSocketClientReconnect(t *testing.T) {
+	addr := testAddr(t)
+	srv := startServer(t, addr, kvstore.NewKVStoreApplication())
+	var reconnected int32
+	opts := abcicli.DefaultSocketOptions()
+	opts.DialRetryInterval = 50 * time.Millisecond
+	opts.OnReconnect = func() error {
+		// e.g. the handshake, failing once.
+		if atomic.AddInt32(&reconnected, 1) == 1 {
+			return assert.AnError
+		}
+		return nil
+	}
+	cli := startClient(t, addr, opts)
+
+	_, err := cli.DeliverTxSync(abci.RequestDeliverTx{Tx: []byte("abc=def")})
+	require.NoError(t, err)
+
+	// the app restarts without its state.
+	require.NoError(t, srv.Stop())
+	require.Eventually(t, func() bool {
+		return cli.Error() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	startServer(t, addr, kvstore.NewKVStoreApplication())
+
+	// requests queued while disconnected are sent once reconnected.
+	resQuery, err := cli.QuerySync(abci.RequestQuery{Path: "/store", Data: []byte("abc")})
+	require.NoError(t, err)
+	assert.Empty(t, resQuery.Value)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&reconnected))
+	assert.NoError(t, cli.Error())
+}
@@ -0,0 +1,196 @@
+// Package server serves an ABCI application to the out-of-process clients
+// of pkgs/bft/abci/client over a socket.
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/errors"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/service"
+)
+
+// MaxRequestSize is the maximum size of an encoded request.
+const MaxRequestSize = 64 * 1024 * 1024 // 64MB
+
+// SocketServer serves app on a socket.  The requests of each connection are
+// handled in order, and those of all connections are serialized by a mutex
+// like with the local client.
+type SocketServer struct {
+	service.BaseService
+
+	proto    string
+	addr     string
+	listener net.Listener
+
+	connsMtx   sync.Mutex
+	conns      map[int]net.Conn
+	nextConnID int
+
+	appMtx sync.Mutex
+	app    abci.Application
+}
+
+// NewSocketServer returns a server of app listening on protoAddr, e.g.
+// "tcp://127.0.0.1:26658" or "unix:///tmp/app.sock".
+func NewSocketServer(protoAddr string, app abci.Application) *SocketServer {
+	proto, addr := osm.ProtocolAndAddress(protoAddr)
+	s := &SocketServer{
+		proto: proto,
+		addr:  addr,
+		conns: make(map[int]net.Conn),
+		app:   app,
+	}
+	s.BaseService = *service.NewBaseService(nil, "ABCIServer", s)
+	return s
+}
+
+func (s *SocketServer) OnStart() error {
+	ln, err := net.Listen(s.proto, s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = ln
+	go s.acceptConnectionsRoutine()
+	return nil
+}
+
+func (s *SocketServer) OnStop() {
+	if err := s.listener.Close(); err != nil {
+		s.Logger.Error("Error closing listener", "err", err)
+	}
+	s.connsMtx.Lock()
+	defer s.connsMtx.Unlock()
+	for id, conn := range s.conns {
+		delete(s.conns, id)
+		if err := conn.Close(); err != nil {
+			s.Logger.Error("Error closing connection", "id", id, "conn", conn, "err", err)
+		}
+	}
+}
+
+// Addr returns the address the server listens on, e.g. with the port
+// chosen for ":0".
+func (s *SocketServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *SocketServer) addConn(conn net.Conn) int {
+	s.connsMtx.Lock()
+	defer s.connsMtx.Unlock()
+
+	connID := s.nextConnID
+	s.nextConnID++
+	s.conns[connID] = conn
+	return connID
+}
+
+func (s *SocketServer) rmConn(connID int) {
+	s.connsMtx.Lock()
+	defer s.connsMtx.Unlock()
+
+	if conn, ok := s.conns[connID]; ok {
+		conn.Close()
+		delete(s.conns, connID)
+	}
+}
+
+func (s *SocketServer) acceptConnectionsRoutine() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !s.IsRunning() {
+				return // Ignore error from listener closing.
+			}
+			s.Logger.Error("Failed to accept connection", "err", err)
+			continue
+		}
+		s.Logger.Info("Accepted a new connection")
+		connID := s.addConn(conn)
+		go s.handleConnection(connID, conn)
+	}
+}
+
+// Handles the requests of conn, until it is closed.  The responses are
+// buffered, and flushed once no more requests are buffered.
+func (s *SocketServer) handleConnection(connID int, conn net.Conn) {
+	defer s.rmConn(connID)
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		var req abci.Request
+		_, err := amino.UnmarshalSizedReader(r, &req, MaxRequestSize)
+		if err != nil {
+			if err != io.EOF && s.IsRunning() {
+				s.Logger.Error("Error reading request", "err", err)
+			}
+			return
+		}
+		res := s.handleRequest(req)
+		if _, err := amino.MarshalAnySizedWriter(w, res); err != nil {
+			s.Logger.Error("Error writing response", "err", err)
+			return
+		}
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				s.Logger.Error("Error flushing responses", "err", err)
+				return
+			}
+		}
+	}
+}
+
+func (s *SocketServer) handleRequest(req abci.Request) (res abci.Response) {
+	s.appMtx.Lock()
+	defer s.appMtx.Unlock()
+
+	// The app may panic, e.g. on a malicious tx or query.
+	defer func() {
+		if r := recover(); r != nil {
+			s.Logger.Error("Panic handling request", "req", req, "err", r)
+			res = abci.ResponseException{
+				ResponseBase: abci.ResponseBase{
+					Error: abci.StringError(fmt.Sprintf("%v", r)),
+				},
+			}
+		}
+	}()
+
+	switch req := req.(type) {
+	case abci.RequestEcho:
+		return abci.ResponseEcho{Message: req.Message}
+	case abci.RequestFlush:
+		return abci.ResponseFlush{}
+	case abci.RequestInfo:
+		return s.app.Info(req)
+	case abci.RequestSetOption:
+		return s.app.SetOption(req)
+	case abci.RequestInitChain:
+		return s.app.InitChain(req)
+	case abci.RequestQuery:
+		return s.app.Query(req)
+	case abci.RequestBeginBlock:
+		return s.app.BeginBlock(req)
+	case abci.RequestCheckTx:
+		return s.app.CheckTx(req)
+	case abci.RequestDeliverTx:
+		return s.app.DeliverTx(req)
+	case abci.RequestEndBlock:
+		return s.app.EndBlock(req)
+	case abci.RequestCommit:
+		return s.app.Commit()
+	default:
+		return abci.ResponseException{
+			ResponseBase: abci.ResponseBase{
+				Error: abci.StringError(errors.New("unknown request type %T", req).Error()),
+			},
+		}
+	}
+}
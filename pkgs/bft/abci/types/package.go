@@ -60,6 +60,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		ConsensusParams{},
 		BlockParams{},
 		ValidatorParams{},
+		EvidenceParams{},
 		ValidatorUpdate{},
 		LastCommitInfo{},
 		VoteInfo{},
@@ -68,6 +69,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 
 		// events
 		EventString(""),
+		EventMsg{},
 
 		// mocks
 		MockHeader{},
@@ -165,12 +165,16 @@ type ResponseCheckTx struct {
 	ResponseBase
 	GasWanted int64 // nondeterministic
 	GasUsed   int64
+	TxHash    []byte // canonical hash of the tx, if defined by the app
+	System    bool   // in the system lane, proposed before the other txs
 }
 
 type ResponseDeliverTx struct {
 	ResponseBase
 	GasWanted int64
 	GasUsed   int64
+	TxHash    []byte     // canonical hash of the tx, if defined by the app
+	EventMsgs []EventMsg // msg of each event, by index, if defined by the app
 }
 
 type ResponseEndBlock struct {
@@ -225,6 +229,12 @@ func (err EventString) Event() string {
 	return string(err)
 }
 
+// EventMsg attributes an event of a tx to the msg which emitted it.
+type EventMsg struct {
+	MsgIndex int    // in the msgs of the tx
+	MsgType  string // route and type of the msg, e.g. "bank/send"
+}
+
 //----------------------------------------
 // Misc
 
@@ -232,6 +242,7 @@ func (err EventString) Event() string {
 type ConsensusParams struct {
 	Block     *BlockParams
 	Validator *ValidatorParams
+	Evidence  *EvidenceParams
 }
 
 type BlockParams struct {
@@ -240,12 +251,17 @@ type BlockParams struct {
 	MaxBlockBytes int64 // must be > 0
 	MaxGas        int64 // must be >= -1
 	TimeIotaMS    int64 // must be > 0
+	MaxTxGas      int64 // must be >= 0, 0 for no limit but MaxGas
 }
 
 type ValidatorParams struct {
 	PubKeyTypeURLs []string
 }
 
+type EvidenceParams struct {
+	MaxAge int64 // in blocks, must be > 0
+}
+
 type ValidatorUpdate struct {
 	Address crypto.Address
 	PubKey  crypto.PubKey
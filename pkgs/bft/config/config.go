@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	abcicli "github.com/gnolang/gno/pkgs/bft/abci/client"
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
 	cns "github.com/gnolang/gno/pkgs/bft/consensus/config"
 	mem "github.com/gnolang/gno/pkgs/bft/mempool/config"
 	rpc "github.com/gnolang/gno/pkgs/bft/rpc/config"
+	txi "github.com/gnolang/gno/pkgs/bft/state/txindex/config"
+	sto "github.com/gnolang/gno/pkgs/bft/store/config"
+	tel "github.com/gnolang/gno/pkgs/bft/telemetry/config"
+	wh "github.com/gnolang/gno/pkgs/bft/webhook/config"
 	"github.com/gnolang/gno/pkgs/errors"
 	osm "github.com/gnolang/gno/pkgs/os"
 	p2p "github.com/gnolang/gno/pkgs/p2p/config"
@@ -20,20 +26,28 @@ type Config struct {
 	BaseConfig `toml:",squash"`
 
 	// Options for services
-	RPC       *rpc.RPCConfig       `toml:"rpc"`
-	P2P       *p2p.P2PConfig       `toml:"p2p"`
-	Mempool   *mem.MempoolConfig   `toml:"mempool"`
-	Consensus *cns.ConsensusConfig `toml:"consensus"`
+	RPC         *rpc.RPCConfig         `toml:"rpc"`
+	P2P         *p2p.P2PConfig         `toml:"p2p"`
+	Mempool     *mem.MempoolConfig     `toml:"mempool"`
+	Consensus   *cns.ConsensusConfig   `toml:"consensus"`
+	TxIndex     *txi.TxIndexConfig     `toml:"tx_index"`
+	Webhook     *wh.WebhookConfig      `toml:"webhook"`
+	ColdStorage *sto.ColdStorageConfig `toml:"cold_storage"`
+	Telemetry   *tel.TelemetryConfig   `toml:"telemetry"`
 }
 
 // DefaultConfig returns a default configuration for a Tendermint node
 func DefaultConfig() *Config {
 	return &Config{
-		BaseConfig: DefaultBaseConfig(),
-		RPC:        rpc.DefaultRPCConfig(),
-		P2P:        p2p.DefaultP2PConfig(),
-		Mempool:    mem.DefaultMempoolConfig(),
-		Consensus:  cns.DefaultConsensusConfig(),
+		BaseConfig:  DefaultBaseConfig(),
+		RPC:         rpc.DefaultRPCConfig(),
+		P2P:         p2p.DefaultP2PConfig(),
+		Mempool:     mem.DefaultMempoolConfig(),
+		Consensus:   cns.DefaultConsensusConfig(),
+		TxIndex:     txi.DefaultTxIndexConfig(),
+		Webhook:     wh.DefaultWebhookConfig(),
+		ColdStorage: sto.DefaultColdStorageConfig(),
+		Telemetry:   tel.DefaultTelemetryConfig(),
 	}
 }
 
@@ -59,6 +73,9 @@ func LoadOrMakeConfigWithOptions(root string, options ConfigOptions) (cfg *Confi
 		cfg.EnsureDirs()
 		WriteConfigFile(configPath, cfg)
 	}
+	if err := cfg.ApplyProfile(); err != nil {
+		panic(err)
+	}
 	if err := cfg.ValidateBasic(); err != nil {
 		panic(err)
 	}
@@ -68,11 +85,15 @@ func LoadOrMakeConfigWithOptions(root string, options ConfigOptions) (cfg *Confi
 // TestConfig returns a configuration that can be used for testing
 func TestConfig() *Config {
 	return &Config{
-		BaseConfig: TestBaseConfig(),
-		RPC:        rpc.TestRPCConfig(),
-		P2P:        p2p.TestP2PConfig(),
-		Mempool:    mem.TestMempoolConfig(),
-		Consensus:  cns.TestConsensusConfig(),
+		BaseConfig:  TestBaseConfig(),
+		RPC:         rpc.TestRPCConfig(),
+		P2P:         p2p.TestP2PConfig(),
+		Mempool:     mem.TestMempoolConfig(),
+		Consensus:   cns.TestConsensusConfig(),
+		TxIndex:     txi.TestTxIndexConfig(),
+		Webhook:     wh.TestWebhookConfig(),
+		ColdStorage: sto.TestColdStorageConfig(),
+		Telemetry:   tel.TestTelemetryConfig(),
 	}
 }
 
@@ -83,6 +104,9 @@ func (cfg *Config) SetRootDir(root string) *Config {
 	cfg.P2P.RootDir = root
 	cfg.Mempool.RootDir = root
 	cfg.Consensus.RootDir = root
+	cfg.Webhook.RootDir = root
+	cfg.ColdStorage.RootDir = root
+	cfg.Telemetry.RootDir = root
 	return cfg
 }
 
@@ -118,6 +142,18 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [consensus] section")
 	}
+	if err := cfg.TxIndex.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [tx_index] section")
+	}
+	if err := cfg.Webhook.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [webhook] section")
+	}
+	if err := cfg.ColdStorage.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [cold_storage] section")
+	}
+	if err := cfg.Telemetry.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [telemetry] section")
+	}
 	return nil
 }
 
@@ -168,6 +204,11 @@ type BaseConfig struct {
 	// A custom human readable name for this node
 	Moniker string `toml:"moniker"`
 
+	// Profile of the options tuned for the kind of network, among local,
+	// lan and wan (see Profiles), whose options override those of the
+	// config file.  Empty for none.
+	Profile string `toml:"profile"`
+
 	// If this node is many blocks behind the tip of the chain, FastSync
 	// allows them to catchup quickly by downloading blocks in parallel
 	// and verifying their commits
@@ -215,9 +256,30 @@ type BaseConfig struct {
 	// Mechanism to connect to the ABCI application: local | socket
 	ABCI string `toml:"abci"`
 
+	// Time an ABCI request to the socket application may take, or 0 for no
+	// limit.  The connection to an application timing out is closed and
+	// redialed, and the blocks it lost are replayed to it.
+	ABCIRequestTimeout time.Duration `toml:"abci_request_timeout"`
+
+	// Maximum number of ABCI requests queued or in flight per connection to
+	// the socket application, beyond which the requests block
+	ABCIMaxPendingRequests int `toml:"abci_max_pending_requests"`
+
 	// TCP or UNIX socket address for the profiling server to listen on
 	ProfListenAddress string `toml:"prof_laddr"`
 
+	// TCP address for the server of the metrics in the Prometheus format
+	// to listen on, at /metrics
+	PrometheusListenAddress string `toml:"prometheus_laddr"`
+
+	// TCP address for the debug server to listen on, serving pprof, expvar,
+	// goroutine dumps and codec stats to the admin
+	DebugListenAddress string `toml:"debug_laddr"`
+
+	// Token of the admin, required by the debug server in the header
+	// "Authorization: Bearer <token>"
+	DebugAuthToken string `toml:"debug_auth_token"`
+
 	// If true, query the ABCI app on connecting to a new peer
 	// so the app can decide if we should keep the connection or not
 	FilterPeers bool `toml:"filter_peers"` // false
@@ -226,20 +288,22 @@ type BaseConfig struct {
 // DefaultBaseConfig returns a default base configuration for a Tendermint node
 func DefaultBaseConfig() BaseConfig {
 	return BaseConfig{
-		Genesis:            defaultGenesisJSONPath,
-		PrivValidatorKey:   defaultPrivValKeyPath,
-		PrivValidatorState: defaultPrivValStatePath,
-		NodeKey:            defaultNodeKeyPath,
-		Moniker:            defaultMoniker,
-		ProxyApp:           "tcp://127.0.0.1:26658",
-		ABCI:               "socket",
-		LogLevel:           DefaultPackageLogLevels(),
-		LogFormat:          LogFormatPlain,
-		ProfListenAddress:  "",
-		FastSyncMode:       true,
-		FilterPeers:        false,
-		DBBackend:          "goleveldb",
-		DBPath:             "data",
+		Genesis:                defaultGenesisJSONPath,
+		PrivValidatorKey:       defaultPrivValKeyPath,
+		PrivValidatorState:     defaultPrivValStatePath,
+		NodeKey:                defaultNodeKeyPath,
+		Moniker:                defaultMoniker,
+		ProxyApp:               "tcp://127.0.0.1:26658",
+		ABCI:                   "socket",
+		ABCIRequestTimeout:     abcicli.DefaultSocketOptions().RequestTimeout,
+		ABCIMaxPendingRequests: abcicli.DefaultSocketOptions().MaxPendingRequests,
+		LogLevel:               DefaultPackageLogLevels(),
+		LogFormat:              LogFormatPlain,
+		ProfListenAddress:      "",
+		FastSyncMode:           true,
+		FilterPeers:            false,
+		DBBackend:              "goleveldb",
+		DBPath:                 "data",
 	}
 }
 
@@ -272,6 +336,15 @@ func (cfg BaseConfig) PrivValidatorStateFile() string {
 	return join(cfg.RootDir, cfg.PrivValidatorState)
 }
 
+// ABCISocketOptions returns the options of the clients of the socket
+// application.
+func (cfg BaseConfig) ABCISocketOptions() abcicli.SocketOptions {
+	opts := abcicli.DefaultSocketOptions()
+	opts.RequestTimeout = cfg.ABCIRequestTimeout
+	opts.MaxPendingRequests = cfg.ABCIMaxPendingRequests
+	return opts
+}
+
 // NodeKeyFile returns the full path to the node_key.json file
 func (cfg BaseConfig) NodeKeyFile() string {
 	return join(cfg.RootDir, cfg.NodeKey)
@@ -290,6 +363,18 @@ func (cfg BaseConfig) ValidateBasic() error {
 	default:
 		return errors.New("unknown log_format (must be 'plain' or 'json')")
 	}
+	if _, ok := Profiles[cfg.Profile]; cfg.Profile != "" && !ok {
+		return errors.New("unknown profile %q (must be one of %v)", cfg.Profile, ProfileNames())
+	}
+	if cfg.ABCIRequestTimeout < 0 {
+		return errors.New("abci_request_timeout can't be negative")
+	}
+	if cfg.ABCIMaxPendingRequests < 0 {
+		return errors.New("abci_max_pending_requests can't be negative")
+	}
+	if cfg.DebugListenAddress != "" && cfg.DebugAuthToken == "" {
+		return errors.New("debug_auth_token must be set to serve debug_laddr")
+	}
 	return nil
 }
 
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// Options of the config are named by their keys in the config file, with
+// those of sections prefixed by the section, e.g. "moniker" or
+// "consensus.timeout_commit".  The root directory ("home") and the test
+// settings ("test_*") are not options, and are not written to the config
+// file.
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Keys returns the sorted keys of the options of cfg.
+func (cfg *Config) Keys() []string {
+	keys := []string{}
+	forEachOption(reflect.ValueOf(cfg).Elem(), "", func(key string, _ reflect.Value) {
+		keys = append(keys, key)
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of the option at key, as written in flags, e.g.
+// "1s" for a duration, or comma separated values for a list.
+func (cfg *Config) Get(key string) (string, error) {
+	field, ok := cfg.option(key)
+	if !ok {
+		return "", errors.New("unknown config option %s", key)
+	}
+	if field.Type() == durationType {
+		return time.Duration(field.Int()).String(), nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'g', -1, 64), nil
+	case reflect.Slice:
+		return strings.Join(field.Interface().([]string), ","), nil
+	default:
+		panic(fmt.Sprintf("unexpected config option type %v", field.Type()))
+	}
+}
+
+// Set parses value as Get formats it, and sets it as the option at key.
+func (cfg *Config) Set(key, value string) error {
+	field, ok := cfg.option(key)
+	if !ok {
+		return errors.New("unknown config option %s", key)
+	}
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("invalid duration %q for %s", value, key)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("invalid bool %q for %s", value, key)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("invalid integer %q for %s", value, key)
+		}
+		field.SetInt(i)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("invalid number %q for %s", value, key)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		values := []string{}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		panic(fmt.Sprintf("unexpected config option type %v", field.Type()))
+	}
+	return nil
+}
+
+// ApplyEnv sets the options of cfg from the environment variables named
+// by prefix and their keys, in upper case and with "." replaced by "_",
+// e.g. GNOLAND_CONSENSUS_TIMEOUT_COMMIT for the prefix "GNOLAND".  It
+// returns the keys of the options set.  A profile set in the environment
+// is applied before the other options of the environment.
+func (cfg *Config) ApplyEnv(prefix string) ([]string, error) {
+	set := []string{}
+	envName := func(key string) string {
+		return prefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	}
+	if value, ok := os.LookupEnv(envName("profile")); ok {
+		cfg.Profile = value
+		if err := cfg.ApplyProfile(); err != nil {
+			return nil, errors.New("environment variable %s: %v", envName("profile"), err)
+		}
+	}
+	for _, key := range cfg.Keys() {
+		name := envName(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := cfg.Set(key, value); err != nil {
+			return nil, errors.New("environment variable %s: %v", name, err)
+		}
+		set = append(set, key)
+	}
+	return set, nil
+}
+
+// Returns the field of the option at key.
+func (cfg *Config) option(key string) (res reflect.Value, ok bool) {
+	forEachOption(reflect.ValueOf(cfg).Elem(), "", func(k string, field reflect.Value) {
+		if k == key {
+			res, ok = field, true
+		}
+	})
+	return
+}
+
+// Calls fn with the key and field of each option of the struct v, whose
+// keys are prefixed by prefix.  Only fields with a toml tag are options.
+func forEachOption(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("toml")
+		if !ok {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		field := v.Field(i)
+		switch {
+		case name == "" && sf.Anonymous:
+			forEachOption(field, prefix, fn) // squashed.
+		case name == "home" || strings.HasPrefix(name, "test_"):
+			// set by the node, or by tests.
+		case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct:
+			if !field.IsNil() {
+				forEachOption(field.Elem(), prefix+name+".", fn)
+			}
+		default:
+			fn(prefix+name, field)
+		}
+	}
+}
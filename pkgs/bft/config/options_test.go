@@ -0,0 +1,111 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigGetSet(t *testing.T) {
+	cfg := DefaultConfig()
+
+	require.NoError(t, cfg.Set("moniker", "foo"))
+	require.NoError(t, cfg.Set("consensus.timeout_commit", "3s"))
+	require.NoError(t, cfg.Set("rpc.cors_allowed_origins", "a, b"))
+	require.NoError(t, cfg.Set("mempool.size", "42"))
+	assert.Equal(t, "foo", cfg.Moniker)
+	assert.Equal(t, 3*time.Second, cfg.Consensus.TimeoutCommit)
+	assert.Equal(t, []string{"a", "b"}, cfg.RPC.CORSAllowedOrigins)
+	assert.Equal(t, 42, cfg.Mempool.Size)
+
+	value, err := cfg.Get("consensus.timeout_commit")
+	require.NoError(t, err)
+	assert.Equal(t, "3s", value)
+	value, err = cfg.Get("rpc.cors_allowed_origins")
+	require.NoError(t, err)
+	assert.Equal(t, "a,b", value)
+
+	assert.Error(t, cfg.Set("mempool.size", "foo"))
+	assert.Error(t, cfg.Set("home", "/tmp"))
+	assert.Error(t, cfg.Set("consensus.timeout", "1s"))
+	_, err = cfg.Get("consensus")
+	assert.Error(t, err)
+}
+
+func TestConfigApplyEnv(t *testing.T) {
+	cfg := DefaultConfig()
+	t.Setenv("TEST_CONSENSUS_TIMEOUT_COMMIT", "5s")
+	t.Setenv("TEST_MONIKER", "bar")
+	set, err := cfg.ApplyEnv("TEST")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"consensus.timeout_commit", "moniker"}, set)
+	assert.Equal(t, 5*time.Second, cfg.Consensus.TimeoutCommit)
+	assert.Equal(t, "bar", cfg.Moniker)
+
+	t.Setenv("TEST_P2P_MAX_NUM_INBOUND_PEERS", "many")
+	_, err = cfg.ApplyEnv("TEST")
+	assert.Error(t, err)
+}
+
+// All options are written to the config file.
+func TestConfigFileOptions(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.SetRootDir(dir)
+	cfg.EnsureDirs()
+	expected := map[string]string{}
+	for i, key := range cfg.Keys() {
+		value, err := cfg.Get(key)
+		require.NoError(t, err)
+		switch value {
+		case "true":
+			value = "false"
+		case "false":
+			value = "true"
+		default:
+			if _, err := time.ParseDuration(value); err == nil && value != "0" {
+				value = strconv.Itoa(i) + "s"
+			} else {
+				value = strconv.Itoa(i)
+			}
+		}
+		require.NoError(t, cfg.Set(key, value))
+		expected[key], _ = cfg.Get(key)
+	}
+	path := join(dir, defaultConfigFilePath)
+	WriteConfigFile(path, cfg)
+
+	cfg2, err := ReadConfigFile(path)
+	require.NoError(t, err)
+	for key, value := range expected {
+		value2, err := cfg2.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, value, value2, key)
+	}
+}
+
+func TestReadConfigFileUnknownKey(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.SetRootDir(dir)
+	cfg.EnsureDirs()
+	path := join(dir, defaultConfigFilePath)
+	WriteConfigFile(path, cfg)
+	_, err := ReadConfigFile(path)
+	require.NoError(t, err)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString("\n[consensus]\ntimeout_comit = \"1s\"\n")
+	require.NoError(t, err)
+	f.Close()
+	bz, _ := ioutil.ReadFile(path)
+	require.Contains(t, string(bz), "timeout_comit")
+	_, err = ReadConfigFile(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// Profiles are the named sets of options tuned together for a kind of
+// network, which set the consensus timeouts, the mempool sizes and the p2p
+// limits coherently:
+//
+//   - "local": nodes on a single machine, e.g. a local devnet, with
+//     negligible latencies and fast blocks;
+//   - "lan": nodes in a datacenter or a private testnet, with low latencies;
+//   - "wan": nodes of a public network over the internet, e.g. a mainnet.
+var Profiles = map[string]map[string]string{
+	"local": {
+		"consensus.timeout_propose":                 "1s",
+		"consensus.timeout_propose_delta":           "100ms",
+		"consensus.timeout_prevote":                 "200ms",
+		"consensus.timeout_prevote_delta":           "100ms",
+		"consensus.timeout_precommit":               "200ms",
+		"consensus.timeout_precommit_delta":         "100ms",
+		"consensus.timeout_commit":                  "500ms",
+		"consensus.skip_timeout_commit":             "true",
+		"consensus.peer_gossip_sleep_duration":      "10ms",
+		"consensus.peer_query_maj23_sleep_duration": "500ms",
+		"mempool.size":                              "5000",
+		"mempool.cache_size":                        "10000",
+		"p2p.max_num_inbound_peers":                 "10",
+		"p2p.max_num_outbound_peers":                "10",
+		"p2p.flush_throttle_timeout":                "10ms",
+		"p2p.send_rate":                             "20480000",
+		"p2p.recv_rate":                             "20480000",
+		"p2p.handshake_timeout":                     "5s",
+		"p2p.dial_timeout":                          "1s",
+		"p2p.allow_duplicate_ip":                    "true",
+	},
+	"lan": {
+		"consensus.timeout_propose":                 "2s",
+		"consensus.timeout_propose_delta":           "500ms",
+		"consensus.timeout_prevote":                 "500ms",
+		"consensus.timeout_prevote_delta":           "500ms",
+		"consensus.timeout_precommit":               "500ms",
+		"consensus.timeout_precommit_delta":         "500ms",
+		"consensus.timeout_commit":                  "1s",
+		"consensus.skip_timeout_commit":             "false",
+		"consensus.peer_gossip_sleep_duration":      "50ms",
+		"consensus.peer_query_maj23_sleep_duration": "1s",
+		"mempool.size":                              "5000",
+		"mempool.cache_size":                        "10000",
+		"p2p.max_num_inbound_peers":                 "40",
+		"p2p.max_num_outbound_peers":                "10",
+		"p2p.flush_throttle_timeout":                "50ms",
+		"p2p.send_rate":                             "10240000",
+		"p2p.recv_rate":                             "10240000",
+		"p2p.handshake_timeout":                     "10s",
+		"p2p.dial_timeout":                          "3s",
+		"p2p.allow_duplicate_ip":                    "false",
+	},
+	"wan": {
+		"consensus.timeout_propose":                 "3s",
+		"consensus.timeout_propose_delta":           "500ms",
+		"consensus.timeout_prevote":                 "1s",
+		"consensus.timeout_prevote_delta":           "500ms",
+		"consensus.timeout_precommit":               "1s",
+		"consensus.timeout_precommit_delta":         "500ms",
+		"consensus.timeout_commit":                  "5s",
+		"consensus.skip_timeout_commit":             "false",
+		"consensus.peer_gossip_sleep_duration":      "100ms",
+		"consensus.peer_query_maj23_sleep_duration": "2s",
+		"mempool.size":                              "10000",
+		"mempool.cache_size":                        "20000",
+		"p2p.max_num_inbound_peers":                 "40",
+		"p2p.max_num_outbound_peers":                "10",
+		"p2p.flush_throttle_timeout":                "100ms",
+		"p2p.send_rate":                             "5120000",
+		"p2p.recv_rate":                             "5120000",
+		"p2p.handshake_timeout":                     "20s",
+		"p2p.dial_timeout":                          "3s",
+		"p2p.allow_duplicate_ip":                    "false",
+	},
+}
+
+// ProfileNames returns the sorted names of the profiles.
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile sets the options of the profile of cfg, if any, overriding
+// their values.
+func (cfg *Config) ApplyProfile() error {
+	if cfg.Profile == "" {
+		return nil
+	}
+	profile, ok := Profiles[cfg.Profile]
+	if !ok {
+		return errors.New("unknown profile %q (must be one of %v)", cfg.Profile, ProfileNames())
+	}
+	for key, value := range profile {
+		if err := cfg.Set(key, value); err != nil {
+			return errors.Wrap(err, "profile %q", cfg.Profile)
+		}
+	}
+	return nil
+}
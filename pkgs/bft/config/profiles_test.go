@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	for _, name := range ProfileNames() {
+		cfg := DefaultConfig()
+		cfg.Profile = name
+		require.NoError(t, cfg.ApplyProfile(), name)
+		require.NoError(t, cfg.ValidateBasic(), name)
+		for key, value := range Profiles[name] {
+			got, err := cfg.Get(key)
+			require.NoError(t, err)
+			v1, _ := time.ParseDuration(value)
+			v2, _ := time.ParseDuration(got)
+			if v1 == 0 {
+				assert.Equal(t, value, got, key)
+			} else {
+				assert.Equal(t, v1, v2, key)
+			}
+		}
+	}
+	// the profiles set the same options.
+	for _, name := range ProfileNames() {
+		assert.Equal(t, len(Profiles["wan"]), len(Profiles[name]), name)
+		for key := range Profiles["wan"] {
+			assert.Contains(t, Profiles[name], key, name)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.Profile = "moon"
+	assert.Error(t, cfg.ApplyProfile())
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := LoadOrMakeConfigWithOptions(dir, func(cfg *Config) {
+		cfg.Profile = "local"
+		cfg.Consensus.TimeoutCommit = 10 * time.Second
+	})
+	assert.Equal(t, 500*time.Millisecond, cfg.Consensus.TimeoutCommit)
+	assert.True(t, cfg.P2P.AllowDuplicateIP)
+
+	// the profile overrides the config file, but not the environment.
+	cfg, err := ReadConfigFile(ConfigFilePath(dir))
+	require.NoError(t, err)
+	assert.Equal(t, "local", cfg.Profile)
+	t.Setenv("TEST_PROFILE", "wan")
+	t.Setenv("TEST_CONSENSUS_TIMEOUT_COMMIT", "7s")
+	_, err = cfg.ApplyEnv("TEST")
+	require.NoError(t, err)
+	assert.Equal(t, "wan", cfg.Profile)
+	assert.Equal(t, 7*time.Second, cfg.Consensus.TimeoutCommit)
+	assert.Equal(t, 20*time.Second, cfg.P2P.HandshakeTimeout)
+
+	t.Setenv("TEST_PROFILE", "moon")
+	_, err = cfg.ApplyEnv("TEST")
+	assert.Error(t, err)
+}
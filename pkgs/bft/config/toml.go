@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"text/template"
 
+	"github.com/gnolang/gno/pkgs/errors"
 	osm "github.com/gnolang/gno/pkgs/os"
 	"github.com/pelletier/go-toml"
 )
@@ -23,17 +24,32 @@ func init() {
 	}
 }
 
+// ConfigFilePath returns the path of the config file in root.
+func ConfigFilePath(root string) string {
+	return join(root, defaultConfigFilePath)
+}
+
 func LoadConfigFile(configFilePath string) *Config {
-	bz, err := ioutil.ReadFile(configFilePath)
+	config, err := ReadConfigFile(configFilePath)
 	if err != nil {
 		panic(err)
 	}
+	return config
+}
+
+// ReadConfigFile reads the config file at configFilePath, and returns an
+// error if it has keys which are not options, e.g. misspelled ones.
+func ReadConfigFile(configFilePath string) (*Config, error) {
+	bz, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
 	var config Config
-	err = toml.Unmarshal(bz, &config)
+	err = toml.NewDecoder(bytes.NewReader(bz)).Strict(true).Decode(&config)
 	if err != nil {
-		panic(err)
+		return nil, errors.New("config file %s: %v", configFilePath, err)
 	}
-	return &config
+	return &config, nil
 }
 
 /****** these are for production settings ***********/
@@ -63,6 +79,11 @@ proxy_app = "{{ .BaseConfig.ProxyApp }}"
 # A custom human readable name for this node
 moniker = "{{ .BaseConfig.Moniker }}"
 
+# Profile of the options tuned for the kind of network: local | lan | wan
+# Its consensus timeouts, mempool sizes and p2p limits override those of
+# this file.  Empty for none.
+profile = "{{ .BaseConfig.Profile }}"
+
 # If this node is many blocks behind the tip of the chain, FastSync
 # allows them to catchup quickly by downloading blocks in parallel
 # and verifying their commits
@@ -112,9 +133,29 @@ node_key_file = "{{ js .BaseConfig.NodeKey }}"
 # Mechanism to connect to the ABCI application: socket | grpc
 abci = "{{ .BaseConfig.ABCI }}"
 
+# Time an ABCI request to the socket application may take, or 0 for no limit.
+# The connection to an application timing out is closed and redialed, and the
+# blocks it lost are replayed to it.
+abci_request_timeout = "{{ .BaseConfig.ABCIRequestTimeout }}"
+
+# Maximum number of ABCI requests queued or in flight per connection to the
+# socket application, beyond which the requests block
+abci_max_pending_requests = {{ .BaseConfig.ABCIMaxPendingRequests }}
+
 # TCP or UNIX socket address for the profiling server to listen on
 prof_laddr = "{{ .BaseConfig.ProfListenAddress }}"
 
+# TCP address for the server of the metrics in the Prometheus format to listen on, at /metrics
+prometheus_laddr = "{{ .BaseConfig.PrometheusListenAddress }}"
+
+# TCP address for the debug server to listen on, serving pprof, expvar,
+# goroutine dumps and codec stats to the admin
+debug_laddr = "{{ .BaseConfig.DebugListenAddress }}"
+
+# Token of the admin, required by the debug server in the header
+# "Authorization: Bearer <token>"
+debug_auth_token = "{{ .BaseConfig.DebugAuthToken }}"
+
 # If true, query the ABCI app on connecting to a new peer
 # so the app can decide if we should keep the connection or not
 filter_peers = {{ .BaseConfig.FilterPeers }}
@@ -175,6 +216,10 @@ max_body_bytes = {{ .RPC.MaxBodyBytes }}
 # Maximum size of request header, in bytes
 max_header_bytes = {{ .RPC.MaxHeaderBytes }}
 
+# If true, the responses to /abci_query are signed with the node key,
+# for clients which trust the node to detect responses tampered with.
+sign_queries = {{ .RPC.SignQueries }}
+
 # The path to a file containing certificate that is used to create the HTTPS server.
 # Migth be either absolute path or path related to tendermint's config directory.
 # If the certificate is signed by a certificate authority,
@@ -206,6 +251,9 @@ seeds = "{{ .P2P.Seeds }}"
 # Comma separated list of nodes to keep persistent connections to
 persistent_peers = "{{ .P2P.PersistentPeers }}"
 
+# Path to the address book, with the known peers and the banned IDs and IPs
+addr_book_file = "{{ js .P2P.AddrBook }}"
+
 # UPNP port forwarding
 upnp = {{ .P2P.UPNP }}
 
@@ -236,6 +284,14 @@ pex = {{ .P2P.PexReactor }}
 # Does not work if the peer-exchange reactor is disabled.
 seed_mode = {{ .P2P.SeedMode }}
 
+# Interval between the crawls of the known peers, reporting their
+# reachability, versions and latencies on /net_topology, e.g. in seed
+# mode.  0 disables the crawler.
+crawl_interval = "{{ .P2P.CrawlInterval }}"
+
+# Path to the JSON export of the last crawl report, if any
+crawl_report_file = "{{ js .P2P.CrawlReport }}"
+
 # Comma separated list of peer IDs to keep private (will not be gossiped to other peers)
 private_peer_ids = "{{ .P2P.PrivatePeerIDs }}"
 
@@ -250,6 +306,12 @@ dial_timeout = "{{ .P2P.DialTimeout }}"
 [mempool]
 
 recheck = {{ .Mempool.Recheck }}
+
+# Number of txs rechecked at once after a block is committed.  The txs are
+# rechecked in the background, by batches between which the mempool is
+# unlocked, so the commit doesn't wait for the recheck.  0 rechecks all the
+# txs during the commit.
+recheck_batch_size = {{ .Mempool.RecheckBatchSize }}
 broadcast = {{ .Mempool.Broadcast }}
 wal_dir = "{{ js .Mempool.WalPath }}"
 
@@ -264,6 +326,11 @@ max_pending_txs_bytes = {{ .Mempool.MaxPendingTxsBytes }}
 # Size of the cache (used to filter transactions we saw earlier) in transactions
 cache_size = {{ .Mempool.CacheSize }}
 
+# Gas of each proposed block reserved to the txs of the system lane, e.g.
+# oracle updates, which the app marks in CheckTx.  They are proposed before
+# the other txs, up to this gas.  0 disables the system lane.
+system_lane_gas = {{ .Mempool.SystemLaneGas }}
+
 ##### consensus configuration options #####
 [consensus]
 
@@ -287,6 +354,94 @@ create_empty_blocks_interval = "{{ .Consensus.CreateEmptyBlocksInterval }}"
 # Reactor sleep duration parameters
 peer_gossip_sleep_duration = "{{ .Consensus.PeerGossipSleepDuration }}"
 peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
+
+##### transactions indexer configuration options #####
+[tx_index]
+
+# What indexer to use for transactions
+#
+# Options:
+#   1) "null"
+#   2) "kv" (default) - the simplest possible indexer, backed by key-value
+#   storage (defaults to levelDB; see DBBackend), indexing the txs by their
+#   hash for /tx.
+indexer = "{{ .TxIndex.Indexer }}"
+
+##### webhook configuration options #####
+[webhook]
+
+# URLs of the webhooks notified of the events of the chain with POST
+# requests; none disables the notifications
+urls = [{{ range .Webhook.URLs }}{{ printf "%q, " . }}{{end}}]
+
+# Kinds of events notified: new_block, tx and validator_set_updates
+events = [{{ range .Webhook.Events }}{{ printf "%q, " . }}{{end}}]
+
+# Query of the txs notified, e.g.
+# "event.type=vm.RealmCallEvent AND event.pkg_path=gno.land/r/demo/boards";
+# empty for all the txs
+tx_query = "{{ js .Webhook.TxQuery }}"
+
+# Secret of the HMAC-SHA256 signatures of the requests, in the header
+# "X-Webhook-Signature: sha256=<hex>"
+secret = "{{ js .Webhook.Secret }}"
+
+# Timeout of a request
+timeout = "{{ .Webhook.Timeout }}"
+
+# Number of retries of a failed request, after a backoff doubled on each retry
+max_retries = {{ .Webhook.MaxRetries }}
+retry_backoff = "{{ .Webhook.RetryBackoff }}"
+
+# Number of notifications queued for a webhook, after which they are
+# dead-lettered until it catches up
+queue_size = {{ .Webhook.QueueSize }}
+
+# File the notifications which could not be delivered are appended to, one
+# JSON per line; empty to only log them
+dead_letter_file = "{{ js .Webhook.DeadLetterPath }}"
+
+##### cold storage configuration options #####
+[cold_storage]
+
+# Number of the last heights whose blocks are kept in the DB of the node; the
+# older blocks are moved to the cold storage, from which they are fetched on
+# demand, e.g. for the RPC.  0 disables the cold storage
+offload_after = {{ .ColdStorage.OffloadAfter }}
+
+# Backend of the cold storage: "file", a directory, e.g. on a cheaper disk, or
+# "s3", a bucket of an S3-compatible object store
+backend = "{{ .ColdStorage.Backend }}"
+
+# Directory of the file backend
+dir = "{{ js .ColdStorage.Dir }}"
+
+# Endpoint, e.g. "https://s3.us-east-1.amazonaws.com", region and bucket of the
+# s3 backend, and the key the requests are signed with, by default from the
+# AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+s3_endpoint = "{{ js .ColdStorage.S3Endpoint }}"
+s3_region = "{{ js .ColdStorage.S3Region }}"
+s3_bucket = "{{ js .ColdStorage.S3Bucket }}"
+s3_access_key = "{{ js .ColdStorage.S3AccessKey }}"
+s3_secret_key = "{{ js .ColdStorage.S3SecretKey }}"
+
+##### telemetry configuration options #####
+[telemetry]
+
+# URL the anonymized health of the node is reported to with POST requests,
+# opt-in: the version, height, peer count and sync status, without the ID,
+# moniker or address of the node; empty disables the reports
+endpoint = "{{ js .Telemetry.Endpoint }}"
+
+# Interval of the reports
+interval = "{{ .Telemetry.Interval }}"
+
+# Timeout of a report request
+timeout = "{{ .Telemetry.Timeout }}"
+
+# File of the status of the node in JSON, updated on every block for the
+# external monitoring agents; empty disables it
+status_file = "{{ js .Telemetry.StatusPath }}"
 `
 
 /****** these are for test settings ***********/
@@ -0,0 +1,214 @@
+package consensus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	cstypes "github.com/gnolang/gno/pkgs/bft/consensus/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+)
+
+// Buckets of the histograms of durations, in seconds, and of rounds.
+var (
+	durationBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	roundsBuckets   = []float64{1, 2, 3, 5, 10}
+)
+
+// Metrics are the metrics of the consensus of a node, served in the text
+// format of Prometheus: the rounds per committed height, the time spent in
+// each step, the proposals missed by each validator, and the latencies of
+// the proposals and of the prevotes and precommits since the start of their
+// round.
+type Metrics struct {
+	mtx sync.Mutex
+
+	height          int64
+	rounds          *histogram            // rounds per committed height
+	stepDurations   map[string]*histogram // step -> seconds in step
+	missedProposals map[string]uint64     // proposer address -> count
+	proposalDelays  *histogram            // seconds since start of round
+	voteDelays      map[string]*histogram // vote type -> seconds since start of round
+
+	stepStart  time.Time
+	roundStart time.Time
+}
+
+// NewMetrics returns empty metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		rounds:          newHistogram(roundsBuckets),
+		stepDurations:   make(map[string]*histogram),
+		missedProposals: make(map[string]uint64),
+		proposalDelays:  newHistogram(durationBuckets),
+		voteDelays:      make(map[string]*histogram),
+	}
+}
+
+// Records the end of step, and the start of the next one.
+func (m *Metrics) markStep(step cstypes.RoundStepType) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := time.Now()
+	if !m.stepStart.IsZero() {
+		name := step.String()
+		if m.stepDurations[name] == nil {
+			m.stepDurations[name] = newHistogram(durationBuckets)
+		}
+		m.stepDurations[name].observe(now.Sub(m.stepStart).Seconds())
+	}
+	m.stepStart = now
+}
+
+// Records the start of a round.
+func (m *Metrics) markRound() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.roundStart = time.Now()
+}
+
+// Records the proposal of the current round.
+func (m *Metrics) markProposal() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.proposalDelays.observe(time.Since(m.roundStart).Seconds())
+}
+
+// Records a round without a proposal from proposer.
+func (m *Metrics) markMissedProposal(proposer crypto.Address) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.missedProposals[proposer.String()]++
+}
+
+// Records a vote of the current round.
+func (m *Metrics) markVote(type_ types.SignedMsgType) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	name := voteTypeName(type_)
+	if m.voteDelays[name] == nil {
+		m.voteDelays[name] = newHistogram(durationBuckets)
+	}
+	m.voteDelays[name].observe(time.Since(m.roundStart).Seconds())
+}
+
+// Records the commit of height, at round commitRound.
+func (m *Metrics) markCommit(height int64, commitRound int) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.height = height
+	m.rounds.observe(float64(commitRound + 1))
+}
+
+func voteTypeName(type_ types.SignedMsgType) string {
+	switch type_ {
+	case types.PrevoteType:
+		return "prevote"
+	case types.PrecommitType:
+		return "precommit"
+	default:
+		return fmt.Sprintf("%d", type_)
+	}
+}
+
+// ServeHTTP serves the metrics, e.g. at /metrics.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteText(w)
+}
+
+// WriteText writes the metrics in the text format of Prometheus.
+func (m *Metrics) WriteText(w io.Writer) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	fmt.Fprintf(w, "# HELP consensus_height Last committed height.\n")
+	fmt.Fprintf(w, "# TYPE consensus_height gauge\n")
+	fmt.Fprintf(w, "consensus_height %d\n", m.height)
+	m.rounds.write(w, "consensus_rounds", "Rounds per committed height.", "")
+	writeHistograms(w, "consensus_step_duration_seconds", "Time spent in each step.", "step", m.stepDurations)
+	fmt.Fprintf(w, "# HELP consensus_missed_proposals_total Rounds without a proposal, by proposer.\n")
+	fmt.Fprintf(w, "# TYPE consensus_missed_proposals_total counter\n")
+	proposers := make([]string, 0, len(m.missedProposals))
+	for proposer := range m.missedProposals {
+		proposers = append(proposers, proposer)
+	}
+	sort.Strings(proposers)
+	for _, key := range proposers {
+		fmt.Fprintf(w, "consensus_missed_proposals_total{proposer=%q} %d\n", key, m.missedProposals[key])
+	}
+	m.proposalDelays.write(w, "consensus_proposal_latency_seconds", "Time from the start of a round to its proposal.", "")
+	writeHistograms(w, "consensus_vote_latency_seconds", "Time from the start of a round to its votes, by type.", "type", m.voteDelays)
+}
+
+//----------------------------------------
+// histogram
+
+// histogram is a Prometheus histogram: the cumulative counts of the
+// observations less than or equal to each bucket.
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bucket := range h.buckets {
+		if v <= bucket {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// Writes the histogram name, with help unless empty, and the label pair
+// label, e.g. `step="Propose"`, unless empty.
+func (h *histogram) write(w io.Writer, name, help, label string) {
+	if help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	}
+	bucketLabels, labels := "", ""
+	if label != "" {
+		bucketLabels, labels = label+",", "{"+label+"}"
+	}
+	for i, bucket := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", name, bucketLabels, bucket, h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, bucketLabels, h.count)
+	fmt.Fprintf(w, "%s_sum%s %g\n", name, labels, h.sum)
+	fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+}
+
+// Writes the histograms name, with a histogram for each label value.
+func writeHistograms(w io.Writer, name, help, label string, hists map[string]*histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	keys := make([]string, 0, len(hists))
+	for key := range hists {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hists[key].write(w, name, "", fmt.Sprintf("%s=%q", label, key))
+	}
+}
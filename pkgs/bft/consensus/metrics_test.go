@@ -0,0 +1,45 @@
+package consensus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cstypes "github.com/gnolang/gno/pkgs/bft/consensus/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+
+	// height 1 is committed at round 1, after a round without a proposal.
+	m.markStep(cstypes.RoundStepNewHeight)
+	m.markRound()
+	m.markStep(cstypes.RoundStepNewRound)
+	m.markMissedProposal(crypto.Address{0x01})
+	m.markStep(cstypes.RoundStepPropose)
+	m.markRound()
+	m.markProposal()
+	m.markVote(types.PrevoteType)
+	m.markVote(types.PrevoteType)
+	m.markVote(types.PrecommitType)
+	m.markStep(cstypes.RoundStepPrecommit)
+	m.markCommit(1, 1)
+
+	buf := new(bytes.Buffer)
+	m.WriteText(buf)
+	out := buf.String()
+	assert.Contains(t, out, "consensus_height 1\n")
+	assert.Contains(t, out, "consensus_rounds_bucket{le=\"1\"} 0\n")
+	assert.Contains(t, out, "consensus_rounds_bucket{le=\"2\"} 1\n")
+	assert.Contains(t, out, "consensus_rounds_sum 2\n")
+	assert.Contains(t, out, "consensus_step_duration_seconds_count{step=\"RoundStepNewRound\"} 1\n")
+	assert.Contains(t, out, "consensus_step_duration_seconds_count{step=\"RoundStepPropose\"} 1\n")
+	assert.NotContains(t, out, "{step=\"RoundStepNewHeight\"}")
+	assert.Contains(t, out, "consensus_missed_proposals_total{proposer=\""+crypto.Address{0x01}.String()+"\"} 1\n")
+	assert.Contains(t, out, "consensus_proposal_latency_seconds_count 1\n")
+	assert.Contains(t, out, "consensus_vote_latency_seconds_count{type=\"prevote\"} 2\n")
+	assert.Contains(t, out, "consensus_vote_latency_seconds_count{type=\"precommit\"} 1\n")
+}
@@ -23,6 +23,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		&ProposalMessage{},
 		&ProposalPOLMessage{},
 		&BlockPartMessage{},
+		&HasBlockPartMessage{},
 		&VoteMessage{},
 		&HasVoteMessage{},
 		&VoteSetMaj23Message{},
@@ -16,6 +16,7 @@ import (
 	"github.com/gnolang/gno/pkgs/events"
 	"github.com/gnolang/gno/pkgs/log"
 	"github.com/gnolang/gno/pkgs/p2p"
+	"github.com/gnolang/gno/pkgs/random"
 )
 
 const (
@@ -41,6 +42,9 @@ type ConsensusReactor struct {
 	mtx      sync.RWMutex
 	fastSync bool
 	evsw     events.EventSwitch
+
+	// Number of peers each part of the proposal block was pushed to.
+	partPushes blockPartPushes
 }
 
 type ReactorOption func(*ConsensusReactor)
@@ -240,6 +244,8 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 			ps.ApplyNewValidBlockMessage(msg)
 		case *HasVoteMessage:
 			ps.ApplyHasVoteMessage(msg)
+		case *HasBlockPartMessage:
+			ps.SetHasProposalBlockPart(msg.Height, msg.Round, msg.Index)
 		case *VoteSetMaj23Message:
 			cs := conR.conS
 			cs.mtx.Lock()
@@ -367,8 +373,8 @@ func (conR *ConsensusReactor) FastSync() bool {
 
 //--------------------------------------
 
-// subscribeToBroadcastEvents subscribes for new round steps and votes
-// using internal pubsub defined on state to broadcast
+// subscribeToBroadcastEvents subscribes for new round steps, votes and
+// block parts using internal pubsub defined on state to broadcast
 // them to peers upon receiving.
 func (conR *ConsensusReactor) subscribeToBroadcastEvents() {
 	const subscriber = "consensus-reactor"
@@ -380,6 +386,8 @@ func (conR *ConsensusReactor) subscribeToBroadcastEvents() {
 			conR.broadcastNewValidBlockMessage(event)
 		case types.EventVote:
 			conR.broadcastHasVoteMessage(event.Vote)
+		case cstypes.EventBlockPart:
+			conR.broadcastHasBlockPartMessage(event)
 		}
 	})
 }
@@ -434,6 +442,28 @@ func (conR *ConsensusReactor) broadcastHasVoteMessage(vote *types.Vote) {
 	*/
 }
 
+// Sends HasBlockPartMessage to the peers at the height of the part which
+// don't have the whole block yet, so that they don't push it to us.
+func (conR *ConsensusReactor) broadcastHasBlockPartMessage(event cstypes.EventBlockPart) {
+	msg := &HasBlockPartMessage{
+		Height: event.Height,
+		Round:  event.Round,
+		Index:  event.Index,
+	}
+	bz := amino.MustMarshalAny(msg)
+	for _, peer := range conR.Switch.Peers().List() {
+		ps, ok := peer.Get(types.PeerStateKey).(*PeerState)
+		if !ok {
+			continue // not added yet
+		}
+		prs := ps.GetRoundState()
+		if prs.Height != event.Height || prs.ProposalBlockParts.IsFull() {
+			continue
+		}
+		peer.TrySend(StateChannel, bz)
+	}
+}
+
 func makeRoundStepMessage(event cstypes.EventNewRoundStep) (nrsMsg *NewRoundStepMessage) {
 	nrsMsg = &NewRoundStepMessage{
 		Height:                event.Height,
@@ -465,17 +495,22 @@ OUTER_LOOP:
 		prs := ps.GetRoundState()
 
 		// Send proposal Block parts?
+		// The parts the peer is missing are pushed rarest first, and no
+		// more once the peer has them all, e.g. from other peers.
 		if rs.ProposalBlockParts.HasHeader(prs.ProposalBlockPartsHeader) {
-			if index, ok := rs.ProposalBlockParts.BitArray().Sub(prs.ProposalBlockParts.Copy()).PickRandom(); ok {
+			header := rs.ProposalBlockParts.Header()
+			missing := rs.ProposalBlockParts.BitArray().Sub(prs.ProposalBlockParts.Copy())
+			if index, ok := conR.partPushes.pick(header, missing); ok {
 				part := rs.ProposalBlockParts.GetPart(index)
 				msg := &BlockPartMessage{
 					Height: rs.Height, // This tells peer that this part applies to us.
 					Round:  rs.Round,  // This tells peer that this part applies to us.
 					Part:   part,
 				}
-				logger.Debug("Sending block part", "height", prs.Height, "round", prs.Round)
+				logger.Debug("Sending block part", "height", prs.Height, "round", prs.Round, "index", index)
 				if peer.Send(DataChannel, amino.MustMarshalAny(msg)) {
 					ps.SetHasProposalBlockPart(prs.Height, prs.Round, index)
+					conR.partPushes.record(header, index)
 				}
 				continue OUTER_LOOP
 			}
@@ -545,6 +580,61 @@ OUTER_LOOP:
 	}
 }
 
+//-----------------------------------------------------------------------------
+
+// blockPartPushes counts the peers each part of the last proposal block
+// gossiped was pushed to.
+type blockPartPushes struct {
+	mtx    sync.Mutex
+	header types.PartSetHeader
+	counts []int
+}
+
+// Resets the counts if header is not the one of the block counted.
+func (bpp *blockPartPushes) reset(header types.PartSetHeader) {
+	if !bpp.header.Equals(header) {
+		bpp.header = header
+		bpp.counts = make([]int, header.Total)
+	}
+}
+
+// Picks the part of missing, the parts of the block of header which a peer
+// is missing, pushed to the fewest peers, at random among them.  Spreading
+// the parts over the peers lets them relay the parts to each other while
+// the others are pushed, rather than all receiving the same parts first.
+func (bpp *blockPartPushes) pick(header types.PartSetHeader, missing *bitarray.BitArray) (int, bool) {
+	bpp.mtx.Lock()
+	defer bpp.mtx.Unlock()
+
+	bpp.reset(header)
+	var rarest []int
+	for i := 0; i < missing.Size(); i++ {
+		if !missing.GetIndex(i) {
+			continue
+		}
+		if len(rarest) > 0 && bpp.counts[i] > bpp.counts[rarest[0]] {
+			continue
+		}
+		if len(rarest) > 0 && bpp.counts[i] < bpp.counts[rarest[0]] {
+			rarest = rarest[:0]
+		}
+		rarest = append(rarest, i)
+	}
+	if len(rarest) == 0 {
+		return 0, false
+	}
+	return rarest[random.RandIntn(len(rarest))], true
+}
+
+// Records the push of the part index of the block of header to a peer.
+func (bpp *blockPartPushes) record(header types.PartSetHeader, index int) {
+	bpp.mtx.Lock()
+	defer bpp.mtx.Unlock()
+
+	bpp.reset(header)
+	bpp.counts[index]++
+}
+
 func (conR *ConsensusReactor) gossipDataForCatchup(logger log.Logger, rs *cstypes.RoundState,
 	prs *cstypes.PeerRoundState, ps *PeerState, peer p2p.Peer,
 ) {
@@ -1513,6 +1603,38 @@ func (m *BlockPartMessage) String() string {
 
 //-------------------------------------
 
+// HasBlockPartMessage is sent to indicate that a particular block part has
+// been received.
+type HasBlockPartMessage struct {
+	Height int64
+	Round  int
+	Index  int
+}
+
+// ValidateBasic performs basic validation.
+func (m *HasBlockPartMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("Negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("Negative Round")
+	}
+	if m.Index < 0 {
+		return errors.New("Negative Index")
+	}
+	if m.Index >= types.MaxBlockPartsCount {
+		return errors.New("Index is too big: %d, max: %d", m.Index, types.MaxBlockPartsCount)
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *HasBlockPartMessage) String() string {
+	return fmt.Sprintf("[HasBlockPart I:%v H:%v R:%v]", m.Index, m.Height, m.Round)
+}
+
+//-------------------------------------
+
 // VoteMessage is sent when voting for a proposal (or lack thereof).
 type VoteMessage struct {
 	Vote *types.Vote
@@ -651,6 +651,77 @@ func TestBlockPartMessageValidateBasic(t *testing.T) {
 	assert.Equal(t, true, message.ValidateBasic() != nil, "Validate Basic had an unexpected result")
 }
 
+func TestHasBlockPartMessageValidateBasic(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		messageHeight int64
+		messageRound  int
+		messageIndex  int
+		expectErr     bool
+	}{
+		{"Valid Message", 0, 0, 0, false},
+		{"Invalid Message", -1, 0, 0, true},
+		{"Invalid Message", 0, -1, 0, true},
+		{"Invalid Message", 0, 0, -1, true},
+		{"Invalid Message", 0, 0, types.MaxBlockPartsCount, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			message := HasBlockPartMessage{
+				Height: tc.messageHeight,
+				Round:  tc.messageRound,
+				Index:  tc.messageIndex,
+			}
+
+			assert.Equal(t, tc.expectErr, message.ValidateBasic() != nil, "Validate Basic had an unexpected result")
+		})
+	}
+}
+
+func TestBlockPartPushesPickRarest(t *testing.T) {
+	header := types.PartSetHeader{Total: 4, Hash: tmhash.Sum([]byte("block"))}
+	var bpp blockPartPushes
+
+	// Each part is pushed once before any is pushed twice.
+	all := bitarray.NewBitArray(4)
+	for i := 0; i < 4; i++ {
+		all.SetIndex(i, true)
+	}
+	picked := map[int]bool{}
+	for i := 0; i < 4; i++ {
+		index, ok := bpp.pick(header, all)
+		assert.True(t, ok)
+		assert.False(t, picked[index], "part %d picked twice", index)
+		picked[index] = true
+		bpp.record(header, index)
+	}
+
+	// Only the parts missing to the peer are picked, rarest first.
+	bpp.record(header, 1)
+	missing := bitarray.NewBitArray(4)
+	missing.SetIndex(1, true)
+	missing.SetIndex(2, true)
+	index, ok := bpp.pick(header, missing)
+	assert.True(t, ok)
+	assert.Equal(t, 2, index)
+
+	// None once the peer has all the parts.
+	_, ok = bpp.pick(header, bitarray.NewBitArray(4))
+	assert.False(t, ok)
+
+	// The counts are reset for a new block.
+	other := types.PartSetHeader{Total: 2, Hash: tmhash.Sum([]byte("other"))}
+	bpp.record(other, 0)
+	missing = bitarray.NewBitArray(2)
+	missing.SetIndex(0, true)
+	missing.SetIndex(1, true)
+	index, ok = bpp.pick(other, missing)
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+}
+
 func TestHasVoteMessageValidateBasic(t *testing.T) {
 	const (
 		validSignedMsgType   types.SignedMsgType = 0x01
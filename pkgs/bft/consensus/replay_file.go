@@ -291,6 +291,7 @@ func newConsensusStateForReplay(config cfg.BaseConfig, csConfig *cnscfg.Consensu
 		config.ProxyApp,
 		config.ABCI,
 		config.DBDir(),
+		config.ABCISocketOptions(),
 	)
 	proxyApp := proxy.NewAppConns(clientCreator)
 	err = proxyApp.Start()
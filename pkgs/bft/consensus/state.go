@@ -133,6 +133,9 @@ type ConsensusState struct {
 	doPrevote      func(height int64, round int)
 	setProposal    func(proposal *types.Proposal) error
 
+	// metrics of the rounds, steps, proposals and votes
+	metrics *Metrics
+
 	// closed when we finish shutting down
 	done chan struct{}
 }
@@ -162,6 +165,7 @@ func NewConsensusState(
 		doWALCatchup:     true,
 		evsw:             tmevents.NewEventSwitch(),
 		wal:              walm.NopWAL{},
+		metrics:          NewMetrics(),
 	}
 	// set function defaults (may be overwritten before calling Start)
 	cs.decideProposal = cs.defaultDecideProposal
@@ -252,6 +256,11 @@ func (cs *ConsensusState) GetHRS() cstypes.HRS {
 	return cs.RoundState.GetHRS()
 }
 
+// Metrics returns the metrics of the consensus.
+func (cs *ConsensusState) Metrics() *Metrics {
+	return cs.metrics
+}
+
 // GetValidators returns a copy of the current validators.
 func (cs *ConsensusState) GetValidators() (int64, []*types.Validator) {
 	cs.mtx.RLock()
@@ -263,6 +272,7 @@ func (cs *ConsensusState) GetValidators() (int64, []*types.Validator) {
 func (cs *ConsensusState) SetPrivValidator(priv types.PrivValidator) {
 	cs.mtx.Lock()
 	cs.privValidator = priv
+	cs.updatePrivValidatorToHeight()
 	cs.mtx.Unlock()
 }
 
@@ -451,6 +461,9 @@ func (cs *ConsensusState) updateHeight(height int64) {
 }
 
 func (cs *ConsensusState) updateRoundStep(round int, step cstypes.RoundStepType) {
+	if cs.Round != round || cs.Step != step {
+		cs.metrics.markStep(cs.Step)
+	}
 	cs.Round = round
 	cs.Step = step
 }
@@ -564,11 +577,21 @@ func (cs *ConsensusState) updateToState(state sm.State) {
 	cs.TriggeredTimeoutPrecommit = false
 
 	cs.state = state
+	cs.updatePrivValidatorToHeight()
 
 	// Finally, broadcast RoundState
 	cs.newStep()
 }
 
+// Lets the privValidator rotate its key for the validators of the height.
+func (cs *ConsensusState) updatePrivValidatorToHeight() {
+	rpv, ok := cs.privValidator.(types.RotatingPrivValidator)
+	if !ok || cs.Validators == nil {
+		return
+	}
+	rpv.UpdateToHeight(cs.Height, cs.Validators)
+}
+
 func (cs *ConsensusState) newStep() {
 	nrsInfo := newRoundStepInfo{HRS: cs.RoundState.GetHRS()}
 	cs.wal.Write(nrsInfo)
@@ -814,6 +837,7 @@ func (cs *ConsensusState) enterNewRound(height int64, round int) {
 	// we don't fire newStep for this step,
 	// but we fire an event, so update the round step first
 	cs.updateRoundStep(round, cstypes.RoundStepNewRound)
+	cs.metrics.markRound()
 	cs.Validators = validators
 	if round == 0 {
 		// We've already reset these upon new height,
@@ -1006,6 +1030,10 @@ func (cs *ConsensusState) enterPrevote(height int64, round int) {
 
 	cs.Logger.Info(fmt.Sprintf("enterPrevote(%v/%v). Current: %v/%v/%v", height, round, cs.Height, cs.Round, cs.Step))
 
+	if cs.Proposal == nil {
+		cs.metrics.markMissedProposal(cs.Validators.GetProposer().Address)
+	}
+
 	// Sign and broadcast vote as necessary
 	cs.doPrevote(height, round)
 
@@ -1353,6 +1381,17 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 		return
 	}
 
+	// Fire the signed header of the block, e.g. for the light clients
+	// subscribed to the headers from the RPC.
+	if cs.evsw != nil {
+		cs.evsw.FireEvent(types.EventNewSignedHeader{
+			Header: block.Header,
+			Commit: cs.blockStore.LoadSeenCommit(height),
+		})
+	}
+
+	cs.metrics.markCommit(height, cs.CommitRound)
+
 	fail.Fail() // XXX
 
 	// NewHeightStep!
@@ -1396,6 +1435,7 @@ func (cs *ConsensusState) defaultSetProposal(proposal *types.Proposal) error {
 	}
 
 	cs.Proposal = proposal
+	cs.metrics.markProposal()
 	// We don't update cs.ProposalBlockParts if it is already set.
 	// This happens if we're already in cstypes.RoundStepCommit or if there is a valid block in the current round.
 	// TODO: We can check if Proposal is for a different block as this is a sign of misbehavior!
@@ -1430,6 +1470,10 @@ func (cs *ConsensusState) addProposalBlockPart(msg *BlockPartMessage, peerID p2p
 	if err != nil {
 		return added, err
 	}
+	if added {
+		// Lets the peers know they don't need to send us the part.
+		cs.evsw.FireEvent(cs.EventBlockPart(part.Index))
+	}
 	if added && cs.ProposalBlockParts.IsComplete() {
 		// Added and completed!
 		_, err = amino.UnmarshalSizedReader(
@@ -1556,6 +1600,9 @@ func (cs *ConsensusState) addVote(vote *types.Vote, peerID p2p.ID) (added bool,
 	}
 
 	cs.evsw.FireEvent(types.EventVote{vote})
+	if vote.Round == cs.Round {
+		cs.metrics.markVote(vote.Type)
+	}
 
 	switch vote.Type {
 	case types.PrevoteType:
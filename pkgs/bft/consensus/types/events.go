@@ -16,6 +16,7 @@ func (_ EventNewRoundStep) AssertEvent()     {}
 func (_ EventNewValidBlock) AssertEvent()    {}
 func (_ EventNewRound) AssertEvent()         {}
 func (_ EventCompleteProposal) AssertEvent() {}
+func (_ EventBlockPart) AssertEvent()        {}
 func (_ EventTimeoutPropose) AssertEvent()   {}
 func (_ EventTimeoutWait) AssertEvent()      {}
 func (_ EventPolka) AssertEvent()            {}
@@ -28,6 +29,7 @@ var (
 	_ ConsensusEvent = EventNewValidBlock{}
 	_ ConsensusEvent = EventNewRound{}
 	_ ConsensusEvent = EventCompleteProposal{}
+	_ ConsensusEvent = EventBlockPart{}
 	_ ConsensusEvent = EventTimeoutPropose{}
 	_ ConsensusEvent = EventTimeoutWait{}
 	_ ConsensusEvent = EventPolka{}
@@ -80,6 +82,16 @@ func (ev EventCompleteProposal) String() string {
 	return fmt.Sprintf("EventCompleteProposal{%v}", ev.HRS)
 }
 
+type EventBlockPart struct {
+	HRS `json:"hrs"`
+
+	Index int `json:"index"`
+}
+
+func (ev EventBlockPart) String() string {
+	return fmt.Sprintf("EventBlockPart{%v %d}", ev.HRS, ev.Index)
+}
+
 type EventTimeoutPropose struct {
 	HRS `json:"hrs"`
 }
@@ -32,6 +32,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		EventNewValidBlock{},
 		EventNewRound{},
 		EventCompleteProposal{},
+		EventBlockPart{},
 		EventTimeoutPropose{},
 		EventTimeoutWait{},
 	))
@@ -188,6 +188,13 @@ func (rs *RoundState) EventCompleteProposal() EventCompleteProposal {
 	}
 }
 
+func (rs *RoundState) EventBlockPart(index int) EventBlockPart {
+	return EventBlockPart{
+		HRS:   rs.GetHRS(),
+		Index: index,
+	}
+}
+
 func (rs *RoundState) EventNewValidBlock() EventNewValidBlock {
 	return EventNewValidBlock{
 		HRS:              rs.GetHRS(),
@@ -1,13 +1,11 @@
 package mempool
 
 import (
-	"bytes"
 	"container/list"
 	"crypto/sha256"
 	"fmt"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	auto "github.com/gnolang/gno/pkgs/autofile"
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
@@ -16,6 +14,7 @@ import (
 	"github.com/gnolang/gno/pkgs/bft/types"
 	"github.com/gnolang/gno/pkgs/clist"
 	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/events"
 	"github.com/gnolang/gno/pkgs/log"
 	"github.com/gnolang/gno/pkgs/maths"
 	osm "github.com/gnolang/gno/pkgs/os"
@@ -37,12 +36,11 @@ type CListMempool struct {
 	preCheck     PreCheckFunc
 	height       int64 // the last block Update()'d to
 	maxTxBytes   int64
+	maxTxGas     int64 // -1 for no limit
 
-	// Track whether we're rechecking txs.
-	// These are not protected by a mutex and are expected to be mutated
-	// in serial (ie. by abci responses which are called in serial).
-	recheckCursor *clist.CElement // next expected response
-	recheckEnd    *clist.CElement // re-checking stops here
+	// Generation of the recheck of the txs after each Update, which
+	// cancels the batches left of the previous one.
+	recheckGen uint64
 
 	// notify listeners (ie. consensus) when txs are available
 	notifiedTxsAvailable bool
@@ -52,9 +50,17 @@ type CListMempool struct {
 	// txsMap: txKey -> CElement
 	txsMap sync.Map
 
+	// Map of the canonical hashes of the txs, if defined by the app, to
+	// reject other encodings of txs already in the mempool.
+	// txHashesMap: string(TxHash) -> CElement
+	txHashesMap sync.Map
+
 	// Atomic integers
 	txsBytes   int64 // total size of mempool, in bytes
-	rechecking int32 // for re-checking filtered txs on Update()
+	rechecking int32 // 1 until the txs are rechecked after Update()
+
+	// Signaled, on mtx, when rechecking is reset to 0.
+	rechecked *sync.Cond
 
 	// Keep a cache of already-seen txs.
 	// This reduces the pressure on the proxyApp.
@@ -63,6 +69,11 @@ type CListMempool struct {
 	// A log of mempool txs
 	wal *auto.AutoFile
 
+	// Fires the txs rejected and evicted
+	evsw events.EventSwitch
+
+	metrics *Metrics
+
 	logger log.Logger
 }
 
@@ -72,33 +83,36 @@ var _ Mempool = &CListMempool{}
 type CListMempoolOption func(*CListMempool)
 
 // NewCListMempool returns a new mempool with the given configuration and connection to an application.
+// Its txs may want at most maxTxGas, or -1 for no limit.
 func NewCListMempool(
 	config *cfg.MempoolConfig,
 	proxyAppConn proxy.AppConnMempool,
 	height int64,
 	maxTxBytes int64,
+	maxTxGas int64,
 	options ...CListMempoolOption,
 ) *CListMempool {
 	if maxTxBytes <= 0 {
 		panic("maxTxBytes must be positive")
 	}
 	mempool := &CListMempool{
-		config:        config,
-		proxyAppConn:  proxyAppConn,
-		txs:           clist.New(),
-		height:        height,
-		maxTxBytes:    maxTxBytes,
-		rechecking:    0,
-		recheckCursor: nil,
-		recheckEnd:    nil,
-		logger:        log.NewNopLogger(),
-	}
+		config:       config,
+		proxyAppConn: proxyAppConn,
+		txs:          clist.New(),
+		height:       height,
+		maxTxBytes:   maxTxBytes,
+		maxTxGas:     maxTxGas,
+		rechecking:   0,
+		evsw:         events.NilEventSwitch(),
+		logger:       log.NewNopLogger(),
+	}
+	mempool.rechecked = sync.NewCond(&mempool.mtx)
+	mempool.metrics = newMetrics(mempool)
 	if config.CacheSize > 0 {
 		mempool.cache = newMapTxCache(config.CacheSize)
 	} else {
 		mempool.cache = nopTxCache{}
 	}
-	proxyAppConn.SetResponseCallback(mempool.globalCb)
 	for _, option := range options {
 		option(mempool)
 	}
@@ -115,6 +129,17 @@ func (mem *CListMempool) SetLogger(l log.Logger) {
 	mem.logger = l
 }
 
+// SetEventSwitch sets the event switch on which the txs rejected and evicted
+// are fired.
+func (mem *CListMempool) SetEventSwitch(evsw events.EventSwitch) {
+	mem.evsw = evsw
+}
+
+// Metrics returns the metrics of the mempool.
+func (mem *CListMempool) Metrics() *Metrics {
+	return mem.metrics
+}
+
 // WithPreCheck sets a filter for the mempool to reject a tx if f(tx) returns
 // false. This is ran before CheckTx.
 func WithPreCheck(f PreCheckFunc) CListMempoolOption {
@@ -184,6 +209,7 @@ func (mem *CListMempool) Flush() {
 	}
 
 	mem.txsMap = sync.Map{}
+	mem.txHashesMap = sync.Map{}
 	_ = atomic.SwapInt64(&mem.txsBytes, 0)
 }
 
@@ -201,7 +227,9 @@ func (mem *CListMempool) TxsWaitChan() <-chan struct{} {
 	return mem.txs.WaitChan()
 }
 
-// It blocks if we're waiting on Update() or Reap().
+// It blocks if we're waiting on Update() or Reap(), or on the recheck of the
+// txs after Update(): the state of the app is only that of the mempool once
+// its txs are rechecked, e.g. a tx may follow one being rechecked.
 // cb: A callback from the CheckTx command.
 //     It gets called from another goroutine.
 // CONTRACT: Either cb will get called, or err returned.
@@ -210,10 +238,17 @@ func (mem *CListMempool) CheckTx(tx types.Tx, cb func(abci.Response)) (err error
 }
 
 func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), txInfo TxInfo) (err error) {
-	mem.mtx.Lock()
+	mem.lockRechecked()
 	// use defer to unlock mutex because application (*local client*) might panic
 	defer mem.mtx.Unlock()
 
+	reason := ""
+	defer func() {
+		if reason != "" {
+			mem.reject(tx, reason, err.Error())
+		}
+	}()
+
 	var (
 		memSize  = mem.Size()
 		txsBytes = mem.TxsBytes()
@@ -223,6 +258,7 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 	// Check max pending txs bytes
 	if memSize >= mem.config.Size ||
 		int64(txSize)+txsBytes > mem.config.MaxPendingTxsBytes {
+		reason = ReasonMempoolFull
 		return ErrMempoolIsFull{
 			memSize, mem.config.Size,
 			txsBytes, mem.config.MaxPendingTxsBytes,
@@ -231,12 +267,14 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 
 	// Check max tx bytes
 	if int64(txSize) > mem.maxTxBytes {
+		reason = ReasonTxTooLarge
 		return ErrTxTooLarge{mem.maxTxBytes, int64(txSize)}
 	}
 
 	// Check custom preCheck function
 	if mem.preCheck != nil {
 		if err := mem.preCheck(tx); err != nil {
+			reason = ReasonPreCheck
 			return err
 		}
 	}
@@ -256,6 +294,7 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 
 		}
 
+		reason = ReasonTxInCache
 		return ErrTxInCache
 	}
 	// END CACHE
@@ -285,21 +324,6 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 	return nil
 }
 
-// Global callback that will be called after every ABCI response.
-// Having a single global callback avoids needing to set a callback for each request.
-// However, processing the checkTx response requires the peerID (so we can track which txs we heard from who),
-// and peerID is not included in the ABCI request, so we have to set request-specific callbacks that
-// include this information. If we're not in the midst of a recheck, this function will just return,
-// so the request specific callback can do the work.
-// When rechecking, we don't need the peerID, so the recheck callback happens here.
-func (mem *CListMempool) globalCb(req abci.Request, res abci.Response) {
-	if mem.recheckCursor == nil {
-		return
-	} else {
-		mem.resCbRecheck(req, res)
-	}
-}
-
 // Request specific callback that should be set on individual reqRes objects
 // to incorporate local information when processing the response.
 // This allows us to track the peer that sent us this tx, so we can avoid sending it back to them.
@@ -311,12 +335,7 @@ func (mem *CListMempool) globalCb(req abci.Request, res abci.Response) {
 // Used in CheckTxWithInfo to record PeerID who sent us the tx.
 func (mem *CListMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci.Response)) func(res abci.Response) {
 	return func(res abci.Response) {
-		if mem.recheckCursor != nil {
-			// this should never happen
-			panic("recheck cursor is not nil in reqResCb")
-		}
-
-		mem.resCbFirstTime(tx, peerID, res)
+		res = mem.resCbFirstTime(tx, peerID, res)
 
 		// Passed in by the caller of CheckTx, eg. the RPC.
 		// The external callback cannot modify the result.
@@ -331,16 +350,22 @@ func (mem *CListMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci
 func (mem *CListMempool) addTx(memTx *mempoolTx) {
 	e := mem.txs.PushBack(memTx)
 	mem.txsMap.Store(txKey(memTx.tx), e)
+	if memTx.txHash != nil {
+		mem.txHashesMap.Store(string(memTx.txHash), e)
+	}
 	atomic.AddInt64(&mem.txsBytes, int64(len(memTx.tx)))
 }
 
 // Called from:
 //  - Update (lock held) if tx was committed
-// 	- resCbRecheck (lock not held) if tx was invalidated
+// 	- resCbRecheck (lock held) if tx was invalidated
 func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
 	mem.txs.Remove(elem)
 	elem.DetachPrev()
 	mem.txsMap.Delete(txKey(tx))
+	if txHash := elem.Value.(*mempoolTx).txHash; txHash != nil {
+		mem.txHashesMap.Delete(string(txHash))
+	}
 	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
 
 	if removeFromCache {
@@ -352,14 +377,26 @@ func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromC
 //
 // The case where the app checks the tx for the second and subsequent times is
 // handled by the resCbRecheck callback.
-func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) {
+//
+// Returns the response, with an error if the tx has the canonical hash of a
+// tx already in the mempool.
+func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) abci.Response {
 	switch res := res.(type) {
 	case abci.ResponseCheckTx:
+		reason := ReasonCheckTx
+		if res.Error == nil && res.TxHash != nil {
+			if _, ok := mem.txHashesMap.Load(string(res.TxHash)); ok {
+				res.Error = abci.StringError(fmt.Sprintf("tx with hash %X already exists in mempool", res.TxHash))
+				reason = ReasonDuplicateHash
+			}
+		}
 		if res.Error == nil {
 			memTx := &mempoolTx{
 				height:    mem.height,
 				gasWanted: res.GasWanted,
 				tx:        tx,
+				txHash:    res.TxHash,
+				system:    res.System,
 			}
 			memTx.senders.Store(peerID, true)
 			mem.addTx(memTx)
@@ -375,55 +412,52 @@ func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Respo
 			mem.logger.Info("Rejected bad transaction", "tx", txID(tx), "res", res, "err", res.Error)
 			// remove from cache (it might be good later)
 			mem.cache.Remove(tx)
+			mem.reject(tx, reason, res.Error.Error())
 		}
+		return res
 	default:
 		// ignore other messages
+		return res
 	}
 }
 
-// callback, which is called after the app rechecked the tx.
+// callback, which is called after the app rechecked the tx of elem, with
+// the lock held.
 //
 // The case where the app checks the tx for the first time is handled by the
 // resCbFirstTime callback.
-func (mem *CListMempool) resCbRecheck(req abci.Request, res abci.Response) {
-	switch res := res.(type) {
-	case abci.ResponseCheckTx:
-		tx := req.(abci.RequestCheckTx).Tx
-		memTx := mem.recheckCursor.Value.(*mempoolTx)
-		if !bytes.Equal(tx, memTx.tx) {
-			panic(fmt.Sprintf(
-				"Unexpected tx response from proxy during recheck\nExpected %X, got %X",
-				memTx.tx,
-				tx))
-		}
-		if res.Error == nil {
-			// Good, nothing to do.
-		} else {
+func (mem *CListMempool) resCbRecheck(elem *clist.CElement) func(res abci.Response) {
+	return func(res abci.Response) {
+		switch res := res.(type) {
+		case abci.ResponseCheckTx:
+			if res.Error == nil || elem.Removed() {
+				// Good, nothing to do.
+				return
+			}
 			// Tx became invalidated due to newly committed block.
+			tx := elem.Value.(*mempoolTx).tx
 			mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", res, "err", res.Error)
 			// NOTE: we remove tx from the cache because it might be good later
-			mem.removeTx(tx, mem.recheckCursor, true)
-		}
-		if mem.recheckCursor == mem.recheckEnd {
-			mem.recheckCursor = nil
-		} else {
-			mem.recheckCursor = mem.recheckCursor.Next()
-		}
-		if mem.recheckCursor == nil {
-			// Done!
-			atomic.StoreInt32(&mem.rechecking, 0)
-			mem.logger.Info("Done rechecking txs")
-
-			// incase the recheck removed all txs
-			if mem.Size() > 0 {
-				mem.notifyTxsAvailable()
-			}
+			mem.removeTx(tx, elem, true)
+			mem.evict(tx, ReasonRecheckTx, res.Error.Error())
+		default:
+			// ignore other messages
 		}
-	default:
-		// ignore other messages
 	}
 }
 
+// Counts the rejection of tx for reason, and fires it.
+func (mem *CListMempool) reject(tx types.Tx, reason string, err string) {
+	mem.metrics.markRejection(reason)
+	mem.evsw.FireEvent(types.EventTxRejected{Tx: tx, Reason: reason, Error: err})
+}
+
+// Counts the eviction of tx for reason, and fires it.
+func (mem *CListMempool) evict(tx types.Tx, reason string, err string) {
+	mem.metrics.markEviction(reason)
+	mem.evsw.FireEvent(types.EventTxEvicted{Tx: tx, Height: mem.height, Reason: reason, Error: err})
+}
+
 func (mem *CListMempool) TxsAvailable() <-chan struct{} {
 	return mem.txsAvailable
 }
@@ -442,27 +476,66 @@ func (mem *CListMempool) notifyTxsAvailable() {
 	}
 }
 
-func (mem *CListMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Txs {
+// Locks the mempool once its txs are rechecked after the last Update, so
+// that the txs invalidated by the last block are not reaped, and the new
+// txs are checked after those of the mempool.
+func (mem *CListMempool) lockRechecked() {
 	mem.mtx.Lock()
+	for atomic.LoadInt32(&mem.rechecking) != 0 {
+		// The batches of the recheck need the lock, released while
+		// waiting.
+		mem.rechecked.Wait()
+	}
+}
+
+func (mem *CListMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Txs {
+	mem.lockRechecked()
 	defer mem.mtx.Unlock()
 
 	if maxDataBytes == 0 {
 		panic("ReapMaxBytesMaxGas requires maxDataBytes > 0")
 	}
 
-	for atomic.LoadInt32(&mem.rechecking) > 0 {
-		// TODO: Something better?
-		time.Sleep(time.Millisecond * 10)
-	}
-
 	var totalBytes int64
 	var totalGas int64
 	// TODO: we will get a performance boost if we have a good estimate of avg
 	// size per tx, and set the initial capacity based off of that.
 	// txs := make([]types.Tx, 0, maths.MinInt(mem.txs.Len(), max/mem.avgTxSize))
 	txs := make([]types.Tx, 0, mem.txs.Len())
+
+	// First the txs of the system lane, up to the gas reserved to them.
+	var system map[*mempoolTx]struct{}
+	if laneGas := mem.config.SystemLaneGas; laneGas > 0 {
+		system = make(map[*mempoolTx]struct{})
+		var laneTotalGas int64
+		for e := mem.txs.Front(); e != nil; e = e.Next() {
+			memTx := e.Value.(*mempoolTx)
+			if !memTx.system || mem.tooLarge(memTx, maxDataBytes, maxGas) {
+				continue
+			}
+			if laneTotalGas+memTx.gasWanted > laneGas ||
+				(maxDataBytes > -1 && totalBytes+int64(len(memTx.tx)) > maxDataBytes) ||
+				(maxGas > -1 && totalGas+memTx.gasWanted > maxGas) {
+				break
+			}
+			laneTotalGas += memTx.gasWanted
+			totalBytes += int64(len(memTx.tx))
+			totalGas += memTx.gasWanted
+			txs = append(txs, memTx.tx)
+			system[memTx] = struct{}{}
+		}
+	}
+
 	for e := mem.txs.Front(); e != nil; e = e.Next() {
 		memTx := e.Value.(*mempoolTx)
+		if _, ok := system[memTx]; ok {
+			continue
+		}
+		// Skip the txs which can't be in any block, e.g. since the
+		// consensus params changed, rather than stopping at them.
+		if mem.tooLarge(memTx, maxDataBytes, maxGas) {
+			continue
+		}
 		// Check total size requirement
 		if maxDataBytes > -1 && totalBytes+int64(len(memTx.tx)) > maxDataBytes {
 			return txs
@@ -482,19 +555,23 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Tx
 	return txs
 }
 
+// Returns true if memTx is above the max tx bytes or gas, or alone exceeds
+// the max data bytes or gas of a block.
+func (mem *CListMempool) tooLarge(memTx *mempoolTx, maxDataBytes, maxGas int64) bool {
+	return int64(len(memTx.tx)) > mem.maxTxBytes ||
+		(maxDataBytes > -1 && int64(len(memTx.tx)) > maxDataBytes) ||
+		(mem.maxTxGas > -1 && memTx.gasWanted > mem.maxTxGas) ||
+		(maxGas > -1 && memTx.gasWanted > maxGas)
+}
+
 func (mem *CListMempool) ReapMaxTxs(max int) types.Txs {
-	mem.mtx.Lock()
+	mem.lockRechecked()
 	defer mem.mtx.Unlock()
 
 	if max < 0 {
 		max = mem.txs.Len()
 	}
 
-	for atomic.LoadInt32(&mem.rechecking) > 0 {
-		// TODO: Something better?
-		time.Sleep(time.Millisecond * 10)
-	}
-
 	txs := make([]types.Tx, 0, maths.MinInt(mem.txs.Len(), max))
 	for e := mem.txs.Front(); e != nil && len(txs) <= max; e = e.Next() {
 		memTx := e.Value.(*mempoolTx)
@@ -509,6 +586,7 @@ func (mem *CListMempool) Update(
 	deliverTxResponses []abci.ResponseDeliverTx,
 	preCheck PreCheckFunc,
 	maxTxBytes int64,
+	maxTxGas int64,
 ) error {
 	// Set height
 	mem.height = height
@@ -520,6 +598,9 @@ func (mem *CListMempool) Update(
 	if maxTxBytes != 0 {
 		mem.maxTxBytes = maxTxBytes
 	}
+	if maxTxGas != 0 {
+		mem.maxTxGas = maxTxGas
+	}
 
 	for i, tx := range txs {
 		if deliverTxResponses[i].Error == nil {
@@ -545,15 +626,35 @@ func (mem *CListMempool) Update(
 		}
 	}
 
+	// Cancel the batches left of the previous recheck, whose txs are
+	// rechecked against the new state.
+	mem.recheckGen++
+	atomic.StoreInt32(&mem.rechecking, 0)
+	mem.rechecked.Broadcast()
+
 	// Either recheck non-committed txs to see if they became invalid
 	// or just notify there're some txs left.
 	if mem.Size() > 0 {
 		if mem.config.Recheck {
 			mem.logger.Info("Recheck txs", "numtxs", mem.Size(), "height", height)
-			mem.recheckTxs()
-			// At this point, mem.txs are being rechecked.
-			// mem.recheckCursor re-scans mem.txs and possibly removes some txs.
-			// Before mem.Reap(), we should wait for mem.recheckCursor to be nil.
+			atomic.StoreInt32(&mem.rechecking, 1)
+			mem.metrics.markRecheckStart()
+			// Snapshot of the txs to recheck: no txs are added until
+			// they are rechecked.
+			elems := make([]*clist.CElement, 0, mem.Size())
+			for e := mem.txs.Front(); e != nil; e = e.Next() {
+				elems = append(elems, e)
+			}
+			if mem.config.RecheckBatchSize == 0 {
+				mem.recheckTxs(elems)
+				mem.recheckDone()
+			} else {
+				// At this point, mem.txs are being rechecked in batches,
+				// between which the mempool is unlocked.
+				// Before mem.Reap() and mem.CheckTx(), we should wait for
+				// mem.rechecking to be 0.
+				go mem.recheckRoutine(mem.recheckGen, elems)
+			}
 		} else {
 			mem.notifyTxsAvailable()
 		}
@@ -562,39 +663,82 @@ func (mem *CListMempool) Update(
 	return nil
 }
 
-func (mem *CListMempool) recheckTxs() {
-	if mem.Size() == 0 {
-		panic("recheckTxs is called, but the mempool is empty")
+// Rechecks the txs of elems by batches of the recheck batch size, locking
+// the mempool for each batch, until the recheck gen is cancelled by a new
+// Update.
+func (mem *CListMempool) recheckRoutine(gen uint64, elems []*clist.CElement) {
+	batchSize := mem.config.RecheckBatchSize
+	for start := 0; start < len(elems); start += batchSize {
+		end := maths.MinInt(start+batchSize, len(elems))
+		if !mem.recheckBatch(gen, elems[start:end], end == len(elems)) {
+			return
+		}
 	}
+}
 
-	atomic.StoreInt32(&mem.rechecking, 1)
-	mem.recheckCursor = mem.txs.Front()
-	mem.recheckEnd = mem.txs.Back()
+// Rechecks a batch of the txs of the recheck gen, and returns false if it
+// was cancelled.
+func (mem *CListMempool) recheckBatch(gen uint64, elems []*clist.CElement, last bool) bool {
+	mem.mtx.Lock()
+	// use defer to unlock mutex because application (*local client*) might panic
+	defer mem.mtx.Unlock()
 
-	// Push txs to proxyAppConn
-	// NOTE: globalCb may be called concurrently.
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
+	if gen != mem.recheckGen {
+		return false
+	}
+	mem.recheckTxs(elems)
+	if last {
+		mem.recheckDone()
+	}
+	return true
+}
+
+// Rechecks the txs of elems, with the lock held.  The txs removed since
+// the snapshot are skipped.
+func (mem *CListMempool) recheckTxs(elems []*clist.CElement) {
+	for _, e := range elems {
+		if e.Removed() {
+			continue
+		}
 		memTx := e.Value.(*mempoolTx)
 		// check tx size
 		if int64(len(memTx.tx)) > mem.maxTxBytes {
 			mem.removeTx(memTx.tx, e, false)
+			mem.evict(memTx.tx, ReasonTxTooLarge, ErrTxTooLarge{mem.maxTxBytes, int64(len(memTx.tx))}.Error())
 			continue
 		}
 		// run precheck
 		if mem.preCheck != nil {
 			if err := mem.preCheck(memTx.tx); err != nil {
 				mem.removeTx(memTx.tx, e, false)
+				mem.evict(memTx.tx, ReasonPreCheck, err.Error())
 				continue
 			}
 		}
 		// run proxy app checktx
-		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
+		reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
 			Tx:   memTx.tx,
 			Type: abci.CheckTxTypeRecheck,
 		})
+		reqRes.SetCallback(mem.resCbRecheck(e))
 	}
+	// The responses of the batch are handled once flushed.
+	if err := mem.proxyAppConn.FlushSync(); err != nil {
+		mem.logger.Error("Error flushing rechecks", "err", err)
+	}
+}
+
+// Ends the recheck, with the lock held.
+func (mem *CListMempool) recheckDone() {
+	atomic.StoreInt32(&mem.rechecking, 0)
+	mem.rechecked.Broadcast()
+	mem.metrics.markRecheckEnd()
+	mem.logger.Info("Done rechecking txs")
 
-	mem.proxyAppConn.FlushAsync()
+	// incase the recheck removed all txs
+	if mem.Size() > 0 {
+		mem.notifyTxsAvailable()
+	}
 }
 
 //--------------------------------------------------------------------------------
@@ -604,6 +748,8 @@ type mempoolTx struct {
 	height    int64    // height that this tx had been validated in
 	gasWanted int64    // amount of gas this tx states it will require
 	tx        types.Tx //
+	txHash    []byte   // canonical hash of the tx, if defined by the app
+	system    bool     // in the system lane
 
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
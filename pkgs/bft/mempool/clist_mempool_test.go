@@ -1,6 +1,7 @@
 package mempool
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
@@ -14,14 +15,26 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/gnolang/gno/pkgs/amino"
 	"github.com/gnolang/gno/pkgs/bft/abci/example/counter"
 	"github.com/gnolang/gno/pkgs/bft/abci/example/kvstore"
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
 	cfg "github.com/gnolang/gno/pkgs/bft/mempool/config"
 	"github.com/gnolang/gno/pkgs/bft/proxy"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/events"
 	"github.com/gnolang/gno/pkgs/log"
 	"github.com/gnolang/gno/pkgs/random"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/auth"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	tu "github.com/gnolang/gno/pkgs/sdk/testutils"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/dbadapter"
+	"github.com/gnolang/gno/pkgs/store/iavl"
 )
 
 // A cleanupFunc cleans up any config / test files created for a particular
@@ -41,7 +54,7 @@ func newMempoolWithAppAndConfig(cc proxy.ClientCreator, config *cfg.MempoolConfi
 	if err != nil {
 		panic(err)
 	}
-	mempool := NewCListMempool(config, appConnMem, 0, testMaxTxBytes)
+	mempool := NewCListMempool(config, appConnMem, 0, testMaxTxBytes, -1)
 	mempool.SetLogger(log.TestingLogger())
 	return mempool, func() {
 		if config.RootDir != "" {
@@ -138,6 +151,66 @@ func TestReapMaxBytesMaxGas(t *testing.T) {
 	}
 }
 
+func TestReapSkipsOversizedTxs(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	// 10 txs of 20 bytes and 1 gas, the 3rd of 30 bytes, the 6th of 5 gas.
+	txs := checkTxs(t, mempool, 2, UnknownPeerID, true)
+	bigTx := make(types.Tx, 30)
+	copy(bigTx, "big")
+	require.NoError(t, mempool.CheckTx(bigTx, nil))
+	txs = append(txs, bigTx)
+	txs = append(txs, checkTxs(t, mempool, 7, UnknownPeerID, true)...)
+	e := mempool.TxsFront()
+	for i := 0; i < 5; i++ {
+		e = e.Next()
+	}
+	e.Value.(*mempoolTx).gasWanted = 5
+	require.Equal(t, 10, mempool.Size())
+
+	// lowered by the consensus params, without recheck.
+	mempool.maxTxBytes = 25
+	mempool.maxTxGas = 4
+	got := mempool.ReapMaxBytesMaxGas(-1, -1)
+	assert.Equal(t, types.Txs(append(append(types.Txs{}, txs[:2]...), txs[3:5]...)), got[:4])
+	assert.Equal(t, types.Txs(txs[6:]), got[4:])
+
+	// txs which alone exceed the maxes of the block don't stop the reap.
+	mempool.maxTxBytes, mempool.maxTxGas = testMaxTxBytes, -1
+	assert.Len(t, mempool.ReapMaxBytesMaxGas(25, -1), 1)
+	assert.Len(t, mempool.ReapMaxBytesMaxGas(-1, 4), 4)
+	assert.Len(t, mempool.ReapMaxBytesMaxGas(-1, 10), 6)
+}
+
+func TestReapSystemLane(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	config := cfg.TestMempoolConfig()
+	config.SystemLaneGas = 2
+	mempool, cleanup := newMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	// 6 txs of 20 bytes and 1 gas, the 3rd, 5th and 6th in the system lane.
+	txs := checkTxs(t, mempool, 6, UnknownPeerID, true)
+	i := 0
+	for e := mempool.TxsFront(); e != nil; e = e.Next() {
+		e.Value.(*mempoolTx).system = i == 2 || i == 4 || i == 5
+		i++
+	}
+
+	// the system txs first, up to the gas of the lane.
+	assert.Equal(t, types.Txs{txs[2], txs[4], txs[0], txs[1], txs[3], txs[5]}, mempool.ReapMaxBytesMaxGas(-1, -1))
+	assert.Equal(t, types.Txs{txs[2], txs[4], txs[0]}, mempool.ReapMaxBytesMaxGas(-1, 3))
+	assert.Equal(t, types.Txs{txs[2]}, mempool.ReapMaxBytesMaxGas(20, -1))
+
+	// without the lane.
+	config.SystemLaneGas = 0
+	assert.Equal(t, txs, mempool.ReapMaxBytesMaxGas(-1, -1))
+}
+
 /* XXX test PreCheck filter.
    XXX this used to be a PostCheck filter test, so the code doesn't make much sense.
    TODO change numTxsToCreate to a slice of tx sizes.
@@ -184,7 +257,7 @@ func TestMempoolUpdate(t *testing.T) {
 
 	// 1. Adds valid txs to the cache
 	{
-		mempool.Update(1, []types.Tx{[]byte{0x01}}, abciResponses(1, nil), nil, 0)
+		mempool.Update(1, []types.Tx{[]byte{0x01}}, abciResponses(1, nil), nil, 0, 0)
 		err := mempool.CheckTx([]byte{0x01}, nil)
 		if assert.Error(t, err) {
 			assert.Equal(t, ErrTxInCache, err)
@@ -195,7 +268,7 @@ func TestMempoolUpdate(t *testing.T) {
 	{
 		err := mempool.CheckTx([]byte{0x02}, nil)
 		require.NoError(t, err)
-		mempool.Update(1, []types.Tx{[]byte{0x02}}, abciResponses(1, nil), nil, 0)
+		mempool.Update(1, []types.Tx{[]byte{0x02}}, abciResponses(1, nil), nil, 0, 0)
 		assert.Zero(t, mempool.Size())
 	}
 
@@ -203,7 +276,7 @@ func TestMempoolUpdate(t *testing.T) {
 	{
 		err := mempool.CheckTx([]byte{0x03}, nil)
 		require.NoError(t, err)
-		mempool.Update(1, []types.Tx{[]byte{0x03}}, abciResponses(1, abci.StringError("1")), nil, 0)
+		mempool.Update(1, []types.Tx{[]byte{0x03}}, abciResponses(1, abci.StringError("1")), nil, 0, 0)
 		assert.Zero(t, mempool.Size())
 
 		err = mempool.CheckTx([]byte{0x03}, nil)
@@ -211,6 +284,167 @@ func TestMempoolUpdate(t *testing.T) {
 	}
 }
 
+// txHashApp is a kvstore whose canonical tx hash is the first byte of txs.
+type txHashApp struct {
+	*kvstore.KVStoreApplication
+}
+
+func (app txHashApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	res := app.KVStoreApplication.CheckTx(req)
+	res.TxHash = req.Tx[:1]
+	return res
+}
+
+func TestMempoolTxHash(t *testing.T) {
+	app := txHashApp{kvstore.NewKVStoreApplication()}
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	checkTx := func(tx types.Tx) abci.ResponseCheckTx {
+		resCh := make(chan abci.ResponseCheckTx, 1)
+		err := mempool.CheckTx(tx, func(res abci.Response) {
+			resCh <- res.(abci.ResponseCheckTx)
+		})
+		require.NoError(t, err)
+		return <-resCh
+	}
+
+	// txs with the same canonical hash are rejected.
+	res := checkTx([]byte{0x01, 0x01})
+	require.NoError(t, res.Error)
+	res = checkTx([]byte{0x01, 0x02})
+	require.Error(t, res.Error)
+	res = checkTx([]byte{0x02, 0x01})
+	require.NoError(t, res.Error)
+	assert.Equal(t, 2, mempool.Size())
+
+	// until the tx is removed from the mempool.
+	mempool.Update(1, []types.Tx{[]byte{0x01, 0x01}}, abciResponses(1, nil), nil, 0, 0)
+	assert.Equal(t, 1, mempool.Size())
+	res = checkTx([]byte{0x01, 0x02})
+	require.NoError(t, res.Error)
+	assert.Equal(t, 2, mempool.Size())
+}
+
+// Returns an app with the auth ante handler and the bank route, at height 1,
+// in which the account of priv has coins.
+func newAuthApp(t *testing.T, chainID string) (app *sdk.BaseApp, priv crypto.PrivKey, addr crypto.Address) {
+	t.Helper()
+
+	db := dbm.NewMemDB()
+	mainKey := store.NewStoreKey("main")
+	baseKey := store.NewStoreKey("base")
+	app = sdk.NewBaseApp("mempool", log.NewNopLogger(), db, baseKey, mainKey)
+	app.MountStoreWithDB(mainKey, iavl.StoreConstructor, db)
+	app.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+	acck := auth.NewAccountKeeper(mainKey, std.ProtoBaseAccount)
+	bankk := bank.NewBankKeeper(acck)
+
+	priv, _, addr = tu.KeyTestPubAddr()
+	app.SetInitChainer(func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+		acc := acck.NewAccountWithAddress(ctx, addr)
+		acc.SetCoins(tu.NewTestCoins())
+		acck.SetAccount(ctx, acc)
+		return abci.ResponseInitChain{}
+	})
+	anteHandler := auth.NewAnteHandler(acck, bankk, auth.DefaultSigVerificationGasConsumer, auth.AnteOptions{})
+	app.SetAnteHandler(func(ctx sdk.Context, tx std.Tx, simulate bool) (sdk.Context, sdk.Result, bool) {
+		ctx = ctx.WithValue(auth.AuthParamsContextKey{}, auth.DefaultParams())
+		return anteHandler(ctx, tx, simulate)
+	})
+	app.SetTxHasher(auth.NewTxHasher())
+	app.Router().AddRoute(bank.ModuleName, bank.NewHandler(bankk))
+	require.NoError(t, app.LoadLatestVersion())
+
+	app.InitChain(abci.RequestInitChain{
+		ChainID: chainID,
+		ConsensusParams: &abci.ConsensusParams{
+			Block: &abci.BlockParams{MaxTxBytes: 10000, MaxDataBytes: 100000, MaxBlockBytes: 100000, MaxGas: -1, TimeIotaMS: 10},
+		},
+	})
+	app.BeginBlock(abci.RequestBeginBlock{Header: &types.Header{ChainID: chainID, Height: 1}})
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+	return app, priv, addr
+}
+
+// Checks tx in the mempool, and returns the response of the app.
+func checkStdTx(t *testing.T, mempool Mempool, tx std.Tx) abci.ResponseCheckTx {
+	t.Helper()
+
+	resCh := make(chan abci.ResponseCheckTx, 1)
+	err := mempool.CheckTx(amino.MustMarshal(tx), func(res abci.Response) {
+		resCh <- res.(abci.ResponseCheckTx)
+	})
+	require.NoError(t, err)
+	return <-resCh
+}
+
+// Test that the txs of an sdk app with the same msgs, fee and memo have the
+// same canonical hash whatever the sequences they are signed at, so that only
+// one of them can be pending in the mempool.
+func TestMempoolTxHashSequences(t *testing.T) {
+	chainID := "test-chain"
+	app, priv, addr := newAuthApp(t, chainID)
+	_, _, addr2 := tu.KeyTestPubAddr()
+	mempool, cleanup := newMempoolWithApp(proxy.NewLocalClientCreator(app))
+	defer cleanup()
+
+	// the same msgs, fee and memo, at sequences 0 and 1.
+	msgs := []std.Msg{bank.NewMsgSend(addr, addr2, std.Coins{std.NewCoin("atom", 10)})}
+	tx0 := tu.NewTestTx(chainID, msgs, []crypto.PrivKey{priv}, []uint64{0}, []uint64{0}, tu.NewTestFee())
+	tx1 := tu.NewTestTx(chainID, msgs, []crypto.PrivKey{priv}, []uint64{0}, []uint64{1}, tu.NewTestFee())
+	res0 := checkStdTx(t, mempool, tx0)
+	require.NoError(t, res0.Error, res0.Log)
+	assert.Equal(t, tx0.Hash(chainID), res0.TxHash)
+	res1 := checkStdTx(t, mempool, tx1)
+	assert.Equal(t, res0.TxHash, res1.TxHash)
+	require.Error(t, res1.Error)
+	assert.Equal(t, 1, mempool.Size())
+
+	// another memo tells them apart.
+	tx1 = tu.NewTestTxWithMemo(chainID, msgs, []crypto.PrivKey{priv}, []uint64{0}, []uint64{1}, tu.NewTestFee(), "1")
+	res1 = checkStdTx(t, mempool, tx1)
+	require.NoError(t, res1.Error, res1.Log)
+	assert.NotEqual(t, res0.TxHash, res1.TxHash)
+	assert.Equal(t, 2, mempool.Size())
+}
+
+func TestMempoolCheckTxAfterRecheck(t *testing.T) {
+	chainID := "test-chain"
+	app, priv, addr := newAuthApp(t, chainID)
+	_, _, addr2 := tu.KeyTestPubAddr()
+	config := cfg.TestMempoolConfig()
+	config.RecheckBatchSize = 1
+	mempool, cleanup := newMempoolWithAppAndConfig(proxy.NewLocalClientCreator(app), config)
+	defer cleanup()
+
+	newTx := func(seq uint64) std.Tx {
+		msgs := []std.Msg{bank.NewMsgSend(addr, addr2, std.Coins{std.NewCoin("atom", 10)})}
+		memo := fmt.Sprintf("%d", seq)
+		return tu.NewTestTxWithMemo(chainID, msgs, []crypto.PrivKey{priv}, []uint64{0}, []uint64{seq}, tu.NewTestFee(), memo)
+	}
+	for seq := uint64(0); seq < 3; seq++ {
+		res := checkStdTx(t, mempool, newTx(seq))
+		require.NoError(t, res.Error, res.Log)
+	}
+
+	// An empty block resets the check state of the app to the committed
+	// state, and the txs are rechecked in batches.
+	app.BeginBlock(abci.RequestBeginBlock{Header: &types.Header{ChainID: chainID, Height: 2}})
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+	mempool.Lock()
+	require.NoError(t, mempool.Update(2, types.Txs{}, abciResponses(0, nil), nil, testMaxTxBytes, -1))
+	mempool.Unlock()
+
+	// The tx following those of the mempool is checked after their recheck.
+	res := checkStdTx(t, mempool, newTx(3))
+	require.NoError(t, res.Error, res.Log)
+	assert.Equal(t, 4, mempool.Size())
+}
+
 func TestTxsAvailable(t *testing.T) {
 	app := kvstore.NewKVStoreApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -232,7 +466,7 @@ func TestTxsAvailable(t *testing.T) {
 	// it should fire once now for the new height
 	// since there are still txs left
 	committedTxs, txs := txs[:50], txs[50:]
-	if err := mempool.Update(1, committedTxs, abciResponses(len(committedTxs), nil), nil, 0); err != nil {
+	if err := mempool.Update(1, committedTxs, abciResponses(len(committedTxs), nil), nil, 0, 0); err != nil {
 		t.Error(err)
 	}
 	ensureFire(t, mempool.TxsAvailable(), timeoutMS)
@@ -244,7 +478,7 @@ func TestTxsAvailable(t *testing.T) {
 
 	// now call update with all the txs. it should not fire as there are no txs left
 	committedTxs = append(txs, moreTxs...) //nolint: gocritic
-	if err := mempool.Update(2, committedTxs, abciResponses(len(committedTxs), nil), nil, 0); err != nil {
+	if err := mempool.Update(2, committedTxs, abciResponses(len(committedTxs), nil), nil, 0, 0); err != nil {
 		t.Error(err)
 	}
 	ensureNoFire(t, mempool.TxsAvailable(), timeoutMS)
@@ -303,7 +537,7 @@ func TestSerialReap(t *testing.T) {
 			binary.BigEndian.PutUint64(txBytes, uint64(i))
 			txs = append(txs, txBytes)
 		}
-		if err := mempool.Update(0, txs, abciResponses(len(txs), nil), nil, 0); err != nil {
+		if err := mempool.Update(0, txs, abciResponses(len(txs), nil), nil, 0, 0); err != nil {
 			t.Error(err)
 		}
 	}
@@ -472,7 +706,7 @@ func TestMempoolMaxPendingTxsBytes(t *testing.T) {
 	assert.EqualValues(t, 1, mempool.TxsBytes())
 
 	// 3. zero again after tx is removed by Update
-	mempool.Update(1, []types.Tx{[]byte{0x01}}, abciResponses(1, nil), nil, 0)
+	mempool.Update(1, []types.Tx{[]byte{0x01}}, abciResponses(1, nil), nil, 0, 0)
 	assert.EqualValues(t, 0, mempool.TxsBytes())
 
 	// 4. zero after Flush
@@ -517,10 +751,127 @@ func TestMempoolMaxPendingTxsBytes(t *testing.T) {
 	require.NotEmpty(t, res2.Data)
 
 	// Pretend like we committed nothing so txBytes gets rechecked and removed.
-	mempool.Update(1, []types.Tx{}, abciResponses(0, nil), nil, 0)
+	mempool.Update(1, []types.Tx{}, abciResponses(0, nil), nil, 0, 0)
+	waitRecheck(mempool)
 	assert.EqualValues(t, 0, mempool.TxsBytes())
 }
 
+func TestMempoolMetricsAndEvents(t *testing.T) {
+	app := counter.NewCounterApplication(true)
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+	mempool.SetEventSwitch(evsw)
+	var evs []events.Event
+	evsw.AddListener("test", func(ev events.Event) { evs = append(evs, ev) })
+
+	txBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(txBytes, uint64(0))
+	require.NoError(t, mempool.CheckTx(txBytes, nil))
+
+	// 1. rejections
+	err := mempool.CheckTx(txBytes, nil)
+	assert.Equal(t, ErrTxInCache, err)
+	err = mempool.CheckTx(make([]byte, testMaxTxBytes+1), nil)
+	assert.IsType(t, ErrTxTooLarge{}, err)
+	err = mempool.CheckTx(make([]byte, 9), nil) // the counter app accepts up to 8 bytes
+	require.NoError(t, err)
+	require.Len(t, evs, 3)
+	assert.Equal(t, types.EventTxRejected{Tx: txBytes, Reason: ReasonTxInCache, Error: ErrTxInCache.Error()}, evs[0])
+	assert.Equal(t, ReasonTxTooLarge, evs[1].(types.EventTxRejected).Reason)
+	assert.Equal(t, ReasonCheckTx, evs[2].(types.EventTxRejected).Reason)
+	assert.NotEmpty(t, evs[2].(types.EventTxRejected).Error)
+
+	// 2. evictions, after the tx is committed without the mempool knowing it
+	appConnCon, _ := cc.NewABCIClient()
+	require.NoError(t, appConnCon.Start())
+	defer appConnCon.Stop()
+	_, err = appConnCon.DeliverTxSync(abci.RequestDeliverTx{Tx: txBytes})
+	require.NoError(t, err)
+	_, err = appConnCon.CommitSync()
+	require.NoError(t, err)
+	mempool.Update(1, []types.Tx{}, abciResponses(0, nil), nil, 0, 0)
+	waitRecheck(mempool)
+	require.Len(t, evs, 4)
+	evicted := evs[3].(types.EventTxEvicted)
+	assert.Equal(t, types.Tx(txBytes), evicted.Tx)
+	assert.Equal(t, int64(1), evicted.Height)
+	assert.Equal(t, ReasonRecheckTx, evicted.Reason)
+
+	// 3. metrics
+	buf := new(bytes.Buffer)
+	mempool.Metrics().WriteText(buf)
+	out := buf.String()
+	assert.Contains(t, out, "mempool_size 0\n")
+	assert.Contains(t, out, "mempool_size_bytes 0\n")
+	assert.Contains(t, out, "mempool_rejected_txs_total{reason=\"check_tx\"} 1\n")
+	assert.Contains(t, out, "mempool_rejected_txs_total{reason=\"tx_in_cache\"} 1\n")
+	assert.Contains(t, out, "mempool_rejected_txs_total{reason=\"tx_too_large\"} 1\n")
+	assert.Contains(t, out, "mempool_evicted_txs_total{reason=\"recheck_tx\"} 1\n")
+	assert.Contains(t, out, "mempool_recheck_duration_seconds_count 1\n")
+}
+
+func TestMempoolRecheckBatches(t *testing.T) {
+	app := counter.NewCounterApplication(true)
+	cc := proxy.NewLocalClientCreator(app)
+	config := cfg.TestMempoolConfig()
+	config.RecheckBatchSize = 2
+	mempool, cleanup := newMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	var evicted []types.EventTxEvicted
+	evsw := events.NewEventSwitch()
+	evsw.AddListener("test", func(ev events.Event) {
+		if ev, ok := ev.(types.EventTxEvicted); ok {
+			evicted = append(evicted, ev)
+		}
+	})
+	mempool.SetEventSwitch(evsw)
+
+	txs := make(types.Txs, 5)
+	for i := range txs {
+		txs[i] = make([]byte, 8)
+		binary.BigEndian.PutUint64(txs[i], uint64(i))
+		require.NoError(t, mempool.CheckTx(txs[i], nil))
+	}
+
+	// Commit the first 3 txs without the mempool knowing it.
+	appConnCon, _ := cc.NewABCIClient()
+	require.NoError(t, appConnCon.Start())
+	defer appConnCon.Stop()
+	for _, tx := range txs[:3] {
+		_, err := appConnCon.DeliverTxSync(abci.RequestDeliverTx{Tx: tx})
+		require.NoError(t, err)
+	}
+	_, err := appConnCon.CommitSync()
+	require.NoError(t, err)
+
+	// The txs are rechecked after the updates, not during them: the
+	// recheck of the first update is cancelled by the second.
+	mempool.Lock()
+	mempool.Update(1, []types.Tx{}, abciResponses(0, nil), nil, 0, 0)
+	mempool.Update(2, []types.Tx{}, abciResponses(0, nil), nil, 0, 0)
+	assert.Equal(t, 5, mempool.Size())
+	mempool.Unlock()
+
+	waitRecheck(mempool)
+	assert.Equal(t, txs[3:], mempool.ReapMaxTxs(-1))
+	require.Len(t, evicted, 3)
+	for i, ev := range evicted {
+		assert.Equal(t, txs[i], ev.Tx)
+		assert.Equal(t, int64(2), ev.Height)
+		assert.Equal(t, ReasonRecheckTx, ev.Reason)
+	}
+
+	buf := new(bytes.Buffer)
+	mempool.Metrics().WriteText(buf)
+	assert.Contains(t, buf.String(), "mempool_recheck_duration_seconds_count 1\n")
+}
+
 func checksumIt(data []byte) string {
 	h := sha256.New()
 	h.Write(data)
@@ -544,3 +895,9 @@ func abciResponses(n int, err abci.Error) []abci.ResponseDeliverTx {
 	}
 	return responses
 }
+
+// Waits for the recheck of the txs after the last Update.
+func waitRecheck(mempool *CListMempool) {
+	mempool.lockRechecked()
+	mempool.mtx.Unlock()
+}
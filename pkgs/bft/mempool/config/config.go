@@ -9,19 +9,22 @@ import "github.com/gnolang/gno/pkgs/errors"
 type MempoolConfig struct {
 	RootDir            string `toml:"home"`
 	Recheck            bool   `toml:"recheck"`
+	RecheckBatchSize   int    `toml:"recheck_batch_size"`
 	Broadcast          bool   `toml:"broadcast"`
 	WalPath            string `toml:"wal_dir"`
 	Size               int    `toml:"size"`
 	MaxPendingTxsBytes int64  `toml:"max_pending_txs_bytes"`
 	CacheSize          int    `toml:"cache_size"`
+	SystemLaneGas      int64  `toml:"system_lane_gas"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
 func DefaultMempoolConfig() *MempoolConfig {
 	return &MempoolConfig{
-		Recheck:   true,
-		Broadcast: true,
-		WalPath:   "",
+		Recheck:          true,
+		RecheckBatchSize: 1000,
+		Broadcast:        true,
+		WalPath:          "",
 		// Each signature verification takes .5ms, Size reduced until we implement
 		// ABCI Recheck
 		Size:               5000,
@@ -50,6 +53,9 @@ func (cfg *MempoolConfig) WalEnabled() bool {
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *MempoolConfig) ValidateBasic() error {
+	if cfg.RecheckBatchSize < 0 {
+		return errors.New("recheck_batch_size can't be negative")
+	}
 	if cfg.Size < 0 {
 		return errors.New("size can't be negative")
 	}
@@ -59,5 +65,8 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.CacheSize < 0 {
 		return errors.New("cache_size can't be negative")
 	}
+	if cfg.SystemLaneGas < 0 {
+		return errors.New("system_lane_gas can't be negative")
+	}
 	return nil
 }
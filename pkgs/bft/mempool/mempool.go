@@ -25,6 +25,10 @@ type Mempool interface {
 	// maxGas.
 	// If both maxes are negative, there is no cap on the size of all returned
 	// transactions (~ all available transactions).
+	// Transactions above the max tx bytes or gas, or which alone exceed the
+	// maxes, are skipped, so that they can't make the block invalid.
+	// The transactions of the system lane are reaped first, up to the
+	// system lane gas of the config.
 	ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Txs
 
 	// ReapMaxTxs reaps up to max transactions from the mempool.
@@ -41,7 +45,8 @@ type Mempool interface {
 	// Update informs the mempool that the given txs were committed and can be discarded.
 	// NOTE: this should be called *after* block is committed by consensus.
 	// NOTE: unsafe; Lock/Unlock must be managed by caller
-	Update(blockHeight int64, blockTxs types.Txs, deliverTxResponses []abci.ResponseDeliverTx, newPreFn PreCheckFunc, maxTxBytes int64) error
+	// NOTE: maxTxBytes and maxTxGas are left unchanged if 0.
+	Update(blockHeight int64, blockTxs types.Txs, deliverTxResponses []abci.ResponseDeliverTx, newPreFn PreCheckFunc, maxTxBytes, maxTxGas int64) error
 
 	// FlushAppConn flushes the mempool connection to ensure async reqResCb calls are
 	// done. E.g. from CheckTx.
@@ -0,0 +1,113 @@
+package mempool
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Reasons for which txs are rejected from, or evicted from, the mempool,
+// counted in the metrics and set in EventTxRejected and EventTxEvicted.
+const (
+	ReasonMempoolFull   = "mempool_full"   // rejected: too many txs or bytes
+	ReasonTxTooLarge    = "tx_too_large"   // rejected, or evicted on recheck
+	ReasonPreCheck      = "pre_check"      // rejected, or evicted on recheck
+	ReasonTxInCache     = "tx_in_cache"    // rejected: seen recently
+	ReasonDuplicateHash = "duplicate_hash" // rejected: same hash as a tx in the mempool
+	ReasonCheckTx       = "check_tx"       // rejected by the app
+	ReasonRecheckTx     = "recheck_tx"     // evicted: rejected by the app on recheck
+)
+
+// Metrics are the metrics of a mempool, served in the text format of
+// Prometheus: the number and bytes of its txs, the txs rejected and
+// evicted by reason, and the duration of the rechecks of the txs after
+// each block.
+type Metrics struct {
+	mem *CListMempool
+
+	mtx            sync.Mutex
+	rejections     map[string]uint64 // reason -> count
+	evictions      map[string]uint64 // reason -> count
+	recheckStart   time.Time
+	recheckSeconds float64
+	rechecks       uint64
+}
+
+func newMetrics(mem *CListMempool) *Metrics {
+	return &Metrics{
+		mem:        mem,
+		rejections: make(map[string]uint64),
+		evictions:  make(map[string]uint64),
+	}
+}
+
+func (m *Metrics) markRejection(reason string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.rejections[reason]++
+}
+
+func (m *Metrics) markEviction(reason string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.evictions[reason]++
+}
+
+func (m *Metrics) markRecheckStart() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.recheckStart = time.Now()
+}
+
+func (m *Metrics) markRecheckEnd() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.recheckSeconds += time.Since(m.recheckStart).Seconds()
+	m.rechecks++
+}
+
+// ServeHTTP serves the metrics, e.g. at /metrics.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteText(w)
+}
+
+// WriteText writes the metrics in the text format of Prometheus.
+func (m *Metrics) WriteText(w io.Writer) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	fmt.Fprintf(w, "# HELP mempool_size Number of txs in the mempool.\n")
+	fmt.Fprintf(w, "# TYPE mempool_size gauge\n")
+	fmt.Fprintf(w, "mempool_size %d\n", m.mem.Size())
+	fmt.Fprintf(w, "# HELP mempool_size_bytes Total bytes of the txs in the mempool.\n")
+	fmt.Fprintf(w, "# TYPE mempool_size_bytes gauge\n")
+	fmt.Fprintf(w, "mempool_size_bytes %d\n", m.mem.TxsBytes())
+	writeCounters(w, "mempool_rejected_txs_total", "Txs rejected from the mempool, by reason.", m.rejections)
+	writeCounters(w, "mempool_evicted_txs_total", "Txs evicted from the mempool, by reason.", m.evictions)
+	fmt.Fprintf(w, "# HELP mempool_recheck_duration_seconds Time spent rechecking the txs after a block.\n")
+	fmt.Fprintf(w, "# TYPE mempool_recheck_duration_seconds summary\n")
+	fmt.Fprintf(w, "mempool_recheck_duration_seconds_sum %g\n", m.recheckSeconds)
+	fmt.Fprintf(w, "mempool_recheck_duration_seconds_count %d\n", m.rechecks)
+}
+
+// Writes the counters name, by reason.
+func writeCounters(w io.Writer, name, help string, counts map[string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "%s{reason=%q} %d\n", name, reason, counts[reason])
+	}
+}
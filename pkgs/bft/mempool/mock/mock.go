@@ -31,7 +31,7 @@ func (Mempool) Update(
 	_ types.Txs,
 	_ []abci.ResponseDeliverTx,
 	_ mempl.PreCheckFunc,
-	_ int64,
+	_, _ int64,
 ) error {
 	return nil
 }
@@ -0,0 +1,59 @@
+package node
+
+import (
+	"crypto/subtle"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"github.com/gnolang/gno/pkgs/amino"
+)
+
+func init() {
+	expvar.Publish("amino", expvar.Func(func() interface{} { return amino.Stats() }))
+	expvar.Publish("goroutines", expvar.Func(func() interface{} { return runtime.NumGoroutine() }))
+}
+
+// newDebugHandler returns the handler of the debug server, serving to the
+// requests with the header "Authorization: Bearer <token>":
+//
+//   - /debug/pprof/: the profiles of pprof, e.g. /debug/pprof/profile for
+//     the cpu, /debug/pprof/heap, and /debug/pprof/goroutine?debug=2 for a
+//     dump of the stacks of all the goroutines
+//   - /debug/vars: the variables of expvar, with the memory stats of the
+//     runtime, the number of goroutines, and the stats of the amino codec
+func newDebugHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	auth := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), auth) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// startDebugServer starts the debug server, listening for the admin on
+// addr, authenticated with token.
+func (n *Node) startDebugServer(addr, token string) *http.Server {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: newDebugHandler(token),
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			// Error starting or closing listener:
+			n.Logger.Error("Debug HTTP server ListenAndServe", "err", err)
+		}
+	}()
+	return srv
+}
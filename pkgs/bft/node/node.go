@@ -1,11 +1,13 @@
 package node
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gnolang/cors"
@@ -26,12 +28,15 @@ import (
 	"github.com/gnolang/gno/pkgs/bft/state/txindex"
 	"github.com/gnolang/gno/pkgs/events"
 
-	//"github.com/gnolang/gno/pkgs/bft/state/txindex/kv"
+	"github.com/gnolang/gno/pkgs/bft/state/txindex/kv"
 	"github.com/gnolang/gno/pkgs/bft/state/txindex/null"
 	"github.com/gnolang/gno/pkgs/bft/store"
+	"github.com/gnolang/gno/pkgs/bft/store/cold"
+	"github.com/gnolang/gno/pkgs/bft/telemetry"
 	"github.com/gnolang/gno/pkgs/bft/types"
 	tmtime "github.com/gnolang/gno/pkgs/bft/types/time"
 	"github.com/gnolang/gno/pkgs/bft/version"
+	"github.com/gnolang/gno/pkgs/bft/webhook"
 	"github.com/gnolang/gno/pkgs/crypto"
 	dbm "github.com/gnolang/gno/pkgs/db"
 	"github.com/gnolang/gno/pkgs/errors"
@@ -95,6 +100,7 @@ func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
 		config.ProxyApp,
 		config.ABCI,
 		config.DBDir(),
+		config.ABCISocketOptions(),
 	)
 
 	return NewNode(config,
@@ -151,6 +157,8 @@ type Node struct {
 	sw          *p2p.Switch // p2p connections
 	nodeInfo    p2p.NodeInfo
 	nodeKey     *p2p.NodeKey // our node privkey
+	addrBook    *p2p.AddrBook
+	crawler     *p2p.Crawler // nil if not crawling
 	isListening bool
 
 	// services
@@ -164,8 +172,13 @@ type Node struct {
 	consensusReactor *cs.ConsensusReactor // for participating in the consensus
 	proxyApp         proxy.AppConns       // connection to the application
 	rpcListeners     []net.Listener       // rpc servers
+	prometheusSrv    *http.Server         // metrics server
+	debugSrv         *http.Server         // debug server
 	txIndexer        txindex.TxIndexer
 	indexerService   *txindex.IndexerService
+	webhookNotifier  *webhook.Notifier  // nil if no webhooks
+	offloader        *cold.Offloader    // nil if no cold storage
+	telemetry        *telemetry.Service // nil if no telemetry
 }
 
 func initDBs(config *cfg.Config, dbProvider DBProvider) (blockStore *store.BlockStore, stateDB dbm.DB, err error) {
@@ -196,26 +209,17 @@ func createAndStartProxyAppConns(clientCreator proxy.ClientCreator, logger log.L
 func createAndStartIndexerService(config *cfg.Config, dbProvider DBProvider,
 	evsw events.EventSwitch, logger log.Logger,
 ) (*txindex.IndexerService, txindex.TxIndexer, error) {
-	var txIndexer txindex.TxIndexer = &null.TxIndex{}
-	/*
-		switch config.TxIndex.Indexer {
-		case "kv":
-			store, err := dbProvider(&DBContext{"tx_index", config})
-			if err != nil {
-				return nil, nil, err
-			}
-			switch {
-			case config.TxIndex.IndexTags != "":
-				txIndexer = kv.NewTxIndex(store, kv.IndexTags(splitAndTrimEmpty(config.TxIndex.IndexTags, ",", " ")))
-			case config.TxIndex.IndexAllTags:
-				txIndexer = kv.NewTxIndex(store, kv.IndexAllTags())
-			default:
-				txIndexer = kv.NewTxIndex(store)
-			}
-		default:
-			txIndexer = &null.TxIndex{}
+	var txIndexer txindex.TxIndexer
+	switch config.TxIndex.Indexer {
+	case "kv":
+		store, err := dbProvider(&DBContext{"tx_index", config})
+		if err != nil {
+			return nil, nil, err
 		}
-	*/
+		txIndexer = kv.NewTxIndex(store)
+	default:
+		txIndexer = &null.TxIndex{}
+	}
 
 	indexerService := txindex.NewIndexerService(txIndexer, evsw)
 	indexerService.SetLogger(logger.With("module", "txindex"))
@@ -237,6 +241,29 @@ func doHandshake(stateDB dbm.DB, state sm.State, blockStore sm.BlockStore,
 	return nil
 }
 
+// Returns the function doing the handshake with the app once reconnected,
+// on connections of their own since those of the node wait for it.  The
+// handshakes of the connections reconnecting together are serialized, and
+// only the first replays blocks.
+func reconnectHandshake(stateDB dbm.DB, blockStore sm.BlockStore, genDoc *types.GenesisDoc,
+	clientCreator proxy.ClientCreator, consensusLogger log.Logger,
+) func() error {
+	var mtx sync.Mutex
+	return func() error {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		proxyApp, err := createAndStartProxyAppConns(clientCreator, consensusLogger)
+		if err != nil {
+			return err
+		}
+		defer proxyApp.Stop()
+		state := sm.LoadState(stateDB)
+		// The events of the replayed blocks were already fired.
+		return doHandshake(stateDB, state, blockStore, genDoc, events.NilEventSwitch(), proxyApp, consensusLogger)
+	}
+}
+
 func logNodeStartupInfo(state sm.State, pubKey crypto.PubKey, logger, consensusLogger log.Logger) {
 	// Log the version info.
 	logger.Info("Version info",
@@ -268,6 +295,7 @@ func createMempoolAndMempoolReactor(config *cfg.Config, proxyApp proxy.AppConns,
 		proxyApp.Mempool(),
 		state.LastBlockHeight,
 		state.ConsensusParams.Block.MaxTxBytes,
+		types.MaxTxGas(state.ConsensusParams.Block),
 		mempl.WithPreCheck(sm.TxPreCheck(state)),
 	)
 	mempoolLogger := logger.With("module", "mempool")
@@ -386,12 +414,14 @@ func createSwitch(config *cfg.Config,
 	consensusReactor *consensus.ConsensusReactor,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
+	addrBook *p2p.AddrBook,
 	p2pLogger log.Logger,
 ) *p2p.Switch {
 	sw := p2p.NewSwitch(
 		config.P2P,
 		transport,
 		p2p.SwitchPeerFilters(peerFilters...),
+		p2p.SwitchAddrBook(addrBook),
 	)
 	sw.SetLogger(p2pLogger)
 	sw.AddReactor("MEMPOOL", mempoolReactor)
@@ -425,6 +455,23 @@ func NewNode(config *cfg.Config,
 		return nil, err
 	}
 
+	// The addresses of the chain are encoded with its prefix, which must be
+	// set on startup.
+	prefix := genDoc.Bech32Prefix
+	if prefix == "" {
+		prefix = crypto.Bech32AddrPrefix
+	}
+	if prefix != crypto.Bech32Prefix() {
+		return nil, fmt.Errorf("bech32 prefix of the genesis %q isn't the one set %q", prefix, crypto.Bech32Prefix())
+	}
+
+	// On reconnection to a socket app, e.g. restarted, replay the blocks it
+	// lost before resuming the requests.
+	if rcc, ok := clientCreator.(*proxy.RemoteClientCreator); ok {
+		rcc.SetOnReconnect(reconnectHandshake(stateDB, blockStore, genDoc,
+			rcc.WithoutOnReconnect(), logger.With("module", "consensus")))
+	}
+
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
 	proxyApp, err := createAndStartProxyAppConns(clientCreator, logger)
 	if err != nil {
@@ -443,6 +490,31 @@ func NewNode(config *cfg.Config,
 		return nil, err
 	}
 
+	// Webhooks, notified from the start of the node.
+	var webhookNotifier *webhook.Notifier
+	if config.Webhook.Enabled() {
+		webhookNotifier, err = webhook.NewNotifier(config.Webhook, evsw)
+		if err != nil {
+			return nil, err
+		}
+		webhookNotifier.SetLogger(logger.With("module", "webhook"))
+	}
+
+	// Cold storage of the old blocks, also needed to load the blocks
+	// offloaded before it was disabled.
+	var offloader *cold.Offloader
+	if config.ColdStorage.Enabled() || blockStore.Offloaded() > 0 {
+		coldStore, err := cold.NewColdStore(config.ColdStorage)
+		if err != nil {
+			return nil, err
+		}
+		blockStore.SetColdStore(coldStore)
+	}
+	if config.ColdStorage.Enabled() {
+		offloader = cold.NewOffloader(blockStore, config.ColdStorage.OffloadAfter, evsw)
+		offloader.SetLogger(logger.With("module", "cold_storage"))
+	}
+
 	// Create the handshaker, which calls RequestInfo, sets the AppVersion on the state,
 	// and replays any blocks as necessary to sync tendermint with the app.
 	consensusLogger := logger.With("module", "consensus")
@@ -465,6 +537,12 @@ func NewNode(config *cfg.Config,
 		}
 	}
 
+	// Rotate the key of the private validator, if it is due, before the
+	// key is used to decide whether to fast-sync.
+	if rpv, ok := privValidator.(types.RotatingPrivValidator); ok {
+		rpv.UpdateToHeight(state.LastBlockHeight+1, state.Validators)
+	}
+
 	pubKey := privValidator.GetPubKey()
 	if pubKey == nil {
 		// TODO: GetPubKey should return errors - https://github.com/gnolang/gno/pkgs/bft/issues/3602
@@ -479,6 +557,7 @@ func NewNode(config *cfg.Config,
 
 	// Make MempoolReactor
 	mempoolReactor, mempool := createMempoolAndMempoolReactor(config, proxyApp, state, logger)
+	mempool.SetEventSwitch(evsw)
 
 	// make block executor for consensus and blockchain reactors to execute blocks
 	blockExec := sm.NewBlockExecutor(
@@ -509,10 +588,14 @@ func NewNode(config *cfg.Config,
 	transport, peerFilters := createTransport(config, nodeInfo, nodeKey, proxyApp)
 
 	// Setup Switch.
+	addrBook, err := p2p.NewAddrBook(config.P2P.AddrBookFile())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load address book")
+	}
 	p2pLogger := logger.With("module", "p2p")
 	sw := createSwitch(
 		config, transport, peerFilters, mempoolReactor, bcReactor,
-		consensusReactor, nodeInfo, nodeKey, p2pLogger,
+		consensusReactor, nodeInfo, nodeKey, addrBook, p2pLogger,
 	)
 
 	err = sw.AddPersistentPeers(splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
@@ -520,6 +603,25 @@ func NewNode(config *cfg.Config,
 		return nil, errors.Wrap(err, "could not add peers from persistent_peers field")
 	}
 
+	var crawler *p2p.Crawler
+	if config.P2P.CrawlInterval > 0 {
+		crawler = p2p.NewCrawler(sw, config.P2P.CrawlInterval, config.P2P.CrawlReportFile())
+		crawler.SetLogger(p2pLogger.With("module", "crawler"))
+	}
+
+	var telemetryService *telemetry.Service
+	if config.Telemetry.Enabled() {
+		telemetryService = telemetry.NewService(config.Telemetry, evsw, telemetry.NodeInfo{
+			Version: nodeInfo.Version,
+			ChainID: nodeInfo.Network,
+			NodeID:  nodeKey.ID().String(),
+			Moniker: config.Moniker,
+		}, func() int {
+			return sw.Peers().Size()
+		}, consensusReactor.FastSync)
+		telemetryService.SetLogger(logger.With("module", "telemetry"))
+	}
+
 	if config.ProfListenAddress != "" {
 		go func() {
 			logger.Error("Profile server", "err", http.ListenAndServe(config.ProfListenAddress, nil))
@@ -535,6 +637,8 @@ func NewNode(config *cfg.Config,
 		sw:        sw,
 		nodeInfo:  nodeInfo,
 		nodeKey:   nodeKey,
+		addrBook:  addrBook,
+		crawler:   crawler,
 
 		evsw:             evsw,
 		stateDB:          stateDB,
@@ -547,6 +651,9 @@ func NewNode(config *cfg.Config,
 		proxyApp:         proxyApp,
 		txIndexer:        txIndexer,
 		indexerService:   indexerService,
+		webhookNotifier:  webhookNotifier,
+		offloader:        offloader,
+		telemetry:        telemetryService,
 	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
 
@@ -576,6 +683,26 @@ func (n *Node) OnStart() error {
 		n.rpcListeners = listeners
 	}
 
+	if n.config.PrometheusListenAddress != "" {
+		n.prometheusSrv = n.startPrometheusServer(n.config.PrometheusListenAddress)
+	}
+
+	if n.config.DebugListenAddress != "" {
+		n.debugSrv = n.startDebugServer(n.config.DebugListenAddress, n.config.DebugAuthToken)
+	}
+
+	if n.webhookNotifier != nil {
+		if err := n.webhookNotifier.Start(); err != nil {
+			return err
+		}
+	}
+
+	if n.offloader != nil {
+		if err := n.offloader.Start(); err != nil {
+			return err
+		}
+	}
+
 	// Start the transport.
 	addr, err := p2p.NewNetAddressFromString(p2p.NetAddressString(n.nodeKey.ID(), n.config.P2P.ListenAddress))
 	if err != nil {
@@ -603,6 +730,18 @@ func (n *Node) OnStart() error {
 		return errors.Wrap(err, "could not dial peers from persistent_peers field")
 	}
 
+	if n.crawler != nil {
+		if err := n.crawler.Start(); err != nil {
+			return err
+		}
+	}
+
+	if n.telemetry != nil {
+		if err := n.telemetry.Start(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -615,8 +754,21 @@ func (n *Node) OnStop() {
 	// first stop the non-reactor services
 	n.evsw.Stop()
 	n.indexerService.Stop()
+	if n.webhookNotifier != nil {
+		n.webhookNotifier.Stop()
+	}
+	if n.offloader != nil {
+		n.offloader.Stop()
+	}
+
+	if n.telemetry != nil {
+		n.telemetry.Stop()
+	}
 
 	// now stop the reactors
+	if n.crawler != nil {
+		n.crawler.Stop()
+	}
 	n.sw.Stop()
 
 	// stop mempool WAL
@@ -634,11 +786,49 @@ func (n *Node) OnStop() {
 		}
 	}
 
+	if n.prometheusSrv != nil {
+		if err := n.prometheusSrv.Shutdown(context.Background()); err != nil {
+			// Error from closing listeners, or context timeout:
+			n.Logger.Error("Prometheus HTTP server Shutdown", "err", err)
+		}
+	}
+
+	if n.debugSrv != nil {
+		if err := n.debugSrv.Shutdown(context.Background()); err != nil {
+			n.Logger.Error("Debug HTTP server Shutdown", "err", err)
+		}
+	}
+
 	if pvsc, ok := n.privValidator.(service.Service); ok {
 		pvsc.Stop()
 	}
 }
 
+// startPrometheusServer starts a Prometheus HTTP server, listening for
+// metrics collectors on addr, serving the metrics of the consensus and of
+// the mempool.
+func (n *Node) startPrometheusServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		n.consensusState.Metrics().WriteText(w)
+		if mempool, ok := n.mempool.(*mempl.CListMempool); ok {
+			mempool.Metrics().WriteText(w)
+		}
+	})
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			// Error starting or closing listener:
+			n.Logger.Error("Prometheus HTTP server ListenAndServe", "err", err)
+		}
+	}()
+	return srv
+}
+
 // ConfigureRPC sets all variables in rpccore so they will serve
 // rpc calls from this node
 func (n *Node) ConfigureRPC() {
@@ -648,8 +838,10 @@ func (n *Node) ConfigureRPC() {
 	rpccore.SetMempool(n.mempool)
 	rpccore.SetP2PPeers(n.sw)
 	rpccore.SetP2PTransport(n)
-	pubKey := n.privValidator.GetPubKey()
-	rpccore.SetPubKey(pubKey)
+	rpccore.SetAddrBook(n.addrBook)
+	rpccore.SetCrawler(n.crawler)
+	rpccore.SetNodeKey(n.nodeKey)
+	rpccore.SetPrivValidator(n.privValidator)
 	rpccore.SetGenesisDoc(n.genesisDoc)
 	rpccore.SetProxyAppQuery(n.proxyApp.Query())
 	rpccore.SetTxIndexer(n.txIndexer)
@@ -1,8 +1,11 @@
 package node
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"syscall"
 	"testing"
@@ -90,6 +93,38 @@ func TestSplitAndTrimEmpty(t *testing.T) {
 	}
 }
 
+func TestDebugHandler(t *testing.T) {
+	handler := newDebugHandler("secret")
+	get := func(path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// the requests without the token are unauthorized.
+	assert.Equal(t, http.StatusUnauthorized, get("/debug/vars", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("/debug/vars", "Bearer wrong").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("/debug/pprof/", "secret").Code)
+
+	rec := get("/debug/vars", "Bearer secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var vars struct {
+		Amino      map[string]int `json:"amino"`
+		Goroutines int            `json:"goroutines"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+	assert.Contains(t, vars.Amino, "types")
+	assert.NotZero(t, vars.Goroutines)
+
+	rec = get("/debug/pprof/goroutine?debug=2", "Bearer secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine")
+}
+
 func TestNodeDelayedStart(t *testing.T) {
 	config := cfg.ResetTestRoot("node_delayed_start_test")
 	defer os.RemoveAll(config.RootDir)
@@ -240,6 +275,7 @@ func TestCreateProposalBlock(t *testing.T) {
 		proxyApp.Mempool(),
 		state.LastBlockHeight,
 		state.ConsensusParams.Block.MaxTxBytes,
+		types.MaxTxGas(state.ConsensusParams.Block),
 		mempl.WithPreCheck(sm.TxPreCheck(state)),
 	)
 	mempool.SetLogger(logger)
@@ -284,7 +320,7 @@ func TestNodeNewNodeCustomReactors(t *testing.T) {
 	n, err := NewNode(config,
 		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
 		nodeKey,
-		proxy.DefaultClientCreator(nil, config.ProxyApp, config.ABCI, config.DBDir()),
+		proxy.DefaultClientCreator(nil, config.ProxyApp, config.ABCI, config.DBDir(), config.ABCISocketOptions()),
 		DefaultGenesisDocProviderFunc(config),
 		DefaultDBProvider,
 		log.TestingLogger(),
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"sync"
 	"time"
 
 	"github.com/gnolang/gno/pkgs/amino"
@@ -136,6 +137,12 @@ func (lss *FilePVLastSignState) Save() {
 type FilePV struct {
 	Key           FilePVKey
 	LastSignState FilePVLastSignState
+
+	// the key to rotate to, if any.
+	NextKey *FilePVNextKey
+
+	// guards Key, which is rotated while it is read, e.g. by rpc.
+	mtx sync.RWMutex
 }
 
 // GenFilePV generates a new validator with randomly generated private key
@@ -201,9 +208,15 @@ func loadFilePV(keyFilePath, stateFilePath string, loadState bool) *FilePV {
 
 	pvState.filePath = stateFilePath
 
+	nextKey, err := LoadFilePVNextKey(NextKeyFilePath(keyFilePath))
+	if err != nil {
+		osm.Exit(err.Error())
+	}
+
 	return &FilePV{
 		Key:           pvKey,
 		LastSignState: pvState,
+		NextKey:       nextKey,
 	}
 }
 
@@ -223,12 +236,16 @@ func LoadOrGenFilePV(keyFilePath, stateFilePath string) *FilePV {
 // GetAddress returns the address of the validator.
 // Implements PrivValidator.
 func (pv *FilePV) GetAddress() types.Address {
+	pv.mtx.RLock()
+	defer pv.mtx.RUnlock()
 	return pv.Key.Address
 }
 
 // GetPubKey returns the public key of the validator.
 // Implements PrivValidator.
 func (pv *FilePV) GetPubKey() crypto.PubKey {
+	pv.mtx.RLock()
+	defer pv.mtx.RUnlock()
 	return pv.Key.PubKey
 }
 
@@ -250,6 +267,22 @@ func (pv *FilePV) SignProposal(chainID string, proposal *types.Proposal) error {
 	return nil
 }
 
+// SignChallenge signs the challenge with the current key, which is not
+// subject to the double signing checks of votes and proposals.
+// Implements ChallengePrivValidator.
+func (pv *FilePV) SignChallenge(chainID string, challenge *types.Challenge) error {
+	pv.mtx.RLock()
+	defer pv.mtx.RUnlock()
+
+	challenge.ValidatorPubKey = pv.Key.PubKey
+	sig, err := pv.Key.PrivKey.Sign(challenge.SignBytes(chainID))
+	if err != nil {
+		return fmt.Errorf("error signing challenge: %v", err)
+	}
+	challenge.ValidatorSignature = sig
+	return nil
+}
+
 // Save persists the FilePV to disk.
 func (pv *FilePV) Save() {
 	pv.Key.Save()
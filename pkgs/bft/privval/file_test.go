@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -325,3 +326,37 @@ func newProposal(height int64, round int, blockID types.BlockID) *types.Proposal
 		Timestamp: tmtime.Now(),
 	}
 }
+
+func TestFilePVRotateKey(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "priv_validator_key.json")
+	stateFile := filepath.Join(dir, "priv_validator_state.json")
+	privVal := GenFilePV(keyFile, stateFile)
+	privVal.Save()
+	prevKey := privVal.Key
+
+	nextKey := GenFilePVNextKey(keyFile, 10)
+	nextKey.Save()
+	privVal = LoadFilePV(keyFile, stateFile)
+	require.NotNil(t, privVal.NextKey)
+	assert.Equal(t, nextKey.Address, privVal.NextKey.Address)
+
+	// the key is not rotated before its height, nor before the next key is
+	// in the validator set.
+	prevVals := types.NewValidatorSet([]*types.Validator{types.NewValidator(prevKey.PubKey, 1)})
+	nextVals := types.NewValidatorSet([]*types.Validator{types.NewValidator(nextKey.PubKey, 1)})
+	privVal.UpdateToHeight(9, nextVals)
+	privVal.UpdateToHeight(10, prevVals)
+	assert.Equal(t, prevKey.Address, privVal.GetAddress())
+
+	privVal.UpdateToHeight(10, nextVals)
+	assert.Equal(t, nextKey.Address, privVal.GetAddress())
+	assert.Nil(t, privVal.NextKey)
+
+	// the rotation is persisted, and the previous key backed up.
+	privVal = LoadFilePV(keyFile, stateFile)
+	assert.Equal(t, nextKey.Address, privVal.GetAddress())
+	assert.Nil(t, privVal.NextKey)
+	prevPrivVal := LoadFilePVEmptyState(PrevKeyFilePath(keyFile, prevKey.Address), stateFile)
+	assert.Equal(t, prevKey.Address, prevPrivVal.GetAddress())
+}
@@ -0,0 +1,128 @@
+package privval
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+	osm "github.com/gnolang/gno/pkgs/os"
+)
+
+// FilePVNextKey stores the key which a FilePV rotates to, from its height,
+// once it is in the validator set, i.e. once the application accepted the
+// rotation.  Until then, the FilePV keeps signing with its current key.
+type FilePVNextKey struct {
+	Height  int64          `json:"height"`
+	Address types.Address  `json:"address"`
+	PubKey  crypto.PubKey  `json:"pub_key"`
+	PrivKey crypto.PrivKey `json:"priv_key"`
+
+	filePath string
+}
+
+// NextKeyFilePath returns the path of the next key of the key file at
+// keyFilePath, e.g. "priv_validator_key.next.json".
+func NextKeyFilePath(keyFilePath string) string {
+	return strings.TrimSuffix(keyFilePath, ".json") + ".next.json"
+}
+
+// PrevKeyFilePath returns the path to which the key file at keyFilePath is
+// backed up when rotating from the key of address, e.g.
+// "priv_validator_key.<address>.json".
+func PrevKeyFilePath(keyFilePath string, address types.Address) string {
+	return strings.TrimSuffix(keyFilePath, ".json") + "." + address.String() + ".json"
+}
+
+// GenFilePVNextKey generates a next key to rotate to from height, and sets
+// its filePath next to keyFilePath, but does not call Save().
+func GenFilePVNextKey(keyFilePath string, height int64) *FilePVNextKey {
+	privKey := ed25519.GenPrivKey()
+
+	return &FilePVNextKey{
+		Height:   height,
+		Address:  privKey.PubKey().Address(),
+		PubKey:   privKey.PubKey(),
+		PrivKey:  privKey,
+		filePath: NextKeyFilePath(keyFilePath),
+	}
+}
+
+// LoadFilePVNextKey loads the next key at filePath, or returns nil if there
+// is none.
+func LoadFilePVNextKey(filePath string) (*FilePVNextKey, error) {
+	if !osm.FileExists(filePath) {
+		return nil, nil
+	}
+	jsonBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	nextKey := &FilePVNextKey{}
+	err = amino.UnmarshalJSON(jsonBytes, nextKey)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading PrivValidator next key from %v: %v", filePath, err)
+	}
+
+	// overwrite pubkey and address for convenience
+	nextKey.PubKey = nextKey.PrivKey.PubKey()
+	nextKey.Address = nextKey.PubKey.Address()
+	nextKey.filePath = filePath
+	return nextKey, nil
+}
+
+// Save persists the FilePVNextKey to its filePath.
+func (nextKey FilePVNextKey) Save() {
+	outFile := nextKey.filePath
+	if outFile == "" {
+		panic("cannot save PrivValidator next key: filePath not set")
+	}
+
+	jsonBytes, err := amino.MarshalJSONIndent(nextKey, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	err = osm.WriteFileAtomic(outFile, jsonBytes, 0o600)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// UpdateToHeight rotates to the next key, if any, when it is in the
+// validator set vals of height.  The previous key is backed up to
+// PrevKeyFilePath, and the next key file is removed.
+// Implements RotatingPrivValidator.
+func (pv *FilePV) UpdateToHeight(height int64, vals *types.ValidatorSet) {
+	// the next key is reloaded, as it is prepared while the node runs.
+	if nextKey, err := LoadFilePVNextKey(NextKeyFilePath(pv.Key.filePath)); err == nil {
+		pv.NextKey = nextKey
+	}
+	nextKey := pv.NextKey
+	if nextKey == nil || height < nextKey.Height || !vals.HasAddress(nextKey.Address) {
+		return
+	}
+	// NOTE: the key is already the next key if we crashed before
+	// removing its file.
+	if nextKey.Address != pv.Key.Address {
+		prevKey := pv.Key
+		prevKey.filePath = PrevKeyFilePath(pv.Key.filePath, pv.Key.Address)
+		prevKey.Save()
+		pv.mtx.Lock()
+		pv.Key = FilePVKey{
+			Address:  nextKey.Address,
+			PubKey:   nextKey.PubKey,
+			PrivKey:  nextKey.PrivKey,
+			filePath: pv.Key.filePath,
+		}
+		pv.mtx.Unlock()
+		pv.Key.Save()
+	}
+	if err := os.Remove(nextKey.filePath); err != nil && !os.IsNotExist(err) {
+		panic(err)
+	}
+	pv.NextKey = nil
+}
@@ -33,12 +33,63 @@ func (l *localClientCreator) NewABCIClient() (abcicli.Client, error) {
 	return abcicli.NewLocalClient(l.mtx, l.app), nil
 }
 
+//---------------------------------------------------------------
+// remote proxy opens new connections to an external app process
+
+type RemoteClientCreator struct {
+	addr        string
+	opts        abcicli.SocketOptions
+	mustConnect bool
+
+	mtx         sync.Mutex
+	onReconnect func() error
+}
+
+// NewRemoteClientCreator returns the creator of the socket clients of the
+// app at addr.  The OnReconnect of opts is ignored, see SetOnReconnect.
+func NewRemoteClientCreator(addr string, opts abcicli.SocketOptions, mustConnect bool) *RemoteClientCreator {
+	return &RemoteClientCreator{
+		addr:        addr,
+		opts:        opts,
+		mustConnect: mustConnect,
+	}
+}
+
+// SetOnReconnect sets the function called by the clients once they
+// reconnect to the app, e.g. to replay the blocks it lost.
+func (r *RemoteClientCreator) SetOnReconnect(onReconnect func() error) {
+	r.mtx.Lock()
+	r.onReconnect = onReconnect
+	r.mtx.Unlock()
+}
+
+// WithoutOnReconnect returns a creator of the clients of the same app,
+// without the function set by SetOnReconnect, e.g. for the connections
+// used by that function.
+func (r *RemoteClientCreator) WithoutOnReconnect() *RemoteClientCreator {
+	return NewRemoteClientCreator(r.addr, r.opts, r.mustConnect)
+}
+
+func (r *RemoteClientCreator) NewABCIClient() (abcicli.Client, error) {
+	opts := r.opts
+	opts.OnReconnect = func() error {
+		r.mtx.Lock()
+		onReconnect := r.onReconnect
+		r.mtx.Unlock()
+		if onReconnect == nil {
+			return nil
+		}
+		return onReconnect()
+	}
+	return abcicli.NewSocketClient(r.addr, r.mustConnect, opts), nil
+}
+
 //-----------------------------------------------------------------
 // DefaultClientCreator
 
 // Returns the local application, or constructs a new one via proxy.
 // This function is meant to work with config fields.
-func DefaultClientCreator(local abci.Application, proxy string, transport, dbDir string) ClientCreator {
+func DefaultClientCreator(local abci.Application, proxy string, transport, dbDir string, opts abcicli.SocketOptions) ClientCreator {
 	if local != nil {
 		// local applications (ignores other arguments)
 		return NewLocalClientCreator(local)
@@ -57,7 +108,10 @@ func DefaultClientCreator(local abci.Application, proxy string, transport, dbDir
 			return NewLocalClientCreator(abci.NewBaseApplication())
 		default:
 			// socket transport applications
-			panic("proxy scheme not yet supported: " + proxy)
+			if transport != "socket" {
+				panic("proxy transport not yet supported: " + transport)
+			}
+			return NewRemoteClientCreator(proxy, opts, true)
 		}
 	}
 }
@@ -0,0 +1,144 @@
+// Package replay re-executes the blocks of a chain against an application
+// with a fresh state, and compares the resulting app hashes with those
+// recorded by the chain, e.g. to diagnose consensus failures or to check
+// that changes of the application do not change its state.
+package replay
+
+import (
+	"bytes"
+	"fmt"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/bft/proxy"
+	sm "github.com/gnolang/gno/pkgs/bft/state"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/log"
+)
+
+// Result is the outcome of the replay of the block at Height.
+type Result struct {
+	Height          int64
+	AppHash         []byte // after the replay of the block.
+	ExpectedAppHash []byte // recorded by the chain.
+}
+
+// Mismatch returns true if the replayed app hash differs from the recorded
+// one.
+func (res Result) Mismatch() bool {
+	return !bytes.Equal(res.AppHash, res.ExpectedAppHash)
+}
+
+func (res Result) String() string {
+	if res.Mismatch() {
+		return fmt.Sprintf("height %d: app hash %X, expected %X", res.Height, res.AppHash, res.ExpectedAppHash)
+	}
+	return fmt.Sprintf("height %d: app hash %X", res.Height, res.AppHash)
+}
+
+// Replayer replays the blocks of a chain, read from its block store and
+// state DB, which are not modified.
+type Replayer struct {
+	genDoc     *types.GenesisDoc
+	blockStore sm.BlockStoreRPC
+	stateDB    dbm.DB
+	logger     log.Logger
+}
+
+func NewReplayer(genDoc *types.GenesisDoc, blockStore sm.BlockStoreRPC, stateDB dbm.DB, logger log.Logger) *Replayer {
+	return &Replayer{
+		genDoc:     genDoc,
+		blockStore: blockStore,
+		stateDB:    stateDB,
+		logger:     logger,
+	}
+}
+
+// Replay initializes app, which must have a fresh state, with the genesis
+// of the chain, and executes its blocks up to height to, or up to the last
+// block if to is 0.  The app hashes of the blocks from height from are
+// compared with those recorded, and the replay stops at the first mismatch,
+// leaving app in the state of the mismatching height.  The state of the
+// blocks before from is rebuilt, but not compared.
+func (rp *Replayer) Replay(app abci.Application, from, to int64) ([]Result, error) {
+	last := rp.blockStore.Height()
+	if to == 0 {
+		to = last
+	}
+	if from < 1 {
+		from = 1
+	}
+	if to > last {
+		return nil, errors.New("height %d is beyond the last block %d", to, last)
+	}
+	if from > to {
+		return nil, errors.New("invalid heights %d to %d", from, to)
+	}
+
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app))
+	if err := proxyApp.Start(); err != nil {
+		return nil, errors.Wrap(err, "starting proxy app")
+	}
+	defer proxyApp.Stop()
+
+	if err := rp.initChain(proxyApp.Consensus()); err != nil {
+		return nil, errors.Wrap(err, "initializing chain")
+	}
+	results := []Result{}
+	for height := int64(1); height <= to; height++ {
+		block := rp.blockStore.LoadBlock(height)
+		if block == nil {
+			return results, errors.New("block %d not found", height)
+		}
+		appHash, err := sm.ExecCommitBlock(proxyApp.Consensus(), block, rp.logger, rp.stateDB)
+		if err != nil {
+			return results, errors.Wrap(err, fmt.Sprintf("executing block %d", height))
+		}
+		if height < from {
+			continue
+		}
+		expected, err := rp.expectedAppHash(height)
+		if err != nil {
+			return results, err
+		}
+		res := Result{Height: height, AppHash: appHash, ExpectedAppHash: expected}
+		results = append(results, res)
+		if res.Mismatch() {
+			break
+		}
+	}
+	return results, nil
+}
+
+// Initializes the app with the genesis doc, as the handshake of a node
+// does.
+func (rp *Replayer) initChain(conn proxy.AppConnConsensus) error {
+	validators := make([]*types.Validator, len(rp.genDoc.Validators))
+	for i, val := range rp.genDoc.Validators {
+		validators[i] = types.NewValidator(val.PubKey, val.Power)
+	}
+	validatorSet := types.NewValidatorSet(validators)
+	csParams := rp.genDoc.ConsensusParams
+	_, err := conn.InitChainSync(abci.RequestInitChain{
+		Time:            rp.genDoc.GenesisTime,
+		ChainID:         rp.genDoc.ChainID,
+		ConsensusParams: &csParams,
+		Validators:      validatorSet.ABCIValidatorUpdates(),
+		AppState:        rp.genDoc.AppState,
+	})
+	return err
+}
+
+// Returns the app hash recorded after the block at height: that of the
+// header of the next block, or of the state for the last block.
+func (rp *Replayer) expectedAppHash(height int64) ([]byte, error) {
+	if next := rp.blockStore.LoadBlockMeta(height + 1); next != nil {
+		return next.Header.AppHash, nil
+	}
+	state := sm.LoadState(rp.stateDB)
+	if state.LastBlockHeight != height {
+		return nil, errors.New("no app hash recorded after height %d", height)
+	}
+	return state.AppHash, nil
+}
@@ -0,0 +1,316 @@
+// Package balancer fronts multiple RPC nodes: it health-checks and
+// load-balances across them, pins the websocket subscriptions of a client to
+// a node, and retries the idempotent queries on another node on failure.
+package balancer
+
+import (
+	"bytes"
+	"encoding/json"
+	"hash/fnv"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/service"
+)
+
+const (
+	// Max size of a request body, buffered to be retried.
+	maxBodyBytes = 1000000 // 1MB
+
+	websocketPath = "/websocket"
+)
+
+// backend is a node fronted by the balancer.
+type backend struct {
+	url     *url.URL
+	proxy   *httputil.ReverseProxy // of the websocket connections
+	healthy bool                   // protected by Balancer.mtx
+}
+
+// Balancer is the http.Handler and service load-balancing the RPC requests
+// across the healthy nodes. Nodes are assumed healthy until checked.
+type Balancer struct {
+	service.BaseService
+
+	backends       []*backend
+	healthInterval time.Duration
+	client         *http.Client
+
+	mtx  sync.Mutex
+	next int // round-robin index of the next backend
+}
+
+var _ http.Handler = (*Balancer)(nil)
+
+// NewBalancer returns the balancer of the nodes of remotes, e.g.
+// "tcp://127.0.0.1:26657" or "https://rpc.gno.land", health-checked every
+// healthInterval.
+func NewBalancer(remotes []string, healthInterval time.Duration) (*Balancer, error) {
+	if len(remotes) == 0 {
+		return nil, errors.New("no remote")
+	}
+	if healthInterval <= 0 {
+		return nil, errors.New("health interval must be positive")
+	}
+	b := &Balancer{
+		healthInterval: healthInterval,
+		client:         &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, remote := range remotes {
+		u, err := parseRemote(remote)
+		if err != nil {
+			return nil, err
+		}
+		b.backends = append(b.backends, &backend{
+			url:     u,
+			proxy:   httputil.NewSingleHostReverseProxy(u),
+			healthy: true,
+		})
+	}
+	b.BaseService = *service.NewBaseService(nil, "Balancer", b)
+	return b, nil
+}
+
+// Parses remote, defaulting to http for tcp or no scheme.
+func parseRemote(remote string) (*url.URL, error) {
+	if !strings.Contains(remote, "://") {
+		remote = "http://" + remote
+	}
+	u, err := url.Parse(remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid remote %q", remote)
+	}
+	switch u.Scheme {
+	case "tcp":
+		u.Scheme = "http"
+	case "http", "https":
+	default:
+		return nil, errors.New("invalid remote %q, must be tcp or http(s)", remote)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+// OnStart implements service.Service by starting the health checks.
+func (b *Balancer) OnStart() error {
+	go b.healthRoutine()
+	return nil
+}
+
+// Healthy returns the URLs of the nodes healthy at the last check.
+func (b *Balancer) Healthy() []string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	var urls []string
+	for _, be := range b.backends {
+		if be.healthy {
+			urls = append(urls, be.url.String())
+		}
+	}
+	return urls
+}
+
+func (b *Balancer) healthRoutine() {
+	ticker := time.NewTicker(b.healthInterval)
+	defer ticker.Stop()
+	for {
+		b.checkHealth()
+		select {
+		case <-ticker.C:
+		case <-b.Quit():
+			return
+		}
+	}
+}
+
+// Checks all the nodes concurrently, with their /health route.
+func (b *Balancer) checkHealth() {
+	var wg sync.WaitGroup
+	for _, be := range b.backends {
+		wg.Add(1)
+		go func(be *backend) {
+			defer wg.Done()
+			healthy := b.ping(be)
+			b.mtx.Lock()
+			if be.healthy != healthy {
+				b.Logger.Info("Node health changed", "node", be.url, "healthy", healthy)
+			}
+			be.healthy = healthy
+			b.mtx.Unlock()
+		}(be)
+	}
+	wg.Wait()
+}
+
+func (b *Balancer) ping(be *backend) bool {
+	client := http.Client{Timeout: b.healthInterval}
+	res, err := client.Get(be.url.String() + "/health")
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	return res.StatusCode == http.StatusOK
+}
+
+func (b *Balancer) setUnhealthy(be *backend) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if be.healthy {
+		b.Logger.Info("Node health changed", "node", be.url, "healthy", false)
+	}
+	be.healthy = false
+}
+
+// Returns the healthy backends, starting from the next one in round-robin
+// order, or all of them if none is healthy.
+func (b *Balancer) pick() []*backend {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	var healthy []*backend
+	for _, be := range b.backends {
+		if be.healthy {
+			healthy = append(healthy, be)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = b.backends
+	}
+	start := b.next % len(healthy)
+	b.next++
+	return append(healthy[start:len(healthy):len(healthy)], healthy[:start]...)
+}
+
+// Returns the backend the websocket connections of the client of r are
+// pinned to: the first healthy one from the hash of its IP.
+func (b *Balancer) pin(r *http.Request) *backend {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	h := fnv.New32a()
+	h.Write([]byte(host))
+	start := int(h.Sum32() % uint32(len(b.backends)))
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for i := range b.backends {
+		be := b.backends[(start+i)%len(b.backends)]
+		if be.healthy {
+			return be
+		}
+	}
+	return b.backends[start]
+}
+
+// ServeHTTP implements http.Handler.
+func (b *Balancer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == websocketPath {
+		// The subscriptions live on the node of the connection.
+		b.pin(r).proxy.ServeHTTP(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxBodyBytes {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	backends := b.pick()
+	if !isIdempotent(r, body) {
+		// e.g. a tx broadcast twice would fail the second time.
+		backends = backends[:1]
+	}
+	for i, be := range backends {
+		last := i == len(backends)-1
+		res, err := b.forward(be, r, body)
+		if err != nil {
+			b.Logger.Error("Failed to forward request", "node", be.url, "err", err)
+			b.setUnhealthy(be)
+			if last {
+				http.Error(w, "no node available", http.StatusBadGateway)
+			}
+			continue
+		}
+		if res.StatusCode >= http.StatusInternalServerError && !last {
+			res.Body.Close()
+			continue
+		}
+		copyResponse(w, res)
+		return
+	}
+}
+
+// Forwards r, with body, to be.
+func (b *Balancer) forward(be *backend, r *http.Request, body []byte) (*http.Response, error) {
+	u := *be.url
+	u.Path += r.URL.Path
+	u.RawQuery = r.URL.RawQuery
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"Content-Type", "Accept"} {
+		if value := r.Header.Get(key); value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+	return b.client.Do(req)
+}
+
+func copyResponse(w http.ResponseWriter, res *http.Response) {
+	defer res.Body.Close()
+	for key, values := range res.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(res.StatusCode)
+	io.Copy(w, res.Body)
+}
+
+// Returns true if r, with body, only queries the node, and can be retried on
+// another: everything but the broadcasts and the unsafe routes.
+func isIdempotent(r *http.Request, body []byte) bool {
+	if r.Method == http.MethodGet {
+		return isIdempotentMethod(strings.TrimPrefix(r.URL.Path, "/"))
+	}
+	// A JSON-RPC request, or batch of requests.
+	type request struct {
+		Method string `json:"method"`
+	}
+	var reqs []request
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			return false
+		}
+	} else {
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			return false
+		}
+		reqs = []request{req}
+	}
+	for _, req := range reqs {
+		if !isIdempotentMethod(req.Method) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdempotentMethod(method string) bool {
+	return !strings.HasPrefix(method, "broadcast_") && !strings.HasPrefix(method, "unsafe_")
+}
@@ -0,0 +1,173 @@
+package balancer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A fake node, counting its requests.
+type fakeNode struct {
+	mtx      sync.Mutex
+	name     string
+	status   int
+	requests int
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if r.URL.Path != "/health" {
+		n.requests++
+	}
+	w.WriteHeader(n.status)
+	w.Write([]byte(n.name))
+}
+
+func (n *fakeNode) set(status int) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.status = status
+}
+
+func (n *fakeNode) count() int {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.requests
+}
+
+func newFakeNodes(t *testing.T, names ...string) ([]*fakeNode, []string) {
+	t.Helper()
+	var nodes []*fakeNode
+	var remotes []string
+	for _, name := range names {
+		node := &fakeNode{name: name, status: http.StatusOK}
+		srv := httptest.NewServer(node)
+		t.Cleanup(srv.Close)
+		nodes = append(nodes, node)
+		remotes = append(remotes, strings.Replace(srv.URL, "http://", "tcp://", 1))
+	}
+	return nodes, remotes
+}
+
+func get(t *testing.T, h http.Handler, target string) (int, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	body, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	return w.Code, string(body)
+}
+
+func post(t *testing.T, h http.Handler, body string) (int, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	res, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	return w.Code, string(res)
+}
+
+func TestBalancerRoundRobin(t *testing.T) {
+	nodes, remotes := newFakeNodes(t, "a", "b")
+	b, err := NewBalancer(remotes, time.Hour)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		code, _ := get(t, b, "/status")
+		assert.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, 2, nodes[0].count())
+	assert.Equal(t, 2, nodes[1].count())
+}
+
+func TestBalancerHealthCheck(t *testing.T) {
+	nodes, remotes := newFakeNodes(t, "a", "b")
+	b, err := NewBalancer(remotes, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, b.Start())
+	defer b.Stop()
+
+	nodes[0].set(http.StatusServiceUnavailable)
+	require.Eventually(t, func() bool { return len(b.Healthy()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	for i := 0; i < 4; i++ {
+		_, body := get(t, b, "/status")
+		assert.Equal(t, "b", body)
+	}
+
+	nodes[0].set(http.StatusOK)
+	require.Eventually(t, func() bool { return len(b.Healthy()) == 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBalancerRetry(t *testing.T) {
+	nodes, remotes := newFakeNodes(t, "a", "b")
+	b, err := NewBalancer(remotes, time.Hour)
+	require.NoError(t, err)
+	nodes[0].set(http.StatusInternalServerError)
+
+	// queries are retried on the next node.
+	for i := 0; i < 2; i++ {
+		code, body := post(t, b, `{"jsonrpc":"2.0","id":"","method":"abci_query","params":{}}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "b", body)
+	}
+
+	// broadcasts aren't.
+	codes := map[int]int{}
+	for i := 0; i < 2; i++ {
+		code, _ := post(t, b, `[{"method":"status"},{"method":"broadcast_tx_sync"}]`)
+		codes[code]++
+	}
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusInternalServerError: 1}, codes)
+}
+
+func TestBalancerPin(t *testing.T) {
+	_, remotes := newFakeNodes(t, "a", "b", "c")
+	b, err := NewBalancer(remotes, time.Hour)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, websocketPath, nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	pinned := b.pin(r)
+	r.RemoteAddr = "10.0.0.1:5678"
+	assert.Equal(t, pinned, b.pin(r))
+
+	// moves off an unhealthy node.
+	b.setUnhealthy(pinned)
+	assert.NotEqual(t, pinned, b.pin(r))
+}
+
+func TestIsIdempotent(t *testing.T) {
+	cases := []struct {
+		method, target, body string
+		idempotent           bool
+	}{
+		{http.MethodGet, "/status", "", true},
+		{http.MethodGet, "/broadcast_tx_commit?tx=0x00", "", false},
+		{http.MethodPost, "/", `{"method":"block"}`, true},
+		{http.MethodPost, "/", `{"method":"unsafe_flush_mempool"}`, false},
+		{http.MethodPost, "/", `[{"method":"block"},{"method":"status"}]`, true},
+		{http.MethodPost, "/", `[{"method":"block"},{"method":"broadcast_tx_async"}]`, false},
+		{http.MethodPost, "/", `invalid`, false},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(tc.method, tc.target, nil)
+		assert.Equal(t, tc.idempotent, isIdempotent(r, []byte(tc.body)), tc.target+tc.body)
+	}
+}
+
+func TestNewBalancerErrors(t *testing.T) {
+	_, err := NewBalancer(nil, time.Second)
+	assert.Error(t, err)
+	_, err = NewBalancer([]string{"unix:///tmp/sock"}, time.Second)
+	assert.Error(t, err)
+	_, err = NewBalancer([]string{"127.0.0.1:26657"}, 0)
+	assert.Error(t, err)
+}
@@ -1,8 +1,13 @@
 package client
 
 import (
+	"bytes"
 	"time"
 
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/errors"
 )
 
@@ -47,3 +52,155 @@ func WaitForHeight(c StatusClient, h int64, waiter Waiter) error {
 	}
 	return nil
 }
+
+// TxWaitClient is the part of Client used by WaitForTx.
+type TxWaitClient interface {
+	StatusClient
+	BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error)
+	Block(height *int64) (*ctypes.ResultBlock, error)
+	BlockResults(height *int64) (*ctypes.ResultBlockResults, error)
+}
+
+// WaitForTxOptions are the options of WaitForTx.  Zero values are replaced
+// by those of DefaultWaitForTxOptions.
+type WaitForTxOptions struct {
+	// Timeout is the maximum time to wait for the tx and its confirmations.
+	Timeout time.Duration
+	// Confirmations is the number of blocks to wait for after the block
+	// which includes the tx, e.g. 0 to return as soon as it is included.
+	Confirmations int64
+	// PollInterval is the initial interval between polls of the status of
+	// the node, which is doubled up to MaxPollInterval while no new block is
+	// committed.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+}
+
+var DefaultWaitForTxOptions = WaitForTxOptions{
+	Timeout:         time.Minute,
+	Confirmations:   0,
+	PollInterval:    250 * time.Millisecond,
+	MaxPollInterval: 2 * time.Second,
+}
+
+// WaitForTx broadcasts tx, and waits until it is included in a block and
+// followed by opts.Confirmations blocks, or until opts.Timeout.  Unlike
+// BroadcastTxCommit, which holds a request and a subscription of the node
+// until the tx is committed, and times out on busy chains, tx is broadcast
+// with BroadcastTxSync, which only waits for CheckTx, and the new blocks are
+// then polled for it.
+//
+// As with BroadcastTxCommit, a failed CheckTx or DeliverTx is not an error,
+// but is returned in the result.
+//
+// NOTE: as events cannot be subscribed to from the RPC APIs, and txs are not
+// indexed by hash, blocks are searched for the tx bytes.
+func WaitForTx(c TxWaitClient, tx types.Tx, opts WaitForTxOptions) (*ctypes.ResultBroadcastTxCommit, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultWaitForTxOptions.Timeout
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = DefaultWaitForTxOptions.PollInterval
+	}
+	if opts.MaxPollInterval == 0 {
+		opts.MaxPollInterval = DefaultWaitForTxOptions.MaxPollInterval
+	}
+	deadline := time.Now().Add(opts.Timeout)
+
+	// blocks up to the current height cannot include tx.
+	status, err := c.Status()
+	if err != nil {
+		return nil, err
+	}
+	searched := status.SyncInfo.LatestBlockHeight
+
+	bres, err := c.BroadcastTxSync(tx)
+	if err != nil {
+		return nil, errors.Wrap(err, "broadcasting tx")
+	}
+	res := &ctypes.ResultBroadcastTxCommit{
+		CheckTx: abci.ResponseCheckTx{
+			ResponseBase: abci.ResponseBase{
+				Error: bres.Error,
+				Data:  bres.Data,
+				Log:   bres.Log,
+			},
+			TxHash: bres.TxHash,
+		},
+		Hash:   bres.Hash,
+		TxHash: bres.TxHash,
+	}
+	if bres.Error != nil {
+		return res, nil
+	}
+
+	interval := opts.PollInterval
+	for {
+		status, err := c.Status()
+		if err != nil {
+			return nil, err
+		}
+		latest := status.SyncInfo.LatestBlockHeight
+		if latest > searched {
+			interval = opts.PollInterval
+		}
+		for res.Height == 0 && searched < latest {
+			searched++
+			if err := findTx(c, searched, tx, res); err != nil {
+				return nil, err
+			}
+		}
+		if res.Height > 0 && latest >= res.Height+opts.Confirmations {
+			return res, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			if res.Height > 0 {
+				return nil, errors.New("timed out after %s waiting for %d confirmations of tx %X included at height %d",
+					opts.Timeout, opts.Confirmations, res.Hash, res.Height)
+			}
+			return nil, errors.New("timed out after %s waiting for tx %X", opts.Timeout, res.Hash)
+		}
+		time.Sleep(interval)
+		if interval *= 2; interval > opts.MaxPollInterval {
+			interval = opts.MaxPollInterval
+		}
+	}
+}
+
+// Sets the height and DeliverTx result of res if the block at height
+// includes tx.
+func findTx(c TxWaitClient, height int64, tx types.Tx, res *ctypes.ResultBroadcastTxCommit) error {
+	bres, err := c.Block(&height)
+	if err != nil {
+		return err
+	}
+	for i, btx := range bres.Block.Txs {
+		if !bytes.Equal(btx, tx) {
+			continue
+		}
+		rres, err := c.BlockResults(&height)
+		if err != nil {
+			return err
+		}
+		if i >= len(rres.Results.DeliverTxs) {
+			return errors.New("missing result of tx %d of block %d", i, height)
+		}
+		res.DeliverTx = rres.Results.DeliverTxs[i]
+		res.Height = height
+		return nil
+	}
+	return nil
+}
+
+// VerifyABCIQuery returns an error unless res, the result of the query of
+// data at path on chainID, is signed by the node with ID nodeID, e.g. a
+// trusted RPC provider with sign_queries set, so that responses tampered
+// with by intermediate proxies are detected.  Unlike proofs, this does not
+// protect against the node itself.
+func VerifyABCIQuery(chainID string, nodeID crypto.ID, path string, data []byte, res *ctypes.ResultABCIQuery) error {
+	if res.Signature == nil {
+		return errors.New("query response is not signed")
+	}
+	return res.Signature.Verify(chainID, nodeID, path, data, res.Response)
+}
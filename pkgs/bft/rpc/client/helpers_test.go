@@ -4,13 +4,17 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
 	"github.com/gnolang/gno/pkgs/bft/rpc/client"
 	"github.com/gnolang/gno/pkgs/bft/rpc/client/mock"
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/pkgs/bft/state"
+	"github.com/gnolang/gno/pkgs/bft/types"
 	"github.com/gnolang/gno/pkgs/random"
 )
 
@@ -83,3 +87,86 @@ func MakeTxKV() ([]byte, []byte, []byte) {
 	v := []byte(random.RandStr(8))
 	return k, v, append(k, append([]byte("="), v...)...)
 }
+
+// txChain is a chain which commits a block on each status query, including
+// the txs broadcast since the last one.
+type txChain struct {
+	pending types.Txs
+	blocks  []types.Txs
+}
+
+func (c *txChain) Status() (*ctypes.ResultStatus, error) {
+	c.blocks = append(c.blocks, c.pending)
+	c.pending = nil
+	return &ctypes.ResultStatus{
+		SyncInfo: ctypes.SyncInfo{LatestBlockHeight: int64(len(c.blocks))},
+	}, nil
+}
+
+func (c *txChain) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if string(tx) == "bad" {
+		return &ctypes.ResultBroadcastTx{Error: abci.StringError("bad tx"), Hash: tx.Hash()}, nil
+	}
+	c.pending = append(c.pending, tx)
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+func (c *txChain) Block(height *int64) (*ctypes.ResultBlock, error) {
+	block := &types.Block{}
+	block.Txs = c.blocks[*height-1]
+	return &ctypes.ResultBlock{Block: block}, nil
+}
+
+func (c *txChain) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+	res := &sm.ABCIResponses{}
+	for _, tx := range c.blocks[*height-1] {
+		res.DeliverTxs = append(res.DeliverTxs, abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{Data: tx},
+		})
+	}
+	return &ctypes.ResultBlockResults{Height: *height, Results: res}, nil
+}
+
+func TestWaitForTx(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+	opts := client.WaitForTxOptions{PollInterval: time.Millisecond}
+
+	c := &txChain{}
+	c.pending = types.Txs{types.Tx("other")}
+	res, err := client.WaitForTx(c, types.Tx("good"), opts)
+	require.Nil(err, "%+v", err)
+	assert.Nil(res.CheckTx.Error)
+	assert.EqualValues(2, res.Height)
+	assert.Equal([]byte("good"), res.DeliverTx.Data)
+	assert.Equal(types.Tx("good").Hash(), res.Hash)
+
+	// waits for confirmations.
+	c = &txChain{}
+	opts.Confirmations = 3
+	res, err = client.WaitForTx(c, types.Tx("good"), opts)
+	require.Nil(err, "%+v", err)
+	assert.EqualValues(2, res.Height)
+	assert.Len(c.blocks, 5)
+
+	// a failed CheckTx is returned in the result.
+	res, err = client.WaitForTx(c, types.Tx("bad"), opts)
+	require.Nil(err, "%+v", err)
+	assert.NotNil(res.CheckTx.Error)
+	assert.EqualValues(0, res.Height)
+
+	// times out if the tx is never included.
+	c = &txChain{}
+	opts = client.WaitForTxOptions{Timeout: 20 * time.Millisecond, PollInterval: time.Millisecond}
+	_, err = client.WaitForTx(&droppingChain{c}, types.Tx("good"), opts)
+	require.NotNil(err)
+	assert.Contains(err.Error(), "timed out")
+}
+
+// droppingChain drops broadcast txs.
+type droppingChain struct {
+	*txChain
+}
+
+func (c *droppingChain) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
@@ -2,11 +2,14 @@ package client
 
 import (
 	"net/http"
+	"time"
 
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	rpcclient "github.com/gnolang/gno/pkgs/bft/rpc/lib/client"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/p2p"
 )
 
 /*
@@ -216,6 +219,48 @@ func (c *baseRPCClient) NetInfo() (*ctypes.ResultNetInfo, error) {
 	return result, nil
 }
 
+func (c *baseRPCClient) NetTopology() (*ctypes.ResultNetTopology, error) {
+	result := new(ctypes.ResultNetTopology)
+	_, err := c.caller.Call("net_topology", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "NetTopology")
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) AddrBook() (*ctypes.ResultAddrBook, error) {
+	return c.callAddrBook("addr_book", map[string]interface{}{})
+}
+
+func (c *baseRPCClient) AddrBookAdd(addr string) (*ctypes.ResultAddrBook, error) {
+	return c.callAddrBook("addr_book_add", map[string]interface{}{"addr": addr})
+}
+
+func (c *baseRPCClient) AddrBookRemove(id string) (*ctypes.ResultAddrBook, error) {
+	return c.callAddrBook("addr_book_remove", map[string]interface{}{"id": id})
+}
+
+func (c *baseRPCClient) AddrBookBan(target string) (*ctypes.ResultAddrBook, error) {
+	return c.callAddrBook("addr_book_ban", map[string]interface{}{"target": target})
+}
+
+func (c *baseRPCClient) AddrBookUnban(target string) (*ctypes.ResultAddrBook, error) {
+	return c.callAddrBook("addr_book_unban", map[string]interface{}{"target": target})
+}
+
+func (c *baseRPCClient) AddrBookImport(book p2p.AddrBookJSON) (*ctypes.ResultAddrBook, error) {
+	return c.callAddrBook("addr_book_import", map[string]interface{}{"book": book})
+}
+
+func (c *baseRPCClient) callAddrBook(method string, params map[string]interface{}) (*ctypes.ResultAddrBook, error) {
+	result := new(ctypes.ResultAddrBook)
+	_, err := c.caller.Call(method, params, result)
+	if err != nil {
+		return nil, errors.Wrap(err, method)
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
 	result := new(ctypes.ResultDumpConsensusState)
 	_, err := c.caller.Call("dump_consensus_state", map[string]interface{}{}, result)
@@ -272,6 +317,15 @@ func (c *baseRPCClient) Block(height *int64) (*ctypes.ResultBlock, error) {
 	return result, nil
 }
 
+func (c *baseRPCClient) BlockByTime(t time.Time) (*ctypes.ResultBlock, error) {
+	result := new(ctypes.ResultBlock)
+	_, err := c.caller.Call("block_by_time", map[string]interface{}{"time": t}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "BlockByTime")
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
 	result := new(ctypes.ResultBlockResults)
 	_, err := c.caller.Call("block_results", map[string]interface{}{"height": height}, result)
@@ -290,6 +344,15 @@ func (c *baseRPCClient) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return result, nil
 }
 
+func (c *baseRPCClient) CompactCommit(height *int64) (*ctypes.ResultCompactCommit, error) {
+	result := new(ctypes.ResultCompactCommit)
+	_, err := c.caller.Call("compact_commit", map[string]interface{}{"height": height}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "CompactCommit")
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	result := new(ctypes.ResultTx)
 	params := map[string]interface{}{
@@ -303,6 +366,19 @@ func (c *baseRPCClient) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	return result, nil
 }
 
+func (c *baseRPCClient) SignChallenge(nonce []byte, validator bool) (*ctypes.ResultSignChallenge, error) {
+	result := new(ctypes.ResultSignChallenge)
+	params := map[string]interface{}{
+		"nonce":     nonce,
+		"validator": validator,
+	}
+	_, err := c.caller.Call("sign_challenge", params, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "SignChallenge")
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
 	result := new(ctypes.ResultTxSearch)
 	params := map[string]interface{}{
@@ -318,9 +394,12 @@ func (c *baseRPCClient) TxSearch(query string, prove bool, page, perPage int) (*
 	return result, nil
 }
 
-func (c *baseRPCClient) Validators(height *int64) (*ctypes.ResultValidators, error) {
+func (c *baseRPCClient) Validators(height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
 	result := new(ctypes.ResultValidators)
-	params := map[string]interface{}{}
+	params := map[string]interface{}{
+		"page":     page,
+		"per_page": perPage,
+	}
 	if height != nil {
 		params["height"] = height
 	}
@@ -330,3 +409,16 @@ func (c *baseRPCClient) Validators(height *int64) (*ctypes.ResultValidators, err
 	}
 	return result, nil
 }
+
+func (c *baseRPCClient) ValidatorByAddress(address crypto.Address, height *int64) (*ctypes.ResultValidator, error) {
+	result := new(ctypes.ResultValidator)
+	params := map[string]interface{}{"address": address}
+	if height != nil {
+		params["height"] = height
+	}
+	_, err := c.caller.Call("validator_by_address", params, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "ValidatorByAddress")
+	}
+	return result, nil
+}
@@ -21,13 +21,18 @@ implementation.
 */
 
 import (
+	"time"
+
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 )
 
 // Client wraps most important rpc calls a client would make.
 //
-// NOTE: Events cannot be subscribed to from the RPC APIs. For events
+// NOTE: Events cannot be subscribed to from the RPC APIs, except the signed
+// headers of new blocks and the txs matching a query over a websocket (see
+// core.SubscribeHeaders and core.SubscribeTxs). For other events
 // subscriptions and filters and queries, an external API must be used that
 // first synchronously consumes the events from the node's synchronous event
 // switch, or reads logged events from the filesystem.
@@ -63,9 +68,14 @@ type ABCIClient interface {
 // and prove anything about the chain.
 type SignClient interface {
 	Block(height *int64) (*ctypes.ResultBlock, error)
+	BlockByTime(t time.Time) (*ctypes.ResultBlock, error)
 	BlockResults(height *int64) (*ctypes.ResultBlockResults, error)
 	Commit(height *int64) (*ctypes.ResultCommit, error)
-	Validators(height *int64) (*ctypes.ResultValidators, error)
+	CompactCommit(height *int64) (*ctypes.ResultCompactCommit, error)
+	Validators(height *int64, page, perPage int) (*ctypes.ResultValidators, error)
+	ValidatorByAddress(address crypto.Address, height *int64) (*ctypes.ResultValidator, error)
+	Tx(hash []byte, prove bool) (*ctypes.ResultTx, error)
+	SignChallenge(nonce []byte, validator bool) (*ctypes.ResultSignChallenge, error)
 }
 
 // HistoryClient provides access to data from genesis to now in large chunks.
@@ -83,6 +93,7 @@ type StatusClient interface {
 // usually.
 type NetworkClient interface {
 	NetInfo() (*ctypes.ResultNetInfo, error)
+	NetTopology() (*ctypes.ResultNetTopology, error)
 	DumpConsensusState() (*ctypes.ResultDumpConsensusState, error)
 	ConsensusState() (*ctypes.ResultConsensusState, error)
 	Health() (*ctypes.ResultHealth, error)
@@ -1,12 +1,16 @@
 package client
 
 import (
+	"time"
+
 	nm "github.com/gnolang/gno/pkgs/bft/node"
 	"github.com/gnolang/gno/pkgs/bft/rpc/core"
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/log"
+	"github.com/gnolang/gno/pkgs/p2p"
 )
 
 /*
@@ -89,6 +93,10 @@ func (c *Local) NetInfo() (*ctypes.ResultNetInfo, error) {
 	return core.NetInfo(c.ctx)
 }
 
+func (c *Local) NetTopology() (*ctypes.ResultNetTopology, error) {
+	return core.NetTopology(c.ctx)
+}
+
 func (c *Local) DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
 	return core.DumpConsensusState(c.ctx)
 }
@@ -109,6 +117,30 @@ func (c *Local) DialPeers(peers []string, persistent bool) (*ctypes.ResultDialPe
 	return core.UnsafeDialPeers(c.ctx, peers, persistent)
 }
 
+func (c *Local) AddrBook() (*ctypes.ResultAddrBook, error) {
+	return core.UnsafeAddrBook(c.ctx)
+}
+
+func (c *Local) AddrBookAdd(addr string) (*ctypes.ResultAddrBook, error) {
+	return core.UnsafeAddrBookAdd(c.ctx, addr)
+}
+
+func (c *Local) AddrBookRemove(id string) (*ctypes.ResultAddrBook, error) {
+	return core.UnsafeAddrBookRemove(c.ctx, id)
+}
+
+func (c *Local) AddrBookBan(target string) (*ctypes.ResultAddrBook, error) {
+	return core.UnsafeAddrBookBan(c.ctx, target)
+}
+
+func (c *Local) AddrBookUnban(target string) (*ctypes.ResultAddrBook, error) {
+	return core.UnsafeAddrBookUnban(c.ctx, target)
+}
+
+func (c *Local) AddrBookImport(book p2p.AddrBookJSON) (*ctypes.ResultAddrBook, error) {
+	return core.UnsafeAddrBookImport(c.ctx, book)
+}
+
 func (c *Local) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
 	return core.BlockchainInfo(c.ctx, minHeight, maxHeight)
 }
@@ -121,6 +153,10 @@ func (c *Local) Block(height *int64) (*ctypes.ResultBlock, error) {
 	return core.Block(c.ctx, height)
 }
 
+func (c *Local) BlockByTime(t time.Time) (*ctypes.ResultBlock, error) {
+	return core.BlockByTime(c.ctx, t)
+}
+
 func (c *Local) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
 	return core.BlockResults(c.ctx, height)
 }
@@ -129,15 +165,27 @@ func (c *Local) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return core.Commit(c.ctx, height)
 }
 
-func (c *Local) Validators(height *int64) (*ctypes.ResultValidators, error) {
-	return core.Validators(c.ctx, height)
+func (c *Local) CompactCommit(height *int64) (*ctypes.ResultCompactCommit, error) {
+	return core.CompactCommit(c.ctx, height)
+}
+
+func (c *Local) Validators(height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	return core.Validators(c.ctx, height, page, perPage)
+}
+
+func (c *Local) ValidatorByAddress(address crypto.Address, height *int64) (*ctypes.ResultValidator, error) {
+	return core.ValidatorByAddress(c.ctx, address, height)
 }
 
-/*
 func (c *Local) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	return core.Tx(c.ctx, hash, prove)
 }
 
+func (c *Local) SignChallenge(nonce []byte, validator bool) (*ctypes.ResultSignChallenge, error) {
+	return core.SignChallenge(c.ctx, nonce, validator)
+}
+
+/*
 func (c *Local) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
 	return core.TxSearch(c.ctx, query, prove, page, perPage)
 }
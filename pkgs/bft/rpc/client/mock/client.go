@@ -22,6 +22,7 @@ import (
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/service"
 )
 
@@ -108,6 +109,10 @@ func (c Client) NetInfo() (*ctypes.ResultNetInfo, error) {
 	return core.NetInfo(&rpctypes.Context{})
 }
 
+func (c Client) NetTopology() (*ctypes.ResultNetTopology, error) {
+	return core.NetTopology(&rpctypes.Context{})
+}
+
 func (c Client) ConsensusState() (*ctypes.ResultConsensusState, error) {
 	return core.ConsensusState(&rpctypes.Context{})
 }
@@ -144,6 +149,14 @@ func (c Client) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return core.Commit(&rpctypes.Context{}, height)
 }
 
-func (c Client) Validators(height *int64) (*ctypes.ResultValidators, error) {
-	return core.Validators(&rpctypes.Context{}, height)
+func (c Client) CompactCommit(height *int64) (*ctypes.ResultCompactCommit, error) {
+	return core.CompactCommit(&rpctypes.Context{}, height)
+}
+
+func (c Client) Validators(height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	return core.Validators(&rpctypes.Context{}, height, page, perPage)
+}
+
+func (c Client) ValidatorByAddress(address crypto.Address, height *int64) (*ctypes.ResultValidator, error) {
+	return core.ValidatorByAddress(&rpctypes.Context{}, address, height)
 }
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// RegistryEnv is the environment variable of the path of a registry file,
+// whose chains are added to, or replace, those of the default registry.
+const RegistryEnv = "GNO_CHAINS"
+
+//go:embed chains.json
+var defaultRegistryJSON []byte
+
+// ChainInfo is the entry of a chain in a registry.
+type ChainInfo struct {
+	ChainID      string   `json:"chain_id"`
+	RPC          []string `json:"rpc"`           // endpoints, in order of preference
+	Bech32Prefix string   `json:"bech32_prefix"` // of the addresses
+	Denoms       []string `json:"denoms"`        // the first one pays the fees
+	GenesisHash  string   `json:"genesis_hash"`  // hex, see GenesisHash; optional
+	GenesisURL   string   `json:"genesis_url"`   // of the genesis file, else served by the RPC; optional
+	Seeds        []string `json:"seeds"`         // <id>@<host>:<port> of the seed nodes; optional
+	Profile      string   `json:"profile"`       // of the config of the nodes, e.g. wan; optional
+}
+
+// ValidateBasic performs basic validation.
+func (ci ChainInfo) ValidateBasic() error {
+	if ci.ChainID == "" {
+		return errors.New("missing chain_id")
+	}
+	if len(ci.RPC) == 0 {
+		return errors.New("chain %q: missing rpc endpoints", ci.ChainID)
+	}
+	if ci.GenesisHash != "" {
+		if bz, err := hex.DecodeString(ci.GenesisHash); err != nil || len(bz) != sha256.Size {
+			return errors.New("chain %q: invalid genesis_hash", ci.ChainID)
+		}
+	}
+	return nil
+}
+
+// VerifyGenesis returns an error if the genesis of the node of c doesn't
+// have the genesis hash of the chain, if any.
+func (ci ChainInfo) VerifyGenesis(c Client) error {
+	if ci.GenesisHash == "" {
+		return nil
+	}
+	res, err := c.Genesis()
+	if err != nil {
+		return errors.Wrap(err, "fetching genesis")
+	}
+	return ci.VerifyGenesisDoc(res.Genesis)
+}
+
+// VerifyGenesisDoc returns an error if doc is not of the chain, or doesn't
+// have the genesis hash of the chain, if any.
+func (ci ChainInfo) VerifyGenesisDoc(doc *types.GenesisDoc) error {
+	if doc.ChainID != ci.ChainID {
+		return errors.New("chain %q: genesis of chain %q", ci.ChainID, doc.ChainID)
+	}
+	if ci.GenesisHash == "" {
+		return nil
+	}
+	hash, err := GenesisHash(doc)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash) != ci.GenesisHash {
+		return errors.New("chain %q: genesis hash mismatch, got %X", ci.ChainID, hash)
+	}
+	return nil
+}
+
+// GenesisHash returns the hash of the genesis doc in the registries: the
+// SHA-256 of its amino JSON.
+func GenesisHash(doc *types.GenesisDoc) ([]byte, error) {
+	bz, err := amino.MarshalJSON(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling genesis")
+	}
+	sum := sha256.Sum256(bz)
+	return sum[:], nil
+}
+
+// Registry is a chains.json-style registry of the chains, by chain ID.
+type Registry struct {
+	Chains []ChainInfo `json:"chains"`
+}
+
+// ParseRegistry parses and validates the JSON registry of bz.
+func ParseRegistry(bz []byte) (*Registry, error) {
+	reg := new(Registry)
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(reg); err != nil {
+		return nil, errors.Wrap(err, "parsing registry")
+	}
+	seen := make(map[string]bool)
+	for _, ci := range reg.Chains {
+		if err := ci.ValidateBasic(); err != nil {
+			return nil, err
+		}
+		if seen[ci.ChainID] {
+			return nil, errors.New("duplicate chain %q", ci.ChainID)
+		}
+		seen[ci.ChainID] = true
+	}
+	return reg, nil
+}
+
+// LoadRegistry reads the registry file of path.
+func LoadRegistry(path string) (*Registry, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRegistry(bz)
+}
+
+// DefaultRegistry returns the registry of the known chains, merged with the
+// registry file of $GNO_CHAINS, if set.
+func DefaultRegistry() (*Registry, error) {
+	reg, err := ParseRegistry(defaultRegistryJSON)
+	if err != nil {
+		panic(err) // embedded.
+	}
+	if path := os.Getenv(RegistryEnv); path != "" {
+		other, err := LoadRegistry(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading $%s", RegistryEnv)
+		}
+		reg.Merge(other)
+	}
+	return reg, nil
+}
+
+// Merge adds the chains of other to reg, replacing those of the same chain
+// ID.
+func (reg *Registry) Merge(other *Registry) {
+	for _, ci := range other.Chains {
+		replaced := false
+		for i := range reg.Chains {
+			if reg.Chains[i].ChainID == ci.ChainID {
+				reg.Chains[i] = ci
+				replaced = true
+			}
+		}
+		if !replaced {
+			reg.Chains = append(reg.Chains, ci)
+		}
+	}
+}
+
+// Chain returns the chain of chainID.
+func (reg *Registry) Chain(chainID string) (ChainInfo, bool) {
+	for _, ci := range reg.Chains {
+		if ci.ChainID == chainID {
+			return ci, true
+		}
+	}
+	return ChainInfo{}, false
+}
+
+// Dial returns the client of the first RPC endpoint of the chain of chainID
+// which is reachable and on that chain.
+func (reg *Registry) Dial(chainID string) (*HTTP, error) {
+	ci, ok := reg.Chain(chainID)
+	if !ok {
+		return nil, errors.New("unknown chain %q", chainID)
+	}
+	var errs []string
+	for _, remote := range ci.RPC {
+		c := NewHTTP(remote, "/websocket")
+		status, err := c.Status()
+		if err != nil {
+			errs = append(errs, remote+": "+err.Error())
+			continue
+		}
+		if network := status.NodeInfo.Network; network != chainID {
+			errs = append(errs, fmt.Sprintf("%s: on chain %q", remote, network))
+			continue
+		}
+		return c, nil
+	}
+	return nil, errors.New("no endpoint of chain %q available: %s", chainID, strings.Join(errs, "; "))
+}
+
+// Dial returns the client of the chain of chainID, e.g. "dev", from the
+// default registry. See Registry.Dial.
+func Dial(chainID string) (*HTTP, error) {
+	reg, err := DefaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return reg.Dial(chainID)
+}
@@ -0,0 +1,87 @@
+package client_test
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	rpctest "github.com/gnolang/gno/pkgs/bft/rpc/test"
+)
+
+func TestParseRegistry(t *testing.T) {
+	reg, err := client.ParseRegistry([]byte(`{"chains": [
+		{"chain_id": "a", "rpc": ["tcp://a:26657"], "bech32_prefix": "g", "denoms": ["ugnot"]}
+	]}`))
+	require.NoError(t, err)
+	ci, ok := reg.Chain("a")
+	require.True(t, ok)
+	assert.Equal(t, []string{"tcp://a:26657"}, ci.RPC)
+	assert.Equal(t, "g", ci.Bech32Prefix)
+	_, ok = reg.Chain("b")
+	assert.False(t, ok)
+
+	for _, bz := range []string{
+		`{"chains": [{"rpc": ["tcp://a:26657"]}]}`,
+		`{"chains": [{"chain_id": "a"}]}`,
+		`{"chains": [{"chain_id": "a", "rpc": ["tcp://a:26657"], "genesis_hash": "00"}]}`,
+		`{"chains": [{"chain_id": "a", "rpc": ["tcp://a:26657"]}, {"chain_id": "a", "rpc": ["tcp://a:26657"]}]}`,
+		`{"chains": [{"chain_id": "a", "rpc": ["tcp://a:26657"], "unknown": 1}]}`,
+	} {
+		_, err := client.ParseRegistry([]byte(bz))
+		assert.Error(t, err, bz)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	reg, err := client.DefaultRegistry()
+	require.NoError(t, err)
+	ci, ok := reg.Chain("dev")
+	require.True(t, ok)
+	assert.Equal(t, []string{"tcp://127.0.0.1:26657"}, ci.RPC)
+
+	// merged with $GNO_CHAINS.
+	path := filepath.Join(t.TempDir(), "chains.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"chains": [
+		{"chain_id": "dev", "rpc": ["tcp://127.0.0.1:36657"]},
+		{"chain_id": "other", "rpc": ["tcp://other:26657"]}
+	]}`), 0o600))
+	t.Setenv(client.RegistryEnv, path)
+	reg, err = client.DefaultRegistry()
+	require.NoError(t, err)
+	ci, _ = reg.Chain("dev")
+	assert.Equal(t, []string{"tcp://127.0.0.1:36657"}, ci.RPC)
+	_, ok = reg.Chain("other")
+	assert.True(t, ok)
+}
+
+func TestRegistryDial(t *testing.T) {
+	rpcAddr := rpctest.GetConfig().RPC.ListenAddress
+	res, err := getHTTPClient().Genesis()
+	require.NoError(t, err)
+	chainID := res.Genesis.ChainID
+	hash, err := client.GenesisHash(res.Genesis)
+	require.NoError(t, err)
+
+	reg := &client.Registry{Chains: []client.ChainInfo{
+		{ChainID: chainID, RPC: []string{"tcp://127.0.0.1:1", rpcAddr}, GenesisHash: hex.EncodeToString(hash)},
+		{ChainID: "other", RPC: []string{rpcAddr}},
+	}}
+	c, err := reg.Dial(chainID)
+	require.NoError(t, err)
+	ci, _ := reg.Chain(chainID)
+	assert.NoError(t, ci.VerifyGenesis(c))
+
+	ci.GenesisHash = hex.EncodeToString(make([]byte, 32))
+	assert.Error(t, ci.VerifyGenesis(c))
+
+	// the node isn't on chain "other".
+	_, err = reg.Dial("other")
+	assert.Error(t, err)
+	_, err = reg.Dial("unknown")
+	assert.Error(t, err)
+}
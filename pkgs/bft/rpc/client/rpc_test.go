@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,6 +15,7 @@ import (
 	rpcclient "github.com/gnolang/gno/pkgs/bft/rpc/lib/client"
 	rpctest "github.com/gnolang/gno/pkgs/bft/rpc/test"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/maths"
 )
 
@@ -143,14 +145,34 @@ func TestGenesisAndValidators(t *testing.T) {
 		gval := gen.Genesis.Validators[0]
 
 		// get the current validators
-		vals, err := c.Validators(nil)
+		vals, err := c.Validators(nil, 0, 0)
 		require.Nil(t, err, "%d: %+v", i, err)
 		require.Equal(t, 1, len(vals.Validators))
+		assert.Equal(t, 1, vals.Count)
+		assert.Equal(t, 1, vals.Total)
 		val := vals.Validators[0]
 
 		// make sure the current set is also the genesis set
 		assert.Equal(t, gval.Power, val.VotingPower)
 		assert.Equal(t, gval.PubKey, val.PubKey)
+
+		// the validators of a past height, by page
+		h := int64(1)
+		vals, err = c.Validators(&h, 1, 10)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, h, vals.BlockHeight)
+		assert.Equal(t, 1, len(vals.Validators))
+		_, err = c.Validators(&h, 2, 10)
+		assert.NotNil(t, err, "%d", i)
+
+		// the validator, by address
+		res, err := c.ValidatorByAddress(val.Address, &h)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, h, res.BlockHeight)
+		assert.Equal(t, 0, res.Index)
+		assert.Equal(t, val.PubKey, res.Validator.PubKey)
+		_, err = c.ValidatorByAddress(crypto.AddressFromPreimage([]byte("nobody")), nil)
+		assert.NotNil(t, err, "%d", i)
 	}
 }
 
@@ -207,13 +229,11 @@ func TestAppCalls(t *testing.T) {
 			assert.EqualValues(v, qres.Value)
 		}
 
-		/*
-			// make sure we can lookup the tx with proof
-			ptx, err := c.Tx(bres.Hash, true)
-			require.Nil(err, "%d: %+v", i, err)
-			assert.EqualValues(txh, ptx.Height)
-			assert.EqualValues(tx, ptx.Tx)
-		*/
+		// make sure we can lookup the tx with proof
+		ptx, err := c.Tx(bres.Hash, true)
+		require.Nil(err, "%d: %+v", i, err)
+		assert.EqualValues(txh, ptx.Height)
+		assert.EqualValues(tx, ptx.Tx)
 
 		// and we can even check the block is added
 		block, err := c.Block(&apph)
@@ -266,6 +286,64 @@ func TestAppCalls(t *testing.T) {
 	}
 }
 
+func TestBlockByTime(t *testing.T) {
+	for i, c := range GetClients() {
+		require.Nil(t, client.WaitForHeight(c, 2, nil), "%d", i)
+		h := int64(2)
+		block, err := c.Block(&h)
+		require.Nil(t, err, "%d: %+v", i, err)
+
+		res, err := c.BlockByTime(block.BlockMeta.Header.Time)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, h, res.BlockMeta.Header.Height)
+		res, err = c.BlockByTime(block.BlockMeta.Header.Time.Add(-time.Nanosecond))
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, h, res.BlockMeta.Header.Height)
+		_, err = c.BlockByTime(time.Now().Add(time.Hour))
+		assert.NotNil(t, err, "%d", i)
+	}
+}
+
+func TestCompactCommit(t *testing.T) {
+	for i, c := range GetClients() {
+		require.Nil(t, client.WaitForHeight(c, 2, nil), "%d", i)
+		h := int64(2)
+		commit, err := c.Commit(&h)
+		require.Nil(t, err, "%d: %+v", i, err)
+
+		res, err := c.CompactCommit(&h)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, commit.CanonicalCommit, res.CanonicalCommit)
+		sh, err := res.SignedHeader()
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, commit.Header.Hash(), sh.Header.Hash())
+		assert.Equal(t, commit.Commit.Hash(), sh.Commit.Hash())
+	}
+}
+
+func TestSignChallenge(t *testing.T) {
+	for i, c := range GetClients() {
+		status, err := c.Status()
+		require.Nil(t, err, "%d: %+v", i, err)
+		nonce := []byte("nonce")
+
+		res, err := c.SignChallenge(nonce, false)
+		require.Nil(t, err, "%d: %+v", i, err)
+		require.Nil(t, res.Challenge.Verify(res.ChainID, nonce), "%d", i)
+		assert.Equal(t, status.NodeInfo.ID(), res.Challenge.NodeID)
+		assert.Equal(t, status.NodeInfo.Network, res.ChainID)
+		assert.Nil(t, res.Challenge.ValidatorPubKey)
+
+		res, err = c.SignChallenge(nonce, true)
+		require.Nil(t, err, "%d: %+v", i, err)
+		require.Nil(t, res.Challenge.Verify(res.ChainID, nonce), "%d", i)
+		assert.Equal(t, status.ValidatorInfo.PubKey, res.Challenge.ValidatorPubKey)
+
+		_, err = c.SignChallenge(nil, false)
+		assert.NotNil(t, err, "%d", i)
+	}
+}
+
 func TestBroadcastTxSync(t *testing.T) {
 	require := require.New(t)
 
@@ -344,7 +422,6 @@ func TestNumUnconfirmedTxs(t *testing.T) {
 	mempool.Flush()
 }
 
-/*
 func TestTx(t *testing.T) {
 	// first we broadcast a tx
 	c := getHTTPClient()
@@ -393,12 +470,16 @@ func TestTx(t *testing.T) {
 				proof := ptx.Proof
 				if tc.prove && assert.EqualValues(t, tx, proof.Data) {
 					assert.NoError(t, proof.Proof.Verify(proof.RootHash, txHash))
+					commit, err := c.Commit(&txHeight)
+					require.Nil(t, err, "%+v", err)
+					assert.NoError(t, proof.Validate(commit.Header.DataHash))
 				}
 			}
 		}
 	}
 }
 
+/*
 func TestTxSearch(t *testing.T) {
 	// first we broadcast a tx
 	c := getHTTPClient()
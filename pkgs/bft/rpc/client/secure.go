@@ -0,0 +1,120 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+
+	rpcclient "github.com/gnolang/gno/pkgs/bft/rpc/lib/client"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// SecureOptions are the options of NewSecureHTTP.  The zero value verifies
+// nothing more than NewHTTP.
+type SecureOptions struct {
+	// SHA-256 hashes of the DER SubjectPublicKeyInfo of certificates (see
+	// SPKIHash), one of which must be in the chain of the server, which
+	// must then be https.  They are checked in addition to the usual
+	// verification of the chain.
+	PinnedSPKIHashes [][]byte
+
+	// The chain the node must be on, if set.
+	ChainID string
+
+	// The hash of the genesis of the node, if set (see GenesisHash).
+	GenesisHash []byte
+
+	// The TLS configuration to start from, e.g. with the root CAs of
+	// self-signed certificates; optional.
+	TLSConfig *tls.Config
+}
+
+// NewSecureHTTP is like NewHTTP, with the certificate of the server pinned
+// and the chain of the node verified on connect according to opts, so that
+// clients pointed at the wrong or a malicious network fail fast.
+func NewSecureHTTP(remote, wsEndpoint string, opts SecureOptions) (*HTTP, error) {
+	var tlsConfig *tls.Config
+	if len(opts.PinnedSPKIHashes) > 0 {
+		if !strings.HasPrefix(remote, "https://") {
+			return nil, errors.New("certificate pinning requires an https remote, got %q", remote)
+		}
+		if opts.TLSConfig != nil {
+			tlsConfig = opts.TLSConfig.Clone()
+		} else {
+			tlsConfig = new(tls.Config)
+		}
+		pins := opts.PinnedSPKIHashes
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			return verifyPinnedSPKI(pins, rawCerts, verifiedChains)
+		}
+	} else {
+		tlsConfig = opts.TLSConfig
+	}
+	c := NewHTTPWithClient(remote, wsEndpoint, rpcclient.TLSHTTPClient(remote, tlsConfig))
+
+	if opts.ChainID != "" {
+		status, err := c.Status()
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching status")
+		}
+		if network := status.NodeInfo.Network; network != opts.ChainID {
+			return nil, errors.New("node is on chain %q, not %q", network, opts.ChainID)
+		}
+	}
+	if opts.GenesisHash != nil {
+		res, err := c.Genesis()
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching genesis")
+		}
+		hash, err := GenesisHash(res.Genesis)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(hash, opts.GenesisHash) {
+			return nil, errors.New("genesis hash mismatch, got %X", hash)
+		}
+	}
+	return c, nil
+}
+
+// SPKIHash returns the SHA-256 of the DER SubjectPublicKeyInfo of cert, as
+// pinned by SecureOptions.  Unlike the hash of the certificate, it doesn't
+// change when the certificate is renewed with the same key.
+func SPKIHash(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return sum[:]
+}
+
+// Returns an error unless a certificate of the verified chains, or of the
+// raw certificates sent by the server if not verified, has a pinned SPKI.
+func verifyPinnedSPKI(pins [][]byte, rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	var certs []*x509.Certificate
+	for _, chain := range verifiedChains {
+		certs = append(certs, chain...)
+	}
+	if len(verifiedChains) == 0 {
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return errors.Wrap(err, "parsing certificate")
+			}
+			certs = append(certs, cert)
+		}
+	}
+	for _, cert := range certs {
+		hash := SPKIHash(cert)
+		for _, pin := range pins {
+			if bytes.Equal(hash, pin) {
+				return nil
+			}
+		}
+	}
+	var hashes []string
+	for _, cert := range certs {
+		hashes = append(hashes, hex.EncodeToString(SPKIHash(cert)))
+	}
+	return errors.New("no pinned certificate in the chain of the server: %s", strings.Join(hashes, ", "))
+}
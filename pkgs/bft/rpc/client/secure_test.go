@@ -0,0 +1,60 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	rpctest "github.com/gnolang/gno/pkgs/bft/rpc/test"
+)
+
+func TestNewSecureHTTP(t *testing.T) {
+	rpcAddr := rpctest.GetConfig().RPC.ListenAddress
+	res, err := getHTTPClient().Genesis()
+	require.NoError(t, err)
+	chainID := res.Genesis.ChainID
+	hash, err := client.GenesisHash(res.Genesis)
+	require.NoError(t, err)
+
+	_, err = client.NewSecureHTTP(rpcAddr, "/websocket", client.SecureOptions{ChainID: chainID, GenesisHash: hash})
+	require.NoError(t, err)
+	_, err = client.NewSecureHTTP(rpcAddr, "/websocket", client.SecureOptions{ChainID: "other"})
+	assert.Error(t, err)
+	_, err = client.NewSecureHTTP(rpcAddr, "/websocket", client.SecureOptions{GenesisHash: make([]byte, 32)})
+	assert.Error(t, err)
+
+	// pinning requires https.
+	_, err = client.NewSecureHTTP(rpcAddr, "/websocket", client.SecureOptions{PinnedSPKIHashes: [][]byte{hash}})
+	assert.Error(t, err)
+
+	// the node behind a TLS proxy.
+	target, err := url.Parse(strings.Replace(rpcAddr, "tcp", "http", 1))
+	require.NoError(t, err)
+	ts := httptest.NewTLSServer(httputil.NewSingleHostReverseProxy(target))
+	defer ts.Close()
+	tlsConfig := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	pin := client.SPKIHash(ts.Certificate())
+
+	c, err := client.NewSecureHTTP(ts.URL, "/websocket", client.SecureOptions{
+		PinnedSPKIHashes: [][]byte{make([]byte, 32), pin},
+		ChainID:          chainID,
+		TLSConfig:        tlsConfig,
+	})
+	require.NoError(t, err)
+	_, err = c.Health()
+	assert.NoError(t, err)
+
+	_, err = client.NewSecureHTTP(ts.URL, "/websocket", client.SecureOptions{
+		PinnedSPKIHashes: [][]byte{make([]byte, 32)},
+		ChainID:          chainID,
+		TLSConfig:        tlsConfig,
+	})
+	assert.Error(t, err)
+}
@@ -68,6 +68,10 @@ type RPCConfig struct {
 	// Maximum size of request header, in bytes
 	MaxHeaderBytes int `toml:"max_header_bytes"`
 
+	// If true, the responses to /abci_query are signed with the node key,
+	// for clients which trust the node to detect responses tampered with.
+	SignQueries bool `toml:"sign_queries"`
+
 	// The path to a file containing certificate that is used to create the HTTPS server.
 	// Migth be either absolute path or path related to tendermint's config directory.
 	//
@@ -103,6 +107,8 @@ func DefaultRPCConfig() *RPCConfig {
 		MaxBodyBytes:   int64(1000000), // 1MB
 		MaxHeaderBytes: 1 << 20,        // same as the net/http default
 
+		SignQueries: false,
+
 		TLSCertFile: "",
 		TLSKeyFile:  "",
 	}
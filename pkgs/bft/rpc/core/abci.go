@@ -4,6 +4,7 @@ import (
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
 )
 
 // Query the application for some information.
@@ -51,6 +52,10 @@ import (
 // | data      | []byte | false   | true     | Data                                           |
 // | height    | int64  | 0       | false    | Height (0 means latest)                        |
 // | prove     | bool   | false   | false    | Includes proof if true                         |
+//
+// If sign_queries is set in the RPC config, the result has the signature of
+// the response by the node key (see types.QuerySignature), which clients
+// trusting the node verify with client.VerifyABCIQuery.
 func ABCIQuery(ctx *rpctypes.Context, path string, data []byte, height int64, prove bool) (*ctypes.ResultABCIQuery, error) {
 	resQuery, err := proxyAppQuery.QuerySync(abci.RequestQuery{
 		Path:   path,
@@ -62,7 +67,20 @@ func ABCIQuery(ctx *rpctypes.Context, path string, data []byte, height int64, pr
 		return nil, err
 	}
 	logger.Info("ABCIQuery", "path", path, "data", data, "result", resQuery)
-	return &ctypes.ResultABCIQuery{Response: resQuery}, nil
+	res := &ctypes.ResultABCIQuery{Response: resQuery}
+	if config.SignQueries {
+		signBytes := types.QuerySignBytes(genDoc.ChainID, path, data, resQuery)
+		sig, err := nodeKey.PrivKey.Sign(signBytes)
+		if err != nil {
+			return nil, err
+		}
+		res.Signature = &types.QuerySignature{
+			NodeID:     nodeKey.ID(),
+			NodePubKey: nodeKey.PubKey(),
+			Signature:  sig,
+		}
+	}
+	return res, nil
 }
 
 // Get some info about the application.
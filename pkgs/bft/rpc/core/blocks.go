@@ -2,6 +2,8 @@ package core
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
@@ -239,6 +241,42 @@ func Block(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlock, error)
 	return &ctypes.ResultBlock{BlockMeta: blockMeta, Block: block}, nil
 }
 
+// Get the first block whose time is at or after the given time, found with a
+// binary search over the headers of the block store, as the times of the
+// blocks increase with their heights.
+//
+// ```shell
+// curl 'localhost:26657/block_by_time?time="2017-05-29T15:05:53Z"'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// info, err := client.BlockByTime(time.Date(2017, 5, 29, 15, 5, 53, 0, time.UTC))
+// ```
+//
+// > The above command returns JSON structured like that of /block, e.g. for
+// > the block of height 10 above.
+func BlockByTime(ctx *rpctypes.Context, t time.Time) (*ctypes.ResultBlock, error) {
+	storeHeight := blockStore.Height()
+	// the number of blocks before t, i.e. the height of the last one.
+	n := sort.Search(int(storeHeight), func(i int) bool {
+		return !blockStore.LoadBlockMeta(int64(i) + 1).Header.Time.Before(t)
+	})
+	height := int64(n) + 1
+	if height > storeHeight {
+		return nil, fmt.Errorf("No block at or after %v", t)
+	}
+
+	blockMeta := blockStore.LoadBlockMeta(height)
+	block := blockStore.LoadBlock(height)
+	return &ctypes.ResultBlock{BlockMeta: blockMeta, Block: block}, nil
+}
+
 // Get block commit at a given height.
 // If no height is provided, it will fetch the commit for the latest block.
 //
@@ -335,6 +373,40 @@ func Commit(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultCommit, erro
 	return ctypes.NewResultCommit(&header, commit, true), nil
 }
 
+// Get the block commit at a given height, as a CompactCommit, which is much
+// smaller than the commit for large validator sets.
+// If no height is provided, it will fetch the commit for the latest block.
+//
+// ```shell
+// curl 'localhost:26657/compact_commit?height=11'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// info, err := client.CompactCommit(11)
+// commit := info.SignedHeader()
+// ```
+func CompactCommit(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultCompactCommit, error) {
+	res, err := Commit(ctx, heightPtr)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := types.NewCompactCommit(res.Commit)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultCompactCommit{
+		Header:          res.Header,
+		Commit:          cc,
+		CanonicalCommit: res.CanonicalCommit,
+	}, nil
+}
+
 // BlockResults gets ABCIResults at a given height.
 // If no height is provided, it will fetch results for the latest block.
 //
@@ -3,8 +3,14 @@ package core
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
+	"github.com/gnolang/gno/pkgs/bft/store"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	dbm "github.com/gnolang/gno/pkgs/db"
 )
 
 func TestBlockchainInfo(t *testing.T) {
@@ -53,3 +59,43 @@ func TestBlockchainInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockByTime(t *testing.T) {
+	bs := store.NewBlockStore(dbm.NewMemDB())
+	blockStore = bs
+	genesis := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := BlockByTime(&rpctypes.Context{}, genesis)
+	require.Error(t, err, "no blocks")
+
+	// blocks every 10 seconds.
+	for height := int64(1); height <= 100; height++ {
+		block := types.MakeBlock(height, nil, new(types.Commit))
+		block.Time = genesis.Add(time.Duration(height-1) * 10 * time.Second)
+		bs.SaveBlock(block, block.MakePartSet(types.BlockPartSizeBytes), new(types.Commit))
+	}
+
+	cases := []struct {
+		time    time.Time
+		height  int64
+		wantErr bool
+	}{
+		{genesis.Add(-time.Hour), 1, false},
+		{genesis, 1, false},
+		{genesis.Add(time.Nanosecond), 2, false},
+		{genesis.Add(10 * time.Second), 2, false},
+		{genesis.Add(495 * time.Second), 51, false},
+		{genesis.Add(990 * time.Second), 100, false},
+		{genesis.Add(991 * time.Second), 0, true},
+	}
+	for i, c := range cases {
+		caseString := fmt.Sprintf("test %d failed", i)
+		res, err := BlockByTime(&rpctypes.Context{}, c.time)
+		if c.wantErr {
+			require.Error(t, err, caseString)
+		} else {
+			require.NoError(t, err, caseString)
+			require.Equal(t, c.height, res.Block.Height, caseString)
+			require.Equal(t, c.height, res.BlockMeta.Header.Height, caseString)
+		}
+	}
+}
@@ -6,15 +6,22 @@ import (
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/pkgs/bft/state"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/maths"
 )
 
 // Get the validator set at the given block height.
 // If no height is provided, it will fetch the current validator set.
+// The validator sets of all the past heights are served from the state store.
 // Note the validators are sorted by their address - this is the canonical
 // order for the validators in the set as used in computing their Merkle root.
 //
+// The validators are paginated: page starts at 1, and per_page defaults to
+// 30, up to 100.
+//
 // ```shell
-// curl 'localhost:26657/validators'
+// curl 'localhost:26657/validators?height=10&page=1&per_page=30'
 // ```
 //
 // ```go
@@ -24,7 +31,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// state, err := client.Validators()
+// state, err := client.Validators(nil, 1, 30)
 // ```
 //
 // The above command returns JSON structured like this:
@@ -44,31 +51,111 @@ import (
 // 				"address": "E89A51D60F68385E09E716D353373B11F8FACD62"
 // 			}
 // 		],
-// 		"block_height": "5241"
+// 		"block_height": "5241",
+// 		"count": "1",
+// 		"total": "1"
 // 	},
 // 	"id": "",
 // 	"jsonrpc": "2.0"
 // }
 // ```
-func Validators(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultValidators, error) {
-	// The latest validator that we know is the
-	// NextValidator of the last block.
-	height := consensusState.GetState().LastBlockHeight + 1
-	height, err := getHeight(height, heightPtr)
+func Validators(ctx *rpctypes.Context, heightPtr *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	height, validators, err := loadValidators(heightPtr)
 	if err != nil {
 		return nil, err
 	}
 
-	validators, err := sm.LoadValidators(stateDB, height)
+	totalCount := len(validators.Validators)
+	perPage = validatePerPage(perPage)
+	page, err = validatePage(page, perPage, totalCount)
 	if err != nil {
 		return nil, err
 	}
+	skipCount := validateSkipCount(page, perPage)
+	pageVals := validators.Validators[skipCount : skipCount+maths.MinInt(perPage, totalCount-skipCount)]
+
 	return &ctypes.ResultValidators{
 		BlockHeight: height,
-		Validators:  validators.Validators,
+		Validators:  pageVals,
+		Count:       len(pageVals),
+		Total:       totalCount,
+	}, nil
+}
+
+// Get a validator of the validator set at the given block height, by
+// address.
+// If no height is provided, it will look it up in the current validator set.
+//
+// ```shell
+// curl 'localhost:26657/validator_by_address?address="g1..."&height=10'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// val, err := client.ValidatorByAddress(address, nil)
+// ```
+//
+// The above command returns JSON structured like this:
+//
+// ```json
+// {
+// 	"error": "",
+// 	"result": {
+// 		"block_height": "5241",
+// 		"index": "0",
+// 		"validator": {
+// 			"proposer_priority": "0",
+// 			"voting_power": "10",
+// 			"pub_key": {
+// 				"data": "68DFDA7E50F82946E7E8546BED37944A422CD1B831E70DF66BA3B8430593944D",
+// 				"type": "ed25519"
+// 			},
+// 			"address": "g1azd9r4s0dqu9ufw8x6el8zl8nlm8wjngxcz8yc"
+// 		}
+// 	},
+// 	"id": "",
+// 	"jsonrpc": "2.0"
+// }
+// ```
+func ValidatorByAddress(ctx *rpctypes.Context, address crypto.Address, heightPtr *int64) (*ctypes.ResultValidator, error) {
+	height, validators, err := loadValidators(heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	index, val := validators.GetByAddress(address)
+	if val == nil {
+		return nil, errors.New("no validator %v at height %d", address, height)
+	}
+	return &ctypes.ResultValidator{
+		BlockHeight: height,
+		Index:       index,
+		Validator:   val,
 	}, nil
 }
 
+// Loads the validator set of the height of heightPtr, or the current one.
+func loadValidators(heightPtr *int64) (int64, *types.ValidatorSet, error) {
+	// The latest validator that we know is the
+	// NextValidator of the last block.
+	height := consensusState.GetState().LastBlockHeight + 1
+	height, err := getHeight(height, heightPtr)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	validators, err := sm.LoadValidators(stateDB, height)
+	if err != nil {
+		return 0, nil, err
+	}
+	return height, validators, nil
+}
+
 // DumpConsensusState dumps consensus state.
 // UNSTABLE
 //
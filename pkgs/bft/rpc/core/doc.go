@@ -60,6 +60,8 @@ JSONRPC requests can be POST'd to the root RPC endpoint via HTTP (e.g. `http://l
 
 JSONRPC requests can be made via websocket. The websocket endpoint is at `/websocket`, e.g. `localhost:26657/websocket`.
 
+The signed headers of new blocks can be subscribed to over the websocket with `subscribe_headers`, e.g. by light clients.
+
 
 ## More Examples
 
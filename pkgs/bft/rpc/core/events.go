@@ -0,0 +1,180 @@
+package core
+
+import (
+	"fmt"
+
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/bft/webhook"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/events"
+	"github.com/gnolang/gno/pkgs/random"
+)
+
+// Number of signed headers buffered for a subscriber, after which the headers
+// are dropped until it catches up.
+const subscribeHeadersCapacity = 100
+
+// Number of txs buffered for a subscriber, after which the txs are dropped
+// until it catches up.
+const subscribeTxsCapacity = 100
+
+// Subscribe to the signed headers of the blocks committed from now on, over
+// a websocket.  Each signed header is written to the websocket as a response
+// to the request, with the same id: its result is the event
+// EventNewSignedHeader, with the header of the block and the commit of the
+// precommits seen by the node, which light clients verify with the
+// validator set of the validators hash of the header.
+//
+// The headers of a subscriber too slow to read them are dropped; the
+// commits of the missing heights can be fetched with /commit.  The
+// subscription ends when the websocket is closed.
+//
+// ```shell
+// wscat -c ws://localhost:26657/websocket
+// > { "jsonrpc": "2.0", "method": "subscribe_headers", "params": [], "id": "headers" }
+// ```
+//
+// > The above command returns JSON structured like this, then a response for
+// > each header:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "headers",
+//   "result": {}
+// }
+// {
+//   "jsonrpc": "2.0",
+//   "id": "headers",
+//   "result": {
+//     "event": {
+//       "@type": "/tm.EventNewSignedHeader",
+//       "header": {
+//         "chain_id": "dev",
+//         "height": "12",
+//         ...
+//         "validators_hash": "9365FC80F234C967BD233F5A3E2AB2F1E4B0E5AA",
+//         ...
+//       },
+//       "commit": {
+//         "block_id": { ... },
+//         "precommits": [ ... ]
+//       }
+//     }
+//   }
+// }
+// ```
+func SubscribeHeaders(ctx *rpctypes.Context) (*ctypes.ResultSubscribe, error) {
+	if ctx.WSConn == nil || ctx.JSONReq == nil {
+		return nil, errors.New("subscribe_headers is only available over a websocket")
+	}
+	listenerID := fmt.Sprintf("subscribeHeaders#%v", random.RandStr(6))
+	ch := make(chan events.Event, subscribeHeadersCapacity)
+	sub := events.SubscribeToEventOn(evsw, listenerID, types.EventNewSignedHeader{}, ch)
+	id := ctx.JSONReq.ID
+	wsCtx := ctx.WSConn.Context()
+	go func() {
+		defer evsw.RemoveListener(listenerID)
+		for {
+			select {
+			case event, ok := <-sub:
+				if !ok {
+					return
+				}
+				res := &ctypes.ResultEvent{Event: event.(types.EventNewSignedHeader)}
+				ctx.WSConn.WriteRPCResponse(rpctypes.NewRPCSuccessResponse(id, res))
+			case <-wsCtx.Done():
+				return
+			}
+		}
+	}()
+	return &ctypes.ResultSubscribe{}, nil
+}
+
+// Subscribe to the txs committed from now on which match query, over a
+// websocket.  The query is that of the webhooks (see webhook.TxQuery), e.g.
+// "event.type=vm.RealmStorageEvent AND event.pkg_path=gno.land/r/demo/boards"
+// to watch the changes to the objects of a realm.  Each matching tx is
+// written to the websocket as a response to the request, with the same id:
+// its result has the hash, height and index of the tx, and its events
+// which satisfy the conditions of the query on events, or all of them if
+// there are none.
+//
+// The txs of a subscriber too slow to read them are dropped.  The
+// subscription ends when the websocket is closed.
+//
+// ```shell
+// wscat -c ws://localhost:26657/websocket
+// > { "jsonrpc": "2.0", "method": "subscribe_txs", "params": ["event.type=vm.RealmStorageEvent AND event.pkg_path=gno.land/r/demo/boards"], "id": "boards" }
+// ```
+//
+// > The above command returns JSON structured like this, then a response for
+// > each matching tx:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "boards",
+//   "result": {}
+// }
+// {
+//   "jsonrpc": "2.0",
+//   "id": "boards",
+//   "result": {
+//     "hash": "4mG5rMb9bH3UMkGwsDuJHRMUBu2cJu8hkRMYPk+7DzU=",
+//     "height": "12",
+//     "index": 0,
+//     "events": [
+//       {
+//         "@type": "/vm.RealmStorageEvent",
+//         "pkg_path": "gno.land/r/demo/boards",
+//         "created": ["a8ada09dee16d791fd406d629fe29bb0ed084a30:21"],
+//         "updated": ["a8ada09dee16d791fd406d629fe29bb0ed084a30:2"],
+//         "deleted": []
+//       }
+//     ]
+//   }
+// }
+// ```
+func SubscribeTxs(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, error) {
+	if ctx.WSConn == nil || ctx.JSONReq == nil {
+		return nil, errors.New("subscribe_txs is only available over a websocket")
+	}
+	q, err := webhook.ParseTxQuery(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid query")
+	}
+	listenerID := fmt.Sprintf("subscribeTxs#%v", random.RandStr(6))
+	ch := make(chan events.Event, subscribeTxsCapacity)
+	sub := events.SubscribeToEventOn(evsw, listenerID, types.EventTx{}, ch)
+	id := ctx.JSONReq.ID
+	wsCtx := ctx.WSConn.Context()
+	go func() {
+		defer evsw.RemoveListener(listenerID)
+		for {
+			select {
+			case event, ok := <-sub:
+				if !ok {
+					return
+				}
+				txRes := event.(types.EventTx).Result
+				evs, ok := q.MatchedEvents(txRes)
+				if !ok {
+					continue
+				}
+				res := &ctypes.ResultTxEvent{
+					Hash:   txRes.Tx.Hash(),
+					Height: txRes.Height,
+					Index:  txRes.Index,
+					Events: evs,
+				}
+				ctx.WSConn.WriteRPCResponse(rpctypes.NewRPCSuccessResponse(id, res))
+			case <-wsCtx.Done():
+				return
+			}
+		}
+	}()
+	return &ctypes.ResultSubscribe{}, nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/events"
+)
+
+// testWSConn is a websocket connection whose responses are sent to resps.
+type testWSConn struct {
+	resps  chan rpctypes.RPCResponse
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newTestWSConn() *testWSConn {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &testWSConn{
+		resps:  make(chan rpctypes.RPCResponse, 10),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (wsc *testWSConn) GetRemoteAddr() string                      { return "test" }
+func (wsc *testWSConn) WriteRPCResponse(resp rpctypes.RPCResponse) { wsc.resps <- resp }
+func (wsc *testWSConn) TryWriteRPCResponse(resp rpctypes.RPCResponse) bool {
+	wsc.resps <- resp
+	return true
+}
+func (wsc *testWSConn) Context() context.Context { return wsc.ctx }
+
+func TestSubscribeHeaders(t *testing.T) {
+	evsw = events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+
+	// the subscription is only available over a websocket.
+	_, err := SubscribeHeaders(&rpctypes.Context{})
+	require.Error(t, err)
+
+	wsc := newTestWSConn()
+	req := &rpctypes.RPCRequest{ID: rpctypes.JSONRPCStringID("headers")}
+	_, err = SubscribeHeaders(&rpctypes.Context{JSONReq: req, WSConn: wsc})
+	require.NoError(t, err)
+
+	// other events are not written.
+	evsw.FireEvent(types.EventNewBlockHeader{})
+	header := types.Header{ChainID: "test", Height: 12, ValidatorsHash: []byte("valhash")}
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	evsw.FireEvent(types.EventNewSignedHeader{Header: header, Commit: commit})
+
+	select {
+	case resp := <-wsc.resps:
+		require.Equal(t, req.ID, resp.ID)
+		var res ctypes.ResultEvent
+		require.NoError(t, amino.UnmarshalJSON(resp.Result, &res))
+		event := res.Event.(types.EventNewSignedHeader)
+		require.Equal(t, int64(12), event.Header.Height)
+		require.Equal(t, []byte("valhash"), []byte(event.Header.ValidatorsHash))
+		require.Equal(t, []byte("blockhash"), []byte(event.Commit.BlockID.Hash))
+	case <-time.After(5 * time.Second):
+		t.Fatal("signed header not written")
+	}
+
+	// the subscription ends with the connection.
+	wsc.cancel()
+	time.Sleep(100 * time.Millisecond)
+	evsw.FireEvent(types.EventNewSignedHeader{Header: header, Commit: commit})
+	time.Sleep(100 * time.Millisecond)
+	require.Len(t, wsc.resps, 0)
+}
+
+func TestSubscribeTxs(t *testing.T) {
+	evsw = events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+
+	// the subscription is only available over a websocket.
+	_, err := SubscribeTxs(&rpctypes.Context{}, "")
+	require.Error(t, err)
+
+	wsc := newTestWSConn()
+	req := &rpctypes.RPCRequest{ID: rpctypes.JSONRPCStringID("txs")}
+	_, err = SubscribeTxs(&rpctypes.Context{JSONReq: req, WSConn: wsc}, "tx.height=x")
+	require.Error(t, err)
+	_, err = SubscribeTxs(&rpctypes.Context{JSONReq: req, WSConn: wsc}, "tx.height=12")
+	require.NoError(t, err)
+
+	// txs not matching the query are not written.
+	txRes := types.TxResult{Height: 11, Index: 1, Tx: types.Tx("tx1")}
+	evsw.FireEvent(types.EventTx{Result: txRes})
+	txRes = types.TxResult{Height: 12, Index: 2, Tx: types.Tx("tx2")}
+	txRes.Response.Events = []abci.Event{abci.EventString("changed")}
+	evsw.FireEvent(types.EventTx{Result: txRes})
+
+	select {
+	case resp := <-wsc.resps:
+		require.Equal(t, req.ID, resp.ID)
+		var res ctypes.ResultTxEvent
+		require.NoError(t, amino.UnmarshalJSON(resp.Result, &res))
+		require.Equal(t, types.Tx("tx2").Hash(), res.Hash)
+		require.Equal(t, int64(12), res.Height)
+		require.Equal(t, uint32(2), res.Index)
+		require.Equal(t, []abci.Event{abci.EventString("changed")}, res.Events)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tx not written")
+	}
+	time.Sleep(100 * time.Millisecond)
+	require.Len(t, wsc.resps, 0)
+
+	// the subscription ends with the connection.
+	wsc.cancel()
+	time.Sleep(100 * time.Millisecond)
+	evsw.FireEvent(types.EventTx{Result: txRes})
+	time.Sleep(100 * time.Millisecond)
+	require.Len(t, wsc.resps, 0)
+}
@@ -124,7 +124,8 @@ func BroadcastTxAsync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadca
 // 		"code": "0",
 // 		"data": "",
 // 		"log": "",
-// 		"hash": "0D33F2F03A5234F38706E43004489E061AC40A2E"
+// 		"hash": "0D33F2F03A5234F38706E43004489E061AC40A2E",
+// 		"tx_hash": "5F4E2AB0E0D8A1C6B9E3D7F2A1B0C9D8E7F6A5B4C3D2E1F0A9B8C7D6E5F4A3B2"
 // 	},
 // 	"error": ""
 // }
@@ -146,10 +147,11 @@ func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcas
 	res := <-resCh
 	r := res.(abci.ResponseCheckTx)
 	return &ctypes.ResultBroadcastTx{
-		Error: r.Error,
-		Data:  r.Data,
-		Log:   r.Log,
-		Hash:  tx.Hash(),
+		Error:  r.Error,
+		Data:   r.Data,
+		Log:    r.Log,
+		Hash:   tx.Hash(),
+		TxHash: r.TxHash,
 	}, nil
 }
 
@@ -231,6 +233,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 			CheckTx:   checkTxRes,
 			DeliverTx: abci.ResponseDeliverTx{},
 			Hash:      tx.Hash(),
+			TxHash:    checkTxRes.TxHash,
 		}, nil
 	}
 
@@ -243,6 +246,7 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 		CheckTx:   checkTxRes,
 		DeliverTx: txRes.Response,
 		Hash:      tx.Hash(),
+		TxHash:    txRes.Response.TxHash,
 		Height:    txRes.Height,
 	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
 	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/p2p"
 )
 
 // Get network info.
@@ -173,6 +174,54 @@ func NetInfo(ctx *rpctypes.Context) (*ctypes.ResultNetInfo, error) {
 	}, nil
 }
 
+// Get the map of the network from the last crawl of the peers known to the
+// node, with their reachability, versions and latencies, if crawl_interval
+// is set in the p2p config.
+//
+// ```shell
+// curl 'localhost:26657/net_topology'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "report": {
+//       "time": "2022-05-10T12:04:05.123Z",
+//       "peers": [
+//         {
+//           "addr": "g1h0n4ajxg3txfwcm3hs37nhgkdx4fpxm4cyz0zh@1.2.3.4:26656",
+//           "connected": true,
+//           "reachable": true,
+//           "latency": "12345678",
+//           "node_info": {
+//             "network": "dev",
+//             "version": "1.0.0",
+//             ...
+//           }
+//         },
+//         {
+//           "addr": "g1q6n7t2ff0nxspsxprlf3c5yr4e2ndx9dmqp9lp@5.6.7.8:26656",
+//           "connected": false,
+//           "reachable": false,
+//           "latency": "0",
+//           "error": "dial tcp 5.6.7.8:26656: i/o timeout"
+//         }
+//       ]
+//     }
+//   }
+// }
+// ```
+func NetTopology(ctx *rpctypes.Context) (*ctypes.ResultNetTopology, error) {
+	if crawler == nil {
+		return nil, errors.New("Crawler is disabled")
+	}
+	return &ctypes.ResultNetTopology{Report: crawler.Report()}, nil
+}
+
 func UnsafeDialSeeds(ctx *rpctypes.Context, seeds []string) (*ctypes.ResultDialSeeds, error) {
 	if len(seeds) == 0 {
 		return &ctypes.ResultDialSeeds{}, errors.New("No seeds provided")
@@ -245,3 +294,139 @@ func UnsafeDialPeers(ctx *rpctypes.Context, peers []string, persistent bool) (*c
 func Genesis(ctx *rpctypes.Context) (*ctypes.ResultGenesis, error) {
 	return &ctypes.ResultGenesis{Genesis: genDoc}, nil
 }
+
+// Get the address book: the known peers, by decreasing score, and the banned
+// IDs and IP ranges.  The score of a peer is its number of successful dials,
+// minus the failed ones.
+//
+// ```shell
+// curl 'localhost:26657/addr_book'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "book": {
+//       "addrs": [
+//         {
+//           "addr": "g1h0n4ajxg3txfwcm3hs37nhgkdx4fpxm4cyz0zh@1.2.3.4:26656",
+//           "score": "3",
+//           "attempts": "0",
+//           "last_attempt": "2022-05-10T12:04:05.123Z",
+//           "last_success": "2022-05-10T12:04:05.123Z"
+//         }
+//       ],
+//       "banned_ids": [],
+//       "banned_ips": [
+//         "10.0.0.0/8"
+//       ]
+//     }
+//   }
+// }
+// ```
+func UnsafeAddrBook(ctx *rpctypes.Context) (*ctypes.ResultAddrBook, error) {
+	if addrBook == nil {
+		return nil, errors.New("Address book is disabled")
+	}
+	return &ctypes.ResultAddrBook{Book: addrBook.Export()}, nil
+}
+
+// Add the address "<id>@<ip>:<port>" of a peer to the address book, and
+// return the address book.
+//
+// ```shell
+// curl 'localhost:26657/addr_book_add?addr="g1h0n4ajxg3txfwcm3hs37nhgkdx4fpxm4cyz0zh@1.2.3.4:26656"'
+// ```
+func UnsafeAddrBookAdd(ctx *rpctypes.Context, addr string) (*ctypes.ResultAddrBook, error) {
+	if addrBook == nil {
+		return nil, errors.New("Address book is disabled")
+	}
+	na, err := p2p.NewNetAddressFromString(addr)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("AddrBookAdd", "addr", na)
+	if err := addrBook.AddAddress(na); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultAddrBook{Book: addrBook.Export()}, nil
+}
+
+// Remove the address of the peer id from the address book, and return the
+// address book.
+//
+// ```shell
+// curl 'localhost:26657/addr_book_remove?id="g1h0n4ajxg3txfwcm3hs37nhgkdx4fpxm4cyz0zh"'
+// ```
+func UnsafeAddrBookRemove(ctx *rpctypes.Context, id string) (*ctypes.ResultAddrBook, error) {
+	if addrBook == nil {
+		return nil, errors.New("Address book is disabled")
+	}
+	logger.Info("AddrBookRemove", "id", id)
+	if err := addrBook.RemoveAddress(p2p.ID(id)); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultAddrBook{Book: addrBook.Export()}, nil
+}
+
+// Ban a peer ID, an IP, or an IP range in the CIDR notation, disconnecting
+// the peers banned, and return the address book.
+//
+// ```shell
+// curl 'localhost:26657/addr_book_ban?target="10.0.0.0/8"'
+// ```
+func UnsafeAddrBookBan(ctx *rpctypes.Context, target string) (*ctypes.ResultAddrBook, error) {
+	if addrBook == nil {
+		return nil, errors.New("Address book is disabled")
+	}
+	logger.Info("AddrBookBan", "target", target)
+	if err := addrBook.Ban(target); err != nil {
+		return nil, err
+	}
+	for _, peer := range p2pPeers.Peers().List() {
+		if addrBook.IsBanned(peer.ID(), peer.RemoteIP()) {
+			p2pPeers.StopPeerGracefully(peer)
+		}
+	}
+	return &ctypes.ResultAddrBook{Book: addrBook.Export()}, nil
+}
+
+// Lift the ban of a peer ID, an IP, or an IP range, as given to
+// /addr_book_ban, and return the address book.
+//
+// ```shell
+// curl 'localhost:26657/addr_book_unban?target="10.0.0.0/8"'
+// ```
+func UnsafeAddrBookUnban(ctx *rpctypes.Context, target string) (*ctypes.ResultAddrBook, error) {
+	if addrBook == nil {
+		return nil, errors.New("Address book is disabled")
+	}
+	logger.Info("AddrBookUnban", "target", target)
+	if err := addrBook.Unban(target); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultAddrBook{Book: addrBook.Export()}, nil
+}
+
+// Import an address book, as returned by /addr_book, merging its peers and
+// bans into the address book, and return the address book.  The peers
+// already known are replaced by the imported ones.
+func UnsafeAddrBookImport(ctx *rpctypes.Context, book p2p.AddrBookJSON) (*ctypes.ResultAddrBook, error) {
+	if addrBook == nil {
+		return nil, errors.New("Address book is disabled")
+	}
+	logger.Info("AddrBookImport", "addrs", len(book.Addrs))
+	if err := addrBook.Import(book); err != nil {
+		return nil, err
+	}
+	for _, peer := range p2pPeers.Peers().List() {
+		if addrBook.IsBanned(peer.ID(), peer.RemoteIP()) {
+			p2pPeers.StopPeerGracefully(peer)
+		}
+	}
+	return &ctypes.ResultAddrBook{Book: addrBook.Export()}, nil
+}
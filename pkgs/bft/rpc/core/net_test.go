@@ -71,3 +71,43 @@ func TestUnsafeDialPeers(t *testing.T) {
 		}
 	}
 }
+
+func TestUnsafeAddrBook(t *testing.T) {
+	sw := p2p.MakeSwitch(p2pcfg.DefaultP2PConfig(), 1, "testing", "123.123.123",
+		func(n int, sw *p2p.Switch) *p2p.Switch { return sw })
+	err := sw.Start()
+	require.NoError(t, err)
+	defer sw.Stop()
+
+	logger = log.TestingLogger()
+	p2pPeers = sw
+	addrBook = nil
+	_, err = UnsafeAddrBook(&rpctypes.Context{})
+	assert.Error(t, err)
+
+	addrBook, err = p2p.NewAddrBook("")
+	require.NoError(t, err)
+	addr := "g1m6kmam774klwlh4dhmhaatd7al02m0h0jwnyc6@127.0.0.1:41198"
+	res, err := UnsafeAddrBookAdd(&rpctypes.Context{}, addr)
+	require.NoError(t, err)
+	require.Len(t, res.Book.Addrs, 1)
+	assert.Equal(t, addr, res.Book.Addrs[0].Addr.String())
+	_, err = UnsafeAddrBookAdd(&rpctypes.Context{}, "127.0.0.1:41198")
+	assert.Error(t, err)
+
+	res, err = UnsafeAddrBookBan(&rpctypes.Context{}, "127.0.0.0/8")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.0/8"}, res.Book.BannedIPs)
+	exported := res.Book
+
+	res, err = UnsafeAddrBookUnban(&rpctypes.Context{}, "127.0.0.0/8")
+	require.NoError(t, err)
+	assert.Empty(t, res.Book.BannedIPs)
+	res, err = UnsafeAddrBookRemove(&rpctypes.Context{}, "g1m6kmam774klwlh4dhmhaatd7al02m0h0jwnyc6")
+	require.NoError(t, err)
+	assert.Empty(t, res.Book.Addrs)
+
+	res, err = UnsafeAddrBookImport(&rpctypes.Context{}, exported)
+	require.NoError(t, err)
+	assert.Equal(t, exported, res.Book)
+}
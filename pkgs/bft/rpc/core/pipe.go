@@ -12,7 +12,6 @@ import (
 	sm "github.com/gnolang/gno/pkgs/bft/state"
 	"github.com/gnolang/gno/pkgs/bft/state/txindex"
 	"github.com/gnolang/gno/pkgs/bft/types"
-	"github.com/gnolang/gno/pkgs/crypto"
 	dbm "github.com/gnolang/gno/pkgs/db"
 	"github.com/gnolang/gno/pkgs/events"
 	"github.com/gnolang/gno/pkgs/log"
@@ -48,6 +47,7 @@ type peers interface {
 	DialPeersAsync([]string) error
 	NumPeers() (outbound, inbound, dialig int)
 	Peers() p2p.IPeerSet
+	StopPeerGracefully(p2p.Peer)
 }
 
 //----------------------------------------------
@@ -64,9 +64,12 @@ var (
 	consensusState Consensus
 	p2pPeers       peers
 	p2pTransport   transport
+	addrBook       *p2p.AddrBook
+	crawler        *p2p.Crawler
+	nodeKey        *p2p.NodeKey
 
 	// objects
-	pubKey           crypto.PubKey
+	privValidator    types.PrivValidator
 	genDoc           *types.GenesisDoc // cache the genesis structure
 	txIndexer        txindex.TxIndexer
 	consensusReactor *consensus.ConsensusReactor
@@ -103,8 +106,20 @@ func SetP2PTransport(t transport) {
 	p2pTransport = t
 }
 
-func SetPubKey(pk crypto.PubKey) {
-	pubKey = pk
+func SetAddrBook(book *p2p.AddrBook) {
+	addrBook = book
+}
+
+func SetCrawler(c *p2p.Crawler) {
+	crawler = c
+}
+
+func SetNodeKey(key *p2p.NodeKey) {
+	nodeKey = key
+}
+
+func SetPrivValidator(pv types.PrivValidator) {
+	privValidator = pv
 }
 
 func SetGenesisDoc(doc *types.GenesisDoc) {
@@ -8,22 +8,27 @@ import (
 // NOTE: Amino is registered in rpc/core/types/codec.go.
 var Routes = map[string]*rpc.RPCFunc{
 	// info API
-	"health":        rpc.NewRPCFunc(Health, ""),
-	"status":        rpc.NewRPCFunc(Status, ""),
-	"net_info":      rpc.NewRPCFunc(NetInfo, ""),
-	"blockchain":    rpc.NewRPCFunc(BlockchainInfo, "minHeight,maxHeight"),
-	"genesis":       rpc.NewRPCFunc(Genesis, ""),
-	"block":         rpc.NewRPCFunc(Block, "height"),
-	"block_results": rpc.NewRPCFunc(BlockResults, "height"),
-	"commit":        rpc.NewRPCFunc(Commit, "height"),
-	//"tx":                   rpc.NewRPCFunc(Tx, "hash,prove"),
+	"health":         rpc.NewRPCFunc(Health, ""),
+	"status":         rpc.NewRPCFunc(Status, ""),
+	"net_info":       rpc.NewRPCFunc(NetInfo, ""),
+	"net_topology":   rpc.NewRPCFunc(NetTopology, ""),
+	"blockchain":     rpc.NewRPCFunc(BlockchainInfo, "minHeight,maxHeight"),
+	"genesis":        rpc.NewRPCFunc(Genesis, ""),
+	"block":          rpc.NewRPCFunc(Block, "height"),
+	"block_by_time":  rpc.NewRPCFunc(BlockByTime, "time"),
+	"block_results":  rpc.NewRPCFunc(BlockResults, "height"),
+	"commit":         rpc.NewRPCFunc(Commit, "height"),
+	"compact_commit": rpc.NewRPCFunc(CompactCommit, "height"),
+	"tx":             rpc.NewRPCFunc(Tx, "hash,prove"),
 	//"tx_search":            rpc.NewRPCFunc(TxSearch, "query,prove,page,per_page"),
-	"validators":           rpc.NewRPCFunc(Validators, "height"),
+	"validators":           rpc.NewRPCFunc(Validators, "height,page,per_page"),
+	"validator_by_address": rpc.NewRPCFunc(ValidatorByAddress, "address,height"),
 	"dump_consensus_state": rpc.NewRPCFunc(DumpConsensusState, ""),
 	"consensus_state":      rpc.NewRPCFunc(ConsensusState, ""),
 	"consensus_params":     rpc.NewRPCFunc(ConsensusParams, "height"),
 	"unconfirmed_txs":      rpc.NewRPCFunc(UnconfirmedTxs, "limit"),
 	"num_unconfirmed_txs":  rpc.NewRPCFunc(NumUnconfirmedTxs, ""),
+	"sign_challenge":       rpc.NewRPCFunc(SignChallenge, "nonce,validator"),
 
 	// tx broadcast API
 	"broadcast_tx_commit": rpc.NewRPCFunc(BroadcastTxCommit, "tx"),
@@ -33,6 +38,10 @@ var Routes = map[string]*rpc.RPCFunc{
 	// abci API
 	"abci_query": rpc.NewRPCFunc(ABCIQuery, "path,data,height,prove"),
 	"abci_info":  rpc.NewRPCFunc(ABCIInfo, ""),
+
+	// websocket API
+	"subscribe_headers": rpc.NewRPCFunc(SubscribeHeaders, ""),
+	"subscribe_txs":     rpc.NewRPCFunc(SubscribeTxs, "query"),
 }
 
 func AddUnsafeRoutes() {
@@ -41,6 +50,14 @@ func AddUnsafeRoutes() {
 	Routes["dial_peers"] = rpc.NewRPCFunc(UnsafeDialPeers, "peers,persistent")
 	Routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(UnsafeFlushMempool, "")
 
+	// address book API
+	Routes["addr_book"] = rpc.NewRPCFunc(UnsafeAddrBook, "")
+	Routes["addr_book_add"] = rpc.NewRPCFunc(UnsafeAddrBookAdd, "addr")
+	Routes["addr_book_remove"] = rpc.NewRPCFunc(UnsafeAddrBookRemove, "id")
+	Routes["addr_book_ban"] = rpc.NewRPCFunc(UnsafeAddrBookBan, "target")
+	Routes["addr_book_unban"] = rpc.NewRPCFunc(UnsafeAddrBookUnban, "target")
+	Routes["addr_book_import"] = rpc.NewRPCFunc(UnsafeAddrBookImport, "book")
+
 	// profiler API
 	Routes["unsafe_start_cpu_profiler"] = rpc.NewRPCFunc(UnsafeStartCPUProfiler, "filename")
 	Routes["unsafe_stop_cpu_profiler"] = rpc.NewRPCFunc(UnsafeStopCPUProfiler, "")
@@ -7,6 +7,7 @@ import (
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/pkgs/bft/state"
 	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/errors"
 )
 
 // Get Tendermint status including node info, pubkey, latest block
@@ -90,6 +91,8 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 
 	latestBlockTime := time.Unix(0, latestBlockTimeNano)
 
+	// NOTE: the key of the validator may be rotated while the node runs.
+	pubKey := privValidator.GetPubKey()
 	var votingPower int64
 	if val := validatorAtHeight(latestHeight); val != nil {
 		votingPower = val.VotingPower
@@ -115,7 +118,7 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 }
 
 func validatorAtHeight(h int64) *types.Validator {
-	privValAddress := pubKey.Address()
+	privValAddress := privValidator.GetPubKey().Address()
 
 	// If we're still at height h, search in the current validator set.
 	lastBlockHeight, vals := consensusState.GetValidators()
@@ -139,3 +142,64 @@ func validatorAtHeight(h int64) *types.Validator {
 
 	return nil
 }
+
+// Sign a nonce chosen by the caller with the node key, and optionally with
+// the validator key, to prove that the node controls them, e.g. to a
+// registry of nodes.  The nonce is at most 256 bytes; the caller verifies
+// the challenge with Challenge.Verify, using the chain ID of the node.
+//
+// ```shell
+// curl 'localhost:26657/sign_challenge?nonce=0x6E6F6E6365&validator=true'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "chain_id": "test-chain-Y1OHx6",
+//     "challenge": {
+//       "nonce": "bm9uY2U=",
+//       "node_id": "g1m6kmam774klwlh4dhmhaatd7al02m0h0jwnyc6",
+//       "node_pub_key": {
+//         "@type": "/tm.PubKeyEd25519",
+//         "value": "LUXOjsrMd3eJ7zFWm8MhhTSQKOBoJTwOmEnE9bqvjuk="
+//       },
+//       "node_signature": "...",
+//       "validator_pub_key": {
+//         "@type": "/tm.PubKeyEd25519",
+//         "value": "wVxKNtEsJmR4vvh651LrVoRguPs+6yJJ9Bz174gw9DM="
+//       },
+//       "validator_signature": "..."
+//     }
+//   }
+// }
+// ```
+func SignChallenge(ctx *rpctypes.Context, nonce []byte, validator bool) (*ctypes.ResultSignChallenge, error) {
+	if len(nonce) == 0 || len(nonce) > types.MaxChallengeNonceSize {
+		return nil, errors.New("nonce must be 1 to %d bytes", types.MaxChallengeNonceSize)
+	}
+	chainID := genDoc.ChainID
+	challenge := types.Challenge{
+		Nonce:      nonce,
+		NodeID:     nodeKey.ID(),
+		NodePubKey: nodeKey.PubKey(),
+	}
+	if validator {
+		pv, ok := privValidator.(types.ChallengePrivValidator)
+		if !ok {
+			return nil, errors.New("Validator key can't sign challenges")
+		}
+		if err := pv.SignChallenge(chainID, &challenge); err != nil {
+			return nil, err
+		}
+	}
+	sig, err := nodeKey.PrivKey.Sign(challenge.SignBytes(chainID))
+	if err != nil {
+		return nil, err
+	}
+	challenge.NodeSignature = sig
+	return &ctypes.ResultSignChallenge{ChainID: chainID, Challenge: challenge}, nil
+}
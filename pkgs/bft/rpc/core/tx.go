@@ -1,13 +1,8 @@
 package core
 
-/*
-
 import (
 	"fmt"
 
-	cmn "github.com/gnolang/gno/pkgs/common"
-
-	tmquery "github.com/gnolang/gno/pkgs/pubsub/query"
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
 	"github.com/gnolang/gno/pkgs/bft/state/txindex/null"
@@ -18,19 +13,22 @@ import (
 // transaction is in the mempool, invalidated, or was not sent in the first
 // place.
 //
+// The hash is either the hash of the tx, or its canonical hash if defined by
+// the app (see the tx_hash of /broadcast_tx_sync).  With prove, the result
+// includes the Merkle proof of the inclusion of the tx in its block, which
+// is verified with TxProof.Validate against the data hash of the header of
+// the block, itself verified with its commit (see /commit).
+//
 // ```shell
-// curl "localhost:26657/tx?hash=0xF87370F68C82D9AC7201248ECA48CEC5F16FFEC99C461C1B2961341A2FE9C1C8"
+// curl "localhost:26657/tx?hash=0xF87370F68C82D9AC7201248ECA48CEC5F16FFEC99C461C1B2961341A2FE9C1C8&prove=true"
 // ```
 //
 // ```go
 // client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
-// err := client.Start()
-// if err != nil {
-//   // handle error
-// }
-// defer client.Stop()
 // hashBytes, err := hex.DecodeString("F87370F68C82D9AC7201248ECA48CEC5F16FFEC99C461C1B2961341A2FE9C1C8")
 // tx, err := client.Tx(hashBytes, true)
+// commit, err := client.Commit(&tx.Height)
+// err = tx.Proof.Validate(commit.Header.DataHash)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -40,23 +38,31 @@ import (
 // 	"error": "",
 // 	"result": {
 // 		"proof": {
-// 			"Proof": {
+// 			"root_hash": "2B8EC32BA2579B3B8606E42C06DE2F7AFA2556EF",
+// 			"data": "YWJjZA==",
+// 			"proof": {
+// 				"total": "1",
+// 				"index": "0",
+// 				"leaf_hash": "2B8EC32BA2579B3B8606E42C06DE2F7AFA2556EF",
 // 				"aunts": []
-// 			},
-// 			"Data": "YWJjZA==",
-// 			"RootHash": "2B8EC32BA2579B3B8606E42C06DE2F7AFA2556EF",
-// 			"Total": "1",
-// 			"Index": "0"
+// 			}
 // 		},
 // 		"tx": "YWJjZA==",
 // 		"tx_result": {
-// 			"log": "",
-// 			"data": "",
-// 			"code": "0"
+// 			"ResponseBase": {
+// 				"Error": null,
+// 				"Data": null,
+// 				"Events": null,
+// 				"Log": "",
+// 				"Info": ""
+// 			},
+// 			"GasWanted": "0",
+// 			"GasUsed": "0",
+// 			"TxHash": null
 // 		},
 // 		"index": "0",
 // 		"height": "52",
-//		"hash": "2B8EC32BA2579B3B8606E42C06DE2F7AFA2556EF"
+// 		"hash": "F87370F68C82D9AC7201248ECA48CEC5F16FFEC99C461C1B2961341A2FE9C1C8"
 // 	},
 // 	"id": "",
 // 	"jsonrpc": "2.0"
@@ -81,7 +87,6 @@ import (
 // - `height`: `int` - height of the block where this transaction was in
 // - `hash`: `[]byte` - hash of the transaction
 func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
-
 	// if index is disabled, return error
 	if _, ok := txIndexer.(*null.TxIndex); ok {
 		return nil, fmt.Errorf("Transaction indexing is disabled")
@@ -102,19 +107,24 @@ func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error
 	var proof types.TxProof
 	if prove {
 		block := blockStore.LoadBlock(height)
+		if block == nil {
+			return nil, fmt.Errorf("Block of height %d not found", height)
+		}
 		proof = block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
 	}
 
 	return &ctypes.ResultTx{
-		Hash:     hash,
+		Hash:     r.Tx.Hash(),
 		Height:   height,
 		Index:    index,
-		TxResult: r.Result,
+		TxResult: r.Response,
 		Tx:       r.Tx,
 		Proof:    proof,
 	}, nil
 }
 
+/*
+
 // TxSearch allows you to query for multiple transactions results. It returns a
 // list of transactions (maximum ?per_page entries) and the total count.
 //
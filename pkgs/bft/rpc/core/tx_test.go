@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	rpctypes "github.com/gnolang/gno/pkgs/bft/rpc/lib/types"
+	"github.com/gnolang/gno/pkgs/bft/state/txindex/kv"
+	"github.com/gnolang/gno/pkgs/bft/state/txindex/null"
+	"github.com/gnolang/gno/pkgs/bft/store"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	dbm "github.com/gnolang/gno/pkgs/db"
+)
+
+func TestTx(t *testing.T) {
+	txs := []types.Tx{types.Tx("a"), types.Tx("b"), types.Tx("c")}
+	block := types.MakeBlock(1, txs, new(types.Commit))
+	bs := store.NewBlockStore(dbm.NewMemDB())
+	bs.SaveBlock(block, block.MakePartSet(types.BlockPartSizeBytes), new(types.Commit))
+	blockStore = bs
+
+	txIndexer = &null.TxIndex{}
+	_, err := Tx(&rpctypes.Context{}, txs[1].Hash(), false)
+	require.Error(t, err, "indexing disabled")
+
+	txIndexer = kv.NewTxIndex(dbm.NewMemDB())
+	for i, tx := range txs {
+		res := types.TxResult{Height: 1, Index: uint32(i), Tx: tx}
+		res.Response.Log = string(tx)
+		res.Response.TxHash = []byte("canonical-" + string(tx))
+		require.NoError(t, txIndexer.Index(&res))
+	}
+
+	res, err := Tx(&rpctypes.Context{}, txs[1].Hash(), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.Height)
+	assert.Equal(t, uint32(1), res.Index)
+	assert.Equal(t, txs[1], res.Tx)
+	assert.Equal(t, "b", res.TxResult.Log)
+	assert.NoError(t, res.Proof.Validate(block.DataHash))
+	assert.Error(t, res.Proof.Validate(types.Txs(txs[:2]).Hash()))
+
+	// by canonical hash.
+	res, err = Tx(&rpctypes.Context{}, []byte("canonical-c"), false)
+	require.NoError(t, err)
+	assert.Equal(t, txs[2].Hash(), res.Hash)
+	assert.Equal(t, "c", res.TxResult.Log)
+	assert.Empty(t, res.Proof.RootHash)
+
+	_, err = Tx(&rpctypes.Context{}, []byte("unknown"), false)
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/gnolang/gno/pkgs/bft/state"
 	"github.com/gnolang/gno/pkgs/bft/types"
 	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/errors"
 	"github.com/gnolang/gno/pkgs/p2p"
 )
 
@@ -36,6 +37,25 @@ type ResultCommit struct {
 	CanonicalCommit    bool `json:"canonical"`
 }
 
+// Compact commit and header
+type ResultCompactCommit struct {
+	Header          *types.Header        `json:"header"`
+	Commit          *types.CompactCommit `json:"commit"`
+	CanonicalCommit bool                 `json:"canonical"`
+}
+
+// SignedHeader returns the header with the commit of the compact commit, or
+// an error if it is invalid.
+func (r *ResultCompactCommit) SignedHeader() (types.SignedHeader, error) {
+	if r.Commit == nil {
+		return types.SignedHeader{}, errors.New("no commit")
+	}
+	if err := r.Commit.ValidateBasic(); err != nil {
+		return types.SignedHeader{}, err
+	}
+	return types.SignedHeader{Header: r.Header, Commit: r.Commit.Commit()}, nil
+}
+
 // ABCI results from a block
 type ResultBlockResults struct {
 	Height  int64                `json:"height"`
@@ -79,6 +99,12 @@ type ResultStatus struct {
 	ValidatorInfo ValidatorInfo `json:"validator_info"`
 }
 
+// Challenge signed by the node, of the chain
+type ResultSignChallenge struct {
+	ChainID   string          `json:"chain_id"`
+	Challenge types.Challenge `json:"challenge"`
+}
+
 // Is TxIndexing enabled
 func (s *ResultStatus) TxIndexEnabled() bool {
 	if s == nil {
@@ -105,6 +131,16 @@ type ResultDialPeers struct {
 	Log string `json:"log"`
 }
 
+// The address book, after the change, if any
+type ResultAddrBook struct {
+	Book p2p.AddrBookJSON `json:"book"`
+}
+
+// Crawl report of the network
+type ResultNetTopology struct {
+	Report p2p.CrawlReport `json:"report"`
+}
+
 // A peer
 type Peer struct {
 	NodeInfo         p2p.NodeInfo         `json:"node_info"`
@@ -117,6 +153,17 @@ type Peer struct {
 type ResultValidators struct {
 	BlockHeight int64              `json:"block_height"`
 	Validators  []*types.Validator `json:"validators"`
+	// Count of the validators of the page
+	Count int `json:"count"`
+	// Total count of the validators
+	Total int `json:"total"`
+}
+
+// Validator of a validator set, with its index in the set
+type ResultValidator struct {
+	BlockHeight int64            `json:"block_height"`
+	Index       int              `json:"index"`
+	Validator   *types.Validator `json:"validator"`
 }
 
 // ConsensusParams for given height
@@ -149,7 +196,8 @@ type ResultBroadcastTx struct {
 	Data  []byte     `json:"data"`
 	Log   string     `json:"log"`
 
-	Hash []byte `json:"hash"`
+	Hash   []byte `json:"hash"`
+	TxHash []byte `json:"tx_hash"` // canonical hash, if defined by the app
 }
 
 // CheckTx and DeliverTx results
@@ -157,6 +205,7 @@ type ResultBroadcastTxCommit struct {
 	CheckTx   abci.ResponseCheckTx   `json:"check_tx"`
 	DeliverTx abci.ResponseDeliverTx `json:"deliver_tx"`
 	Hash      []byte                 `json:"hash"`
+	TxHash    []byte                 `json:"tx_hash"` // canonical hash, if defined by the app
 	Height    int64                  `json:"height"`
 }
 
@@ -191,7 +240,8 @@ type ResultABCIInfo struct {
 
 // Query abci msg
 type ResultABCIQuery struct {
-	Response abci.ResponseQuery `json:"response"`
+	Response  abci.ResponseQuery    `json:"response"`
+	Signature *types.QuerySignature `json:"signature,omitempty"` // if signed by the node
 }
 
 // empty results
@@ -199,9 +249,18 @@ type (
 	ResultUnsafeFlushMempool struct{}
 	ResultUnsafeProfile      struct{}
 	ResultHealth             struct{}
+	ResultSubscribe          struct{}
 )
 
 // Event data from a subscription
 type ResultEvent struct {
 	Event types.TMEvent `json:"event"`
 }
+
+// A tx of a subscription, with its events matching the query
+type ResultTxEvent struct {
+	Hash   []byte       `json:"hash"`
+	Height int64        `json:"height"`
+	Index  uint32       `json:"index"`
+	Events []abci.Event `json:"events"`
+}
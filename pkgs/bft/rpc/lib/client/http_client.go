@@ -2,6 +2,7 @@ package rpcclient
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -48,6 +49,10 @@ func toClientAddrAndParse(remoteAddr string) (network string, trimmedS string, e
 	default:
 		clientProtocol = protoHTTP
 	}
+	// https is parsed as tcp for dialing, but must stay https here
+	if strings.HasPrefix(remoteAddr, protoHTTPS+"://") {
+		clientProtocol = protoHTTPS
+	}
 
 	// replace / with . for http requests (kvstore domain)
 	trimmedAddress := strings.Replace(address, "/", ".", -1)
@@ -108,11 +113,18 @@ func makeHTTPDialer(remoteAddr string) func(string, string) (net.Conn, error) {
 // We overwrite the http.Client.Dial so we can do http over tcp or unix.
 // remoteAddr should be fully featured (eg. with tcp:// or unix://)
 func DefaultHTTPClient(remoteAddr string) *http.Client {
+	return TLSHTTPClient(remoteAddr, nil)
+}
+
+// TLSHTTPClient is like DefaultHTTPClient, with the TLS configuration of
+// https connections, e.g. to verify the certificate of the server.
+func TLSHTTPClient(remoteAddr string, tlsConfig *tls.Config) *http.Client {
 	return &http.Client{
 		Transport: &http.Transport{
 			// Set to true to prevent GZIP-bomb DoS attacks
 			DisableCompression: true,
 			Dial:               makeHTTPDialer(remoteAddr),
+			TLSClientConfig:    tlsConfig,
 		},
 	}
 }
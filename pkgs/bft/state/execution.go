@@ -126,6 +126,10 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 	if err != nil {
 		return state, fmt.Errorf("Commit failed for application: %v", err)
 	}
+	if abciResponses.EndBlock.ConsensusParams != nil {
+		blockExec.logger.Info("Updates to consensus params", "height", state.LastHeightConsensusParamsChanged,
+			"updates", abciResponses.EndBlock.ConsensusParams)
+	}
 
 	// Lock mempool, commit app state, update mempoool.
 	appHash, err := blockExec.Commit(state, block, abciResponses.DeliverTxs)
@@ -143,7 +147,7 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 
 	// Events are fired after everything else.
 	// NOTE: if we crash between Commit and Save, events wont be fired during replay
-	fireEvents(blockExec.logger, blockExec.evsw, block, abciResponses)
+	fireEvents(blockExec.logger, blockExec.evsw, block, abciResponses, state)
 
 	return state, nil
 }
@@ -195,6 +199,7 @@ func (blockExec *BlockExecutor) Commit(
 		deliverTxResponses,
 		TxPreCheck(state),
 		state.ConsensusParams.Block.MaxTxBytes,
+		types.MaxTxGas(state.ConsensusParams.Block),
 	)
 
 	return res.Data, err
@@ -397,8 +402,10 @@ func updateState(
 
 // Fire NewBlock, NewBlockHeader.
 // Fire TxEvent for every tx.
+// Fire ValidatorSetUpdates and ConsensusParamsUpdates, if any, with the
+// state updated by the block.
 // NOTE: if Tendermint crashes before commit, some or all of these events may be published again.
-func fireEvents(logger log.Logger, evsw events.EventSwitch, block *types.Block, abciResponses *ABCIResponses) {
+func fireEvents(logger log.Logger, evsw events.EventSwitch, block *types.Block, abciResponses *ABCIResponses, state State) {
 	evsw.FireEvent(types.EventNewBlock{
 		Block:            block,
 		ResultBeginBlock: abciResponses.BeginBlock,
@@ -423,6 +430,13 @@ func fireEvents(logger log.Logger, evsw events.EventSwitch, block *types.Block,
 		evsw.FireEvent(
 			types.EventValidatorSetUpdates{ValidatorUpdates: u})
 	}
+
+	if abciResponses.EndBlock.ConsensusParams != nil {
+		evsw.FireEvent(types.EventConsensusParamsUpdates{
+			Height:          state.LastHeightConsensusParamsChanged,
+			ConsensusParams: state.ConsensusParams,
+		})
+	}
 }
 
 //----------------------------------------------------------------------------------------------------
@@ -314,6 +314,78 @@ LOOP:
 	<-done
 }
 
+func TestEndBlockConsensusParamsUpdates(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	state, stateDB, _ := makeState(1, 1)
+
+	blockExec := sm.NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(), mock.Mempool{})
+
+	evsw := events.NewEventSwitch()
+	err = evsw.Start()
+	require.NoError(t, err)
+	defer evsw.Stop()
+	blockExec.SetEventSwitch(evsw)
+
+	updatesSub := events.Subscribe(evsw, "TestEndBlockConsensusParamsUpdates")
+
+	block := makeBlock(state, 1)
+	blockID := types.BlockID{Hash: block.Hash(), PartsHeader: block.MakePartSet(testPartSize).Header()}
+
+	// invalid updates are rejected.
+	app.ConsensusParams = &abci.ConsensusParams{
+		Evidence: &abci.EvidenceParams{MaxAge: 0},
+	}
+	_, err = blockExec.ApplyBlock(state, blockID, block)
+	assert.NotNil(t, err)
+
+	app.ConsensusParams = &abci.ConsensusParams{
+		Block: &abci.BlockParams{
+			MaxTxBytes:   1024,
+			MaxDataBytes: 2048,
+			MaxGas:       1000,
+			TimeIotaMS:   1000,
+		},
+		Evidence: &abci.EvidenceParams{MaxAge: 10},
+	}
+
+	// Run in goroutine.
+	done := async.Routine(func() {
+		state, err := blockExec.ApplyBlock(state, blockID, block)
+		require.Nil(t, err)
+
+		// applied from the next height.
+		assert.Equal(t, int64(2), state.LastHeightConsensusParamsChanged)
+		assert.Equal(t, int64(1000), state.ConsensusParams.Block.MaxGas)
+		assert.Equal(t, int64(10), state.ConsensusParams.Evidence.MaxAge)
+		assert.NotEmpty(t, state.ConsensusParams.Validator.PubKeyTypeURLs)
+	})
+
+	// test we threw an event
+LOOP:
+	for {
+		select {
+		case msg := <-updatesSub:
+			switch event := msg.(type) {
+			case types.EventConsensusParamsUpdates:
+				assert.Equal(t, int64(2), event.Height)
+				assert.Equal(t, int64(1000), event.ConsensusParams.Block.MaxGas)
+				assert.Equal(t, int64(10), event.ConsensusParams.Evidence.MaxAge)
+				break LOOP
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatal("Did not receive EventConsensusParamsUpdates within 1 sec.")
+		}
+	}
+
+	<-done
+}
+
 // TestEndBlockValidatorUpdatesResultingInEmptySet checks that processing validator updates that
 // would result in empty set causes no panic, an error is raised and NextValidators is not updated
 func TestEndBlockValidatorUpdatesResultingInEmptySet(t *testing.T) {
@@ -214,6 +214,7 @@ type testApp struct {
 
 	CommitVotes      []abci.VoteInfo
 	ValidatorUpdates []abci.ValidatorUpdate
+	ConsensusParams  *abci.ConsensusParams
 }
 
 var _ abci.Application = (*testApp)(nil)
@@ -228,7 +229,7 @@ func (app *testApp) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlo
 }
 
 func (app *testApp) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
-	return abci.ResponseEndBlock{ValidatorUpdates: app.ValidatorUpdates}
+	return abci.ResponseEndBlock{ValidatorUpdates: app.ValidatorUpdates, ConsensusParams: app.ConsensusParams}
 }
 
 func (app *testApp) DeliverTx(req abci.RequestDeliverTx) abci.ResponseDeliverTx {
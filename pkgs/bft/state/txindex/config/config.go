@@ -0,0 +1,41 @@
+package config
+
+import "github.com/gnolang/gno/pkgs/errors"
+
+//-----------------------------------------------------------------------------
+// TxIndexConfig
+
+// TxIndexConfig defines the configuration for the transaction indexer.
+type TxIndexConfig struct {
+	// What indexer to use for transactions
+	//
+	// Options:
+	//   1) "null"
+	//   2) "kv" (default) - the simplest possible indexer, backed by
+	//   key-value storage (defaults to levelDB; see DBBackend), indexing the
+	//   txs by their hash for /tx.
+	Indexer string `toml:"indexer"`
+}
+
+// DefaultTxIndexConfig returns a default configuration for the transaction indexer.
+func DefaultTxIndexConfig() *TxIndexConfig {
+	return &TxIndexConfig{
+		Indexer: "kv",
+	}
+}
+
+// TestTxIndexConfig returns a default configuration for the transaction indexer.
+func TestTxIndexConfig() *TxIndexConfig {
+	return DefaultTxIndexConfig()
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *TxIndexConfig) ValidateBasic() error {
+	switch cfg.Indexer {
+	case "null", "kv":
+		return nil
+	default:
+		return errors.New("unknown indexer %q (must be 'null' or 'kv')", cfg.Indexer)
+	}
+}
@@ -1,10 +1,13 @@
 package txindex
 
 import (
+	"github.com/gnolang/gno/pkgs/bft/types"
 	"github.com/gnolang/gno/pkgs/events"
 	"github.com/gnolang/gno/pkgs/service"
 )
 
+const listenerID = "txindex"
+
 // IndexerService connects event bus and transaction indexer together in order
 // to index transactions coming from event bus.
 type IndexerService struct {
@@ -21,11 +24,22 @@ func NewIndexerService(idr TxIndexer, evsw events.EventSwitch) *IndexerService {
 	return is
 }
 
+// OnStart implements service.Service by subscribing for the txs, indexed as
+// they are fired after the execution of their block.
 func (is *IndexerService) OnStart() error {
-	// TODO
+	is.evsw.AddListener(listenerID, func(event events.Event) {
+		ev, ok := event.(types.EventTx)
+		if !ok {
+			return
+		}
+		if err := is.idr.Index(&ev.Result); err != nil {
+			is.Logger.Error("Failed to index tx", "height", ev.Result.Height, "index", ev.Result.Index, "err", err)
+		}
+	})
 	return nil
 }
 
+// OnStop implements service.Service by unsubscribing from the txs.
 func (is *IndexerService) OnStop() {
-	// TODO
+	is.evsw.RemoveListener(listenerID)
 }
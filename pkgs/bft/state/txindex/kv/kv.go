@@ -0,0 +1,78 @@
+package kv
+
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/state/txindex"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+var _ txindex.TxIndexer = (*TxIndex)(nil)
+
+// TxIndex is the simplest possible indexer, backed by key-value storage
+// (levelDB).  It indexes the txs by their hash, and by their canonical
+// hash if the app defines one (see ResponseDeliverTx.TxHash).
+type TxIndex struct {
+	store dbm.DB
+}
+
+// NewTxIndex creates new KV indexer.
+func NewTxIndex(store dbm.DB) *TxIndex {
+	return &TxIndex{
+		store: store,
+	}
+}
+
+// Get gets transaction from the TxIndex storage and returns it or nil if the
+// transaction is not found, by its hash or its canonical hash.
+func (txi *TxIndex) Get(hash []byte) (*types.TxResult, error) {
+	if len(hash) == 0 {
+		return nil, txindex.ErrorEmptyHash
+	}
+
+	rawBytes := txi.store.Get(txKey(hash))
+	if rawBytes == nil {
+		txHash := txi.store.Get(canonicalKey(hash))
+		if txHash == nil {
+			return nil, nil
+		}
+		rawBytes = txi.store.Get(txKey(txHash))
+		if rawBytes == nil {
+			return nil, nil
+		}
+	}
+
+	txResult := new(types.TxResult)
+	err := amino.Unmarshal(rawBytes, txResult)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading TxResult")
+	}
+	return txResult, nil
+}
+
+// Index indexes a single transaction.
+func (txi *TxIndex) Index(result *types.TxResult) error {
+	hash := result.Tx.Hash()
+	rawBytes, err := amino.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	b := txi.store.NewBatch()
+	defer b.Close()
+	b.Set(txKey(hash), rawBytes)
+	if txHash := result.Response.TxHash; len(txHash) != 0 {
+		b.Set(canonicalKey(txHash), hash)
+	}
+	b.WriteSync()
+	return nil
+}
+
+func txKey(hash []byte) []byte {
+	return append([]byte("tx/"), hash...)
+}
+
+func canonicalKey(txHash []byte) []byte {
+	return append([]byte("txhash/"), txHash...)
+}
@@ -2,6 +2,8 @@ package null
 
 import (
 	"github.com/gnolang/gno/pkgs/bft/state/txindex"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/errors"
 )
 
 var _ txindex.TxIndexer = (*TxIndex)(nil)
@@ -9,19 +11,19 @@ var _ txindex.TxIndexer = (*TxIndex)(nil)
 // TxIndex acts as a /dev/null.
 type TxIndex struct{}
 
-/*
-// Get on a TxIndex is disabled and panics when invoked.
+// Get on a TxIndex is disabled and returns an error.
 func (txi *TxIndex) Get(hash []byte) (*types.TxResult, error) {
-	return nil, errors.New(`Indexing is disabled (set 'tx_index = "kv"' in config)`)
+	return nil, errors.New(`Indexing is disabled (set 'indexer = "kv"' in the [tx_index] config)`)
 }
 
-// AddBatch is a noop and always returns nil.
-func (txi *TxIndex) AddBatch(batch *txindex.Batch) error {
+// Index is a noop and always returns nil.
+func (txi *TxIndex) Index(result *types.TxResult) error {
 	return nil
 }
 
-// Index is a noop and always returns nil.
-func (txi *TxIndex) Index(result *types.TxResult) error {
+/*
+// AddBatch is a noop and always returns nil.
+func (txi *TxIndex) AddBatch(batch *txindex.Batch) error {
 	return nil
 }
 
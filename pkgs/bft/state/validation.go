@@ -61,6 +61,18 @@ func validateBlock(stateDB dbm.DB, state State, block *types.Block) error {
 		)
 	}
 
+	// Validate the size of the txs, which the proposer must have reaped
+	// within the consensus params.
+	for i, tx := range block.Data.Txs {
+		if int64(len(tx)) > state.ConsensusParams.Block.MaxTxBytes {
+			return fmt.Errorf("Tx #%d is too big. %d > %d",
+				i,
+				len(tx),
+				state.ConsensusParams.Block.MaxTxBytes,
+			)
+		}
+	}
+
 	// Validate app info
 	if !bytes.Equal(block.AppHash, state.AppHash) {
 		return fmt.Errorf("Wrong Block.Header.AppHash.  Expected %X, got %v",
@@ -148,6 +160,23 @@ func validateBlock(stateDB dbm.DB, state State, block *types.Block) error {
 	return nil
 }
 
+// VerifyEvidenceAge returns an error if the evidence of misbehavior at
+// height is older than the Evidence.MaxAge of the consensus params of state.
+// It is the part of VerifyEvidence not depending on the evidence types.
+func VerifyEvidenceAge(state State, height int64) error {
+	params := state.ConsensusParams.Evidence
+	if params == nil {
+		// Nil in the states saved before the evidence params.
+		params = types.DefaultEvidenceParams()
+	}
+	evidenceAge := state.LastBlockHeight - height
+	if evidenceAge > params.MaxAge {
+		return fmt.Errorf("Evidence from height %d is too old. Min height is %d",
+			height, state.LastBlockHeight-params.MaxAge)
+	}
+	return nil
+}
+
 /*
 // VerifyEvidence verifies the evidence fully by checking:
 // - it is sufficiently recent (MaxAge)
@@ -155,13 +184,8 @@ func validateBlock(stateDB dbm.DB, state State, block *types.Block) error {
 // - it is internally consistent
 // - it was properly signed by the alleged equivocator
 func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error {
-	height := state.LastBlockHeight
-
-	evidenceAge := height - evidence.Height()
-	maxAge := state.ConsensusParams.Evidence.MaxAge
-	if evidenceAge > maxAge {
-		return fmt.Errorf("Evidence from height %d is too old. Min height is %d",
-			evidence.Height(), height-maxAge)
+	if err := VerifyEvidenceAge(state, evidence.Height()); err != nil {
+		return err
 	}
 
 	valset, err := LoadValidators(stateDB, evidence.Height())
@@ -79,6 +79,38 @@ func TestValidateBlockHeader(t *testing.T) {
 	}
 }
 
+func TestValidateBlockTxSize(t *testing.T) {
+	proxyApp := newTestApp()
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop()
+
+	state, stateDB, _ := makeState(1, 1)
+	blockExec := sm.NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(), mock.Mempool{})
+	lastCommit := types.NewCommit(types.BlockID{}, nil)
+	proposerAddr := state.Validators.GetProposer().Address
+	maxTxBytes := state.ConsensusParams.Block.MaxTxBytes
+
+	block, _ := state.MakeBlock(1, []types.Tx{make(types.Tx, maxTxBytes)}, lastCommit, proposerAddr)
+	require.NoError(t, blockExec.ValidateBlock(state, block))
+
+	block, _ = state.MakeBlock(1, []types.Tx{types.Tx("small"), make(types.Tx, maxTxBytes+1)}, lastCommit, proposerAddr)
+	require.Error(t, blockExec.ValidateBlock(state, block))
+}
+
+func TestVerifyEvidenceAge(t *testing.T) {
+	state, _, _ := makeState(1, 1)
+	state.LastBlockHeight = 20
+	state.ConsensusParams.Evidence.MaxAge = 10
+
+	require.NoError(t, sm.VerifyEvidenceAge(state, 20))
+	require.NoError(t, sm.VerifyEvidenceAge(state, 10))
+	require.Error(t, sm.VerifyEvidenceAge(state, 9))
+
+	// the states saved before the evidence params have the default.
+	state.ConsensusParams.Evidence = nil
+	require.NoError(t, sm.VerifyEvidenceAge(state, 9))
+}
+
 func TestValidateBlockCommit(t *testing.T) {
 	proxyApp := newTestApp()
 	require.NoError(t, proxyApp.Start())
@@ -0,0 +1,148 @@
+package cold
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/gnolang/gno/pkgs/bft/config"
+	sm "github.com/gnolang/gno/pkgs/bft/state"
+	"github.com/gnolang/gno/pkgs/bft/store"
+	"github.com/gnolang/gno/pkgs/bft/store/config"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	tmtime "github.com/gnolang/gno/pkgs/bft/types/time"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/events"
+)
+
+func TestFileStore(t *testing.T) {
+	fs, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	bz, err := fs.Get("blocks/1")
+	require.NoError(t, err)
+	assert.Nil(t, bz)
+	require.NoError(t, fs.Put("blocks/1", []byte("block")))
+	bz, err = fs.Get("blocks/1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("block"), bz)
+}
+
+// A fake S3 bucket, checking that the requests are signed.
+type fakeS3 struct {
+	mtx     sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/20240102/eu-west-1/s3/aws4_request, "+
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") ||
+		r.Header.Get("X-Amz-Date") != "20240102T030405Z" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s3, err := NewS3Store(srv.URL, "eu-west-1", "bucket", "access", "secret")
+	require.NoError(t, err)
+	s3.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	bz, err := s3.Get("blocks/1")
+	require.NoError(t, err)
+	assert.Nil(t, bz)
+	require.NoError(t, s3.Put("blocks/1", []byte("block")))
+	assert.Equal(t, []byte("block"), fake.objects["/bucket/blocks/1"])
+	bz, err = s3.Get("blocks/1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("block"), bz)
+
+	// errors.
+	s3.now = time.Now
+	assert.Error(t, s3.Put("blocks/2", []byte("block")))
+	_, err = NewS3Store("ftp://host", "eu-west-1", "bucket", "access", "secret")
+	assert.Error(t, err)
+}
+
+func TestNewColdStore(t *testing.T) {
+	c := config.TestColdStorageConfig()
+	c.RootDir = t.TempDir()
+	cs, err := NewColdStore(c)
+	require.NoError(t, err)
+	assert.IsType(t, &FileStore{}, cs)
+
+	c.Backend = config.BackendS3
+	c.S3Endpoint = "http://localhost:9000"
+	cs, err = NewColdStore(c)
+	require.NoError(t, err)
+	assert.IsType(t, &S3Store{}, cs)
+}
+
+func TestOffloader(t *testing.T) {
+	c := cfg.ResetTestRoot("cold_offloader_test")
+	defer os.RemoveAll(c.RootDir)
+	state, err := sm.LoadStateFromDBOrGenesisFile(dbm.NewMemDB(), c.GenesisFile())
+	require.NoError(t, err)
+	bs := store.NewBlockStore(dbm.NewMemDB())
+	fs, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	bs.SetColdStore(fs)
+
+	saveBlock := func(height int64) *types.Block {
+		block, _ := state.MakeBlock(height, nil, new(types.Commit), state.Validators.GetProposer().Address)
+		seenCommit := types.NewCommit(types.BlockID{}, []*types.CommitSig{{Height: height, Timestamp: tmtime.Now()}})
+		bs.SaveBlock(block, block.MakePartSet(2), seenCommit)
+		return block
+	}
+	for height := int64(1); height <= 3; height++ {
+		saveBlock(height)
+	}
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+	o := NewOffloader(bs, 2, evsw)
+	require.NoError(t, o.Start())
+	defer o.Stop()
+
+	// the blocks already old enough on start.
+	require.Eventually(t, func() bool { return bs.Offloaded() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// then after each new block.
+	block := saveBlock(4)
+	evsw.FireEvent(types.EventNewBlock{Block: block})
+	require.Eventually(t, func() bool { return bs.Offloaded() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.NotNil(t, bs.LoadBlock(1))
+	assert.NotNil(t, bs.LoadBlock(2))
+}
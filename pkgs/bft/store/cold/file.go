@@ -0,0 +1,48 @@
+package cold
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gnolang/gno/pkgs/bft/store"
+	osm "github.com/gnolang/gno/pkgs/os"
+)
+
+// FileStore is a cold store of files in a directory, e.g. on a cheaper disk
+// than the DB of the node, or a mounted object store.
+type FileStore struct {
+	dir string
+}
+
+var _ store.ColdStore = (*FileStore)(nil)
+
+// NewFileStore returns the cold store of the files of dir, created if it
+// doesn't exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := osm.EnsureDir(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Get implements store.ColdStore.
+func (fs *FileStore) Get(key string) ([]byte, error) {
+	bz, err := os.ReadFile(fs.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return bz, err
+}
+
+// Put implements store.ColdStore.
+func (fs *FileStore) Put(key string, value []byte) error {
+	path := fs.path(key)
+	if err := osm.EnsureDir(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return osm.WriteFileAtomic(path, value, 0o600)
+}
+
+func (fs *FileStore) path(key string) string {
+	return filepath.Join(fs.dir, filepath.FromSlash(key))
+}
@@ -0,0 +1,103 @@
+package cold
+
+import (
+	"os"
+
+	"github.com/gnolang/gno/pkgs/bft/store"
+	"github.com/gnolang/gno/pkgs/bft/store/config"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/events"
+	"github.com/gnolang/gno/pkgs/service"
+)
+
+const listenerID = "cold-storage"
+
+// NewColdStore returns the cold store of the backend of cfg.
+func NewColdStore(cfg *config.ColdStorageConfig) (store.ColdStore, error) {
+	switch cfg.Backend {
+	case config.BackendFile:
+		return NewFileStore(cfg.DirPath())
+	case config.BackendS3:
+		accessKey, secretKey := cfg.S3AccessKey, cfg.S3SecretKey
+		if accessKey == "" {
+			accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		}
+		if secretKey == "" {
+			secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		}
+		return NewS3Store(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, accessKey, secretKey)
+	default:
+		return nil, errors.New("unknown cold storage backend %q", cfg.Backend)
+	}
+}
+
+// Offloader is the service offloading the blocks older than offloadAfter
+// heights to the cold store of the block store, after each new block.
+type Offloader struct {
+	service.BaseService
+
+	blockStore   *store.BlockStore
+	offloadAfter int64
+	evsw         events.EventSwitch
+	newBlock     chan int64 // the last height, for the offload routine
+}
+
+// NewOffloader returns the offloader of the blocks of blockStore, which must
+// have a cold store, offloaded after new blocks fired on evsw.
+func NewOffloader(blockStore *store.BlockStore, offloadAfter int64, evsw events.EventSwitch) *Offloader {
+	o := &Offloader{
+		blockStore:   blockStore,
+		offloadAfter: offloadAfter,
+		evsw:         evsw,
+		newBlock:     make(chan int64, 1),
+	}
+	o.BaseService = *service.NewBaseService(nil, "Offloader", o)
+	return o
+}
+
+// OnStart implements service.Service by subscribing for the new blocks,
+// and offloading the blocks already old enough.
+func (o *Offloader) OnStart() error {
+	o.evsw.AddListener(listenerID, func(event events.Event) {
+		ev, ok := event.(types.EventNewBlock)
+		if !ok {
+			return
+		}
+		// The routine offloads up to the last height signaled.
+		select {
+		case <-o.newBlock:
+		default:
+		}
+		o.newBlock <- ev.Block.Height
+	})
+	o.newBlock <- o.blockStore.Height()
+	go o.offloadRoutine()
+	return nil
+}
+
+// OnStop implements service.Service by unsubscribing from the new blocks.
+func (o *Offloader) OnStop() {
+	o.evsw.RemoveListener(listenerID)
+}
+
+func (o *Offloader) offloadRoutine() {
+	for {
+		select {
+		case height := <-o.newBlock:
+			retainHeight := height - o.offloadAfter + 1
+			if retainHeight <= o.blockStore.Offloaded()+1 {
+				continue
+			}
+			offloaded, err := o.blockStore.OffloadBlocks(retainHeight)
+			if err != nil {
+				// Retried after the next block.
+				o.Logger.Error("Failed to offload blocks", "height", offloaded+1, "err", err)
+				continue
+			}
+			o.Logger.Debug("Offloaded blocks", "height", offloaded)
+		case <-o.Quit():
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+//-----------------------------------------------------------------------------
+// ColdStorageConfig
+
+// The backends of the cold storage.
+const (
+	BackendFile = "file"
+	BackendS3   = "s3"
+)
+
+// ColdStorageConfig defines the configuration options of the cold storage,
+// to which the blocks older than OffloadAfter heights are moved, and from
+// which they are fetched on demand, e.g. for the RPC.
+type ColdStorageConfig struct {
+	RootDir string `toml:"home"`
+
+	// Number of the last heights whose blocks are kept in the DB of the
+	// node; 0 disables the cold storage.
+	OffloadAfter int64 `toml:"offload_after"`
+
+	// Backend of the cold storage: "file", a directory, e.g. on a cheaper
+	// disk, or "s3", a bucket of an S3-compatible object store.
+	Backend string `toml:"backend"`
+
+	// Directory of the file backend.
+	Dir string `toml:"dir"`
+
+	// Endpoint, e.g. "https://s3.us-east-1.amazonaws.com", region and
+	// bucket of the s3 backend, and the key the requests are signed with,
+	// by default from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	// environment variables.
+	S3Endpoint  string `toml:"s3_endpoint"`
+	S3Region    string `toml:"s3_region"`
+	S3Bucket    string `toml:"s3_bucket"`
+	S3AccessKey string `toml:"s3_access_key"`
+	S3SecretKey string `toml:"s3_secret_key"`
+}
+
+// DefaultColdStorageConfig returns a default configuration of the cold
+// storage, disabled.
+func DefaultColdStorageConfig() *ColdStorageConfig {
+	return &ColdStorageConfig{
+		OffloadAfter: 0,
+		Backend:      BackendFile,
+		Dir:          "data/cold",
+		S3Region:     "us-east-1",
+	}
+}
+
+// TestColdStorageConfig returns a configuration of the cold storage for
+// testing.
+func TestColdStorageConfig() *ColdStorageConfig {
+	return DefaultColdStorageConfig()
+}
+
+// DirPath returns the full path of the directory of the file backend.
+func (cfg *ColdStorageConfig) DirPath() string {
+	if filepath.IsAbs(cfg.Dir) {
+		return cfg.Dir
+	}
+	return filepath.Join(cfg.RootDir, cfg.Dir)
+}
+
+// Enabled returns true if blocks are offloaded to the cold storage.
+func (cfg *ColdStorageConfig) Enabled() bool {
+	return cfg.OffloadAfter > 0
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *ColdStorageConfig) ValidateBasic() error {
+	if cfg.OffloadAfter < 0 {
+		return errors.New("offload_after can't be negative")
+	}
+	switch cfg.Backend {
+	case BackendFile:
+		if cfg.Enabled() && cfg.Dir == "" {
+			return errors.New("dir must be set for the file backend")
+		}
+	case BackendS3:
+		if cfg.Enabled() && (cfg.S3Endpoint == "" || cfg.S3Bucket == "") {
+			return errors.New("s3_endpoint and s3_bucket must be set for the s3 backend")
+		}
+	default:
+		return errors.New("unknown backend %q (must be 'file' or 's3')", cfg.Backend)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 
@@ -19,17 +20,36 @@ There are three types of information stored:
  - Commit:      The commit part of each block, for gossiping precommit votes
 
 Currently the precommit signatures are duplicated in the Block parts as
-well as the Commit.  In the future this may change, perhaps by moving
+well as the Commit.  The commits are stored as CompactCommits, the ones
+stored in full by the previous versions being still loaded.  In the future this may change, perhaps by moving
 the Commit data outside the Block. (TODO)
 
+The old blocks may be offloaded to a ColdStore, from which they are fetched
+on demand when loaded.
+
 // NOTE: BlockStore methods will panic if they encounter errors
 // deserializing loaded data, indicating probable corruption on disk.
 */
 type BlockStore struct {
-	db dbm.DB
+	db   dbm.DB
+	cold ColdStore // nil if none
+
+	mtx       sync.RWMutex
+	height    int64
+	offloaded int64 // last height offloaded to the cold store
 
-	mtx    sync.RWMutex
-	height int64
+	// last block fetched from the cold store, since loading a block loads
+	// its meta and each of its parts.
+	coldMtx   sync.Mutex
+	coldBlock *coldBlock
+}
+
+// ColdStore is a cheaper backend than the DB, e.g. an S3-compatible object
+// store, to which the old blocks are offloaded.
+type ColdStore interface {
+	// Get returns the value of key, or nil if there is none.
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
 }
 
 // NewBlockStore returns a new BlockStore with the given DB,
@@ -37,11 +57,18 @@ type BlockStore struct {
 func NewBlockStore(db dbm.DB) *BlockStore {
 	bsjson := LoadBlockStoreStateJSON(db)
 	return &BlockStore{
-		height: bsjson.Height,
-		db:     db,
+		height:    bsjson.Height,
+		offloaded: bsjson.Offloaded,
+		db:        db,
 	}
 }
 
+// SetColdStore sets the store the blocks are offloaded to, and fetched
+// from.  Without it, the blocks offloaded are not found.
+func (bs *BlockStore) SetColdStore(cold ColdStore) {
+	bs.cold = cold
+}
+
 // Height returns the last known contiguous block height.
 func (bs *BlockStore) Height() int64 {
 	bs.mtx.RLock()
@@ -49,6 +76,13 @@ func (bs *BlockStore) Height() int64 {
 	return bs.height
 }
 
+// Offloaded returns the last height offloaded to the cold store, or 0.
+func (bs *BlockStore) Offloaded() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.offloaded
+}
+
 // LoadBlock returns the block with the given height.
 // If no block is found for that height, it returns nil.
 func (bs *BlockStore) LoadBlock(height int64) *types.Block {
@@ -77,7 +111,7 @@ func (bs *BlockStore) LoadBlock(height int64) *types.Block {
 // If no part is found for the given height and index, it returns nil.
 func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 	part := new(types.Part)
-	bz := bs.db.Get(calcBlockPartKey(height, index))
+	bz := bs.get(height, calcBlockPartKey(height, index))
 	if len(bz) == 0 {
 		return nil
 	}
@@ -92,7 +126,7 @@ func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 // If no block is found for the given height, it returns nil.
 func (bs *BlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
 	blockMeta := new(types.BlockMeta)
-	bz := bs.db.Get(calcBlockMetaKey(height))
+	bz := bs.get(height, calcBlockMetaKey(height))
 	if len(bz) == 0 {
 		return nil
 	}
@@ -108,12 +142,7 @@ func (bs *BlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
 // and it comes from the block.LastCommit for `height+1`.
 // If no commit is found for the given height, it returns nil.
 func (bs *BlockStore) LoadBlockCommit(height int64) *types.Commit {
-	commit := new(types.Commit)
-	bz := bs.db.Get(calcBlockCommitKey(height))
-	if len(bz) == 0 {
-		return nil
-	}
-	err := amino.Unmarshal(bz, commit)
+	commit, err := bs.loadCommit(height, calcBlockCommitKey(height), calcLegacyBlockCommitKey(height))
 	if err != nil {
 		panic(errors.Wrap(err, "Error reading block commit"))
 	}
@@ -124,18 +153,48 @@ func (bs *BlockStore) LoadBlockCommit(height int64) *types.Commit {
 // This is useful when we've seen a commit, but there has not yet been
 // a new block at `height + 1` that includes this commit in its block.LastCommit.
 func (bs *BlockStore) LoadSeenCommit(height int64) *types.Commit {
-	commit := new(types.Commit)
-	bz := bs.db.Get(calcSeenCommitKey(height))
-	if len(bz) == 0 {
-		return nil
-	}
-	err := amino.Unmarshal(bz, commit)
+	commit, err := bs.loadCommit(height, calcSeenCommitKey(height), calcLegacySeenCommitKey(height))
 	if err != nil {
 		panic(errors.Wrap(err, "Error reading block seen commit"))
 	}
 	return commit
 }
 
+// Loads the CompactCommit of key, or else the Commit of legacyKey, of the
+// block of height.
+func (bs *BlockStore) loadCommit(height int64, key, legacyKey []byte) (*types.Commit, error) {
+	if bz := bs.get(height, key); len(bz) != 0 {
+		cc := new(types.CompactCommit)
+		if err := amino.Unmarshal(bz, cc); err != nil {
+			return nil, err
+		}
+		if err := cc.ValidateBasic(); err != nil {
+			return nil, err
+		}
+		return cc.Commit(), nil
+	}
+	bz := bs.get(height, legacyKey)
+	if len(bz) == 0 {
+		return nil, nil
+	}
+	commit := new(types.Commit)
+	if err := amino.Unmarshal(bz, commit); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+// Saves commit as a CompactCommit at key, or in full at legacyKey if it
+// can't be compacted.
+func (bs *BlockStore) saveCommit(key, legacyKey []byte, commit *types.Commit) {
+	cc, err := types.NewCompactCommit(commit)
+	if err != nil {
+		bs.db.Set(legacyKey, amino.MustMarshal(commit))
+		return
+	}
+	bs.db.Set(key, amino.MustMarshal(cc))
+}
+
 // SaveBlock persists the given block, blockParts, and seenCommit to the underlying db.
 // blockParts: Must be parts of the block
 // seenCommit: The +2/3 precommits that were seen which committed at height.
@@ -166,19 +225,17 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	}
 
 	// Save block commit (duplicate and separate from the Block)
-	blockCommitBytes := amino.MustMarshal(block.LastCommit)
-	bs.db.Set(calcBlockCommitKey(height-1), blockCommitBytes)
+	bs.saveCommit(calcBlockCommitKey(height-1), calcLegacyBlockCommitKey(height-1), block.LastCommit)
 
 	// Save seen commit (seen +2/3 precommits for block)
 	// NOTE: we can delete this at a later height
-	seenCommitBytes := amino.MustMarshal(seenCommit)
-	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
+	bs.saveCommit(calcSeenCommitKey(height), calcLegacySeenCommitKey(height), seenCommit)
 
 	// Save new BlockStoreStateJSON descriptor
-	BlockStoreStateJSON{Height: height}.Save(bs.db)
+	bs.mtx.Lock()
+	BlockStoreStateJSON{Height: height, Offloaded: bs.offloaded}.Save(bs.db)
 
 	// Done!
-	bs.mtx.Lock()
 	bs.height = height
 	bs.mtx.Unlock()
 
@@ -194,6 +251,111 @@ func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 	bs.db.Set(calcBlockPartKey(height, index), partBytes)
 }
 
+// OffloadBlocks moves the blocks below retainHeight to the cold store,
+// deleting them from the DB, and returns the last height offloaded.
+// The block of the last height is never offloaded, since its commit is only
+// saved with the next block.
+func (bs *BlockStore) OffloadBlocks(retainHeight int64) (int64, error) {
+	if bs.cold == nil {
+		return 0, errors.New("no cold store to offload blocks to")
+	}
+	if retainHeight > bs.Height() {
+		retainHeight = bs.Height()
+	}
+	for height := bs.Offloaded() + 1; height < retainHeight; height++ {
+		keys := bs.blockKeys(height)
+		cb := &coldBlock{Height: height}
+		for _, key := range keys {
+			cb.Keys = append(cb.Keys, key)
+			cb.Values = append(cb.Values, bs.db.Get(key))
+		}
+		bz, err := amino.Marshal(cb)
+		if err != nil {
+			return bs.Offloaded(), err
+		}
+		if err := bs.cold.Put(calcColdBlockKey(height), bz); err != nil {
+			return bs.Offloaded(), errors.Wrap(err, "error offloading block %d", height)
+		}
+
+		// Loaded from the cold store from now on.
+		bs.mtx.Lock()
+		bs.offloaded = height
+		BlockStoreStateJSON{Height: bs.height, Offloaded: height}.Save(bs.db)
+		bs.mtx.Unlock()
+
+		batch := bs.db.NewBatch()
+		for _, key := range keys {
+			batch.Delete(key)
+		}
+		batch.WriteSync()
+		batch.Close()
+	}
+	return bs.Offloaded(), nil
+}
+
+// Returns the keys of the block of height in the DB, e.g. none if it was
+// offloaded.
+func (bs *BlockStore) blockKeys(height int64) [][]byte {
+	bz := bs.db.Get(calcBlockMetaKey(height))
+	if len(bz) == 0 {
+		return nil
+	}
+	blockMeta := new(types.BlockMeta)
+	if err := amino.Unmarshal(bz, blockMeta); err != nil {
+		panic(errors.Wrap(err, "Error reading block meta"))
+	}
+	keys := [][]byte{calcBlockMetaKey(height)}
+	for i := 0; i < blockMeta.BlockID.PartsHeader.Total; i++ {
+		keys = append(keys, calcBlockPartKey(height, i))
+	}
+	keys = append(keys, calcBlockCommitKey(height), calcSeenCommitKey(height))
+	for _, key := range [][]byte{calcLegacyBlockCommitKey(height), calcLegacySeenCommitKey(height)} {
+		if bs.db.Has(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Returns the value of key, of the block of height, from the DB, or from
+// the cold store if offloaded.  A block which can't be fetched from the cold
+// store is not found.
+func (bs *BlockStore) get(height int64, key []byte) []byte {
+	if bz := bs.db.Get(key); len(bz) != 0 {
+		return bz
+	}
+	if bs.cold == nil || height > bs.Offloaded() {
+		return nil
+	}
+
+	bs.coldMtx.Lock()
+	defer bs.coldMtx.Unlock()
+	if bs.coldBlock == nil || bs.coldBlock.Height != height {
+		bz, err := bs.cold.Get(calcColdBlockKey(height))
+		if err != nil || bz == nil {
+			return nil
+		}
+		cb := new(coldBlock)
+		if err := amino.Unmarshal(bz, cb); err != nil {
+			panic(errors.Wrap(err, "Error reading offloaded block"))
+		}
+		bs.coldBlock = cb
+	}
+	for i, k := range bs.coldBlock.Keys {
+		if bytes.Equal(k, key) {
+			return bs.coldBlock.Values[i]
+		}
+	}
+	return nil
+}
+
+// The values of the keys of a block offloaded to the cold store.
+type coldBlock struct {
+	Height int64
+	Keys   [][]byte
+	Values [][]byte
+}
+
 //-----------------------------------------------------------------------------
 
 func calcBlockMetaKey(height int64) []byte {
@@ -205,20 +367,35 @@ func calcBlockPartKey(height int64, partIndex int) []byte {
 }
 
 func calcBlockCommitKey(height int64) []byte {
-	return []byte(fmt.Sprintf("C:%v", height))
+	return []byte(fmt.Sprintf("CC:%v", height))
 }
 
 func calcSeenCommitKey(height int64) []byte {
+	return []byte(fmt.Sprintf("SCC:%v", height))
+}
+
+// The keys of the commits stored in full by the previous versions.
+
+func calcLegacyBlockCommitKey(height int64) []byte {
+	return []byte(fmt.Sprintf("C:%v", height))
+}
+
+func calcLegacySeenCommitKey(height int64) []byte {
 	return []byte(fmt.Sprintf("SC:%v", height))
 }
 
+func calcColdBlockKey(height int64) string {
+	return fmt.Sprintf("blocks/%020d", height)
+}
+
 //-----------------------------------------------------------------------------
 
 var blockStoreKey = []byte("blockStore")
 
 // BlockStoreStateJSON is the block store state JSON structure.
 type BlockStoreStateJSON struct {
-	Height int64 `json:"height"`
+	Height    int64 `json:"height"`
+	Offloaded int64 `json:"offloaded"` // last height offloaded to the cold store
 }
 
 // Save persists the blockStore state to the database as JSON.
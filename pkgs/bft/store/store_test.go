@@ -28,7 +28,7 @@ type cleanupFunc func()
 
 // make a Commit with a single vote containing just the height and a timestamp
 func makeTestCommit(height int64, timestamp time.Time) *types.Commit {
-	commitSigs := []*types.CommitSig{{Height: height, Timestamp: timestamp}}
+	commitSigs := []*types.CommitSig{{Type: types.PrecommitType, Height: height, Timestamp: timestamp}}
 	return types.NewCommit(types.BlockID{}, commitSigs)
 }
 
@@ -205,7 +205,7 @@ func TestBlockStoreSaveLoadBlock(t *testing.T) {
 			parts:             validPartSet,
 			seenCommit:        seenCommit1,
 			corruptCommitInDB: true, // Corrupt the DB's commit entry
-			wantPanic:         "unmarshal to types.Commit failed",
+			wantPanic:         "unmarshal to types.CompactCommit failed",
 		},
 
 		{
@@ -231,7 +231,7 @@ func TestBlockStoreSaveLoadBlock(t *testing.T) {
 			seenCommit: seenCommit1,
 
 			corruptSeenCommitInDB: true,
-			wantPanic:             "unmarshal to types.Commit failed",
+			wantPanic:             "unmarshal to types.CompactCommit failed",
 		},
 
 		{
@@ -383,6 +383,29 @@ func TestLoadBlockMeta(t *testing.T) {
 		"expecting successful retrieval of previously saved blockMeta")
 }
 
+func TestLoadCommit(t *testing.T) {
+	bs, db := freshBlockStore()
+	height := int64(10)
+	commit := makeTestCommit(height, tmtime.Now())
+
+	// 1. The commits are stored compactly.
+	bs.saveCommit(calcSeenCommitKey(height), calcLegacySeenCommitKey(height), commit)
+	require.False(t, db.Has(calcLegacySeenCommitKey(height)))
+	require.Equal(t, commit.Hash(), bs.LoadSeenCommit(height).Hash())
+
+	// 2. The commits stored in full by the previous versions are loaded.
+	db.Delete(calcSeenCommitKey(height))
+	db.Set(calcLegacyBlockCommitKey(height), amino.MustMarshal(commit))
+	require.Nil(t, bs.LoadSeenCommit(height))
+	require.Equal(t, commit.Hash(), bs.LoadBlockCommit(height).Hash())
+
+	// 3. The commits which can't be compacted are stored in full.
+	invalid := types.NewCommit(types.BlockID{}, []*types.CommitSig{{Height: height}})
+	bs.saveCommit(calcSeenCommitKey(height), calcLegacySeenCommitKey(height), invalid)
+	require.False(t, db.Has(calcSeenCommitKey(height)))
+	require.Equal(t, invalid.Hash(), bs.LoadSeenCommit(height).Hash())
+}
+
 func TestBlockFetchAtHeight(t *testing.T) {
 	state, bs, cleanup := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
 	defer cleanup()
@@ -435,3 +458,55 @@ func newBlock(hdr types.Header, lastCommit *types.Commit) *types.Block {
 		LastCommit: lastCommit,
 	}
 }
+
+type memColdStore map[string][]byte
+
+func (m memColdStore) Get(key string) ([]byte, error)     { return m[key], nil }
+func (m memColdStore) Put(key string, value []byte) error { m[key] = value; return nil }
+
+func TestOffloadBlocks(t *testing.T) {
+	state, bs, cleanup := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+	defer cleanup()
+
+	_, err := bs.OffloadBlocks(1)
+	require.Error(t, err) // no cold store.
+	cold := memColdStore{}
+	bs.SetColdStore(cold)
+
+	var blocks []*types.Block
+	for height := int64(1); height <= 5; height++ {
+		block := makeBlock(height, state, new(types.Commit))
+		bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(height, tmtime.Now()))
+		blocks = append(blocks, block)
+	}
+
+	offloaded, err := bs.OffloadBlocks(4)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), offloaded)
+	assert.Equal(t, int64(3), bs.Offloaded())
+	assert.Len(t, cold, 3)
+	assert.Nil(t, bs.db.Get(calcBlockMetaKey(3)))
+	assert.NotNil(t, bs.db.Get(calcBlockMetaKey(4)))
+
+	// fetched from the cold store.
+	for _, block := range blocks {
+		loaded := bs.LoadBlock(block.Height)
+		require.NotNil(t, loaded, "height %d", block.Height)
+		assert.Equal(t, block.Hash(), loaded.Hash())
+		assert.NotNil(t, bs.LoadSeenCommit(block.Height))
+	}
+	assert.NotNil(t, bs.LoadBlockPart(2, 0))
+
+	// the last height is never offloaded, and the offload resumes.
+	offloaded, err = bs.OffloadBlocks(100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), offloaded)
+
+	// reloaded.
+	bs2 := NewBlockStore(bs.db)
+	assert.Equal(t, int64(5), bs2.Height())
+	assert.Equal(t, int64(4), bs2.Offloaded())
+	assert.Nil(t, bs2.LoadBlock(2)) // without the cold store.
+	bs2.SetColdStore(cold)
+	assert.Equal(t, blocks[1].Hash(), bs2.LoadBlock(2).Hash())
+}
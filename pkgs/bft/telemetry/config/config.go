@@ -0,0 +1,91 @@
+package config
+
+import (
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+//-----------------------------------------------------------------------------
+// TelemetryConfig
+
+// TelemetryConfig defines the configuration options of the telemetry of the
+// node: the periodic reports of its anonymized health to an endpoint, which
+// is opt-in, and the status file for the local monitoring agents.
+type TelemetryConfig struct {
+	RootDir string `toml:"home"`
+
+	// URL the anonymized health reports of the node are posted to; empty
+	// disables the reports.
+	Endpoint string `toml:"endpoint"`
+
+	// Interval of the reports.
+	Interval time.Duration `toml:"interval"`
+
+	// Timeout of a report request.
+	Timeout time.Duration `toml:"timeout"`
+
+	// File of the status of the node in JSON, updated on every block; empty
+	// disables it.
+	StatusPath string `toml:"status_file"`
+}
+
+// DefaultTelemetryConfig returns a default configuration of the telemetry,
+// disabled.
+func DefaultTelemetryConfig() *TelemetryConfig {
+	return &TelemetryConfig{
+		Endpoint:   "",
+		Interval:   1 * time.Hour,
+		Timeout:    10 * time.Second,
+		StatusPath: "",
+	}
+}
+
+// TestTelemetryConfig returns a configuration of the telemetry for testing.
+func TestTelemetryConfig() *TelemetryConfig {
+	cfg := DefaultTelemetryConfig()
+	cfg.Interval = 100 * time.Millisecond
+	cfg.Timeout = 1 * time.Second
+	return cfg
+}
+
+// StatusFile returns the full path of the status file, or "" if none.
+func (cfg *TelemetryConfig) StatusFile() string {
+	if cfg.StatusPath == "" {
+		return ""
+	}
+	if filepath.IsAbs(cfg.StatusPath) {
+		return cfg.StatusPath
+	}
+	return filepath.Join(cfg.RootDir, cfg.StatusPath)
+}
+
+// ReportsEnabled returns true if the health reports are sent.
+func (cfg *TelemetryConfig) ReportsEnabled() bool {
+	return cfg.Endpoint != ""
+}
+
+// Enabled returns true if the reports or the status file are enabled.
+func (cfg *TelemetryConfig) Enabled() bool {
+	return cfg.ReportsEnabled() || cfg.StatusPath != ""
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *TelemetryConfig) ValidateBasic() error {
+	if cfg.Endpoint != "" {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("endpoint must be an http(s) URL, got %q", cfg.Endpoint)
+		}
+	}
+	if cfg.Interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("timeout can't be negative")
+	}
+	return nil
+}
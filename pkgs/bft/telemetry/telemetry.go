@@ -0,0 +1,233 @@
+// Package telemetry reports the health of the node: periodically, and
+// anonymized, to the endpoint of the config if the operator opts in, and on
+// every block to a local status file for the external monitoring agents.
+package telemetry
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	cfg "github.com/gnolang/gno/pkgs/bft/telemetry/config"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/events"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/service"
+)
+
+const listenerID = "telemetry"
+
+// NodeInfo is the static information of the node reported.
+type NodeInfo struct {
+	Version string
+	ChainID string
+	NodeID  string
+	Moniker string
+}
+
+// Report is the JSON body of the reports to the endpoint.  It identifies
+// neither the node nor its operator: Instance is random on each start, so
+// that the reports of a run can be told apart from those of other nodes.
+type Report struct {
+	Instance   string    `json:"instance"`
+	Version    string    `json:"version"`
+	ChainID    string    `json:"chain_id"`
+	Height     int64     `json:"height"`
+	Peers      int       `json:"peers"`
+	CatchingUp bool      `json:"catching_up"`
+	Time       time.Time `json:"time"`
+}
+
+// Status is the JSON of the status file: the report of the node, with the
+// information identifying it, and its last block.
+type Status struct {
+	Report
+	NodeID          string    `json:"node_id"`
+	Moniker         string    `json:"moniker"`
+	LatestBlockHash string    `json:"latest_block_hash"`
+	LatestBlockTime time.Time `json:"latest_block_time"`
+}
+
+// Service is the service of the telemetry of the node.  The headers of the
+// new blocks are received from the event switch; the status file is then
+// written by its own routine, so that a slow disk doesn't block the switch.
+type Service struct {
+	service.BaseService
+
+	config     *cfg.TelemetryConfig
+	evsw       events.EventSwitch
+	info       NodeInfo
+	peers      func() int
+	catchingUp func() bool
+	client     *http.Client
+	instance   string
+
+	mtx    sync.Mutex
+	header types.Header // of the last block
+
+	blocks chan struct{} // signals a new block
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewService returns the telemetry service of config, reporting the health
+// of the node of info, with its number of peers and sync status returned by
+// peers and catchingUp, and its blocks from the events of evsw.
+func NewService(config *cfg.TelemetryConfig, evsw events.EventSwitch, info NodeInfo,
+	peers func() int, catchingUp func() bool,
+) *Service {
+	s := &Service{
+		config:     config,
+		evsw:       evsw,
+		info:       info,
+		peers:      peers,
+		catchingUp: catchingUp,
+		client:     &http.Client{Timeout: config.Timeout},
+		instance:   hex.EncodeToString(crypto.CRandBytes(8)),
+		blocks:     make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}
+	s.BaseService = *service.NewBaseService(nil, "Telemetry", s)
+	return s
+}
+
+func (s *Service) OnStart() error {
+	if s.config.StatusFile() != "" {
+		s.wg.Add(1)
+		go s.statusRoutine()
+	}
+	if s.config.ReportsEnabled() {
+		s.wg.Add(1)
+		go s.reportRoutine()
+	}
+	s.evsw.AddListener(listenerID, s.onEvent)
+	return nil
+}
+
+func (s *Service) OnStop() {
+	s.evsw.RemoveListener(listenerID)
+	close(s.quit)
+	s.wg.Wait()
+}
+
+// Records the header of a new block.  It is called synchronously by the
+// event switch, so it must not block.
+func (s *Service) onEvent(event events.Event) {
+	ev, ok := event.(types.EventNewBlockHeader)
+	if !ok {
+		return
+	}
+	s.mtx.Lock()
+	s.header = ev.Header
+	s.mtx.Unlock()
+	select {
+	case s.blocks <- struct{}{}:
+	default: // already signaled.
+	}
+}
+
+// Report returns the current report of the node.
+func (s *Service) Report() Report {
+	s.mtx.Lock()
+	height := s.header.Height
+	s.mtx.Unlock()
+	return s.report(height)
+}
+
+func (s *Service) report(height int64) Report {
+	return Report{
+		Instance:   s.instance,
+		Version:    s.info.Version,
+		ChainID:    s.info.ChainID,
+		Height:     height,
+		Peers:      s.peers(),
+		CatchingUp: s.catchingUp(),
+		Time:       time.Now().UTC(),
+	}
+}
+
+// Status returns the current status of the node.
+func (s *Service) Status() Status {
+	s.mtx.Lock()
+	header := s.header
+	s.mtx.Unlock()
+	status := Status{
+		Report:          s.report(header.Height),
+		NodeID:          s.info.NodeID,
+		Moniker:         s.info.Moniker,
+		LatestBlockTime: header.Time,
+	}
+	if header.Height > 0 {
+		status.LatestBlockHash = hex.EncodeToString(header.Hash())
+	}
+	return status
+}
+
+// Writes the status file on each new block, until the service stops.
+func (s *Service) statusRoutine() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.blocks:
+			if err := s.writeStatus(); err != nil {
+				s.Logger.Error("Could not write telemetry status", "path", s.config.StatusFile(), "err", err)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Service) writeStatus() error {
+	bz, err := json.MarshalIndent(s.Status(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return osm.WriteFileAtomic(s.config.StatusFile(), bz, 0o644)
+}
+
+// Sends a report every interval, until the service stops.  The reports
+// failing are only logged: they are not retried.
+func (s *Service) reportRoutine() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.sendReport(); err != nil {
+				s.Logger.Info("Could not send telemetry report", "endpoint", s.config.Endpoint, "err", err)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Service) sendReport() error {
+	body, err := json.Marshal(s.Report())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.New("unexpected status %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package telemetry
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/gnolang/gno/pkgs/bft/telemetry/config"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/events"
+)
+
+func TestService(t *testing.T) {
+	var mtx sync.Mutex
+	var received []Report
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var report Report
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		mtx.Lock()
+		defer mtx.Unlock()
+		received = append(received, report)
+	}))
+	defer srv.Close()
+
+	config := cfg.TestTelemetryConfig()
+	config.RootDir = t.TempDir()
+	config.Endpoint = srv.URL
+	config.StatusPath = "status.json"
+	require.NoError(t, config.ValidateBasic())
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+	info := NodeInfo{Version: "v1.0.0", ChainID: "dev", NodeID: "node-id", Moniker: "moniker"}
+	s := NewService(config, evsw, info, func() int { return 3 }, func() bool { return false })
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	header := types.Header{ChainID: "dev", Height: 7, Time: time.Unix(100, 0).UTC()}
+	evsw.FireEvent(types.EventNewBlockHeader{Header: header})
+
+	// the status file, with the last block.
+	path := filepath.Join(config.RootDir, config.StatusPath)
+	var status Status
+	require.Eventually(t, func() bool {
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		require.NoError(t, json.Unmarshal(bz, &status))
+		return status.Height == 7
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "node-id", status.NodeID)
+	assert.Equal(t, "moniker", status.Moniker)
+	assert.Equal(t, hex.EncodeToString(header.Hash()), status.LatestBlockHash)
+	assert.Equal(t, header.Time, status.LatestBlockTime)
+	assert.Equal(t, 3, status.Peers)
+
+	// the reports, anonymized.
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(received) > 0 && received[len(received)-1].Height == 7
+	}, 5*time.Second, 10*time.Millisecond)
+	mtx.Lock()
+	report := received[len(received)-1]
+	mtx.Unlock()
+	assert.Equal(t, status.Instance, report.Instance)
+	assert.Equal(t, "v1.0.0", report.Version)
+	assert.Equal(t, "dev", report.ChainID)
+	assert.Equal(t, 3, report.Peers)
+	assert.False(t, report.CatchingUp)
+}
+
+func TestTelemetryConfig(t *testing.T) {
+	config := cfg.DefaultTelemetryConfig()
+	require.NoError(t, config.ValidateBasic())
+	assert.False(t, config.Enabled())
+
+	config.StatusPath = "status.json"
+	assert.True(t, config.Enabled())
+	assert.False(t, config.ReportsEnabled())
+
+	for _, endpoint := range []string{"localhost:8080", "ftp://example.com", "https://"} {
+		config.Endpoint = endpoint
+		assert.Error(t, config.ValidateBasic(), endpoint)
+	}
+	config.Endpoint = "https://telemetry.example.com/report"
+	require.NoError(t, config.ValidateBasic())
+	config.Interval = 0
+	assert.Error(t, config.ValidateBasic())
+}
@@ -3,7 +3,10 @@ package types
 import (
 	"time"
 
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
 	tmtime "github.com/gnolang/gno/pkgs/bft/types/time"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/tmhash"
 )
 
 // Canonical* wraps the structs in types for amino encoding them for use in SignBytes / the Signable interface.
@@ -40,6 +43,27 @@ type CanonicalVote struct {
 	ChainID   string
 }
 
+// CanonicalChallenge starts with a string, unlike CanonicalProposal and
+// CanonicalVote, so that its sign bytes can't be theirs.
+type CanonicalChallenge struct {
+	Type             string // "challenge"
+	Nonce            []byte
+	NodeID           crypto.ID
+	ValidatorAddress crypto.Address
+	ChainID          string
+}
+
+// CanonicalQuery starts with a string, like CanonicalChallenge, and is of a
+// different type.
+type CanonicalQuery struct {
+	Type         string // "query"
+	Path         string
+	DataHash     []byte
+	Height       int64
+	ResponseHash []byte
+	ChainID      string
+}
+
 //-----------------------------------
 // Canonicalize the structs
 
@@ -80,6 +104,30 @@ func CanonicalizeVote(chainID string, vote *Vote) CanonicalVote {
 	}
 }
 
+func CanonicalizeChallenge(chainID string, challenge *Challenge) CanonicalChallenge {
+	cc := CanonicalChallenge{
+		Type:    "challenge",
+		Nonce:   challenge.Nonce,
+		NodeID:  challenge.NodeID,
+		ChainID: chainID,
+	}
+	if challenge.ValidatorPubKey != nil {
+		cc.ValidatorAddress = challenge.ValidatorPubKey.Address()
+	}
+	return cc
+}
+
+func CanonicalizeQuery(chainID string, path string, data []byte, res abci.ResponseQuery) CanonicalQuery {
+	return CanonicalQuery{
+		Type:         "query",
+		Path:         path,
+		DataHash:     tmhash.Sum(data),
+		Height:       res.Height,
+		ResponseHash: queryResponseHash(res),
+		ChainID:      chainID,
+	}
+}
+
 // CanonicalTime can be used to stringify time in a canonical way.
 func CanonicalTime(t time.Time) string {
 	// Note that sending time over amino resets it to
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+)
+
+// MaxChallengeNonceSize is the maximum size of the nonce of a Challenge.
+const MaxChallengeNonceSize = 256
+
+// Challenge is a nonce chosen by a verifier, e.g. a registry of nodes, and
+// signed by a node with its node key, and optionally with its validator key,
+// to prove that the node controls them.
+type Challenge struct {
+	Nonce              []byte        `json:"nonce"`
+	NodeID             crypto.ID     `json:"node_id"`
+	NodePubKey         crypto.PubKey `json:"node_pub_key"`
+	NodeSignature      []byte        `json:"node_signature"`
+	ValidatorPubKey    crypto.PubKey `json:"validator_pub_key"` // nil if not signed by the validator key
+	ValidatorSignature []byte        `json:"validator_signature"`
+}
+
+// SignBytes returns the bytes signed by both the node and validator keys.
+// They commit to both keys, and can't be those of a vote or a proposal.
+func (c *Challenge) SignBytes(chainID string) []byte {
+	bz, err := amino.MarshalSized(CanonicalizeChallenge(chainID, c))
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// Verify returns an error if the challenge is not of nonce on chainID, or if
+// its signatures are not valid.
+func (c *Challenge) Verify(chainID string, nonce []byte) error {
+	if !bytes.Equal(c.Nonce, nonce) {
+		return errors.New("Invalid nonce")
+	}
+	if c.NodePubKey == nil {
+		return errors.New("Missing node public key")
+	}
+	if c.NodePubKey.Address().ID() != c.NodeID {
+		return fmt.Errorf("Node public key is not that of %s", c.NodeID)
+	}
+	signBytes := c.SignBytes(chainID)
+	if !c.NodePubKey.VerifyBytes(signBytes, c.NodeSignature) {
+		return errors.New("Invalid node signature")
+	}
+	if c.ValidatorPubKey != nil && !c.ValidatorPubKey.VerifyBytes(signBytes, c.ValidatorSignature) {
+		return errors.New("Invalid validator signature")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+)
+
+func TestChallenge(t *testing.T) {
+	nodeKey := ed25519.GenPrivKey()
+	pv := NewMockPV()
+	nonce := []byte("nonce")
+
+	sign := func(c *Challenge) {
+		sig, err := nodeKey.Sign(c.SignBytes("chain"))
+		require.NoError(t, err)
+		c.NodeSignature = sig
+	}
+	newChallenge := func() *Challenge {
+		return &Challenge{
+			Nonce:      nonce,
+			NodeID:     nodeKey.PubKey().Address().ID(),
+			NodePubKey: nodeKey.PubKey(),
+		}
+	}
+
+	// signed by the node only.
+	c := newChallenge()
+	sign(c)
+	require.NoError(t, c.Verify("chain", nonce))
+	assert.Error(t, c.Verify("other-chain", nonce))
+	assert.Error(t, c.Verify("chain", []byte("other-nonce")))
+
+	// signed by both keys.
+	c = newChallenge()
+	require.NoError(t, pv.SignChallenge("chain", c))
+	sign(c)
+	require.NoError(t, c.Verify("chain", nonce))
+	assert.Equal(t, pv.GetPubKey(), c.ValidatorPubKey)
+
+	// the validator signature is bound to the validator key.
+	c.ValidatorPubKey = NewMockPV().GetPubKey()
+	assert.Error(t, c.Verify("chain", nonce))
+
+	// the node ID must be that of the node key.
+	c = newChallenge()
+	c.NodeID = ed25519.GenPrivKey().PubKey().Address().ID()
+	sign(c)
+	assert.Error(t, c.Verify("chain", nonce))
+
+	// sign bytes can't be those of a vote or a proposal.
+	vote := &Vote{Type: PrecommitType, Height: 1}
+	proposal := &Proposal{Type: ProposalType, Height: 1}
+	bz := newChallenge().SignBytes("chain")
+	assert.NotEqual(t, vote.SignBytes("chain")[1], bz[1])
+	assert.NotEqual(t, proposal.SignBytes("chain")[1], bz[1])
+}
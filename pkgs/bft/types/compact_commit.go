@@ -0,0 +1,142 @@
+package types
+
+import (
+	"time"
+
+	"github.com/gnolang/gno/pkgs/bitarray"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// CompactCommit is the compact representation of a Commit, in the block
+// store and over RPC, for the chains with large validator sets.
+//
+// The fields common to the precommits (type, height, round and block ID)
+// are stored once, the precommits present are marked by validator index in
+// a bit array, and the addresses, timestamps and signatures of the signers
+// are batched in lists, in the order of the validators.  The few precommits
+// which are not for the block of the commit, e.g. for nil, keep their block
+// ID aside.
+//
+// The conversion is lossless: Commit() returns the commit it was made of,
+// with the same hash.
+type CompactCommit struct {
+	BlockID    BlockID            `json:"block_id"`
+	Height     int64              `json:"height"`
+	Round      int                `json:"round"`
+	Signers    *bitarray.BitArray `json:"signers"`    // by validator index
+	Addresses  []Address          `json:"addresses"`  // of the signers
+	Timestamps []time.Time        `json:"timestamps"` // of the signers
+	Signatures [][]byte           `json:"signatures"` // of the signers
+	Others     []CommitOtherVote  `json:"others"`     // by validator index
+}
+
+// CommitOtherVote is the block ID of a precommit of a CompactCommit which is
+// not for the block of the commit.
+type CommitOtherVote struct {
+	ValidatorIndex int     `json:"validator_index"`
+	BlockID        BlockID `json:"block_id"`
+}
+
+// NewCompactCommit returns the compact representation of commit, or an
+// error if its precommits are not those of one height and round, in the
+// order of the validators.
+func NewCompactCommit(commit *Commit) (*CompactCommit, error) {
+	if commit == nil {
+		return nil, nil
+	}
+	cc := &CompactCommit{
+		BlockID: commit.BlockID,
+		Height:  commit.Height(),
+		Round:   commit.Round(),
+		Signers: bitarray.NewBitArray(len(commit.Precommits)),
+	}
+	for i, precommit := range commit.Precommits {
+		if precommit == nil {
+			continue
+		}
+		switch {
+		case precommit.Type != PrecommitType:
+			return nil, errors.New("precommit %d is a %v", i, precommit.Type)
+		case precommit.Height != cc.Height || precommit.Round != cc.Round:
+			return nil, errors.New("precommit %d is for %d/%d, not %d/%d",
+				i, precommit.Height, precommit.Round, cc.Height, cc.Round)
+		case precommit.ValidatorIndex != i:
+			return nil, errors.New("precommit %d has validator index %d", i, precommit.ValidatorIndex)
+		}
+		cc.Signers.SetIndex(i, true)
+		cc.Addresses = append(cc.Addresses, precommit.ValidatorAddress)
+		cc.Timestamps = append(cc.Timestamps, precommit.Timestamp)
+		cc.Signatures = append(cc.Signatures, precommit.Signature)
+		if !precommit.BlockID.Equals(commit.BlockID) {
+			cc.Others = append(cc.Others, CommitOtherVote{ValidatorIndex: i, BlockID: precommit.BlockID})
+		}
+	}
+	return cc, nil
+}
+
+// ValidateBasic checks the consistency of the lists with the signers, e.g.
+// of a compact commit received over RPC, before calling Commit().
+func (cc *CompactCommit) ValidateBasic() error {
+	if cc.Height < 0 {
+		return errors.New("negative Height")
+	}
+	if cc.Round < 0 {
+		return errors.New("negative Round")
+	}
+	if cc.Signers.Size() > MaxVotesCount {
+		return errors.New("too many signers: %d, max: %d", cc.Signers.Size(), MaxVotesCount)
+	}
+	signers := 0
+	for i := 0; i < cc.Signers.Size(); i++ {
+		if cc.Signers.GetIndex(i) {
+			signers++
+		}
+	}
+	if len(cc.Addresses) != signers || len(cc.Timestamps) != signers || len(cc.Signatures) != signers {
+		return errors.New("expected %d addresses, timestamps and signatures, got %d, %d and %d",
+			signers, len(cc.Addresses), len(cc.Timestamps), len(cc.Signatures))
+	}
+	for j, other := range cc.Others {
+		if !cc.Signers.GetIndex(other.ValidatorIndex) {
+			return errors.New("other vote %d of validator %d which didn't sign", j, other.ValidatorIndex)
+		}
+		if j > 0 && other.ValidatorIndex <= cc.Others[j-1].ValidatorIndex {
+			return errors.New("other votes not sorted by validator index")
+		}
+	}
+	return nil
+}
+
+// Commit returns the commit of cc, which must be valid.
+func (cc *CompactCommit) Commit() *Commit {
+	if cc == nil {
+		return nil
+	}
+	var precommits []*CommitSig
+	if size := cc.Signers.Size(); size > 0 {
+		precommits = make([]*CommitSig, size)
+	}
+	signer, other := 0, 0
+	for i := range precommits {
+		if !cc.Signers.GetIndex(i) {
+			continue
+		}
+		blockID := cc.BlockID
+		if other < len(cc.Others) && cc.Others[other].ValidatorIndex == i {
+			blockID = cc.Others[other].BlockID
+			other++
+		}
+		precommits[i] = &CommitSig{
+			Type:             PrecommitType,
+			Height:           cc.Height,
+			Round:            cc.Round,
+			BlockID:          blockID,
+			Timestamp:        cc.Timestamps[signer],
+			ValidatorAddress: cc.Addresses[signer],
+			ValidatorIndex:   i,
+			Signature:        cc.Signatures[signer],
+		}
+		signer++
+	}
+	return NewCommit(cc.BlockID, precommits)
+}
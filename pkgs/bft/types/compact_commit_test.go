@@ -0,0 +1,105 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	tmtime "github.com/gnolang/gno/pkgs/bft/types/time"
+)
+
+func TestCompactCommit(t *testing.T) {
+	blockID := makeBlockID([]byte("blockhash"), 1000, []byte("partshash"))
+	blockID2 := makeBlockID([]byte("blockhash2"), 1000, []byte("partshash"))
+	height, round := int64(2), 1
+
+	// 70 precommits for the block, 20 for another and 5 for nil, and 5
+	// absent.
+	voteSet, valSet, vals := randVoteSet(height, round, PrecommitType, 100, 1)
+	for i := 0; i < 95; i++ {
+		vote := &Vote{
+			ValidatorAddress: vals[i].GetPubKey().Address(),
+			ValidatorIndex:   i,
+			Height:           height,
+			Round:            round,
+			Type:             PrecommitType,
+			BlockID:          blockID,
+			Timestamp:        tmtime.Now(),
+		}
+		switch {
+		case i >= 90:
+			vote.BlockID = BlockID{}
+		case i >= 70:
+			vote.BlockID = blockID2
+		}
+		_, err := signAddVote(vals[i], vote, voteSet)
+		require.NoError(t, err)
+	}
+	commit := voteSet.MakeCommit()
+
+	cc, err := NewCompactCommit(commit)
+	require.NoError(t, err)
+	assert.Len(t, cc.Addresses, 95)
+	assert.Len(t, cc.Others, 25)
+	require.NoError(t, cc.ValidateBasic())
+
+	// Lossless, and smaller.
+	bz := amino.MustMarshal(cc)
+	assert.Less(t, len(bz), len(amino.MustMarshal(commit)))
+	cc2 := new(CompactCommit)
+	require.NoError(t, amino.Unmarshal(bz, cc2))
+	commit2 := cc2.Commit()
+	assert.Equal(t, commit.Hash(), commit2.Hash())
+	assert.Equal(t, amino.MustMarshal(commit), amino.MustMarshal(commit2))
+	assert.NoError(t, valSet.VerifyCommit(voteSet.ChainID(), blockID, height, commit2))
+
+	// The empty commit of the first block.
+	cc, err = NewCompactCommit(new(Commit))
+	require.NoError(t, err)
+	assert.Equal(t, amino.MustMarshal(new(Commit)), amino.MustMarshal(cc.Commit()))
+}
+
+func TestCompactCommitValidateBasic(t *testing.T) {
+	testCases := []struct {
+		testName       string
+		malleateCommit func(*CompactCommit)
+		expectErr      bool
+	}{
+		{"Valid Commit", func(cc *CompactCommit) {}, false},
+		{"Negative Height", func(cc *CompactCommit) { cc.Height = -1 }, true},
+		{"Negative Round", func(cc *CompactCommit) { cc.Round = -1 }, true},
+		{"Missing Address", func(cc *CompactCommit) { cc.Addresses = cc.Addresses[1:] }, true},
+		{"Missing Signature", func(cc *CompactCommit) { cc.Signatures = cc.Signatures[1:] }, true},
+		{"Other Vote Of No Signer", func(cc *CompactCommit) {
+			cc.Signers.SetIndex(0, false)
+			cc.Addresses, cc.Timestamps, cc.Signatures = cc.Addresses[1:], cc.Timestamps[1:], cc.Signatures[1:]
+			cc.Others = []CommitOtherVote{{ValidatorIndex: 0}}
+		}, true},
+		{"Unsorted Other Votes", func(cc *CompactCommit) {
+			cc.Others = []CommitOtherVote{{ValidatorIndex: 2}, {ValidatorIndex: 1}}
+		}, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			cc, err := NewCompactCommit(randCommit())
+			require.NoError(t, err)
+			tc.malleateCommit(cc)
+			assert.Equal(t, tc.expectErr, cc.ValidateBasic() != nil, "Validate Basic had an unexpected result")
+		})
+	}
+}
+
+func TestNewCompactCommitInvalid(t *testing.T) {
+	commit := randCommit()
+	commit.Precommits[1].ValidatorIndex = 0
+	_, err := NewCompactCommit(commit)
+	assert.Error(t, err)
+
+	commit = randCommit()
+	commit.Precommits[1].Type = PrevoteType
+	_, err = NewCompactCommit(commit)
+	assert.Error(t, err)
+}
@@ -10,12 +10,16 @@ type TMEvent interface {
 	events.Event
 }
 
-func (_ EventNewBlock) AssertEvent()            {}
-func (_ EventNewBlockHeader) AssertEvent()      {}
-func (_ EventTx) AssertEvent()                  {}
-func (_ EventVote) AssertEvent()                {}
-func (_ EventString) AssertEvent()              {}
-func (_ EventValidatorSetUpdates) AssertEvent() {}
+func (_ EventNewBlock) AssertEvent()               {}
+func (_ EventNewBlockHeader) AssertEvent()         {}
+func (_ EventNewSignedHeader) AssertEvent()        {}
+func (_ EventTx) AssertEvent()                     {}
+func (_ EventTxRejected) AssertEvent()             {}
+func (_ EventTxEvicted) AssertEvent()              {}
+func (_ EventVote) AssertEvent()                   {}
+func (_ EventString) AssertEvent()                 {}
+func (_ EventValidatorSetUpdates) AssertEvent()    {}
+func (_ EventConsensusParamsUpdates) AssertEvent() {}
 
 // Most event messages are basic types (a block, a transaction)
 // but some (an input to a call tx or a receive) are more exotic
@@ -35,11 +39,38 @@ type EventNewBlockHeader struct {
 	ResultEndBlock   abci.ResponseEndBlock   `json:"result_end_block"`
 }
 
+// EventNewSignedHeader is fired once a block is committed, with its header
+// and the commit of the precommits seen by the node, from which light
+// clients verify the header with the validator set of its ValidatorsHash.
+type EventNewSignedHeader struct {
+	Header Header  `json:"header"`
+	Commit *Commit `json:"commit"`
+}
+
 // All txs fire EventTx
 type EventTx struct {
 	Result TxResult `json:"result"`
 }
 
+// EventTxRejected is fired when a tx is rejected from the mempool, with the
+// reason of the rejection (see the Reason constants of the mempool) and its
+// error.
+type EventTxRejected struct {
+	Tx     Tx     `json:"tx"`
+	Reason string `json:"reason"`
+	Error  string `json:"error"`
+}
+
+// EventTxEvicted is fired when a tx of the mempool is evicted from it, as
+// it is no longer valid after the block of height, with the reason of the
+// eviction (see the Reason constants of the mempool) and its error.
+type EventTxEvicted struct {
+	Tx     Tx     `json:"tx"`
+	Height int64  `json:"height"`
+	Reason string `json:"reason"`
+	Error  string `json:"error"`
+}
+
 type EventVote struct {
 	Vote *Vote `json:"vote"`
 }
@@ -49,3 +80,11 @@ type EventString string
 type EventValidatorSetUpdates struct {
 	ValidatorUpdates []abci.ValidatorUpdate `json:"validator_updates"`
 }
+
+// EventConsensusParamsUpdates is fired when the application updates the
+// consensus params in EndBlock, with the params from Height, the height
+// following the block.
+type EventConsensusParamsUpdates struct {
+	Height          int64                `json:"height"`
+	ConsensusParams abci.ConsensusParams `json:"consensus_params"`
+}
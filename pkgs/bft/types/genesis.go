@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"time"
@@ -36,6 +37,7 @@ type GenesisValidator struct {
 type GenesisDoc struct {
 	GenesisTime     time.Time            `json:"genesis_time"`
 	ChainID         string               `json:"chain_id"`
+	Bech32Prefix    string               `json:"bech32_prefix,omitempty"` // of the addresses, "g" if empty
 	ConsensusParams abci.ConsensusParams `json:"consensus_params,omitempty"`
 	Validators      []GenesisValidator   `json:"validators,omitempty"`
 	AppHash         []byte               `json:"app_hash"`
@@ -70,6 +72,11 @@ func (genDoc *GenesisDoc) ValidateAndComplete() error {
 	if len(genDoc.ChainID) > MaxChainIDLen {
 		return errors.New("chain_id in genesis doc is too long (max: %d)", MaxChainIDLen)
 	}
+	if genDoc.Bech32Prefix != "" {
+		if err := crypto.ValidateBech32Prefix(genDoc.Bech32Prefix); err != nil {
+			return errors.Wrap(err, "invalid bech32_prefix in genesis doc")
+		}
+	}
 
 	// Start from defaults and fill in consensus params from GenesisDoc.
 	genDoc.ConsensusParams = DefaultConsensusParams().Update(genDoc.ConsensusParams)
@@ -113,6 +120,22 @@ func GenesisDocFromJSON(jsonBlob []byte) (*GenesisDoc, error) {
 	return &genDoc, err
 }
 
+// GenesisBech32PrefixFromJSON returns the Bech32 prefix of the addresses of
+// the genesis doc of jsonBlob, to set with crypto.SetBech32Prefix before the
+// genesis doc, whose app state may contain addresses, is unmarshalled.
+func GenesisBech32PrefixFromJSON(jsonBlob []byte) (string, error) {
+	var genDoc struct {
+		Bech32Prefix string `json:"bech32_prefix"`
+	}
+	if err := json.Unmarshal(jsonBlob, &genDoc); err != nil {
+		return "", err
+	}
+	if genDoc.Bech32Prefix == "" {
+		return crypto.Bech32AddrPrefix, nil
+	}
+	return genDoc.Bech32Prefix, nil
+}
+
 // GenesisDocFromFile reads JSON data from a file and unmarshalls it into a GenesisDoc.
 func GenesisDocFromFile(genDocFile string) (*GenesisDoc, error) {
 	jsonBlob, err := ioutil.ReadFile(genDocFile)
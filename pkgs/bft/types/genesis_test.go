@@ -28,6 +28,8 @@ func TestGenesisBad(t *testing.T) {
 		[]byte(`{"chain_id": "Lorem ipsum dolor sit amet, consectetuer adipiscing", "validators": [{"pub_key":{"@type":"/tm.PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`),
 		// wrong address
 		[]byte(`{"chain_id":"mychain", "validators":[{"address": "A", "pub_key":{"@type":"/tm.PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`),
+		// invalid bech32_prefix
+		[]byte(`{"chain_id":"mychain", "bech32_prefix": "G-1", "validators":[{"pub_key":{"@type":"/tm.PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`),
 	}
 
 	for _, testCase := range testCases {
@@ -86,6 +88,19 @@ func TestGenesisGood(t *testing.T) {
 	}
 }
 
+func TestGenesisBech32PrefixFromJSON(t *testing.T) {
+	prefix, err := GenesisBech32PrefixFromJSON([]byte(`{"chain_id":"mychain","bech32_prefix":"other"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "other", prefix)
+
+	prefix, err = GenesisBech32PrefixFromJSON([]byte(`{"chain_id":"mychain"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "g", prefix)
+
+	_, err = GenesisBech32PrefixFromJSON([]byte(`junk`))
+	assert.Error(t, err)
+}
+
 func TestGenesisSaveAs(t *testing.T) {
 	tmpfile, err := ioutil.TempFile("", "genesis")
 	require.NoError(t, err)
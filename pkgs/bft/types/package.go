@@ -30,6 +30,8 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		Commit{},
 		BlockID{},
 		CommitSig{},
+		CompactCommit{},
+		CommitOtherVote{},
 		Vote{},
 		// Tx{},
 		// Txs{},
@@ -44,10 +46,14 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		// Event types
 		EventNewBlock{},
 		EventNewBlockHeader{},
+		EventNewSignedHeader{},
 		EventTx{},
+		EventTxRejected{},
+		EventTxEvicted{},
 		EventVote{},
 		EventString(""),
 		EventValidatorSetUpdates{},
+		EventConsensusParamsUpdates{},
 
 		// Evidence types
 		DuplicateVoteEvidence{},
@@ -26,6 +26,7 @@ func DefaultConsensusParams() abci.ConsensusParams {
 	return abci.ConsensusParams{
 		DefaultBlockParams(),
 		DefaultValidatorParams(),
+		DefaultEvidenceParams(),
 	}
 }
 
@@ -44,6 +45,12 @@ func DefaultValidatorParams() *abci.ValidatorParams {
 	}}
 }
 
+func DefaultEvidenceParams() *abci.EvidenceParams {
+	return &abci.EvidenceParams{
+		MaxAge: 100000, // 27.8 hrs at 1block/s
+	}
+}
+
 func ValidateConsensusParams(params abci.ConsensusParams) error {
 	if params.Block.MaxTxBytes <= 0 {
 		return errors.New("Block.MaxTxBytes must be greater than 0. Got %d",
@@ -54,16 +61,44 @@ func ValidateConsensusParams(params abci.ConsensusParams) error {
 			params.Block.MaxTxBytes, MaxBlockSizeBytes)
 	}
 
+	if params.Block.MaxDataBytes <= 0 {
+		return errors.New("Block.MaxDataBytes must be greater than 0. Got %d",
+			params.Block.MaxDataBytes)
+	}
+	if params.Block.MaxDataBytes > MaxBlockSizeBytes {
+		return errors.New("Block.MaxDataBytes is too big. %d > %d",
+			params.Block.MaxDataBytes, MaxBlockSizeBytes)
+	}
+	if params.Block.MaxTxBytes > params.Block.MaxDataBytes {
+		return errors.New("Block.MaxTxBytes is greater than Block.MaxDataBytes. %d > %d",
+			params.Block.MaxTxBytes, params.Block.MaxDataBytes)
+	}
+
 	if params.Block.MaxGas < -1 {
 		return errors.New("Block.MaxGas must be greater or equal to -1. Got %d",
 			params.Block.MaxGas)
 	}
 
+	if params.Block.MaxTxGas < 0 {
+		return errors.New("Block.MaxTxGas must be greater or equal to 0. Got %d",
+			params.Block.MaxTxGas)
+	}
+	if params.Block.MaxGas > -1 && params.Block.MaxTxGas > params.Block.MaxGas {
+		return errors.New("Block.MaxTxGas is greater than Block.MaxGas. %d > %d",
+			params.Block.MaxTxGas, params.Block.MaxGas)
+	}
+
 	if params.Block.TimeIotaMS <= 0 {
 		return errors.New("Block.TimeIotaMS must be greater than 0. Got %v",
 			params.Block.TimeIotaMS)
 	}
 
+	// Nil in the states saved before the evidence params.
+	if params.Evidence != nil && params.Evidence.MaxAge <= 0 {
+		return errors.New("Evidence.MaxAge must be greater than 0. Got %d",
+			params.Evidence.MaxAge)
+	}
+
 	if len(params.Validator.PubKeyTypeURLs) == 0 {
 		return errors.New("len(Validator.PubKeyTypeURLs) must be greater than 0")
 	}
@@ -79,3 +114,11 @@ func ValidateConsensusParams(params abci.ConsensusParams) error {
 
 	return nil
 }
+
+// MaxTxGas returns the maximum gas wanted by a tx, or -1 for no limit.
+func MaxTxGas(params *abci.BlockParams) int64 {
+	if params.MaxTxGas > 0 {
+		return params.MaxTxGas
+	}
+	return params.MaxGas
+}
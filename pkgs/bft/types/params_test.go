@@ -34,6 +34,16 @@ func TestConsensusParamsValidation(t *testing.T) {
 		9: {makeParams(1, 1024, 0, 10, []string{}), false},
 		// test invalid pubkey type provided
 		10: {makeParams(1, 1024, 0, 10, []string{"potatoes make good pubkeys"}), false},
+		// test txs bigger than the data of blocks
+		11: {withDataBytes(makeParams(1024, 1024, 0, 10, valEd25519), 1023), false},
+		// test evidence params
+		12: {withEvidence(makeParams(1, 1024, 0, 10, valEd25519), 1), true},
+		13: {withEvidence(makeParams(1, 1024, 0, 10, valEd25519), 0), false},
+		// test max gas of txs
+		14: {withTxGas(makeParams(1, 1024, 100, 10, valEd25519), 100), true},
+		15: {withTxGas(makeParams(1, 1024, 100, 10, valEd25519), 101), false},
+		16: {withTxGas(makeParams(1, 1024, -1, 10, valEd25519), 101), true},
+		17: {withTxGas(makeParams(1, 1024, -1, 10, valEd25519), -1), false},
 	}
 	for i, tc := range testCases {
 		if tc.valid {
@@ -52,6 +62,7 @@ func makeParams(
 	return abci.ConsensusParams{
 		Block: &abci.BlockParams{
 			MaxTxBytes:    dataBytes,
+			MaxDataBytes:  dataBytes,
 			MaxBlockBytes: blockBytes,
 			MaxGas:        blockGas,
 			TimeIotaMS:    blockTimeIotaMS,
@@ -62,6 +73,28 @@ func makeParams(
 	}
 }
 
+func withDataBytes(params abci.ConsensusParams, dataBytes int64) abci.ConsensusParams {
+	params.Block.MaxDataBytes = dataBytes
+	return params
+}
+
+func withEvidence(params abci.ConsensusParams, maxAge int64) abci.ConsensusParams {
+	params.Evidence = &abci.EvidenceParams{MaxAge: maxAge}
+	return params
+}
+
+func withTxGas(params abci.ConsensusParams, txGas int64) abci.ConsensusParams {
+	params.Block.MaxTxGas = txGas
+	return params
+}
+
+func TestMaxTxGas(t *testing.T) {
+	assert.Equal(t, int64(-1), MaxTxGas(&abci.BlockParams{MaxGas: -1}))
+	assert.Equal(t, int64(100), MaxTxGas(&abci.BlockParams{MaxGas: 100}))
+	assert.Equal(t, int64(10), MaxTxGas(&abci.BlockParams{MaxGas: 100, MaxTxGas: 10}))
+	assert.Equal(t, int64(10), MaxTxGas(&abci.BlockParams{MaxGas: -1, MaxTxGas: 10}))
+}
+
 func TestConsensusParamsHash(t *testing.T) {
 	params := []abci.ConsensusParams{
 		makeParams(4, 1024, 2, 10, valEd25519),
@@ -107,6 +140,7 @@ func TestConsensusParamsUpdate(t *testing.T) {
 			abci.ConsensusParams{
 				Block: &abci.BlockParams{
 					MaxTxBytes:    100,
+					MaxDataBytes:  100,
 					MaxBlockBytes: 1024,
 					MaxGas:        200,
 					TimeIotaMS:    10,
@@ -117,6 +151,14 @@ func TestConsensusParamsUpdate(t *testing.T) {
 			},
 			makeParams(100, 1024, 200, 10, valSecp256k1),
 		},
+		// evidence updates
+		{
+			makeParams(1, 1024, 2, 10, valEd25519),
+			abci.ConsensusParams{
+				Evidence: &abci.EvidenceParams{MaxAge: 1000},
+			},
+			withEvidence(makeParams(1, 1024, 2, 10, valEd25519), 1000),
+		},
 	}
 	for _, tc := range testCases {
 		assert.Equal(t, tc.updatedParams, tc.params.Update(tc.updates))
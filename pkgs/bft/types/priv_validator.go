@@ -18,6 +18,27 @@ type PrivValidator interface {
 	SignProposal(chainID string, proposal *Proposal) error
 }
 
+// RotatingPrivValidator is a PrivValidator which may rotate its key, e.g.
+// to the next key of a validator whose rotation was announced to the
+// application, once the key is in the validator set.
+type RotatingPrivValidator interface {
+	PrivValidator
+
+	// UpdateToHeight is called before the consensus of height, whose
+	// validator set is vals.
+	UpdateToHeight(height int64, vals *ValidatorSet)
+}
+
+// ChallengePrivValidator is a PrivValidator which may sign challenges, to
+// prove that the node controls the validator key.
+type ChallengePrivValidator interface {
+	PrivValidator
+
+	// SignChallenge sets the validator public key of the challenge, and
+	// signs it.
+	SignChallenge(chainID string, challenge *Challenge) error
+}
+
 //----------------------------------------
 // Misc.
 
@@ -95,6 +116,17 @@ func (pv *MockPV) SignProposal(chainID string, proposal *Proposal) error {
 	return nil
 }
 
+// Implements ChallengePrivValidator.
+func (pv *MockPV) SignChallenge(chainID string, challenge *Challenge) error {
+	challenge.ValidatorPubKey = pv.GetPubKey()
+	sig, err := pv.privKey.Sign(challenge.SignBytes(chainID))
+	if err != nil {
+		return err
+	}
+	challenge.ValidatorSignature = sig
+	return nil
+}
+
 // String returns a string representation of the MockPV.
 func (pv *MockPV) String() string {
 	addr := pv.GetPubKey().Address()
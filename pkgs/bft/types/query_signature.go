@@ -0,0 +1,62 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/tmhash"
+)
+
+// QuerySignature is the signature by a node, with its node key, of its
+// response to an ABCI query, with which clients trusting the node detect
+// responses tampered with, e.g. by intermediate proxies, without verifying
+// proofs of the response against a light client.
+type QuerySignature struct {
+	NodeID     crypto.ID     `json:"node_id"`
+	NodePubKey crypto.PubKey `json:"node_pub_key"`
+	Signature  []byte        `json:"signature"`
+}
+
+// QuerySignBytes returns the bytes signed for the response res to the
+// query of data at path.  They commit to the request, the height and the
+// hash of the response, and can't be those of a vote, a proposal or a
+// challenge.
+func QuerySignBytes(chainID string, path string, data []byte, res abci.ResponseQuery) []byte {
+	bz, err := amino.MarshalSized(CanonicalizeQuery(chainID, path, data, res))
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// Verify returns an error if the signature is not of the node with ID
+// nodeID, or not of the response res to the query of data at path on
+// chainID.
+func (qs *QuerySignature) Verify(chainID string, nodeID crypto.ID, path string, data []byte, res abci.ResponseQuery) error {
+	if qs.NodeID != nodeID {
+		return fmt.Errorf("Query signed by %s, not %s", qs.NodeID, nodeID)
+	}
+	if qs.NodePubKey == nil {
+		return errors.New("Missing node public key")
+	}
+	if qs.NodePubKey.Address().ID() != qs.NodeID {
+		return fmt.Errorf("Node public key is not that of %s", qs.NodeID)
+	}
+	signBytes := QuerySignBytes(chainID, path, data, res)
+	if !qs.NodePubKey.VerifyBytes(signBytes, qs.Signature) {
+		return errors.New("Invalid query signature")
+	}
+	return nil
+}
+
+// Returns the hash of the amino encoded response to a query.
+func queryResponseHash(res abci.ResponseQuery) []byte {
+	bz, err := amino.Marshal(res)
+	if err != nil {
+		panic(err)
+	}
+	return tmhash.Sum(bz)
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+)
+
+func TestQuerySignature(t *testing.T) {
+	nodeKey := ed25519.GenPrivKey()
+	nodeID := nodeKey.PubKey().Address().ID()
+	data := []byte("data")
+	res := abci.ResponseQuery{Height: 10}
+	res.Value = []byte("value")
+
+	sig, err := nodeKey.Sign(QuerySignBytes("chain", "path", data, res))
+	require.NoError(t, err)
+	qs := &QuerySignature{
+		NodeID:     nodeID,
+		NodePubKey: nodeKey.PubKey(),
+		Signature:  sig,
+	}
+	require.NoError(t, qs.Verify("chain", nodeID, "path", data, res))
+	assert.Error(t, qs.Verify("other-chain", nodeID, "path", data, res))
+	assert.Error(t, qs.Verify("chain", nodeID, "other-path", data, res))
+	assert.Error(t, qs.Verify("chain", nodeID, "path", []byte("other-data"), res))
+	assert.Error(t, qs.Verify("chain", ed25519.GenPrivKey().PubKey().Address().ID(), "path", data, res))
+
+	// the response can't be tampered with.
+	tampered := res
+	tampered.Value = []byte("other-value")
+	assert.Error(t, qs.Verify("chain", nodeID, "path", data, tampered))
+	tampered = res
+	tampered.Height = 11
+	assert.Error(t, qs.Verify("chain", nodeID, "path", data, tampered))
+
+	// the node ID must be that of the node key.
+	qs2 := *qs
+	qs2.NodePubKey = ed25519.GenPrivKey().PubKey()
+	assert.Error(t, qs2.Verify("chain", nodeID, "path", data, res))
+
+	// sign bytes can't be those of a vote or a proposal.
+	vote := &Vote{Type: PrecommitType, Height: 1}
+	proposal := &Proposal{Type: ProposalType, Height: 1}
+	bz := QuerySignBytes("chain", "path", data, res)
+	assert.NotEqual(t, vote.SignBytes("chain")[1], bz[1])
+	assert.NotEqual(t, proposal.SignBytes("chain")[1], bz[1])
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+//-----------------------------------------------------------------------------
+// WebhookConfig
+
+// The kinds of events notified to the webhooks.
+const (
+	EventNewBlock            = "new_block"
+	EventTx                  = "tx"
+	EventValidatorSetUpdates = "validator_set_updates"
+)
+
+// WebhookConfig defines the configuration options of the webhooks, notified
+// of the events of the chain with HTTP POST requests.
+type WebhookConfig struct {
+	RootDir string `toml:"home"`
+
+	// URLs of the webhooks; none disables the notifications.
+	URLs []string `toml:"urls"`
+
+	// Kinds of events notified to the webhooks: new_block, tx and
+	// validator_set_updates.
+	Events []string `toml:"events"`
+
+	// Query of the txs notified, e.g. "event.type=vm.RealmCallEvent AND
+	// event.pkg_path=gno.land/r/demo/boards"; empty for all the txs.
+	TxQuery string `toml:"tx_query"`
+
+	// Secret of the HMAC-SHA256 signatures of the requests, in the header
+	// "X-Webhook-Signature: sha256=<hex>".
+	Secret string `toml:"secret"`
+
+	// Timeout of a request.
+	Timeout time.Duration `toml:"timeout"`
+
+	// Number of retries of a request failing, after a backoff doubled on
+	// each retry.
+	MaxRetries   int           `toml:"max_retries"`
+	RetryBackoff time.Duration `toml:"retry_backoff"`
+
+	// Number of notifications queued for a webhook, after which they are
+	// dead-lettered until it catches up.
+	QueueSize int `toml:"queue_size"`
+
+	// File the notifications which could not be delivered are appended to,
+	// one JSON per line; empty to only log them.
+	DeadLetterPath string `toml:"dead_letter_file"`
+}
+
+// DefaultWebhookConfig returns a default configuration of the webhooks.
+func DefaultWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		URLs:           []string{},
+		Events:         []string{EventNewBlock, EventTx, EventValidatorSetUpdates},
+		Timeout:        10 * time.Second,
+		MaxRetries:     5,
+		RetryBackoff:   1 * time.Second,
+		QueueSize:      1000,
+		DeadLetterPath: "data/webhook_dead_letters.jsonl",
+	}
+}
+
+// TestWebhookConfig returns a configuration of the webhooks for testing.
+func TestWebhookConfig() *WebhookConfig {
+	cfg := DefaultWebhookConfig()
+	cfg.Timeout = 1 * time.Second
+	cfg.RetryBackoff = 10 * time.Millisecond
+	return cfg
+}
+
+// DeadLetterFile returns the full path of the dead letters, or "" if none.
+func (cfg *WebhookConfig) DeadLetterFile() string {
+	if cfg.DeadLetterPath == "" {
+		return ""
+	}
+	if filepath.IsAbs(cfg.DeadLetterPath) {
+		return cfg.DeadLetterPath
+	}
+	return filepath.Join(cfg.RootDir, cfg.DeadLetterPath)
+}
+
+// Enabled returns true if any webhook is configured.
+func (cfg *WebhookConfig) Enabled() bool {
+	return len(cfg.URLs) != 0
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *WebhookConfig) ValidateBasic() error {
+	for _, event := range cfg.Events {
+		switch event {
+		case EventNewBlock, EventTx, EventValidatorSetUpdates:
+		default:
+			return errors.New("unknown event %q in events", event)
+		}
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("timeout can't be negative")
+	}
+	if cfg.MaxRetries < 0 {
+		return errors.New("max_retries can't be negative")
+	}
+	if cfg.RetryBackoff < 0 {
+		return errors.New("retry_backoff can't be negative")
+	}
+	if cfg.QueueSize <= 0 {
+		return errors.New("queue_size must be positive")
+	}
+	if cfg.Enabled() && cfg.Secret == "" {
+		return errors.New("secret must be set to notify the webhooks")
+	}
+	return nil
+}
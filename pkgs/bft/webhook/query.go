@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// TxQuery selects the txs notified to the webhooks.  It is a conjunction
+// of conditions "<key>=<value>" separated by " AND ", whose keys are:
+//
+//   - tx.height: the height of the block of the tx
+//   - tx.hash: the hex of the hash of the tx
+//   - tx.failed: true or false, whether the tx failed
+//   - event.type: the type of an event of the tx, e.g. vm.RealmCallEvent
+//   - event.msg_index: the index of the msg of the tx which emitted the
+//     same event, if annotated by the app
+//   - event.msg_type: the route and type of that msg, e.g. vm/exec
+//   - event.<field>: the value of a field of the same event, by its JSON
+//     name, e.g. event.pkg_path=gno.land/r/demo/boards
+//
+// The empty query selects all the txs.
+type TxQuery struct {
+	tx    []condition
+	event []condition
+}
+
+type condition struct {
+	key   string
+	value string
+}
+
+// ParseTxQuery parses the query of the txs notified.
+func ParseTxQuery(query string) (TxQuery, error) {
+	q := TxQuery{}
+	if strings.TrimSpace(query) == "" {
+		return q, nil
+	}
+	for _, part := range strings.Split(query, " AND ") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return q, errors.New("invalid condition %q, expected <key>=<value>", part)
+		}
+		cond := condition{key: kv[0], value: kv[1]}
+		switch {
+		case cond.key == "tx.height":
+			if _, err := strconv.ParseInt(cond.value, 10, 64); err != nil {
+				return q, errors.New("invalid height %q", cond.value)
+			}
+			q.tx = append(q.tx, cond)
+		case cond.key == "tx.hash":
+			if _, err := hex.DecodeString(cond.value); err != nil {
+				return q, errors.New("invalid hash %q", cond.value)
+			}
+			q.tx = append(q.tx, cond)
+		case cond.key == "tx.failed":
+			if cond.value != "true" && cond.value != "false" {
+				return q, errors.New("invalid failed %q, expected true or false", cond.value)
+			}
+			q.tx = append(q.tx, cond)
+		case strings.HasPrefix(cond.key, "event.") && len(cond.key) > len("event."):
+			cond.key = strings.TrimPrefix(cond.key, "event.")
+			q.event = append(q.event, cond)
+		default:
+			return q, errors.New("unknown key %q", cond.key)
+		}
+	}
+	return q, nil
+}
+
+// Matches returns true if the tx of res satisfies the conditions of the
+// query; those on events must all be satisfied by one of its events.
+func (q TxQuery) Matches(res types.TxResult) bool {
+	if !q.matchesTx(res) {
+		return false
+	}
+	if len(q.event) == 0 {
+		return true
+	}
+	for i, event := range res.Response.Events {
+		if q.matchesEvent(event, eventMsg(res, i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchedEvents returns the events of the tx of res which satisfy the
+// conditions of the query on events, and whether the tx matches.  All the
+// events of a matching tx are returned if there are no such conditions.
+func (q TxQuery) MatchedEvents(res types.TxResult) (events []abci.Event, ok bool) {
+	if !q.matchesTx(res) {
+		return nil, false
+	}
+	if len(q.event) == 0 {
+		return res.Response.Events, true
+	}
+	for i, event := range res.Response.Events {
+		if q.matchesEvent(event, eventMsg(res, i)) {
+			events = append(events, event)
+		}
+	}
+	return events, len(events) > 0
+}
+
+// Returns the msg of the event of res at index i, if annotated by the app.
+func eventMsg(res types.TxResult, i int) *abci.EventMsg {
+	if i < len(res.Response.EventMsgs) {
+		return &res.Response.EventMsgs[i]
+	}
+	return nil
+}
+
+func (q TxQuery) matchesTx(res types.TxResult) bool {
+	for _, cond := range q.tx {
+		var value string
+		switch cond.key {
+		case "tx.height":
+			value = strconv.FormatInt(res.Height, 10)
+		case "tx.hash":
+			value = fmt.Sprintf("%X", res.Tx.Hash())
+			cond.value = strings.ToUpper(cond.value)
+		case "tx.failed":
+			value = strconv.FormatBool(res.Response.IsErr())
+		}
+		if value != cond.value {
+			return false
+		}
+	}
+	return true
+}
+
+func (q TxQuery) matchesEvent(event interface{}, msg *abci.EventMsg) bool {
+	var fields map[string]interface{}
+	bz, err := amino.MarshalJSON(event)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(bz, &fields); err != nil {
+		// Not an object, e.g. an abci.EventString.
+		fields = map[string]interface{}{}
+	}
+	for _, cond := range q.event {
+		var value string
+		if cond.key == "type" {
+			value = strings.TrimPrefix(amino.GetTypeURL(event), "/")
+		} else if cond.key == "msg_index" && msg != nil {
+			value = strconv.Itoa(msg.MsgIndex)
+		} else if cond.key == "msg_type" && msg != nil {
+			value = msg.MsgType
+		} else if v, ok := fields[cond.key]; ok {
+			value = fmt.Sprint(v)
+		} else {
+			return false
+		}
+		if value != cond.value {
+			return false
+		}
+	}
+	return true
+}
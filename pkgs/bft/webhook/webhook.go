@@ -0,0 +1,255 @@
+// Package webhook notifies HTTP webhooks of the events of the chain, for the
+// services which can't maintain a websocket to the node.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	cfg "github.com/gnolang/gno/pkgs/bft/webhook/config"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/events"
+	"github.com/gnolang/gno/pkgs/service"
+)
+
+const listenerID = "webhook"
+
+// Notification is the JSON body of the requests to the webhooks, with the
+// kind of the event (see the Event constants of the config), the height of
+// its block, and the event: EventNewBlockHeader, EventTx or
+// EventValidatorSetUpdates.
+type Notification struct {
+	Type   string        `json:"type"`
+	Height int64         `json:"height"`
+	Event  types.TMEvent `json:"event"`
+}
+
+// DeadLetter is a notification which could not be delivered to a webhook,
+// appended to the dead letters file.
+type DeadLetter struct {
+	Time  time.Time       `json:"time"`
+	URL   string          `json:"url"`
+	Type  string          `json:"type"`
+	Error string          `json:"error"`
+	Body  json.RawMessage `json:"body"`
+}
+
+// Sign returns the HMAC-SHA256 signature of the body of a request with
+// secret, in hex, sent in the header "X-Webhook-Signature: sha256=<hex>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type notification struct {
+	typ  string
+	body []byte
+}
+
+type webhook struct {
+	url   string
+	queue chan notification
+}
+
+// Notifier is the service posting the events of the chain to the webhooks
+// of the config.  Each webhook is notified in order by its own routine, so
+// that a slow webhook doesn't delay the others, retrying the failed
+// requests with an exponential backoff.  The notifications still failing,
+// or overflowing the queue of a webhook, are dead-lettered.  The
+// notifications queued when the service stops are dropped.
+type Notifier struct {
+	service.BaseService
+
+	config   *cfg.WebhookConfig
+	query    TxQuery
+	events   map[string]bool
+	evsw     events.EventSwitch
+	client   *http.Client
+	webhooks []*webhook
+	height   int64 // of the last block
+
+	deadMtx sync.Mutex
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewNotifier returns the service notifying the webhooks of config of the
+// events of evsw.
+func NewNotifier(config *cfg.WebhookConfig, evsw events.EventSwitch) (*Notifier, error) {
+	query, err := ParseTxQuery(config.TxQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid tx_query")
+	}
+	n := &Notifier{
+		config: config,
+		query:  query,
+		events: make(map[string]bool),
+		evsw:   evsw,
+		client: &http.Client{Timeout: config.Timeout},
+		quit:   make(chan struct{}),
+	}
+	for _, event := range config.Events {
+		n.events[event] = true
+	}
+	for _, url := range config.URLs {
+		n.webhooks = append(n.webhooks, &webhook{
+			url:   url,
+			queue: make(chan notification, config.QueueSize),
+		})
+	}
+	n.BaseService = *service.NewBaseService(nil, "WebhookNotifier", n)
+	return n, nil
+}
+
+func (n *Notifier) OnStart() error {
+	for _, wh := range n.webhooks {
+		n.wg.Add(1)
+		go n.run(wh)
+	}
+	n.evsw.AddListener(listenerID, n.onEvent)
+	return nil
+}
+
+func (n *Notifier) OnStop() {
+	n.evsw.RemoveListener(listenerID)
+	close(n.quit)
+	n.wg.Wait()
+}
+
+// Queues the notification of event, if any, to the webhooks.  It is called
+// synchronously by the event switch, so it must not block.
+func (n *Notifier) onEvent(event events.Event) {
+	var typ string
+	var height int64
+	switch ev := event.(type) {
+	case types.EventNewBlockHeader:
+		n.height = ev.Header.Height
+		typ, height = cfg.EventNewBlock, ev.Header.Height
+	case types.EventTx:
+		if !n.query.Matches(ev.Result) {
+			return
+		}
+		typ, height = cfg.EventTx, ev.Result.Height
+	case types.EventValidatorSetUpdates:
+		// fired after the header of its block.
+		typ, height = cfg.EventValidatorSetUpdates, n.height
+	default:
+		return
+	}
+	if !n.events[typ] {
+		return
+	}
+	body, err := amino.MarshalJSON(Notification{
+		Type:   typ,
+		Height: height,
+		Event:  event.(types.TMEvent),
+	})
+	if err != nil {
+		n.Logger.Error("Could not marshal webhook notification", "type", typ, "err", err)
+		return
+	}
+	notif := notification{typ: typ, body: body}
+	for _, wh := range n.webhooks {
+		select {
+		case wh.queue <- notif:
+		default:
+			n.deadLetter(wh.url, notif, errors.New("queue full"))
+		}
+	}
+}
+
+// Delivers the notifications of wh, until the service stops.
+func (n *Notifier) run(wh *webhook) {
+	defer n.wg.Done()
+	for {
+		select {
+		case notif := <-wh.queue:
+			if err := n.deliver(wh.url, notif); err != nil {
+				n.deadLetter(wh.url, notif, err)
+			}
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// Posts notif to url, retrying up to MaxRetries times on failure.
+func (n *Notifier) deliver(url string, notif notification) error {
+	backoff := n.config.RetryBackoff
+	for i := 0; ; i++ {
+		err := n.post(url, notif)
+		if err == nil {
+			return nil
+		}
+		if i == n.config.MaxRetries {
+			return err
+		}
+		n.Logger.Info("Webhook request failed, retrying", "url", url, "backoff", backoff, "err", err)
+		select {
+		case <-time.After(backoff):
+		case <-n.quit:
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (n *Notifier) post(url string, notif notification) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(notif.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", notif.typ)
+	req.Header.Set("X-Webhook-Signature", "sha256="+Sign(n.config.Secret, notif.body))
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.New("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+// Logs the notification which could not be delivered to url, and appends
+// it to the dead letters file, if any.
+func (n *Notifier) deadLetter(url string, notif notification, err error) {
+	n.Logger.Error("Could not deliver webhook notification", "url", url, "type", notif.typ, "err", err)
+	path := n.config.DeadLetterFile()
+	if path == "" {
+		return
+	}
+	bz, _ := json.Marshal(DeadLetter{
+		Time:  time.Now().UTC(),
+		URL:   url,
+		Type:  notif.typ,
+		Error: err.Error(),
+		Body:  notif.body,
+	})
+
+	n.deadMtx.Lock()
+	defer n.deadMtx.Unlock()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		n.Logger.Error("Could not open webhook dead letters", "path", path, "err", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(bz, '\n')); err != nil {
+		n.Logger.Error("Could not write webhook dead letter", "path", path, "err", err)
+	}
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	cfg "github.com/gnolang/gno/pkgs/bft/webhook/config"
+	"github.com/gnolang/gno/pkgs/events"
+)
+
+type TestEvent struct {
+	PkgPath string `json:"pkg_path"`
+	Func    string `json:"func"`
+}
+
+func (TestEvent) AssertABCIEvent() {}
+
+var testPackage = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/pkgs/bft/webhook",
+	"webhook",
+	amino.GetCallersDirname(),
+).
+	WithTypes(
+		TestEvent{},
+	))
+
+func testTxResult(height int64, evs ...abci.Event) types.TxResult {
+	res := types.TxResult{Height: height, Tx: types.Tx("tx")}
+	res.Response.Events = evs
+	return res
+}
+
+func TestTxQuery(t *testing.T) {
+	boards := TestEvent{PkgPath: "gno.land/r/demo/boards", Func: "CreatePost"}
+	users := TestEvent{PkgPath: "gno.land/r/demo/users", Func: "Register"}
+	multiMsg := testTxResult(1, users, boards)
+	multiMsg.Response.EventMsgs = []abci.EventMsg{{MsgIndex: 0, MsgType: "vm/exec"}, {MsgIndex: 1, MsgType: "vm/exec"}}
+
+	for _, tc := range []struct {
+		query string
+		res   types.TxResult
+		match bool
+	}{
+		{"", testTxResult(1), true},
+		{"tx.height=2", testTxResult(1), false},
+		{"tx.height=2", testTxResult(2), true},
+		{"tx.failed=false", testTxResult(2), true},
+		{"tx.hash=" + hex.EncodeToString(types.Tx("tx").Hash()), testTxResult(2), true},
+		{"tx.hash=00", testTxResult(2), false},
+		{"event.type=webhook.TestEvent", testTxResult(1), false},
+		{"event.type=webhook.TestEvent", testTxResult(1, abci.EventString("x"), boards), true},
+		{"event.type=webhook.TestEvent AND event.pkg_path=gno.land/r/demo/boards", testTxResult(1, users, boards), true},
+		{"event.pkg_path=gno.land/r/demo/boards AND event.func=Register", testTxResult(1, users, boards), false},
+		{"tx.height=2 AND event.func=Register", testTxResult(1, users), false},
+		{"event.msg_index=1 AND event.func=CreatePost", multiMsg, true},
+		{"event.msg_index=1 AND event.func=Register", multiMsg, false},
+		{"event.msg_type=vm/exec", multiMsg, true},
+		{"event.msg_type=vm/exec", testTxResult(1, users), false},
+	} {
+		q, err := ParseTxQuery(tc.query)
+		require.NoError(t, err, tc.query)
+		assert.Equal(t, tc.match, q.Matches(tc.res), tc.query)
+		_, ok := q.MatchedEvents(tc.res)
+		assert.Equal(t, tc.match, ok, tc.query)
+	}
+
+	// only the events satisfying the conditions on events are matched.
+	q, err := ParseTxQuery("event.pkg_path=gno.land/r/demo/boards")
+	require.NoError(t, err)
+	evs, ok := q.MatchedEvents(testTxResult(1, users, boards, boards))
+	assert.True(t, ok)
+	assert.Equal(t, []abci.Event{boards, boards}, evs)
+
+	for _, query := range []string{"tx.height", "tx.height=x", "tx.failed=no", "tx.hash=zz", "event.=x", "block.height=1"} {
+		_, err := ParseTxQuery(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	var mtx sync.Mutex
+	var received []Notification
+	failures := 2 // of the first request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "sha256="+Sign("secret", body), r.Header.Get("X-Webhook-Signature"))
+		mtx.Lock()
+		defer mtx.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var notif Notification
+		require.NoError(t, amino.UnmarshalJSON(body, &notif))
+		assert.Equal(t, notif.Type, r.Header.Get("X-Webhook-Event"))
+		received = append(received, notif)
+	}))
+	defer srv.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer down.Close()
+
+	config := cfg.TestWebhookConfig()
+	config.RootDir = t.TempDir()
+	config.URLs = []string{srv.URL, down.URL}
+	config.Events = []string{cfg.EventNewBlock, cfg.EventTx}
+	config.TxQuery = "event.pkg_path=gno.land/r/demo/boards"
+	config.Secret = "secret"
+	config.MaxRetries = 2
+	config.DeadLetterPath = "dead_letters.jsonl"
+	require.NoError(t, config.ValidateBasic())
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+	n, err := NewNotifier(config, evsw)
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer n.Stop()
+
+	evsw.FireEvent(types.EventNewBlockHeader{Header: types.Header{Height: 7}})
+	evsw.FireEvent(types.EventTx{Result: testTxResult(7, TestEvent{PkgPath: "gno.land/r/demo/users"})})
+	evsw.FireEvent(types.EventTx{Result: testTxResult(7, TestEvent{PkgPath: "gno.land/r/demo/boards"})})
+	evsw.FireEvent(types.EventValidatorSetUpdates{})
+
+	// delivered in order, after 2 retries of the first.
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(received) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, cfg.EventNewBlock, received[0].Type)
+	assert.Equal(t, int64(7), received[0].Height)
+	assert.Equal(t, int64(7), received[0].Event.(types.EventNewBlockHeader).Header.Height)
+	assert.Equal(t, cfg.EventTx, received[1].Type)
+	tx := received[1].Event.(types.EventTx)
+	assert.Equal(t, TestEvent{PkgPath: "gno.land/r/demo/boards"}, tx.Result.Response.Events[0])
+
+	// dead-lettered by the webhook down, after its retries.
+	path := filepath.Join(config.RootDir, config.DeadLetterPath)
+	var letters []DeadLetter
+	require.Eventually(t, func() bool {
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		letters = nil
+		for _, line := range strings.Split(strings.TrimSpace(string(bz)), "\n") {
+			var letter DeadLetter
+			require.NoError(t, json.Unmarshal([]byte(line), &letter))
+			letters = append(letters, letter)
+		}
+		return len(letters) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, down.URL, letters[0].URL)
+	assert.Equal(t, cfg.EventNewBlock, letters[0].Type)
+	assert.Contains(t, letters[0].Error, "500")
+	assert.Equal(t, cfg.EventTx, letters[1].Type)
+}
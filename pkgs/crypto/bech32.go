@@ -8,8 +8,52 @@ import (
 	"github.com/gnolang/gno/pkgs/bech32"
 )
 
+// The Bech32 prefixes of the chain, see SetBech32Prefix.
+var (
+	bech32AddrPrefix   = Bech32AddrPrefix
+	bech32PubKeyPrefix = Bech32PubKeyPrefix
+)
+
+// SetBech32Prefix sets the Bech32 prefix of the addresses of the chain, e.g.
+// "g", and that of its pubkeys, the prefix followed by "pub". It isn't safe
+// for concurrent use, and must be called on startup, before any address is
+// encoded or decoded.
+func SetBech32Prefix(prefix string) error {
+	if err := ValidateBech32Prefix(prefix); err != nil {
+		return err
+	}
+	bech32AddrPrefix = prefix
+	bech32PubKeyPrefix = prefix + Bech32PubKeySuffix
+	return nil
+}
+
+// Bech32Prefix returns the Bech32 prefix of the addresses of the chain.
+func Bech32Prefix() string {
+	return bech32AddrPrefix
+}
+
+// ValidateBech32Prefix returns an error if prefix isn't a valid Bech32
+// prefix: 1 to 16 lowercase letters or digits, starting with a letter.
+func ValidateBech32Prefix(prefix string) error {
+	if len(prefix) == 0 || len(prefix) > 16 {
+		return fmt.Errorf("invalid Bech32 prefix %q: must be 1 to 16 characters", prefix)
+	}
+	for i, c := range prefix {
+		if !('a' <= c && c <= 'z') && (i == 0 || !('0' <= c && c <= '9')) {
+			return fmt.Errorf("invalid Bech32 prefix %q: must be lowercase letters or digits, starting with a letter", prefix)
+		}
+	}
+	return nil
+}
+
 func AddressToBech32(addr Address) string {
-	bech32Addr, err := bech32.Encode(Bech32AddrPrefix, addr[:])
+	return AddressToBech32WithPrefix(bech32AddrPrefix, addr)
+}
+
+// AddressToBech32WithPrefix encodes addr with the Bech32 prefix of another
+// chain.
+func AddressToBech32WithPrefix(prefix string, addr Address) string {
+	bech32Addr, err := bech32.Encode(prefix, addr[:])
 	if err != nil {
 		panic(err)
 	}
@@ -17,16 +61,24 @@ func AddressToBech32(addr Address) string {
 }
 
 func AddressFromBech32(bech32str string) (Address, error) {
-	bz, err := GetFromBech32(bech32str, Bech32AddrPrefix)
+	return AddressFromBech32WithPrefix(bech32AddrPrefix, bech32str)
+}
+
+// AddressFromBech32WithPrefix decodes the address of bech32str, which must
+// have the Bech32 prefix of another chain.
+func AddressFromBech32WithPrefix(prefix, bech32str string) (Address, error) {
+	bz, err := GetFromBech32(bech32str, prefix)
 	if err != nil {
 		return Address{}, err
-	} else {
-		return AddressFromBytes(bz), nil
 	}
+	if len(bz) != AddressSize {
+		return Address{}, fmt.Errorf("unexpected address byte length. expected %v, got %v", AddressSize, len(bz))
+	}
+	return AddressFromBytes(bz), nil
 }
 
 func PubKeyToBech32(pub PubKey) string {
-	bech32PubKey, err := bech32.Encode(Bech32PubKeyPrefix, pub.Bytes())
+	bech32PubKey, err := bech32.Encode(bech32PubKeyPrefix, pub.Bytes())
 	if err != nil {
 		panic(err)
 	}
@@ -34,7 +86,7 @@ func PubKeyToBech32(pub PubKey) string {
 }
 
 func PubKeyFromBech32(bech32str string) (pubKey PubKey, err error) {
-	bz, err := GetFromBech32(bech32str, Bech32PubKeyPrefix)
+	bz, err := GetFromBech32(bech32str, bech32PubKeyPrefix)
 	if err != nil {
 		return PubKey(nil), err
 	} else {
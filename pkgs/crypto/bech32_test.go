@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/pkgs/amino"
@@ -65,3 +66,42 @@ func TestRandBech32AddrConsistency(t *testing.T) {
 		require.NotNil(t, err)
 	}
 }
+
+func TestSetBech32Prefix(t *testing.T) {
+	defer crypto.SetBech32Prefix(crypto.Bech32AddrPrefix)
+
+	for _, prefix := range []string{"", "G", "1g", "g-1", "abcdefghijklmnopq"} {
+		assert.Error(t, crypto.SetBech32Prefix(prefix), prefix)
+	}
+	assert.Equal(t, "g", crypto.Bech32Prefix())
+
+	addr := crypto.AddressFromBytes([]byte("12345678901234567890"))
+	gstr := addr.String()
+	require.NoError(t, crypto.SetBech32Prefix("other1"))
+	assert.Equal(t, "other1", crypto.Bech32Prefix())
+	str := addr.String()
+	assert.Equal(t, "other11", str[:7])
+	assert.Equal(t, str, crypto.AddressToBech32WithPrefix("other1", addr))
+	assert.Equal(t, gstr, crypto.AddressToBech32WithPrefix("g", addr))
+
+	// inputs of another prefix are invalid.
+	res, err := crypto.AddressFromBech32(str)
+	require.NoError(t, err)
+	assert.Equal(t, addr, res)
+	_, err = crypto.AddressFromBech32(gstr)
+	assert.Error(t, err)
+	res, err = crypto.AddressFromBech32WithPrefix("g", gstr)
+	require.NoError(t, err)
+	assert.Equal(t, addr, res)
+	var addr2 crypto.Address
+	assert.Error(t, addr2.DecodeString(gstr))
+	require.NoError(t, addr2.DecodeString(str))
+
+	// pubkeys too.
+	pub := ed25519.GenPrivKey().PubKey()
+	pubStr := crypto.PubKeyToBech32(pub)
+	assert.Equal(t, "other1pub1", pubStr[:10])
+	pub2, err := crypto.PubKeyFromBech32(pubStr)
+	require.NoError(t, err)
+	assert.Equal(t, pub, pub2)
+}
@@ -1,11 +1,16 @@
 package crypto
 
 const (
-	// Bech32AddrPrefix defines the Bech32 prefix of an address
+	// Bech32AddrPrefix defines the default Bech32 prefix of an address,
+	// see SetBech32Prefix.
 	Bech32AddrPrefix = "g"
 
-	// Bech32PubKeyPrefix defines the Bech32 prefix of a pubkey
-	Bech32PubKeyPrefix = "gpub"
+	// Bech32PubKeyPrefix defines the default Bech32 prefix of a pubkey.
+	Bech32PubKeyPrefix = Bech32AddrPrefix + Bech32PubKeySuffix
+
+	// Bech32PubKeySuffix is appended to the Bech32 prefix of the addresses
+	// for that of the pubkeys.
+	Bech32PubKeySuffix = "pub"
 
 	// Atom in https://github.com/satoshilabs/slips/blob/master/slip-0044.md
 	CoinType uint32 = 118
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"encoding/base64"
 	"fmt"
 	"sort"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/gnolang/gno/pkgs/crypto/bip39"
 	"github.com/gnolang/gno/pkgs/crypto/keys"
 	"github.com/gnolang/gno/pkgs/crypto/multisig"
+	"github.com/gnolang/gno/pkgs/crypto/webauthn"
 	"github.com/gnolang/gno/pkgs/errors"
 )
 
@@ -18,6 +20,7 @@ type AddOptions struct {
 	MultisigThreshold int      `flag:"threshold" help:"K out of N required signatures. For use in conjunction with --multisig"`
 	NoSort            bool     `flag:"nosort" help:"Keys passed to --multisig are taken in the order they're supplied"`
 	PublicKey         string   `flag:"pubkey" help:"Parse a public key in bech32 format and save it to disk"`
+	WebAuthn          string   `flag:"webauthn" help:"Parse the base64 SubjectPublicKeyInfo of a WebAuthn credential and save it to disk"`
 	UseLedger         bool     `flag:"ledger" help:"Store a local reference to a private key on a Ledger device"`
 	Recover           bool     `flag:"recover" help:"Provide seed phrase to recover existing key instead of creating"`
 	NoBackup          bool     `flag:"nobackup" help:"Don't print out seed phrase (if others are watching the terminal)"`
@@ -114,7 +117,7 @@ func addApp(cmd *command.Command, args []string, iopts interface{}) error {
 		}
 
 		// ask for a password when generating a local key
-		if opts.PublicKey == "" && !opts.UseLedger {
+		if opts.PublicKey == "" && opts.WebAuthn == "" && !opts.UseLedger {
 			encryptPassword, err = cmd.GetCheckPassword(
 				"Enter a passphrase to encrypt your key to disk:",
 				"Repeat the passphrase:")
@@ -136,12 +139,29 @@ func addApp(cmd *command.Command, args []string, iopts interface{}) error {
 		return nil
 	}
 
+	if opts.WebAuthn != "" {
+		der, err := base64.StdEncoding.DecodeString(opts.WebAuthn)
+		if err != nil {
+			return errors.Wrap(err, "decoding webauthn public key")
+		}
+		pk, err := webauthn.NewPubKeyFromSPKI(der)
+		if err != nil {
+			return err
+		}
+		info, err := kb.CreateOffline(name, pk)
+		if err != nil {
+			return err
+		}
+		printNewInfo(cmd, info)
+		return nil
+	}
+
 	account := uint32(opts.Account)
 	index := uint32(opts.Index)
 
 	// If we're using ledger, only thing we need is the path and the bech32 prefix.
 	if opts.UseLedger {
-		bech32PrefixAddr := crypto.Bech32AddrPrefix
+		bech32PrefixAddr := crypto.Bech32Prefix()
 		info, err := kb.CreateLedger(name, keys.Secp256k1, bech32PrefixAddr, account, index)
 		if err != nil {
 			return err
@@ -2,8 +2,10 @@ package client
 
 import (
 	"io/ioutil"
+	"time"
 
 	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
 	"github.com/gnolang/gno/pkgs/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	"github.com/gnolang/gno/pkgs/command"
@@ -13,6 +15,8 @@ import (
 
 type BroadcastOptions struct {
 	BaseOptions
+	Wait    bool   `flag:"wait" help:"wait for the inclusion of the tx, until timeout, instead of its commit by the node"`
+	Timeout string `flag:"timeout" help:"how long to wait for the tx (only useful if --wait)"`
 
 	// internal
 	Tx *std.Tx `flag:"-"`
@@ -20,6 +24,7 @@ type BroadcastOptions struct {
 
 var DefaultBroadcastOptions = BroadcastOptions{
 	BaseOptions: DefaultBaseOptions,
+	Timeout:     DefaultTxWaitOptions.Timeout,
 }
 
 func broadcastApp(cmd *command.Command, args []string, iopts interface{}) error {
@@ -56,6 +61,7 @@ func broadcastApp(cmd *command.Command, args []string, iopts interface{}) error
 		cmd.Println("OK!")
 		cmd.Println("GAS WANTED:", res.DeliverTx.GasWanted)
 		cmd.Println("GAS USED:  ", res.DeliverTx.GasUsed)
+		cmd.Printf("TX HASH:    %X\n", res.TxHash)
 	}
 	return nil
 }
@@ -75,7 +81,14 @@ func BroadcastHandler(opts BroadcastOptions) (*ctypes.ResultBroadcastTxCommit, e
 		return nil, errors.Wrap(err, "remarshaling tx binary bytes")
 	}
 
-	cli := client.NewHTTP(remote, "/websocket")
+	cli, err := dialRemote(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Wait {
+		return broadcastWait(cli, bz, opts.Timeout)
+	}
 
 	bres, err := cli.BroadcastTxCommit(bz)
 	if err != nil {
@@ -84,3 +97,40 @@ func BroadcastHandler(opts BroadcastOptions) (*ctypes.ResultBroadcastTxCommit, e
 
 	return bres, nil
 }
+
+// Broadcasts the tx bz once checked, and waits for its inclusion until
+// timeout, e.g. longer than the node waits for the commit of a tx.
+func broadcastWait(cli *client.HTTP, bz []byte, timeout string) (*ctypes.ResultBroadcastTxCommit, error) {
+	wait, err := time.ParseDuration(timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing timeout")
+	}
+
+	res, err := cli.BroadcastTxSync(bz)
+	if err != nil {
+		return nil, errors.Wrap(err, "broadcasting bytes")
+	}
+	bres := &ctypes.ResultBroadcastTxCommit{
+		CheckTx: abci.ResponseCheckTx{
+			ResponseBase: abci.ResponseBase{
+				Error: res.Error,
+				Data:  res.Data,
+				Log:   res.Log,
+			},
+			TxHash: res.TxHash,
+		},
+		Hash:   res.Hash,
+		TxHash: res.TxHash,
+	}
+	if bres.CheckTx.IsErr() {
+		return bres, nil
+	}
+
+	tres, err := waitTx(cli, res.Hash, wait)
+	if err != nil {
+		return nil, err
+	}
+	bres.DeliverTx = tres.TxResult
+	bres.Height = tres.Height
+	return bres, nil
+}
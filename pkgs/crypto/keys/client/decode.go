@@ -0,0 +1,156 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/tmhash"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+type DecodeOptions struct {
+	BaseOptions
+	ChainID       string  `flag:"chainid" help:"chainid of the sign bytes to show (optional)"`
+	AccountNumber *uint64 `flag:"number" help:"account number of the sign bytes to show"`
+	Sequence      *uint64 `flag:"sequence" help:"sequence of the sign bytes to show"`
+
+	// internal flags, when called programatically
+	Tx string `flag:"-"` // base64 or hex
+}
+
+var DefaultDecodeOptions = DecodeOptions{
+	BaseOptions: DefaultBaseOptions,
+}
+
+// DecodedTx is the human-readable form of a tx.
+type DecodedTx struct {
+	Hash       string             `json:"hash"` // of the encoded tx, as indexed by the node
+	Msgs       []DecodedMsg       `json:"msgs"`
+	GasWanted  int64              `json:"gas_wanted"`
+	GasFee     string             `json:"gas_fee"`
+	Memo       string             `json:"memo"`
+	Signers    []string           `json:"signers"`
+	Signatures []DecodedSignature `json:"signatures"`
+	SignBytes  string             `json:"sign_bytes,omitempty"` // of the first signer, if requested
+}
+
+// DecodedMsg is a msg of a DecodedTx, with its fields by name.
+type DecodedMsg struct {
+	Route string          `json:"route"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// DecodedSignature is a signature of a DecodedTx.
+type DecodedSignature struct {
+	PubKey    string `json:"pub_key,omitempty"` // bech32, if set
+	Signature []byte `json:"signature"`
+}
+
+func decodeApp(cmd *command.Command, args []string, iopts interface{}) error {
+	var opts DecodeOptions = iopts.(DecodeOptions)
+
+	if len(args) > 1 {
+		cmd.ErrPrintfln("Usage: decode <base64 or hex tx>")
+		return errors.New("invalid args")
+	}
+	if len(args) == 1 {
+		opts.Tx = args[0]
+	} else { // from stdin.
+		txstr, err := cmd.GetString("Enter tx to decode, in base64 or hex.")
+		if err != nil {
+			return err
+		}
+		opts.Tx = txstr
+	}
+
+	decoded, err := DecodeHandler(opts)
+	if err != nil {
+		return err
+	}
+	bz, err := json.MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		return err
+	}
+	cmd.Println(string(bz))
+	return nil
+}
+
+func DecodeHandler(opts DecodeOptions) (*DecodedTx, error) {
+	if (opts.AccountNumber == nil) != (opts.Sequence == nil) {
+		return nil, errors.New("number and sequence must both be set to show the sign bytes")
+	}
+	bz, err := DecodeTxString(opts.Tx)
+	if err != nil {
+		return nil, err
+	}
+	var tx std.Tx
+	if err := amino.Unmarshal(bz, &tx); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling tx")
+	}
+	decoded, err := DecodeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	decoded.Hash = hex.EncodeToString(tmhash.Sum(bz))
+	if opts.AccountNumber != nil {
+		decoded.SignBytes = string(tx.GetSignBytes(opts.ChainID, *opts.AccountNumber, *opts.Sequence))
+	}
+	return decoded, nil
+}
+
+// DecodeTxString decodes the bytes of a tx encoded in hex, optionally
+// prefixed with "0x", or else in base64.
+func DecodeTxString(txstr string) ([]byte, error) {
+	txstr = strings.TrimSpace(txstr)
+	if txstr == "" {
+		return nil, errors.New("empty tx")
+	}
+	if bz, err := hex.DecodeString(strings.TrimPrefix(txstr, "0x")); err == nil {
+		return bz, nil
+	}
+	bz, err := base64.StdEncoding.DecodeString(txstr)
+	if err != nil {
+		return nil, errors.New("tx is neither hex nor base64")
+	}
+	return bz, nil
+}
+
+// DecodeTx returns the human-readable form of tx, without its hash.
+func DecodeTx(tx std.Tx) (*DecodedTx, error) {
+	decoded := &DecodedTx{
+		Msgs:       make([]DecodedMsg, len(tx.Msgs)),
+		GasWanted:  tx.Fee.GasWanted,
+		GasFee:     tx.Fee.GasFee.String(),
+		Memo:       tx.Memo,
+		Signers:    []string{},
+		Signatures: make([]DecodedSignature, len(tx.Signatures)),
+	}
+	for i, msg := range tx.Msgs {
+		value, err := amino.MarshalJSON(msg)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshaling msg #%d", i)
+		}
+		decoded.Msgs[i] = DecodedMsg{
+			Route: msg.Route(),
+			Type:  msg.Type(),
+			Value: value,
+		}
+	}
+	for _, signer := range tx.GetSigners() {
+		decoded.Signers = append(decoded.Signers, signer.String())
+	}
+	for i, sig := range tx.Signatures {
+		decoded.Signatures[i].Signature = sig.Signature
+		if sig.PubKey != nil {
+			decoded.Signatures[i].PubKey = crypto.PubKeyToBech32(sig.PubKey)
+		}
+	}
+	return decoded, nil
+}
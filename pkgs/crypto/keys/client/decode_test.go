@@ -0,0 +1,73 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/crypto/secp256k1"
+	testutils2 "github.com/gnolang/gno/pkgs/sdk/testutils"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
+)
+
+func Test_decodeApp(t *testing.T) {
+	priv := secp256k1.GenPrivKey()
+	addr := priv.PubKey().Address()
+	msg := testutils2.NewTestMsg(addr)
+	fee := std.NewFee(50000, std.MustParseCoin("1000000ugnot"))
+	tx := std.NewTx([]std.Msg{msg}, fee, []std.Signature{{PubKey: priv.PubKey(), Signature: []byte{1, 2, 3}}}, "memo")
+	bz := amino.MustMarshal(tx)
+
+	for _, txstr := range []string{
+		base64.StdEncoding.EncodeToString(bz),
+		hex.EncodeToString(bz),
+		"0x" + hex.EncodeToString(bz),
+	} {
+		number, sequence := uint64(1), uint64(2)
+		decoded, err := DecodeHandler(DecodeOptions{
+			ChainID:       "dev",
+			AccountNumber: &number,
+			Sequence:      &sequence,
+			Tx:            txstr,
+		})
+		require.NoError(t, err)
+		assert.Len(t, decoded.Hash, 64)
+		require.Len(t, decoded.Msgs, 1)
+		assert.Equal(t, msg.Route(), decoded.Msgs[0].Route)
+		assert.Equal(t, msg.Type(), decoded.Msgs[0].Type)
+		assert.Contains(t, string(decoded.Msgs[0].Value), addr.String())
+		assert.Equal(t, int64(50000), decoded.GasWanted)
+		assert.Equal(t, "1000000ugnot", decoded.GasFee)
+		assert.Equal(t, "memo", decoded.Memo)
+		assert.Equal(t, []string{addr.String()}, decoded.Signers)
+		require.Len(t, decoded.Signatures, 1)
+		assert.Equal(t, []byte{1, 2, 3}, decoded.Signatures[0].Signature)
+		assert.NotEmpty(t, decoded.Signatures[0].PubKey)
+		assert.Equal(t, string(tx.GetSignBytes("dev", 1, 2)), decoded.SignBytes)
+	}
+
+	// the command.
+	cmd := command.NewMockCommand()
+	out := new(bytes.Buffer)
+	cmd.SetOut(command.WriteNopCloser(out))
+	require.NoError(t, decodeApp(cmd, []string{hex.EncodeToString(bz)}, DefaultDecodeOptions))
+	var decoded DecodedTx
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &decoded))
+	assert.Equal(t, "memo", decoded.Memo)
+	assert.Empty(t, decoded.SignBytes)
+
+	// errors.
+	for _, txstr := range []string{"", "not a tx!", "0000"} {
+		_, err := DecodeHandler(DecodeOptions{Tx: txstr})
+		assert.Error(t, err, txstr)
+	}
+	number := uint64(1)
+	_, err := DecodeHandler(DecodeOptions{AccountNumber: &number, Tx: hex.EncodeToString(bz)})
+	assert.Error(t, err)
+}
@@ -2,12 +2,20 @@ package client
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
+	"sync"
+
+	"github.com/gnolang/gno/pkgs/bft/rpc/client"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/keys"
+	"github.com/gnolang/gno/pkgs/errors"
 )
 
 type BaseOptions struct {
 	Home   string `flag:"home" help:"home directory"`
-	Remote string `flag:"remote" help:"remote node URL (default 127.0.0.1:26657)"`
+	Remote string `flag:"remote" help:"remote node URL, or chain ID of the chain registry (default 127.0.0.1:26657)"`
 	Quiet  bool   `flag:"quiet" help:"for parsing output"`
 }
 
@@ -32,3 +40,108 @@ func homeDir() string {
 	}
 	return fmt.Sprintf("%s/.gno", hd)
 }
+
+// Returns the client of remote, resolved from the chain registry if it's a
+// known chain ID.
+func dialRemote(remote string) (*client.HTTP, error) {
+	reg, err := client.DefaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := reg.Chain(remote); ok {
+		return reg.Dial(remote)
+	}
+	return client.NewHTTP(remote, "/websocket"), nil
+}
+
+// KeySourceOptions are the options of the non-interactive signing, e.g. in
+// deployment pipelines: the mnemonic of the key is read from an environment
+// variable or a file descriptor, instead of the keybase, and there is no
+// password prompt.
+type KeySourceOptions struct {
+	KeyEnv string `flag:"key-env" help:"environment variable of the mnemonic of the key to sign with, instead of the keybase"`
+	KeyFD  *int   `flag:"key-fd" help:"file descriptor to read the mnemonic of the key to sign with from, instead of the keybase"`
+}
+
+// IsSet returns whether the key is read from an environment variable or a
+// file descriptor.
+func (opts KeySourceOptions) IsSet() bool {
+	return opts.KeyEnv != "" || opts.KeyFD != nil
+}
+
+// Keybase returns the keybase with the key nameOrBech32: the keybase in
+// home, or if the key source is set, an in-memory keybase with the key of
+// the mnemonic, unencrypted.  The key is named nameOrBech32, or if it's an
+// address, it must be that of the key.
+func (opts KeySourceOptions) Keybase(home, nameOrBech32 string) (keys.Keybase, error) {
+	if !opts.IsSet() {
+		return keys.NewKeyBaseFromDir(home)
+	}
+	mnemonic, err := opts.mnemonic()
+	if err != nil {
+		return nil, err
+	}
+	name := nameOrBech32
+	addr, addrErr := crypto.AddressFromBech32(nameOrBech32)
+	if addrErr == nil {
+		name = "key"
+	}
+	kb := keys.NewInMemory()
+	info, err := kb.CreateAccount(name, mnemonic, "", "", 0, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating key from mnemonic")
+	}
+	if addrErr == nil && info.GetAddress() != addr {
+		return nil, errors.New("key of the mnemonic is %s, not %s", info.GetAddress(), addr)
+	}
+	return kb, nil
+}
+
+func (opts KeySourceOptions) mnemonic() (string, error) {
+	var mnemonic string
+	switch {
+	case opts.KeyEnv != "" && opts.KeyFD != nil:
+		return "", errors.New("key-env and key-fd are exclusive")
+	case opts.KeyEnv != "":
+		mnemonic = os.Getenv(opts.KeyEnv)
+		if mnemonic == "" {
+			return "", errors.New("$%s not set", opts.KeyEnv)
+		}
+	default:
+		var err error
+		mnemonic, err = readKeyFD(*opts.KeyFD)
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(mnemonic), nil
+}
+
+// The contents read from the file descriptors, each read once, e.g. as
+// pipes can't be read again.
+var (
+	keyFDsMtx sync.Mutex
+	keyFDs    = make(map[int]string)
+)
+
+func readKeyFD(fd int) (string, error) {
+	keyFDsMtx.Lock()
+	defer keyFDsMtx.Unlock()
+	if content, ok := keyFDs[fd]; ok {
+		return content, nil
+	}
+	if fd < 0 {
+		return "", errors.New("invalid key-fd %d", fd)
+	}
+	file := os.NewFile(uintptr(fd), fmt.Sprintf("key-fd %d", fd))
+	if file == nil {
+		return "", errors.New("invalid key-fd %d", fd)
+	}
+	defer file.Close()
+	bz, err := ioutil.ReadAll(file)
+	if err != nil {
+		return "", errors.Wrap(err, "reading key-fd %d", fd)
+	}
+	keyFDs[fd] = string(bz)
+	return keyFDs[fd], nil
+}
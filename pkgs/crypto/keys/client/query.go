@@ -1,19 +1,22 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/gnolang/gno/pkgs/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
 	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/errors"
 )
 
 type QueryOptions struct {
 	BaseOptions        // home,remote,...
-	Data        []byte `flag:"data" help:"query data bytes"`                        // <pkgpath>\n<expr> for queryexprs.
-	Height      int64  `flag:"height" help:"query height (not yet supported)"`      // not yet used
+	Data        []byte `flag:"data" help:"query data bytes"` // <pkgpath>\n<expr> for queryexprs.
+	Height      int64  `flag:"height" help:"query height, or 0 for the latest"`
 	Prove       bool   `flag:"prove" help:"prove query result (not yet supported)"` // not yet used
+	JSON        bool   `flag:"json" help:"print the height and data as JSON"`
 
 	// internal
 	Path string `flag:"-"`
@@ -23,7 +26,38 @@ var DefaultQueryOptions = QueryOptions{
 	BaseOptions: DefaultBaseOptions,
 }
 
+// The typed query subcommands, for the common paths, e.g.
+// `query bank/balance --address <addr>` for `query bank/balances/<addr>`.
+var queryApps AppList = []AppItem{
+	{queryBalanceApp, "bank/balance", "query the balance of an account", DefaultQueryBalanceOptions},
+	{queryAccountApp, "auth/account", "query an account", DefaultQueryAccountOptions},
+	{queryEvalApp, "vm/qeval", "evaluate an expression in a package", DefaultQueryEvalOptions},
+	{queryPackageApp, "vm/package", "query the metadata of a package", DefaultQueryPackageOptions},
+}
+
 func queryApp(cmd *command.Command, args []string, iopts interface{}) error {
+	// show help message.
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" {
+		cmd.Println("Usage: query <path> [--data <data>]")
+		cmd.Println("or: query <subcommand>, available subcommands:")
+		for _, appItem := range queryApps {
+			cmd.Printf("  %s - %s\n", appItem.Name, appItem.Desc)
+		}
+		return nil
+	}
+
+	// switch on first argument.
+	for _, appItem := range queryApps {
+		if appItem.Name == args[0] {
+			return cmd.Run(appItem.App, args[1:], appItem.Defaults)
+		}
+	}
+
+	// any other path.
+	return cmd.Run(queryPathApp, args, DefaultQueryOptions)
+}
+
+func queryPathApp(cmd *command.Command, args []string, iopts interface{}) error {
 	var opts QueryOptions = iopts.(QueryOptions)
 
 	if len(args) != 1 {
@@ -32,6 +66,11 @@ func queryApp(cmd *command.Command, args []string, iopts interface{}) error {
 	}
 	opts.Path = args[0]
 
+	return queryPrint(cmd, opts)
+}
+
+// Makes the query of opts and prints the result.
+func queryPrint(cmd *command.Command, opts QueryOptions) error {
 	qres, err := QueryHandler(opts)
 	if err != nil {
 		return err
@@ -42,6 +81,14 @@ func queryApp(cmd *command.Command, args []string, iopts interface{}) error {
 			qres.Response.Log)
 		return qres.Response.Error
 	}
+	if opts.JSON {
+		bz, err := queryResultJSON(qres)
+		if err != nil {
+			return err
+		}
+		cmd.Println(string(bz))
+		return nil
+	}
 	resdata := qres.Response.Data
 	// XXX in general, how do we know what to show?
 	// proof := qres.Response.Proof
@@ -52,6 +99,158 @@ func queryApp(cmd *command.Command, args []string, iopts interface{}) error {
 	return nil
 }
 
+// QueryResult is the JSON output of a query.
+type QueryResult struct {
+	Height int64           `json:"height"`
+	Data   json.RawMessage `json:"data"` // as is if JSON, or as a string
+}
+
+// Returns the JSON output of qres.
+func queryResultJSON(qres *ctypes.ResultABCIQuery) ([]byte, error) {
+	data := qres.Response.Data
+	if !json.Valid(data) {
+		bz, err := json.Marshal(string(data))
+		if err != nil {
+			return nil, err
+		}
+		data = bz
+	}
+	return json.MarshalIndent(QueryResult{
+		Height: qres.Response.Height,
+		Data:   data,
+	}, "", "  ")
+}
+
+//----------------------------------------
+// typed queries
+
+type QueryCommonOptions struct {
+	Height int64 `flag:"height" help:"query height, or 0 for the latest"`
+	JSON   bool  `flag:"json" help:"print the height and data as JSON"`
+}
+
+// Returns the query options of a typed query, for path and data.
+func (opts QueryCommonOptions) query(bopts BaseOptions, path string, data []byte) QueryOptions {
+	return QueryOptions{
+		BaseOptions: bopts,
+		Data:        data,
+		Height:      opts.Height,
+		JSON:        opts.JSON,
+		Path:        path,
+	}
+}
+
+type QueryBalanceOptions struct {
+	BaseOptions               // home,remote,...
+	QueryCommonOptions        // height,json
+	Address            string `flag:"address" help:"bech32 address of the account (required)"`
+}
+
+var DefaultQueryBalanceOptions = QueryBalanceOptions{
+	BaseOptions: DefaultBaseOptions,
+}
+
+// Query returns the query options of `bank/balances/<address>`.
+func (opts QueryBalanceOptions) Query() (QueryOptions, error) {
+	if _, err := crypto.AddressFromBech32(opts.Address); err != nil {
+		return QueryOptions{}, errors.Wrap(err, "invalid address %q", opts.Address)
+	}
+	return opts.query(opts.BaseOptions, "bank/balances/"+opts.Address, nil), nil
+}
+
+func queryBalanceApp(cmd *command.Command, args []string, iopts interface{}) error {
+	return queryTypedApp(cmd, args, iopts.(QueryBalanceOptions), "bank/balance")
+}
+
+type QueryAccountOptions struct {
+	BaseOptions               // home,remote,...
+	QueryCommonOptions        // height,json
+	Address            string `flag:"address" help:"bech32 address of the account (required)"`
+}
+
+var DefaultQueryAccountOptions = QueryAccountOptions{
+	BaseOptions: DefaultBaseOptions,
+}
+
+// Query returns the query options of `auth/accounts/<address>`.
+func (opts QueryAccountOptions) Query() (QueryOptions, error) {
+	if _, err := crypto.AddressFromBech32(opts.Address); err != nil {
+		return QueryOptions{}, errors.Wrap(err, "invalid address %q", opts.Address)
+	}
+	return opts.query(opts.BaseOptions, "auth/accounts/"+opts.Address, nil), nil
+}
+
+func queryAccountApp(cmd *command.Command, args []string, iopts interface{}) error {
+	return queryTypedApp(cmd, args, iopts.(QueryAccountOptions), "auth/account")
+}
+
+type QueryEvalOptions struct {
+	BaseOptions               // home,remote,...
+	QueryCommonOptions        // height,json
+	PkgPath            string `flag:"pkgpath" help:"package path (required)"`
+	Expr               string `flag:"expr" help:"expression to evaluate in the package (required)"`
+}
+
+var DefaultQueryEvalOptions = QueryEvalOptions{
+	BaseOptions: DefaultBaseOptions,
+}
+
+// Query returns the query options of `vm/qeval`, with the data
+// "<pkgpath>\n<expr>".
+func (opts QueryEvalOptions) Query() (QueryOptions, error) {
+	if opts.PkgPath == "" {
+		return QueryOptions{}, errors.New("pkgpath not specified")
+	}
+	if opts.Expr == "" {
+		return QueryOptions{}, errors.New("expr not specified")
+	}
+	data := []byte(opts.PkgPath + "\n" + opts.Expr)
+	return opts.query(opts.BaseOptions, "vm/qeval", data), nil
+}
+
+func queryEvalApp(cmd *command.Command, args []string, iopts interface{}) error {
+	return queryTypedApp(cmd, args, iopts.(QueryEvalOptions), "vm/qeval")
+}
+
+type QueryPackageOptions struct {
+	BaseOptions               // home,remote,...
+	QueryCommonOptions        // height,json
+	PkgPath            string `flag:"pkgpath" help:"package path (required)"`
+}
+
+var DefaultQueryPackageOptions = QueryPackageOptions{
+	BaseOptions: DefaultBaseOptions,
+}
+
+// Query returns the query options of `vm/qpkginfo`, the metadata of the
+// package as JSON.
+func (opts QueryPackageOptions) Query() (QueryOptions, error) {
+	if opts.PkgPath == "" {
+		return QueryOptions{}, errors.New("pkgpath not specified")
+	}
+	return opts.query(opts.BaseOptions, "vm/qpkginfo", []byte(opts.PkgPath)), nil
+}
+
+func queryPackageApp(cmd *command.Command, args []string, iopts interface{}) error {
+	return queryTypedApp(cmd, args, iopts.(QueryPackageOptions), "vm/package")
+}
+
+type typedQuery interface {
+	Query() (QueryOptions, error)
+}
+
+func queryTypedApp(cmd *command.Command, args []string, tq typedQuery, name string) error {
+	if len(args) != 0 {
+		cmd.ErrPrintfln("Usage: query %s [flags]", name)
+		return errors.New("invalid args")
+	}
+	opts, err := tq.Query()
+	if err != nil {
+		return err
+	}
+	return queryPrint(cmd, opts)
+}
+
 func QueryHandler(opts QueryOptions) (*ctypes.ResultABCIQuery, error) {
 	remote := opts.Remote
 	if remote == "" || remote == "y" {
@@ -60,10 +259,13 @@ func QueryHandler(opts QueryOptions) (*ctypes.ResultABCIQuery, error) {
 
 	data := opts.Data
 	opts2 := client.ABCIQueryOptions{
-		// Height: height, XXX
+		Height: opts.Height,
 		// Prove: false, XXX
 	}
-	cli := client.NewHTTP(remote, "/websocket")
+	cli, err := dialRemote(remote)
+	if err != nil {
+		return nil, err
+	}
 	qres, err := cli.ABCIQueryWithOptions(
 		opts.Path, data, opts2)
 	if err != nil {
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	"github.com/gnolang/gno/pkgs/crypto/secp256k1"
+	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
+)
+
+func Test_typedQueries(t *testing.T) {
+	addr := secp256k1.GenPrivKey().PubKey().Address().String()
+	common := QueryCommonOptions{Height: 10, JSON: true}
+
+	opts, err := QueryBalanceOptions{QueryCommonOptions: common, Address: addr}.Query()
+	require.NoError(t, err)
+	assert.Equal(t, "bank/balances/"+addr, opts.Path)
+	assert.Empty(t, opts.Data)
+	assert.Equal(t, int64(10), opts.Height)
+	assert.True(t, opts.JSON)
+
+	opts, err = QueryAccountOptions{Address: addr}.Query()
+	require.NoError(t, err)
+	assert.Equal(t, "auth/accounts/"+addr, opts.Path)
+
+	opts, err = QueryEvalOptions{PkgPath: "gno.land/r/demo/boards", Expr: "GetBoardIDFromName(\"x\")"}.Query()
+	require.NoError(t, err)
+	assert.Equal(t, "vm/qeval", opts.Path)
+	assert.Equal(t, "gno.land/r/demo/boards\nGetBoardIDFromName(\"x\")", string(opts.Data))
+
+	opts, err = QueryPackageOptions{PkgPath: "gno.land/p/demo/avl"}.Query()
+	require.NoError(t, err)
+	assert.Equal(t, "vm/qpkginfo", opts.Path)
+	assert.Equal(t, "gno.land/p/demo/avl", string(opts.Data))
+
+	// errors.
+	for _, tq := range []typedQuery{
+		QueryBalanceOptions{},
+		QueryAccountOptions{Address: "not an address"},
+		QueryEvalOptions{PkgPath: "gno.land/r/demo/boards"},
+		QueryEvalOptions{Expr: "1"},
+		QueryPackageOptions{},
+	} {
+		_, err := tq.Query()
+		assert.Error(t, err, "%#v", tq)
+	}
+}
+
+func Test_queryResultJSON(t *testing.T) {
+	for _, tc := range []struct {
+		data     string
+		expected string
+	}{
+		{`[{"denom":"ugnot"}]`, `[{"denom":"ugnot"}]`},
+		{`(1 int)`, `"(1 int)"`},
+		{``, `""`},
+	} {
+		bz, err := queryResultJSON(&ctypes.ResultABCIQuery{
+			Response: abci.ResponseQuery{ResponseBase: abci.ResponseBase{Data: []byte(tc.data)}, Height: 3},
+		})
+		require.NoError(t, err)
+		var res struct {
+			Height int64
+			Data   json.RawMessage
+		}
+		require.NoError(t, json.Unmarshal(bz, &res))
+		assert.Equal(t, int64(3), res.Height)
+		assert.JSONEq(t, tc.expected, string(res.Data))
+	}
+}
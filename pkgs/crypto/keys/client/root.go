@@ -1,10 +1,17 @@
 package client
 
 import (
+	"os"
+
 	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/errors"
 )
 
+// Bech32PrefixEnv is the environment variable of the bech32 prefix of the
+// addresses, for the keys of another chain than gno.land.
+const Bech32PrefixEnv = "GNO_BECH32_PREFIX"
+
 type (
 	AppItem = command.AppItem
 	AppList = command.AppList
@@ -18,7 +25,9 @@ var mainApps AppList = []AppItem{
 	{signApp, "sign", "sign a document", DefaultSignOptions},
 	{verifyApp, "verify", "verify a document signature", DefaultVerifyOptions},
 	{broadcastApp, "broadcast", "broadcast a signed document", DefaultBroadcastOptions},
-	{queryApp, "query", "make an ABCI query", DefaultQueryOptions},
+	{queryApp, "query", "make an ABCI query", nil},
+	{txApp, "tx", "wait for a broadcast tx", nil},
+	{decodeApp, "decode", "decode a tx to human-readable JSON", DefaultDecodeOptions},
 }
 
 // For clients that want to extend the functionality of the base client.
@@ -32,6 +41,12 @@ func AddApp(app command.App, name string, desc string, defaults interface{}) {
 }
 
 func RunMain(cmd *command.Command, exec string, args []string) error {
+	if prefix := os.Getenv(Bech32PrefixEnv); prefix != "" {
+		if err := crypto.SetBech32Prefix(prefix); err != nil {
+			return errors.Wrap(err, "invalid $%s", Bech32PrefixEnv)
+		}
+	}
+
 	// show help message.
 	if len(args) == 0 || args[0] == "help" || args[0] == "--help" {
 		cmd.Println("available subcommands:")
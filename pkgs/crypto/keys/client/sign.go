@@ -1,23 +1,30 @@
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/gnolang/gno/pkgs/amino"
 	"github.com/gnolang/gno/pkgs/command"
-	"github.com/gnolang/gno/pkgs/crypto/keys"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/webauthn"
 	"github.com/gnolang/gno/pkgs/errors"
 	"github.com/gnolang/gno/pkgs/std"
 )
 
 type SignOptions struct {
-	BaseOptions           // home,...
-	TxPath        string  `flag:"txpath" help:"path to file of tx to sign"`
-	ChainID       string  `flag:"chainid" help:"chainid to sign for"`
-	AccountNumber *uint64 `flag:"number" help:"account number to sign with (required)"`
-	Sequence      *uint64 `flag:"sequence" help:"sequence to sign with (required)"`
-	ShowSignBytes bool    `flag:"show-signbytes" help:"show sign bytes and quit"`
+	BaseOptions              // home,...
+	KeySourceOptions         // key-env, key-fd
+	TxPath           string  `flag:"txpath" help:"path to file of tx to sign"`
+	ChainID          string  `flag:"chainid" help:"chainid to sign for"`
+	AccountNumber    *uint64 `flag:"number" help:"account number to sign with (required)"`
+	Sequence         *uint64 `flag:"sequence" help:"sequence to sign with (required)"`
+	ShowSignBytes    bool    `flag:"show-signbytes" help:"show sign bytes and quit"`
+	Signer           string  `flag:"signer" help:"address of the signer to sign for, if not the key, e.g. the granter of a session key"`
+	Yes              bool    `flag:"yes" help:"sign without confirming the preview of the tx"`
 
 	// internal flags, when called programatically
 	NameOrBech32 string `flag:"-"`
@@ -55,13 +62,27 @@ func signApp(cmd *command.Command, args []string, iopts interface{}) error {
 		}
 	}
 
-	if opts.Quiet {
-		opts.Pass, err = cmd.GetPassword("")
-	} else {
-		opts.Pass, err = cmd.GetPassword("Enter password.")
+	// preview the tx, and confirm.
+	if !opts.Yes {
+		ok, err := confirmSign(cmd, opts)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("signing aborted")
+		}
 	}
-	if err != nil {
-		return err
+
+	// the key of a key source is unencrypted.
+	if !opts.KeySourceOptions.IsSet() {
+		if opts.Quiet {
+			opts.Pass, err = cmd.GetPassword("")
+		} else {
+			opts.Pass, err = cmd.GetPassword("Enter password.")
+		}
+		if err != nil {
+			return err
+		}
 	}
 
 	signedTx, err := SignHandler(opts)
@@ -78,6 +99,98 @@ func signApp(cmd *command.Command, args []string, iopts interface{}) error {
 	return nil
 }
 
+// Shows the preview of the tx to sign, and returns whether the user
+// confirms signing it.
+func confirmSign(cmd *command.Command, opts SignOptions) (bool, error) {
+	var tx std.Tx
+	err := amino.UnmarshalJSON(opts.TxJson, &tx)
+	if err != nil {
+		return false, err
+	}
+	kb, err := opts.Keybase(opts.Home, opts.NameOrBech32)
+	if err != nil {
+		return false, err
+	}
+	info, err := kb.GetByNameOrAddress(opts.NameOrBech32)
+	if err != nil {
+		return false, err
+	}
+	signer := info.GetAddress().String()
+	if opts.Signer != "" {
+		signer = opts.Signer
+	}
+	preview, err := TxPreview(tx, opts.ChainID, signer)
+	if err != nil {
+		return false, err
+	}
+	// On stderr so it isn't part of the signed tx output.
+	cmd.ErrPrintln(preview)
+	return cmd.GetConfirmation("Sign this tx?")
+}
+
+// The max length of the values of the msg fields in a preview, e.g. of the
+// files of a package.
+const previewMaxValueLen = 120
+
+// TxPreview returns the human-readable summary of tx, to be confirmed before
+// signing it for chainID by signer: its msgs, with their fields in order,
+// its fee and memo.
+func TxPreview(tx std.Tx, chainID string, signer string) (string, error) {
+	decoded, err := DecodeTx(tx)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "chain-id: %s\n", chainID)
+	fmt.Fprintf(&sb, "signer:   %s\n", signer)
+	for i, msg := range decoded.Msgs {
+		fmt.Fprintf(&sb, "msg #%d:   %s/%s\n", i+1, msg.Route, msg.Type)
+		fields, err := previewFields(msg.Value)
+		if err != nil {
+			return "", errors.Wrap(err, "previewing msg #%d", i+1)
+		}
+		for _, field := range fields {
+			fmt.Fprintf(&sb, "  %s: %s\n", field[0], field[1])
+		}
+	}
+	fmt.Fprintf(&sb, "fee:      %s for %d gas\n", decoded.GasFee, decoded.GasWanted)
+	fmt.Fprintf(&sb, "memo:     %q", decoded.Memo)
+	return sb.String(), nil
+}
+
+// Returns the name and value of the fields of the JSON object bz, in order.
+// Strings are unquoted, and long values truncated.
+func previewFields(bz []byte) ([][2]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("expected a JSON object")
+	}
+	var fields [][2]string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if name == "@type" {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil || value == "" {
+			value = string(raw)
+		}
+		if len(value) > previewMaxValueLen {
+			value = fmt.Sprintf("%s... (%d bytes)", value[:previewMaxValueLen], len(value))
+		}
+		fields = append(fields, [2]string{name, value})
+	}
+	return fields, nil
+}
+
 func SignHandler(opts SignOptions) (*std.Tx, error) {
 	var err error
 	var tx std.Tx
@@ -92,7 +205,7 @@ func SignHandler(opts SignOptions) (*std.Tx, error) {
 		return nil, errors.New("invalid tx content")
 	}
 
-	kb, err := keys.NewKeyBaseFromDir(opts.Home)
+	kb, err := opts.Keybase(opts.Home, opts.NameOrBech32)
 	if err != nil {
 		return nil, err
 	}
@@ -102,53 +215,50 @@ func SignHandler(opts SignOptions) (*std.Tx, error) {
 		return nil, err
 	}
 
-	// fill tx signatures.
-	signers := tx.GetSigners()
-	if tx.Signatures == nil {
-		for range signers {
-			tx.Signatures = append(tx.Signatures, std.Signature{
-				PubKey:    nil, // zero signature
-				Signature: nil, // zero signature
-			})
+	// validate the given signatures, if any; the builder fills the
+	// missing ones.
+	if tx.Signatures != nil {
+		err = tx.ValidateBasic()
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	// validate document to sign.
-	err = tx.ValidateBasic()
+	info, err := kb.GetByNameOrAddress(opts.NameOrBech32)
 	if err != nil {
 		return nil, err
 	}
+	signer := info.GetAddress()
+	if opts.Signer != "" {
+		signer, err = crypto.AddressFromBech32(opts.Signer)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing signer address")
+		}
+	}
+	b := NewTxBuilderFromTx(opts.ChainID, tx)
+	b.SetSignerInfo(SignerInfo{
+		Address:       signer,
+		AccountNumber: *opts.AccountNumber,
+		Sequence:      *opts.Sequence,
+	})
 
 	// derive sign doc bytes.
-	chainID := opts.ChainID
-	accountNumber := *opts.AccountNumber
-	sequence := *opts.Sequence
-	signbz := tx.GetSignBytes(chainID, accountNumber, sequence)
 	if opts.ShowSignBytes {
+		signbz, err := b.SignBytes(signer)
+		if err != nil {
+			return nil, err
+		}
 		fmt.Printf("sign bytes: %X\n", signbz)
+		if _, ok := info.GetPubKey().(webauthn.PubKeyWebAuthn); ok {
+			fmt.Printf("webauthn challenge: %s\n", webauthn.ChallengeString(signbz))
+		}
 		return nil, nil
 	}
 
-	sig, pub, err := kb.Sign(opts.NameOrBech32, opts.Pass, signbz)
+	err = b.SignFor(signer, kb, opts.NameOrBech32, opts.Pass)
 	if err != nil {
 		return nil, err
 	}
-	addr := pub.Address()
-	found := false
-	for i := range tx.Signatures {
-		// override signature for matching slot.
-		if signers[i] == addr {
-			found = true
-			tx.Signatures[i] = std.Signature{
-				PubKey:    pub,
-				Signature: sig,
-			}
-		}
-	}
-	if !found {
-		return nil, errors.New("addr %v (%s) not in signer set",
-			addr, opts.NameOrBech32)
-	}
-
+	tx = b.Tx()
 	return &tx, nil
 }
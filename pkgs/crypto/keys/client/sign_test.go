@@ -8,10 +8,12 @@ import (
 	"github.com/gnolang/gno/pkgs/amino"
 	"github.com/gnolang/gno/pkgs/command"
 	"github.com/gnolang/gno/pkgs/crypto/keys"
+	"github.com/gnolang/gno/pkgs/crypto/secp256k1"
 	testutils2 "github.com/gnolang/gno/pkgs/sdk/testutils"
 	"github.com/gnolang/gno/pkgs/std"
 	"github.com/gnolang/gno/pkgs/testutils"
 	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
 )
 
 func Test_signAppBasic(t *testing.T) {
@@ -79,5 +81,128 @@ func Test_signAppBasic(t *testing.T) {
 	))
 	args = []string{fakeKeyName1}
 	err = signApp(cmd, args, opts)
+	assert.Error(t, err) // not confirmed.
+
+	cmd.SetIn(strings.NewReader(
+		fmt.Sprintf("%s\nn\n%s\n",
+			txjson,
+			encPassword,
+		),
+	))
+	args = []string{fakeKeyName1}
+	err = signApp(cmd, args, opts)
+	assert.Error(t, err)
+
+	cmd.SetIn(strings.NewReader(
+		fmt.Sprintf("%s\ny\n%s\n",
+			txjson,
+			encPassword,
+		),
+	))
+	args = []string{fakeKeyName1}
+	err = signApp(cmd, args, opts)
 	assert.NoError(t, err)
+
+	// without confirmation.
+	opts.Yes = true
+	cmd.SetIn(strings.NewReader(
+		fmt.Sprintf("%s\n%s\n",
+			txjson,
+			encPassword,
+		),
+	))
+	args = []string{fakeKeyName1}
+	err = signApp(cmd, args, opts)
+	assert.NoError(t, err)
+}
+
+func Test_TxPreview(t *testing.T) {
+	kb := keys.NewInMemory()
+	acc, err := kb.CreateAccount("key", testMnemonic, "", "pass", 0, 0)
+	require.NoError(t, err)
+	addr := acc.GetAddress()
+
+	msg := testutils2.NewTestMsg(addr)
+	fee := std.NewFee(2000, std.NewCoin("ugnot", 10))
+	tx := std.NewTx([]std.Msg{msg, msg}, fee, nil, "hi")
+	preview, err := TxPreview(tx, "dev", addr.String())
+	require.NoError(t, err)
+	assert.Contains(t, preview, "chain-id: dev\n")
+	assert.Contains(t, preview, "signer:   "+addr.String()+"\n")
+	assert.Contains(t, preview, "msg #1:   "+msg.Route()+"/"+msg.Type()+"\n")
+	assert.Contains(t, preview, "msg #2:   ")
+	assert.Contains(t, preview, addr.String())
+	assert.Contains(t, preview, "fee:      10ugnot for 2000 gas\n")
+	assert.Contains(t, preview, `memo:     "hi"`)
+	assert.NotContains(t, preview, "@type")
+
+	// fields in order, strings unquoted, long values truncated.
+	fields, err := previewFields([]byte(`{"@type":"/x","b":"str","a":["x"],"c":"","d":"` + strings.Repeat("z", 200) + `"}`))
+	require.NoError(t, err)
+	require.Len(t, fields, 4)
+	assert.Equal(t, [2]string{"b", "str"}, fields[0])
+	assert.Equal(t, [2]string{"a", `["x"]`}, fields[1])
+	assert.Equal(t, [2]string{"c", `""`}, fields[2])
+	assert.Equal(t, strings.Repeat("z", previewMaxValueLen)+"... (200 bytes)", fields[3][1])
+	_, err = previewFields([]byte(`["not an object"]`))
+	assert.Error(t, err)
+}
+
+func Test_signAppKeySource(t *testing.T) {
+	cmd := command.NewMockCommand()
+
+	// the keybase in home is empty.
+	kbHome, kbCleanUp := testutils.NewTestCaseDir(t)
+	defer kbCleanUp()
+
+	kb := keys.NewInMemory()
+	acc, err := kb.CreateAccount("key", testMnemonic, "", "pass", 0, 0)
+	require.NoError(t, err)
+	addr := acc.GetAddress()
+	other := secp256k1.GenPrivKey().PubKey().Address()
+
+	msg := testutils2.NewTestMsg(addr)
+	fee := std.NewFee(1, std.NewCoin("ugnot", 1000000))
+	tx := std.NewTx([]std.Msg{msg}, fee, nil, "")
+	txjson := string(amino.MustMarshalJSON(tx))
+
+	opts := SignOptions{
+		BaseOptions: BaseOptions{
+			Home: kbHome,
+		},
+		KeySourceOptions: KeySourceOptions{
+			KeyEnv: "GNOKEY_TEST_MNEMONIC",
+		},
+		TxPath:        "-", // stdin
+		ChainID:       "dev",
+		AccountNumber: new(uint64),
+		Sequence:      new(uint64),
+		Yes:           true,
+	}
+
+	// the variable must be set.
+	cmd.SetIn(strings.NewReader(txjson + "\n"))
+	assert.Error(t, signApp(cmd, []string{"ci"}, opts))
+
+	// no password, by name or address of the key.
+	t.Setenv("GNOKEY_TEST_MNEMONIC", testMnemonic)
+	for _, nameOrBech32 := range []string{"ci", addr.String()} {
+		out := new(strings.Builder)
+		cmd.SetOut(command.WriteNopCloser(out))
+		cmd.SetIn(strings.NewReader(txjson + "\n"))
+		require.NoError(t, signApp(cmd, []string{nameOrBech32}, opts))
+		var signed std.Tx
+		require.NoError(t, amino.UnmarshalJSON([]byte(out.String()), &signed))
+		assert.True(t, acc.GetPubKey().VerifyBytes(tx.GetSignBytes("dev", 0, 0), signed.Signatures[0].Signature))
+	}
+
+	// not the key of the mnemonic.
+	cmd.SetIn(strings.NewReader(txjson + "\n"))
+	assert.Error(t, signApp(cmd, []string{other.String()}, opts))
+
+	// exclusive.
+	fd := 0
+	opts.KeyFD = &fd
+	_, err = opts.Keybase(kbHome, "ci")
+	assert.Error(t, err)
 }
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	"github.com/gnolang/gno/pkgs/command"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+var txApps AppList = []AppItem{
+	{txWaitApp, "wait", "wait for the inclusion of a tx in a block", DefaultTxWaitOptions},
+}
+
+func txApp(cmd *command.Command, args []string, iopts interface{}) error {
+	// show help message.
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" {
+		cmd.Println("available subcommands:")
+		for _, appItem := range txApps {
+			cmd.Printf("  %s - %s\n", appItem.Name, appItem.Desc)
+		}
+		return nil
+	}
+
+	// switch on first argument.
+	for _, appItem := range txApps {
+		if appItem.Name == args[0] {
+			return cmd.Run(appItem.App, args[1:], appItem.Defaults)
+		}
+	}
+
+	// unknown app subcommand!
+	return errors.New("unknown subcommand " + args[0])
+}
+
+//----------------------------------------
+// txWaitApp
+
+type TxWaitOptions struct {
+	BaseOptions
+	Timeout string `flag:"timeout" help:"how long to wait for the tx, e.g. 30s"`
+
+	// internal
+	Hash []byte `flag:"-"`
+}
+
+var DefaultTxWaitOptions = TxWaitOptions{
+	BaseOptions: DefaultBaseOptions,
+	Timeout:     "1m",
+}
+
+// The interval of the queries of a tx being waited for.
+var waitTxInterval = time.Second
+
+func txWaitApp(cmd *command.Command, args []string, iopts interface{}) error {
+	var opts TxWaitOptions = iopts.(TxWaitOptions)
+
+	if len(args) != 1 {
+		cmd.ErrPrintfln("Usage: tx wait <hash>")
+		return errors.New("invalid args")
+	}
+	hash, err := parseTxHash(args[0])
+	if err != nil {
+		return err
+	}
+	opts.Hash = hash
+
+	res, err := TxWaitHandler(opts)
+	if err != nil {
+		return err
+	}
+
+	if res.TxResult.IsErr() {
+		return errors.New("transaction failed %#v\nlog %s", res, res.TxResult.Log)
+	}
+	cmd.Println(string(res.TxResult.Data))
+	cmd.Println("OK!")
+	cmd.Println("HEIGHT:    ", res.Height)
+	cmd.Println("GAS WANTED:", res.TxResult.GasWanted)
+	cmd.Println("GAS USED:  ", res.TxResult.GasUsed)
+	cmd.Printf("TX HASH:    %X\n", res.Hash)
+	return nil
+}
+
+// TxWaitHandler waits until the tx of opts.Hash, its hash or canonical
+// hash, is included in a block, and returns it.  A tx included but failed
+// is returned too: check its TxResult.
+func TxWaitHandler(opts TxWaitOptions) (*ctypes.ResultTx, error) {
+	if len(opts.Hash) == 0 {
+		return nil, errors.New("invalid tx hash")
+	}
+	timeout, err := time.ParseDuration(opts.Timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing timeout")
+	}
+
+	remote := opts.Remote
+	if remote == "" || remote == "y" {
+		return nil, errors.New("missing remote url")
+	}
+	cli, err := dialRemote(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return waitTx(cli, opts.Hash, timeout)
+}
+
+// The client queries of a tx by hash.
+type txGetter interface {
+	Tx(hash []byte, prove bool) (*ctypes.ResultTx, error)
+}
+
+// Queries the tx of hash until it is found, or timeout.  The errors of the
+// queries, e.g. "not found" until the tx is included, are retried.
+func waitTx(cli txGetter, hash []byte, timeout time.Duration) (*ctypes.ResultTx, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		res, err := cli.Tx(hash, false)
+		if err == nil {
+			return res, nil
+		}
+		if time.Now().Add(waitTxInterval).After(deadline) {
+			return nil, errors.Wrap(err, "timed out waiting for tx %X", hash)
+		}
+		time.Sleep(waitTxInterval)
+	}
+}
+
+// Returns the bytes of the hex hash, as printed by broadcast.
+func parseTxHash(hash string) ([]byte, error) {
+	bz, err := hex.DecodeString(strings.TrimPrefix(hash, "0x"))
+	if err != nil || len(bz) == 0 {
+		return nil, errors.New("invalid tx hash %q", hash)
+	}
+	return bz, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
+)
+
+// Finds the tx after a number of queries.
+type testTxGetter struct {
+	queries int
+	found   int
+}
+
+func (g *testTxGetter) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	g.queries++
+	if g.queries < g.found || g.found == 0 {
+		return nil, errors.New("Tx (%X) not found", hash)
+	}
+	return &ctypes.ResultTx{Hash: hash, Height: 3}, nil
+}
+
+func Test_waitTx(t *testing.T) {
+	defer func(interval time.Duration) { waitTxInterval = interval }(waitTxInterval)
+	waitTxInterval = 10 * time.Millisecond
+
+	g := &testTxGetter{found: 3}
+	res, err := waitTx(g, []byte{0xAB}, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), res.Height)
+	assert.Equal(t, 3, g.queries)
+
+	// never found.
+	g = &testTxGetter{}
+	_, err = waitTx(g, []byte{0xAB}, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.True(t, g.queries > 1)
+}
+
+func Test_parseTxHash(t *testing.T) {
+	for _, hash := range []string{"AB12", "ab12", "0xAB12"} {
+		bz, err := parseTxHash(hash)
+		require.NoError(t, err, hash)
+		assert.Equal(t, []byte{0xAB, 0x12}, bz)
+	}
+	for _, hash := range []string{"", "0x", "zz", "ABC"} {
+		_, err := parseTxHash(hash)
+		assert.Error(t, err, hash)
+	}
+}
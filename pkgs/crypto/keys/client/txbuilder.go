@@ -0,0 +1,297 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/keys"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+//----------------------------------------
+// SignMode
+
+// A SignMode derives the bytes signed by a signer of a tx.
+type SignMode interface {
+	SignBytes(chainID string, signer SignerInfo, tx std.Tx) ([]byte, error)
+}
+
+// SignModeAminoJSON signs the sorted amino JSON of the std.SignDoc of a tx,
+// as verified by the auth ante handler.  It is the default sign mode.
+var SignModeAminoJSON SignMode = aminoJSONSignMode{}
+
+type aminoJSONSignMode struct{}
+
+func (aminoJSONSignMode) SignBytes(chainID string, signer SignerInfo, tx std.Tx) ([]byte, error) {
+	return tx.GetSignBytes(chainID, signer.AccountNumber, signer.Sequence), nil
+}
+
+//----------------------------------------
+// TxBuilder
+
+// SignerInfo is the account of a signer of a tx.
+type SignerInfo struct {
+	Address       crypto.Address
+	AccountNumber uint64
+	Sequence      uint64
+}
+
+// TxBuilder builds a std.Tx: its msgs, fee and memo are set, then the sign
+// bytes of each of its signers are signed, and the signatures attached.
+// Setting the msgs, fee or memo clears the signatures.
+type TxBuilder struct {
+	chainID   string
+	signMode  SignMode
+	msgs      []std.Msg
+	gasWanted int64
+	gasFee    std.Coin
+	memo      string
+	metadata  map[string]string
+	signers   map[crypto.Address]SignerInfo
+	sigs      map[crypto.Address]std.Signature
+}
+
+// NewTxBuilder returns a builder of a tx for chainID, signed in
+// SignModeAminoJSON.
+func NewTxBuilder(chainID string) *TxBuilder {
+	return &TxBuilder{
+		chainID:  chainID,
+		signMode: SignModeAminoJSON,
+		metadata: make(map[string]string),
+		signers:  make(map[crypto.Address]SignerInfo),
+		sigs:     make(map[crypto.Address]std.Signature),
+	}
+}
+
+// NewTxBuilderFromTx returns a builder of tx, e.g. decoded from a file to be
+// signed, keeping its signatures.
+func NewTxBuilderFromTx(chainID string, tx std.Tx) *TxBuilder {
+	b := NewTxBuilder(chainID)
+	b.msgs = tx.Msgs
+	b.gasWanted = tx.Fee.GasWanted
+	b.gasFee = tx.Fee.GasFee
+	b.memo, b.metadata = ParseMemo(tx.Memo)
+	for i, signer := range tx.GetSigners() {
+		if i < len(tx.Signatures) && tx.Signatures[i].Signature != nil {
+			b.sigs[signer] = tx.Signatures[i]
+		}
+	}
+	return b
+}
+
+func (b *TxBuilder) SetSignMode(mode SignMode) {
+	b.signMode = mode
+	b.clearSignatures()
+}
+
+func (b *TxBuilder) SetMsgs(msgs ...std.Msg) {
+	b.msgs = msgs
+	b.clearSignatures()
+}
+
+// AddMsgs appends msgs to the msgs of the tx, e.g. to compose several
+// calls or sends in one atomic tx, with a single fee and signature per
+// signer.
+func (b *TxBuilder) AddMsgs(msgs ...std.Msg) {
+	b.msgs = append(b.msgs[:len(b.msgs):len(b.msgs)], msgs...)
+	b.clearSignatures()
+}
+
+func (b *TxBuilder) SetGasWanted(gasWanted int64) {
+	b.gasWanted = gasWanted
+	b.clearSignatures()
+}
+
+func (b *TxBuilder) SetGasFee(gasFee std.Coin) {
+	b.gasFee = gasFee
+	b.clearSignatures()
+}
+
+func (b *TxBuilder) SetMemo(memo string) {
+	b.memo = memo
+	b.clearSignatures()
+}
+
+// SetMetadata sets the value of key in the metadata of the tx, which is
+// encoded in its memo (see ParseMemo).  An empty value deletes the key.
+func (b *TxBuilder) SetMetadata(key, value string) {
+	if value == "" {
+		delete(b.metadata, key)
+	} else {
+		b.metadata[key] = value
+	}
+	b.clearSignatures()
+}
+
+// SetSignerInfo sets the account number and sequence of a signer.
+func (b *TxBuilder) SetSignerInfo(info SignerInfo) {
+	b.signers[info.Address] = info
+}
+
+func (b *TxBuilder) clearSignatures() {
+	b.sigs = make(map[crypto.Address]std.Signature)
+}
+
+// Signers returns the addresses which must sign the tx, in order.
+func (b *TxBuilder) Signers() []crypto.Address {
+	return b.unsignedTx().GetSigners()
+}
+
+// SignBytes returns the bytes to be signed by the signer addr, whose info
+// must be set.
+func (b *TxBuilder) SignBytes(addr crypto.Address) ([]byte, error) {
+	info, ok := b.signers[addr]
+	if !ok {
+		return nil, errors.New("no signer info for %s", addr)
+	}
+	if !b.isSigner(addr) {
+		return nil, errors.New("addr %s not in signer set", addr)
+	}
+	tx := b.unsignedTx()
+	if err := tx.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return b.signMode.SignBytes(b.chainID, info, tx)
+}
+
+// AddSignature attaches the signature sig of the sign bytes of the signer
+// of pubKey, after verifying it.
+func (b *TxBuilder) AddSignature(pubKey crypto.PubKey, sig []byte) error {
+	return b.AddSignatureFor(pubKey.Address(), pubKey, sig)
+}
+
+// AddSignatureFor attaches the signature sig of the sign bytes of signer by
+// pubKey, after verifying it.  pubKey may be another key than that of
+// signer, e.g. a session key granted by signer (see the authz module).
+func (b *TxBuilder) AddSignatureFor(signer crypto.Address, pubKey crypto.PubKey, sig []byte) error {
+	signbz, err := b.SignBytes(signer)
+	if err != nil {
+		return err
+	}
+	if !pubKey.VerifyBytes(signbz, sig) {
+		return errors.New("invalid signature of %s", pubKey.Address())
+	}
+	b.sigs[signer] = std.Signature{
+		PubKey:    pubKey,
+		Signature: sig,
+	}
+	return nil
+}
+
+// Sign signs the tx with the key nameOrBech32 of kb, and attaches the
+// signature.
+func (b *TxBuilder) Sign(kb keys.Keybase, nameOrBech32, pass string) error {
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	return b.SignFor(info.GetAddress(), kb, nameOrBech32, pass)
+}
+
+// SignFor signs the tx for signer with the key nameOrBech32 of kb, e.g. a
+// session key granted by signer, and attaches the signature.
+func (b *TxBuilder) SignFor(signer crypto.Address, kb keys.Keybase, nameOrBech32, pass string) error {
+	signbz, err := b.SignBytes(signer)
+	if err != nil {
+		return err
+	}
+	sig, pubKey, err := kb.Sign(nameOrBech32, pass, signbz)
+	if err != nil {
+		return err
+	}
+	return b.AddSignatureFor(signer, pubKey, sig)
+}
+
+// Tx returns the tx, with zero signatures for its missing signers.
+func (b *TxBuilder) Tx() std.Tx {
+	tx := b.unsignedTx()
+	for i, signer := range tx.GetSigners() {
+		if sig, ok := b.sigs[signer]; ok {
+			tx.Signatures[i] = sig
+		}
+	}
+	return tx
+}
+
+// Build returns the tx, after checking that it is signed by all its
+// signers.
+func (b *TxBuilder) Build() (std.Tx, error) {
+	tx := b.Tx()
+	if err := tx.ValidateBasic(); err != nil {
+		return std.Tx{}, err
+	}
+	for _, signer := range tx.GetSigners() {
+		if _, ok := b.sigs[signer]; !ok {
+			return std.Tx{}, errors.New("missing signature of %s", signer)
+		}
+	}
+	return tx, nil
+}
+
+// Marshal returns the amino encoding of the built tx, to be broadcast.
+func (b *TxBuilder) Marshal() ([]byte, error) {
+	tx, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return amino.Marshal(tx)
+}
+
+// Returns the tx with zero signatures.
+func (b *TxBuilder) unsignedTx() std.Tx {
+	tx := std.Tx{
+		Msgs: b.msgs,
+		Fee:  std.NewFee(b.gasWanted, b.gasFee),
+		Memo: FormatMemo(b.memo, b.metadata),
+	}
+	tx.Signatures = make([]std.Signature, len(tx.GetSigners()))
+	return tx
+}
+
+func (b *TxBuilder) isSigner(addr crypto.Address) bool {
+	for _, signer := range b.unsignedTx().GetSigners() {
+		if signer == addr {
+			return true
+		}
+	}
+	return false
+}
+
+//----------------------------------------
+// Memo metadata
+
+// FormatMemo returns the memo of text and metadata: text, followed if any
+// metadata by a line of its JSON object, with sorted keys.
+func FormatMemo(text string, metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return text
+	}
+	bz, err := json.Marshal(metadata) // sorts keys.
+	if err != nil {
+		panic(err)
+	}
+	if text == "" {
+		return string(bz)
+	}
+	return text + "\n" + string(bz)
+}
+
+// ParseMemo returns the text and metadata of a memo formatted by
+// FormatMemo.  Other memos are text only, so that they are formatted back
+// to the same memo, whose sign bytes are unchanged.
+func ParseMemo(memo string) (text string, metadata map[string]string) {
+	metadata = make(map[string]string)
+	last := memo
+	if i := strings.LastIndexByte(memo, '\n'); i >= 0 {
+		text, last = memo[:i], memo[i+1:]
+	}
+	if !strings.HasPrefix(last, "{") ||
+		json.Unmarshal([]byte(last), &metadata) != nil ||
+		FormatMemo(text, metadata) != memo {
+		return memo, make(map[string]string)
+	}
+	return text, metadata
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/keys"
+	testutils2 "github.com/gnolang/gno/pkgs/sdk/testutils"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
+)
+
+func Test_TxBuilder(t *testing.T) {
+	kb := keys.NewInMemory()
+	info1, err := kb.CreateAccount("key1", `lounge napkin all odor tilt dove win inject sleep jazz uncover traffic hint require cargo arm rocket round scan bread report squirrel step lake`, "", "pass1", 0, 0)
+	require.NoError(t, err)
+	info2, err := kb.CreateAccount("key2", `lecture salt about avocado smooth height escape general arch head barrel clutch dismiss supply doctor project cat truck fruit abuse gorilla symbol portion glare`, "", "pass2", 0, 0)
+	require.NoError(t, err)
+	addr1, addr2 := info1.GetAddress(), info2.GetAddress()
+
+	b := NewTxBuilder("dev")
+	b.SetMsgs(testutils2.NewTestMsg(addr1), testutils2.NewTestMsg(addr2, addr1))
+	b.SetGasWanted(100000)
+	b.SetGasFee(std.NewCoin("ugnot", 1))
+	b.SetMemo("hello")
+	b.SetMetadata("app", "bot")
+	assert.Equal(t, []crypto.Address{addr1, addr2}, b.Signers())
+
+	// signer infos are required.
+	_, err = b.SignBytes(addr1)
+	assert.Error(t, err)
+	b.SetSignerInfo(SignerInfo{Address: addr1, AccountNumber: 1, Sequence: 2})
+	b.SetSignerInfo(SignerInfo{Address: addr2, AccountNumber: 3, Sequence: 4})
+	signbz, err := b.SignBytes(addr1)
+	require.NoError(t, err)
+	tx := b.Tx()
+	assert.Equal(t, tx.GetSignBytes("dev", 1, 2), signbz)
+	assert.Equal(t, "hello\n{\"app\":\"bot\"}", tx.Memo)
+
+	// all signatures are required.
+	require.NoError(t, b.Sign(kb, "key1", "pass1"))
+	_, err = b.Build()
+	assert.Error(t, err)
+	assert.Error(t, b.Sign(kb, "key2", "wrong"))
+	require.NoError(t, b.Sign(kb, "key2", "pass2"))
+	tx, err = b.Build()
+	require.NoError(t, err)
+	assert.True(t, info1.GetPubKey().VerifyBytes(tx.GetSignBytes("dev", 1, 2), tx.Signatures[0].Signature))
+	assert.True(t, info2.GetPubKey().VerifyBytes(tx.GetSignBytes("dev", 3, 4), tx.Signatures[1].Signature))
+	txbz, err := b.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, amino.MustMarshal(tx), txbz)
+
+	// a wrong signature is rejected.
+	assert.Error(t, b.AddSignature(info1.GetPubKey(), tx.Signatures[1].Signature))
+
+	// the signatures are kept from a tx, and cleared by changes.
+	b = NewTxBuilderFromTx("dev", tx)
+	tx2, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, tx, tx2)
+	b.SetMemo("changed")
+	_, err = b.Build()
+	assert.Error(t, err)
+
+	// msgs are appended, with the signers of the new ones.
+	b = NewTxBuilderFromTx("dev", tx)
+	b.AddMsgs(testutils2.NewTestMsg(addr2))
+	assert.Len(t, b.Tx().Msgs, 3)
+	assert.Len(t, tx.Msgs, 2)
+	assert.Equal(t, []crypto.Address{addr1, addr2}, b.Signers())
+	_, err = b.Build()
+	assert.Error(t, err)
+}
+
+func Test_ParseMemo(t *testing.T) {
+	cases := []struct {
+		memo     string
+		text     string
+		metadata map[string]string
+	}{
+		{"", "", map[string]string{}},
+		{"hello", "hello", map[string]string{}},
+		{`{"a":"1"}`, "", map[string]string{"a": "1"}},
+		{"hi\nthere\n{\"a\":\"1\",\"b\":\"2\"}", "hi\nthere", map[string]string{"a": "1", "b": "2"}},
+		// not formatted by FormatMemo, so text only.
+		{`{"a": "1"}`, `{"a": "1"}`, map[string]string{}},
+		{"hi\n{}", "hi\n{}", map[string]string{}},
+		{"hi\n{\"a\":1}", "hi\n{\"a\":1}", map[string]string{}},
+	}
+	for _, c := range cases {
+		text, metadata := ParseMemo(c.memo)
+		assert.Equal(t, c.text, text, c.memo)
+		assert.Equal(t, c.metadata, metadata, c.memo)
+		assert.Equal(t, c.memo, FormatMemo(text, metadata), c.memo)
+	}
+}
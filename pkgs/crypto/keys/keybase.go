@@ -109,6 +109,9 @@ func (kb dbKeybase) CreateLedger(name string, algo SigningAlgo, hrp string, acco
 	if algo != Secp256k1 {
 		return nil, ErrUnsupportedSigningAlgo
 	}
+	if err := crypto.ValidateBech32Prefix(hrp); err != nil {
+		return nil, err
+	}
 
 	coinType := crypto.CoinType
 	hdPath := hd.NewFundraiserParams(account, coinType, index)
@@ -115,7 +115,7 @@ func LedgerShowAddress(path hd.BIP44Params, expectedPubKey crypto.PubKey) error
 		return fmt.Errorf("the key's pubkey does not match with the one retrieved from Ledger. Check that the HD path and device are the correct ones")
 	}
 
-	pubKey2, _, err := getPubKeyAddrSafe(device, path, crypto.Bech32AddrPrefix)
+	pubKey2, _, err := getPubKeyAddrSafe(device, path, crypto.Bech32Prefix())
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,14 @@
+package webauthn
+
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/pkgs/crypto/webauthn",
+	"tm",
+	amino.GetCallersDirname(),
+).WithDependencies().WithTypes(
+	PubKeyWebAuthn{}, "PubKeyWebAuthn",
+	Signature{}, "WebAuthnSignature",
+))
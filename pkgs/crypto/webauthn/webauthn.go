@@ -0,0 +1,166 @@
+// Package webauthn implements the public keys of WebAuthn credentials, e.g.
+// passkeys, which sign with secp256r1 (ES256) in an authenticator, so that
+// gno.land accounts can be managed natively by browsers.
+//
+// An authenticator does not sign a message, but an assertion of a
+// challenge: the tx is signed by an assertion whose challenge is that of
+// its sign bytes (see Challenge), and the signature of the tx is the amino
+// encoding of the Signature of the assertion (see Signature.Bytes).
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/tmhash"
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+//-------------------------------------
+
+var _ crypto.PubKey = PubKeyWebAuthn{}
+
+// PubKeyWebAuthnSize is the size of a compressed secp256r1 point.
+const PubKeyWebAuthnSize = 33
+
+// PubKeyWebAuthn implements crypto.PubKey for the WebAuthn assertions of a
+// credential.  It is the compressed form of its secp256r1 public key.
+type PubKeyWebAuthn [PubKeyWebAuthnSize]byte
+
+// NewPubKeyFromSPKI returns the pubkey of a credential from the DER of its
+// SubjectPublicKeyInfo, as returned by getPublicKey() of the response of
+// navigator.credentials.create() in browsers.
+func NewPubKeyFromSPKI(der []byte) (PubKeyWebAuthn, error) {
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return PubKeyWebAuthn{}, errors.Wrap(err, "parsing public key")
+	}
+	ecpub, ok := pub.(*ecdsa.PublicKey)
+	if !ok || ecpub.Curve != elliptic.P256() {
+		return PubKeyWebAuthn{}, errors.New("expected secp256r1 public key, got %T", pub)
+	}
+	var pubKey PubKeyWebAuthn
+	copy(pubKey[:], elliptic.MarshalCompressed(ecpub.Curve, ecpub.X, ecpub.Y))
+	return pubKey, nil
+}
+
+// Address is the SHA256-20 of the raw pubkey bytes.
+func (pubKey PubKeyWebAuthn) Address() crypto.Address {
+	return crypto.AddressFromBytes(tmhash.SumTruncated(pubKey[:]))
+}
+
+// Bytes marshals the PubKey using amino encoding.
+func (pubKey PubKeyWebAuthn) Bytes() []byte {
+	return amino.MustMarshalAny(pubKey)
+}
+
+// VerifyBytes verifies that sig is the amino encoding of the Signature of
+// an assertion of the challenge of msg.
+func (pubKey PubKeyWebAuthn) VerifyBytes(msg []byte, sig []byte) bool {
+	var wsig Signature
+	if err := amino.Unmarshal(sig, &wsig); err != nil {
+		return false
+	}
+	return pubKey.Verify(msg, wsig) == nil
+}
+
+// Verify returns an error unless wsig is a valid assertion of the challenge
+// of msg.
+func (pubKey PubKeyWebAuthn) Verify(msg []byte, wsig Signature) error {
+	x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pubKey[:])
+	if x == nil {
+		return errors.New("invalid pubkey")
+	}
+	// flags are after the rpIdHash.
+	if len(wsig.AuthenticatorData) < authDataMinSize {
+		return errors.New("authenticator data too short")
+	}
+	if wsig.AuthenticatorData[authDataFlagsIndex]&flagUserPresent == 0 {
+		return errors.New("user not present")
+	}
+	var clientData clientData
+	if err := json.Unmarshal(wsig.ClientDataJSON, &clientData); err != nil {
+		return errors.Wrap(err, "decoding client data")
+	}
+	if clientData.Type != clientDataTypeGet {
+		return errors.New("expected client data type %q, got %q", clientDataTypeGet, clientData.Type)
+	}
+	if clientData.Challenge != ChallengeString(msg) {
+		return errors.New("challenge mismatch")
+	}
+	pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	if !ecdsa.VerifyASN1(pub, wsig.signedHash(), wsig.Signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func (pubKey PubKeyWebAuthn) String() string {
+	return crypto.PubKeyToBech32(pubKey)
+}
+
+func (pubKey PubKeyWebAuthn) Equals(other crypto.PubKey) bool {
+	if otherWa, ok := other.(PubKeyWebAuthn); ok {
+		return bytes.Equal(pubKey[:], otherWa[:])
+	}
+	return false
+}
+
+//-------------------------------------
+
+const (
+	authDataMinSize    = 37 // rpIdHash, flags and signCount.
+	authDataFlagsIndex = 32
+	flagUserPresent    = 0x01
+	clientDataTypeGet  = "webauthn.get"
+)
+
+// The fields of the client data of an assertion which are verified.  The
+// origin is not, as the credential is scoped to its relying party.
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// Signature is the response of an assertion, by an authenticator, as
+// returned by navigator.credentials.get() in browsers.
+type Signature struct {
+	AuthenticatorData []byte `json:"authenticator_data" yaml:"authenticator_data"`
+	ClientDataJSON    []byte `json:"client_data_json" yaml:"client_data_json"`
+	Signature         []byte `json:"signature" yaml:"signature"` // ASN.1 DER
+}
+
+// Bytes returns the amino encoding of wsig, to be the signature of a tx.
+func (wsig Signature) Bytes() []byte {
+	return amino.MustMarshal(wsig)
+}
+
+// Returns the hash signed by the authenticator: that of its data followed
+// by the hash of the client data.
+func (wsig Signature) signedHash() []byte {
+	clientDataHash := sha256.Sum256(wsig.ClientDataJSON)
+	h := sha256.New()
+	h.Write(wsig.AuthenticatorData)
+	h.Write(clientDataHash[:])
+	return h.Sum(nil)
+}
+
+// Challenge returns the challenge of an assertion signing msg, e.g. the sign
+// bytes of a tx: its SHA256, to be passed to navigator.credentials.get().
+func Challenge(msg []byte) []byte {
+	hash := sha256.Sum256(msg)
+	return hash[:]
+}
+
+// ChallengeString returns the challenge of msg as encoded in the client data
+// of an assertion, in unpadded base64url.
+func ChallengeString(msg []byte) string {
+	return base64.RawURLEncoding.EncodeToString(Challenge(msg))
+}
@@ -0,0 +1,99 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+)
+
+// Returns a credential of an authenticator, and its pubkey.
+func newTestCredential(t *testing.T) (*ecdsa.PrivateKey, PubKeyWebAuthn) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pubKey, err := NewPubKeyFromSPKI(der)
+	require.NoError(t, err)
+	return priv, pubKey
+}
+
+// Returns the assertion of challenge by priv, as an authenticator would.
+func newTestAssertion(t *testing.T, priv *ecdsa.PrivateKey, typ, challenge string, flags byte) Signature {
+	t.Helper()
+
+	rpIDHash := sha256.Sum256([]byte("gno.land"))
+	authData := append(rpIDHash[:], flags, 0, 0, 0, 1)
+	clientDataJSON := []byte(fmt.Sprintf(`{"type":%q,"challenge":%q,"origin":"https://gno.land","crossOrigin":false}`, typ, challenge))
+	wsig := Signature{
+		AuthenticatorData: authData,
+		ClientDataJSON:    clientDataJSON,
+	}
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, wsig.signedHash())
+	require.NoError(t, err)
+	wsig.Signature = sig
+	return wsig
+}
+
+func TestVerifyBytes(t *testing.T) {
+	priv, pubKey := newTestCredential(t)
+	msg := []byte("sign bytes")
+
+	wsig := newTestAssertion(t, priv, "webauthn.get", ChallengeString(msg), flagUserPresent)
+	assert.NoError(t, pubKey.Verify(msg, wsig))
+	assert.True(t, pubKey.VerifyBytes(msg, wsig.Bytes()))
+	assert.False(t, pubKey.VerifyBytes([]byte("other bytes"), wsig.Bytes()))
+	assert.False(t, pubKey.VerifyBytes(msg, wsig.Signature))
+
+	// another credential.
+	_, pubKey2 := newTestCredential(t)
+	assert.Error(t, pubKey2.Verify(msg, wsig))
+
+	// tampered data.
+	tampered := wsig
+	tampered.AuthenticatorData = append([]byte(nil), wsig.AuthenticatorData...)
+	tampered.AuthenticatorData[36] = 2 // signCount
+	assert.Error(t, pubKey.Verify(msg, tampered))
+
+	// invalid assertions.
+	for _, wsig := range []Signature{
+		newTestAssertion(t, priv, "webauthn.create", ChallengeString(msg), flagUserPresent),
+		newTestAssertion(t, priv, "webauthn.get", ChallengeString([]byte("other bytes")), flagUserPresent),
+		newTestAssertion(t, priv, "webauthn.get", ChallengeString(msg), 0),
+		{AuthenticatorData: wsig.AuthenticatorData[:10], ClientDataJSON: wsig.ClientDataJSON, Signature: wsig.Signature},
+	} {
+		assert.Error(t, pubKey.Verify(msg, wsig))
+	}
+}
+
+func TestPubKey(t *testing.T) {
+	_, pubKey := newTestCredential(t)
+
+	// amino
+	var pubKey2 crypto.PubKey
+	require.NoError(t, amino.Unmarshal(pubKey.Bytes(), &pubKey2))
+	assert.True(t, pubKey.Equals(pubKey2))
+	pubKey3, err := crypto.PubKeyFromBech32(pubKey.String())
+	require.NoError(t, err)
+	assert.Equal(t, pubKey, pubKey3)
+	assert.Len(t, pubKey.Address().Bytes(), crypto.AddressSize)
+
+	// not secp256r1
+	priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	_, err = NewPubKeyFromSPKI(der)
+	assert.Error(t, err)
+}
@@ -0,0 +1,288 @@
+package p2p
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/errors"
+	osm "github.com/gnolang/gno/pkgs/os"
+)
+
+// KnownAddress is the address of a peer in the address book, with the
+// results of the dials of the node, from which its score is computed.
+type KnownAddress struct {
+	Addr        *NetAddress `json:"addr"`
+	Score       int         `json:"score"`    // successful dials, minus failed ones
+	Attempts    int         `json:"attempts"` // failed dials since the last success
+	LastAttempt time.Time   `json:"last_attempt"`
+	LastSuccess time.Time   `json:"last_success"`
+}
+
+// AddrBookJSON is the content of the file of the address book, also exported
+// and imported by the admin.  BannedIPs are IP ranges in the CIDR notation.
+type AddrBookJSON struct {
+	Addrs     []*KnownAddress `json:"addrs"`
+	BannedIDs []ID            `json:"banned_ids"`
+	BannedIPs []string        `json:"banned_ips"`
+}
+
+// AddrBook is the book of the known addresses of the peers, and of the IDs
+// and IP ranges banned by the admin, persisted to its file on each change.
+// It is safe for concurrent use.
+type AddrBook struct {
+	mtx       sync.Mutex
+	filePath  string
+	addrs     map[ID]*KnownAddress
+	bannedIDs map[ID]struct{}
+	bannedIPs map[string]*net.IPNet
+}
+
+// NewAddrBook returns the address book of filePath, loaded from it if it
+// exists.
+func NewAddrBook(filePath string) (*AddrBook, error) {
+	book := &AddrBook{
+		filePath:  filePath,
+		addrs:     make(map[ID]*KnownAddress),
+		bannedIDs: make(map[ID]struct{}),
+		bannedIPs: make(map[string]*net.IPNet),
+	}
+	if filePath == "" || !osm.FileExists(filePath) {
+		return book, nil
+	}
+	bz, err := osm.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var content AddrBookJSON
+	if err := amino.UnmarshalJSON(bz, &content); err != nil {
+		return nil, errors.Wrap(err, "error reading address book %s", filePath)
+	}
+	if err := book.merge(content); err != nil {
+		return nil, errors.Wrap(err, "error reading address book %s", filePath)
+	}
+	return book, nil
+}
+
+// AddAddress adds addr to the book, replacing the address of the same ID,
+// if any, but keeping its score.
+func (book *AddrBook) AddAddress(addr *NetAddress) error {
+	if err := addr.Validate(); err != nil {
+		return err
+	}
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	book.addAddress(addr)
+	return book.save()
+}
+
+// RemoveAddress removes the address of id from the book.
+func (book *AddrBook) RemoveAddress(id ID) error {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	if _, ok := book.addrs[id]; !ok {
+		return errors.New("no address of %s in the address book", id)
+	}
+	delete(book.addrs, id)
+	return book.save()
+}
+
+// MarkGood records a successful dial of addr, adding it to the book.
+func (book *AddrBook) MarkGood(addr *NetAddress) error {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	ka := book.addAddress(addr)
+	ka.Score++
+	ka.Attempts = 0
+	ka.LastAttempt = time.Now().UTC()
+	ka.LastSuccess = ka.LastAttempt
+	return book.save()
+}
+
+// MarkAttempt records a failed dial of addr, adding it to the book.
+func (book *AddrBook) MarkAttempt(addr *NetAddress) error {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	ka := book.addAddress(addr)
+	ka.Score--
+	ka.Attempts++
+	ka.LastAttempt = time.Now().UTC()
+	return book.save()
+}
+
+// Ban bans target, a peer ID, an IP, or an IP range in the CIDR notation.
+func (book *AddrBook) Ban(target string) error {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	if err := book.ban(target); err != nil {
+		return err
+	}
+	return book.save()
+}
+
+// Unban lifts the ban of target, as given to Ban.
+func (book *AddrBook) Unban(target string) error {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	if ipNet, err := parseIPRange(target); err == nil {
+		if _, ok := book.bannedIPs[ipNet.String()]; !ok {
+			return errors.New("%s is not banned", target)
+		}
+		delete(book.bannedIPs, ipNet.String())
+	} else {
+		if _, ok := book.bannedIDs[ID(target)]; !ok {
+			return errors.New("%s is not banned", target)
+		}
+		delete(book.bannedIDs, ID(target))
+	}
+	return book.save()
+}
+
+// IsBanned returns true if the ID of the peer or its IP is banned.
+func (book *AddrBook) IsBanned(id ID, ip net.IP) bool {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	if _, ok := book.bannedIDs[id]; ok {
+		return true
+	}
+	for _, ipNet := range book.bannedIPs {
+		if ip != nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Export returns the content of the book, with the addresses by decreasing
+// score.
+func (book *AddrBook) Export() AddrBookJSON {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	return book.export()
+}
+
+// Import adds the addresses and bans of content to the book, replacing the
+// addresses of the same IDs.
+func (book *AddrBook) Import(content AddrBookJSON) error {
+	book.mtx.Lock()
+	defer book.mtx.Unlock()
+
+	if err := book.merge(content); err != nil {
+		return err
+	}
+	return book.save()
+}
+
+func (book *AddrBook) addAddress(addr *NetAddress) *KnownAddress {
+	ka, ok := book.addrs[addr.ID]
+	if !ok {
+		ka = &KnownAddress{}
+		book.addrs[addr.ID] = ka
+	}
+	ka.Addr = addr
+	return ka
+}
+
+func (book *AddrBook) ban(target string) error {
+	if ipNet, err := parseIPRange(target); err == nil {
+		book.bannedIPs[ipNet.String()] = ipNet
+		return nil
+	}
+	if err := ID(target).Validate(); err != nil {
+		return errors.New("%q is neither an ID, an IP nor an IP range", target)
+	}
+	book.bannedIDs[ID(target)] = struct{}{}
+	return nil
+}
+
+func (book *AddrBook) merge(content AddrBookJSON) error {
+	for _, ka := range content.Addrs {
+		if ka == nil || ka.Addr == nil {
+			return errors.New("address missing in the address book")
+		}
+		if err := ka.Addr.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, id := range content.BannedIDs {
+		if err := book.ban(string(id)); err != nil {
+			return err
+		}
+	}
+	for _, ipRange := range content.BannedIPs {
+		if err := book.ban(ipRange); err != nil {
+			return err
+		}
+	}
+	for _, ka := range content.Addrs {
+		ka := *ka
+		book.addrs[ka.Addr.ID] = &ka
+	}
+	return nil
+}
+
+func (book *AddrBook) export() AddrBookJSON {
+	content := AddrBookJSON{
+		Addrs:     make([]*KnownAddress, 0, len(book.addrs)),
+		BannedIDs: make([]ID, 0, len(book.bannedIDs)),
+		BannedIPs: make([]string, 0, len(book.bannedIPs)),
+	}
+	for _, ka := range book.addrs {
+		ka := *ka
+		content.Addrs = append(content.Addrs, &ka)
+	}
+	sort.Slice(content.Addrs, func(i, j int) bool {
+		a, b := content.Addrs[i], content.Addrs[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Addr.ID < b.Addr.ID
+	})
+	for id := range book.bannedIDs {
+		content.BannedIDs = append(content.BannedIDs, id)
+	}
+	sort.Slice(content.BannedIDs, func(i, j int) bool { return content.BannedIDs[i] < content.BannedIDs[j] })
+	for ipRange := range book.bannedIPs {
+		content.BannedIPs = append(content.BannedIPs, ipRange)
+	}
+	sort.Strings(content.BannedIPs)
+	return content
+}
+
+func (book *AddrBook) save() error {
+	if book.filePath == "" {
+		return nil
+	}
+	bz, err := amino.MarshalJSONIndent(book.export(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return osm.WriteFileAtomic(book.filePath, bz, 0o600)
+}
+
+// Returns the IP range of an IP, or of an IP range in the CIDR notation.
+func parseIPRange(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, ipNet, err := net.ParseCIDR(s)
+		return ipNet, err
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.New("invalid IP %q", s)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
@@ -0,0 +1,74 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+)
+
+func testAddr(t *testing.T, ip string) *NetAddress {
+	t.Helper()
+
+	id := ed25519.GenPrivKey().PubKey().Address().ID()
+	addr, err := NewNetAddressFromString(fmt.Sprintf("%s@%s:26656", id, ip))
+	require.NoError(t, err)
+	return addr
+}
+
+func TestAddrBook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addrbook.json")
+	book, err := NewAddrBook(path)
+	require.NoError(t, err)
+	assert.Empty(t, book.Export().Addrs)
+
+	a, b, c := testAddr(t, "1.2.3.4"), testAddr(t, "10.0.0.1"), testAddr(t, "5.6.7.8")
+	require.NoError(t, book.AddAddress(a))
+	require.NoError(t, book.MarkGood(b))
+	require.NoError(t, book.MarkGood(b))
+	require.NoError(t, book.MarkAttempt(c))
+	exported := book.Export()
+	require.Len(t, exported.Addrs, 3)
+	assert.Equal(t, b.ID, exported.Addrs[0].Addr.ID)
+	assert.Equal(t, 2, exported.Addrs[0].Score)
+	assert.Equal(t, a.ID, exported.Addrs[1].Addr.ID)
+	assert.Equal(t, c.ID, exported.Addrs[2].Addr.ID)
+	assert.Equal(t, -1, exported.Addrs[2].Score)
+	assert.Equal(t, 1, exported.Addrs[2].Attempts)
+
+	require.NoError(t, book.RemoveAddress(c.ID))
+	assert.Error(t, book.RemoveAddress(c.ID))
+
+	// bans.
+	require.NoError(t, book.Ban("10.0.0.0/8"))
+	require.NoError(t, book.Ban(string(a.ID)))
+	assert.Error(t, book.Ban("nonsense"))
+	assert.True(t, book.IsBanned(a.ID, nil))
+	assert.True(t, book.IsBanned(c.ID, net.ParseIP("10.1.2.3")))
+	assert.False(t, book.IsBanned(c.ID, net.ParseIP("11.1.2.3")))
+	require.NoError(t, book.Unban("10.0.0.0/8"))
+	assert.False(t, book.IsBanned(c.ID, net.ParseIP("10.1.2.3")))
+	assert.Error(t, book.Unban("10.0.0.0/8"))
+	require.NoError(t, book.Ban("5.6.7.8"))
+	assert.True(t, book.IsBanned(c.ID, net.ParseIP("5.6.7.8")))
+
+	// reloaded from its file.
+	reloaded, err := NewAddrBook(path)
+	require.NoError(t, err)
+	assert.Equal(t, book.Export(), reloaded.Export())
+
+	// imported into another.
+	other, err := NewAddrBook("")
+	require.NoError(t, err)
+	require.NoError(t, other.AddAddress(c))
+	require.NoError(t, other.Import(book.Export()))
+	exported = other.Export()
+	assert.Len(t, exported.Addrs, 3)
+	assert.Equal(t, []ID{a.ID}, exported.BannedIDs)
+	assert.Equal(t, []string{"5.6.7.8/32"}, exported.BannedIPs)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/gnolang/gno/pkgs/errors"
@@ -34,6 +35,9 @@ type P2PConfig struct {
 	// Comma separated list of nodes to keep persistent connections to
 	PersistentPeers string `toml:"persistent_peers"`
 
+	// Path to the address book, with the known peers and the banned IDs and IPs
+	AddrBook string `toml:"addr_book_file"`
+
 	// UPNP port forwarding
 	UPNP bool `toml:"upnp"`
 
@@ -64,6 +68,14 @@ type P2PConfig struct {
 	// Does not work if the peer-exchange reactor is disabled.
 	SeedMode bool `toml:"seed_mode"`
 
+	// Interval between the crawls of the known peers, reporting their
+	// reachability, versions and latencies on /net_topology, e.g. in seed
+	// mode.  0 disables the crawler.
+	CrawlInterval time.Duration `toml:"crawl_interval"`
+
+	// Path to the JSON export of the last crawl report, if any
+	CrawlReport string `toml:"crawl_report_file"`
+
 	// Comma separated list of peer IDs to keep private (will not be gossiped to
 	// other peers)
 	PrivatePeerIDs string `toml:"private_peer_ids"`
@@ -88,6 +100,7 @@ func DefaultP2PConfig() *P2PConfig {
 	return &P2PConfig{
 		ListenAddress:           "tcp://0.0.0.0:26656",
 		ExternalAddress:         "",
+		AddrBook:                filepath.Join(defaultConfigDir, "addrbook.json"),
 		UPNP:                    false,
 		MaxNumInboundPeers:      40,
 		MaxNumOutboundPeers:     10,
@@ -97,6 +110,8 @@ func DefaultP2PConfig() *P2PConfig {
 		RecvRate:                5120000, // 5 mB/s
 		PexReactor:              true,
 		SeedMode:                false,
+		CrawlInterval:           0,
+		CrawlReport:             "",
 		AllowDuplicateIP:        false,
 		HandshakeTimeout:        20 * time.Second,
 		DialTimeout:             3 * time.Second,
@@ -136,9 +151,28 @@ func (cfg *P2PConfig) ValidateBasic() error {
 	if cfg.RecvRate < 0 {
 		return errors.New("recv_rate can't be negative")
 	}
+	if cfg.CrawlInterval < 0 {
+		return errors.New("crawl_interval can't be negative")
+	}
 	return nil
 }
 
+// AddrBookFile returns the full path to the address book, or "" if none.
+func (cfg *P2PConfig) AddrBookFile() string {
+	if cfg.AddrBook == "" || filepath.IsAbs(cfg.AddrBook) {
+		return cfg.AddrBook
+	}
+	return filepath.Join(cfg.RootDir, cfg.AddrBook)
+}
+
+// CrawlReportFile returns the full path to the crawl report, or "" if none.
+func (cfg *P2PConfig) CrawlReportFile() string {
+	if cfg.CrawlReport == "" || filepath.IsAbs(cfg.CrawlReport) {
+		return cfg.CrawlReport
+	}
+	return filepath.Join(cfg.RootDir, cfg.CrawlReport)
+}
+
 // FuzzConnConfig is a FuzzedConnection configuration.
 type FuzzConnConfig struct {
 	Mode         int
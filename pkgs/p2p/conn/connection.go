@@ -108,6 +108,8 @@ type MConnection struct {
 	// close conn if pong is not received in pongTimeout
 	pongTimer     *time.Timer
 	pongTimeoutCh chan bool // true - timeout, false - peer sent pong
+	pingSent      time.Time // of the last ping
+	rtt           int64     // atomic; round trip time of the last ping
 
 	chStatsTimer *time.Ticker // update channel stats periodically
 
@@ -427,6 +429,7 @@ FOR_LOOP:
 				break SELECTION
 			}
 			c.sendMonitor.Update(int(_n))
+			c.pingSent = time.Now()
 			c.Logger.Debug("Starting pong timer", "dur", c.config.PongTimeout)
 			c.pongTimer = time.AfterFunc(c.config.PongTimeout, func() {
 				select {
@@ -441,6 +444,7 @@ FOR_LOOP:
 				err = errors.New("pong timeout")
 			} else {
 				c.stopPongTimer()
+				atomic.StoreInt64(&c.rtt, int64(time.Since(c.pingSent)))
 			}
 		case <-c.pong:
 			c.Logger.Debug("Send Pong")
@@ -661,6 +665,7 @@ func (c *MConnection) maxPacketMsgSize() int {
 
 type ConnectionStatus struct {
 	Duration    time.Duration
+	RTT         time.Duration // of the last ping, 0 before the first pong
 	SendMonitor flow.Status
 	RecvMonitor flow.Status
 	Channels    []ChannelStatus
@@ -677,6 +682,7 @@ type ChannelStatus struct {
 func (c *MConnection) Status() ConnectionStatus {
 	var status ConnectionStatus
 	status.Duration = time.Since(c.created)
+	status.RTT = time.Duration(atomic.LoadInt64(&c.rtt))
 	status.SendMonitor = c.sendMonitor.Status()
 	status.RecvMonitor = c.recvMonitor.Status()
 	status.Channels = make([]ChannelStatus, len(c.channels))
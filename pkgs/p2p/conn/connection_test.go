@@ -345,6 +345,7 @@ func TestMConnectionPingPongs(t *testing.T) {
 	case <-time.After(2 * pongTimerExpired):
 		assert.True(t, mconn.IsRunning())
 	}
+	assert.NotZero(t, mconn.Status().RTT)
 }
 
 func TestMConnectionStopsAndReturnsError(t *testing.T) {
@@ -0,0 +1,174 @@
+package p2p
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	osm "github.com/gnolang/gno/pkgs/os"
+	"github.com/gnolang/gno/pkgs/service"
+)
+
+// Maximum number of addresses crawled concurrently.
+const maxConcurrentCrawls = 16
+
+// CrawlReport is the map of the network from the last crawl of the peers
+// known to the node.
+type CrawlReport struct {
+	Time  time.Time   `json:"time"`  // of the end of the crawl
+	Peers []CrawlPeer `json:"peers"` // by ID
+}
+
+// CrawlPeer is the result of the crawl of a peer.
+type CrawlPeer struct {
+	Addr      *NetAddress   `json:"addr"`
+	Connected bool          `json:"connected"` // a peer of the node
+	Reachable bool          `json:"reachable"`
+	Latency   time.Duration `json:"latency"`             // of the handshake, or of the pings if connected
+	NodeInfo  *NodeInfo     `json:"node_info,omitempty"` // with its version
+	Error     string        `json:"error,omitempty"`     // of the dial, if not reachable
+}
+
+// Crawler is the service crawling periodically the peers of the switch and
+// those of its address book, e.g. in seed mode, to map the reachable peers,
+// their versions and latencies.  The dialed peers are disconnected after
+// the handshake.
+type Crawler struct {
+	service.BaseService
+
+	sw         *Switch
+	interval   time.Duration
+	reportFile string // "" if not exported
+
+	mtx    sync.Mutex
+	report CrawlReport
+}
+
+// NewCrawler returns the crawler of the peers of sw, every interval,
+// exporting its reports as JSON to reportFile if not "".
+func NewCrawler(sw *Switch, interval time.Duration, reportFile string) *Crawler {
+	c := &Crawler{
+		sw:         sw,
+		interval:   interval,
+		reportFile: reportFile,
+	}
+	c.BaseService = *service.NewBaseService(nil, "Crawler", c)
+	return c
+}
+
+// OnStart implements service.Service.
+func (c *Crawler) OnStart() error {
+	go c.crawlRoutine()
+	return nil
+}
+
+// Report returns the report of the last crawl, empty before the end of the
+// first.
+func (c *Crawler) Report() CrawlReport {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.report
+}
+
+func (c *Crawler) crawlRoutine() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.Crawl()
+		select {
+		case <-ticker.C:
+		case <-c.Quit():
+			return
+		}
+	}
+}
+
+// Crawl crawls the peers now, and returns the new report.
+func (c *Crawler) Crawl() CrawlReport {
+	// The addresses of the peers, and of the book.
+	addrs := make(map[ID]*NetAddress)
+	connected := make(map[ID]Peer)
+	if c.sw.addrBook != nil {
+		for _, ka := range c.sw.addrBook.Export().Addrs {
+			if !c.sw.addrBook.IsBanned(ka.Addr.ID, ka.Addr.IP) {
+				addrs[ka.Addr.ID] = ka.Addr
+			}
+		}
+	}
+	for _, p := range c.sw.Peers().List() {
+		connected[p.ID()] = p
+		if p.IsOutbound() {
+			addrs[p.ID()] = p.SocketAddr()
+		} else if _, ok := addrs[p.ID()]; !ok {
+			addrs[p.ID()] = p.NodeInfo().NetAddress // self-reported
+		}
+	}
+
+	peers := make([]CrawlPeer, 0, len(addrs))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentCrawls)
+	for id, addr := range addrs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(addr *NetAddress, p Peer) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			cp := c.crawlPeer(addr, p)
+			mtx.Lock()
+			peers = append(peers, cp)
+			mtx.Unlock()
+		}(addr, connected[id])
+	}
+	wg.Wait()
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Addr.ID < peers[j].Addr.ID })
+
+	report := CrawlReport{Time: time.Now(), Peers: peers}
+	c.mtx.Lock()
+	c.report = report
+	c.mtx.Unlock()
+	if err := c.saveReport(report); err != nil {
+		c.Logger.Error("Error saving crawl report", "err", err)
+	}
+	c.Logger.Debug("Crawled peers", "peers", len(peers))
+	return report
+}
+
+// Crawls addr, the address of the connected peer p if not nil.  The
+// connected peers are not dialed again: their latency is the round trip
+// time of the pings of the connection.
+func (c *Crawler) crawlPeer(addr *NetAddress, p Peer) CrawlPeer {
+	cp := CrawlPeer{Addr: addr, Connected: p != nil, Reachable: true}
+	if p != nil {
+		ni := p.NodeInfo()
+		cp.NodeInfo = &ni
+		cp.Latency = p.Status().RTT
+		return cp
+	}
+	start := time.Now()
+	ni, err := c.sw.handshakePeer(addr)
+	if err != nil {
+		cp.Reachable = false
+		cp.Error = err.Error()
+		return cp
+	}
+	cp.NodeInfo = &ni
+	cp.Latency = time.Since(start)
+	return cp
+}
+
+func (c *Crawler) saveReport(report CrawlReport) error {
+	if c.reportFile == "" {
+		return nil
+	}
+	bz, err := amino.MarshalJSONIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return osm.WriteFileAtomic(c.reportFile, bz, 0o644)
+}
@@ -0,0 +1,72 @@
+package p2p
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+	osm "github.com/gnolang/gno/pkgs/os"
+)
+
+func TestCrawler(t *testing.T) {
+	book, err := NewAddrBook("")
+	require.NoError(t, err)
+	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc, SwitchAddrBook(book))
+	require.NoError(t, sw.Start())
+	defer sw.Stop()
+
+	// a connected peer, a known one and an unreachable one.
+	connected := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	connected.Start()
+	defer connected.Stop()
+	require.NoError(t, sw.DialPeerWithAddress(connected.Addr()))
+	known := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	known.Start()
+	defer known.Stop()
+	require.NoError(t, book.AddAddress(known.Addr()))
+	id := ed25519.GenPrivKey().PubKey().Address().ID()
+	unreachable, err := NewNetAddressFromString(NetAddressString(id, "127.0.0.1:1"))
+	require.NoError(t, err)
+	require.NoError(t, book.AddAddress(unreachable))
+
+	reportFile := filepath.Join(t.TempDir(), "crawl.json")
+	c := NewCrawler(sw, cfg.DialTimeout, reportFile)
+	report := c.Crawl()
+	assert.Equal(t, report, c.Report())
+	require.Len(t, report.Peers, 3)
+	byID := make(map[ID]CrawlPeer)
+	for _, cp := range report.Peers {
+		byID[cp.Addr.ID] = cp
+	}
+
+	cp := byID[connected.ID()]
+	assert.True(t, cp.Connected)
+	assert.True(t, cp.Reachable)
+	require.NotNil(t, cp.NodeInfo)
+	assert.Equal(t, "1.2.3-rc0-deadbeef", cp.NodeInfo.Version)
+
+	cp = byID[known.ID()]
+	assert.False(t, cp.Connected)
+	assert.True(t, cp.Reachable)
+	assert.True(t, cp.Latency > 0)
+	require.NotNil(t, cp.NodeInfo)
+	assert.Equal(t, "remote_peer", cp.NodeInfo.Moniker)
+	// the crawled peer isn't added.
+	assert.Nil(t, sw.Peers().Get(known.ID()))
+
+	cp = byID[id]
+	assert.False(t, cp.Reachable)
+	assert.Nil(t, cp.NodeInfo)
+	assert.NotEmpty(t, cp.Error)
+
+	// the report is exported.
+	bz, err := osm.ReadFile(reportFile)
+	require.NoError(t, err)
+	var exported CrawlReport
+	require.NoError(t, amino.UnmarshalJSON(bz, &exported))
+	assert.Len(t, exported.Peers, 3)
+}
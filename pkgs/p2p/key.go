@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/gnolang/gno/pkgs/amino"
 	"github.com/gnolang/gno/pkgs/crypto"
@@ -51,6 +52,30 @@ func LoadNodeKey(filePath string) (*NodeKey, error) {
 	return nodeKey, nil
 }
 
+// RotateNodeKey replaces the NodeKey at filePath with a new one, and backs
+// up the previous one to "<name>.<id>.json", e.g. "node_key.<id>.json".
+// The node uses the new NodeKey, and so ID, once it is restarted.
+func RotateNodeKey(filePath string) (prev, next *NodeKey, err error) {
+	prev, err = LoadNodeKey(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	jsonBytes, err := amino.MarshalJSON(prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	prevPath := strings.TrimSuffix(filePath, ".json") + "." + string(prev.ID()) + ".json"
+	err = osm.WriteFileAtomic(prevPath, jsonBytes, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	next, err = genNodeKey(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prev, next, nil
+}
+
 func genNodeKey(filePath string) (*NodeKey, error) {
 	privKey := ed25519.GenPrivKey()
 	nodeKey := &NodeKey{
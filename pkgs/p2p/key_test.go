@@ -22,6 +22,28 @@ func TestLoadOrGenNodeKey(t *testing.T) {
 	assert.Equal(t, nodeKey, nodeKey2)
 }
 
+func TestRotateNodeKey(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "node_key.json")
+
+	_, _, err := RotateNodeKey(filePath)
+	assert.Error(t, err)
+
+	nodeKey, err := LoadOrGenNodeKey(filePath)
+	assert.Nil(t, err)
+	prev, next, err := RotateNodeKey(filePath)
+	assert.Nil(t, err)
+	assert.Equal(t, nodeKey, prev)
+	assert.NotEqual(t, prev.ID(), next.ID())
+
+	nodeKey2, err := LoadNodeKey(filePath)
+	assert.Nil(t, err)
+	assert.Equal(t, next, nodeKey2)
+	prev2, err := LoadNodeKey(filepath.Join(dir, "node_key."+string(prev.ID())+".json"))
+	assert.Nil(t, err)
+	assert.Equal(t, prev, prev2)
+}
+
 //----------------------------------------------------------
 
 func padBytes(bz []byte, targetBytes int) []byte {
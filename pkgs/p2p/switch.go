@@ -70,6 +70,7 @@ type Switch struct {
 
 	filterTimeout time.Duration
 	peerFilters   []PeerFilterFunc
+	addrBook      *AddrBook // may be nil
 
 	rng *random.Rand // seed for randomizing dial times and orders
 }
@@ -124,6 +125,12 @@ func SwitchPeerFilters(filters ...PeerFilterFunc) SwitchOption {
 	return func(sw *Switch) { sw.peerFilters = filters }
 }
 
+// SwitchAddrBook sets the address book recording the dials of the switch,
+// whose banned IDs and IPs are rejected.
+func SwitchAddrBook(book *AddrBook) SwitchOption {
+	return func(sw *Switch) { sw.addrBook = book }
+}
+
 //---------------------------------------------------------------------
 // Switch setup
 
@@ -463,6 +470,22 @@ func (sw *Switch) DialPeerWithAddress(addr *NetAddress) error {
 	return sw.addOutboundPeerWithConfig(addr, sw.config)
 }
 
+// handshakePeer dials the given peer, without adding it, and returns its
+// node info after the handshake.
+func (sw *Switch) handshakePeer(addr *NetAddress) (NodeInfo, error) {
+	p, err := sw.transport.Dial(*addr, peerConfig{
+		chDescs:      sw.chDescs,
+		onPeerError:  func(Peer, interface{}) {},
+		reactorsByCh: sw.reactorsByCh,
+	})
+	if err != nil {
+		return NodeInfo{}, err
+	}
+	sw.transport.Cleanup(p)
+	p.CloseConn() // nolint: errcheck
+	return p.NodeInfo(), nil
+}
+
 // sleep for interval plus some random amount of ms on [0, dialRandomizerIntervalMilliseconds]
 func (sw *Switch) randomSleep(interval time.Duration) {
 	r := time.Duration(sw.rng.Int63n(dialRandomizerIntervalMilliseconds)) * time.Millisecond
@@ -600,6 +623,10 @@ func (sw *Switch) addOutboundPeerWithConfig(
 ) error {
 	sw.Logger.Info("Dialing peer", "address", addr)
 
+	if sw.addrBook != nil && sw.addrBook.IsBanned(addr.ID, addr.IP) {
+		return ErrRejected{addr: *addr, id: addr.ID, err: errors.New("banned"), isFiltered: true}
+	}
+
 	// XXX(xla): Remove the leakage of test concerns in implementation.
 	if cfg.TestDialFail {
 		go sw.reconnectToPeer(addr)
@@ -619,6 +646,7 @@ func (sw *Switch) addOutboundPeerWithConfig(
 				return err
 			}
 		}
+		sw.markAddr(addr, false)
 
 		// retry persistent peers after
 		// any dial error besides IsSelf()
@@ -636,16 +664,37 @@ func (sw *Switch) addOutboundPeerWithConfig(
 		}
 		return err
 	}
+	sw.markAddr(addr, true)
 
 	return nil
 }
 
+// Records the result of the dial of addr in the address book, if any.
+func (sw *Switch) markAddr(addr *NetAddress, good bool) {
+	if sw.addrBook == nil {
+		return
+	}
+	var err error
+	if good {
+		err = sw.addrBook.MarkGood(addr)
+	} else {
+		err = sw.addrBook.MarkAttempt(addr)
+	}
+	if err != nil {
+		sw.Logger.Error("Error saving address book", "err", err)
+	}
+}
+
 func (sw *Switch) filterPeer(p Peer) error {
 	// Avoid duplicate
 	if sw.peers.Has(p.ID()) {
 		return ErrRejected{id: p.ID(), isDuplicate: true}
 	}
 
+	if sw.addrBook != nil && sw.addrBook.IsBanned(p.ID(), p.RemoteIP()) {
+		return ErrRejected{id: p.ID(), err: errors.New("banned"), isFiltered: true}
+	}
+
 	errc := make(chan error, len(sw.peerFilters))
 
 	for _, f := range sw.peerFilters {
@@ -445,6 +445,33 @@ func TestSwitchDialPeersAsync(t *testing.T) {
 	require.NotNil(t, sw.Peers().Get(rp.ID()))
 }
 
+func TestSwitchAddrBook(t *testing.T) {
+	book, err := NewAddrBook("")
+	require.NoError(t, err)
+	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc, SwitchAddrBook(book))
+	err = sw.Start()
+	require.NoError(t, err)
+	defer sw.Stop()
+
+	rp := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp.Start()
+	defer rp.Stop()
+
+	require.NoError(t, book.Ban(string(rp.ID())))
+	err = sw.DialPeerWithAddress(rp.Addr())
+	require.Error(t, err)
+	assert.True(t, err.(ErrRejected).IsFiltered())
+	assert.Empty(t, book.Export().Addrs)
+
+	require.NoError(t, book.Unban(string(rp.ID())))
+	require.NoError(t, sw.DialPeerWithAddress(rp.Addr()))
+	require.NotNil(t, sw.Peers().Get(rp.ID()))
+	addrs := book.Export().Addrs
+	require.Len(t, addrs, 1)
+	assert.Equal(t, rp.ID(), addrs[0].Addr.ID)
+	assert.Equal(t, 1, addrs[0].Score)
+}
+
 func waitUntilSwitchHasAtLeastNPeers(sw *Switch, n int) {
 	for i := 0; i < 20; i++ {
 		time.Sleep(250 * time.Millisecond)
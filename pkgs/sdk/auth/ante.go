@@ -10,6 +10,7 @@ import (
 	"github.com/gnolang/gno/pkgs/crypto/ed25519"
 	"github.com/gnolang/gno/pkgs/crypto/multisig"
 	"github.com/gnolang/gno/pkgs/crypto/secp256k1"
+	"github.com/gnolang/gno/pkgs/crypto/webauthn"
 	"github.com/gnolang/gno/pkgs/sdk"
 	"github.com/gnolang/gno/pkgs/std"
 	"github.com/gnolang/gno/pkgs/store"
@@ -36,6 +37,23 @@ type AnteOptions struct {
 	// This is useful for development, and maybe production chains.
 	// Always check your settings and inspect genesis transactions.
 	VerifyGenesisSignatures bool
+
+	// If AuthorizeSigner is set, the signature of a signer may be by another
+	// key, e.g. a session key granted by the signer, of the sign bytes of
+	// the account of the signer, whose sequence it increments.  It returns
+	// an error unless the key of grantee may sign tx in place of signer.
+	AuthorizeSigner func(ctx sdk.Context, tx std.Tx, signer, grantee crypto.Address) error
+
+	// If VerifySigner is set, it is called for each signer of a tx, with the
+	// address of the key of its signature, once verified.  verified is
+	// false unless the signer has custom verification, e.g. by a realm, in
+	// which case the signature may be by another key, and err rejects tx.
+	VerifySigner func(ctx sdk.Context, tx std.Tx, signer, key crypto.Address) (verified bool, err error)
+
+	// If CheckTx is set, it is called on CheckTx (and simulation) once the
+	// signatures are verified, e.g. to check the messages of tx before they
+	// enter the mempool, with the gas meter of tx.  err rejects tx.
+	CheckTx func(ctx sdk.Context, tx std.Tx) error
 }
 
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
@@ -59,6 +77,16 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			))
 			return ctx, res, true
 		}
+		if consParams.Block.MaxTxGas > 0 && consParams.Block.MaxTxGas < tx.Fee.GasWanted {
+			// tx gas-wanted too large.
+			res = abciResult(std.ErrInvalidGasWanted(
+				fmt.Sprintf(
+					"invalid gas-wanted; got: %d tx-max-gas: %d",
+					tx.Fee.GasWanted, consParams.Block.MaxTxGas,
+				),
+			))
+			return ctx, res, true
+		}
 
 		// Ensure that the provided fees meet a minimum threshold for the validator,
 		// if this is a CheckTx. This is only for local mempool purposes, and thus
@@ -151,6 +179,18 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			sacc := signerAccs[i]
 			if isGenesis && !opts.VerifyGenesisSignatures {
 				// No signatures are needed for genesis.
+			} else if sig := stdSigs[i]; (opts.AuthorizeSigner != nil || opts.VerifySigner != nil) &&
+				sig.PubKey != nil && sig.PubKey.Address() != sacc.GetAddress() {
+				// Check signature of another key
+				signBytes := GetSignBytes(newCtx.ChainID(), tx, sacc, isGenesis)
+				signerAccs[i], res = processGranteeSig(newCtx, sacc, sig, signBytes, simulate, params, sigGasConsumer)
+				if !res.IsOK() {
+					return newCtx, res, true
+				}
+				err := authorizeKey(newCtx, tx, sacc.GetAddress(), sig.PubKey.Address(), opts)
+				if err != nil {
+					return newCtx, abciResult(err), true
+				}
 			} else {
 				// Check signature
 				signBytes := GetSignBytes(newCtx.ChainID(), tx, sacc, isGenesis)
@@ -158,10 +198,23 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 				if !res.IsOK() {
 					return newCtx, res, true
 				}
+				if opts.VerifySigner != nil {
+					_, err := opts.VerifySigner(newCtx, tx, sacc.GetAddress(), sacc.GetAddress())
+					if err != nil {
+						return newCtx, abciResult(err), true
+					}
+				}
 			}
 			ak.SetAccount(newCtx, signerAccs[i])
 		}
 
+		// Check the messages, once the fees are paid.
+		if opts.CheckTx != nil && (ctx.IsCheckTx() || simulate) {
+			if err := opts.CheckTx(newCtx, tx); err != nil {
+				return newCtx, abciResult(err), true
+			}
+		}
+
 		// TODO: tx tags (?)
 		return newCtx, sdk.Result{GasWanted: tx.Fee.GasWanted}, false // continue...
 	}
@@ -248,6 +301,47 @@ func processSig(
 	return acc, res
 }
 
+// authorizeKey returns an error unless key may sign tx in place of signer,
+// as verified by opts.VerifySigner, or else authorized by
+// opts.AuthorizeSigner.
+func authorizeKey(ctx sdk.Context, tx std.Tx, signer, key crypto.Address, opts AnteOptions) error {
+	if opts.VerifySigner != nil {
+		verified, err := opts.VerifySigner(ctx, tx, signer, key)
+		if verified || err != nil {
+			return err
+		}
+	}
+	if opts.AuthorizeSigner != nil {
+		return opts.AuthorizeSigner(ctx, tx, signer, key)
+	}
+	return std.ErrUnauthorized(fmt.Sprintf("%s may not sign for %s", key, signer))
+}
+
+// verify the signature of another key than that of acc, whose pubkey is
+// unchanged, and increment the sequence.
+func processGranteeSig(
+	ctx sdk.Context, acc std.Account, sig std.Signature, signBytes []byte, simulate bool, params Params,
+	sigGasConsumer SignatureVerificationGasConsumer,
+) (updatedAcc std.Account, res sdk.Result) {
+	if simulate {
+		consumeSimSigGas(ctx.GasMeter(), sig.PubKey, sig, params)
+	}
+
+	if res := sigGasConsumer(ctx.GasMeter(), sig.Signature, sig.PubKey, params); !res.IsOK() {
+		return nil, res
+	}
+
+	if !simulate && !sig.PubKey.VerifyBytes(signBytes, sig.Signature) {
+		return nil, abciResult(std.ErrUnauthorized("signature verification failed; verify correct account sequence and chain-id"))
+	}
+
+	if err := acc.SetSequence(acc.GetSequence() + 1); err != nil {
+		panic(err)
+	}
+
+	return acc, res
+}
+
 func consumeSimSigGas(gasmeter store.GasMeter, pubkey crypto.PubKey, sig std.Signature, params Params) {
 	simSig := std.Signature{PubKey: pubkey}
 	if len(sig.Signature) == 0 {
@@ -315,6 +409,10 @@ func DefaultSigVerificationGasConsumer(
 		meter.ConsumeGas(params.SigVerifyCostSecp256k1, "ante verify: secp256k1")
 		return sdk.Result{}
 
+	case webauthn.PubKeyWebAuthn:
+		meter.ConsumeGas(params.SigVerifyCostWebAuthn, "ante verify: webauthn")
+		return sdk.Result{}
+
 	case multisig.PubKeyMultisigThreshold:
 		var multisignature multisig.Multisignature
 		amino.MustUnmarshal(sig, &multisignature)
@@ -368,6 +466,46 @@ func DeductFees(bank BankKeeperI, ctx sdk.Context, acc std.Account, fees std.Coi
 	return sdk.Result{}
 }
 
+// NewGasRefunder returns a GasRefunder that refunds the first signer the
+// fees paid for unused gas, scaled by Params.GasRefundPercent.
+func NewGasRefunder(ak AccountKeeper, bank BankKeeperI) sdk.GasRefunder {
+	return func(ctx sdk.Context, tx std.Tx, gasUsed int64) {
+		params := ctx.Value(AuthParamsContextKey{}).(Params)
+		refund := GasRefundAmount(tx.Fee, gasUsed, params.GasRefundPercent)
+		if refund.IsZero() {
+			return
+		}
+		// The refund itself is not metered.
+		ctx = ctx.WithGasMeter(store.NewInfiniteGasMeter())
+		payer := tx.GetSigners()[0]
+		err := bank.SendCoins(ctx, FeeCollectorAddress(), payer, std.Coins{refund})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// GasRefundAmount returns the portion of fee paid for gas that was wanted but
+// not used, scaled by refundPercent (0 to 100).
+func GasRefundAmount(fee std.Fee, gasUsed int64, refundPercent int64) std.Coin {
+	zero := std.Coin{Denom: fee.GasFee.Denom, Amount: 0}
+	if fee.GasWanted <= 0 || gasUsed >= fee.GasWanted || fee.GasFee.IsZero() {
+		return zero
+	}
+	if refundPercent <= 0 {
+		return zero
+	} else if refundPercent > 100 {
+		refundPercent = 100
+	}
+	// refund = amount * (wanted - used) / wanted * percent / 100
+	amt := big.NewInt(fee.GasFee.Amount)
+	amt.Mul(amt, big.NewInt(fee.GasWanted-gasUsed))
+	amt.Mul(amt, big.NewInt(refundPercent))
+	amt.Quo(amt, big.NewInt(fee.GasWanted))
+	amt.Quo(amt, big.NewInt(100))
+	return std.Coin{Denom: fee.GasFee.Denom, Amount: amt.Int64()}
+}
+
 // EnsureSufficientMempoolFees verifies that the given transaction has supplied
 // enough fees to cover a proposer's minimum fees. A result object is returned
 // indicating success or failure.
@@ -418,7 +556,11 @@ func EnsureSufficientMempoolFees(ctx sdk.Context, fee std.Fee) sdk.Result {
 func SetGasMeter(simulate bool, ctx sdk.Context, gasLimit int64) sdk.Context {
 	// In various cases such as simulation and during the genesis block, we do not
 	// meter any gas utilization.
-	if simulate || ctx.BlockHeight() == 0 {
+	if simulate {
+		// Simulated txs also record a breakdown of gas consumption.
+		return ctx.WithGasMeter(store.NewAuditGasMeter(store.NewInfiniteGasMeter()))
+	}
+	if ctx.BlockHeight() == 0 {
 		return ctx.WithGasMeter(store.NewInfiniteGasMeter())
 	}
 
@@ -438,6 +580,15 @@ func GetSignBytes(chainID string, tx std.Tx, acc std.Account, genesis bool) []by
 	return signbz
 }
 
+// NewTxHasher returns the canonical hasher of the txs (see std.Tx.Hash), on
+// the chain of the context.  It does not depend on the accounts of the
+// signers, so that the replays of a tx, at any sequence, have its hash.
+func NewTxHasher() sdk.TxHasher {
+	return func(ctx sdk.Context, tx std.Tx) []byte {
+		return tx.Hash(ctx.ChainID())
+	}
+}
+
 func abciResult(err error) sdk.Result {
 	return sdk.ABCIResultFromError(err)
 }
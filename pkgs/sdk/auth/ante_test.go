@@ -1,6 +1,11 @@
 package auth
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -16,6 +21,7 @@ import (
 	"github.com/gnolang/gno/pkgs/crypto/ed25519"
 	"github.com/gnolang/gno/pkgs/crypto/multisig"
 	"github.com/gnolang/gno/pkgs/crypto/secp256k1"
+	"github.com/gnolang/gno/pkgs/crypto/webauthn"
 	"github.com/gnolang/gno/pkgs/sdk"
 	tu "github.com/gnolang/gno/pkgs/sdk/testutils"
 	"github.com/gnolang/gno/pkgs/std"
@@ -335,6 +341,38 @@ func TestAnteHandlerFees(t *testing.T) {
 	require.Equal(t, env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"), int64(0))
 }
 
+// Test logic around the max gas of txs.
+func TestAnteHandlerMaxTxGas(t *testing.T) {
+	// setup
+	env := setupTestEnv()
+	ctx := env.ctx
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, acc1)
+
+	// msg and signatures
+	msg := tu.NewTestMsg(addr1)
+	privs, accnums, seqs := []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}
+	fee := tu.NewTestFee()
+	tx := tu.NewTestTx(ctx.ChainID(), []std.Msg{msg}, privs, accnums, seqs, fee)
+
+	// above the max gas of txs, although below the max gas of blocks.
+	consParams := *ctx.ConsensusParams()
+	blockParams := *consParams.Block
+	blockParams.MaxTxGas = fee.GasWanted - 1
+	consParams.Block = &blockParams
+	checkInvalidTx(t, anteHandler, ctx.WithConsensusParams(&consParams), tx, false, std.InvalidGasWantedError{})
+
+	blockParams.MaxTxGas = fee.GasWanted
+	checkValidTx(t, anteHandler, ctx.WithConsensusParams(&consParams), tx, false)
+}
+
 // Test logic around memo gas consumption.
 func TestAnteHandlerMemoGas(t *testing.T) {
 	// setup
@@ -555,6 +593,199 @@ func TestAnteHandlerSetPubKey(t *testing.T) {
 	require.Nil(t, acc2.GetPubKey())
 }
 
+func TestAnteHandlerWebAuthn(t *testing.T) {
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	ctx := env.ctx
+
+	// credential of an authenticator, and its account
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), crand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pubKey, err := webauthn.NewPubKeyFromSPKI(der)
+	require.NoError(t, err)
+	addr := pubKey.Address()
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	acc.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, acc)
+
+	// Returns the tx signed by an assertion of the challenge of signBytes.
+	newTx := func(signBytes []byte) std.Tx {
+		msgs := []std.Msg{tu.NewTestMsg(addr)}
+		fee := tu.NewTestFee()
+		if signBytes == nil {
+			signBytes = std.SignBytes(ctx.ChainID(), 0, 0, fee, msgs, "")
+		}
+		wsig := webauthn.Signature{
+			AuthenticatorData: append(make([]byte, 32), 0x01, 0, 0, 0, 1), // user present
+			ClientDataJSON: []byte(fmt.Sprintf(`{"type":"webauthn.get","challenge":%q}`,
+				webauthn.ChallengeString(signBytes))),
+		}
+		clientDataHash := sha256.Sum256(wsig.ClientDataJSON)
+		hash := sha256.Sum256(append(wsig.AuthenticatorData, clientDataHash[:]...))
+		wsig.Signature, err = ecdsa.SignASN1(crand.Reader, priv, hash[:])
+		require.NoError(t, err)
+		sigs := []std.Signature{{PubKey: pubKey, Signature: wsig.Bytes()}}
+		return std.NewTx(msgs, fee, sigs, "")
+	}
+
+	// wrong sign bytes
+	checkInvalidTx(t, anteHandler, ctx, newTx([]byte("wrong")), false, std.UnauthorizedError{})
+
+	// good tx, setting the public key
+	checkValidTx(t, anteHandler, ctx, newTx(nil), false)
+	acc = env.acck.GetAccount(ctx, addr)
+	require.Equal(t, pubKey, acc.GetPubKey())
+	require.Equal(t, uint64(1), acc.GetSequence())
+}
+
+func TestAnteHandlerGrantee(t *testing.T) {
+	// setup
+	env := setupTestEnv()
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	priv2, _, addr2 := tu.KeyTestPubAddr()
+	opts := defaultAnteOptions()
+	opts.AuthorizeSigner = func(ctx sdk.Context, tx std.Tx, signer, grantee crypto.Address) error {
+		if signer != addr1 || grantee != addr2 {
+			return std.ErrUnauthorized("not granted")
+		}
+		return nil
+	}
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, opts)
+	ctx := env.ctx
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	require.NoError(t, acc1.SetAccountNumber(0))
+	env.acck.SetAccount(ctx, acc1)
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	acc2.SetCoins(tu.NewTestCoins())
+	require.NoError(t, acc2.SetAccountNumber(1))
+	env.acck.SetAccount(ctx, acc2)
+
+	fee := tu.NewTestFee()
+
+	// the grantee signs the sign bytes of the granter
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	tx := tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv2}, []uint64{0}, []uint64{0}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	acc1 = env.acck.GetAccount(ctx, addr1)
+	require.Nil(t, acc1.GetPubKey())
+	require.Equal(t, uint64(1), acc1.GetSequence())
+	acc2 = env.acck.GetAccount(ctx, addr2)
+	require.Equal(t, uint64(0), acc2.GetSequence())
+
+	// the sign bytes of the grantee are rejected
+	tx = tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv2}, []uint64{1}, []uint64{0}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// the granter is not granted to sign for the grantee
+	msgs = []std.Msg{tu.NewTestMsg(addr2)}
+	tx = tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{1}, []uint64{0}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+}
+
+func TestAnteHandlerVerifySigner(t *testing.T) {
+	// setup
+	env := setupTestEnv()
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	priv2, _, addr2 := tu.KeyTestPubAddr()
+	priv3, _, addr3 := tu.KeyTestPubAddr()
+	var keys []crypto.Address
+	opts := defaultAnteOptions()
+	opts.VerifySigner = func(ctx sdk.Context, tx std.Tx, signer, key crypto.Address) (bool, error) {
+		if signer != addr1 {
+			return false, nil
+		}
+		keys = append(keys, key)
+		if key == addr3 {
+			return true, std.ErrUnauthorized("rejected")
+		}
+		return true, nil
+	}
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, opts)
+	ctx := env.ctx
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	require.NoError(t, acc1.SetAccountNumber(0))
+	env.acck.SetAccount(ctx, acc1)
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	acc2.SetCoins(tu.NewTestCoins())
+	require.NoError(t, acc2.SetAccountNumber(1))
+	env.acck.SetAccount(ctx, acc2)
+
+	fee := tu.NewTestFee()
+
+	// the signer's own key is verified too
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	tx := tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, []crypto.Address{addr1}, keys)
+
+	// another key verified for the signer
+	tx = tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv2}, []uint64{0}, []uint64{1}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, []crypto.Address{addr1, addr2}, keys)
+	acc1 = env.acck.GetAccount(ctx, addr1)
+	require.Equal(t, priv1.PubKey(), acc1.GetPubKey())
+	require.Equal(t, uint64(2), acc1.GetSequence())
+
+	// a key rejected for the signer
+	tx = tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv3}, []uint64{0}, []uint64{2}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// signers without verification may not be signed for
+	msgs = []std.Msg{tu.NewTestMsg(addr2)}
+	tx = tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{1}, []uint64{0}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+}
+
+func TestAnteHandlerCheckTx(t *testing.T) {
+	// setup
+	env := setupTestEnv()
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	var checked int
+	opts := defaultAnteOptions()
+	opts.CheckTx = func(ctx sdk.Context, tx std.Tx) error {
+		checked++
+		if tx.GetMemo() == "invalid" {
+			return std.ErrUnauthorized("rejected")
+		}
+		return nil
+	}
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, opts)
+	ctx := env.ctx
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	require.NoError(t, acc1.SetAccountNumber(0))
+	env.acck.SetAccount(ctx, acc1)
+
+	fee := tu.NewTestFee()
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+
+	// not called on DeliverTx
+	tx := tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, 0, checked)
+
+	// called on CheckTx, and may reject the tx
+	ctx = ctx.WithMode(sdk.RunTxModeCheck)
+	tx = tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{1}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, 1, checked)
+	tx = tu.NewTestTxWithMemo(ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{2}, fee, "invalid")
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+	require.Equal(t, 2, checked)
+}
+
 func TestProcessPubKey(t *testing.T) {
 	env := setupTestEnv()
 	ctx := env.ctx
@@ -617,6 +848,7 @@ func TestConsumeSignatureVerificationGas(t *testing.T) {
 	}{
 		{"PubKeyEd25519", args{store.NewInfiniteGasMeter(), nil, ed25519.GenPrivKey().PubKey(), params}, DefaultSigVerifyCostED25519, true},
 		{"PubKeySecp256k1", args{store.NewInfiniteGasMeter(), nil, secp256k1.GenPrivKey().PubKey(), params}, DefaultSigVerifyCostSecp256k1, false},
+		{"PubKeyWebAuthn", args{store.NewInfiniteGasMeter(), nil, webauthn.PubKeyWebAuthn{}, params}, DefaultSigVerifyCostWebAuthn, false},
 		{"Multisig", args{store.NewInfiniteGasMeter(), amino.MustMarshal(multisignature1), multisigKey1, params}, expectedCost1, false},
 		{"unknown key", args{store.NewInfiniteGasMeter(), nil, nil, params}, 0, true},
 	}
@@ -769,6 +1001,55 @@ func TestEnsureSufficientMempoolFees(t *testing.T) {
 	}
 }
 
+func TestGasRefundAmount(t *testing.T) {
+	fee := std.NewFee(200000, std.NewCoin("atom", 1000))
+
+	testCases := []struct {
+		gasUsed       int64
+		refundPercent int64
+		expected      int64
+	}{
+		{200000, 100, 0},
+		{300000, 100, 0},
+		{0, 100, 1000},
+		{50000, 100, 750},
+		{50000, 50, 375},
+		{50000, 0, 0},
+		{50000, 200, 750},
+	}
+
+	for i, tc := range testCases {
+		refund := GasRefundAmount(fee, tc.gasUsed, tc.refundPercent)
+		require.Equal(t, "atom", refund.Denom, "tc #%d", i)
+		require.Equal(t, tc.expected, refund.Amount, "tc #%d", i)
+	}
+}
+
+func TestGasRefunder(t *testing.T) {
+	// setup
+	env := setupTestEnv()
+	ctx := env.ctx
+	anteHandler := NewAnteHandler(env.acck, env.bank, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	refunder := NewGasRefunder(env.acck, env.bank)
+
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	require.NoError(t, acc1.SetCoins(std.NewCoins(std.NewCoin("atom", 1000))))
+	env.acck.SetAccount(ctx, acc1)
+
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	fee := std.NewFee(100000, std.NewCoin("atom", 100))
+	tx := tu.NewTestTx(ctx.ChainID(), msgs, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}, fee)
+	newCtx, _, abort := anteHandler(ctx, tx, false)
+	require.False(t, abort)
+	require.Equal(t, int64(900), env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"))
+
+	// a quarter of the gas was used, so half of 75% of the fee is refunded.
+	refunder(newCtx, tx, 25000)
+	require.Equal(t, int64(937), env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"))
+	require.Equal(t, int64(63), env.acck.GetAccount(ctx, FeeCollectorAddress()).GetCoins().AmountOf("atom"))
+}
+
 // Test custom SignatureVerificationGasConsumer
 func TestCustomSignatureVerificationGasConsumer(t *testing.T) {
 	// setup
@@ -12,16 +12,37 @@ import (
 )
 
 type authHandler struct {
-	acck AccountKeeper
+	acck    AccountKeeper
+	queries sdk.QueryRouter
 }
 
 // NewHandler returns a handler for "auth" type messages.
 func NewHandler(acck AccountKeeper) authHandler {
-	return authHandler{
+	ah := authHandler{
+		acck:    acck,
+		queries: sdk.NewQueryRouter(),
+	}
+	ah.registerQueries(ah.queries)
+	return ah
+}
+
+type authModule struct {
+	acck AccountKeeper
+}
+
+// NewModule returns the module of "auth" type messages and queries.
+func NewModule(acck AccountKeeper) sdk.Module {
+	return authModule{
 		acck: acck,
 	}
 }
 
+func (am authModule) Name() string { return ModuleName }
+
+func (am authModule) Handler() sdk.Handler { return NewHandler(am.acck) }
+
+func (am authModule) RegisterQueries(qr sdk.QueryRouter) { NewHandler(am.acck).registerQueries(qr) }
+
 func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 	// no messages supported yet.
 	errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
@@ -34,15 +55,13 @@ func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 // query account path
 const QueryAccount = "accounts"
 
-func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
-	switch secondPart(req.Path) {
-	case QueryAccount:
-		return ah.queryAccount(ctx, req)
-	default:
-		res = sdk.ABCIResponseQueryFromError(
-			std.ErrUnknownRequest("unknown auth query endpoint"))
-		return
-	}
+// Adds the query routes of the auth module, e.g. "auth/accounts", to qr.
+func (ah authHandler) registerQueries(qr sdk.QueryRouter) {
+	qr.AddRoute(ModuleName+"/"+QueryAccount, ah.queryAccount)
+}
+
+func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return sdk.RouteQuery(ah.queries, ctx, req)
 }
 
 // queryAccount fetch an account for the supplied height.
@@ -75,16 +94,6 @@ func (ah authHandler) queryAccount(ctx sdk.Context, req abci.RequestQuery) (res
 //----------------------------------------
 // misc
 
-// returns the second component of a path.
-func secondPart(path string) string {
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 {
-		return ""
-	} else {
-		return parts[1]
-	}
-}
-
 // returns the third component of a path.
 func thirdPart(path string) string {
 	parts := strings.Split(path, "/")
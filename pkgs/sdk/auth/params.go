@@ -16,6 +16,10 @@ const (
 	DefaultTxSizeCostPerByte      int64 = 10
 	DefaultSigVerifyCostED25519   int64 = 590
 	DefaultSigVerifyCostSecp256k1 int64 = 1000
+	DefaultSigVerifyCostWebAuthn  int64 = 1500
+	// Half, so that overstating the gas wanted, which reserves space in
+	// blocks, is not free.
+	DefaultGasRefundPercent int64 = 50
 )
 
 // Params defines the parameters for the auth module.
@@ -25,11 +29,13 @@ type Params struct {
 	TxSizeCostPerByte      int64 `json:"tx_size_cost_per_byte" yaml:"tx_size_cost_per_byte"`
 	SigVerifyCostED25519   int64 `json:"sig_verify_cost_ed25519" yaml:"sig_verify_cost_ed25519"`
 	SigVerifyCostSecp256k1 int64 `json:"sig_verify_cost_secp256k1" yaml:"sig_verify_cost_secp256k1"`
+	SigVerifyCostWebAuthn  int64 `json:"sig_verify_cost_webauthn" yaml:"sig_verify_cost_webauthn"`
+	GasRefundPercent       int64 `json:"gas_refund_percent" yaml:"gas_refund_percent"`
 }
 
 // NewParams creates a new Params object
 func NewParams(maxMemoBytes, txSigLimit, txSizeCostPerByte,
-	sigVerifyCostED25519, sigVerifyCostSecp256k1 int64,
+	sigVerifyCostED25519, sigVerifyCostSecp256k1, sigVerifyCostWebAuthn, gasRefundPercent int64,
 ) Params {
 	return Params{
 		MaxMemoBytes:           maxMemoBytes,
@@ -37,6 +43,8 @@ func NewParams(maxMemoBytes, txSigLimit, txSizeCostPerByte,
 		TxSizeCostPerByte:      txSizeCostPerByte,
 		SigVerifyCostED25519:   sigVerifyCostED25519,
 		SigVerifyCostSecp256k1: sigVerifyCostSecp256k1,
+		SigVerifyCostWebAuthn:  sigVerifyCostWebAuthn,
+		GasRefundPercent:       gasRefundPercent,
 	}
 }
 
@@ -53,6 +61,8 @@ func DefaultParams() Params {
 		TxSizeCostPerByte:      DefaultTxSizeCostPerByte,
 		SigVerifyCostED25519:   DefaultSigVerifyCostED25519,
 		SigVerifyCostSecp256k1: DefaultSigVerifyCostSecp256k1,
+		SigVerifyCostWebAuthn:  DefaultSigVerifyCostWebAuthn,
+		GasRefundPercent:       DefaultGasRefundPercent,
 	}
 }
 
@@ -65,5 +75,7 @@ func (p Params) String() string {
 	sb.WriteString(fmt.Sprintf("TxSizeCostPerByte: %d\n", p.TxSizeCostPerByte))
 	sb.WriteString(fmt.Sprintf("SigVerifyCostED25519: %d\n", p.SigVerifyCostED25519))
 	sb.WriteString(fmt.Sprintf("SigVerifyCostSecp256k1: %d\n", p.SigVerifyCostSecp256k1))
+	sb.WriteString(fmt.Sprintf("SigVerifyCostWebAuthn: %d\n", p.SigVerifyCostWebAuthn))
+	sb.WriteString(fmt.Sprintf("GasRefundPercent: %d\n", p.GasRefundPercent))
 	return sb.String()
 }
@@ -0,0 +1,36 @@
+package authz
+
+// DONTCOVER
+
+import (
+	"time"
+
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/log"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/iavl"
+)
+
+type testEnv struct {
+	ctx   sdk.Context
+	authz AuthzKeeper
+}
+
+func setupTestEnv() testEnv {
+	db := dbm.NewMemDB()
+
+	mainCapKey := store.NewStoreKey("mainCapKey")
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(mainCapKey, iavl.StoreConstructor, db)
+	ms.LoadLatestVersion()
+
+	header := &bft.Header{ChainID: "test-chain-id", Height: 10, Time: time.Unix(1000000, 0).UTC()}
+	ctx := sdk.NewContext(sdk.RunTxModeDeliver, ms, header, log.NewNopLogger())
+	authz := NewAuthzKeeper(mainCapKey)
+
+	return testEnv{ctx: ctx, authz: authz}
+}
@@ -0,0 +1,29 @@
+package authz
+
+import (
+	"github.com/gnolang/gno/pkgs/crypto"
+)
+
+const (
+	// module name
+	ModuleName = "authz"
+
+	// RouterKey is the name of the authz module
+	RouterKey = ModuleName
+
+	// GrantStoreKeyPrefix prefix for grant-by-granter-and-grantee store
+	GrantStoreKeyPrefix = "/authz/g/"
+)
+
+// GrantStoreKey turns a granter and a grantee to the key used to get the
+// grant from the store, ordered by granter.
+func GrantStoreKey(granter, grantee crypto.Address) []byte {
+	key := append([]byte(GrantStoreKeyPrefix), granter.Bytes()...)
+	return append(key, grantee.Bytes()...)
+}
+
+// GranterStoreKeyPrefix returns the prefix of the keys of the grants of
+// granter.
+func GranterStoreKeyPrefix(granter crypto.Address) []byte {
+	return append([]byte(GrantStoreKeyPrefix), granter.Bytes()...)
+}
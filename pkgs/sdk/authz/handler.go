@@ -0,0 +1,156 @@
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+type authzHandler struct {
+	authz   AuthzKeeper
+	queries sdk.QueryRouter
+}
+
+// NewHandler returns a handler for "authz" type messages.
+func NewHandler(authz AuthzKeeper) authzHandler {
+	ah := authzHandler{
+		authz:   authz,
+		queries: sdk.NewQueryRouter(),
+	}
+	ah.registerQueries(ah.queries)
+	return ah
+}
+
+type authzModule struct {
+	authz AuthzKeeper
+}
+
+var _ sdk.GenesisModule = authzModule{}
+
+// NewModule returns the module of "authz" type messages and queries, whose
+// state at genesis is a GenesisState.
+func NewModule(authz AuthzKeeper) sdk.Module {
+	return authzModule{
+		authz: authz,
+	}
+}
+
+func (am authzModule) Name() string { return ModuleName }
+
+func (am authzModule) Handler() sdk.Handler { return NewHandler(am.authz) }
+
+func (am authzModule) RegisterQueries(qr sdk.QueryRouter) { NewHandler(am.authz).registerQueries(qr) }
+
+// InitGenesis implements sdk.GenesisModule.
+func (am authzModule) InitGenesis(ctx sdk.Context, state interface{}) error {
+	gs, ok := state.(GenesisState)
+	if !ok {
+		return fmt.Errorf("invalid authz genesis state %T", state)
+	}
+	for _, grant := range gs.Grants {
+		if err := MsgGrant(grant).ValidateBasic(); err != nil {
+			return err
+		}
+		am.authz.SetGrant(ctx, grant)
+	}
+	return nil
+}
+
+// ExportGenesis implements sdk.GenesisModule.
+func (am authzModule) ExportGenesis(ctx sdk.Context) interface{} {
+	return GenesisState{
+		Grants: am.authz.GetAllGrants(ctx),
+	}
+}
+
+func (ah authzHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
+	switch msg := msg.(type) {
+	case MsgGrant:
+		return ah.handleMsgGrant(ctx, msg)
+
+	case MsgRevoke:
+		return ah.handleMsgRevoke(ctx, msg)
+
+	default:
+		errMsg := fmt.Sprintf("unrecognized authz message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Handle MsgGrant.
+func (ah authzHandler) handleMsgGrant(ctx sdk.Context, msg MsgGrant) sdk.Result {
+	grant := msg.Grant()
+	if grant.IsExpired(ctx.BlockTime()) {
+		return abciResult(std.ErrUnknownRequest("grant already expired"))
+	}
+	ah.authz.SetGrant(ctx, grant)
+	return sdk.Result{}
+}
+
+// Handle MsgRevoke.
+func (ah authzHandler) handleMsgRevoke(ctx sdk.Context, msg MsgRevoke) sdk.Result {
+	if _, ok := ah.authz.GetGrant(ctx, msg.Granter, msg.Grantee); !ok {
+		return abciResult(std.ErrUnknownRequest(
+			fmt.Sprintf("no grant of %s to %s", msg.Granter, msg.Grantee)))
+	}
+	ah.authz.DeleteGrant(ctx, msg.Granter, msg.Grantee)
+	return sdk.Result{}
+}
+
+//----------------------------------------
+// Query
+
+const QueryGrants = "grants"
+
+// Adds the query routes of the authz module, e.g. "authz/grants", to qr.
+func (ah authzHandler) registerQueries(qr sdk.QueryRouter) {
+	qr.AddRoute(ModuleName+"/"+QueryGrants, ah.queryGrants)
+}
+
+func (ah authzHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return sdk.RouteQuery(ah.queries, ctx, req)
+}
+
+// queryGrants returns the grants of the granter of the path
+// "grants/<granter>".
+func (ah authzHandler) queryGrants(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	b32addr := thirdPart(req.Path)
+	addr, err := crypto.AddressFromBech32(b32addr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInvalidAddress(
+				"invalid query address " + b32addr))
+		return
+	}
+
+	bz, err := amino.MarshalJSONIndent(ah.authz.GetGrants(ctx, addr), "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+	res.Data = bz
+	return
+}
+
+//----------------------------------------
+// misc
+
+func abciResult(err error) sdk.Result {
+	return sdk.ABCIResultFromError(err)
+}
+
+// returns the third component of a path.
+func thirdPart(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 {
+		return ""
+	} else {
+		return parts[2]
+	}
+}
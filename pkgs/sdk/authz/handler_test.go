@@ -0,0 +1,115 @@
+package authz
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	tu "github.com/gnolang/gno/pkgs/sdk/testutils"
+	"github.com/gnolang/gno/pkgs/sdk/vm"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+func TestInvalidMsg(t *testing.T) {
+	h := NewHandler(AuthzKeeper{})
+	res := h.Process(sdk.NewContext(sdk.RunTxModeDeliver, nil, &bft.Header{ChainID: "test-chain"}, nil), tu.NewTestMsg())
+	require.False(t, res.IsOK())
+	require.True(t, strings.Contains(res.Log, "unrecognized authz message type"))
+}
+
+func TestMsgGrantValidateBasic(t *testing.T) {
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+	coins := std.NewCoins(std.NewCoin("ugnot", 10))
+
+	require.NoError(t, NewMsgGrant(granter, grantee, []string{"vm/exec"}, nil, coins, time.Time{}).ValidateBasic())
+	require.Error(t, NewMsgGrant(granter, granter, []string{"vm/exec"}, nil, coins, time.Time{}).ValidateBasic())
+	require.Error(t, NewMsgGrant(granter, grantee, nil, nil, coins, time.Time{}).ValidateBasic())
+	require.Error(t, NewMsgGrant(granter, grantee, []string{"exec"}, nil, coins, time.Time{}).ValidateBasic())
+	require.Error(t, NewMsgGrant(granter, grantee, []string{"authz/grant"}, nil, coins, time.Time{}).ValidateBasic())
+	require.NoError(t, NewMsgRevoke(granter, grantee).ValidateBasic())
+}
+
+func TestGrantRevoke(t *testing.T) {
+	env := setupTestEnv()
+	h := NewHandler(env.authz)
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+
+	// expired grants are rejected.
+	expired := NewMsgGrant(granter, grantee, []string{"vm/exec"}, nil, nil, env.ctx.BlockTime())
+	require.False(t, h.Process(env.ctx, expired).IsOK())
+
+	msg := NewMsgGrant(granter, grantee, []string{"vm/exec"}, []string{"gno.land/r/demo/game"}, nil, time.Time{})
+	require.True(t, h.Process(env.ctx, msg).IsOK())
+	grant, ok := env.authz.GetGrant(env.ctx, granter, grantee)
+	require.True(t, ok)
+	require.Equal(t, msg.Grant(), grant)
+
+	res := h.Query(env.ctx, abci.RequestQuery{Path: "authz/grants/" + granter.String()})
+	require.True(t, res.IsOK())
+	var grants []Grant
+	amino.MustUnmarshalJSON(res.Data, &grants)
+	require.Equal(t, []Grant{grant}, grants)
+
+	require.True(t, h.Process(env.ctx, NewMsgRevoke(granter, grantee)).IsOK())
+	_, ok = env.authz.GetGrant(env.ctx, granter, grantee)
+	require.False(t, ok)
+	require.False(t, h.Process(env.ctx, NewMsgRevoke(granter, grantee)).IsOK())
+}
+
+func TestAuthorizeSigner(t *testing.T) {
+	env := setupTestEnv()
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+	_, _, other := tu.KeyTestPubAddr()
+	fee := std.NewFee(100000, std.NewCoin("ugnot", 10))
+	game := "gno.land/r/demo/game"
+
+	newTx := func(msgs ...std.Msg) std.Tx {
+		return std.NewTx(msgs, fee, nil, "")
+	}
+	call := vm.NewMsgCall(granter, std.NewCoins(std.NewCoin("ugnot", 50)), game, "Play", nil)
+
+	// no grant.
+	require.Error(t, env.authz.AuthorizeSigner(env.ctx, newTx(call), granter, grantee))
+
+	env.authz.SetGrant(env.ctx, Grant{
+		Granter:    granter,
+		Grantee:    grantee,
+		MsgTypes:   []string{"vm/exec"},
+		PkgPaths:   []string{game},
+		SpendLimit: std.NewCoins(std.NewCoin("ugnot", 100)),
+		Expiration: env.ctx.BlockTime().Add(time.Hour),
+	})
+
+	// msg types and packages not granted.
+	send := bank.NewMsgSend(granter, other, std.NewCoins(std.NewCoin("ugnot", 1)))
+	require.Error(t, env.authz.AuthorizeSigner(env.ctx, newTx(send), granter, grantee))
+	otherCall := vm.NewMsgCall(granter, nil, "gno.land/r/demo/other", "Play", nil)
+	require.Error(t, env.authz.AuthorizeSigner(env.ctx, newTx(otherCall), granter, grantee))
+
+	// fees and sends decrease the spend limit.
+	require.NoError(t, env.authz.AuthorizeSigner(env.ctx, newTx(call), granter, grantee))
+	grant, _ := env.authz.GetGrant(env.ctx, granter, grantee)
+	require.Equal(t, std.NewCoins(std.NewCoin("ugnot", 40)), grant.SpendLimit)
+	require.Error(t, env.authz.AuthorizeSigner(env.ctx, newTx(call), granter, grantee))
+
+	// fees paid by another signer are not spent by the granter.
+	other2 := vm.NewMsgCall(other, nil, game, "Play", nil)
+	free := vm.NewMsgCall(granter, nil, game, "Play", nil)
+	require.NoError(t, env.authz.AuthorizeSigner(env.ctx, newTx(other2, free), granter, grantee))
+	grant, _ = env.authz.GetGrant(env.ctx, granter, grantee)
+	require.Equal(t, std.NewCoins(std.NewCoin("ugnot", 40)), grant.SpendLimit)
+
+	// expired grant.
+	ctx := env.ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 11, Time: grant.Expiration})
+	require.Error(t, env.authz.AuthorizeSigner(ctx, newTx(free), granter, grantee))
+}
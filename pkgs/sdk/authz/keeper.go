@@ -0,0 +1,121 @@
+package authz
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// authz.AuthzKeeperI stores the grants of accounts to other keys, and
+// authorizes the txs signed by those keys.
+type AuthzKeeperI interface {
+	GetGrant(ctx sdk.Context, granter, grantee crypto.Address) (Grant, bool)
+	GetGrants(ctx sdk.Context, granter crypto.Address) []Grant
+	GetAllGrants(ctx sdk.Context) []Grant
+	SetGrant(ctx sdk.Context, grant Grant)
+	DeleteGrant(ctx sdk.Context, granter, grantee crypto.Address)
+
+	AuthorizeSigner(ctx sdk.Context, tx std.Tx, signer, grantee crypto.Address) error
+}
+
+var _ AuthzKeeperI = AuthzKeeper{}
+
+// AuthzKeeper stores the grants under the key.
+type AuthzKeeper struct {
+	key store.StoreKey
+}
+
+// NewAuthzKeeper returns a new AuthzKeeper.
+func NewAuthzKeeper(key store.StoreKey) AuthzKeeper {
+	return AuthzKeeper{
+		key: key,
+	}
+}
+
+// GetGrant returns the grant of granter to grantee, if any.
+func (ak AuthzKeeper) GetGrant(ctx sdk.Context, granter, grantee crypto.Address) (grant Grant, ok bool) {
+	stor := ctx.Store(ak.key)
+	bz := stor.Get(GrantStoreKey(granter, grantee))
+	if bz == nil {
+		return grant, false
+	}
+	amino.MustUnmarshal(bz, &grant)
+	return grant, true
+}
+
+// GetGrants returns the grants of granter, sorted by grantee.
+func (ak AuthzKeeper) GetGrants(ctx sdk.Context, granter crypto.Address) []Grant {
+	grants := []Grant{}
+	stor := ctx.Store(ak.key)
+	iter := store.PrefixIterator(stor, GranterStoreKeyPrefix(granter))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var grant Grant
+		amino.MustUnmarshal(iter.Value(), &grant)
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// GetAllGrants returns the grants of all granters, sorted by granter and
+// grantee.
+func (ak AuthzKeeper) GetAllGrants(ctx sdk.Context) []Grant {
+	grants := []Grant{}
+	stor := ctx.Store(ak.key)
+	iter := store.PrefixIterator(stor, []byte(GrantStoreKeyPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var grant Grant
+		amino.MustUnmarshal(iter.Value(), &grant)
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// SetGrant sets the grant, replacing the previous grant of its granter to
+// its grantee, if any.
+func (ak AuthzKeeper) SetGrant(ctx sdk.Context, grant Grant) {
+	stor := ctx.Store(ak.key)
+	stor.Set(GrantStoreKey(grant.Granter, grant.Grantee), amino.MustMarshal(grant))
+}
+
+// DeleteGrant deletes the grant of granter to grantee, if any.
+func (ak AuthzKeeper) DeleteGrant(ctx sdk.Context, granter, grantee crypto.Address) {
+	stor := ctx.Store(ak.key)
+	stor.Delete(GrantStoreKey(granter, grantee))
+}
+
+// AuthorizeSigner returns an error unless grantee is granted to sign tx in
+// place of signer, and decreases the spend limit of the grant by the fees
+// paid and the coins sent by signer in tx.  It is the
+// auth.AnteOptions.AuthorizeSigner of an application.
+func (ak AuthzKeeper) AuthorizeSigner(ctx sdk.Context, tx std.Tx, signer, grantee crypto.Address) error {
+	grant, ok := ak.GetGrant(ctx, signer, grantee)
+	if !ok {
+		return std.ErrUnauthorized(fmt.Sprintf("no grant of %s to %s", signer, grantee))
+	}
+	var msgs []std.Msg
+	spent := std.Coins{}
+	if tx.GetSigners()[0] == signer && !tx.Fee.GasFee.IsZero() {
+		spent = spent.Add(std.Coins{tx.Fee.GasFee})
+	}
+	for _, msg := range tx.GetMsgs() {
+		for _, addr := range msg.GetSigners() {
+			if addr == signer {
+				msgs = append(msgs, msg)
+				spent = spent.Add(msgSpent(msg, signer))
+				break
+			}
+		}
+	}
+	grant, err := grant.Authorize(ctx.BlockTime(), msgs, spent)
+	if err != nil {
+		return err
+	}
+	ak.SetGrant(ctx, grant)
+	return nil
+}
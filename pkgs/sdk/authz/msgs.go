@@ -0,0 +1,142 @@
+package authz
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+//----------------------------------------
+// MsgGrant
+
+// MsgGrant - grant Grantee the permission to sign txs in place of Granter,
+// replacing its previous grant if any.  See Grant.
+type MsgGrant struct {
+	Granter    crypto.Address `json:"granter" yaml:"granter"`
+	Grantee    crypto.Address `json:"grantee" yaml:"grantee"`
+	MsgTypes   []string       `json:"msg_types" yaml:"msg_types"`
+	PkgPaths   []string       `json:"pkg_paths" yaml:"pkg_paths"`
+	SpendLimit std.Coins      `json:"spend_limit" yaml:"spend_limit"`
+	Expiration time.Time      `json:"expiration" yaml:"expiration"`
+}
+
+var _ std.Msg = MsgGrant{}
+
+// NewMsgGrant - construct a grant msg.
+func NewMsgGrant(granter, grantee crypto.Address, msgTypes, pkgPaths []string, spendLimit std.Coins, expiration time.Time) MsgGrant {
+	return MsgGrant{
+		Granter:    granter,
+		Grantee:    grantee,
+		MsgTypes:   msgTypes,
+		PkgPaths:   pkgPaths,
+		SpendLimit: spendLimit,
+		Expiration: expiration,
+	}
+}
+
+// Route Implements Msg.
+func (msg MsgGrant) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgGrant) Type() string { return "grant" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgGrant) ValidateBasic() error {
+	if msg.Granter.IsZero() {
+		return std.ErrInvalidAddress("missing granter address")
+	}
+	if msg.Grantee.IsZero() {
+		return std.ErrInvalidAddress("missing grantee address")
+	}
+	if msg.Granter == msg.Grantee {
+		return std.ErrInvalidAddress("granter is the grantee")
+	}
+	if len(msg.MsgTypes) == 0 {
+		return std.ErrUnknownRequest("missing msg types")
+	}
+	for _, msgType := range msg.MsgTypes {
+		parts := strings.Split(msgType, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return std.ErrUnknownRequest("invalid msg type " + msgType + ", expected <route>/<type>")
+		}
+		// a grantee must not grant itself more.
+		if parts[0] == RouterKey {
+			return std.ErrUnauthorized("authz msgs cannot be granted")
+		}
+	}
+	if !msg.SpendLimit.IsValid() {
+		return std.ErrInvalidCoins("invalid spend limit " + msg.SpendLimit.String())
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgGrant) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgGrant) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
+
+// Grant returns the grant of msg.
+func (msg MsgGrant) Grant() Grant {
+	return Grant{
+		Granter:    msg.Granter,
+		Grantee:    msg.Grantee,
+		MsgTypes:   msg.MsgTypes,
+		PkgPaths:   msg.PkgPaths,
+		SpendLimit: msg.SpendLimit,
+		Expiration: msg.Expiration,
+	}
+}
+
+//----------------------------------------
+// MsgRevoke
+
+// MsgRevoke - revoke the grant of Granter to Grantee.
+type MsgRevoke struct {
+	Granter crypto.Address `json:"granter" yaml:"granter"`
+	Grantee crypto.Address `json:"grantee" yaml:"grantee"`
+}
+
+var _ std.Msg = MsgRevoke{}
+
+// NewMsgRevoke - construct a revoke msg.
+func NewMsgRevoke(granter, grantee crypto.Address) MsgRevoke {
+	return MsgRevoke{
+		Granter: granter,
+		Grantee: grantee,
+	}
+}
+
+// Route Implements Msg.
+func (msg MsgRevoke) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRevoke) Type() string { return "revoke" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRevoke) ValidateBasic() error {
+	if msg.Granter.IsZero() {
+		return std.ErrInvalidAddress("missing granter address")
+	}
+	if msg.Grantee.IsZero() {
+		return std.ErrInvalidAddress("missing grantee address")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRevoke) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRevoke) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
@@ -0,0 +1,16 @@
+package authz
+
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/pkgs/sdk/authz",
+	"authz",
+	amino.GetCallersDirname(),
+).WithDependencies().WithTypes(
+	Grant{}, "Grant",
+	GenesisState{}, "GenesisState",
+	MsgGrant{}, "MsgGrant",
+	MsgRevoke{}, "MsgRevoke",
+))
@@ -0,0 +1,120 @@
+package authz
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/sdk/vm"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Grant is the permission of Grantee, e.g. a session key of a game or a
+// bot, to sign txs in place of Granter, whose msgs signed by Granter are
+// all of MsgTypes, e.g. "vm/exec", and if PkgPaths is not empty, whose vm
+// msgs are all of those packages.
+//
+// The coins spent by Granter in those txs, i.e. the fees it pays and the
+// coins it sends, are limited by SpendLimit, which each tx decreases: a
+// grant without a spend limit permits no spending.  The grant expires at
+// Expiration, unless it is zero.
+type Grant struct {
+	Granter    crypto.Address `json:"granter" yaml:"granter"`
+	Grantee    crypto.Address `json:"grantee" yaml:"grantee"`
+	MsgTypes   []string       `json:"msg_types" yaml:"msg_types"`
+	PkgPaths   []string       `json:"pkg_paths" yaml:"pkg_paths"`
+	SpendLimit std.Coins      `json:"spend_limit" yaml:"spend_limit"`
+	Expiration time.Time      `json:"expiration" yaml:"expiration"`
+}
+
+// GenesisState is the state of the authz module at genesis.
+type GenesisState struct {
+	Grants []Grant `json:"grants" yaml:"grants"`
+}
+
+// MsgType returns the type of msg in grants, its route and type.
+func MsgType(msg std.Msg) string {
+	return msg.Route() + "/" + msg.Type()
+}
+
+// IsExpired returns whether the grant is expired at now.
+func (g Grant) IsExpired(now time.Time) bool {
+	return !g.Expiration.IsZero() && !now.Before(g.Expiration)
+}
+
+// Authorize returns an error unless the grant permits the txs of the
+// grantee of msgs, spending spent, at now, and returns the grant with its
+// spend limit decreased.
+func (g Grant) Authorize(now time.Time, msgs []std.Msg, spent std.Coins) (Grant, error) {
+	if g.IsExpired(now) {
+		return g, std.ErrUnauthorized(fmt.Sprintf("grant of %s to %s expired", g.Granter, g.Grantee))
+	}
+	for _, msg := range msgs {
+		if !contains(g.MsgTypes, MsgType(msg)) {
+			return g, std.ErrUnauthorized(fmt.Sprintf("msg type %s not granted", MsgType(msg)))
+		}
+		if pkgPath, ok := msgPkgPath(msg); ok && len(g.PkgPaths) > 0 && !contains(g.PkgPaths, pkgPath) {
+			return g, std.ErrUnauthorized(fmt.Sprintf("package %s not granted", pkgPath))
+		}
+	}
+	if !spent.IsZero() {
+		if !g.SpendLimit.IsAllGTE(spent) {
+			return g, std.ErrInsufficientCoins(fmt.Sprintf("spend limit %s of grant less than %s", g.SpendLimit, spent))
+		}
+		g.SpendLimit = g.SpendLimit.Sub(spent)
+	}
+	return g, nil
+}
+
+// Returns the coins sent by signer in msg.
+func msgSpent(msg std.Msg, signer crypto.Address) std.Coins {
+	switch msg := msg.(type) {
+	case bank.MsgSend:
+		if msg.FromAddress == signer {
+			return msg.Amount
+		}
+	case bank.MsgMultiSend:
+		spent := std.Coins{}
+		for _, in := range msg.Inputs {
+			if in.Address == signer {
+				spent = spent.Add(in.Coins)
+			}
+		}
+		return spent
+	case vm.MsgAddPackage:
+		return msg.Deposit
+	case vm.MsgUpgradePackage:
+		return msg.Deposit
+	case vm.MsgCall:
+		return msg.Send
+	case vm.MsgApprove:
+		// the spender may transfer it.
+		return msg.Amount
+	}
+	return nil
+}
+
+// Returns the path of the package of msg, if a vm msg.
+func msgPkgPath(msg std.Msg) (string, bool) {
+	switch msg := msg.(type) {
+	case vm.MsgAddPackage:
+		return msg.Package.Path, true
+	case vm.MsgUpgradePackage:
+		return msg.PkgPath, true
+	case vm.MsgCall:
+		return msg.PkgPath, true
+	case vm.MsgPausePackage:
+		return msg.PkgPath, true
+	}
+	return "", false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
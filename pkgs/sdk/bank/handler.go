@@ -12,16 +12,37 @@ import (
 )
 
 type bankHandler struct {
-	bank BankKeeper
+	bank    BankKeeper
+	queries sdk.QueryRouter
 }
 
 // NewHandler returns a handler for "bank" type messages.
 func NewHandler(bank BankKeeper) bankHandler {
-	return bankHandler{
+	bh := bankHandler{
+		bank:    bank,
+		queries: sdk.NewQueryRouter(),
+	}
+	bh.registerQueries(bh.queries)
+	return bh
+}
+
+type bankModule struct {
+	bank BankKeeper
+}
+
+// NewModule returns the module of "bank" type messages and queries.
+func NewModule(bank BankKeeper) sdk.Module {
+	return bankModule{
 		bank: bank,
 	}
 }
 
+func (bm bankModule) Name() string { return ModuleName }
+
+func (bm bankModule) Handler() sdk.Handler { return NewHandler(bm.bank) }
+
+func (bm bankModule) RegisterQueries(qr sdk.QueryRouter) { NewHandler(bm.bank).registerQueries(qr) }
+
 func (bh bankHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 	switch msg := msg.(type) {
 	case MsgSend:
@@ -101,15 +122,13 @@ func (bh bankHandler) handleMsgMultiSend(ctx sdk.Context, msg MsgMultiSend) sdk.
 // query balance path
 const QueryBalance = "balances"
 
-func (bh bankHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
-	switch secondPart(req.Path) {
-	case QueryBalance:
-		return bh.queryBalance(ctx, req)
-	default:
-		res = sdk.ABCIResponseQueryFromError(
-			std.ErrUnknownRequest("unknown bank query endpoint"))
-		return
-	}
+// Adds the query routes of the bank module, e.g. "bank/balances", to qr.
+func (bh bankHandler) registerQueries(qr sdk.QueryRouter) {
+	qr.AddRoute(ModuleName+"/"+QueryBalance, bh.queryBalance)
+}
+
+func (bh bankHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return sdk.RouteQuery(bh.queries, ctx, req)
 }
 
 // queryBalance fetch an account's balance for the supplied height.
@@ -142,16 +161,6 @@ func abciResult(err error) sdk.Result {
 	return sdk.ABCIResultFromError(err)
 }
 
-// returns the second component of a path.
-func secondPart(path string) string {
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 {
-		return ""
-	} else {
-		return parts[1]
-	}
-}
-
 // returns the third component of a path.
 func thirdPart(path string) string {
 	parts := strings.Split(path, "/")
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gnolang/gno/pkgs/sdk"
 	"github.com/gnolang/gno/pkgs/sdk/auth"
+	"github.com/gnolang/gno/pkgs/std"
 )
 
 // RegisterInvariants registers the bank module invariants
@@ -13,6 +14,13 @@ func RegisterInvariants(ir sdk.InvariantRegistry, acck auth.AccountKeeper) {
 		NonnegativeBalanceInvariant(acck))
 }
 
+// RegisterSupplyInvariant registers the invariant of the total supply of
+// coins, for apps which neither mint nor burn coins.
+func RegisterSupplyInvariant(ir sdk.InvariantRegistry, acck auth.AccountKeeper, supply std.Coins) {
+	ir.RegisterRoute(ModuleName, "total-supply",
+		TotalSupplyInvariant(acck, supply))
+}
+
 // NonnegativeBalanceInvariant checks that all accounts in the application have non-negative balances
 func NonnegativeBalanceInvariant(acck auth.AccountKeeper) sdk.Invariant {
 	return func(ctx sdk.Context) (string, bool) {
@@ -35,3 +43,24 @@ func NonnegativeBalanceInvariant(acck auth.AccountKeeper) sdk.Invariant {
 			fmt.Sprintf("amount of negative accounts found %d\n%s", count, msg)), broken
 	}
 }
+
+// TotalSupplyInvariant checks that the coins of all accounts add up to the
+// supply, e.g. that of the genesis.
+func TotalSupplyInvariant(acck auth.AccountKeeper, supply std.Coins) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		total := std.Coins{}
+		acck.IterateAccounts(ctx, func(acc std.Account) bool {
+			total = total.Add(acc.GetCoins())
+			return false
+		})
+		broken := false
+		for _, coin := range total.Add(supply) {
+			if total.AmountOf(coin.Denom) != supply.AmountOf(coin.Denom) {
+				broken = true
+			}
+		}
+
+		return sdk.FormatInvariant(ModuleName, "total-supply",
+			fmt.Sprintf("\tsum of balances:  %v\n\texpected supply:  %v\n", total, supply)), broken
+	}
+}
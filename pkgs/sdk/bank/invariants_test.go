@@ -0,0 +1,40 @@
+package bank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+func TestTotalSupplyInvariant(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	env.acck.SetAccount(ctx, env.acck.NewAccountWithAddress(ctx, addr))
+	env.bank.SetCoins(ctx, addr, std.NewCoins(std.NewCoin("foocoin", 10), std.NewCoin("barcoin", 5)))
+
+	invar := TotalSupplyInvariant(env.acck, std.NewCoins(std.NewCoin("foocoin", 10), std.NewCoin("barcoin", 5)))
+	_, broken := invar(ctx)
+	require.False(t, broken)
+
+	// sends conserve the supply.
+	require.NoError(t, env.bank.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 4))))
+	_, broken = invar(ctx)
+	require.False(t, broken)
+
+	// coins are minted, or of another denomination.
+	_, err := env.bank.AddCoins(ctx, addr2, std.NewCoins(std.NewCoin("foocoin", 1)))
+	require.NoError(t, err)
+	msg, broken := invar(ctx)
+	require.True(t, broken)
+	require.Contains(t, msg, "11foocoin")
+
+	invar = TotalSupplyInvariant(env.acck, std.NewCoins(std.NewCoin("foocoin", 11)))
+	_, broken = invar(ctx)
+	require.True(t, broken)
+}
@@ -13,4 +13,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	NoOutputsError{}, "NoOutputsError",
 	InputOutputMismatchError{}, "InputOutputMismatchError",
 	MsgSend{}, "MsgSend",
+	MsgMultiSend{}, "MsgMultiSend",
+	Input{}, "Input",
+	Output{}, "Output",
 ))
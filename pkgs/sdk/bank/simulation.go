@@ -0,0 +1,26 @@
+package bank
+
+import (
+	"math/rand"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/simulation"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// SimulateMsgSend returns an operation which sends a random amount of a
+// random denomination of the coins of a signer, beyond the fee, to a
+// random account.
+func SimulateMsgSend(bank ViewKeeperI) simulation.Operation {
+	return func(r *rand.Rand, ctx sdk.Context, env *simulation.Env) (simulation.OperationMsg, error) {
+		from := simulation.RandomAccount(r, env.Signers)
+		to := simulation.RandomAccount(r, env.Accounts)
+		spendable := env.Spendable(bank.GetCoins(ctx, from.Address))
+		if len(spendable) == 0 {
+			return simulation.NoOpMsg("bank/send: no coins to send"), nil
+		}
+		amount := std.Coins{simulation.RandomCoin(r, spendable)}
+		msg := NewMsgSend(from.Address, to.Address, amount)
+		return simulation.NewOperationMsg(msg, from), nil
+	}
+}
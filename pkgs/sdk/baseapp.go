@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"runtime/debug"
@@ -27,20 +28,27 @@ var (
 // BaseApp reflects the ABCI application implementation.
 type BaseApp struct {
 	// initialized on creation
-	logger log.Logger
-	name   string                 // application name from abci.Info
-	db     dbm.DB                 // common DB backend
-	cms    store.CommitMultiStore // Main (uncached) state
-	router Router                 // handle any kind of message
+	logger      log.Logger
+	name        string                 // application name from abci.Info
+	db          dbm.DB                 // common DB backend
+	cms         store.CommitMultiStore // Main (uncached) state
+	router      Router                 // handle any kind of message
+	queryRouter QueryRouter            // answer queries by path
 
 	// set upon LoadVersion or LoadLatestVersion.
 	baseKey store.StoreKey // Base Store in cms (raw db, not hashed)
 	mainKey store.StoreKey // Main Store in cms (e.g. iavl, merkle-ized)
 
-	anteHandler  AnteHandler  // ante handler for fee and auth
-	initChainer  InitChainer  // initialize state with validators and state blob
-	beginBlocker BeginBlocker // logic to run before any txs
-	endBlocker   EndBlocker   // logic to run after all txs, and to determine valset changes
+	storeKeys []store.StoreKey // of the stores mounted in cms
+
+	anteHandler    AnteHandler    // ante handler for fee and auth
+	gasRefunder    GasRefunder    // refunds unused gas after deliver tx
+	txHasher       TxHasher       // canonical hashes of the txs
+	systemTxFilter SystemTxFilter // marks the system txs in check tx
+	initChainer    InitChainer    // initialize state with validators and state blob
+	beginBlocker   BeginBlocker   // logic to run before any txs
+	endBlocker     EndBlocker     // logic to run after all txs, and to determine valset changes
+	moduleManager  *ModuleManager // modules initialized and exported at genesis
 
 	// --------------------
 	// Volatile state
@@ -70,6 +78,19 @@ type BaseApp struct {
 
 	// application's version string
 	appVersion string
+
+	// canonical hashes of the txs delivered successfully in the last
+	// recentTxWindow blocks, with their heights, to reject duplicates in
+	// CheckTx.
+	recentTxWindow int64
+	recentTxs      map[string]int64
+
+	// invariants registered by modules, checked at the end of the blocks
+	// of heights multiple of invCheckPeriod, if positive.  A broken
+	// invariant halts the chain if haltOnBrokenInvariant.
+	invariants            []invariantRoute
+	invCheckPeriod        int64
+	haltOnBrokenInvariant bool
 }
 
 var _ abci.Application = (*BaseApp)(nil)
@@ -83,14 +104,16 @@ func NewBaseApp(
 	name string, logger log.Logger, db dbm.DB, baseKey store.StoreKey, mainKey store.StoreKey, options ...func(*BaseApp),
 ) *BaseApp {
 	app := &BaseApp{
-		logger:  logger,
-		name:    name,
-		db:      db,
-		cms:     store.NewCommitMultiStore(db),
-		router:  NewRouter(),
-		baseKey: baseKey,
-		mainKey: mainKey,
+		logger:      logger,
+		name:        name,
+		db:          db,
+		cms:         store.NewCommitMultiStore(db),
+		router:      NewRouter(),
+		queryRouter: NewQueryRouter(),
+		baseKey:     baseKey,
+		mainKey:     mainKey,
 	}
+	app.registerQueries(app.queryRouter)
 	for _, option := range options {
 		option(app)
 	}
@@ -117,12 +140,14 @@ func (app *BaseApp) Logger() log.Logger {
 // multistore, using a specified DB.
 func (app *BaseApp) MountStoreWithDB(key store.StoreKey, cons store.CommitStoreConstructor, db dbm.DB) {
 	app.cms.MountStoreWithDB(key, cons, db)
+	app.storeKeys = append(app.storeKeys, key)
 }
 
 // MountStore mounts a store to the provided key in the BaseApp multistore,
 // using the default DB.
 func (app *BaseApp) MountStore(key store.StoreKey, cons store.CommitStoreConstructor) {
 	app.cms.MountStoreWithDB(key, cons, nil)
+	app.storeKeys = append(app.storeKeys, key)
 }
 
 // LoadLatestVersion loads the latest application version. It will panic if
@@ -157,6 +182,124 @@ func (app *BaseApp) LastBlockHeight() int64 {
 	return app.cms.LastCommitID().Version
 }
 
+// DiffStores returns the differences between the state of each store of app
+// and that of the store of the same name of other, both at version, keyed by
+// store name.  Stores without differences are omitted, and if limit > 0, at
+// most limit differences are returned per store.
+//
+// NOTE: stores which are not versioned (e.g. the base store) are compared at
+// their latest state, whatever the version.
+func (app *BaseApp) DiffStores(other *BaseApp, version int64, limit int) (map[string][]store.KVDiff, error) {
+	ms, err := app.cms.MultiImmutableCacheWrapWithVersion(version)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("loading state at height %d", version))
+	}
+	oms, err := other.cms.MultiImmutableCacheWrapWithVersion(version)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("loading other state at height %d", version))
+	}
+	res := make(map[string][]store.KVDiff)
+	for _, key := range app.storeKeys {
+		var okey store.StoreKey
+		for _, k := range other.storeKeys {
+			if k.Name() == key.Name() {
+				okey = k
+			}
+		}
+		if okey == nil {
+			return nil, errors.New("store %s not mounted in other app", key.Name())
+		}
+		diffs := store.DiffStoreKVs(ms.GetStore(key), oms.GetStore(okey), limit)
+		if len(diffs) > 0 {
+			res[key.Name()] = diffs
+		}
+	}
+	return res, nil
+}
+
+// ExportStores returns the key/value pairs of the mounted stores at
+// version, by store name, except those set by the BaseApp itself, e.g. to
+// start a new chain from them with ImportStores.
+//
+// NOTE: unversioned stores, e.g. those of dbadapter, are exported at their
+// latest state.
+func (app *BaseApp) ExportStores(version int64) (map[string][]std.KVPair, error) {
+	ms, err := app.cms.MultiImmutableCacheWrapWithVersion(version)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("loading state at height %d", version))
+	}
+	res := make(map[string][]std.KVPair)
+	for _, key := range app.storeKeys {
+		kvs := []std.KVPair{}
+		itr := ms.GetStore(key).Iterator(nil, nil)
+		for ; itr.Valid(); itr.Next() {
+			if app.isBaseAppKey(key, itr.Key()) {
+				continue
+			}
+			kvs = append(kvs, std.KVPair{Key: itr.Key(), Value: itr.Value()})
+		}
+		itr.Close()
+		res[key.Name()] = kvs
+	}
+	return res, nil
+}
+
+// ImportStores sets the key/value pairs of stores, by store name, in the
+// stores of ctx, e.g. at genesis from the state exported by ExportStores.
+func (app *BaseApp) ImportStores(ctx Context, stores map[string][]std.KVPair) error {
+	for name, kvs := range stores {
+		var key store.StoreKey
+		for _, k := range app.storeKeys {
+			if k.Name() == name {
+				key = k
+			}
+		}
+		if key == nil {
+			return errors.New("store %s not mounted", name)
+		}
+		st := ctx.Store(key)
+		for _, kv := range kvs {
+			if app.isBaseAppKey(key, kv.Key) {
+				return errors.New("cannot import key %q of store %s, set by the app", kv.Key, name)
+			}
+			st.Set(kv.Key, kv.Value)
+		}
+	}
+	return nil
+}
+
+// ExportGenesis returns the states of the modules at version, by module
+// name, e.g. to start a new chain from them with InitGenesis.
+func (app *BaseApp) ExportGenesis(version int64) (map[string]interface{}, error) {
+	if app.moduleManager == nil {
+		return map[string]interface{}{}, nil
+	}
+	ms, err := app.cms.MultiImmutableCacheWrapWithVersion(version)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("loading state at height %d", version))
+	}
+	ctx := NewContext(RunTxModeCheck, ms, app.checkState.ctx.BlockHeader(), app.logger)
+	return app.moduleManager.ExportGenesis(ctx), nil
+}
+
+// InitGenesis initializes the modules with their states in genesis, by
+// module name, e.g. from the states exported by ExportGenesis.
+func (app *BaseApp) InitGenesis(ctx Context, genesis map[string]interface{}) error {
+	if app.moduleManager == nil {
+		if len(genesis) > 0 {
+			return errors.New("genesis state of modules without a module manager")
+		}
+		return nil
+	}
+	return app.moduleManager.InitGenesis(ctx, genesis)
+}
+
+// Returns true if key of the store of storeKey is set by the BaseApp.
+func (app *BaseApp) isBaseAppKey(storeKey store.StoreKey, key []byte) bool {
+	return (storeKey == app.mainKey && bytes.Equal(key, mainConsensusParamsKey)) ||
+		(storeKey == app.baseKey && bytes.Equal(key, mainLastHeaderKey))
+}
+
 // initializes the app from app.cms after loading.
 func (app *BaseApp) initFromMainStore() error {
 	baseStore := app.cms.GetStore(app.baseKey)
@@ -212,12 +355,58 @@ func (app *BaseApp) setHaltTime(haltTime uint64) {
 	app.haltTime = haltTime
 }
 
+func (app *BaseApp) setRecentTxWindow(blocks int64) {
+	app.recentTxWindow = blocks
+	app.recentTxs = make(map[string]int64)
+}
+
+func (app *BaseApp) setInvariantCheckPeriod(blocks int64) {
+	app.invCheckPeriod = blocks
+}
+
+func (app *BaseApp) setHaltOnBrokenInvariant(halt bool) {
+	app.haltOnBrokenInvariant = halt
+}
+
+// Returns true if a tx with the canonical hash txHash was delivered in the
+// recent tx window.
+func (app *BaseApp) isRecentTx(txHash []byte) bool {
+	if app.recentTxWindow <= 0 || txHash == nil {
+		return false
+	}
+	_, ok := app.recentTxs[string(txHash)]
+	return ok
+}
+
+// Records a tx delivered at height, if the recent tx window is set.
+func (app *BaseApp) addRecentTx(txHash []byte, height int64) {
+	if app.recentTxWindow <= 0 {
+		return
+	}
+	app.recentTxs[string(txHash)] = height
+}
+
+// Forgets the txs delivered before the recent tx window ending at height.
+func (app *BaseApp) pruneRecentTxs(height int64) {
+	for txHash, txHeight := range app.recentTxs {
+		if txHeight <= height-app.recentTxWindow {
+			delete(app.recentTxs, txHash)
+		}
+	}
+}
+
 // Returns a read-only (cache) MultiStore.
 // This may be used by keepers for initialization upon restart.
 func (app *BaseApp) GetCacheMultiStore() store.MultiStore {
 	return app.cms.MultiCacheWrap()
 }
 
+// NewCommittedContext returns a context on a cache of the last committed
+// state, e.g. to export the genesis state of modules.
+func (app *BaseApp) NewCommittedContext() Context {
+	return NewContext(RunTxModeCheck, app.cms.MultiCacheWrap(), app.checkState.ctx.BlockHeader(), app.logger)
+}
+
 // Router returns the router of the BaseApp.
 func (app *BaseApp) Router() Router {
 	if app.sealed {
@@ -228,6 +417,14 @@ func (app *BaseApp) Router() Router {
 	return app.router
 }
 
+// QueryRouter returns the query router of the BaseApp.
+func (app *BaseApp) QueryRouter() QueryRouter {
+	if app.sealed {
+		panic("QueryRouter() on sealed BaseApp")
+	}
+	return app.queryRouter
+}
+
 // Seal seals a BaseApp. It prohibits any further modifications to a BaseApp.
 func (app *BaseApp) Seal() { app.sealed = true }
 
@@ -240,8 +437,9 @@ func (app *BaseApp) IsSealed() bool { return app.sealed }
 func (app *BaseApp) setCheckState(header abci.Header) {
 	ms := app.cms.MultiCacheWrap()
 	app.checkState = &state{
-		ms:  ms,
-		ctx: NewContext(RunTxModeCheck, ms, header, app.logger).WithMinGasPrices(app.minGasPrices),
+		ms:       ms,
+		ctx:      NewContext(RunTxModeCheck, ms, header, app.logger).WithMinGasPrices(app.minGasPrices),
+		txHashes: make(map[string]struct{}),
 	}
 }
 
@@ -359,144 +557,141 @@ func (app *BaseApp) InitChain(req abci.RequestInitChain) (res abci.ResponseInitC
 	return
 }
 
-// Splits a string path using the delimiter '/'.
-// e.g. "this/is/funny" becomes []string{"this", "is", "funny"}
-func splitPath(requestPath string) (path []string) {
-	path = strings.Split(requestPath, "/")
-	// first element is empty string
-	if len(path) > 0 && path[0] == "" {
-		path = path[1:]
-	}
-	return path
+// Adds the query routes of the app itself, which start with ".", to qr:
+// ".app/simulate", ".app/gasaudit", ".app/version", and ".store", whose
+// path is that of the query of the multistore, e.g. ".store/main/key".
+func (app *BaseApp) registerQueries(qr QueryRouter) {
+	qr.AddRoute(".app/simulate", app.querySimulate)
+	qr.AddRoute(".app/gasaudit", app.queryGasAudit)
+	qr.AddRoute(".app/version", app.queryVersion)
+	qr.AddRoute(".store", app.queryStore)
 }
 
-// Query implements the ABCI interface. It delegates to CommitMultiStore if it
-// implements Queryable.
+// Query implements the ABCI interface.  The query is answered by the querier
+// of the route of its path, at its height, the latest if zero: except for
+// the routes of the app itself, the context of the querier is that of the
+// state committed at that height, among those kept by the pruning options of
+// the app.  Stores without versions, e.g. dbadapter stores, are those of the
+// latest height.
 func (app *BaseApp) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
-	path := splitPath(req.Path)
-	if len(path) == 0 {
-		msg := "no query path provided"
-		res.Error = ABCIError(std.ErrUnknownRequest(msg))
-		return
+	route, querier := app.queryRouter.Route(req.Path)
+	if querier == nil {
+		return ABCIResponseQueryFromError(std.ErrUnknownQueryPath(req.Path, app.queryRouter.Routes()))
 	}
 
-	switch path[0] {
-	// "/.app", "/.store" prefix for special application queries
-	case ".app":
-		return handleQueryApp(app, path, req)
-
-	case ".store":
-		return handleQueryStore(app, path, req)
-
-	// default router queries
-	default:
-		return handleQueryCustom(app, path, req)
+	// when a client did not provide a query height, manually inject the latest
+	latest := app.LastBlockHeight()
+	if req.Height == 0 {
+		req.Height = latest
 	}
 
-	msg := "unknown query path " + req.Path
-	res.Error = ABCIError(std.ErrUnknownRequest(msg))
-	return
-}
-
-func handleQueryApp(app *BaseApp, path []string, req abci.RequestQuery) (res abci.ResponseQuery) {
-	if len(path) >= 2 {
-		var result Result
-
-		switch path[1] {
-		case "simulate":
-			txBytes := req.Data
-			var tx Tx
-			err := amino.Unmarshal(txBytes, &tx)
-			if err != nil {
-				res.Error = ABCIError(std.ErrTxDecode(err.Error()))
-			} else {
-				result = app.Simulate(txBytes, tx)
-			}
-			res.Height = req.Height
-			res.Value = amino.MustMarshal(result)
-			return res
-		case "version":
-			res.Height = req.Height
-			res.Value = []byte(app.appVersion)
-			return res
-		default:
-			res.Error = ABCIError(std.ErrUnknownRequest(fmt.Sprintf("Unknown query: %s", path)))
-			return
-		}
-	} else {
-		res.Error = ABCIError(std.ErrUnknownRequest(fmt.Sprintf("Unknown query: %s", path)))
-		return
+	if req.Height < 0 || req.Height > latest {
+		return ABCIResponseQueryFromError(std.ErrInternal(fmt.Sprintf(
+			"invalid query height %d (latest height: %d)", req.Height, latest)))
 	}
-}
 
-func handleQueryStore(app *BaseApp, path []string, req abci.RequestQuery) (res abci.ResponseQuery) {
-	// "/store" prefix for store queries
-	queryable, ok := app.cms.(store.Queryable)
-	if !ok {
-		msg := "multistore doesn't support queries"
-		res.Error = ABCIError(std.ErrUnknownRequest(msg))
+	if req.Height <= 1 && req.Prove {
+		res.Error = ABCIError(std.ErrInternal("cannot query with proof when height <= 1; please provide a valid height"))
 		return
 	}
 
-	req.Path = "/" + strings.Join(path[1:], "/")
-
-	// when a client did not provide a query height, manually inject the latest
-	if req.Height == 0 {
-		req.Height = app.LastBlockHeight()
+	if !strings.HasPrefix(route, ".app/") {
+		pruning := app.cms.GetStoreOptions().PruningOptions
+		if latest > 0 && !pruning.IsKept(req.Height, latest) {
+			return ABCIResponseQueryFromError(std.ErrInternal(fmt.Sprintf(
+				"state at height %d is pruned; the last %d heights are kept (latest height: %d)",
+				req.Height, pruning.KeepRecent+1, latest)))
+		}
 	}
 
-	if req.Height <= 1 && req.Prove {
-		res.Error = ABCIError(std.ErrInternal("cannot query with proof when height <= 1; please provide a valid height"))
-		return
+	var ctx Context
+	if !strings.HasPrefix(route, ".") {
+		cacheMS, err := app.cms.MultiImmutableCacheWrapWithVersion(req.Height)
+		if err != nil {
+			res.Error = ABCIError(std.ErrInternal(
+				fmt.Sprintf(
+					"failed to load state at height %d; %s (latest height: %d)",
+					req.Height, err, latest,
+				),
+			))
+			return
+		}
+
+		// cache wrap the commit-multistore for safety
+		// XXX RunTxModeQuery?
+		ctx = NewContext(RunTxModeCheck, cacheMS, app.checkState.ctx.BlockHeader(), app.logger).WithMinGasPrices(app.minGasPrices)
 	}
 
-	resp := queryable.Query(req)
-	resp.Height = req.Height
-	return resp
+	res = querier(ctx, req)
+	res.Height = req.Height
+	return
 }
 
-func handleQueryCustom(app *BaseApp, path []string, req abci.RequestQuery) (res abci.ResponseQuery) {
-	if len(path) < 1 || path[0] == "" {
-		res.Error = ABCIError(std.ErrUnknownRequest("No route for custom query specified"))
-		return
+// Returns an error unless the query is of the latest height, that of the
+// check state of txs simulated.
+func (app *BaseApp) checkLatestHeight(req abci.RequestQuery) error {
+	if req.Height != app.LastBlockHeight() {
+		return std.ErrInternal(fmt.Sprintf(
+			"cannot simulate txs at height %d (latest height: %d)",
+			req.Height, app.LastBlockHeight()))
 	}
+	return nil
+}
 
-	handler := app.router.Route(path[0])
-	if handler == nil {
-		res.Error = ABCIError(std.ErrUnknownRequest(fmt.Sprintf("no custom handler found for route %s", path[0])))
+func (app *BaseApp) querySimulate(_ Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	if err := app.checkLatestHeight(req); err != nil {
+		res.Error = ABCIError(err)
 		return
 	}
-
-	// when a client did not provide a query height, manually inject the latest
-	if req.Height == 0 {
-		req.Height = app.LastBlockHeight()
+	var result Result
+	txBytes := req.Data
+	var tx Tx
+	err := amino.Unmarshal(txBytes, &tx)
+	if err != nil {
+		res.Error = ABCIError(std.ErrTxDecode(err.Error()))
+	} else {
+		result = app.Simulate(txBytes, tx)
 	}
+	res.Value = amino.MustMarshal(result)
+	return
+}
 
-	if req.Height <= 1 && req.Prove {
-		res.Error = ABCIError(std.ErrInternal("cannot query with proof when height <= 1; please provide a valid height"))
+func (app *BaseApp) queryGasAudit(_ Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	if err := app.checkLatestHeight(req); err != nil {
+		res.Error = ABCIError(err)
 		return
 	}
-
-	cacheMS, err := app.cms.MultiImmutableCacheWrapWithVersion(req.Height)
+	txBytes := req.Data
+	var tx Tx
+	err := amino.Unmarshal(txBytes, &tx)
 	if err != nil {
-		res.Error = ABCIError(std.ErrInternal(
-			fmt.Sprintf(
-				"failed to load state at height %d; %s (latest height: %d)",
-				req.Height, err, app.LastBlockHeight(),
-			),
-		))
-		return
+		res.Error = ABCIError(std.ErrTxDecode(err.Error()))
+	} else {
+		audit := app.SimulateWithGasAudit(txBytes, tx)
+		res.Value = amino.MustMarshalJSON(audit)
 	}
+	return
+}
 
-	// cache wrap the commit-multistore for safety
-	// XXX RunTxModeQuery?
-	ctx := NewContext(RunTxModeCheck, cacheMS, app.checkState.ctx.BlockHeader(), app.logger).WithMinGasPrices(app.minGasPrices)
-
-	// Passes the query to the handler.
-	res = handler.Query(ctx, req)
+func (app *BaseApp) queryVersion(_ Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	res.Value = []byte(app.appVersion)
 	return
 }
 
+// queryStore answers the query of the multistore at the height of req, e.g.
+// ".store/main/key" with the value of the key req.Data in the store "main".
+func (app *BaseApp) queryStore(_ Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	queryable, ok := app.cms.(store.Queryable)
+	if !ok {
+		msg := "multistore doesn't support queries"
+		res.Error = ABCIError(std.ErrUnknownRequest(msg))
+		return
+	}
+
+	req.Path = strings.TrimPrefix(strings.TrimPrefix(req.Path, "/"), ".store")
+	return queryable.Query(req)
+}
+
 func (app *BaseApp) validateHeight(req abci.RequestBeginBlock) error {
 	if req.Header.GetHeight() < 1 {
 		return fmt.Errorf("invalid height: %d", req.Header.GetHeight())
@@ -553,6 +748,10 @@ func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeg
 // whether or not a transaction can possibly be executed, first decoding and then
 // the ante handler (which checks signatures/fees/ValidateBasic).
 //
+// If a recent tx window is set, txs with the same canonical hash as a tx
+// delivered in the window are rejected, whatever their signatures.  So are
+// those with the hash of a tx checked since the last commit.
+//
 // NOTE:CheckTx does not run the actual Msg handler function(s).
 func (app *BaseApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx) {
 	var tx Tx
@@ -561,10 +760,28 @@ func (app *BaseApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx)
 		res.Error = ABCIError(std.ErrTxDecode(err.Error()))
 		return
 	} else {
+		res.TxHash = app.txHash(RunTxModeCheck, req.Tx, tx)
+		if app.isRecentTx(res.TxHash) {
+			res.Error = ABCIError(std.ErrDuplicateTx(fmt.Sprintf(
+				"tx %X was delivered in the last %d blocks", res.TxHash, app.recentTxWindow)))
+			return
+		}
+		// A tx with the hash of a tx already checked in the check state would
+		// pass, as it is signed at the next sequence, but be rejected by the
+		// mempool after changing the check state.
+		if _, ok := app.checkState.txHashes[string(res.TxHash)]; ok && res.TxHash != nil {
+			res.Error = ABCIError(std.ErrDuplicateTx(fmt.Sprintf(
+				"tx %X was already checked", res.TxHash)))
+			return
+		}
 		result := app.runTx(RunTxModeCheck, req.Tx, tx)
 		res.ResponseBase = result.ResponseBase
 		res.GasWanted = result.GasWanted
 		res.GasUsed = result.GasUsed
+		if result.IsOK() && res.TxHash != nil {
+			app.checkState.txHashes[string(res.TxHash)] = struct{}{}
+		}
+		res.System = app.systemTxFilter != nil && app.systemTxFilter(tx)
 		return
 	}
 }
@@ -577,14 +794,29 @@ func (app *BaseApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliv
 		res.Error = ABCIError(std.ErrTxDecode(err.Error()))
 		return
 	} else {
+		res.TxHash = app.txHash(RunTxModeDeliver, req.Tx, tx)
 		result := app.runTx(RunTxModeDeliver, req.Tx, tx)
 		res.ResponseBase = result.ResponseBase
 		res.GasWanted = result.GasWanted
 		res.GasUsed = result.GasUsed
+		res.EventMsgs = result.EventMsgs
+		if result.IsOK() && res.TxHash != nil {
+			app.addRecentTx(res.TxHash, app.deliverState.ctx.BlockHeight())
+		}
 		return
 	}
 }
 
+// Returns the canonical hash of tx on the chain of the context of mode, or
+// nil if there is no tx hasher.
+func (app *BaseApp) txHash(mode RunTxMode, txBytes []byte, tx Tx) []byte {
+	if app.txHasher == nil {
+		return nil
+	}
+	ctx, _ := app.getContextForTx(mode, txBytes).CacheContext()
+	return app.txHasher(ctx.WithGasMeter(store.NewInfiniteGasMeter()), tx)
+}
+
 // validateBasicTxMsgs executes basic validator calls for messages.
 func validateBasicTxMsgs(msgs []Msg) error {
 	if msgs == nil || len(msgs) == 0 {
@@ -624,6 +856,7 @@ func (app *BaseApp) runMsgs(ctx Context, msgs []Msg, mode RunTxMode) (result Res
 	data := make([]byte, 0, len(msgs))
 	err := error(nil)
 	events := []Event{}
+	var eventMsgs []abci.EventMsg
 
 	// NOTE: GasWanted is determined by ante handler and GasUsed by the GasMeter.
 	for i, msg := range msgs {
@@ -648,7 +881,13 @@ func (app *BaseApp) runMsgs(ctx Context, msgs []Msg, mode RunTxMode) (result Res
 		// each result.
 		data = append(data, msgResult.Data...)
 		events = append(events, msgResult.Events...)
-		// TODO append msgevent from ctx. XXX XXX
+		events = append(events, ctx.EventLogger().Events()...)
+		for len(eventMsgs) < len(events) {
+			eventMsgs = append(eventMsgs, abci.EventMsg{
+				MsgIndex: i,
+				MsgType:  msgRoute + "/" + msg.Type(),
+			})
+		}
 
 		// stop execution and return on first failed message
 		if !msgResult.IsOK() {
@@ -669,6 +908,7 @@ func (app *BaseApp) runMsgs(ctx Context, msgs []Msg, mode RunTxMode) (result Res
 	result.Log = strings.Join(msgLogs, "\n")
 	result.GasUsed = ctx.GasMeter().GasConsumed()
 	result.Events = events
+	result.EventMsgs = eventMsgs
 	return result
 }
 
@@ -698,6 +938,13 @@ func (app *BaseApp) cacheTxContext(ctx Context, txBytes []byte) (
 // further details on transaction execution, reference the BaseApp SDK
 // documentation.
 func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result) {
+	result, _ = app.runTxWithGasMeter(mode, txBytes, tx)
+	return result
+}
+
+// runTxWithGasMeter is like runTx, but also returns the gas meter of the
+// tx, e.g. to inspect the gas breakdown of a simulated tx.
+func (app *BaseApp) runTxWithGasMeter(mode RunTxMode, txBytes []byte, tx Tx) (result Result, gasMeter store.GasMeter) {
 	// NOTE: GasWanted should be returned by the AnteHandler. GasUsed is
 	// determined by the GasMeter. We need access to the context to get the gas
 	// meter so we initialize upfront.
@@ -716,7 +963,7 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 	// only run the tx if there is block gas remaining
 	if mode == RunTxModeDeliver && ctx.BlockGasMeter().IsOutOfGas() {
 		result.Error = ABCIError(std.ErrOutOfGas("no block gas left to run tx"))
-		return
+		return result, nil
 	}
 
 	var startingGas int64
@@ -738,6 +985,7 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 				result.Log = log
 				result.GasWanted = gasWanted
 				result.GasUsed = ctx.GasMeter().GasConsumed()
+				gasMeter = ctx.GasMeter()
 				return
 			default:
 				log := fmt.Sprintf("recovered: %v\nstack:\n%v", r, string(debug.Stack()))
@@ -745,12 +993,14 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 				result.Log = log
 				result.GasWanted = gasWanted
 				result.GasUsed = ctx.GasMeter().GasConsumed()
+				gasMeter = ctx.GasMeter()
 				return
 			}
 		}
 		// Whether AnteHandler panics or not.
 		result.GasWanted = gasWanted
 		result.GasUsed = ctx.GasMeter().GasConsumed()
+		gasMeter = ctx.GasMeter()
 	}()
 
 	// If BlockGasMeter() panics it will be caught by the above recover and will
@@ -808,7 +1058,7 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 			// NOTE: first we must set ctx above,
 			// because a previous defer call sets
 			// result.GasUsed, regardless of error.
-			return result
+			return result, nil
 		} else {
 			// Revert cache wrapping of multistore.
 			ctx = newCtx.WithMultiStore(ms)
@@ -825,15 +1075,21 @@ func (app *BaseApp) runTx(mode RunTxMode, txBytes []byte, tx Tx) (result Result)
 
 	// Safety check: don't write the cache state unless we're in DeliverTx.
 	if mode != RunTxModeDeliver {
-		return result
+		return result, nil
 	}
 
-	// only update state if all messages pass
-	if result.IsOK() {
-		msCache.MultiWrite()
+	// only update state if all messages pass, but refund unused gas
+	// regardless, within the cache written, so that a failed refund
+	// writes nothing.
+	if !result.IsOK() {
+		runMsgCtx, msCache = app.cacheTxContext(ctx, txBytes)
 	}
+	if app.gasRefunder != nil {
+		app.gasRefunder(runMsgCtx, tx, ctx.GasMeter().GasConsumed())
+	}
+	msCache.MultiWrite()
 
-	return result
+	return result, nil
 }
 
 // EndBlock implements the ABCI interface.
@@ -842,6 +1098,20 @@ func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBloc
 		res = app.endBlocker(app.deliverState.ctx, req)
 	}
 
+	if app.invCheckPeriod > 0 && req.Height%app.invCheckPeriod == 0 {
+		app.checkInvariants(app.deliverState.ctx, req.Height)
+	}
+
+	// Like consensus, the updated consensus params apply from the next block.
+	if res.ConsensusParams != nil {
+		params := *res.ConsensusParams
+		if app.consensusParams != nil {
+			params = app.consensusParams.Update(params)
+		}
+		app.setConsensusParams(&params)
+		app.storeConsensusParams(&params)
+	}
+
 	return
 }
 
@@ -889,6 +1159,8 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	headerBz := amino.MustMarshal(header)
 	baseStore.Set(mainLastHeaderKey, headerBz)
 
+	app.pruneRecentTxs(header.GetHeight())
+
 	// Reset the Check state to the latest committed.
 	//
 	// NOTE: This is safe because Tendermint holds a lock on the mempool for
@@ -936,6 +1208,8 @@ func (app *BaseApp) Close() error {
 type state struct {
 	ms  store.MultiStore
 	ctx Context
+
+	txHashes map[string]struct{} // canonical hashes of the txs checked in it
 }
 
 func (st *state) MultiCacheWrap() store.MultiStore {
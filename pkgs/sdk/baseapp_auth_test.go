@@ -0,0 +1,95 @@
+package sdk_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/log"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/auth"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	tu "github.com/gnolang/gno/pkgs/sdk/testutils"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/dbadapter"
+	"github.com/gnolang/gno/pkgs/store/iavl"
+)
+
+// Test that CheckTx rejects the replays of a tx delivered in the recent tx
+// window with the canonical hasher of auth, even signed again at the
+// following sequence of the signer.
+func TestCheckTxRecentTxWindowAuth(t *testing.T) {
+	chainID := "test-chain"
+	db := dbm.NewMemDB()
+	mainKey := store.NewStoreKey("main")
+	baseKey := store.NewStoreKey("base")
+	app := sdk.NewBaseApp(t.Name(), log.NewNopLogger(), db, baseKey, mainKey, sdk.SetRecentTxWindow(2))
+	app.MountStoreWithDB(mainKey, iavl.StoreConstructor, db)
+	app.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+	acck := auth.NewAccountKeeper(mainKey, std.ProtoBaseAccount)
+	bankk := bank.NewBankKeeper(acck)
+
+	priv, _, addr := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+	app.SetInitChainer(func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+		acc := acck.NewAccountWithAddress(ctx, addr)
+		acc.SetCoins(tu.NewTestCoins())
+		acck.SetAccount(ctx, acc)
+		return abci.ResponseInitChain{}
+	})
+	anteHandler := auth.NewAnteHandler(acck, bankk, auth.DefaultSigVerificationGasConsumer, auth.AnteOptions{})
+	app.SetAnteHandler(func(ctx sdk.Context, tx std.Tx, simulate bool) (sdk.Context, sdk.Result, bool) {
+		ctx = ctx.WithValue(auth.AuthParamsContextKey{}, auth.DefaultParams())
+		return anteHandler(ctx, tx, simulate)
+	})
+	app.SetTxHasher(auth.NewTxHasher())
+	app.Router().AddRoute(bank.ModuleName, bank.NewHandler(bankk))
+	require.NoError(t, app.LoadLatestVersion())
+
+	app.InitChain(abci.RequestInitChain{
+		ChainID: chainID,
+		ConsensusParams: &abci.ConsensusParams{
+			Block: &abci.BlockParams{MaxTxBytes: 10000, MaxDataBytes: 100000, MaxBlockBytes: 100000, MaxGas: -1, TimeIotaMS: 10},
+		},
+	})
+	newTx := func(seq uint64, memo string) std.Tx {
+		msgs := []std.Msg{bank.NewMsgSend(addr, addr2, std.Coins{std.NewCoin("atom", 10)})}
+		return tu.NewTestTxWithMemo(chainID, msgs, []crypto.PrivKey{priv}, []uint64{0}, []uint64{seq}, tu.NewTestFee(), memo)
+	}
+
+	tx := newTx(0, "")
+	txBytes := amino.MustMarshal(tx)
+	app.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: chainID, Height: 1}})
+	dres := app.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	require.True(t, dres.IsOK(), fmt.Sprintf("%v", dres))
+	require.Equal(t, tx.Hash(chainID), dres.TxHash)
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+
+	// the same bytes, after the sequence of the signer moved.
+	cres := app.CheckTx(abci.RequestCheckTx{Tx: txBytes})
+	require.Equal(t, dres.TxHash, cres.TxHash)
+	require.IsType(t, std.DuplicateTxError{}, cres.Error)
+
+	// the same tx signed again at the following sequence.
+	tx2Bytes := amino.MustMarshal(newTx(1, ""))
+	require.NotEqual(t, txBytes, tx2Bytes)
+	cres = app.CheckTx(abci.RequestCheckTx{Tx: tx2Bytes})
+	require.Equal(t, dres.TxHash, cres.TxHash)
+	require.IsType(t, std.DuplicateTxError{}, cres.Error)
+
+	// another memo makes another tx, which cannot be checked twice either.
+	tx3Bytes := amino.MustMarshal(newTx(1, "again"))
+	cres = app.CheckTx(abci.RequestCheckTx{Tx: tx3Bytes})
+	require.True(t, cres.IsOK(), fmt.Sprintf("%v", cres))
+	require.NotEqual(t, dres.TxHash, cres.TxHash)
+	cres = app.CheckTx(abci.RequestCheckTx{Tx: tx3Bytes})
+	require.IsType(t, std.DuplicateTxError{}, cres.Error)
+}
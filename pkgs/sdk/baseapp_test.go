@@ -326,6 +326,44 @@ func TestInitChainer(t *testing.T) {
 	require.Equal(t, value, res.Value)
 }
 
+func TestExportImportStores(t *testing.T) {
+	key, value := []byte("hello"), []byte("goodbye")
+	app := setupBaseApp(t, func(bap *BaseApp) {
+		bap.SetInitChainer(func(ctx Context, req abci.RequestInitChain) abci.ResponseInitChain {
+			ctx.Store(mainKey).Set(key, value)
+			return abci.ResponseInitChain{}
+		})
+	})
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain", ConsensusParams: &abci.ConsensusParams{Block: &abci.BlockParams{MaxTxBytes: 1}}})
+	app.Commit()
+
+	// the keys of the BaseApp are not exported.
+	stores, err := app.ExportStores(1)
+	require.NoError(t, err)
+	require.Equal(t, []std.KVPair{{Key: key, Value: value}}, stores["main"])
+	require.Empty(t, stores["base"])
+	_, err = app.ExportStores(2)
+	require.Error(t, err)
+
+	var app2 *BaseApp
+	app2 = setupBaseApp(t, func(bap *BaseApp) {
+		bap.SetInitChainer(func(ctx Context, req abci.RequestInitChain) abci.ResponseInitChain {
+			require.NoError(t, app2.ImportStores(ctx, stores))
+			return abci.ResponseInitChain{}
+		})
+	})
+	app2.InitChain(abci.RequestInitChain{ChainID: "test-chain-2"})
+	app2.Commit()
+	res := app2.Query(abci.RequestQuery{Path: ".store/main/key", Data: key})
+	require.Equal(t, value, res.Value)
+
+	ctx := app2.NewCommittedContext()
+	require.Error(t, app2.ImportStores(ctx, map[string][]std.KVPair{"foo": nil}))
+	require.Error(t, app2.ImportStores(ctx, map[string][]std.KVPair{
+		"main": {{Key: mainConsensusParamsKey, Value: value}},
+	}))
+}
+
 type testTxData struct {
 	FailOnAnte bool
 	Counter    int64
@@ -554,6 +592,87 @@ func TestDeliverTx(t *testing.T) {
 	}
 }
 
+// Test that CheckTx rejects copies of txs delivered in the recent tx window,
+// even with other signatures.
+func TestCheckTxRecentTxWindow(t *testing.T) {
+	anteOpt := func(bapp *BaseApp) {
+		bapp.SetAnteHandler(func(ctx Context, tx Tx, simulate bool) (Context, Result, bool) {
+			return ctx, Result{}, false
+		})
+	}
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result { return Result{} }))
+	}
+	hasherOpt := func(bapp *BaseApp) {
+		bapp.SetTxHasher(func(ctx Context, tx Tx) []byte {
+			return tx.Hash(ctx.ChainID())
+		})
+	}
+	app := setupBaseApp(t, anteOpt, routerOpt, hasherOpt, SetRecentTxWindow(2))
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	tx := newTxCounter(0, 0)
+	txBytes := amino.MustMarshal(tx)
+	header := &bft.Header{ChainID: "test-chain", Height: 1}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	dres := app.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	require.True(t, dres.IsOK(), fmt.Sprintf("%v", dres))
+	require.Equal(t, tx.Hash("test-chain"), dres.TxHash)
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+
+	// a copy with another signature has the same canonical hash.
+	tx2 := newTxCounter(0, 0)
+	tx2.Signatures = []std.Signature{{Signature: []byte("malleated")}}
+	tx2Bytes := amino.MustMarshal(tx2)
+	require.NotEqual(t, txBytes, tx2Bytes)
+	cres := app.CheckTx(abci.RequestCheckTx{Tx: tx2Bytes})
+	require.Equal(t, tx.Hash("test-chain"), cres.TxHash)
+	require.IsType(t, std.DuplicateTxError{}, cres.Error)
+
+	// other txs are accepted.
+	cres = app.CheckTx(abci.RequestCheckTx{Tx: amino.MustMarshal(newTxCounter(1, 0))})
+	require.True(t, cres.IsOK(), fmt.Sprintf("%v", cres))
+
+	// the tx is accepted again after the window.
+	for height := int64(2); height <= 3; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.EndBlock(abci.RequestEndBlock{})
+		app.Commit()
+	}
+	cres = app.CheckTx(abci.RequestCheckTx{Tx: tx2Bytes})
+	require.True(t, cres.IsOK(), fmt.Sprintf("%v", cres))
+}
+
+// Test that CheckTx marks the txs of the system tx filter.
+func TestCheckTxSystemTxFilter(t *testing.T) {
+	anteOpt := func(bapp *BaseApp) {
+		bapp.SetAnteHandler(func(ctx Context, tx Tx, simulate bool) (Context, Result, bool) {
+			return ctx, Result{}, false
+		})
+	}
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result { return Result{} }))
+	}
+	filterOpt := func(bapp *BaseApp) {
+		bapp.SetSystemTxFilter(func(tx Tx) bool { return tx.Memo == "oracle" })
+	}
+	app := setupBaseApp(t, anteOpt, routerOpt, filterOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	tx := newTxCounter(0, 0)
+	cres := app.CheckTx(abci.RequestCheckTx{Tx: amino.MustMarshal(tx)})
+	require.True(t, cres.IsOK(), fmt.Sprintf("%v", cres))
+	require.False(t, cres.System)
+
+	tx = newTxCounter(1, 1)
+	tx.Memo = "oracle"
+	cres = app.CheckTx(abci.RequestCheckTx{Tx: amino.MustMarshal(tx)})
+	require.True(t, cres.IsOK(), fmt.Sprintf("%v", cres))
+	require.True(t, cres.System)
+}
+
 // Number of messages doesn't matter to CheckTx.
 func TestMultiMsgCheckTx(t *testing.T) {
 	// TODO: ensure we get the same results
@@ -621,6 +740,41 @@ func TestMultiMsgDeliverTx(t *testing.T) {
 	require.Equal(t, int64(2), msgCounter2)
 }
 
+func TestDeliverTxEventMsgs(t *testing.T) {
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result {
+			ctx.EventLogger().EmitEvent(abci.EventString("logged"))
+			return Result{}
+		}))
+		bapp.Router().AddRoute(routeMsgCounter2, newTestHandler(func(ctx Context, msg Msg) (res Result) {
+			res.Events = []abci.Event{abci.EventString("a"), abci.EventString("b")}
+			return
+		}))
+	}
+	app := setupBaseApp(t, routerOpt)
+
+	header := &bft.Header{ChainID: "test-chain", Height: 1}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+	tx := newTxCounter(0, 0)
+	tx.Msgs = append(tx.Msgs, msgCounter2{1}, msgCounter{Counter: 2})
+	txBytes, err := amino.Marshal(tx)
+	require.NoError(t, err)
+	res := app.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	require.True(t, res.IsOK(), fmt.Sprintf("%v", res))
+
+	type1 := routeMsgCounter + "/" + tx.Msgs[0].Type()
+	type2 := routeMsgCounter2 + "/" + tx.Msgs[1].Type()
+	require.Equal(t, []abci.Event{
+		abci.EventString("logged"), abci.EventString("a"), abci.EventString("b"), abci.EventString("logged"),
+	}, res.Events)
+	require.Equal(t, []abci.EventMsg{
+		{MsgIndex: 0, MsgType: type1},
+		{MsgIndex: 1, MsgType: type2},
+		{MsgIndex: 1, MsgType: type2},
+		{MsgIndex: 2, MsgType: type1},
+	}, res.EventMsgs)
+}
+
 // Interleave calls to Check and Deliver and ensure
 // that there is no cross-talk. Check sees results of the previous Check calls
 // and Deliver sees that of the previous Deliver calls, but they don't see eachother.
@@ -691,6 +845,57 @@ func TestSimulateTx(t *testing.T) {
 	}
 }
 
+// Simulate a transaction with an audit gas meter.
+// SimulateWithGasAudit() and Query(".app/gasaudit", txBytes) should give
+// the same breakdown.
+func TestSimulateTxWithGasAudit(t *testing.T) {
+	anteOpt := func(bapp *BaseApp) {
+		bapp.SetAnteHandler(func(ctx Context, tx Tx, simulate bool) (newCtx Context, res Result, abort bool) {
+			newCtx = ctx.WithGasMeter(store.NewAuditGasMeter(store.NewInfiniteGasMeter()))
+			newCtx.GasMeter().ConsumeGas(3, "ante")
+			return
+		})
+	}
+
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result {
+			ctx.GasMeter().ConsumeGas(5, "test")
+			ctx.GasMeter().ConsumeGas(5, "test")
+			return Result{}
+		}))
+	}
+
+	app := setupBaseApp(t, anteOpt, routerOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+	header := &bft.Header{ChainID: "test-chain", Height: 1}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+
+	tx := newTxCounter(1, 1)
+	txBytes, err := amino.Marshal(tx)
+	require.Nil(t, err)
+
+	expected := []store.GasAuditEntry{
+		{Descriptor: "ante", Count: 1, Gas: 3},
+		{Descriptor: "test", Count: 2, Gas: 10},
+	}
+	audit := app.SimulateWithGasAudit(txBytes, tx)
+	require.True(t, audit.Result.IsOK(), audit.Result.Log)
+	require.Equal(t, int64(13), audit.Result.GasUsed)
+	require.Equal(t, expected, audit.Entries)
+
+	query := abci.RequestQuery{
+		Path: ".app/gasaudit",
+		Data: txBytes,
+	}
+	queryResult := app.Query(query)
+	require.True(t, queryResult.IsOK(), queryResult.Log)
+
+	var res GasAudit
+	amino.MustUnmarshalJSON(queryResult.Value, &res)
+	require.True(t, res.Result.IsOK(), res.Result.Log)
+	require.Equal(t, expected, res.Entries)
+}
+
 func TestRunInvalidTransaction(t *testing.T) {
 	anteOpt := func(bapp *BaseApp) {
 		bapp.SetAnteHandler(func(ctx Context, tx Tx, simulate bool) (newCtx Context, res Result, abort bool) {
@@ -998,6 +1203,59 @@ func TestBaseAppAnteHandler(t *testing.T) {
 	app.Commit()
 }
 
+func TestBaseAppGasRefunder(t *testing.T) {
+	deliverKey := []byte("deliver-key")
+	refundKey := []byte("refund-key")
+	failRefund := false
+	refundOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newMsgCounterHandler(t, mainKey, deliverKey))
+		bapp.SetGasRefunder(func(ctx Context, tx Tx, gasUsed int64) {
+			store := ctx.Store(mainKey)
+			setIntOnStore(store, refundKey, getIntFromStore(store, refundKey)+1)
+			if failRefund {
+				panic("refund failure")
+			}
+		})
+	}
+
+	app := setupBaseApp(t, refundOpt)
+
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	header := &bft.Header{ChainID: "test-chain", Height: app.LastBlockHeight() + 1}
+	app.BeginBlock(abci.RequestBeginBlock{Header: header})
+
+	deliver := func(tx Tx) abci.ResponseDeliverTx {
+		txBytes, err := amino.Marshal(tx)
+		require.NoError(t, err)
+		return app.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	}
+	checkStore := func(deliverCount, refundCount int64) {
+		store := app.getState(RunTxModeDeliver).ctx.Store(mainKey)
+		require.Equal(t, deliverCount, getIntFromStore(store, deliverKey))
+		require.Equal(t, refundCount, getIntFromStore(store, refundKey))
+	}
+
+	// gas is refunded whether or not the messages succeed.
+	res := deliver(newTxCounter(0, 0))
+	require.True(t, res.IsOK(), fmt.Sprintf("%v", res))
+	checkStore(1, 1)
+	tx := newTxCounter(1, 1)
+	setFailOnHandler(&tx, true)
+	res = deliver(tx)
+	require.False(t, res.IsOK(), fmt.Sprintf("%v", res))
+	checkStore(1, 2)
+
+	// a failed refund writes nothing, not even the state of the messages.
+	failRefund = true
+	res = deliver(newTxCounter(2, 1))
+	require.False(t, res.IsOK(), fmt.Sprintf("%v", res))
+	checkStore(1, 2)
+
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+}
+
 func TestGasConsumptionBadTx(t *testing.T) {
 	gasWanted := int64(5)
 	anteOpt := func(bapp *BaseApp) {
@@ -1060,6 +1318,46 @@ func TestGasConsumptionBadTx(t *testing.T) {
 	require.False(t, res.IsOK(), fmt.Sprintf("%v", res))
 }
 
+func TestEndBlockConsensusParams(t *testing.T) {
+	db := dbm.NewMemDB()
+	endBlocker := func(bapp *BaseApp) {
+		bapp.SetEndBlocker(func(ctx Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+			if req.Height != 1 {
+				return abci.ResponseEndBlock{}
+			}
+			return abci.ResponseEndBlock{ConsensusParams: &abci.ConsensusParams{
+				Block: &abci.BlockParams{MaxGas: 100},
+			}}
+		})
+	}
+	app := newBaseApp(t.Name(), db, endBlocker)
+	require.NoError(t, app.LoadLatestVersion())
+	app.InitChain(abci.RequestInitChain{
+		ChainID: "test-chain",
+		ConsensusParams: &abci.ConsensusParams{
+			Block:     &abci.BlockParams{MaxGas: 9},
+			Validator: &abci.ValidatorParams{PubKeyTypeURLs: []string{"/tm.PubKeyEd25519"}},
+		},
+	})
+
+	for height := int64(1); height <= 2; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		res := app.EndBlock(abci.RequestEndBlock{Height: height})
+		app.Commit()
+		assert.Equal(t, height == 1, res.ConsensusParams != nil)
+		// the updated params and the others.
+		assert.Equal(t, int64(100), app.consensusParams.Block.MaxGas)
+		assert.Equal(t, []string{"/tm.PubKeyEd25519"}, app.consensusParams.Validator.PubKeyTypeURLs)
+	}
+
+	// loaded from the main store.
+	app = newBaseApp(t.Name(), db, endBlocker)
+	require.NoError(t, app.LoadLatestVersion())
+	assert.Equal(t, int64(100), app.consensusParams.Block.MaxGas)
+	assert.Equal(t, int64(100), app.getMaximumBlockGas())
+}
+
 // Test that we can only query from the latest committed state.
 func TestQuery(t *testing.T) {
 	key, value := []byte("hello"), []byte("goodbye")
@@ -1133,3 +1431,135 @@ func TestGetMaximumBlockGas(t *testing.T) {
 	app.setConsensusParams(&abci.ConsensusParams{Block: &abci.BlockParams{MaxGas: -5000000}})
 	require.Panics(t, func() { app.getMaximumBlockGas() })
 }
+
+func TestInvariantCheckPeriod(t *testing.T) {
+	checked := []int64{}
+	invar := func(ctx Context) (string, bool) {
+		checked = append(checked, ctx.BlockHeight())
+		// changes are discarded.
+		ctx.Store(mainKey).Set([]byte("invariant"), []byte("checked"))
+		return "broken at 4", ctx.BlockHeight() == 4
+	}
+	app := setupBaseApp(t, SetInvariantCheckPeriod(2))
+	app.RegisterRoute("test", "height", invar)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	// broken invariants are only logged by default.
+	for height := int64(1); height <= 5; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.EndBlock(abci.RequestEndBlock{Height: height})
+		app.Commit()
+	}
+	require.Equal(t, []int64{2, 4}, checked)
+	require.Nil(t, app.NewCommittedContext().Store(mainKey).Get([]byte("invariant")))
+
+	broken := app.AssertInvariants(app.NewCommittedContext().WithBlockHeader(&bft.Header{Height: 4}))
+	require.Equal(t, []string{"test/height: broken at 4"}, broken)
+}
+
+func TestHaltOnBrokenInvariant(t *testing.T) {
+	app := setupBaseApp(t, SetInvariantCheckPeriod(1), SetHaltOnBrokenInvariant(true))
+	app.RegisterRoute("test", "height", func(ctx Context) (string, bool) {
+		return "broken at 2", ctx.BlockHeight() == 2
+	})
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	app.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "test-chain", Height: 1}})
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	// the block of the broken invariant is not committed.
+	app.BeginBlock(abci.RequestBeginBlock{Header: &bft.Header{ChainID: "test-chain", Height: 2}})
+	require.PanicsWithValue(t, fmt.Sprintf(
+		"1 broken invariant(s) at height 2, halting before its commit, after that of app hash %X:\ntest/height: broken at 2",
+		app.LastCommitID().Hash), func() {
+		app.EndBlock(abci.RequestEndBlock{Height: 2})
+	})
+	require.Equal(t, int64(1), app.LastBlockHeight())
+}
+
+// Test that queries are routed by path, at their height.
+func TestQueryHeight(t *testing.T) {
+	key := []byte("key")
+	queryOpt := func(bapp *BaseApp) {
+		bapp.QueryRouter().AddRoute("test/value", func(ctx Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+			res.Value = ctx.Store(mainKey).Get(key)
+			return
+		})
+	}
+	app := setupBaseApp(t, SetPruningOptions(store.PruneNothing), queryOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	// commit the value of key at heights 1 and 2.
+	for height := int64(1); height <= 2; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.deliverState.ctx.Store(mainKey).Set(key, []byte(fmt.Sprintf("value%d", height)))
+		app.EndBlock(abci.RequestEndBlock{})
+		app.Commit()
+	}
+
+	// queries are of the latest height by default.
+	res := app.Query(abci.RequestQuery{Path: "test/value"})
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, "value2", string(res.Value))
+	require.Equal(t, int64(2), res.Height)
+
+	// sub paths are answered by their route, at their height.
+	res = app.Query(abci.RequestQuery{Path: "/test/value/sub", Height: 1})
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, "value1", string(res.Value))
+	require.Equal(t, int64(1), res.Height)
+	res = app.Query(abci.RequestQuery{Path: ".store/main/key", Data: key, Height: 1})
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, "value1", string(res.Value))
+
+	// heights after the latest are invalid.
+	res = app.Query(abci.RequestQuery{Path: "test/value", Height: 3})
+	require.False(t, res.IsOK())
+	res = app.Query(abci.RequestQuery{Path: "test/value", Height: -1})
+	require.False(t, res.IsOK())
+
+	// txs are only simulated at the latest height.
+	res = app.Query(abci.RequestQuery{Path: ".app/simulate", Height: 1})
+	require.False(t, res.IsOK())
+
+	// unknown paths list the routes.
+	res = app.Query(abci.RequestQuery{Path: "test/unknown"})
+	require.False(t, res.IsOK())
+	uerr, ok := res.Error.(std.UnknownQueryPathError)
+	require.True(t, ok, "%#v", res.Error)
+	require.Equal(t, "test/unknown", uerr.Path)
+	require.Equal(t, []string{".app/gasaudit", ".app/simulate", ".app/version", ".store", "test/value"}, uerr.Routes)
+}
+
+// Test that queries of pruned heights fail, and those of kept heights
+// succeed.
+func TestQueryPrunedHeight(t *testing.T) {
+	key := []byte("key")
+	app := setupBaseApp(t, SetPruningOptions(store.NewPruningOptions(1, 0)))
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	for height := int64(1); height <= 3; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.deliverState.ctx.Store(mainKey).Set(key, []byte(fmt.Sprintf("value%d", height)))
+		app.EndBlock(abci.RequestEndBlock{})
+		app.Commit()
+	}
+
+	// the last two heights are kept.
+	for height := int64(2); height <= 3; height++ {
+		res := app.Query(abci.RequestQuery{Path: ".store/main/key", Data: key, Height: height})
+		require.True(t, res.IsOK(), res.Log)
+		require.Equal(t, fmt.Sprintf("value%d", height), string(res.Value))
+	}
+	res := app.Query(abci.RequestQuery{Path: ".store/main/key", Data: key, Height: 1})
+	require.False(t, res.IsOK())
+	require.Contains(t, res.Log, "pruned")
+
+	// the routes of the app are answered at any height.
+	res = app.Query(abci.RequestQuery{Path: ".app/version", Height: 1})
+	require.True(t, res.IsOK(), res.Log)
+}
@@ -5,10 +5,13 @@ import (
 	"regexp"
 
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/store"
 )
 
 var isAlphaNumeric = regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString
 
+var isQueryRoute = regexp.MustCompile(`^\.?[a-zA-Z0-9]+(/[a-zA-Z0-9]+)*$`).MatchString
+
 // nolint - Mostly for testing
 func (app *BaseApp) Check(tx Tx) (result Result) {
 	return app.runTx(RunTxModeCheck, nil, tx)
@@ -19,6 +22,17 @@ func (app *BaseApp) Simulate(txBytes []byte, tx Tx) (result Result) {
 	return app.runTx(RunTxModeSimulate, txBytes, tx)
 }
 
+// SimulateWithGasAudit is like Simulate, but also returns the gas breakdown
+// of the tx if the ante handler set an audit gas meter.
+func (app *BaseApp) SimulateWithGasAudit(txBytes []byte, tx Tx) (audit GasAudit) {
+	var gasMeter store.GasMeter
+	audit.Result, gasMeter = app.runTxWithGasMeter(RunTxModeSimulate, txBytes, tx)
+	if agm, ok := gasMeter.(*store.AuditGasMeter); ok {
+		audit.Entries = agm.Entries()
+	}
+	return audit
+}
+
 // nolint - full tx execution (commit)
 func (app *BaseApp) Deliver(tx Tx) (result Result) {
 	return app.runTx(RunTxModeDeliver, nil, tx)
@@ -0,0 +1,128 @@
+package ibc
+
+// DONTCOVER
+
+import (
+	"time"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+	"github.com/gnolang/gno/pkgs/crypto/merkle"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/log"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/iavl"
+)
+
+// testChain is a chain of a single validator, whose blocks are the
+// commits of its multistore.
+type testChain struct {
+	chainID string
+	ms      store.CommitMultiStore
+	ctx     sdk.Context
+	ibc     IBCKeeper
+	app     *testApp
+	priv    crypto.PrivKey
+	vals    *bft.ValidatorSet
+	height  int64  // of the next block
+	appHash []byte // of the last commit
+}
+
+func newTestChain(chainID string) *testChain {
+	db := dbm.NewMemDB()
+
+	mainCapKey := store.NewStoreKey("main")
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(mainCapKey, iavl.StoreConstructor, db)
+	ms.LoadLatestVersion()
+
+	priv := ed25519.GenPrivKey()
+	c := &testChain{
+		chainID: chainID,
+		ms:      ms,
+		ibc:     NewIBCKeeper(mainCapKey),
+		app:     &testApp{},
+		priv:    priv,
+		vals:    bft.NewValidatorSet([]*bft.Validator{bft.NewValidator(priv.PubKey(), 10)}),
+	}
+	c.ibc.BindPort("transfer", c.app)
+	c.commit()
+	return c
+}
+
+// Commits the state, and starts the next block.
+func (c *testChain) commit() {
+	cid := c.ms.Commit()
+	c.appHash = cid.Hash
+	c.height = cid.Version + 1
+	c.ctx = sdk.NewContext(sdk.RunTxModeDeliver, c.ms, &bft.Header{ChainID: c.chainID, Height: c.height}, log.NewNopLogger())
+}
+
+// Returns the trusted consensus state of the current block.
+func (c *testChain) consensusState() ConsensusState {
+	return ConsensusState{
+		Root:               c.appHash,
+		NextValidatorsHash: c.vals.Hash(),
+	}
+}
+
+// Returns the header of the current block, and its commit.
+func (c *testChain) signedHeader() (bft.Header, *bft.Commit) {
+	header := bft.Header{
+		ChainID:            c.chainID,
+		Height:             c.height,
+		Time:               time.Now().UTC(),
+		ValidatorsHash:     c.vals.Hash(),
+		NextValidatorsHash: c.vals.Hash(),
+		AppHash:            c.appHash,
+	}
+	blockID := bft.BlockID{Hash: header.Hash()}
+	vote := &bft.Vote{
+		Type:             bft.PrecommitType,
+		Height:           c.height,
+		BlockID:          blockID,
+		Timestamp:        header.Time,
+		ValidatorAddress: c.priv.PubKey().Address(),
+	}
+	vote.Signature, _ = c.priv.Sign(vote.SignBytes(c.chainID))
+	return header, bft.NewCommit(blockID, []*bft.CommitSig{vote.CommitSig()})
+}
+
+// Updates the client clientID of the counterparty cp to the current block,
+// whose height is that of the proofs of the last commit.
+func (c *testChain) updateClientOf(cp *testChain, clientID string) error {
+	header, commit := c.signedHeader()
+	return cp.ibc.UpdateClient(cp.ctx, clientID, header, commit, c.vals)
+}
+
+// Returns the proof of key in the last commit.
+func (c *testChain) prove(key []byte) *merkle.Proof {
+	res := c.ms.(store.Queryable).Query(abci.RequestQuery{
+		Path:   "/main/key",
+		Data:   key,
+		Height: c.height - 1,
+		Prove:  true,
+	})
+	return res.Proof
+}
+
+// testApp handles the packets of the port "transfer".
+type testApp struct {
+	received []string
+	acked    []string
+}
+
+func (app *testApp) OnRecvPacket(ctx sdk.Context, packet Packet) []byte {
+	app.received = append(app.received, string(packet.Data))
+	return []byte("ok:" + string(packet.Data))
+}
+
+func (app *testApp) OnAcknowledgementPacket(ctx sdk.Context, packet Packet, ack []byte) error {
+	app.acked = append(app.acked, string(ack))
+	return nil
+}
@@ -0,0 +1,105 @@
+package ibc
+
+import (
+	"fmt"
+)
+
+const (
+	// module name
+	ModuleName = "ibc"
+
+	// RouterKey is the name of the ibc module
+	RouterKey = ModuleName
+
+	// StoreKeyPrefix prefix for the keys of the ibc module, followed by
+	// the paths of ICS-24, e.g. "clients/<client id>/clientState".
+	StoreKeyPrefix = "/ibc/"
+
+	// ClientType is the type of the clients, i.e. light clients of
+	// tendermint chains, which prefixes their ids.
+	ClientType = "07-tendermint"
+)
+
+// The keys are the paths of ICS-24 under StoreKeyPrefix, so that a
+// counterparty chain can verify the proofs of the state of this chain, as
+// returned by the query "/.store/<store name>/key" of those keys, with
+// the merkle key path "/<store name>/<key>".
+
+// ClientStateKey returns the key of the client state of clientID.
+func ClientStateKey(clientID string) []byte {
+	return []byte(fmt.Sprintf("%sclients/%s/clientState", StoreKeyPrefix, clientID))
+}
+
+// ConsensusStateKey returns the key of the consensus state of clientID at
+// height.
+func ConsensusStateKey(clientID string, height int64) []byte {
+	return []byte(fmt.Sprintf("%sclients/%s/consensusStates/%d", StoreKeyPrefix, clientID, height))
+}
+
+// ConnectionKey returns the key of the connection end of connectionID.
+func ConnectionKey(connectionID string) []byte {
+	return []byte(fmt.Sprintf("%sconnections/%s", StoreKeyPrefix, connectionID))
+}
+
+// ChannelKey returns the key of the channel end of channelID on portID.
+func ChannelKey(portID, channelID string) []byte {
+	return []byte(fmt.Sprintf("%schannelEnds/ports/%s/channels/%s", StoreKeyPrefix, portID, channelID))
+}
+
+// NextSequenceSendKey returns the key of the sequence of the next packet
+// sent on the channel.
+func NextSequenceSendKey(portID, channelID string) []byte {
+	return []byte(fmt.Sprintf("%snextSequenceSend/ports/%s/channels/%s", StoreKeyPrefix, portID, channelID))
+}
+
+// NextSequenceRecvKey returns the key of the sequence of the next packet
+// received on the channel, if ordered.
+func NextSequenceRecvKey(portID, channelID string) []byte {
+	return []byte(fmt.Sprintf("%snextSequenceRecv/ports/%s/channels/%s", StoreKeyPrefix, portID, channelID))
+}
+
+// PacketCommitmentKeyPrefix returns the prefix of the keys of the
+// commitments of the packets sent on the channel.
+func PacketCommitmentKeyPrefix(portID, channelID string) []byte {
+	return []byte(fmt.Sprintf("%scommitments/ports/%s/channels/%s/sequences/", StoreKeyPrefix, portID, channelID))
+}
+
+// PacketCommitmentKey returns the key of the commitment of the packet of
+// sequence sent on the channel.
+func PacketCommitmentKey(portID, channelID string, sequence uint64) []byte {
+	return []byte(fmt.Sprintf("%s%d", PacketCommitmentKeyPrefix(portID, channelID), sequence))
+}
+
+// PacketReceiptKey returns the key of the receipt of the packet of
+// sequence received on the channel, if unordered.
+func PacketReceiptKey(portID, channelID string, sequence uint64) []byte {
+	return []byte(fmt.Sprintf("%sreceipts/ports/%s/channels/%s/sequences/%d", StoreKeyPrefix, portID, channelID, sequence))
+}
+
+// PacketAcknowledgementKey returns the key of the commitment of the
+// acknowledgement of the packet of sequence received on the channel.
+func PacketAcknowledgementKey(portID, channelID string, sequence uint64) []byte {
+	return []byte(fmt.Sprintf("%sacks/ports/%s/channels/%s/sequences/%d", StoreKeyPrefix, portID, channelID, sequence))
+}
+
+// Keys of the counters of the ids of clients, connections and channels.
+var (
+	nextClientSequenceKey     = []byte(StoreKeyPrefix + "nextClientSequence")
+	nextConnectionSequenceKey = []byte(StoreKeyPrefix + "nextConnectionSequence")
+	nextChannelSequenceKey    = []byte(StoreKeyPrefix + "nextChannelSequence")
+)
+
+// ClientID returns the id of the client of sequence.
+func ClientID(sequence uint64) string {
+	return fmt.Sprintf("%s-%d", ClientType, sequence)
+}
+
+// ConnectionID returns the id of the connection of sequence.
+func ConnectionID(sequence uint64) string {
+	return fmt.Sprintf("connection-%d", sequence)
+}
+
+// ChannelID returns the id of the channel of sequence.
+func ChannelID(sequence uint64) string {
+	return fmt.Sprintf("channel-%d", sequence)
+}
@@ -0,0 +1,165 @@
+package ibc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+type ibcHandler struct {
+	ibc     IBCKeeper
+	queries sdk.QueryRouter
+}
+
+// NewHandler returns a handler for "ibc" type messages.
+func NewHandler(ibc IBCKeeper) ibcHandler {
+	ih := ibcHandler{
+		ibc:     ibc,
+		queries: sdk.NewQueryRouter(),
+	}
+	ih.registerQueries(ih.queries)
+	return ih
+}
+
+type ibcModule struct {
+	ibc IBCKeeper
+}
+
+// NewModule returns the module of "ibc" type messages and queries.
+func NewModule(ibc IBCKeeper) sdk.Module {
+	return ibcModule{
+		ibc: ibc,
+	}
+}
+
+func (im ibcModule) Name() string { return ModuleName }
+
+func (im ibcModule) Handler() sdk.Handler { return NewHandler(im.ibc) }
+
+func (im ibcModule) RegisterQueries(qr sdk.QueryRouter) { NewHandler(im.ibc).registerQueries(qr) }
+
+func (ih ibcHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
+	switch msg := msg.(type) {
+	case MsgCreateClient:
+		clientID := ih.ibc.CreateClient(ctx, msg.ChainID, msg.Height, msg.ConsensusState)
+		return result(clientID, nil)
+	case MsgUpdateClient:
+		err := ih.ibc.UpdateClient(ctx, msg.ClientID, msg.Header, msg.Commit, msg.Validators)
+		return result("", err)
+	case MsgConnectionOpenInit:
+		return result(ih.ibc.ConnOpenInit(ctx, msg.ClientID, msg.Counterparty))
+	case MsgConnectionOpenTry:
+		return result(ih.ibc.ConnOpenTry(ctx, msg.ClientID, msg.Counterparty, msg.ProofInit, msg.ProofHeight))
+	case MsgConnectionOpenAck:
+		err := ih.ibc.ConnOpenAck(ctx, msg.ConnectionID, msg.CounterpartyConnectionID, msg.ProofTry, msg.ProofHeight)
+		return result("", err)
+	case MsgConnectionOpenConfirm:
+		err := ih.ibc.ConnOpenConfirm(ctx, msg.ConnectionID, msg.ProofAck, msg.ProofHeight)
+		return result("", err)
+	case MsgChannelOpenInit:
+		return result(ih.ibc.ChanOpenInit(ctx, msg.PortID, msg.Channel))
+	case MsgChannelOpenTry:
+		return result(ih.ibc.ChanOpenTry(ctx, msg.PortID, msg.Channel, msg.ProofInit, msg.ProofHeight))
+	case MsgChannelOpenAck:
+		err := ih.ibc.ChanOpenAck(ctx, msg.PortID, msg.ChannelID, msg.CounterpartyChannelID, msg.ProofTry, msg.ProofHeight)
+		return result("", err)
+	case MsgChannelOpenConfirm:
+		err := ih.ibc.ChanOpenConfirm(ctx, msg.PortID, msg.ChannelID, msg.ProofAck, msg.ProofHeight)
+		return result("", err)
+	case MsgRecvPacket:
+		err := ih.ibc.RecvPacket(ctx, msg.Packet, msg.ProofCommitment, msg.ProofHeight)
+		return result("", err)
+	case MsgAcknowledgement:
+		err := ih.ibc.AcknowledgePacket(ctx, msg.Packet, msg.Acknowledgement, msg.ProofAcked, msg.ProofHeight)
+		return result("", err)
+
+	default:
+		errMsg := fmt.Sprintf("unrecognized ibc message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Returns the result of a msg, whose data is the id of the client,
+// connection or channel it created, if any.
+func result(id string, err error) (res sdk.Result) {
+	if err != nil {
+		return abciResult(err)
+	}
+	res.Data = []byte(id)
+	return
+}
+
+//----------------------------------------
+// Query
+
+// The queries of the module list the ibc state for relayers, who query
+// its proofs with "/.store/<store name>/key" of the keys of ICS-24, e.g.
+// ConnectionKey.
+const (
+	QueryClients           = "clients"
+	QueryConnections       = "connections"
+	QueryChannels          = "channels"
+	QueryPacketCommitments = "commitments"
+)
+
+// Adds the query routes of the ibc module, e.g. "ibc/clients", to qr.
+func (ih ibcHandler) registerQueries(qr sdk.QueryRouter) {
+	qr.AddRoute(ModuleName+"/"+QueryClients, ih.queryClients)
+	qr.AddRoute(ModuleName+"/"+QueryConnections, ih.queryConnections)
+	qr.AddRoute(ModuleName+"/"+QueryChannels, ih.queryChannels)
+	qr.AddRoute(ModuleName+"/"+QueryPacketCommitments, ih.queryPacketCommitments)
+}
+
+func (ih ibcHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return sdk.RouteQuery(ih.queries, ctx, req)
+}
+
+// queryClients returns the states of the clients.
+func (ih ibcHandler) queryClients(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return queryJSON(ih.ibc.GetClients(ctx))
+}
+
+// queryConnections returns the connection ends.
+func (ih ibcHandler) queryConnections(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return queryJSON(ih.ibc.GetConnections(ctx))
+}
+
+// queryChannels returns the channel ends.
+func (ih ibcHandler) queryChannels(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return queryJSON(ih.ibc.GetChannels(ctx))
+}
+
+// queryPacketCommitments returns the commitments of the packets not yet
+// acknowledged of the path "commitments/<port id>/<channel id>".
+func (ih ibcHandler) queryPacketCommitments(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	parts := strings.Split(req.Path, "/")
+	if len(parts) != 4 {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrUnknownRequest("expected path commitments/<port id>/<channel id>"))
+		return
+	}
+	return queryJSON(ih.ibc.GetPacketCommitments(ctx, parts[2], parts[3]))
+}
+
+// Returns the response of the JSON of result.
+func queryJSON(result interface{}) (res abci.ResponseQuery) {
+	bz, err := amino.MarshalJSONIndent(result, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+	res.Data = bz
+	return
+}
+
+//----------------------------------------
+// misc
+
+func abciResult(err error) sdk.Result {
+	return sdk.ABCIResultFromError(err)
+}
@@ -0,0 +1,608 @@
+package ibc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto/merkle"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/rootmulti"
+)
+
+// ibc.IBCKeeperI tracks the light clients of counterparty chains, and the
+// connections, channels and packets with them.
+type IBCKeeperI interface {
+	BindPort(portID string, handler PacketHandler)
+
+	GetClientState(ctx sdk.Context, clientID string) (ClientState, bool)
+	GetConsensusState(ctx sdk.Context, clientID string, height int64) (ConsensusState, bool)
+	GetConnection(ctx sdk.Context, connectionID string) (ConnectionEnd, bool)
+	GetChannel(ctx sdk.Context, portID, channelID string) (ChannelEnd, bool)
+	GetClients(ctx sdk.Context) []IdentifiedClientState
+	GetConnections(ctx sdk.Context) []IdentifiedConnection
+	GetChannels(ctx sdk.Context) []IdentifiedChannel
+	GetPacketCommitments(ctx sdk.Context, portID, channelID string) []PacketState
+
+	CreateClient(ctx sdk.Context, chainID string, height int64, cs ConsensusState) string
+	UpdateClient(ctx sdk.Context, clientID string, header bft.Header, commit *bft.Commit, vals *bft.ValidatorSet) error
+
+	ConnOpenInit(ctx sdk.Context, clientID string, counterparty ConnectionCounterparty) (string, error)
+	ConnOpenTry(ctx sdk.Context, clientID string, counterparty ConnectionCounterparty, proofInit *merkle.Proof, proofHeight int64) (string, error)
+	ConnOpenAck(ctx sdk.Context, connectionID, counterpartyConnectionID string, proofTry *merkle.Proof, proofHeight int64) error
+	ConnOpenConfirm(ctx sdk.Context, connectionID string, proofAck *merkle.Proof, proofHeight int64) error
+
+	ChanOpenInit(ctx sdk.Context, portID string, channel ChannelEnd) (string, error)
+	ChanOpenTry(ctx sdk.Context, portID string, channel ChannelEnd, proofInit *merkle.Proof, proofHeight int64) (string, error)
+	ChanOpenAck(ctx sdk.Context, portID, channelID, counterpartyChannelID string, proofTry *merkle.Proof, proofHeight int64) error
+	ChanOpenConfirm(ctx sdk.Context, portID, channelID string, proofAck *merkle.Proof, proofHeight int64) error
+
+	SendPacket(ctx sdk.Context, portID, channelID string, data []byte, timeoutHeight int64) (uint64, error)
+	RecvPacket(ctx sdk.Context, packet Packet, proof *merkle.Proof, proofHeight int64) error
+	AcknowledgePacket(ctx sdk.Context, packet Packet, ack []byte, proof *merkle.Proof, proofHeight int64) error
+}
+
+var _ IBCKeeperI = IBCKeeper{}
+
+// IBCKeeper stores the ibc state under the key, whose name is the prefix
+// of the proofs of this chain for its counterparties.
+type IBCKeeper struct {
+	key   store.StoreKey
+	ports map[string]PacketHandler // port id -> handler
+}
+
+// NewIBCKeeper returns a new IBCKeeper.
+func NewIBCKeeper(key store.StoreKey) IBCKeeper {
+	return IBCKeeper{
+		key:   key,
+		ports: make(map[string]PacketHandler),
+	}
+}
+
+// BindPort binds portID to handler, which handles its packets.  The ports
+// must be bound when constructing the app, before any channel is opened.
+func (ik IBCKeeper) BindPort(portID string, handler PacketHandler) {
+	if _, ok := ik.ports[portID]; ok {
+		panic(fmt.Sprintf("port %s already bound", portID))
+	}
+	ik.ports[portID] = handler
+}
+
+//----------------------------------------
+// getters
+
+// GetClientState returns the state of the client clientID, if any.
+func (ik IBCKeeper) GetClientState(ctx sdk.Context, clientID string) (cs ClientState, ok bool) {
+	ok = ik.get(ctx, ClientStateKey(clientID), &cs)
+	return
+}
+
+// GetConsensusState returns the consensus state of the client clientID at
+// height, if any.
+func (ik IBCKeeper) GetConsensusState(ctx sdk.Context, clientID string, height int64) (cs ConsensusState, ok bool) {
+	ok = ik.get(ctx, ConsensusStateKey(clientID, height), &cs)
+	return
+}
+
+// GetConnection returns the connection end of connectionID, if any.
+func (ik IBCKeeper) GetConnection(ctx sdk.Context, connectionID string) (conn ConnectionEnd, ok bool) {
+	ok = ik.get(ctx, ConnectionKey(connectionID), &conn)
+	return
+}
+
+// GetChannel returns the channel end of channelID on portID, if any.
+func (ik IBCKeeper) GetChannel(ctx sdk.Context, portID, channelID string) (ch ChannelEnd, ok bool) {
+	ok = ik.get(ctx, ChannelKey(portID, channelID), &ch)
+	return
+}
+
+// GetClients returns the states of the clients, in the order of their
+// creation.
+func (ik IBCKeeper) GetClients(ctx sdk.Context) []IdentifiedClientState {
+	clients := []IdentifiedClientState{}
+	for seq := uint64(0); seq < ik.getSequence(ctx, nextClientSequenceKey); seq++ {
+		id := ClientID(seq)
+		cs, _ := ik.GetClientState(ctx, id)
+		clients = append(clients, IdentifiedClientState{ClientID: id, ClientState: cs})
+	}
+	return clients
+}
+
+// GetConnections returns the connection ends, in the order of their
+// creation.
+func (ik IBCKeeper) GetConnections(ctx sdk.Context) []IdentifiedConnection {
+	conns := []IdentifiedConnection{}
+	for seq := uint64(0); seq < ik.getSequence(ctx, nextConnectionSequenceKey); seq++ {
+		id := ConnectionID(seq)
+		conn, _ := ik.GetConnection(ctx, id)
+		conns = append(conns, IdentifiedConnection{ConnectionID: id, Connection: conn})
+	}
+	return conns
+}
+
+// GetChannels returns the channel ends, sorted by port and channel.
+func (ik IBCKeeper) GetChannels(ctx sdk.Context) []IdentifiedChannel {
+	chs := []IdentifiedChannel{}
+	prefix := StoreKeyPrefix + "channelEnds/ports/"
+	stor := ctx.Store(ik.key)
+	iter := store.PrefixIterator(stor, []byte(prefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		// <port id>/channels/<channel id>
+		parts := strings.Split(string(iter.Key()[len(prefix):]), "/")
+		var ch ChannelEnd
+		amino.MustUnmarshal(iter.Value(), &ch)
+		chs = append(chs, IdentifiedChannel{PortID: parts[0], ChannelID: parts[2], Channel: ch})
+	}
+	return chs
+}
+
+// GetPacketCommitments returns the commitments of the packets sent on the
+// channel and not yet acknowledged, sorted by sequence.
+func (ik IBCKeeper) GetPacketCommitments(ctx sdk.Context, portID, channelID string) []PacketState {
+	commitments := []PacketState{}
+	prefix := PacketCommitmentKeyPrefix(portID, channelID)
+	stor := ctx.Store(ik.key)
+	iter := store.PrefixIterator(stor, prefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		seq, _ := strconv.ParseUint(string(iter.Key()[len(prefix):]), 10, 64)
+		commitments = append(commitments, PacketState{Sequence: seq, Data: iter.Value()})
+	}
+	sort.Slice(commitments, func(i, j int) bool {
+		return commitments[i].Sequence < commitments[j].Sequence
+	})
+	return commitments
+}
+
+//----------------------------------------
+// clients
+
+// CreateClient creates a client of the chain chainID, which trusts cs at
+// height, and returns its id.
+func (ik IBCKeeper) CreateClient(ctx sdk.Context, chainID string, height int64, cs ConsensusState) string {
+	clientID := ClientID(ik.nextSequence(ctx, nextClientSequenceKey))
+	ik.set(ctx, ClientStateKey(clientID), ClientState{ChainID: chainID, LatestHeight: height})
+	ik.set(ctx, ConsensusStateKey(clientID, height), cs)
+	return clientID
+}
+
+// UpdateClient verifies header, committed by commit of vals, and adds its
+// consensus state to the client clientID.  The header must be after the
+// latest height of the client, and vals must be the next validators of its
+// consensus state there, which is the case if the header is the next one,
+// or if the validators have not changed since.
+func (ik IBCKeeper) UpdateClient(ctx sdk.Context, clientID string, header bft.Header, commit *bft.Commit, vals *bft.ValidatorSet) error {
+	client, ok := ik.GetClientState(ctx, clientID)
+	if !ok {
+		return std.ErrUnknownRequest(fmt.Sprintf("client %s does not exist", clientID))
+	}
+	if header.Height <= client.LatestHeight {
+		return std.ErrUnknownRequest(fmt.Sprintf("header height %d not after latest height %d", header.Height, client.LatestHeight))
+	}
+	trusted, _ := ik.GetConsensusState(ctx, clientID, client.LatestHeight)
+	if !bytes.Equal(vals.Hash(), header.ValidatorsHash) {
+		return std.ErrUnauthorized("validators are not those of the header")
+	}
+	if !bytes.Equal(header.ValidatorsHash, trusted.NextValidatorsHash) {
+		return std.ErrUnauthorized(fmt.Sprintf("validators changed since height %d", client.LatestHeight))
+	}
+	sh := bft.SignedHeader{Header: &header, Commit: commit}
+	if err := sh.ValidateBasic(client.ChainID); err != nil {
+		return std.ErrUnauthorized(err.Error())
+	}
+	if err := vals.VerifyCommit(client.ChainID, commit.BlockID, header.Height, commit); err != nil {
+		return std.ErrUnauthorized(err.Error())
+	}
+	client.LatestHeight = header.Height
+	ik.set(ctx, ClientStateKey(clientID), client)
+	ik.set(ctx, ConsensusStateKey(clientID, header.Height), ConsensusState{
+		Time:               header.Time,
+		Root:               header.AppHash,
+		NextValidatorsHash: header.NextValidatorsHash,
+	})
+	return nil
+}
+
+// Verifies proof of value at key in the state of the counterparty of conn,
+// with the root of the consensus state of its client at height.
+func (ik IBCKeeper) verifyMembership(ctx sdk.Context, conn ConnectionEnd, height int64, proof *merkle.Proof, key []byte, value interface{}) error {
+	cs, ok := ik.GetConsensusState(ctx, conn.ClientID, height)
+	if !ok {
+		return std.ErrUnknownRequest(fmt.Sprintf("client %s has no consensus state at height %d", conn.ClientID, height))
+	}
+	keypath := merkle.KeyPath{}.
+		AppendKey([]byte(conn.Counterparty.Prefix), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL)
+	var bz []byte
+	switch value := value.(type) {
+	case []byte:
+		bz = value
+	default:
+		bz = amino.MustMarshal(value)
+	}
+	err := rootmulti.DefaultProofRuntime().VerifyValue(proof, cs.Root, keypath.String(), bz)
+	if err != nil {
+		return std.ErrUnauthorized(fmt.Sprintf("invalid proof of %s: %v", key, err))
+	}
+	return nil
+}
+
+//----------------------------------------
+// connections
+
+// ConnOpenInit initializes a connection over the client clientID to the
+// client of counterparty, and returns its id.
+func (ik IBCKeeper) ConnOpenInit(ctx sdk.Context, clientID string, counterparty ConnectionCounterparty) (string, error) {
+	if _, ok := ik.GetClientState(ctx, clientID); !ok {
+		return "", std.ErrUnknownRequest(fmt.Sprintf("client %s does not exist", clientID))
+	}
+	counterparty.ConnectionID = ""
+	connectionID := ConnectionID(ik.nextSequence(ctx, nextConnectionSequenceKey))
+	ik.set(ctx, ConnectionKey(connectionID), ConnectionEnd{
+		State:        StateInit,
+		ClientID:     clientID,
+		Counterparty: counterparty,
+	})
+	return connectionID, nil
+}
+
+// ConnOpenTry opens a connection over the client clientID to the
+// connection of counterparty, proven by proofInit to be initialized to
+// this chain, and returns its id.
+func (ik IBCKeeper) ConnOpenTry(ctx sdk.Context, clientID string, counterparty ConnectionCounterparty, proofInit *merkle.Proof, proofHeight int64) (string, error) {
+	if _, ok := ik.GetClientState(ctx, clientID); !ok {
+		return "", std.ErrUnknownRequest(fmt.Sprintf("client %s does not exist", clientID))
+	}
+	conn := ConnectionEnd{
+		State:        StateTryOpen,
+		ClientID:     clientID,
+		Counterparty: counterparty,
+	}
+	expected := ConnectionEnd{
+		State:    StateInit,
+		ClientID: counterparty.ClientID,
+		Counterparty: ConnectionCounterparty{
+			ClientID: clientID,
+			Prefix:   ik.key.Name(),
+		},
+	}
+	err := ik.verifyMembership(ctx, conn, proofHeight, proofInit, ConnectionKey(counterparty.ConnectionID), expected)
+	if err != nil {
+		return "", err
+	}
+	connectionID := ConnectionID(ik.nextSequence(ctx, nextConnectionSequenceKey))
+	ik.set(ctx, ConnectionKey(connectionID), conn)
+	return connectionID, nil
+}
+
+// ConnOpenAck opens the connection connectionID, initialized by this
+// chain, to the connection counterpartyConnectionID, proven by proofTry to
+// be opened by the counterparty.
+func (ik IBCKeeper) ConnOpenAck(ctx sdk.Context, connectionID, counterpartyConnectionID string, proofTry *merkle.Proof, proofHeight int64) error {
+	conn, ok := ik.GetConnection(ctx, connectionID)
+	if !ok || conn.State != StateInit {
+		return std.ErrUnknownRequest(fmt.Sprintf("connection %s is not initialized", connectionID))
+	}
+	expected := ConnectionEnd{
+		State:    StateTryOpen,
+		ClientID: conn.Counterparty.ClientID,
+		Counterparty: ConnectionCounterparty{
+			ClientID:     conn.ClientID,
+			ConnectionID: connectionID,
+			Prefix:       ik.key.Name(),
+		},
+	}
+	err := ik.verifyMembership(ctx, conn, proofHeight, proofTry, ConnectionKey(counterpartyConnectionID), expected)
+	if err != nil {
+		return err
+	}
+	conn.State = StateOpen
+	conn.Counterparty.ConnectionID = counterpartyConnectionID
+	ik.set(ctx, ConnectionKey(connectionID), conn)
+	return nil
+}
+
+// ConnOpenConfirm opens the connection connectionID, opened by the
+// counterparty as proven by proofAck.
+func (ik IBCKeeper) ConnOpenConfirm(ctx sdk.Context, connectionID string, proofAck *merkle.Proof, proofHeight int64) error {
+	conn, ok := ik.GetConnection(ctx, connectionID)
+	if !ok || conn.State != StateTryOpen {
+		return std.ErrUnknownRequest(fmt.Sprintf("connection %s is not tried", connectionID))
+	}
+	expected := ConnectionEnd{
+		State:    StateOpen,
+		ClientID: conn.Counterparty.ClientID,
+		Counterparty: ConnectionCounterparty{
+			ClientID:     conn.ClientID,
+			ConnectionID: connectionID,
+			Prefix:       ik.key.Name(),
+		},
+	}
+	err := ik.verifyMembership(ctx, conn, proofHeight, proofAck, ConnectionKey(conn.Counterparty.ConnectionID), expected)
+	if err != nil {
+		return err
+	}
+	conn.State = StateOpen
+	ik.set(ctx, ConnectionKey(connectionID), conn)
+	return nil
+}
+
+// Returns the open connection of ch.
+func (ik IBCKeeper) getOpenConnection(ctx sdk.Context, ch ChannelEnd) (ConnectionEnd, error) {
+	conn, ok := ik.GetConnection(ctx, ch.ConnectionID)
+	if !ok || conn.State != StateOpen {
+		return conn, std.ErrUnknownRequest(fmt.Sprintf("connection %s is not open", ch.ConnectionID))
+	}
+	return conn, nil
+}
+
+//----------------------------------------
+// channels
+
+// ChanOpenInit initializes channel on the port portID, over an open
+// connection, and returns its id.
+func (ik IBCKeeper) ChanOpenInit(ctx sdk.Context, portID string, channel ChannelEnd) (string, error) {
+	if _, ok := ik.ports[portID]; !ok {
+		return "", std.ErrUnknownRequest(fmt.Sprintf("port %s is not bound", portID))
+	}
+	if _, err := ik.getOpenConnection(ctx, channel); err != nil {
+		return "", err
+	}
+	channel.State = StateInit
+	channel.Counterparty.ChannelID = ""
+	channelID := ChannelID(ik.nextSequence(ctx, nextChannelSequenceKey))
+	ik.set(ctx, ChannelKey(portID, channelID), channel)
+	return channelID, nil
+}
+
+// ChanOpenTry opens channel on the port portID, to the channel of its
+// counterparty, proven by proofInit to be initialized to this chain, and
+// returns its id.
+func (ik IBCKeeper) ChanOpenTry(ctx sdk.Context, portID string, channel ChannelEnd, proofInit *merkle.Proof, proofHeight int64) (string, error) {
+	if _, ok := ik.ports[portID]; !ok {
+		return "", std.ErrUnknownRequest(fmt.Sprintf("port %s is not bound", portID))
+	}
+	conn, err := ik.getOpenConnection(ctx, channel)
+	if err != nil {
+		return "", err
+	}
+	expected := ChannelEnd{
+		State:        StateInit,
+		Ordering:     channel.Ordering,
+		Counterparty: ChannelCounterparty{PortID: portID},
+		ConnectionID: conn.Counterparty.ConnectionID,
+		Version:      channel.Version,
+	}
+	key := ChannelKey(channel.Counterparty.PortID, channel.Counterparty.ChannelID)
+	if err := ik.verifyMembership(ctx, conn, proofHeight, proofInit, key, expected); err != nil {
+		return "", err
+	}
+	channel.State = StateTryOpen
+	channelID := ChannelID(ik.nextSequence(ctx, nextChannelSequenceKey))
+	ik.set(ctx, ChannelKey(portID, channelID), channel)
+	return channelID, nil
+}
+
+// ChanOpenAck opens the channel channelID of the port portID, initialized
+// by this chain, to the channel counterpartyChannelID, proven by proofTry
+// to be opened by the counterparty.
+func (ik IBCKeeper) ChanOpenAck(ctx sdk.Context, portID, channelID, counterpartyChannelID string, proofTry *merkle.Proof, proofHeight int64) error {
+	ch, ok := ik.GetChannel(ctx, portID, channelID)
+	if !ok || ch.State != StateInit {
+		return std.ErrUnknownRequest(fmt.Sprintf("channel %s of port %s is not initialized", channelID, portID))
+	}
+	conn, err := ik.getOpenConnection(ctx, ch)
+	if err != nil {
+		return err
+	}
+	expected := ChannelEnd{
+		State:        StateTryOpen,
+		Ordering:     ch.Ordering,
+		Counterparty: ChannelCounterparty{PortID: portID, ChannelID: channelID},
+		ConnectionID: conn.Counterparty.ConnectionID,
+		Version:      ch.Version,
+	}
+	key := ChannelKey(ch.Counterparty.PortID, counterpartyChannelID)
+	if err := ik.verifyMembership(ctx, conn, proofHeight, proofTry, key, expected); err != nil {
+		return err
+	}
+	ch.State = StateOpen
+	ch.Counterparty.ChannelID = counterpartyChannelID
+	ik.set(ctx, ChannelKey(portID, channelID), ch)
+	return nil
+}
+
+// ChanOpenConfirm opens the channel channelID of the port portID, opened
+// by the counterparty as proven by proofAck.
+func (ik IBCKeeper) ChanOpenConfirm(ctx sdk.Context, portID, channelID string, proofAck *merkle.Proof, proofHeight int64) error {
+	ch, ok := ik.GetChannel(ctx, portID, channelID)
+	if !ok || ch.State != StateTryOpen {
+		return std.ErrUnknownRequest(fmt.Sprintf("channel %s of port %s is not tried", channelID, portID))
+	}
+	conn, err := ik.getOpenConnection(ctx, ch)
+	if err != nil {
+		return err
+	}
+	expected := ChannelEnd{
+		State:        StateOpen,
+		Ordering:     ch.Ordering,
+		Counterparty: ChannelCounterparty{PortID: portID, ChannelID: channelID},
+		ConnectionID: conn.Counterparty.ConnectionID,
+		Version:      ch.Version,
+	}
+	key := ChannelKey(ch.Counterparty.PortID, ch.Counterparty.ChannelID)
+	if err := ik.verifyMembership(ctx, conn, proofHeight, proofAck, key, expected); err != nil {
+		return err
+	}
+	ch.State = StateOpen
+	ik.set(ctx, ChannelKey(portID, channelID), ch)
+	return nil
+}
+
+// Returns the open channel channelID of the port portID.
+func (ik IBCKeeper) getOpenChannel(ctx sdk.Context, portID, channelID string) (ChannelEnd, error) {
+	ch, ok := ik.GetChannel(ctx, portID, channelID)
+	if !ok || ch.State != StateOpen {
+		return ch, std.ErrUnknownRequest(fmt.Sprintf("channel %s of port %s is not open", channelID, portID))
+	}
+	return ch, nil
+}
+
+//----------------------------------------
+// packets
+
+// SendPacket sends data from the port portID, on its open channel
+// channelID, to be received before timeoutHeight of the counterparty
+// chain, unless it is zero, and returns the sequence of its packet.  It is
+// called by the module which bound the port.
+func (ik IBCKeeper) SendPacket(ctx sdk.Context, portID, channelID string, data []byte, timeoutHeight int64) (uint64, error) {
+	ch, err := ik.getOpenChannel(ctx, portID, channelID)
+	if err != nil {
+		return 0, err
+	}
+	seq := ik.getPacketSequence(ctx, NextSequenceSendKey(portID, channelID))
+	ik.setSequence(ctx, NextSequenceSendKey(portID, channelID), seq+1)
+	packet := Packet{
+		Sequence:      seq,
+		SourcePort:    portID,
+		SourceChannel: channelID,
+		DestPort:      ch.Counterparty.PortID,
+		DestChannel:   ch.Counterparty.ChannelID,
+		Data:          data,
+		TimeoutHeight: timeoutHeight,
+	}
+	stor := ctx.Store(ik.key)
+	stor.Set(PacketCommitmentKey(portID, channelID, seq), PacketCommitment(packet))
+	ctx.EventLogger().EmitEvent(SendPacketEvent{Packet: packet})
+	return seq, nil
+}
+
+// RecvPacket receives packet, proven by proof to be sent by the
+// counterparty, and writes the acknowledgement of the handler of its port.
+// The packets of ordered channels must be received in order, and those of
+// unordered channels at most once.
+func (ik IBCKeeper) RecvPacket(ctx sdk.Context, packet Packet, proof *merkle.Proof, proofHeight int64) error {
+	ch, err := ik.getOpenChannel(ctx, packet.DestPort, packet.DestChannel)
+	if err != nil {
+		return err
+	}
+	if packet.SourcePort != ch.Counterparty.PortID || packet.SourceChannel != ch.Counterparty.ChannelID {
+		return std.ErrUnknownRequest("packet source is not the counterparty of the channel")
+	}
+	if packet.TimeoutHeight != 0 && ctx.BlockHeight() >= packet.TimeoutHeight {
+		return std.ErrUnknownRequest(fmt.Sprintf("packet timed out at height %d", packet.TimeoutHeight))
+	}
+	conn, err := ik.getOpenConnection(ctx, ch)
+	if err != nil {
+		return err
+	}
+	key := PacketCommitmentKey(packet.SourcePort, packet.SourceChannel, packet.Sequence)
+	if err := ik.verifyMembership(ctx, conn, proofHeight, proof, key, PacketCommitment(packet)); err != nil {
+		return err
+	}
+	stor := ctx.Store(ik.key)
+	switch ch.Ordering {
+	case OrderOrdered:
+		next := ik.getPacketSequence(ctx, NextSequenceRecvKey(packet.DestPort, packet.DestChannel))
+		if packet.Sequence != next {
+			return std.ErrUnknownRequest(fmt.Sprintf("packet sequence %d is not the next one %d", packet.Sequence, next))
+		}
+		ik.setSequence(ctx, NextSequenceRecvKey(packet.DestPort, packet.DestChannel), next+1)
+	default:
+		receiptKey := PacketReceiptKey(packet.DestPort, packet.DestChannel, packet.Sequence)
+		if stor.Has(receiptKey) {
+			return std.ErrUnknownRequest(fmt.Sprintf("packet %d already received", packet.Sequence))
+		}
+		stor.Set(receiptKey, []byte{1})
+	}
+	ack := ik.ports[packet.DestPort].OnRecvPacket(ctx, packet)
+	stor.Set(PacketAcknowledgementKey(packet.DestPort, packet.DestChannel, packet.Sequence), AcknowledgementCommitment(ack))
+	ctx.EventLogger().EmitEvent(WriteAcknowledgementEvent{Packet: packet, Acknowledgement: ack})
+	return nil
+}
+
+// AcknowledgePacket handles ack of packet, sent by this chain, proven by
+// proof to be written by the counterparty, and deletes its commitment.
+func (ik IBCKeeper) AcknowledgePacket(ctx sdk.Context, packet Packet, ack []byte, proof *merkle.Proof, proofHeight int64) error {
+	ch, err := ik.getOpenChannel(ctx, packet.SourcePort, packet.SourceChannel)
+	if err != nil {
+		return err
+	}
+	if packet.DestPort != ch.Counterparty.PortID || packet.DestChannel != ch.Counterparty.ChannelID {
+		return std.ErrUnknownRequest("packet destination is not the counterparty of the channel")
+	}
+	stor := ctx.Store(ik.key)
+	commitmentKey := PacketCommitmentKey(packet.SourcePort, packet.SourceChannel, packet.Sequence)
+	if !bytes.Equal(stor.Get(commitmentKey), PacketCommitment(packet)) {
+		return std.ErrUnknownRequest(fmt.Sprintf("packet %d was not sent, or already acknowledged", packet.Sequence))
+	}
+	conn, err := ik.getOpenConnection(ctx, ch)
+	if err != nil {
+		return err
+	}
+	key := PacketAcknowledgementKey(packet.DestPort, packet.DestChannel, packet.Sequence)
+	if err := ik.verifyMembership(ctx, conn, proofHeight, proof, key, AcknowledgementCommitment(ack)); err != nil {
+		return err
+	}
+	stor.Delete(commitmentKey)
+	return ik.ports[packet.SourcePort].OnAcknowledgementPacket(ctx, packet, ack)
+}
+
+//----------------------------------------
+// misc
+
+// Gets the value at key into ptr, and returns whether it exists.
+func (ik IBCKeeper) get(ctx sdk.Context, key []byte, ptr interface{}) bool {
+	stor := ctx.Store(ik.key)
+	bz := stor.Get(key)
+	if bz == nil {
+		return false
+	}
+	amino.MustUnmarshal(bz, ptr)
+	return true
+}
+
+func (ik IBCKeeper) set(ctx sdk.Context, key []byte, value interface{}) {
+	stor := ctx.Store(ik.key)
+	stor.Set(key, amino.MustMarshal(value))
+}
+
+// Returns the sequence at key, zero if none.
+func (ik IBCKeeper) getSequence(ctx sdk.Context, key []byte) uint64 {
+	stor := ctx.Store(ik.key)
+	bz := stor.Get(key)
+	if bz == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(bz)
+}
+
+func (ik IBCKeeper) setSequence(ctx sdk.Context, key []byte, seq uint64) {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, seq)
+	stor := ctx.Store(ik.key)
+	stor.Set(key, bz)
+}
+
+// Returns the sequence of the next packet at key, starting at one.
+func (ik IBCKeeper) getPacketSequence(ctx sdk.Context, key []byte) uint64 {
+	if seq := ik.getSequence(ctx, key); seq != 0 {
+		return seq
+	}
+	return 1
+}
+
+// Returns the sequence at key, and increments it.
+func (ik IBCKeeper) nextSequence(ctx sdk.Context, key []byte) uint64 {
+	seq := ik.getSequence(ctx, key)
+	ik.setSequence(ctx, key, seq+1)
+	return seq
+}
@@ -0,0 +1,135 @@
+package ibc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+)
+
+func TestUpdateClient(t *testing.T) {
+	a, b := newTestChain("chain-a"), newTestChain("chain-b")
+	clientID := a.ibc.CreateClient(a.ctx, b.chainID, b.height, b.consensusState())
+	assert.Equal(t, "07-tendermint-0", clientID)
+
+	// headers must be after the latest height.
+	require.Error(t, b.updateClientOf(a, clientID))
+	b.commit()
+	require.NoError(t, b.updateClientOf(a, clientID))
+	client, ok := a.ibc.GetClientState(a.ctx, clientID)
+	require.True(t, ok)
+	assert.Equal(t, b.height, client.LatestHeight)
+	cs, ok := a.ibc.GetConsensusState(a.ctx, clientID, b.height)
+	require.True(t, ok)
+	assert.Equal(t, b.appHash, cs.Root)
+
+	// headers must be signed by the trusted validators, of the chain.
+	b.commit()
+	header, commit := b.signedHeader()
+	other := newTestChain("chain-b")
+	require.Error(t, a.ibc.UpdateClient(a.ctx, clientID, header, commit, other.vals))
+	header.ChainID = "chain-c"
+	require.Error(t, a.ibc.UpdateClient(a.ctx, clientID, header, commit, b.vals))
+	header.ChainID = "chain-b"
+	header.AppHash = []byte("forged")
+	require.Error(t, a.ibc.UpdateClient(a.ctx, clientID, header, commit, b.vals))
+}
+
+func TestHandshakesAndPackets(t *testing.T) {
+	a, b := newTestChain("chain-a"), newTestChain("chain-b")
+	clientA := a.ibc.CreateClient(a.ctx, b.chainID, b.height, b.consensusState())
+	clientB := b.ibc.CreateClient(b.ctx, a.chainID, a.height, a.consensusState())
+
+	// connection handshake.
+	connA, err := a.ibc.ConnOpenInit(a.ctx, clientA, ConnectionCounterparty{ClientID: clientB, Prefix: "main"})
+	require.NoError(t, err)
+	a.commit()
+	require.NoError(t, a.updateClientOf(b, clientB))
+	counterparty := ConnectionCounterparty{ClientID: clientA, ConnectionID: connA, Prefix: "main"}
+	_, err = b.ibc.ConnOpenTry(b.ctx, clientB, counterparty, a.prove(ConnectionKey("connection-1")), a.height)
+	require.Error(t, err, "proof of another key")
+	connB, err := b.ibc.ConnOpenTry(b.ctx, clientB, counterparty, a.prove(ConnectionKey(connA)), a.height)
+	require.NoError(t, err)
+	b.commit()
+	require.NoError(t, b.updateClientOf(a, clientA))
+	require.NoError(t, a.ibc.ConnOpenAck(a.ctx, connA, connB, b.prove(ConnectionKey(connB)), b.height))
+	a.commit()
+	require.NoError(t, a.updateClientOf(b, clientB))
+	require.NoError(t, b.ibc.ConnOpenConfirm(b.ctx, connB, a.prove(ConnectionKey(connA)), a.height))
+	b.commit()
+	conn, _ := a.ibc.GetConnection(a.ctx, connA)
+	assert.Equal(t, StateOpen, conn.State)
+	assert.Equal(t, connB, conn.Counterparty.ConnectionID)
+	conn, _ = b.ibc.GetConnection(b.ctx, connB)
+	assert.Equal(t, StateOpen, conn.State)
+
+	// channel handshake.
+	chA, err := a.ibc.ChanOpenInit(a.ctx, "transfer", ChannelEnd{
+		Ordering:     OrderUnordered,
+		Counterparty: ChannelCounterparty{PortID: "transfer"},
+		ConnectionID: connA,
+		Version:      "ics20-1",
+	})
+	require.NoError(t, err)
+	a.commit()
+	require.NoError(t, a.updateClientOf(b, clientB))
+	chB, err := b.ibc.ChanOpenTry(b.ctx, "transfer", ChannelEnd{
+		Ordering:     OrderUnordered,
+		Counterparty: ChannelCounterparty{PortID: "transfer", ChannelID: chA},
+		ConnectionID: connB,
+		Version:      "ics20-1",
+	}, a.prove(ChannelKey("transfer", chA)), a.height)
+	require.NoError(t, err)
+	b.commit()
+	require.NoError(t, b.updateClientOf(a, clientA))
+	require.NoError(t, a.ibc.ChanOpenAck(a.ctx, "transfer", chA, chB, b.prove(ChannelKey("transfer", chB)), b.height))
+	a.commit()
+	require.NoError(t, a.updateClientOf(b, clientB))
+	require.NoError(t, b.ibc.ChanOpenConfirm(b.ctx, "transfer", chB, a.prove(ChannelKey("transfer", chA)), a.height))
+	b.commit()
+
+	// packets.
+	seq, err := a.ibc.SendPacket(a.ctx, "transfer", chA, []byte("hello"), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), seq)
+	assert.Equal(t, 1, len(a.ctx.EventLogger().Events()))
+	assert.Equal(t, 1, len(a.ibc.GetPacketCommitments(a.ctx, "transfer", chA)))
+	a.commit()
+	require.NoError(t, a.updateClientOf(b, clientB))
+	packet := Packet{
+		Sequence:      seq,
+		SourcePort:    "transfer",
+		SourceChannel: chA,
+		DestPort:      "transfer",
+		DestChannel:   chB,
+		Data:          []byte("hello"),
+	}
+	proof := a.prove(PacketCommitmentKey("transfer", chA, seq))
+	forged := packet
+	forged.Data = []byte("forged")
+	require.Error(t, b.ibc.RecvPacket(b.ctx, forged, proof, a.height))
+	require.NoError(t, b.ibc.RecvPacket(b.ctx, packet, proof, a.height))
+	require.Error(t, b.ibc.RecvPacket(b.ctx, packet, proof, a.height), "received twice")
+	assert.Equal(t, []string{"hello"}, b.app.received)
+	b.commit()
+	require.NoError(t, b.updateClientOf(a, clientA))
+	proof = b.prove(PacketAcknowledgementKey("transfer", chB, seq))
+	require.Error(t, a.ibc.AcknowledgePacket(a.ctx, packet, []byte("forged"), proof, b.height))
+	require.NoError(t, a.ibc.AcknowledgePacket(a.ctx, packet, []byte("ok:hello"), proof, b.height))
+	assert.Equal(t, []string{"ok:hello"}, a.app.acked)
+	assert.Empty(t, a.ibc.GetPacketCommitments(a.ctx, "transfer", chA))
+
+	// queries.
+	h := NewHandler(a.ibc)
+	res := h.Query(a.ctx, abci.RequestQuery{Path: "ibc/channels"})
+	require.Nil(t, res.Error)
+	assert.Contains(t, string(res.Data), `"channel_id": "channel-0"`)
+	res = h.Query(a.ctx, abci.RequestQuery{Path: "ibc/connections"})
+	require.Nil(t, res.Error)
+	assert.Contains(t, string(res.Data), `"connection_id": "connection-0"`)
+	res = h.Query(a.ctx, abci.RequestQuery{Path: "ibc/commitments/transfer/" + chA})
+	require.Nil(t, res.Error)
+	assert.Equal(t, "[]", string(res.Data))
+}
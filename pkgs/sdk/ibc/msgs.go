@@ -0,0 +1,395 @@
+package ibc
+
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/merkle"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// The msgs of the handshakes and packets carry the proofs of the state of
+// the counterparty chain, as returned by its store queries, verified with
+// the root of the consensus state of the client at ProofHeight.  As the app
+// hash of a header is that of the state after the previous height, the
+// proofs are queried at ProofHeight-1.  All the msgs are signed by Signer,
+// e.g. a relayer, who pays the fee.
+
+// MsgCreateClient - create a client of the chain ChainID, which trusts
+// ConsensusState at Height.
+type MsgCreateClient struct {
+	Signer         crypto.Address `json:"signer" yaml:"signer"`
+	ChainID        string         `json:"chain_id" yaml:"chain_id"`
+	Height         int64          `json:"height" yaml:"height"`
+	ConsensusState ConsensusState `json:"consensus_state" yaml:"consensus_state"`
+}
+
+var _ std.Msg = MsgCreateClient{}
+
+func (msg MsgCreateClient) Route() string { return RouterKey }
+func (msg MsgCreateClient) Type() string  { return "create_client" }
+
+func (msg MsgCreateClient) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.ChainID == "" {
+		return std.ErrUnknownRequest("missing chain id")
+	}
+	if msg.Height <= 0 {
+		return std.ErrUnknownRequest("height must be positive")
+	}
+	if len(msg.ConsensusState.Root) == 0 || len(msg.ConsensusState.NextValidatorsHash) == 0 {
+		return std.ErrUnknownRequest("missing root or next validators hash")
+	}
+	return nil
+}
+
+func (msg MsgCreateClient) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgCreateClient) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgUpdateClient - update the client ClientID with Header, committed by
+// Commit of Validators.
+type MsgUpdateClient struct {
+	Signer     crypto.Address    `json:"signer" yaml:"signer"`
+	ClientID   string            `json:"client_id" yaml:"client_id"`
+	Header     bft.Header        `json:"header" yaml:"header"`
+	Commit     *bft.Commit       `json:"commit" yaml:"commit"`
+	Validators *bft.ValidatorSet `json:"validators" yaml:"validators"`
+}
+
+var _ std.Msg = MsgUpdateClient{}
+
+func (msg MsgUpdateClient) Route() string { return RouterKey }
+func (msg MsgUpdateClient) Type() string  { return "update_client" }
+
+func (msg MsgUpdateClient) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.ClientID == "" {
+		return std.ErrUnknownRequest("missing client id")
+	}
+	if msg.Commit == nil || msg.Validators == nil {
+		return std.ErrUnknownRequest("missing commit or validators")
+	}
+	return nil
+}
+
+func (msg MsgUpdateClient) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgUpdateClient) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgConnectionOpenInit - open a connection over the client ClientID to
+// the client of Counterparty.
+type MsgConnectionOpenInit struct {
+	Signer       crypto.Address         `json:"signer" yaml:"signer"`
+	ClientID     string                 `json:"client_id" yaml:"client_id"`
+	Counterparty ConnectionCounterparty `json:"counterparty" yaml:"counterparty"`
+}
+
+var _ std.Msg = MsgConnectionOpenInit{}
+
+func (msg MsgConnectionOpenInit) Route() string { return RouterKey }
+func (msg MsgConnectionOpenInit) Type() string  { return "connection_open_init" }
+
+func (msg MsgConnectionOpenInit) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.ClientID == "" || msg.Counterparty.ClientID == "" || msg.Counterparty.Prefix == "" {
+		return std.ErrUnknownRequest("missing client id, or counterparty client id or prefix")
+	}
+	return nil
+}
+
+func (msg MsgConnectionOpenInit) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgConnectionOpenInit) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgConnectionOpenTry - open a connection over the client ClientID to
+// the connection of Counterparty, proven by ProofInit to be initialized.
+type MsgConnectionOpenTry struct {
+	Signer       crypto.Address         `json:"signer" yaml:"signer"`
+	ClientID     string                 `json:"client_id" yaml:"client_id"`
+	Counterparty ConnectionCounterparty `json:"counterparty" yaml:"counterparty"`
+	ProofInit    *merkle.Proof          `json:"proof_init" yaml:"proof_init"`
+	ProofHeight  int64                  `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgConnectionOpenTry{}
+
+func (msg MsgConnectionOpenTry) Route() string { return RouterKey }
+func (msg MsgConnectionOpenTry) Type() string  { return "connection_open_try" }
+
+func (msg MsgConnectionOpenTry) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.ClientID == "" || msg.Counterparty.ClientID == "" ||
+		msg.Counterparty.ConnectionID == "" || msg.Counterparty.Prefix == "" {
+		return std.ErrUnknownRequest("missing client id, or counterparty ids or prefix")
+	}
+	return validateProof(msg.ProofInit, msg.ProofHeight)
+}
+
+func (msg MsgConnectionOpenTry) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgConnectionOpenTry) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgConnectionOpenAck - open the connection ConnectionID, initialized by
+// this chain, to the connection CounterpartyConnectionID, proven by
+// ProofTry to be opened by the counterparty.
+type MsgConnectionOpenAck struct {
+	Signer                   crypto.Address `json:"signer" yaml:"signer"`
+	ConnectionID             string         `json:"connection_id" yaml:"connection_id"`
+	CounterpartyConnectionID string         `json:"counterparty_connection_id" yaml:"counterparty_connection_id"`
+	ProofTry                 *merkle.Proof  `json:"proof_try" yaml:"proof_try"`
+	ProofHeight              int64          `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgConnectionOpenAck{}
+
+func (msg MsgConnectionOpenAck) Route() string { return RouterKey }
+func (msg MsgConnectionOpenAck) Type() string  { return "connection_open_ack" }
+
+func (msg MsgConnectionOpenAck) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.ConnectionID == "" || msg.CounterpartyConnectionID == "" {
+		return std.ErrUnknownRequest("missing connection ids")
+	}
+	return validateProof(msg.ProofTry, msg.ProofHeight)
+}
+
+func (msg MsgConnectionOpenAck) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgConnectionOpenAck) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgConnectionOpenConfirm - open the connection ConnectionID, opened by
+// the counterparty as proven by ProofAck.
+type MsgConnectionOpenConfirm struct {
+	Signer       crypto.Address `json:"signer" yaml:"signer"`
+	ConnectionID string         `json:"connection_id" yaml:"connection_id"`
+	ProofAck     *merkle.Proof  `json:"proof_ack" yaml:"proof_ack"`
+	ProofHeight  int64          `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgConnectionOpenConfirm{}
+
+func (msg MsgConnectionOpenConfirm) Route() string { return RouterKey }
+func (msg MsgConnectionOpenConfirm) Type() string  { return "connection_open_confirm" }
+
+func (msg MsgConnectionOpenConfirm) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.ConnectionID == "" {
+		return std.ErrUnknownRequest("missing connection id")
+	}
+	return validateProof(msg.ProofAck, msg.ProofHeight)
+}
+
+func (msg MsgConnectionOpenConfirm) GetSignBytes() []byte { return getSignBytes(msg) }
+func (msg MsgConnectionOpenConfirm) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Signer}
+}
+
+// MsgChannelOpenInit - open Channel on the port PortID, to a port of the
+// counterparty chain.
+type MsgChannelOpenInit struct {
+	Signer  crypto.Address `json:"signer" yaml:"signer"`
+	PortID  string         `json:"port_id" yaml:"port_id"`
+	Channel ChannelEnd     `json:"channel" yaml:"channel"`
+}
+
+var _ std.Msg = MsgChannelOpenInit{}
+
+func (msg MsgChannelOpenInit) Route() string { return RouterKey }
+func (msg MsgChannelOpenInit) Type() string  { return "channel_open_init" }
+
+func (msg MsgChannelOpenInit) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.PortID == "" || msg.Channel.ConnectionID == "" || msg.Channel.Counterparty.PortID == "" {
+		return std.ErrUnknownRequest("missing port id, connection id or counterparty port id")
+	}
+	if msg.Channel.Ordering != OrderUnordered && msg.Channel.Ordering != OrderOrdered {
+		return std.ErrUnknownRequest("invalid channel ordering")
+	}
+	return nil
+}
+
+func (msg MsgChannelOpenInit) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgChannelOpenInit) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgChannelOpenTry - open Channel on the port PortID, to the channel of
+// its counterparty, proven by ProofInit to be initialized.
+type MsgChannelOpenTry struct {
+	Signer      crypto.Address `json:"signer" yaml:"signer"`
+	PortID      string         `json:"port_id" yaml:"port_id"`
+	Channel     ChannelEnd     `json:"channel" yaml:"channel"`
+	ProofInit   *merkle.Proof  `json:"proof_init" yaml:"proof_init"`
+	ProofHeight int64          `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgChannelOpenTry{}
+
+func (msg MsgChannelOpenTry) Route() string { return RouterKey }
+func (msg MsgChannelOpenTry) Type() string  { return "channel_open_try" }
+
+func (msg MsgChannelOpenTry) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.PortID == "" || msg.Channel.ConnectionID == "" ||
+		msg.Channel.Counterparty.PortID == "" || msg.Channel.Counterparty.ChannelID == "" {
+		return std.ErrUnknownRequest("missing port id, connection id or counterparty ids")
+	}
+	if msg.Channel.Ordering != OrderUnordered && msg.Channel.Ordering != OrderOrdered {
+		return std.ErrUnknownRequest("invalid channel ordering")
+	}
+	return validateProof(msg.ProofInit, msg.ProofHeight)
+}
+
+func (msg MsgChannelOpenTry) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgChannelOpenTry) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgChannelOpenAck - open the channel ChannelID of the port PortID,
+// initialized by this chain, to the channel CounterpartyChannelID, proven
+// by ProofTry to be opened by the counterparty.
+type MsgChannelOpenAck struct {
+	Signer                crypto.Address `json:"signer" yaml:"signer"`
+	PortID                string         `json:"port_id" yaml:"port_id"`
+	ChannelID             string         `json:"channel_id" yaml:"channel_id"`
+	CounterpartyChannelID string         `json:"counterparty_channel_id" yaml:"counterparty_channel_id"`
+	ProofTry              *merkle.Proof  `json:"proof_try" yaml:"proof_try"`
+	ProofHeight           int64          `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgChannelOpenAck{}
+
+func (msg MsgChannelOpenAck) Route() string { return RouterKey }
+func (msg MsgChannelOpenAck) Type() string  { return "channel_open_ack" }
+
+func (msg MsgChannelOpenAck) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.PortID == "" || msg.ChannelID == "" || msg.CounterpartyChannelID == "" {
+		return std.ErrUnknownRequest("missing port id or channel ids")
+	}
+	return validateProof(msg.ProofTry, msg.ProofHeight)
+}
+
+func (msg MsgChannelOpenAck) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgChannelOpenAck) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgChannelOpenConfirm - open the channel ChannelID of the port PortID,
+// opened by the counterparty as proven by ProofAck.
+type MsgChannelOpenConfirm struct {
+	Signer      crypto.Address `json:"signer" yaml:"signer"`
+	PortID      string         `json:"port_id" yaml:"port_id"`
+	ChannelID   string         `json:"channel_id" yaml:"channel_id"`
+	ProofAck    *merkle.Proof  `json:"proof_ack" yaml:"proof_ack"`
+	ProofHeight int64          `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgChannelOpenConfirm{}
+
+func (msg MsgChannelOpenConfirm) Route() string { return RouterKey }
+func (msg MsgChannelOpenConfirm) Type() string  { return "channel_open_confirm" }
+
+func (msg MsgChannelOpenConfirm) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if msg.PortID == "" || msg.ChannelID == "" {
+		return std.ErrUnknownRequest("missing port id or channel id")
+	}
+	return validateProof(msg.ProofAck, msg.ProofHeight)
+}
+
+func (msg MsgChannelOpenConfirm) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgChannelOpenConfirm) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgRecvPacket - receive Packet, proven by ProofCommitment to be sent by
+// the counterparty.
+type MsgRecvPacket struct {
+	Signer          crypto.Address `json:"signer" yaml:"signer"`
+	Packet          Packet         `json:"packet" yaml:"packet"`
+	ProofCommitment *merkle.Proof  `json:"proof_commitment" yaml:"proof_commitment"`
+	ProofHeight     int64          `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgRecvPacket{}
+
+func (msg MsgRecvPacket) Route() string { return RouterKey }
+func (msg MsgRecvPacket) Type() string  { return "recv_packet" }
+
+func (msg MsgRecvPacket) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if err := validatePacket(msg.Packet); err != nil {
+		return err
+	}
+	return validateProof(msg.ProofCommitment, msg.ProofHeight)
+}
+
+func (msg MsgRecvPacket) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgRecvPacket) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+// MsgAcknowledgement - handle the Acknowledgement of Packet, proven by
+// ProofAcked to be written by the counterparty.
+type MsgAcknowledgement struct {
+	Signer          crypto.Address `json:"signer" yaml:"signer"`
+	Packet          Packet         `json:"packet" yaml:"packet"`
+	Acknowledgement []byte         `json:"acknowledgement" yaml:"acknowledgement"`
+	ProofAcked      *merkle.Proof  `json:"proof_acked" yaml:"proof_acked"`
+	ProofHeight     int64          `json:"proof_height" yaml:"proof_height"`
+}
+
+var _ std.Msg = MsgAcknowledgement{}
+
+func (msg MsgAcknowledgement) Route() string { return RouterKey }
+func (msg MsgAcknowledgement) Type() string  { return "acknowledgement" }
+
+func (msg MsgAcknowledgement) ValidateBasic() error {
+	if msg.Signer.IsZero() {
+		return std.ErrInvalidAddress("missing signer address")
+	}
+	if err := validatePacket(msg.Packet); err != nil {
+		return err
+	}
+	return validateProof(msg.ProofAcked, msg.ProofHeight)
+}
+
+func (msg MsgAcknowledgement) GetSignBytes() []byte         { return getSignBytes(msg) }
+func (msg MsgAcknowledgement) GetSigners() []crypto.Address { return []crypto.Address{msg.Signer} }
+
+//----------------------------------------
+// misc
+
+func getSignBytes(msg std.Msg) []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+func validateProof(proof *merkle.Proof, height int64) error {
+	if proof == nil || len(proof.Ops) == 0 {
+		return std.ErrUnknownRequest("missing proof")
+	}
+	if height <= 0 {
+		return std.ErrUnknownRequest("proof height must be positive")
+	}
+	return nil
+}
+
+func validatePacket(packet Packet) error {
+	if packet.Sequence == 0 {
+		return std.ErrUnknownRequest("packet sequence must be positive")
+	}
+	if packet.SourcePort == "" || packet.SourceChannel == "" ||
+		packet.DestPort == "" || packet.DestChannel == "" {
+		return std.ErrUnknownRequest("missing packet ports or channels")
+	}
+	return nil
+}
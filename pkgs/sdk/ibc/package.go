@@ -0,0 +1,49 @@
+package ibc
+
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto/merkle"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/pkgs/sdk/ibc",
+	"ibc",
+	amino.GetCallersDirname(),
+).WithDependencies(
+	bft.Package,
+	merkle.Package,
+).WithTypes(
+	// state
+	ClientState{}, "ClientState",
+	ConsensusState{}, "ConsensusState",
+	ConnectionEnd{}, "ConnectionEnd",
+	ConnectionCounterparty{}, "ConnectionCounterparty",
+	ChannelEnd{}, "ChannelEnd",
+	ChannelCounterparty{}, "ChannelCounterparty",
+	Packet{}, "Packet",
+
+	// queries
+	IdentifiedClientState{}, "IdentifiedClientState",
+	IdentifiedConnection{}, "IdentifiedConnection",
+	IdentifiedChannel{}, "IdentifiedChannel",
+	PacketState{}, "PacketState",
+
+	// events
+	SendPacketEvent{}, "SendPacketEvent",
+	WriteAcknowledgementEvent{}, "WriteAcknowledgementEvent",
+
+	// msgs
+	MsgCreateClient{}, "MsgCreateClient",
+	MsgUpdateClient{}, "MsgUpdateClient",
+	MsgConnectionOpenInit{}, "MsgConnectionOpenInit",
+	MsgConnectionOpenTry{}, "MsgConnectionOpenTry",
+	MsgConnectionOpenAck{}, "MsgConnectionOpenAck",
+	MsgConnectionOpenConfirm{}, "MsgConnectionOpenConfirm",
+	MsgChannelOpenInit{}, "MsgChannelOpenInit",
+	MsgChannelOpenTry{}, "MsgChannelOpenTry",
+	MsgChannelOpenAck{}, "MsgChannelOpenAck",
+	MsgChannelOpenConfirm{}, "MsgChannelOpenConfirm",
+	MsgRecvPacket{}, "MsgRecvPacket",
+	MsgAcknowledgement{}, "MsgAcknowledgement",
+))
@@ -0,0 +1,185 @@
+package ibc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+)
+
+// State is the state of a connection or channel end in its handshake.
+type State uint8
+
+const (
+	StateUninitialized State = iota
+	StateInit                // opened by this chain
+	StateTryOpen             // opened by the counterparty, then by this chain
+	StateOpen                // opened by both chains
+)
+
+func (s State) String() string {
+	switch s {
+	case StateUninitialized:
+		return "UNINITIALIZED"
+	case StateInit:
+		return "INIT"
+	case StateTryOpen:
+		return "TRYOPEN"
+	case StateOpen:
+		return "OPEN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Order is the order in which the packets of a channel are received.
+type Order uint8
+
+const (
+	OrderNone      Order = iota
+	OrderUnordered       // in any order, each at most once
+	OrderOrdered         // in the order they are sent
+)
+
+func (o Order) String() string {
+	switch o {
+	case OrderUnordered:
+		return "UNORDERED"
+	case OrderOrdered:
+		return "ORDERED"
+	default:
+		return "NONE"
+	}
+}
+
+// ClientState is the state of a light client of the counterparty chain of
+// ChainID, which has verified its headers up to LatestHeight.
+type ClientState struct {
+	ChainID      string `json:"chain_id" yaml:"chain_id"`
+	LatestHeight int64  `json:"latest_height" yaml:"latest_height"`
+}
+
+// ConsensusState is the state of the counterparty chain at a height known
+// by a light client: the time and app hash of its header, i.e. the root of
+// its state after the previous height, and the hash of the validators which
+// sign the next header.
+type ConsensusState struct {
+	Time               time.Time `json:"time" yaml:"time"`
+	Root               []byte    `json:"root" yaml:"root"`
+	NextValidatorsHash []byte    `json:"next_validators_hash" yaml:"next_validators_hash"`
+}
+
+// ConnectionEnd is the end of a connection between this chain, which knows
+// the counterparty chain through its client ClientID, and the counterparty
+// chain.
+type ConnectionEnd struct {
+	State        State                  `json:"state" yaml:"state"`
+	ClientID     string                 `json:"client_id" yaml:"client_id"`
+	Counterparty ConnectionCounterparty `json:"counterparty" yaml:"counterparty"`
+}
+
+// ConnectionCounterparty is the end of a connection on the counterparty
+// chain, whose ibc state is in the store Prefix, e.g. "main".
+type ConnectionCounterparty struct {
+	ClientID     string `json:"client_id" yaml:"client_id"`
+	ConnectionID string `json:"connection_id" yaml:"connection_id"`
+	Prefix       string `json:"prefix" yaml:"prefix"`
+}
+
+// ChannelEnd is the end of a channel between a port of this chain and a
+// port of the counterparty chain, over the connection ConnectionID.
+type ChannelEnd struct {
+	State        State               `json:"state" yaml:"state"`
+	Ordering     Order               `json:"ordering" yaml:"ordering"`
+	Counterparty ChannelCounterparty `json:"counterparty" yaml:"counterparty"`
+	ConnectionID string              `json:"connection_id" yaml:"connection_id"`
+	Version      string              `json:"version" yaml:"version"`
+}
+
+// ChannelCounterparty is the end of a channel on the counterparty chain.
+type ChannelCounterparty struct {
+	PortID    string `json:"port_id" yaml:"port_id"`
+	ChannelID string `json:"channel_id" yaml:"channel_id"`
+}
+
+// Packet is the Data sent from a port of a chain to a port of another
+// chain, over a channel, which must be received before TimeoutHeight of
+// the receiving chain, unless it is zero.
+type Packet struct {
+	Sequence      uint64 `json:"sequence" yaml:"sequence"`
+	SourcePort    string `json:"source_port" yaml:"source_port"`
+	SourceChannel string `json:"source_channel" yaml:"source_channel"`
+	DestPort      string `json:"dest_port" yaml:"dest_port"`
+	DestChannel   string `json:"dest_channel" yaml:"dest_channel"`
+	Data          []byte `json:"data" yaml:"data"`
+	TimeoutHeight int64  `json:"timeout_height" yaml:"timeout_height"`
+}
+
+// PacketCommitment returns the commitment of packet stored by the sending
+// chain, the hash of its timeout height and of the hash of its data.
+func PacketCommitment(packet Packet) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(packet.TimeoutHeight))
+	hash := sha256.Sum256(packet.Data)
+	sum := sha256.Sum256(append(bz, hash[:]...))
+	return sum[:]
+}
+
+// AcknowledgementCommitment returns the commitment of ack stored by the
+// receiving chain.
+func AcknowledgementCommitment(ack []byte) []byte {
+	sum := sha256.Sum256(ack)
+	return sum[:]
+}
+
+// PacketHandler handles the packets of a port, e.g. of a token transfer
+// module.
+type PacketHandler interface {
+	// OnRecvPacket handles packet received by the port, and returns its
+	// acknowledgement, written for the sender.
+	OnRecvPacket(ctx sdk.Context, packet Packet) (ack []byte)
+
+	// OnAcknowledgementPacket handles the acknowledgement ack of packet
+	// sent by the port.
+	OnAcknowledgementPacket(ctx sdk.Context, packet Packet, ack []byte) error
+}
+
+// Identified values of the queries.
+
+type IdentifiedClientState struct {
+	ClientID    string      `json:"client_id" yaml:"client_id"`
+	ClientState ClientState `json:"client_state" yaml:"client_state"`
+}
+
+type IdentifiedConnection struct {
+	ConnectionID string        `json:"connection_id" yaml:"connection_id"`
+	Connection   ConnectionEnd `json:"connection" yaml:"connection"`
+}
+
+type IdentifiedChannel struct {
+	PortID    string     `json:"port_id" yaml:"port_id"`
+	ChannelID string     `json:"channel_id" yaml:"channel_id"`
+	Channel   ChannelEnd `json:"channel" yaml:"channel"`
+}
+
+type PacketState struct {
+	Sequence uint64 `json:"sequence" yaml:"sequence"`
+	Data     []byte `json:"data" yaml:"data"` // commitment
+}
+
+// SendPacketEvent is emitted when Packet is sent, for relayers.
+type SendPacketEvent struct {
+	Packet Packet `json:"packet" yaml:"packet"`
+}
+
+// WriteAcknowledgementEvent is emitted when Packet is received, with its
+// Acknowledgement, for relayers.
+type WriteAcknowledgementEvent struct {
+	Packet          Packet `json:"packet" yaml:"packet"`
+	Acknowledgement []byte `json:"acknowledgement" yaml:"acknowledgement"`
+}
+
+// Implements abci.Event.
+func (SendPacketEvent) AssertABCIEvent()           {}
+func (WriteAcknowledgementEvent) AssertABCIEvent() {}
@@ -1,6 +1,11 @@
 package sdk
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/store"
+)
 
 // An Invariant is a function which tests a particular invariant.
 // The invariant returns a descriptive message about what happened
@@ -20,3 +25,53 @@ type InvariantRegistry interface {
 func FormatInvariant(module, name, msg string) string {
 	return fmt.Sprintf("%s: %s invariant\n%s\n", module, name, msg)
 }
+
+// An invariant registered by a module.
+type invariantRoute struct {
+	moduleName string
+	route      string
+	invar      Invariant
+}
+
+var _ InvariantRegistry = (*BaseApp)(nil)
+
+// RegisterRoute implements InvariantRegistry.  The invariants are checked
+// at the end of blocks, see SetInvariantCheckPeriod.
+func (app *BaseApp) RegisterRoute(moduleName, route string, invar Invariant) {
+	app.invariants = append(app.invariants, invariantRoute{
+		moduleName: moduleName,
+		route:      route,
+		invar:      invar,
+	})
+}
+
+// AssertInvariants checks the registered invariants in ctx, in the order in
+// which they were registered, and returns the messages of the broken ones.
+// Changes of ctx by the invariants are discarded.
+func (app *BaseApp) AssertInvariants(ctx Context) (broken []string) {
+	ctx, _ = ctx.CacheContext()
+	ctx = ctx.WithGasMeter(store.NewInfiniteGasMeter())
+	for _, ir := range app.invariants {
+		if msg, stop := ir.invar(ctx); stop {
+			broken = append(broken, fmt.Sprintf("%s/%s: %s", ir.moduleName, ir.route, msg))
+		}
+	}
+	return broken
+}
+
+// Checks the invariants at the end of the block of height, which is not
+// committed if an invariant is broken and the app halts on broken
+// invariants.
+func (app *BaseApp) checkInvariants(ctx Context, height int64) {
+	broken := app.AssertInvariants(ctx)
+	if len(broken) == 0 {
+		return
+	}
+	for _, msg := range broken {
+		app.logger.Error("broken invariant", "height", height, "invariant", msg)
+	}
+	if app.haltOnBrokenInvariant {
+		panic(fmt.Sprintf("%d broken invariant(s) at height %d, halting before its commit, after that of app hash %X:\n%s",
+			len(broken), height, app.LastCommitID().Hash, strings.Join(broken, "\n")))
+	}
+}
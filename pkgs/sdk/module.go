@@ -0,0 +1,234 @@
+package sdk
+
+import (
+	"fmt"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+)
+
+// Module is a part of an application, whose handler processes the messages
+// and answers the queries of the route of its name.  A module may also take
+// part in the lifecycle of the chain, by implementing BeginBlockModule,
+// EndBlockModule, or GenesisModule, and register the paths of its queries,
+// by implementing QueryModule.
+type Module interface {
+	Name() string
+	Handler() Handler
+}
+
+// QueryModule is a module which registers the paths of its queries, e.g.
+// "bank/balances", instead of answering all the queries of its route.
+type QueryModule interface {
+	Module
+	RegisterQueries(qr QueryRouter)
+}
+
+// BeginBlockModule is a module which runs code before the txs of a block.
+type BeginBlockModule interface {
+	Module
+	BeginBlock(ctx Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock
+}
+
+// EndBlockModule is a module which runs code after the txs of a block, and
+// may update the validator set.
+type EndBlockModule interface {
+	Module
+	EndBlock(ctx Context, req abci.RequestEndBlock) abci.ResponseEndBlock
+}
+
+// GenesisModule is a module with a state at genesis, which can be exported
+// to start a new chain.
+type GenesisModule interface {
+	Module
+	InitGenesis(ctx Context, state interface{}) error
+	ExportGenesis(ctx Context) interface{}
+}
+
+// ModuleManager runs the lifecycle of the modules of an application.  By
+// default, the modules begin and end blocks, and are initialized and
+// exported, in the order in which they were given.
+type ModuleManager struct {
+	modules map[string]Module
+	order   []string
+
+	orderBeginBlockers []string
+	orderEndBlockers   []string
+	orderInitGenesis   []string
+	orderExportGenesis []string
+}
+
+// NewModuleManager returns a manager of modules, whose names must be
+// unique.
+func NewModuleManager(modules ...Module) *ModuleManager {
+	mm := &ModuleManager{
+		modules: make(map[string]Module),
+	}
+	for _, module := range modules {
+		name := module.Name()
+		if mm.modules[name] != nil {
+			panic(fmt.Sprintf("module %s already registered", name))
+		}
+		mm.modules[name] = module
+		mm.order = append(mm.order, name)
+	}
+	mm.orderBeginBlockers = mm.filter(isBeginBlockModule)
+	mm.orderEndBlockers = mm.filter(isEndBlockModule)
+	mm.orderInitGenesis = mm.filter(isGenesisModule)
+	mm.orderExportGenesis = mm.filter(isGenesisModule)
+	return mm
+}
+
+func isBeginBlockModule(m Module) bool { _, ok := m.(BeginBlockModule); return ok }
+func isEndBlockModule(m Module) bool   { _, ok := m.(EndBlockModule); return ok }
+func isGenesisModule(m Module) bool    { _, ok := m.(GenesisModule); return ok }
+
+// Returns the names of the modules for which fn is true, in order.
+func (mm *ModuleManager) filter(fn func(Module) bool) []string {
+	names := []string{}
+	for _, name := range mm.order {
+		if fn(mm.modules[name]) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Checks that names are those of all the modules for which fn is true.
+func (mm *ModuleManager) checkOrder(what string, names []string, fn func(Module) bool) {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		module := mm.modules[name]
+		switch {
+		case module == nil:
+			panic(fmt.Sprintf("%s: unknown module %s", what, name))
+		case !fn(module):
+			panic(fmt.Sprintf("%s: module %s does not implement it", what, name))
+		case seen[name]:
+			panic(fmt.Sprintf("%s: duplicate module %s", what, name))
+		}
+		seen[name] = true
+	}
+	for _, name := range mm.filter(fn) {
+		if !seen[name] {
+			panic(fmt.Sprintf("%s: missing module %s", what, name))
+		}
+	}
+}
+
+// SetOrderBeginBlockers sets the order in which modules begin blocks.
+func (mm *ModuleManager) SetOrderBeginBlockers(names ...string) {
+	mm.checkOrder("SetOrderBeginBlockers", names, isBeginBlockModule)
+	mm.orderBeginBlockers = names
+}
+
+// SetOrderEndBlockers sets the order in which modules end blocks.
+func (mm *ModuleManager) SetOrderEndBlockers(names ...string) {
+	mm.checkOrder("SetOrderEndBlockers", names, isEndBlockModule)
+	mm.orderEndBlockers = names
+}
+
+// SetOrderInitGenesis sets the order in which modules are initialized at
+// genesis, e.g. for a module to use accounts initialized by another.
+func (mm *ModuleManager) SetOrderInitGenesis(names ...string) {
+	mm.checkOrder("SetOrderInitGenesis", names, isGenesisModule)
+	mm.orderInitGenesis = names
+}
+
+// SetOrderExportGenesis sets the order in which modules are exported.
+func (mm *ModuleManager) SetOrderExportGenesis(names ...string) {
+	mm.checkOrder("SetOrderExportGenesis", names, isGenesisModule)
+	mm.orderExportGenesis = names
+}
+
+// Module returns the module with name, or nil.
+func (mm *ModuleManager) Module(name string) Module {
+	return mm.modules[name]
+}
+
+// RegisterRoutes adds the route of each module to rtr.
+func (mm *ModuleManager) RegisterRoutes(rtr Router) {
+	for _, name := range mm.order {
+		rtr.AddRoute(name, mm.modules[name].Handler())
+	}
+}
+
+// RegisterQueryRoutes adds the query routes of each module to qr: those it
+// registers if a QueryModule, or else its name, answered by its handler.
+func (mm *ModuleManager) RegisterQueryRoutes(qr QueryRouter) {
+	for _, name := range mm.order {
+		module := mm.modules[name]
+		if qm, ok := module.(QueryModule); ok {
+			qm.RegisterQueries(qr)
+		} else {
+			qr.AddRoute(name, module.Handler().Query)
+		}
+	}
+}
+
+// BeginBlock is a BeginBlocker which begins the block in each module, and
+// returns their events.
+func (mm *ModuleManager) BeginBlock(ctx Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	res := abci.ResponseBeginBlock{}
+	for _, name := range mm.orderBeginBlockers {
+		mres := mm.modules[name].(BeginBlockModule).BeginBlock(ctx, req)
+		res.Events = append(res.Events, mres.Events...)
+	}
+	return res
+}
+
+// EndBlock is an EndBlocker which ends the block in each module, and returns
+// their events.  At most one module may update the validator set, or the
+// consensus params.
+func (mm *ModuleManager) EndBlock(ctx Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	res := abci.ResponseEndBlock{}
+	var valsetModule, paramsModule string
+	for _, name := range mm.orderEndBlockers {
+		mres := mm.modules[name].(EndBlockModule).EndBlock(ctx, req)
+		res.Events = append(res.Events, mres.Events...)
+		if len(mres.ValidatorUpdates) > 0 {
+			if valsetModule != "" {
+				panic(fmt.Sprintf("validator set updated by modules %s and %s", valsetModule, name))
+			}
+			valsetModule = name
+			res.ValidatorUpdates = mres.ValidatorUpdates
+		}
+		if mres.ConsensusParams != nil {
+			if paramsModule != "" {
+				panic(fmt.Sprintf("consensus params updated by modules %s and %s", paramsModule, name))
+			}
+			paramsModule = name
+			res.ConsensusParams = mres.ConsensusParams
+		}
+	}
+	return res
+}
+
+// InitGenesis initializes each module with its state in genesis, by name.
+// Modules without a state in genesis are not initialized.
+func (mm *ModuleManager) InitGenesis(ctx Context, genesis map[string]interface{}) error {
+	for name := range genesis {
+		if module := mm.modules[name]; module == nil || !isGenesisModule(module) {
+			return fmt.Errorf("genesis state of unknown module %s", name)
+		}
+	}
+	for _, name := range mm.orderInitGenesis {
+		state, ok := genesis[name]
+		if !ok {
+			continue
+		}
+		if err := mm.modules[name].(GenesisModule).InitGenesis(ctx, state); err != nil {
+			return fmt.Errorf("initializing module %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ExportGenesis returns the state of each module, by name, from which
+// InitGenesis can start a new chain.
+func (mm *ModuleManager) ExportGenesis(ctx Context) map[string]interface{} {
+	genesis := make(map[string]interface{})
+	for _, name := range mm.orderExportGenesis {
+		genesis[name] = mm.modules[name].(GenesisModule).ExportGenesis(ctx)
+	}
+	return genesis
+}
@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+)
+
+// testModule records the calls of its lifecycle in calls.
+type testModule struct {
+	name    string
+	calls   *[]string
+	valset  bool
+	genesis interface{}
+}
+
+func (tm *testModule) Name() string     { return tm.name }
+func (tm *testModule) Handler() Handler { return nopTestHandler{} }
+
+func (tm *testModule) BeginBlock(ctx Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	*tm.calls = append(*tm.calls, "begin "+tm.name)
+	res := abci.ResponseBeginBlock{}
+	res.Events = []abci.Event{abci.EventString(tm.name)}
+	return res
+}
+
+func (tm *testModule) EndBlock(ctx Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	*tm.calls = append(*tm.calls, "end "+tm.name)
+	res := abci.ResponseEndBlock{}
+	if tm.valset {
+		res.ValidatorUpdates = []abci.ValidatorUpdate{{Power: 1}}
+	}
+	return res
+}
+
+func (tm *testModule) InitGenesis(ctx Context, state interface{}) error {
+	*tm.calls = append(*tm.calls, "init "+tm.name)
+	tm.genesis = state
+	return nil
+}
+
+func (tm *testModule) ExportGenesis(ctx Context) interface{} {
+	return tm.genesis
+}
+
+// routeModule only has a handler.
+type routeModule struct{}
+
+func (routeModule) Name() string     { return "route" }
+func (routeModule) Handler() Handler { return nopTestHandler{} }
+
+func TestModuleManager(t *testing.T) {
+	calls := []string{}
+	a := &testModule{name: "a", calls: &calls}
+	b := &testModule{name: "b", calls: &calls, valset: true}
+	mm := NewModuleManager(a, b, routeModule{})
+
+	rtr := NewRouter()
+	mm.RegisterRoutes(rtr)
+	require.NotNil(t, rtr.Route("a"))
+	require.NotNil(t, rtr.Route("route"))
+	qr := NewQueryRouter()
+	mm.RegisterQueryRoutes(qr)
+	require.Equal(t, []string{"a", "b", "route"}, qr.Routes())
+
+	// modules are called in the order in which they were given by default.
+	res := mm.BeginBlock(Context{}, abci.RequestBeginBlock{})
+	require.Equal(t, []abci.Event{abci.EventString("a"), abci.EventString("b")}, res.Events)
+	mm.SetOrderEndBlockers("b", "a")
+	eres := mm.EndBlock(Context{}, abci.RequestEndBlock{})
+	require.Len(t, eres.ValidatorUpdates, 1)
+	require.Equal(t, []string{"begin a", "begin b", "end b", "end a"}, calls)
+
+	// only one module may update the validator set.
+	a.valset = true
+	require.Panics(t, func() { mm.EndBlock(Context{}, abci.RequestEndBlock{}) })
+
+	// orders must list all the modules implementing the interface.
+	require.Panics(t, func() { mm.SetOrderBeginBlockers("a") })
+	require.Panics(t, func() { mm.SetOrderBeginBlockers("a", "b", "route") })
+	require.Panics(t, func() { mm.SetOrderBeginBlockers("a", "b", "c") })
+	require.Panics(t, func() { NewModuleManager(a, a) })
+}
+
+func TestModuleManagerGenesis(t *testing.T) {
+	calls := []string{}
+	a := &testModule{name: "a", calls: &calls}
+	b := &testModule{name: "b", calls: &calls}
+	mm := NewModuleManager(a, b, routeModule{})
+	mm.SetOrderInitGenesis("b", "a")
+
+	err := mm.InitGenesis(Context{}, map[string]interface{}{"a": 1, "b": 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"init b", "init a"}, calls)
+	require.Equal(t, map[string]interface{}{"a": 1, "b": 2}, mm.ExportGenesis(Context{}))
+
+	// states of modules without genesis are rejected.
+	err = mm.InitGenesis(Context{}, map[string]interface{}{"route": 1})
+	require.Error(t, err)
+}
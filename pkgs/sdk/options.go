@@ -44,6 +44,31 @@ func SetHaltTime(haltTime uint64) func(*BaseApp) {
 	return func(bap *BaseApp) { bap.setHaltTime(haltTime) }
 }
 
+// SetRecentTxWindow returns a BaseApp option function that makes CheckTx
+// reject the txs with the canonical hash of a tx delivered in the last
+// blocks blocks, e.g. copies of a tx with malleated signatures.
+//
+// NOTE: delivered txs are only remembered in memory, so the window is empty
+// after a restart.
+func SetRecentTxWindow(blocks int64) func(*BaseApp) {
+	return func(bap *BaseApp) { bap.setRecentTxWindow(blocks) }
+}
+
+// SetInvariantCheckPeriod returns a BaseApp option function that checks the
+// registered invariants at the end of every blocks blocks, or never if
+// blocks is 0.
+func SetInvariantCheckPeriod(blocks int64) func(*BaseApp) {
+	return func(bap *BaseApp) { bap.setInvariantCheckPeriod(blocks) }
+}
+
+// SetHaltOnBrokenInvariant returns a BaseApp option function that makes a
+// broken invariant halt the chain, with a panic at the end of the block
+// with the messages of the broken invariants, before the block is
+// committed.  Otherwise broken invariants are only logged.
+func SetHaltOnBrokenInvariant(halt bool) func(*BaseApp) {
+	return func(bap *BaseApp) { bap.setHaltOnBrokenInvariant(halt) }
+}
+
 func (app *BaseApp) SetName(name string) {
 	if app.sealed {
 		panic("SetName() on sealed BaseApp")
@@ -94,9 +119,48 @@ func (app *BaseApp) SetEndBlocker(endBlocker EndBlocker) {
 	app.endBlocker = endBlocker
 }
 
+// SetModuleManager adds the routes and query routes of the modules of mm,
+// which also begin and end blocks, and are initialized and exported at
+// genesis with InitGenesis and ExportGenesis.
+func (app *BaseApp) SetModuleManager(mm *ModuleManager) {
+	if app.sealed {
+		panic("SetModuleManager() on sealed BaseApp")
+	}
+	mm.RegisterRoutes(app.router)
+	mm.RegisterQueryRoutes(app.queryRouter)
+	app.beginBlocker = mm.BeginBlock
+	app.endBlocker = mm.EndBlock
+	app.moduleManager = mm
+}
+
 func (app *BaseApp) SetAnteHandler(ah AnteHandler) {
 	if app.sealed {
 		panic("SetAnteHandler() on sealed BaseApp")
 	}
 	app.anteHandler = ah
 }
+
+func (app *BaseApp) SetGasRefunder(gr GasRefunder) {
+	if app.sealed {
+		panic("SetGasRefunder() on sealed BaseApp")
+	}
+	app.gasRefunder = gr
+}
+
+// SetTxHasher sets the canonical hasher of the txs, returned by CheckTx and
+// DeliverTx, and by which the duplicate txs are rejected.
+func (app *BaseApp) SetTxHasher(th TxHasher) {
+	if app.sealed {
+		panic("SetTxHasher() on sealed BaseApp")
+	}
+	app.txHasher = th
+}
+
+// SetSystemTxFilter sets the filter of the system txs, which are in the
+// system lane of the mempool.
+func (app *BaseApp) SetSystemTxFilter(f SystemTxFilter) {
+	if app.sealed {
+		panic("SetSystemTxFilter() on sealed BaseApp")
+	}
+	app.systemTxFilter = f
+}
@@ -2,6 +2,11 @@ package sdk
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/std"
 )
 
 type router struct {
@@ -35,3 +40,72 @@ func (rtr *router) AddRoute(path string, h Handler) Router {
 func (rtr *router) Route(path string) Handler {
 	return rtr.routes[path]
 }
+
+//----------------------------------------
+// QueryRouter
+
+type queryRouter struct {
+	routes map[string]Querier
+}
+
+var _ QueryRouter = NewQueryRouter()
+
+// NewQueryRouter returns a reference to a new query router.
+func NewQueryRouter() *queryRouter { // nolint: golint
+	return &queryRouter{
+		routes: make(map[string]Querier),
+	}
+}
+
+// AddRoute adds a route path to the query router with a given querier.  The
+// route is a path of alphanumeric components, e.g. "vm/qrender"; those of
+// the app itself start with ".", e.g. ".app/simulate".  A route also answers
+// the paths under it, e.g. "auth/accounts" answers "auth/accounts/<addr>".
+func (qrt *queryRouter) AddRoute(path string, q Querier) QueryRouter {
+	if !isQueryRoute(path) {
+		panic(fmt.Sprintf("invalid query route %q", path))
+	}
+	if qrt.routes[path] != nil {
+		panic(fmt.Sprintf("query route %s has already been initialized", path))
+	}
+
+	qrt.routes[path] = q
+	return qrt
+}
+
+// Route returns the route of a query path, i.e. the path itself or the
+// deepest route under which it is, and its querier, or nil if none.
+func (qrt *queryRouter) Route(path string) (string, Querier) {
+	route := strings.TrimPrefix(path, "/")
+	for {
+		if q := qrt.routes[route]; q != nil {
+			return route, q
+		}
+		i := strings.LastIndexByte(route, '/')
+		if i < 0 {
+			return "", nil
+		}
+		route = route[:i]
+	}
+}
+
+// Routes returns the routes of the query router, sorted.
+func (qrt *queryRouter) Routes() []string {
+	routes := make([]string, 0, len(qrt.routes))
+	for route := range qrt.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// RouteQuery answers req with the querier of its route in qr, or with an
+// std.UnknownQueryPathError listing the routes of qr.  It is the Query() of
+// the handlers of modules which register their queries.
+func RouteQuery(qr QueryRouter, ctx Context, req abci.RequestQuery) abci.ResponseQuery {
+	_, querier := qr.Route(req.Path)
+	if querier == nil {
+		return ABCIResponseQueryFromError(std.ErrUnknownQueryPath(req.Path, qr.Routes()))
+	}
+	return querier(ctx, req)
+}
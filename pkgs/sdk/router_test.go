@@ -5,7 +5,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/gnolang/gno/pkgs/amino"
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/std"
 )
 
 type nopTestHandler struct{}
@@ -35,3 +37,48 @@ func TestRouter(t *testing.T) {
 		rtr.AddRoute("testRoute", nopTestHandler{})
 	})
 }
+
+func TestQueryRouter(t *testing.T) {
+	qr := NewQueryRouter()
+	nop := func(_ Context, _ abci.RequestQuery) abci.ResponseQuery {
+		return abci.ResponseQuery{}
+	}
+
+	// require panic on invalid route
+	require.Panics(t, func() { qr.AddRoute("test/*", nop) })
+	require.Panics(t, func() { qr.AddRoute("/test", nop) })
+	require.Panics(t, func() { qr.AddRoute("test/", nop) })
+
+	qr.AddRoute("test/route", nop)
+	qr.AddRoute(".app/route", nop)
+
+	// require panic on duplicate route
+	require.Panics(t, func() { qr.AddRoute("test/route", nop) })
+
+	// paths are routed to their deepest route.
+	for path, route := range map[string]string{
+		"test/route":         "test/route",
+		"/test/route":        "test/route",
+		"test/route/arg/arg": "test/route",
+		".app/route":         ".app/route",
+		"test/routes":        "",
+		"test":               "",
+		"":                   "",
+	} {
+		r, q := qr.Route(path)
+		require.Equal(t, route, r, path)
+		require.Equal(t, route != "", q != nil, path)
+	}
+	require.Equal(t, []string{".app/route", "test/route"}, qr.Routes())
+
+	// unknown paths are answered with the routes.
+	res := RouteQuery(qr, Context{}, abci.RequestQuery{Path: "test/unknown"})
+	require.Equal(t, std.UnknownQueryPathError{
+		Path:   "test/unknown",
+		Routes: []string{".app/route", "test/route"},
+	}, res.Error)
+	bz := amino.MustMarshalAny(res.Error)
+	var err abci.Error
+	amino.MustUnmarshalAny(bz, &err)
+	require.Equal(t, res.Error, err)
+}
@@ -0,0 +1,54 @@
+package simulation
+
+import (
+	"github.com/gnolang/gno/pkgs/sdk"
+)
+
+type invariantRoute struct {
+	moduleName string
+	route      string
+	invar      sdk.Invariant
+}
+
+// InvariantRegistry is the sdk.InvariantRegistry of a simulation, whose
+// invariants are checked at the end of each block.
+type InvariantRegistry struct {
+	routes []invariantRoute
+}
+
+var _ sdk.InvariantRegistry = &InvariantRegistry{}
+
+// NewInvariantRegistry returns a new, empty InvariantRegistry.
+func NewInvariantRegistry() *InvariantRegistry {
+	return &InvariantRegistry{}
+}
+
+// RegisterRoute implements sdk.InvariantRegistry.
+func (ir *InvariantRegistry) RegisterRoute(moduleName, route string, invar sdk.Invariant) {
+	ir.routes = append(ir.routes, invariantRoute{
+		moduleName: moduleName,
+		route:      route,
+		invar:      invar,
+	})
+}
+
+// Routes returns the routes of the invariants, as "<module>/<route>", in
+// the order in which they were registered.
+func (ir *InvariantRegistry) Routes() []string {
+	routes := make([]string, len(ir.routes))
+	for i, rt := range ir.routes {
+		routes[i] = rt.moduleName + "/" + rt.route
+	}
+	return routes
+}
+
+// Assert checks the invariants in order, and returns the message of the
+// first broken invariant, if any.
+func (ir *InvariantRegistry) Assert(ctx sdk.Context) (msg string, broken bool) {
+	for _, rt := range ir.routes {
+		if msg, broken := rt.invar(ctx); broken {
+			return msg, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,66 @@
+package simulation
+
+import (
+	"math/rand"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// An Operation returns a random msg of a module, to be signed by one of
+// the signers of env, with the state of ctx, which is that of the last
+// block.  An error stops the simulation, e.g. for an unexpected state.
+type Operation func(r *rand.Rand, ctx sdk.Context, env *Env) (OperationMsg, error)
+
+// OperationMsg is the msg of an operation, and its signer.  A nil Msg
+// skips the operation, e.g. if no signer has the coins it needs.
+type OperationMsg struct {
+	Msg     std.Msg
+	Signer  Account
+	Comment string // why the operation is skipped, if it is
+
+	// whether the tx of the msg may fail, which otherwise stops the
+	// simulation.
+	MayFail bool
+}
+
+// NewOperationMsg returns the msg of an operation, signed by signer.
+func NewOperationMsg(msg std.Msg, signer Account) OperationMsg {
+	return OperationMsg{
+		Msg:    msg,
+		Signer: signer,
+	}
+}
+
+// NoOpMsg returns the msg of a skipped operation.
+func NoOpMsg(comment string) OperationMsg {
+	return OperationMsg{
+		Comment: comment,
+	}
+}
+
+// WeightedOperation is an operation, and its weight among the operations
+// of a simulation.
+type WeightedOperation struct {
+	Weight int
+	Op     Operation
+}
+
+// WeightedOperations is a list of weighted operations.
+type WeightedOperations []WeightedOperation
+
+// Returns a random operation of wops, chosen by weight.
+func (wops WeightedOperations) random(r *rand.Rand) Operation {
+	total := 0
+	for _, wop := range wops {
+		total += wop.Weight
+	}
+	n := r.Intn(total)
+	for _, wop := range wops {
+		if n < wop.Weight {
+			return wop.Op
+		}
+		n -= wop.Weight
+	}
+	panic("should not happen")
+}
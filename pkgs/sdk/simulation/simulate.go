@@ -0,0 +1,209 @@
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/auth"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// time between the blocks of a simulation.
+const blockInterval = 5 * time.Second
+
+// Params are the parameters of a simulation, which is reproduced by
+// running it again with the same parameters, and in particular Seed.
+type Params struct {
+	Seed          int64
+	ChainID       string
+	GenesisTime   time.Time
+	NumBlocks     int
+	BlockSize     int // maximum number of txs of a block
+	NumAccounts   int
+	NumValidators int
+	Fee           std.Fee // fee of each tx
+}
+
+// DefaultParams returns the default parameters of a simulation with seed.
+func DefaultParams(seed int64) Params {
+	return Params{
+		Seed:          seed,
+		ChainID:       "simulation",
+		GenesisTime:   time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		NumBlocks:     50,
+		BlockSize:     20,
+		NumAccounts:   20,
+		NumValidators: 4,
+		Fee:           std.NewFee(2000000, std.NewCoin("ugnot", 1000000)),
+	}
+}
+
+// App is an application under simulation.
+type App struct {
+	*sdk.BaseApp
+
+	// AccountKeeper reads the account numbers and sequences with which the
+	// txs of operations are signed.
+	AccountKeeper auth.AccountKeeperI
+
+	// AppState returns the state of the app at genesis, e.g. with the
+	// balances of the accounts of the simulation.
+	AppState func(r *rand.Rand, accs []Account) interface{}
+
+	Operations WeightedOperations
+	Invariants *InvariantRegistry
+}
+
+// Result is the result of a simulation.
+type Result struct {
+	Height  int64
+	AppHash []byte
+
+	// numbers of delivered txs, by route and type of msg, e.g.
+	// "bank/send", and of failed ones, e.g. "bank/send (failed)", and of
+	// skipped operations, by comment.
+	Ops map[string]int
+}
+
+// Simulate runs a simulation of app with params, and writes its progress
+// to w.  Each block has up to params.BlockSize txs of random operations of
+// app, and the invariants of app are checked once it is committed.  The
+// first block is empty, as it commits the genesis state, which the
+// operations read.  The returned error, e.g. of a broken invariant, has
+// the seed with which the simulation reproduces it.
+func Simulate(w io.Writer, app App, params Params) (res Result, err error) {
+	r := rand.New(rand.NewSource(params.Seed))
+	res.Ops = make(map[string]int)
+	height := int64(0) // of the block being simulated, or 0 at genesis.
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+		if err != nil {
+			err = fmt.Errorf("simulation with seed %d failed at height %d, rerun with the same seed to reproduce: %w",
+				params.Seed, height, err)
+		}
+	}()
+
+	accs := RandomAccounts(r, params.NumAccounts)
+	vals := RandomValidators(r, params.NumValidators, 10)
+	env := newEnv(params.ChainID, params.Fee, accs, vals)
+	updates := make([]abci.ValidatorUpdate, len(vals))
+	for i, val := range vals {
+		updates[i] = val.ABCIValidatorUpdate()
+	}
+	consParams := bft.DefaultConsensusParams()
+	app.InitChain(abci.RequestInitChain{
+		Time:            params.GenesisTime,
+		ChainID:         params.ChainID,
+		ConsensusParams: &consParams,
+		Validators:      updates,
+		AppState:        app.AppState(r, accs),
+	})
+	fmt.Fprintf(w, "Simulating %d blocks with seed %d, %d accounts and %d validators, and invariants %v.\n",
+		params.NumBlocks, params.Seed, len(accs), len(vals), app.Invariants.Routes())
+
+	for height = 1; height <= int64(params.NumBlocks); height++ {
+		env.Height = height
+		env.Signers = nil
+		proposer := env.Validators[r.Intn(len(env.Validators))]
+		app.BeginBlock(abci.RequestBeginBlock{
+			Header: &bft.Header{
+				ChainID:         params.ChainID,
+				Height:          height,
+				Time:            params.GenesisTime.Add(time.Duration(height) * blockInterval),
+				ProposerAddress: proposer.Address(),
+			},
+		})
+
+		if height > 1 {
+			ctx := app.NewCommittedContext()
+			for _, acc := range env.Accounts {
+				if a := app.AccountKeeper.GetAccount(ctx, acc.Address); a != nil && env.Spendable(a.GetCoins()) != nil {
+					env.Signers = append(env.Signers, acc)
+				}
+			}
+			for i := 0; i < params.BlockSize && len(env.Signers) > 0; i++ {
+				op := app.Operations.random(r)
+				opMsg, err := op(r, ctx, env)
+				if err != nil {
+					return res, err
+				}
+				if opMsg.Msg == nil {
+					res.Ops["skipped: "+opMsg.Comment]++
+					continue
+				}
+				if err := deliverOperationMsg(app, ctx, env, opMsg, res.Ops); err != nil {
+					return res, err
+				}
+			}
+		}
+
+		endRes := app.EndBlock(abci.RequestEndBlock{Height: height})
+		if err := env.updateValidators(endRes.ValidatorUpdates); err != nil {
+			return res, err
+		}
+		commitRes := app.Commit()
+		res.Height, res.AppHash = height, commitRes.Data
+
+		if msg, broken := app.Invariants.Assert(app.NewCommittedContext()); broken {
+			return res, fmt.Errorf("broken invariant:\n%s", msg)
+		}
+	}
+
+	fmt.Fprintf(w, "Simulated %d blocks, with app hash %X:\n", res.Height, res.AppHash)
+	names := make([]string, 0, len(res.Ops))
+	for name := range res.Ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s: %d\n", name, res.Ops[name])
+	}
+	return res, nil
+}
+
+// Signs and delivers the tx of opMsg, with the account number and sequence
+// of its signer in ctx.
+func deliverOperationMsg(app App, ctx sdk.Context, env *Env, opMsg OperationMsg, ops map[string]int) error {
+	signer := opMsg.Signer
+	acc := app.AccountKeeper.GetAccount(ctx, signer.Address)
+	if acc == nil {
+		return fmt.Errorf("unknown signer %s", signer.Address)
+	}
+	msgs := []std.Msg{opMsg.Msg}
+	signBytes := std.SignBytes(env.ChainID, acc.GetAccountNumber(), acc.GetSequence(), env.Fee, msgs, "")
+	sig, err := signer.PrivKey.Sign(signBytes)
+	if err != nil {
+		return err
+	}
+	tx := std.Tx{
+		Msgs:       msgs,
+		Fee:        env.Fee,
+		Signatures: []std.Signature{{PubKey: signer.PubKey, Signature: sig}},
+	}
+	env.removeSigner(signer.Address)
+
+	name := opMsg.Msg.Route() + "/" + opMsg.Msg.Type()
+	res := app.DeliverTx(abci.RequestDeliverTx{Tx: amino.MustMarshal(tx)})
+	if res.IsErr() {
+		if !opMsg.MayFail {
+			return fmt.Errorf("tx of %s failed: %s\n%s", name, res.Log, amino.MustMarshalJSON(tx))
+		}
+		name += " (failed)"
+	}
+	ops[name]++
+	return nil
+}
+
+func errUnknownValidator(addr crypto.Address) error {
+	return fmt.Errorf("validator update of unknown validator %s", addr)
+}
@@ -0,0 +1,129 @@
+package simulation_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/log"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/auth"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/sdk/simulation"
+	"github.com/gnolang/gno/pkgs/sdk/valset"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/dbadapter"
+	"github.com/gnolang/gno/pkgs/store/iavl"
+)
+
+var initialCoins = std.NewCoins(std.NewCoin("ugnot", 100000000), std.NewCoin("foo", 1000))
+
+// Returns an app of the auth, bank and valset modules, whose accounts
+// start with initialCoins.
+func newTestApp(t *testing.T, params simulation.Params) simulation.App {
+	t.Helper()
+
+	db := dbm.NewMemDB()
+	mainKey := store.NewStoreKey("main")
+	baseKey := store.NewStoreKey("base")
+	baseApp := sdk.NewBaseApp("simulation", log.NewNopLogger(), db, baseKey, mainKey)
+	baseApp.MountStoreWithDB(mainKey, iavl.StoreConstructor, db)
+	baseApp.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+
+	acctKpr := auth.NewAccountKeeper(mainKey, std.ProtoBaseAccount)
+	bankKpr := bank.NewBankKeeper(acctKpr)
+	valsetKpr := valset.NewValsetKeeper(mainKey)
+
+	baseApp.SetInitChainer(func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+		valsetKpr.InitValidators(ctx, req.Validators)
+		for _, addr := range req.AppState.([]crypto.Address) {
+			acctKpr.SetAccount(ctx, acctKpr.NewAccountWithAddress(ctx, addr))
+			require.NoError(t, bankKpr.SetCoins(ctx, addr, initialCoins))
+		}
+		return abci.ResponseInitChain{Validators: req.Validators}
+	})
+	anteHandler := auth.NewAnteHandler(acctKpr, bankKpr, auth.DefaultSigVerificationGasConsumer, auth.AnteOptions{})
+	baseApp.SetAnteHandler(func(ctx sdk.Context, tx std.Tx, simulate bool) (sdk.Context, sdk.Result, bool) {
+		ctx = ctx.WithValue(auth.AuthParamsContextKey{}, auth.DefaultParams())
+		return anteHandler(ctx, tx, simulate)
+	})
+	baseApp.SetModuleManager(sdk.NewModuleManager(
+		auth.NewModule(acctKpr),
+		bank.NewModule(bankKpr),
+		valset.NewModule(valsetKpr),
+	))
+	require.NoError(t, baseApp.LoadLatestVersion())
+
+	supply := std.Coins{}
+	for i := 0; i < params.NumAccounts; i++ {
+		supply = supply.Add(initialCoins)
+	}
+	invariants := simulation.NewInvariantRegistry()
+	bank.RegisterInvariants(invariants, acctKpr)
+	bank.RegisterSupplyInvariant(invariants, acctKpr, supply)
+
+	return simulation.App{
+		BaseApp:       baseApp,
+		AccountKeeper: acctKpr,
+		AppState: func(r *rand.Rand, accs []simulation.Account) interface{} {
+			addrs := make([]crypto.Address, len(accs))
+			for i, acc := range accs {
+				addrs[i] = acc.Address
+			}
+			return addrs
+		},
+		Operations: simulation.WeightedOperations{
+			{Weight: 10, Op: bank.SimulateMsgSend(bankKpr)},
+			{Weight: 1, Op: valset.SimulateMsgRotateKey()},
+		},
+		Invariants: invariants,
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	params := simulation.DefaultParams(42)
+	var out bytes.Buffer
+	res, err := simulation.Simulate(&out, newTestApp(t, params), params)
+	require.NoError(t, err, out.String())
+	assert.Equal(t, int64(params.NumBlocks), res.Height)
+	assert.NotZero(t, res.Ops["bank/send"])
+	assert.NotZero(t, res.Ops["valset/rotate_key"])
+	assert.Contains(t, out.String(), "with seed 42")
+}
+
+// A simulation is reproduced by its seed.
+func TestSimulateSeed(t *testing.T) {
+	params := simulation.DefaultParams(42)
+	params.NumBlocks = 20
+	res, err := simulation.Simulate(&bytes.Buffer{}, newTestApp(t, params), params)
+	require.NoError(t, err)
+	res2, err := simulation.Simulate(&bytes.Buffer{}, newTestApp(t, params), params)
+	require.NoError(t, err)
+	assert.Equal(t, res.AppHash, res2.AppHash)
+	assert.Equal(t, res.Ops, res2.Ops)
+
+	params.Seed = 43
+	res3, err := simulation.Simulate(&bytes.Buffer{}, newTestApp(t, params), params)
+	require.NoError(t, err)
+	assert.NotEqual(t, res.AppHash, res3.AppHash)
+}
+
+func TestSimulateBrokenInvariant(t *testing.T) {
+	params := simulation.DefaultParams(7)
+	app := newTestApp(t, params)
+	app.Invariants.RegisterRoute("test", "height", func(ctx sdk.Context) (string, bool) {
+		return "height is 5", ctx.BlockHeight() == 5
+	})
+	res, err := simulation.Simulate(&bytes.Buffer{}, app, params)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "seed 7 failed at height 5")
+	assert.Contains(t, err.Error(), "height is 5")
+	assert.Equal(t, int64(5), res.Height)
+}
@@ -0,0 +1,223 @@
+package simulation
+
+import (
+	"math/rand"
+	"sort"
+
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+	"github.com/gnolang/gno/pkgs/crypto/secp256k1"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Account is an account of a simulation, with its keys.
+type Account struct {
+	PrivKey crypto.PrivKey
+	PubKey  crypto.PubKey
+	Address crypto.Address
+}
+
+// RandomAccounts returns n accounts, with secp256k1 keys generated from r.
+func RandomAccounts(r *rand.Rand, n int) []Account {
+	accs := make([]Account, n)
+	for i := range accs {
+		priv := secp256k1.GenPrivKeySecp256k1(randomSecret(r))
+		accs[i] = Account{
+			PrivKey: priv,
+			PubKey:  priv.PubKey(),
+			Address: priv.PubKey().Address(),
+		}
+	}
+	return accs
+}
+
+// RandomAccount returns one of accs, which must not be empty.
+func RandomAccount(r *rand.Rand, accs []Account) Account {
+	return accs[r.Intn(len(accs))]
+}
+
+// Validator is a validator of a simulation, with its consensus key.
+type Validator struct {
+	PrivKey crypto.PrivKey
+	Power   int64
+}
+
+// Address returns the address of the consensus key of the validator.
+func (val Validator) Address() crypto.Address {
+	return val.PrivKey.PubKey().Address()
+}
+
+// ABCIValidatorUpdate returns the update which sets the validator.
+func (val Validator) ABCIValidatorUpdate() abci.ValidatorUpdate {
+	return abci.ValidatorUpdate{
+		Address: val.Address(),
+		PubKey:  val.PrivKey.PubKey(),
+		Power:   val.Power,
+	}
+}
+
+// RandomValidatorKey returns an ed25519 consensus key generated from r.
+func RandomValidatorKey(r *rand.Rand) crypto.PrivKey {
+	return ed25519.GenPrivKeyFromSecret(randomSecret(r))
+}
+
+// RandomValidators returns n validators of power, sorted by address.
+func RandomValidators(r *rand.Rand, n int, power int64) []Validator {
+	vals := make([]Validator, n)
+	for i := range vals {
+		vals[i] = Validator{
+			PrivKey: RandomValidatorKey(r),
+			Power:   power,
+		}
+	}
+	sortValidators(vals)
+	return vals
+}
+
+// RandomAmount returns an amount in [1, max], or 0 if max is not
+// positive.
+func RandomAmount(r *rand.Rand, max int64) int64 {
+	if max <= 0 {
+		return 0
+	}
+	return r.Int63n(max) + 1
+}
+
+// RandomCoin returns a random amount, of a random denomination, of coins,
+// or a zero coin if coins is empty.
+func RandomCoin(r *rand.Rand, coins std.Coins) std.Coin {
+	if len(coins) == 0 {
+		return std.Coin{}
+	}
+	coin := coins[r.Intn(len(coins))]
+	return std.NewCoin(coin.Denom, RandomAmount(r, coin.Amount))
+}
+
+func randomSecret(r *rand.Rand) []byte {
+	secret := make([]byte, 32)
+	r.Read(secret)
+	return secret
+}
+
+func sortValidators(vals []Validator) {
+	sort.Slice(vals, func(i, j int) bool {
+		return vals[i].Address().Compare(vals[j].Address()) < 0
+	})
+}
+
+//----------------------------------------
+// Env
+
+// Env is the state of a simulation, as seen by the operations of a block.
+type Env struct {
+	ChainID string
+	Height  int64   // height of the block
+	Fee     std.Fee // fee of each tx
+
+	// Accounts are all the accounts of the simulation, while Signers are
+	// those which can pay the fee, and have not signed a tx of the block
+	// yet, so that their sequences, and the coins they may spend, are those
+	// of the context of the operations.
+	Accounts []Account
+	Signers  []Account
+
+	// Validators are the current validators, sorted by address.
+	Validators []Validator
+
+	// next consensus keys of validators, by address of the validator.
+	nextKeys map[crypto.Address]crypto.PrivKey
+}
+
+func newEnv(chainID string, fee std.Fee, accs []Account, vals []Validator) *Env {
+	return &Env{
+		ChainID:    chainID,
+		Fee:        fee,
+		Accounts:   accs,
+		Validators: vals,
+		nextKeys:   make(map[crypto.Address]crypto.PrivKey),
+	}
+}
+
+// SetNextValidatorKey records the next consensus key of the validator at
+// addr, with which it is validating once the chain updates the validator
+// set with the key.
+func (env *Env) SetNextValidatorKey(addr crypto.Address, next crypto.PrivKey) {
+	env.nextKeys[addr] = next
+}
+
+// HasNextValidatorKey returns whether the validator at addr has a next
+// consensus key not yet used.
+func (env *Env) HasNextValidatorKey(addr crypto.Address) bool {
+	_, ok := env.nextKeys[addr]
+	return ok
+}
+
+// Spendable returns the coins of coins which are left to spend once the
+// fee is paid, or nil if the fee can't be paid.
+func (env *Env) Spendable(coins std.Coins) std.Coins {
+	fee := env.Fee.GasFee
+	if coins.AmountOf(fee.Denom) < fee.Amount {
+		return nil
+	}
+	spendable := std.Coins{}
+	for _, coin := range coins {
+		if coin.Denom == fee.Denom {
+			coin.Amount -= fee.Amount
+		}
+		if coin.Amount > 0 {
+			spendable = append(spendable, coin)
+		}
+	}
+	return spendable
+}
+
+// Removes signer from the signers of the block.
+func (env *Env) removeSigner(signer crypto.Address) {
+	for i, acc := range env.Signers {
+		if acc.Address == signer {
+			env.Signers = append(env.Signers[:i:i], env.Signers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Applies the validator updates of the end of a block, whose new keys
+// must be next keys of validators.
+func (env *Env) updateValidators(updates []abci.ValidatorUpdate) error {
+	for _, update := range updates {
+		addr := update.PubKey.Address()
+		idx := -1
+		for i, val := range env.Validators {
+			if val.Address() == addr {
+				idx = i
+			}
+		}
+		switch {
+		case update.Power == 0 && idx >= 0:
+			env.Validators = append(env.Validators[:idx:idx], env.Validators[idx+1:]...)
+		case update.Power == 0:
+			return errUnknownValidator(addr)
+		case idx >= 0:
+			env.Validators[idx].Power = update.Power
+		default:
+			var priv crypto.PrivKey
+			for valAddr, next := range env.nextKeys {
+				if next.PubKey().Address() == addr {
+					priv = next
+					delete(env.nextKeys, valAddr)
+					break
+				}
+			}
+			if priv == nil {
+				return errUnknownValidator(addr)
+			}
+			env.Validators = append(env.Validators, Validator{
+				PrivKey: priv,
+				Power:   update.Power,
+			})
+		}
+	}
+	sortValidators(env.Validators)
+	return nil
+}
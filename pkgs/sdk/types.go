@@ -3,6 +3,7 @@ package sdk
 import (
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
 	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
 )
 
 // Router provides handlers for each transaction type.
@@ -11,6 +12,17 @@ type Router interface {
 	Route(path string) Handler
 }
 
+// QueryRouter provides queriers for each query path.
+type QueryRouter interface {
+	AddRoute(path string, q Querier) QueryRouter
+	Route(path string) (route string, q Querier)
+	Routes() []string
+}
+
+// A Querier answers the queries of a route, with the state of the context
+// at the height of the query.
+type Querier func(ctx Context, req abci.RequestQuery) abci.ResponseQuery
+
 // A Handler handles processing messages and answering queries
 // for a particular application concern.
 type Handler interface {
@@ -25,11 +37,32 @@ type Result struct {
 	abci.ResponseBase
 	GasWanted int64
 	GasUsed   int64
+	EventMsgs []abci.EventMsg // msg of each event, by index
 }
 
 // AnteHandler authenticates transactions, before their internal messages are handled.
 type AnteHandler func(ctx Context, tx Tx, simulate bool) (newCtx Context, result Result, abort bool)
 
+// TxHasher returns the canonical hash of a tx (see std.Tx.Hash) on the chain
+// of ctx, or nil if it has none.  It must not depend on the state, so that a
+// tx has the same hash in CheckTx and DeliverTx, and in all the blocks.
+type TxHasher func(ctx Context, tx Tx) []byte
+
+// GasRefunder refunds fees paid for unused gas after a tx was delivered,
+// whether or not its messages succeeded.
+type GasRefunder func(ctx Context, tx Tx, gasUsed int64)
+
+// SystemTxFilter returns true if tx is a system tx, e.g. an oracle update,
+// proposed before the other txs up to the system lane gas of the mempool.
+type SystemTxFilter func(tx Tx) bool
+
+// GasAudit is the result of a simulated tx along with a breakdown of its gas
+// consumption by descriptor (e.g. store access or VM op).
+type GasAudit struct {
+	Result  Result
+	Entries []store.GasAuditEntry
+}
+
 // Exports from std.
 type Msg = std.Msg
 
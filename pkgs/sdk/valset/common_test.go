@@ -0,0 +1,33 @@
+package valset
+
+// DONTCOVER
+
+import (
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/log"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/iavl"
+)
+
+type testEnv struct {
+	ctx    sdk.Context
+	valset ValsetKeeper
+}
+
+func setupTestEnv() testEnv {
+	db := dbm.NewMemDB()
+
+	mainCapKey := store.NewStoreKey("mainCapKey")
+
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(mainCapKey, iavl.StoreConstructor, db)
+	ms.LoadLatestVersion()
+
+	ctx := sdk.NewContext(sdk.RunTxModeDeliver, ms, &bft.Header{ChainID: "test-chain-id", Height: 10}, log.NewNopLogger())
+	valset := NewValsetKeeper(mainCapKey)
+
+	return testEnv{ctx: ctx, valset: valset}
+}
@@ -0,0 +1,41 @@
+package valset
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+)
+
+const (
+	// module name
+	ModuleName = "valset"
+
+	// RouterKey is the name of the valset module
+	RouterKey = ModuleName
+
+	// ValidatorStoreKeyPrefix prefix for validator-by-address store
+	ValidatorStoreKeyPrefix = "/valset/v/"
+
+	// RotationStoreKeyPrefix prefix for rotation-by-height store
+	RotationStoreKeyPrefix = "/valset/r/"
+
+	// MinRotationDelay is the minimum number of blocks between the block
+	// of a MsgRotateKey and its height, as validator updates of a block
+	// take effect two blocks later.
+	MinRotationDelay = 2
+)
+
+// ValidatorStoreKey turns an address to the key used to get the validator
+// from the store.
+func ValidatorStoreKey(addr crypto.Address) []byte {
+	return append([]byte(ValidatorStoreKeyPrefix), addr.Bytes()...)
+}
+
+// RotationStoreKey turns a height and an address to the key used to get
+// the rotation from the store, ordered by height.
+func RotationStoreKey(height int64, addr crypto.Address) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	key := append([]byte(RotationStoreKeyPrefix), bz...)
+	return append(key, addr.Bytes()...)
+}
@@ -0,0 +1,117 @@
+package valset
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+type valsetHandler struct {
+	valset  ValsetKeeper
+	queries sdk.QueryRouter
+}
+
+// NewHandler returns a handler for "valset" type messages.
+func NewHandler(valset ValsetKeeper) valsetHandler {
+	vh := valsetHandler{
+		valset:  valset,
+		queries: sdk.NewQueryRouter(),
+	}
+	vh.registerQueries(vh.queries)
+	return vh
+}
+
+type valsetModule struct {
+	valset ValsetKeeper
+}
+
+// NewModule returns the module of "valset" type messages and queries,
+// which ends blocks with the updates of the rotations of validator keys.
+func NewModule(valset ValsetKeeper) sdk.Module {
+	return valsetModule{
+		valset: valset,
+	}
+}
+
+func (vm valsetModule) Name() string { return ModuleName }
+
+func (vm valsetModule) Handler() sdk.Handler { return NewHandler(vm.valset) }
+
+func (vm valsetModule) RegisterQueries(qr sdk.QueryRouter) { NewHandler(vm.valset).registerQueries(qr) }
+
+// EndBlock implements sdk.EndBlockModule.
+func (vm valsetModule) EndBlock(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	return abci.ResponseEndBlock{
+		ValidatorUpdates: vm.valset.ApplyRotations(ctx, req.Height),
+	}
+}
+
+func (vh valsetHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
+	switch msg := msg.(type) {
+	case MsgRotateKey:
+		return vh.handleMsgRotateKey(ctx, msg)
+
+	default:
+		errMsg := fmt.Sprintf("unrecognized valset message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Handle MsgRotateKey.
+func (vh valsetHandler) handleMsgRotateKey(ctx sdk.Context, msg MsgRotateKey) sdk.Result {
+	err := vh.valset.AddRotation(ctx, ctx.ChainID(), msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+//----------------------------------------
+// Query
+
+const (
+	QueryValidators = "validators"
+	QueryRotations  = "rotations"
+)
+
+// Adds the query routes of the valset module, e.g. "valset/validators", to qr.
+func (vh valsetHandler) registerQueries(qr sdk.QueryRouter) {
+	qr.AddRoute(ModuleName+"/"+QueryValidators, vh.queryValidators)
+	qr.AddRoute(ModuleName+"/"+QueryRotations, vh.queryRotations)
+}
+
+func (vh valsetHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return sdk.RouteQuery(vh.queries, ctx, req)
+}
+
+// queryValidators returns the validators of the chain.
+func (vh valsetHandler) queryValidators(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return queryJSON(vh.valset.GetValidators(ctx))
+}
+
+// queryRotations returns the pending rotations of validator keys.
+func (vh valsetHandler) queryRotations(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return queryJSON(vh.valset.GetRotations(ctx))
+}
+
+// Returns the response of the JSON of result.
+func queryJSON(result interface{}) (res abci.ResponseQuery) {
+	bz, err := amino.MarshalJSONIndent(result, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+	res.Data = bz
+	return
+}
+
+//----------------------------------------
+// misc
+
+func abciResult(err error) sdk.Result {
+	return sdk.ABCIResultFromError(err)
+}
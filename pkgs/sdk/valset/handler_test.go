@@ -0,0 +1,86 @@
+package valset
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/crypto/ed25519"
+	"github.com/gnolang/gno/pkgs/sdk"
+	tu "github.com/gnolang/gno/pkgs/sdk/testutils"
+)
+
+func TestInvalidMsg(t *testing.T) {
+	h := NewHandler(ValsetKeeper{})
+	res := h.Process(sdk.NewContext(sdk.RunTxModeDeliver, nil, &bft.Header{ChainID: "test-chain"}, nil), tu.NewTestMsg())
+	require.False(t, res.IsOK())
+	require.True(t, strings.Contains(res.Log, "unrecognized valset message type"))
+}
+
+func signedRotation(key crypto.PrivKey, newKey crypto.PrivKey, height int64) MsgRotateKey {
+	_, _, caller := tu.KeyTestPubAddr()
+	msg := NewMsgRotateKey(caller, key.PubKey().Address(), newKey.PubKey(), height)
+	sig, err := key.Sign(msg.ValidatorSignBytes("test-chain-id"))
+	if err != nil {
+		panic(err)
+	}
+	msg.Signature = sig
+	return msg
+}
+
+func TestRotateKey(t *testing.T) {
+	env := setupTestEnv()
+	h := NewHandler(env.valset)
+	m := NewModule(env.valset).(sdk.EndBlockModule)
+	key, other := ed25519.GenPrivKey(), ed25519.GenPrivKey()
+	newKey := ed25519.GenPrivKey()
+	env.valset.InitValidators(env.ctx, []abci.ValidatorUpdate{
+		{PubKey: key.PubKey(), Power: 10},
+		{Address: other.PubKey().Address(), PubKey: other.PubKey(), Power: 1},
+	})
+	require.Len(t, env.valset.GetValidators(env.ctx), 2)
+
+	// invalid rotations are rejected.
+	msg := signedRotation(key, newKey, 20)
+	msg.Signature = signedRotation(other, newKey, 20).Signature
+	require.False(t, h.Process(env.ctx, msg).IsOK())
+	msg = signedRotation(key, other, 20)
+	require.False(t, h.Process(env.ctx, msg).IsOK())
+	msg = signedRotation(key, newKey, 11)
+	require.False(t, h.Process(env.ctx, msg).IsOK())
+	msg = signedRotation(newKey, key, 20)
+	require.False(t, h.Process(env.ctx, msg).IsOK())
+
+	msg = signedRotation(key, newKey, 20)
+	require.NoError(t, msg.ValidateBasic())
+	res := h.Process(env.ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.False(t, h.Process(env.ctx, msg).IsOK())
+
+	var rots []Rotation
+	qres := h.Query(env.ctx, abci.RequestQuery{Path: "valset/" + QueryRotations})
+	require.Nil(t, qres.Error)
+	require.NoError(t, amino.UnmarshalJSON(qres.Data, &rots))
+	require.Len(t, rots, 1)
+	require.Equal(t, int64(20), rots[0].Height)
+
+	// the rotation is applied by the block two blocks before its height.
+	eres := m.EndBlock(env.ctx, abci.RequestEndBlock{Height: 17})
+	require.Empty(t, eres.ValidatorUpdates)
+	eres = m.EndBlock(env.ctx, abci.RequestEndBlock{Height: 18})
+	require.Equal(t, []abci.ValidatorUpdate{
+		{Address: key.PubKey().Address(), PubKey: key.PubKey(), Power: 0},
+		{Address: newKey.PubKey().Address(), PubKey: newKey.PubKey(), Power: 10},
+	}, eres.ValidatorUpdates)
+	require.Empty(t, env.valset.GetRotations(env.ctx))
+	_, ok := env.valset.GetValidator(env.ctx, key.PubKey().Address())
+	require.False(t, ok)
+	val, ok := env.valset.GetValidator(env.ctx, newKey.PubKey().Address())
+	require.True(t, ok)
+	require.Equal(t, int64(10), val.Power)
+}
@@ -0,0 +1,173 @@
+package valset
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// valset.ValsetKeeperI tracks the validator set of the chain, and the
+// announced rotations of the keys of validators.
+type ValsetKeeperI interface {
+	InitValidators(ctx sdk.Context, updates []abci.ValidatorUpdate)
+	GetValidator(ctx sdk.Context, addr crypto.Address) (Validator, bool)
+	GetValidators(ctx sdk.Context) []Validator
+	GetRotations(ctx sdk.Context) []Rotation
+
+	AddRotation(ctx sdk.Context, chainID string, msg MsgRotateKey) error
+	ApplyRotations(ctx sdk.Context, height int64) []abci.ValidatorUpdate
+}
+
+var _ ValsetKeeperI = ValsetKeeper{}
+
+// ValsetKeeper stores the validators and rotations under the key.
+type ValsetKeeper struct {
+	key store.StoreKey
+}
+
+// NewValsetKeeper returns a new ValsetKeeper.
+func NewValsetKeeper(key store.StoreKey) ValsetKeeper {
+	return ValsetKeeper{
+		key: key,
+	}
+}
+
+// InitValidators sets the validators of the genesis, which replace those
+// of an imported state, if any.
+func (vk ValsetKeeper) InitValidators(ctx sdk.Context, updates []abci.ValidatorUpdate) {
+	for _, val := range vk.GetValidators(ctx) {
+		vk.removeValidator(ctx, val.Address)
+	}
+	for _, update := range updates {
+		addr := update.Address
+		if addr.IsZero() {
+			addr = update.PubKey.Address()
+		}
+		vk.setValidator(ctx, Validator{
+			Address: addr,
+			PubKey:  update.PubKey,
+			Power:   update.Power,
+		})
+	}
+}
+
+// GetValidator returns the validator at addr, if any.
+func (vk ValsetKeeper) GetValidator(ctx sdk.Context, addr crypto.Address) (val Validator, ok bool) {
+	stor := ctx.Store(vk.key)
+	bz := stor.Get(ValidatorStoreKey(addr))
+	if bz == nil {
+		return val, false
+	}
+	amino.MustUnmarshal(bz, &val)
+	return val, true
+}
+
+// GetValidators returns the validators, sorted by address.
+func (vk ValsetKeeper) GetValidators(ctx sdk.Context) []Validator {
+	vals := []Validator{}
+	stor := ctx.Store(vk.key)
+	iter := store.PrefixIterator(stor, []byte(ValidatorStoreKeyPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var val Validator
+		amino.MustUnmarshal(iter.Value(), &val)
+		vals = append(vals, val)
+	}
+	return vals
+}
+
+// GetRotations returns the pending rotations, sorted by height.
+func (vk ValsetKeeper) GetRotations(ctx sdk.Context) []Rotation {
+	rots := []Rotation{}
+	stor := ctx.Store(vk.key)
+	iter := store.PrefixIterator(stor, []byte(RotationStoreKeyPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var rot Rotation
+		amino.MustUnmarshal(iter.Value(), &rot)
+		rots = append(rots, rot)
+	}
+	return rots
+}
+
+// AddRotation checks the rotation of msg, signed by the validator for
+// chainID, and adds it to the pending rotations.
+func (vk ValsetKeeper) AddRotation(ctx sdk.Context, chainID string, msg MsgRotateKey) error {
+	val, ok := vk.GetValidator(ctx, msg.Validator)
+	if !ok {
+		return std.ErrUnknownAddress(fmt.Sprintf("validator %s does not exist", msg.Validator))
+	}
+	if !val.PubKey.VerifyBytes(msg.ValidatorSignBytes(chainID), msg.Signature) {
+		return std.ErrUnauthorized("invalid validator signature")
+	}
+	// the consensus accepts the same types of keys as the current key.
+	if amino.GetTypeURL(msg.NewPubKey) != amino.GetTypeURL(val.PubKey) {
+		return std.ErrInvalidPubKey(fmt.Sprintf("new pubkey must be of type %s", amino.GetTypeURL(val.PubKey)))
+	}
+	if _, ok := vk.GetValidator(ctx, msg.NewPubKey.Address()); ok {
+		return std.ErrInvalidPubKey("new pubkey is the key of a validator")
+	}
+	if minHeight := ctx.BlockHeight() + MinRotationDelay; msg.Height < minHeight {
+		return std.ErrUnknownRequest(fmt.Sprintf("height must be at least %d", minHeight))
+	}
+	for _, rot := range vk.GetRotations(ctx) {
+		if rot.Validator == msg.Validator {
+			return std.ErrUnknownRequest(fmt.Sprintf("validator %s already rotates its key at height %d", rot.Validator, rot.Height))
+		}
+		if rot.NewPubKey.Address() == msg.NewPubKey.Address() {
+			return std.ErrInvalidPubKey("new pubkey is the next key of a validator")
+		}
+	}
+	rot := Rotation{
+		Validator: msg.Validator,
+		NewPubKey: msg.NewPubKey,
+		Height:    msg.Height,
+	}
+	stor := ctx.Store(vk.key)
+	stor.Set(RotationStoreKey(rot.Height, rot.Validator), amino.MustMarshal(rot))
+	return nil
+}
+
+// ApplyRotations applies the rotations which take effect by the height
+// following the updates of the block of height, and returns the updates,
+// which replace the validators by ones with the new keys.
+func (vk ValsetKeeper) ApplyRotations(ctx sdk.Context, height int64) []abci.ValidatorUpdate {
+	var updates []abci.ValidatorUpdate
+	for _, rot := range vk.GetRotations(ctx) {
+		if rot.Height > height+MinRotationDelay {
+			break
+		}
+		stor := ctx.Store(vk.key)
+		stor.Delete(RotationStoreKey(rot.Height, rot.Validator))
+		val, ok := vk.GetValidator(ctx, rot.Validator)
+		if !ok {
+			continue // removed since.
+		}
+		next := Validator{
+			Address: rot.NewPubKey.Address(),
+			PubKey:  rot.NewPubKey,
+			Power:   val.Power,
+		}
+		vk.removeValidator(ctx, val.Address)
+		vk.setValidator(ctx, next)
+		removed := val.ABCIValidatorUpdate()
+		removed.Power = 0
+		updates = append(updates, removed, next.ABCIValidatorUpdate())
+	}
+	return updates
+}
+
+func (vk ValsetKeeper) setValidator(ctx sdk.Context, val Validator) {
+	stor := ctx.Store(vk.key)
+	stor.Set(ValidatorStoreKey(val.Address), amino.MustMarshal(val))
+}
+
+func (vk ValsetKeeper) removeValidator(ctx sdk.Context, addr crypto.Address) {
+	stor := ctx.Store(vk.key)
+	stor.Delete(ValidatorStoreKey(addr))
+}
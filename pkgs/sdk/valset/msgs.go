@@ -0,0 +1,82 @@
+package valset
+
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// MsgRotateKey - announce the rotation of the consensus key of a validator
+// to NewPubKey at Height.  It is signed by the current key of the
+// validator, while the fee is paid by Caller.
+type MsgRotateKey struct {
+	Caller    crypto.Address `json:"caller" yaml:"caller"`
+	Validator crypto.Address `json:"validator" yaml:"validator"`
+	NewPubKey crypto.PubKey  `json:"new_pub_key" yaml:"new_pub_key"`
+	Height    int64          `json:"height" yaml:"height"`
+	Signature []byte         `json:"signature" yaml:"signature"`
+}
+
+var _ std.Msg = MsgRotateKey{}
+
+// NewMsgRotateKey - construct an unsigned rotation msg, see
+// ValidatorSignBytes.
+func NewMsgRotateKey(caller, validator crypto.Address, newPubKey crypto.PubKey, height int64) MsgRotateKey {
+	return MsgRotateKey{
+		Caller:    caller,
+		Validator: validator,
+		NewPubKey: newPubKey,
+		Height:    height,
+	}
+}
+
+// Route Implements Msg.
+func (msg MsgRotateKey) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRotateKey) Type() string { return "rotate_key" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRotateKey) ValidateBasic() error {
+	if msg.Caller.IsZero() {
+		return std.ErrInvalidAddress("missing caller address")
+	}
+	if msg.Validator.IsZero() {
+		return std.ErrInvalidAddress("missing validator address")
+	}
+	if msg.NewPubKey == nil {
+		return std.ErrInvalidPubKey("missing new pubkey")
+	}
+	if msg.NewPubKey.Address() == msg.Validator {
+		return std.ErrInvalidPubKey("new pubkey is the current key of the validator")
+	}
+	if msg.Height <= 0 {
+		return std.ErrUnknownRequest("height must be positive")
+	}
+	if len(msg.Signature) == 0 {
+		return std.ErrUnauthorized("missing validator signature")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRotateKey) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRotateKey) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Caller}
+}
+
+// ValidatorSignBytes returns the bytes which the current key of the
+// validator signs for chainID, i.e. the rotation without its caller.
+func (msg MsgRotateKey) ValidatorSignBytes(chainID string) []byte {
+	doc := struct {
+		ChainID   string         `json:"chain_id"`
+		Validator crypto.Address `json:"validator"`
+		NewPubKey crypto.PubKey  `json:"new_pub_key"`
+		Height    int64          `json:"height"`
+	}{chainID, msg.Validator, msg.NewPubKey, msg.Height}
+	return std.MustSortJSON(amino.MustMarshalJSON(doc))
+}
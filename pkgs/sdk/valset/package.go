@@ -0,0 +1,15 @@
+package valset
+
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/pkgs/sdk/valset",
+	"valset",
+	amino.GetCallersDirname(),
+).WithDependencies().WithTypes(
+	Validator{}, "Validator",
+	Rotation{}, "Rotation",
+	MsgRotateKey{}, "MsgRotateKey",
+))
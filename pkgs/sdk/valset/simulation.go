@@ -0,0 +1,31 @@
+package valset
+
+import (
+	"math/rand"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/simulation"
+)
+
+// SimulateMsgRotateKey returns an operation which rotates the key of a
+// random validator, without a pending rotation, to a random key, from a
+// random height at least MinRotationDelay blocks after the block.
+func SimulateMsgRotateKey() simulation.Operation {
+	return func(r *rand.Rand, ctx sdk.Context, env *simulation.Env) (simulation.OperationMsg, error) {
+		caller := simulation.RandomAccount(r, env.Signers)
+		val := env.Validators[r.Intn(len(env.Validators))]
+		if env.HasNextValidatorKey(val.Address()) {
+			return simulation.NoOpMsg("valset/rotate_key: validator already rotates its key"), nil
+		}
+		next := simulation.RandomValidatorKey(r)
+		height := env.Height + MinRotationDelay + r.Int63n(5)
+		msg := NewMsgRotateKey(caller.Address, val.Address(), next.PubKey(), height)
+		sig, err := val.PrivKey.Sign(msg.ValidatorSignBytes(env.ChainID))
+		if err != nil {
+			return simulation.OperationMsg{}, err
+		}
+		msg.Signature = sig
+		env.SetNextValidatorKey(val.Address(), next)
+		return simulation.NewOperationMsg(msg, caller), nil
+	}
+}
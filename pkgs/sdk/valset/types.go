@@ -0,0 +1,30 @@
+package valset
+
+import (
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+)
+
+// Validator is a validator of the chain, as known by the application.
+type Validator struct {
+	Address crypto.Address `json:"address" yaml:"address"`
+	PubKey  crypto.PubKey  `json:"pub_key" yaml:"pub_key"`
+	Power   int64          `json:"power" yaml:"power"`
+}
+
+// ABCIValidatorUpdate returns the update which sets the validator.
+func (val Validator) ABCIValidatorUpdate() abci.ValidatorUpdate {
+	return abci.ValidatorUpdate{
+		Address: val.Address,
+		PubKey:  val.PubKey,
+		Power:   val.Power,
+	}
+}
+
+// Rotation is the announced rotation of the key of a validator, which takes
+// effect at Height.
+type Rotation struct {
+	Validator crypto.Address `json:"validator" yaml:"validator"`
+	NewPubKey crypto.PubKey  `json:"new_pub_key" yaml:"new_pub_key"`
+	Height    int64          `json:"height" yaml:"height"`
+}
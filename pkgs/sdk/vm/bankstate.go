@@ -0,0 +1,82 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// Allowances, denom metadata and denom issuers set through std.Banker are
+// kept in the iavl store, so that they are part of consensus state.
+func allowanceKey(owner, spender crypto.Address) []byte {
+	return []byte("allowance:" + owner.String() + ":" + spender.String())
+}
+
+func denomMetadataKey(denom string) []byte {
+	return []byte("denommeta:" + denom)
+}
+
+func denomIssuerKey(denom string) []byte {
+	return []byte("denomissuer:" + denom)
+}
+
+func getAllowance(iavlStore store.Store, owner, spender crypto.Address) (allowance std.Coins) {
+	bz := iavlStore.Get(allowanceKey(owner, spender))
+	if bz == nil {
+		return nil
+	}
+	amino.MustUnmarshal(bz, &allowance)
+	return allowance
+}
+
+// A zero allowance is deleted rather than stored.
+func setAllowance(iavlStore store.Store, owner, spender crypto.Address, allowance std.Coins) {
+	if allowance.IsZero() {
+		iavlStore.Delete(allowanceKey(owner, spender))
+		return
+	}
+	bz := amino.MustMarshal(allowance)
+	iavlStore.Set(allowanceKey(owner, spender), bz)
+}
+
+// Returns metadata with only the denom set if none was recorded.
+func getDenomMetadata(iavlStore store.Store, denom string) (meta std.DenomMetadata) {
+	bz := iavlStore.Get(denomMetadataKey(denom))
+	if bz == nil {
+		return std.DenomMetadata{Denom: denom}
+	}
+	amino.MustUnmarshal(bz, &meta)
+	return meta
+}
+
+func setDenomMetadata(iavlStore store.Store, meta std.DenomMetadata) {
+	bz := amino.MustMarshal(meta)
+	iavlStore.Set(denomMetadataKey(meta.Denom), bz)
+}
+
+// Returns the zero address if denom was not issued by a realm.
+func getDenomIssuer(iavlStore store.Store, denom string) crypto.Address {
+	bz := iavlStore.Get(denomIssuerKey(denom))
+	if bz == nil {
+		return crypto.Address{}
+	}
+	return crypto.AddressFromBytes(bz)
+}
+
+func setDenomIssuer(iavlStore store.Store, denom string, issuer crypto.Address) {
+	iavlStore.Set(denomIssuerKey(denom), issuer.Bytes())
+}
+
+// Approve sets the allowance of the spender of msg from the coins of its
+// owner, as realms do with std.Banker, replacing any previous allowance.
+func (vm *VMKeeper) Approve(ctx sdk.Context, msg MsgApprove) error {
+	if vm.acck.GetAccount(ctx, msg.Owner) == nil {
+		return std.ErrUnknownAddress(fmt.Sprintf("account %s does not exist", msg.Owner))
+	}
+	setAllowance(ctx.Store(vm.iavlKey), msg.Owner, msg.Spender, msg.Amount)
+	return nil
+}
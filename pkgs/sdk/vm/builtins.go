@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -75,6 +76,77 @@ func (vmk *VMKeeper) packageInjector(store gno.Store, pn *gno.PackageNode) {
 	}
 }
 
+//----------------------------------------
+// SDKScheduler
+
+type SDKScheduler struct {
+	vmk *VMKeeper
+	ctx sdk.Context
+}
+
+func NewSDKScheduler(vmk *VMKeeper, ctx sdk.Context) *SDKScheduler {
+	return &SDKScheduler{
+		vmk: vmk,
+		ctx: ctx,
+	}
+}
+
+func (sch *SDKScheduler) ScheduleCall(pkgPath string, height int64, fn string, arg string, gas int64) {
+	err := sch.vmk.ScheduleCall(sch.ctx, ScheduledCall{
+		PkgPath: pkgPath,
+		Func:    fn,
+		Arg:     arg,
+		Height:  height,
+		Gas:     gas,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (sch *SDKScheduler) SendMessage(from string, pkgPath string, fn string, arg string, gas int64) {
+	err := sch.vmk.SendMessage(sch.ctx, RealmMessage{
+		From:    from,
+		PkgPath: pkgPath,
+		Func:    fn,
+		Arg:     arg,
+		Gas:     gas,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//----------------------------------------
+// SDKEventEmitter
+
+// SDKEventEmitter emits the events of std.Emit, already validated and
+// charged for by the VM, as RealmEvents to the event logger of ctx.
+type SDKEventEmitter struct {
+	ctx sdk.Context
+}
+
+func NewSDKEventEmitter(ctx sdk.Context) *SDKEventEmitter {
+	return &SDKEventEmitter{
+		ctx: ctx,
+	}
+}
+
+func (em *SDKEventEmitter) Emit(pkgPath string, typ string, attrs []string) {
+	event := RealmEvent{
+		PkgPath: pkgPath,
+		Type:    typ,
+		Attrs:   make([]RealmEventAttr, 0, len(attrs)/2),
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		event.Attrs = append(event.Attrs, RealmEventAttr{
+			Key:   attrs[i],
+			Value: attrs[i+1],
+		})
+	}
+	em.ctx.EventLogger().EmitEvent(event)
+}
+
 //----------------------------------------
 // SDKBanker
 
@@ -124,3 +196,64 @@ func (bnk *SDKBanker) RemoveCoin(b32addr crypto.Bech32Address, denom string, amo
 		panic(err)
 	}
 }
+
+func (bnk *SDKBanker) Allowance(b32owner, b32spender crypto.Bech32Address) (allowance std.Coins) {
+	owner := crypto.MustAddressFromString(string(b32owner))
+	spender := crypto.MustAddressFromString(string(b32spender))
+	return getAllowance(bnk.ctx.Store(bnk.vmk.iavlKey), owner, spender)
+}
+
+func (bnk *SDKBanker) Approve(b32owner, b32spender crypto.Bech32Address, amt std.Coins) {
+	owner := crypto.MustAddressFromString(string(b32owner))
+	spender := crypto.MustAddressFromString(string(b32spender))
+	if !amt.IsZero() && !amt.IsValid() {
+		panic(std.ErrInvalidCoins(amt.String()))
+	}
+	setAllowance(bnk.ctx.Store(bnk.vmk.iavlKey), owner, spender, amt)
+}
+
+func (bnk *SDKBanker) TransferFrom(b32spender, b32from, b32to crypto.Bech32Address, amt std.Coins) {
+	spender := crypto.MustAddressFromString(string(b32spender))
+	from := crypto.MustAddressFromString(string(b32from))
+	to := crypto.MustAddressFromString(string(b32to))
+	iavlStore := bnk.ctx.Store(bnk.vmk.iavlKey)
+	allowance := getAllowance(iavlStore, from, spender)
+	if !allowance.IsAllGTE(amt) {
+		panic(std.ErrInsufficientCoins(fmt.Sprintf(
+			"allowance %s of %s for %s is less than %s",
+			allowance, b32from, b32spender, amt)))
+	}
+	err := bnk.vmk.bank.SendCoins(bnk.ctx, from, to, amt)
+	if err != nil {
+		panic(err)
+	}
+	setAllowance(iavlStore, from, spender, allowance.Sub(amt))
+}
+
+func (bnk *SDKBanker) GetDenomMetadata(denom string) (meta std.DenomMetadata) {
+	return getDenomMetadata(bnk.ctx.Store(bnk.vmk.iavlKey), denom)
+}
+
+func (bnk *SDKBanker) SetDenomMetadata(meta std.DenomMetadata) {
+	if err := meta.ValidateBasic(); err != nil {
+		panic(err)
+	}
+	setDenomMetadata(bnk.ctx.Store(bnk.vmk.iavlKey), meta)
+}
+
+func (bnk *SDKBanker) GetDenomIssuer(denom string) crypto.Bech32Address {
+	issuer := getDenomIssuer(bnk.ctx.Store(bnk.vmk.iavlKey), denom)
+	if issuer.IsZero() {
+		return ""
+	}
+	return issuer.Bech32()
+}
+
+func (bnk *SDKBanker) SetDenomIssuer(denom string, b32issuer crypto.Bech32Address) {
+	if bnk.vmk.isReservedDenom(denom) {
+		panic(std.ErrUnauthorized(fmt.Sprintf(
+			"denom %s is reserved", denom)))
+	}
+	issuer := crypto.MustAddressFromString(string(b32issuer))
+	setDenomIssuer(bnk.ctx.Store(bnk.vmk.iavlKey), denom, issuer)
+}
@@ -0,0 +1,164 @@
+package vm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/overflow"
+)
+
+// A namespace, e.g. alice of gno.land/r/alice and gno.land/p/alice/foo, may
+// be registered by an account, after which only that account may deploy
+// packages under it.  Unregistered namespaces are open to all.  The owners
+// of namespaces are kept in the iavl store, so that they are part of
+// consensus state.
+func namespaceOwnerKey(namespace string) []byte {
+	return []byte("nsowner:" + namespace)
+}
+
+// Returns the zero address if namespace is not registered.
+func getNamespaceOwner(iavlStore store.Store, namespace string) crypto.Address {
+	bz := iavlStore.Get(namespaceOwnerKey(namespace))
+	if bz == nil {
+		return crypto.Address{}
+	}
+	return crypto.AddressFromBytes(bz)
+}
+
+func setNamespaceOwner(iavlStore store.Store, namespace string, owner crypto.Address) {
+	iavlStore.Set(namespaceOwnerKey(namespace), owner.Bytes())
+}
+
+var reNamespace = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Returns the namespace of pkgPath, i.e. its first element under
+// gno.land/r/ or gno.land/p/, or "" if none.
+func pkgNamespace(pkgPath string) string {
+	var rest string
+	switch {
+	case strings.HasPrefix(pkgPath, "gno.land/r/"):
+		rest = pkgPath[len("gno.land/r/"):]
+	case strings.HasPrefix(pkgPath, "gno.land/p/"):
+		rest = pkgPath[len("gno.land/p/"):]
+	default:
+		return ""
+	}
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// RegisterNamespace registers the namespace of msg to its owner, if not
+// already registered, nor holding packages deployed by other accounts.
+// The fee of the registration (see NamespaceFee) is charged by the handler.
+func (vm *VMKeeper) RegisterNamespace(ctx sdk.Context, msg MsgRegisterNamespace) error {
+	iavlStore := ctx.Store(vm.iavlKey)
+	if !reNamespace.MatchString(msg.Namespace) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"invalid namespace: %s", msg.Namespace))
+	}
+	if owner := getNamespaceOwner(iavlStore, msg.Namespace); !owner.IsZero() {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"namespace %s is already registered to %s", msg.Namespace, owner))
+	}
+	if creator, pkgPath, ok := otherNamespaceCreator(iavlStore, msg.Namespace, msg.Owner); ok {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"namespace %s holds package %s deployed by %s", msg.Namespace, pkgPath, creator))
+	}
+	setNamespaceOwner(iavlStore, msg.Namespace, msg.Owner)
+	return nil
+}
+
+// Returns the creator and path of a package deployed under namespace by an
+// account other than owner, if any.
+func otherNamespaceCreator(iavlStore store.Store, namespace string, owner crypto.Address) (crypto.Address, string, bool) {
+	prefixLen := len(packageCreatorKey(""))
+	for _, root := range []string{"gno.land/r/" + namespace, "gno.land/p/" + namespace} {
+		itr := store.PrefixIterator(iavlStore, packageCreatorKey(root))
+		for ; itr.Valid(); itr.Next() {
+			pkgPath := string(itr.Key()[prefixLen:])
+			if pkgNamespace(pkgPath) != namespace {
+				continue // e.g. gno.land/r/alicex of gno.land/r/alice.
+			}
+			if creator := crypto.AddressFromBytes(itr.Value()); creator != owner {
+				itr.Close()
+				return creator, pkgPath, true
+			}
+		}
+		itr.Close()
+	}
+	return crypto.Address{}, "", false
+}
+
+// NamespaceFee returns the fee charged for registering a namespace, beyond
+// the fee of its tx.
+func (vm *VMKeeper) NamespaceFee() std.Coins {
+	if vm.namespaceFee.IsZero() {
+		return std.Coins{}
+	}
+	return std.Coins{vm.namespaceFee}
+}
+
+// QueryNamespaceOwner returns the owner of namespace, or the zero address
+// if not registered.
+func (vm *VMKeeper) QueryNamespaceOwner(ctx sdk.Context, namespace string) crypto.Address {
+	return getNamespaceOwner(ctx.Store(vm.iavlKey), namespace)
+}
+
+// Returns an error unless creator may deploy a new package, or a new version
+// of a realm, at pkgPath, as per the deployment whitelist, if any, and the
+// owner of its namespace, if registered.
+func (vm *VMKeeper) checkDeployPolicy(ctx sdk.Context, creator crypto.Address, pkgPath string) error {
+	if vm.deployWhitelist != nil && !containsAddress(vm.deployWhitelist, creator) {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"%s may not deploy packages", creator))
+	}
+	namespace := pkgNamespace(pkgPath)
+	if namespace == "" {
+		return nil
+	}
+	owner := getNamespaceOwner(ctx.Store(vm.iavlKey), namespace)
+	if !owner.IsZero() && owner != creator {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"%s may not deploy packages in namespace %s of %s", creator, namespace, owner))
+	}
+	return nil
+}
+
+func containsAddress(addrs []crypto.Address, addr crypto.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// DeployFee returns the fee charged for deploying or upgrading memPkg,
+// beyond the fee of its tx: the flat deployment fee, plus the per byte fee
+// times the size of its files.
+func (vm *VMKeeper) DeployFee(memPkg *std.MemPackage) (std.Coins, error) {
+	fee := std.Coins{}
+	if !vm.deployFee.IsZero() {
+		fee = fee.Add(std.Coins{vm.deployFee})
+	}
+	if !vm.deployFeePerByte.IsZero() {
+		size := int64(0)
+		for _, file := range memPkg.Files {
+			size += int64(len(file.Body))
+		}
+		amount, ok := overflow.Mul64(size, vm.deployFeePerByte.Amount)
+		if !ok {
+			return nil, std.ErrInsufficientFunds(fmt.Sprintf(
+				"deployment fee overflow for %d bytes", size))
+		}
+		fee = fee.Add(std.Coins{std.NewCoin(vm.deployFeePerByte.Denom, amount)})
+	}
+	return fee, nil
+}
@@ -0,0 +1,135 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// The deployment height and the resolved dependencies of packages are kept
+// in the iavl store, so that they are part of consensus state.
+func packageHeightKey(pkgPath string) []byte {
+	return []byte("pkgheight:" + pkgPath)
+}
+
+func packageDepsKey(pkgPath string) []byte {
+	return []byte("pkgdeps:" + pkgPath)
+}
+
+// Returns zero for packages added before heights were recorded, or added
+// at genesis.
+func getPackageHeight(iavlStore store.Store, pkgPath string) (height int64) {
+	bz := iavlStore.Get(packageHeightKey(pkgPath))
+	if bz == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+func setPackageHeight(iavlStore store.Store, pkgPath string, height int64) {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	iavlStore.Set(packageHeightKey(pkgPath), bz)
+}
+
+// Returns the paths of the on-chain packages imported by the package at
+// pkgPath, as resolved when it was added.
+func getPackageDeps(iavlStore store.Store, pkgPath string) (deps []string) {
+	bz := iavlStore.Get(packageDepsKey(pkgPath))
+	if bz == nil {
+		return nil
+	}
+	amino.MustUnmarshal(bz, &deps)
+	return deps
+}
+
+func setPackageDeps(iavlStore store.Store, pkgPath string, deps []string) {
+	if len(deps) == 0 {
+		return // empty values are not allowed.
+	}
+	iavlStore.Set(packageDepsKey(pkgPath), amino.MustMarshal(deps))
+}
+
+// Resolves the on-chain imports of memPkg to the versions pinned by its
+// gno.mod file, if any.  Imports that are not pinned resolve to the
+// package at their path, i.e. version v1.  Returns a copy of memPkg with
+// imports rewritten to the resolved paths if needed, and the resolved
+// paths of the on-chain imports.
+func resolveImports(iavlStore store.Store, gnoStore gno.Store, memPkg *std.MemPackage) (resolvedPkg *std.MemPackage, deps []string, err error) {
+	mf, err := gno.ReadModFile(memPkg)
+	if err != nil {
+		return nil, nil, ErrInvalidPkgPath(err.Error())
+	}
+	if mf != nil && mf.Module != "" && mf.Module != memPkg.Path {
+		return nil, nil, ErrInvalidPkgPath(fmt.Sprintf(
+			"module %s does not match package path %s", mf.Module, memPkg.Path))
+	}
+	imports, err := gno.MemPackageImports(memPkg)
+	if err != nil {
+		return nil, nil, ErrInvalidPkgPath(err.Error())
+	}
+	rewrites := make(map[string]string)
+	for _, path := range imports {
+		if !strings.HasPrefix(path, "gno.land/") {
+			continue // stdlib.
+		}
+		resolved := path
+		if mf != nil {
+			if mr, ok := mf.Require(path); ok {
+				resolved, err = resolveRequire(iavlStore, mr)
+				if err != nil {
+					return nil, nil, err
+				}
+				rewrites[path] = resolved
+			}
+		}
+		if pv := gnoStore.GetPackage(resolved, false); pv == nil {
+			return nil, nil, ErrInvalidPkgPath(fmt.Sprintf(
+				"package not found: %s", resolved))
+		}
+		deps = append(deps, resolved)
+	}
+	if len(rewrites) == 0 {
+		return memPkg, deps, nil
+	}
+	// do not modify the package of the message.
+	resolvedPkg = &std.MemPackage{Name: memPkg.Name, Path: memPkg.Path}
+	for _, mfile := range memPkg.Files {
+		mfile2 := *mfile
+		resolvedPkg.Files = append(resolvedPkg.Files, &mfile2)
+	}
+	if err := gno.RewriteImports(resolvedPkg, rewrites); err != nil {
+		return nil, nil, ErrInvalidPkgPath(err.Error())
+	}
+	return resolvedPkg, deps, nil
+}
+
+// Returns the path of the version of a package pinned by mr.
+func resolveRequire(iavlStore store.Store, mr gno.ModRequire) (string, error) {
+	versions := append([]string{mr.Path}, getPackageVersions(iavlStore, mr.Path)...)
+	if mr.Version > 0 {
+		if mr.Version > len(versions) {
+			return "", ErrInvalidPkgPath(fmt.Sprintf(
+				"version v%d of %s not found", mr.Version, mr.Path))
+		}
+		return versions[mr.Version-1], nil
+	}
+	// the latest version added at or before the height.
+	resolved := ""
+	for _, version := range versions {
+		if getPackageHeight(iavlStore, version) > mr.Height {
+			break
+		}
+		resolved = version
+	}
+	if resolved == "" {
+		return "", ErrInvalidPkgPath(fmt.Sprintf(
+			"no version of %s at height %d", mr.Path, mr.Height))
+	}
+	return resolved, nil
+}
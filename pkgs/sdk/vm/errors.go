@@ -1,6 +1,11 @@
 package vm
 
-import "github.com/gnolang/gno/pkgs/errors"
+import (
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/errors"
+)
 
 // for convenience:
 type abciError struct{}
@@ -12,13 +17,56 @@ func (_ abciError) AssertABCIError() {}
 type InvalidPkgPathError struct{ abciError }
 
 type (
-	InvalidStmtError struct{ abciError }
-	InvalidExprError struct{ abciError }
+	InvalidStmtError        struct{ abciError }
+	InvalidExprError        struct{ abciError }
+	UnformattedPackageError struct{ abciError }
+	PackagePausedError      struct{ abciError }
+	PackageTooLargeError    struct{ abciError }
+	TooManyFilesError       struct{ abciError }
+	TooManyDeclsError       struct{ abciError }
+	ASTTooDeepError         struct{ abciError }
+	TypeCheckError          struct{ abciError }
+	MigrationError          struct{ abciError }
 )
 
-func (e InvalidPkgPathError) Error() string { return "invalid package path" }
-func (e InvalidStmtError) Error() string    { return "invalid statement" }
-func (e InvalidExprError) Error() string    { return "invalid expression" }
+func (e InvalidPkgPathError) Error() string     { return "invalid package path" }
+func (e InvalidStmtError) Error() string        { return "invalid statement" }
+func (e InvalidExprError) Error() string        { return "invalid expression" }
+func (e UnformattedPackageError) Error() string { return "unformatted package" }
+func (e PackagePausedError) Error() string      { return "package paused" }
+func (e PackageTooLargeError) Error() string    { return "package too large" }
+func (e TooManyFilesError) Error() string       { return "too many files" }
+func (e TooManyDeclsError) Error() string       { return "too many declarations" }
+func (e ASTTooDeepError) Error() string         { return "syntax tree too deep" }
+func (e TypeCheckError) Error() string          { return "type check failed" }
+func (e MigrationError) Error() string          { return "migration failed" }
+
+// VMPanicError is the error of a realm call which panicked, with the gno
+// call stack of the panic, innermost call first.
+type VMPanicError struct {
+	abciError
+	Exception  string       `json:"exception"`
+	Stacktrace []StackFrame `json:"stacktrace"`
+}
+
+// StackFrame is a gno call frame of a VMPanicError.
+type StackFrame struct {
+	PkgPath string `json:"pkg_path"`
+	Func    string `json:"func"`
+	File    string `json:"file"` // "" if native
+	Line    int    `json:"line"` // 0 if unknown
+}
+
+func (e VMPanicError) Error() string {
+	lines := []string{"panic: " + e.Exception}
+	for _, sf := range e.Stacktrace {
+		lines = append(lines, gno.StackFrame(sf).String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Shown in the logs of results, instead of the fields.
+func (e VMPanicError) GoString() string { return e.Error() }
 
 func ErrInvalidPkgPath(msg string) error {
 	return errors.Wrap(InvalidPkgPathError{}, msg)
@@ -31,3 +79,43 @@ func ErrInvalidStmt(msg string) error {
 func ErrInvalidExpr(msg string) error {
 	return errors.Wrap(InvalidExprError{}, msg)
 }
+
+func ErrUnformattedPackage(msg string) error {
+	return errors.Wrap(UnformattedPackageError{}, msg)
+}
+
+func ErrPackagePaused(msg string) error {
+	return errors.Wrap(PackagePausedError{}, msg)
+}
+
+func ErrPackageTooLarge(msg string) error {
+	return errors.Wrap(PackageTooLargeError{}, msg)
+}
+
+func ErrTooManyFiles(msg string) error {
+	return errors.Wrap(TooManyFilesError{}, msg)
+}
+
+func ErrTooManyDecls(msg string) error {
+	return errors.Wrap(TooManyDeclsError{}, msg)
+}
+
+func ErrASTTooDeep(msg string) error {
+	return errors.Wrap(ASTTooDeepError{}, msg)
+}
+
+func ErrTypeCheck(msg string) error {
+	return errors.Wrap(TypeCheckError{}, msg)
+}
+
+func ErrMigration(msg string) error {
+	return errors.Wrap(MigrationError{}, msg)
+}
+
+func ErrVMPanic(exception string, st gno.Stacktrace, msg string) error {
+	e := VMPanicError{Exception: exception}
+	for _, sf := range st {
+		e.Stacktrace = append(e.Stacktrace, StackFrame(sf))
+	}
+	return errors.Wrap(e, msg)
+}
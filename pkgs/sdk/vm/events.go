@@ -0,0 +1,103 @@
+package vm
+
+import (
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/sdk"
+)
+
+// RealmCallEvent is emitted for each call into a realm, whether from a
+// message (with no caller realm) or from another realm.  Together these
+// events form the call graph of a transaction.
+type RealmCallEvent struct {
+	Caller  string `json:"caller" yaml:"caller"`     // realm path of caller, or "" if none
+	PkgPath string `json:"pkg_path" yaml:"pkg_path"` // realm path of callee
+	Func    string `json:"func" yaml:"func"`
+	Depth   int    `json:"depth" yaml:"depth"`
+	Cycles  int64  `json:"cycles" yaml:"cycles"`
+}
+
+// Implements abci.Event.
+func (RealmCallEvent) AssertABCIEvent() {}
+
+// IsCrossRealm returns true if the call was made from another realm.
+func (e RealmCallEvent) IsCrossRealm() bool {
+	return e.Caller != "" && e.Caller != e.PkgPath
+}
+
+// newCallTracer returns a tracer that emits a RealmCallEvent to the event
+// logger of ctx for each traced call.
+func newCallTracer(ctx sdk.Context) gno.CallTracer {
+	return func(ce gno.CallEvent) {
+		ctx.EventLogger().EmitEvent(RealmCallEvent{
+			Caller:  ce.Caller,
+			PkgPath: ce.Realm,
+			Func:    string(ce.Func),
+			Depth:   ce.Depth,
+			Cycles:  ce.Cycles,
+		})
+	}
+}
+
+// RealmEvent is emitted by a realm with std.Emit.
+type RealmEvent struct {
+	PkgPath string           `json:"pkg_path" yaml:"pkg_path"` // realm path of emitter
+	Type    string           `json:"event_type" yaml:"event_type"`
+	Attrs   []RealmEventAttr `json:"attrs" yaml:"attrs"`
+}
+
+// RealmEventAttr is an attribute of a RealmEvent.
+type RealmEventAttr struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// Implements abci.Event.
+func (RealmEvent) AssertABCIEvent() {}
+
+// GetAttr returns the value of the first attribute of key.
+func (e RealmEvent) GetAttr(key string) (string, bool) {
+	for _, attr := range e.Attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// RealmStorageEvent is emitted for each realm whose persisted objects were
+// created, updated or deleted by a message, e.g. for clients to invalidate
+// their caches of the state of the realm.  Objects are identified by their
+// object IDs, e.g. "0a1b...:12".
+type RealmStorageEvent struct {
+	PkgPath string   `json:"pkg_path" yaml:"pkg_path"`
+	Created []string `json:"created" yaml:"created"`
+	Updated []string `json:"updated" yaml:"updated"`
+	Deleted []string `json:"deleted" yaml:"deleted"`
+}
+
+// Implements abci.Event.
+func (RealmStorageEvent) AssertABCIEvent() {}
+
+// emitStorageEvents emits a RealmStorageEvent to the event logger of ctx
+// for each realm whose objects were changed in the gno store.
+func emitStorageEvents(ctx sdk.Context, gnoStore gno.Store) {
+	for _, rc := range gnoStore.RealmObjectChanges() {
+		if !gno.IsRealmPath(rc.Path) {
+			continue // e.g. a package added.
+		}
+		ctx.EventLogger().EmitEvent(RealmStorageEvent{
+			PkgPath: rc.Path,
+			Created: objectIDStrings(rc.Created),
+			Updated: objectIDStrings(rc.Updated),
+			Deleted: objectIDStrings(rc.Deleted),
+		})
+	}
+}
+
+func objectIDStrings(oids []gno.ObjectID) []string {
+	ss := make([]string, len(oids))
+	for i, oid := range oids {
+		ss[i] = oid.String()
+	}
+	return ss
+}
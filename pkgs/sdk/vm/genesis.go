@@ -0,0 +1,99 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+var genesisStateKey = []byte("vmgenesis")
+
+// GenesisState is the state of the vm module at genesis: the configuration
+// of its keeper, which is kept in its store, so that the keeper is
+// configured again on restart.
+type GenesisState struct {
+	DeployWhitelist  []crypto.Address `json:"deploy_whitelist" yaml:"deploy_whitelist"`
+	DeployFee        std.Coin         `json:"deploy_fee" yaml:"deploy_fee"`
+	DeployFeePerByte std.Coin         `json:"deploy_fee_per_byte" yaml:"deploy_fee_per_byte"`
+	NamespaceFee     std.Coin         `json:"namespace_fee" yaml:"namespace_fee"`
+	RejectFloats     bool             `json:"reject_floats" yaml:"reject_floats"`
+	RequireFormatted bool             `json:"require_formatted" yaml:"require_formatted"`
+	StoragePrice     std.Coin         `json:"storage_price" yaml:"storage_price"`
+	ReservedDenoms   []string         `json:"reserved_denoms" yaml:"reserved_denoms"` // or nil for the default ones
+	UpgradeAuthority crypto.Address   `json:"upgrade_authority" yaml:"upgrade_authority"`
+	PackageLimits    *PackageLimits   `json:"package_limits" yaml:"package_limits"` // or nil for the default ones
+}
+
+// Validate returns an error if gs is invalid.
+func (gs GenesisState) Validate() error {
+	for _, addr := range gs.DeployWhitelist {
+		if addr.IsZero() {
+			return std.ErrInvalidAddress("missing address in deploy whitelist")
+		}
+	}
+	for _, coin := range []std.Coin{gs.DeployFee, gs.DeployFeePerByte, gs.NamespaceFee, gs.StoragePrice} {
+		if !coin.IsZero() && !coin.IsValid() {
+			return std.ErrInvalidCoins(fmt.Sprintf("invalid coin %s", coin))
+		}
+	}
+	return nil
+}
+
+// InitGenesis configures the keeper with gs, and keeps gs in the store of
+// ctx, from which Initialize configures the keeper again on restart.
+func (vm *VMKeeper) InitGenesis(ctx sdk.Context, gs GenesisState) error {
+	if err := gs.Validate(); err != nil {
+		return err
+	}
+	vm.setGenesisState(gs)
+	ctx.Store(vm.iavlKey).Set(genesisStateKey, amino.MustMarshalSized(gs))
+	return nil
+}
+
+// ExportGenesis returns the configuration of the keeper.
+func (vm *VMKeeper) ExportGenesis(ctx sdk.Context) GenesisState {
+	limits := vm.packageLimits
+	return GenesisState{
+		DeployWhitelist:  vm.deployWhitelist,
+		DeployFee:        vm.deployFee,
+		DeployFeePerByte: vm.deployFeePerByte,
+		NamespaceFee:     vm.namespaceFee,
+		RejectFloats:     vm.rejectFloats,
+		RequireFormatted: vm.requireFormatted,
+		StoragePrice:     vm.storagePrice,
+		ReservedDenoms:   vm.reservedDenoms,
+		UpgradeAuthority: vm.upgradeAuthority,
+		PackageLimits:    &limits,
+	}
+}
+
+func (vm *VMKeeper) setGenesisState(gs GenesisState) {
+	vm.SetDeployWhitelist(gs.DeployWhitelist)
+	vm.SetDeployFee(gs.DeployFee, gs.DeployFeePerByte)
+	vm.SetNamespaceFee(gs.NamespaceFee)
+	vm.SetRejectFloats(gs.RejectFloats)
+	vm.SetRequireFormatted(gs.RequireFormatted)
+	vm.SetStoragePrice(gs.StoragePrice)
+	if gs.ReservedDenoms != nil {
+		vm.SetReservedDenoms(gs.ReservedDenoms)
+	}
+	vm.SetUpgradeAuthority(gs.UpgradeAuthority)
+	if gs.PackageLimits != nil {
+		vm.SetPackageLimits(*gs.PackageLimits)
+	}
+}
+
+// Configures the keeper with the genesis state kept in iavlStore, if any.
+func (vm *VMKeeper) loadGenesisState(iavlStore store.Store) {
+	bz := iavlStore.Get(genesisStateKey)
+	if bz == nil {
+		return
+	}
+	var gs GenesisState
+	amino.MustUnmarshalSized(bz, &gs)
+	vm.setGenesisState(gs)
+}
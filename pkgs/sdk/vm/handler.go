@@ -2,31 +2,93 @@ package vm
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/sdk"
 	"github.com/gnolang/gno/pkgs/sdk/auth"
 	"github.com/gnolang/gno/pkgs/std"
 )
 
 type vmHandler struct {
-	vm *VMKeeper
+	vm      *VMKeeper
+	queries sdk.QueryRouter
 }
 
 // NewHandler returns a handler for "vm" type messages.
 func NewHandler(vm *VMKeeper) vmHandler {
-	return vmHandler{
+	vh := vmHandler{
+		vm:      vm,
+		queries: sdk.NewQueryRouter(),
+	}
+	vh.registerQueries(vh.queries)
+	return vh
+}
+
+type vmModule struct {
+	vm *VMKeeper
+}
+
+var _ sdk.GenesisModule = vmModule{}
+
+// NewModule returns the module of "vm" type messages and queries, which
+// ends blocks with the calls scheduled by realms, then the messages sent by
+// realms, and whose state at genesis is a GenesisState.
+func NewModule(vm *VMKeeper) sdk.Module {
+	return vmModule{
 		vm: vm,
 	}
 }
 
+func (vm vmModule) Name() string { return ModuleName }
+
+func (vm vmModule) Handler() sdk.Handler { return NewHandler(vm.vm) }
+
+func (vm vmModule) RegisterQueries(qr sdk.QueryRouter) { NewHandler(vm.vm).registerQueries(qr) }
+
+// InitGenesis implements sdk.GenesisModule.
+func (vm vmModule) InitGenesis(ctx sdk.Context, state interface{}) error {
+	gs, ok := state.(GenesisState)
+	if !ok {
+		return fmt.Errorf("invalid vm genesis state %T", state)
+	}
+	return vm.vm.InitGenesis(ctx, gs)
+}
+
+// ExportGenesis implements sdk.GenesisModule.
+func (vm vmModule) ExportGenesis(ctx sdk.Context) interface{} {
+	return vm.vm.ExportGenesis(ctx)
+}
+
+// EndBlock implements sdk.EndBlockModule.
+func (vm vmModule) EndBlock(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+	events := vm.vm.RunScheduledCalls(ctx)
+	events = append(events, vm.vm.DeliverMessages(ctx)...)
+	return abci.ResponseEndBlock{
+		ResponseBase: abci.ResponseBase{
+			Events: events,
+		},
+	}
+}
+
 func (vh vmHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 	switch msg := msg.(type) {
 	case MsgAddPackage:
 		return vh.handleMsgAddPackage(ctx, msg)
 	case MsgCall:
 		return vh.handleMsgCall(ctx, msg)
+	case MsgUpgradePackage:
+		return vh.handleMsgUpgradePackage(ctx, msg)
+	case MsgSetVerifier:
+		return vh.handleMsgSetVerifier(ctx, msg)
+	case MsgPausePackage:
+		return vh.handleMsgPausePackage(ctx, msg)
+	case MsgRegisterNamespace:
+		return vh.handleMsgRegisterNamespace(ctx, msg)
+	case MsgApprove:
+		return vh.handleMsgApprove(ctx, msg)
 	default:
 		errMsg := fmt.Sprintf("unrecognized vm message type: %T", msg)
 		return abciResult(std.ErrUnknownRequest(errMsg))
@@ -35,7 +97,7 @@ func (vh vmHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 
 // Handle MsgAddPackage.
 func (vh vmHandler) handleMsgAddPackage(ctx sdk.Context, msg MsgAddPackage) sdk.Result {
-	amount, err := std.ParseCoins("1000000ugnot") // XXX calculate
+	amount, err := vh.vm.DeployFee(msg.Package)
 	if err != nil {
 		return abciResult(err)
 	}
@@ -50,9 +112,69 @@ func (vh vmHandler) handleMsgAddPackage(ctx sdk.Context, msg MsgAddPackage) sdk.
 	return sdk.Result{}
 }
 
+// Handle MsgUpgradePackage.
+func (vh vmHandler) handleMsgUpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) sdk.Result {
+	amount, err := vh.vm.DeployFee(msg.Package)
+	if err != nil {
+		return abciResult(err)
+	}
+	err = vh.vm.bank.SendCoins(ctx, msg.Creator, auth.FeeCollectorAddress(), amount)
+	if err != nil {
+		return abciResult(err)
+	}
+	err = vh.vm.UpgradePackage(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Handle MsgSetVerifier.
+func (vh vmHandler) handleMsgSetVerifier(ctx sdk.Context, msg MsgSetVerifier) sdk.Result {
+	err := vh.vm.SetVerifier(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Handle MsgPausePackage.
+func (vh vmHandler) handleMsgPausePackage(ctx sdk.Context, msg MsgPausePackage) sdk.Result {
+	err := vh.vm.PausePackage(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Handle MsgRegisterNamespace.
+func (vh vmHandler) handleMsgRegisterNamespace(ctx sdk.Context, msg MsgRegisterNamespace) sdk.Result {
+	err := vh.vm.bank.SendCoins(ctx, msg.Owner, auth.FeeCollectorAddress(), vh.vm.NamespaceFee())
+	if err != nil {
+		return abciResult(err)
+	}
+	err = vh.vm.RegisterNamespace(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Handle MsgApprove.
+func (vh vmHandler) handleMsgApprove(ctx sdk.Context, msg MsgApprove) sdk.Result {
+	err := vh.vm.Approve(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
+// Amount charged by each MsgCall, beyond the fee of its tx.
+const callFee = "1000000ugnot" // XXX calculate
+
 // Handle MsgCall.
 func (vh vmHandler) handleMsgCall(ctx sdk.Context, msg MsgCall) (res sdk.Result) {
-	amount, err := std.ParseCoins("1000000ugnot") // XXX calculate
+	amount, err := std.ParseCoins(callFee)
 	if err != nil {
 		return abciResult(err)
 	}
@@ -82,35 +204,42 @@ func (vh vmHandler) handleMsgCall(ctx sdk.Context, msg MsgCall) (res sdk.Result)
 
 // query paths
 const (
-	QueryPackage = "package"
-	QueryStore   = "store"
-	QueryRender  = "qrender"
-	QueryFuncs   = "qfuncs"
-	QueryEval    = "qeval"
-	QueryFile    = "qfile"
+	QueryPackage   = "package"
+	QueryStore     = "store"
+	QueryRender    = "qrender"
+	QueryFuncs     = "qfuncs"
+	QueryEval      = "qeval"
+	QueryFile      = "qfile"
+	QueryStorage   = "qstorage"
+	QueryVersions  = "qversions"
+	QueryDeps      = "qdeps"
+	QueryVerifier  = "qverifier"
+	QueryNamespace = "qnamespace"
+	QueryPackages  = "qpackages"
+	QueryPkgInfo   = "qpkginfo"
+	QueryRealm     = "qrealm"
 )
 
-func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
-	switch secondPart(req.Path) {
-	case QueryPackage:
-		return vh.queryPackage(ctx, req)
-	case QueryStore:
-		return vh.queryStore(ctx, req)
-	case QueryRender:
-		return vh.queryRender(ctx, req)
-	case QueryFuncs:
-		return vh.queryFuncs(ctx, req)
-	case QueryEval:
-		return vh.queryEval(ctx, req)
-	case QueryFile:
-		return vh.queryFile(ctx, req)
-	default:
-		res = sdk.ABCIResponseQueryFromError(
-			std.ErrUnknownRequest(fmt.Sprintf(
-				"unknown vm query endpoint %s in %s",
-				secondPart(req.Path), req.Path)))
-		return
-	}
+// Adds the query routes of the vm, e.g. "vm/qrender", to qr.
+func (vh vmHandler) registerQueries(qr sdk.QueryRouter) {
+	qr.AddRoute(ModuleName+"/"+QueryPackage, vh.queryPackage)
+	qr.AddRoute(ModuleName+"/"+QueryStore, vh.queryStore)
+	qr.AddRoute(ModuleName+"/"+QueryRender, vh.queryRender)
+	qr.AddRoute(ModuleName+"/"+QueryFuncs, vh.queryFuncs)
+	qr.AddRoute(ModuleName+"/"+QueryEval, vh.queryEval)
+	qr.AddRoute(ModuleName+"/"+QueryFile, vh.queryFile)
+	qr.AddRoute(ModuleName+"/"+QueryStorage, vh.queryStorage)
+	qr.AddRoute(ModuleName+"/"+QueryVersions, vh.queryVersions)
+	qr.AddRoute(ModuleName+"/"+QueryDeps, vh.queryDeps)
+	qr.AddRoute(ModuleName+"/"+QueryVerifier, vh.queryVerifier)
+	qr.AddRoute(ModuleName+"/"+QueryNamespace, vh.queryNamespace)
+	qr.AddRoute(ModuleName+"/"+QueryPackages, vh.queryPackages)
+	qr.AddRoute(ModuleName+"/"+QueryPkgInfo, vh.queryPkgInfo)
+	qr.AddRoute(ModuleName+"/"+QueryRealm, vh.queryRealm)
+}
+
+func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) abci.ResponseQuery {
+	return sdk.RouteQuery(vh.queries, ctx, req)
 }
 
 // queryPackage fetch a package's files.
@@ -193,22 +322,119 @@ func (vh vmHandler) queryFile(ctx sdk.Context, req abci.RequestQuery) (res abci.
 	return
 }
 
-//----------------------------------------
-// misc
+// queryStorage returns the number of bytes stored by a package.
+func (vh vmHandler) queryStorage(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	usage, err := vh.vm.QueryStorage(ctx, pkgPath)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(fmt.Sprintf("%d", usage))
+	return
+}
 
-func abciResult(err error) sdk.Result {
-	return sdk.ABCIResultFromError(err)
+// queryVersions returns the paths of all versions of a package, one per line.
+func (vh vmHandler) queryVersions(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	versions, err := vh.vm.QueryVersions(ctx, pkgPath)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(strings.Join(versions, "\n"))
+	return
+}
+
+// queryDeps returns the paths of the on-chain packages imported by a
+// package, one per line.
+func (vh vmHandler) queryDeps(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	deps, err := vh.vm.QueryDeps(ctx, pkgPath)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(strings.Join(deps, "\n"))
+	return
+}
+
+// queryVerifier returns the verification realm of an account, if any.
+func (vh vmHandler) queryVerifier(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	b32addr := string(req.Data)
+	addr, err := crypto.AddressFromBech32(b32addr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInvalidAddress("invalid query address " + b32addr))
+		return
+	}
+	res.Data = []byte(vh.vm.QueryVerifier(ctx, addr))
+	return
 }
 
-// returns the second component of a path.
-func secondPart(path string) string {
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 {
-		return ""
-	} else {
-		if parts[0] != "vm" {
-			panic("should not happen")
+// queryNamespace returns the owner of a namespace, or "" if not registered.
+func (vh vmHandler) queryNamespace(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	namespace := string(req.Data)
+	owner := vh.vm.QueryNamespaceOwner(ctx, namespace)
+	if !owner.IsZero() {
+		res.Data = []byte(owner.Bech32())
+	}
+	return
+}
+
+// queryPackages returns a page of the paths of packages as JSON.  The
+// input data is the path prefix, and optionally the path to start at and
+// the maximum number of paths, one per line.
+func (vh vmHandler) queryPackages(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	reqData := string(req.Data)
+	reqParts := strings.Split(reqData, "\n")
+	if len(reqParts) > 3 {
+		panic("expected at most three lines in query input data")
+	}
+	prefix, start, limit := reqParts[0], "", 0
+	if len(reqParts) > 1 {
+		start = reqParts[1]
+	}
+	if len(reqParts) > 2 && reqParts[2] != "" {
+		var err error
+		limit, err = strconv.Atoi(reqParts[2])
+		if err != nil || limit < 0 {
+			res = sdk.ABCIResponseQueryFromError(
+				std.ErrUnknownRequest("invalid limit " + reqParts[2]))
+			return
 		}
-		return parts[1]
 	}
+	res.Data = []byte(vh.vm.QueryPackages(ctx, prefix, start, limit).JSON())
+	return
+}
+
+// queryPkgInfo returns the metadata of a package as JSON.
+func (vh vmHandler) queryPkgInfo(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	info, err := vh.vm.QueryPackageInfo(ctx, pkgPath)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(info.JSON())
+	return
+}
+
+// queryRealm returns the persisted state of a realm as JSON.
+func (vh vmHandler) queryRealm(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	result, err := vh.vm.QueryRealmState(ctx, pkgPath)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(err)
+		return
+	}
+	res.Data = []byte(result)
+	return
+}
+
+//----------------------------------------
+// misc
+
+func abciResult(err error) sdk.Result {
+	return sdk.ABCIResultFromError(err)
 }
@@ -6,12 +6,15 @@ import (
 	"strings"
 
 	"github.com/gnolang/gno"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/errors"
 	"github.com/gnolang/gno/pkgs/sdk"
 	"github.com/gnolang/gno/pkgs/sdk/auth"
 	"github.com/gnolang/gno/pkgs/sdk/bank"
 	"github.com/gnolang/gno/pkgs/std"
 	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/pkgs/store/gas"
 	"github.com/gnolang/gno/stdlibs"
 )
 
@@ -24,7 +27,10 @@ const (
 // smart contracts programming (scripting).
 type VMKeeperI interface {
 	AddPackage(ctx sdk.Context, msg MsgAddPackage) error
+	UpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) error
 	Call(ctx sdk.Context, msg MsgCall) (res string, err error)
+	LoadPackages(ctx sdk.Context)
+	ImportRealm(ctx sdk.Context, state *gno.RealmState) error
 }
 
 var _ VMKeeperI = &VMKeeper{}
@@ -37,6 +43,26 @@ type VMKeeper struct {
 	bank       bank.BankKeeper
 	stdlibsDir string
 
+	// price per byte of storage growth, or zero.
+	storagePrice std.Coin
+	// may upgrade or pause any package (e.g. governance), or zero.
+	upgradeAuthority crypto.Address
+	// if true, packages must be formatted (see gno.FormatSource).
+	requireFormatted bool
+	// if true, packages must not use floating-point types.
+	rejectFloats bool
+	// limits of added and upgraded packages.
+	packageLimits PackageLimits
+	// may deploy packages, or nil if anyone may.
+	deployWhitelist []crypto.Address
+	// fee of deployments and upgrades, flat and per byte of files.
+	deployFee        std.Coin
+	deployFeePerByte std.Coin
+	// fee of namespace registrations.
+	namespaceFee std.Coin
+	// denoms which realms may not issue, e.g. of the chain.
+	reservedDenoms []string
+
 	// cached, the DeliverTx persistent state.
 	gnoStore gno.Store
 }
@@ -44,15 +70,86 @@ type VMKeeper struct {
 // NewVMKeeper returns a new VMKeeper.
 func NewVMKeeper(baseKey store.StoreKey, iavlKey store.StoreKey, acck auth.AccountKeeper, bank bank.BankKeeper, stdlibsDir string) *VMKeeper {
 	vmk := &VMKeeper{
-		baseKey:    baseKey,
-		iavlKey:    iavlKey,
-		acck:       acck,
-		bank:       bank,
-		stdlibsDir: stdlibsDir,
+		baseKey:        baseKey,
+		iavlKey:        iavlKey,
+		acck:           acck,
+		bank:           bank,
+		stdlibsDir:     stdlibsDir,
+		packageLimits:  DefaultPackageLimits(),
+		reservedDenoms: []string{"ugnot"},
 	}
 	return vmk
 }
 
+// SetStoragePrice sets the price charged per byte of realm storage growth,
+// at deployment and upon calls.  A zero price (default) disables storage
+// fees, but storage usage is tracked regardless.
+func (vmk *VMKeeper) SetStoragePrice(price std.Coin) {
+	vmk.storagePrice = price
+}
+
+// SetRequireFormatted sets whether added and upgraded packages must be
+// formatted as by gno fmt, in which case unformatted packages are rejected.
+func (vmk *VMKeeper) SetRequireFormatted(require bool) {
+	vmk.requireFormatted = require
+}
+
+// SetRejectFloats sets whether added and upgraded packages which use
+// floating-point types are rejected, as their arithmetic may differ across
+// platforms (see gno.CheckNoFloats).
+func (vmk *VMKeeper) SetRejectFloats(reject bool) {
+	vmk.rejectFloats = reject
+}
+
+// SetUpgradeAuthority sets an address (e.g. of governance) that may upgrade
+// or pause any realm, in addition to the realm's creator.
+func (vmk *VMKeeper) SetUpgradeAuthority(addr crypto.Address) {
+	vmk.upgradeAuthority = addr
+}
+
+// SetPackageLimits sets the limits of added and upgraded packages, beyond
+// which they are rejected.
+func (vmk *VMKeeper) SetPackageLimits(limits PackageLimits) {
+	vmk.packageLimits = limits
+}
+
+// SetDeployWhitelist restricts the deployment of packages to addrs, e.g.
+// on permissioned chains, or lifts the restriction if addrs is nil.
+func (vmk *VMKeeper) SetDeployWhitelist(addrs []crypto.Address) {
+	vmk.deployWhitelist = addrs
+}
+
+// SetDeployFee sets the fee charged for each deployment or upgrade of a
+// package, beyond the fee of its tx, as a flat fee plus a fee per byte of
+// its files.  Either may be zero, as both are by default.
+func (vmk *VMKeeper) SetDeployFee(flat std.Coin, perByte std.Coin) {
+	vmk.deployFee = flat
+	vmk.deployFeePerByte = perByte
+}
+
+// SetNamespaceFee sets the fee charged for each namespace registration,
+// beyond the fee of its tx, so that namespaces are not squatted for free.
+// There is no fee by default.
+func (vmk *VMKeeper) SetNamespaceFee(fee std.Coin) {
+	vmk.namespaceFee = fee
+}
+
+// SetReservedDenoms sets the denoms which realms may not issue, nor
+// manage as their issuers, e.g. the native denoms of the chain ("ugnot" by
+// default).
+func (vmk *VMKeeper) SetReservedDenoms(denoms []string) {
+	vmk.reservedDenoms = denoms
+}
+
+func (vmk *VMKeeper) isReservedDenom(denom string) bool {
+	for _, reserved := range vmk.reservedDenoms {
+		if denom == reserved {
+			return true
+		}
+	}
+	return false
+}
+
 func (vmk *VMKeeper) Initialize(ms store.MultiStore) {
 	if vmk.gnoStore != nil {
 		panic("should not happen")
@@ -62,6 +159,7 @@ func (vmk *VMKeeper) Initialize(ms store.MultiStore) {
 	iavlSDKStore := ms.GetStore(vmk.iavlKey)
 	vmk.gnoStore = gno.NewStore(alloc, baseSDKStore, iavlSDKStore)
 	vmk.initBuiltinPackagesAndTypes(vmk.gnoStore)
+	vmk.loadGenesisState(iavlSDKStore)
 	if vmk.gnoStore.NumMemPackages() > 0 {
 		// for now, all mem packages must be re-run after reboot.
 		// TODO remove this, and generally solve for in-mem garbage collection
@@ -78,6 +176,25 @@ func (vmk *VMKeeper) Initialize(ms store.MultiStore) {
 	}
 }
 
+// LoadPackages preprocesses the packages in the stores of ctx, and loads the
+// configuration of the keeper kept in them, as done on restart by
+// Initialize, e.g. after their state is imported at genesis.  Objects are
+// stored by their IDs, which are kept by the import, so that their
+// references need not be relinked; and only the in-memory nodes of packages
+// are set, so that the state, and the app hash, do not change.
+func (vmk *VMKeeper) LoadPackages(ctx sdk.Context) {
+	vmk.loadGenesisState(ctx.Store(vmk.iavlKey))
+	m2 := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath: "",
+			Output:  os.Stdout, // XXX
+			Store:   vmk.getGnoStore(ctx),
+		})
+	gno.DisableDebug()
+	m2.PreprocessAllFilesAndSaveBlockNodes()
+	gno.EnableDebug()
+}
+
 func (vmk *VMKeeper) getGnoStore(ctx sdk.Context) gno.Store {
 	// construct main gnoStore if nil.
 	if vmk.gnoStore == nil {
@@ -87,26 +204,27 @@ func (vmk *VMKeeper) getGnoStore(ctx sdk.Context) gno.Store {
 	case sdk.RunTxModeDeliver:
 		// swap sdk store of existing gnoStore.
 		// this is needed due to e.g. gas wrappers.
-		baseSDKStore := ctx.Store(vmk.baseKey)
-		iavlSDKStore := ctx.Store(vmk.iavlKey)
+		baseSDKStore := gasStore(ctx, vmk.baseKey)
+		iavlSDKStore := gasStore(ctx, vmk.iavlKey)
 		vmk.gnoStore.SwapStores(baseSDKStore, iavlSDKStore)
 		// clear object cache for every transaction.
 		// NOTE: this is inefficient, but simple.
 		// in the future, replace with more advanced caching strategy.
 		vmk.gnoStore.ClearObjectCache()
+		vmk.gnoStore.ResetRealmStorageDiffs()
 		return vmk.gnoStore
 	case sdk.RunTxModeCheck:
 		// For query??? XXX Why not RunTxModeQuery?
 		simStore := vmk.gnoStore.Fork()
-		baseSDKStore := ctx.Store(vmk.baseKey)
-		iavlSDKStore := ctx.Store(vmk.iavlKey)
+		baseSDKStore := gasStore(ctx, vmk.baseKey)
+		iavlSDKStore := gasStore(ctx, vmk.iavlKey)
 		simStore.SwapStores(baseSDKStore, iavlSDKStore)
 		return simStore
 	case sdk.RunTxModeSimulate:
 		// always make a new store for simulate for isolation.
 		simStore := vmk.gnoStore.Fork()
-		baseSDKStore := ctx.Store(vmk.baseKey)
-		iavlSDKStore := ctx.Store(vmk.iavlKey)
+		baseSDKStore := gasStore(ctx, vmk.baseKey)
+		iavlSDKStore := gasStore(ctx, vmk.iavlKey)
 		simStore.SwapStores(baseSDKStore, iavlSDKStore)
 		return simStore
 	default:
@@ -115,7 +233,7 @@ func (vmk *VMKeeper) getGnoStore(ctx sdk.Context) gno.Store {
 }
 
 // AddPackage adds a package with given fileset.
-func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) error {
+func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	creator := msg.Creator
 	pkgPath := msg.Package.Path
 	memPkg := msg.Package
@@ -133,35 +251,301 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) error {
 	if err := msg.Package.Validate(); err != nil {
 		return ErrInvalidPkgPath(err.Error())
 	}
+	if err := vm.packageLimits.Check(memPkg); err != nil {
+		return err
+	}
+	if err := vm.checkFormat(memPkg); err != nil {
+		return err
+	}
 	if pv := store.GetPackage(pkgPath, false); pv != nil {
 		// TODO: return error instead of panicking?
 		panic("package already exists: " + pkgPath)
 	}
+	if _, version := splitVersion(pkgPath); version > 1 {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"versioned realm path is reserved for upgrades: %s", pkgPath))
+	}
+	if err := vm.checkDeployPolicy(ctx, creator, pkgPath); err != nil {
+		return err
+	}
+	// Resolve imports to their pinned versions.
+	memPkg, deps, err := resolveImports(ctx.Store(vm.iavlKey), store, memPkg)
+	if err != nil {
+		return err
+	}
 	// Pay deposit from creator.
 	pkgAddr := gno.DerivePkgAddr(pkgPath)
-	err := vm.bank.SendCoins(ctx, creator, pkgAddr, deposit)
+	err = vm.bank.SendCoins(ctx, creator, pkgAddr, deposit)
 	if err != nil {
 		return err
 	}
 	// Parse and run the files, construct *PV.
+	opCounts := newOpCounts(ctx)
+	params := getParams(ctx)
 	m2 := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:   "",
-			Output:    os.Stdout, // XXX
-			Store:     store,
-			Alloc:     store.GetAllocator(),
-			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			PkgPath:    "",
+			Output:     os.Stdout, // XXX
+			Store:      store,
+			Alloc:      store.GetAllocator(),
+			MaxCycles:  10 * 1000 * 1000, // 10M cycles // XXX
+			MaxFrames:  int(params.MaxFrames),
+			MaxValues:  int(params.MaxValues),
+			MaxExprs:   int(params.MaxExprs),
+			OpCounts:   opCounts,
+			CallTracer: newCallTracer(ctx),
+			RealmGuard: vm.newRealmGuard(ctx),
+			GasMeter:   ctx.GasMeter(),
+			GasConfig:  params.MachineGasConfig(),
+			FileChecks: vm.machineFileChecks(),
 		})
+	defer func() {
+		if r := recover(); r != nil {
+			if ex, ok := r.(realmGuardPanic); ok {
+				err = ex.err
+				return
+			}
+			if ex, ok := r.(fileCheckPanic); ok {
+				err = ErrTypeCheck(ex.err.Error())
+				return
+			}
+			panic(r)
+		}
+	}()
 	m2.RunMemPackage(memPkg, true)
 	fmt.Println("CPUCYCLES addpkg", m2.Cycles)
+	recordOpCounts(ctx, opCounts)
+	// Record creator for upgrades, and dependencies.
+	setPackageCreator(ctx.Store(vm.iavlKey), pkgPath, creator)
+	setPackageHeight(ctx.Store(vm.iavlKey), pkgPath, ctx.BlockHeight())
+	setPackageDeps(ctx.Store(vm.iavlKey), pkgPath, deps)
+	// Account for and charge storage.
+	return vm.processStorageDiffs(ctx, store, creator)
+}
+
+// CheckTx type-checks the packages added or upgraded by tx, so that those
+// ill-typed are rejected before entering the mempool, and consumes the
+// TypeCheckGasPerByte of the vm params per byte of their files.  On
+// DeliverTx, the packages are checked as they are preprocessed to run.  A
+// package importing one added earlier in tx is only checked then.  It is
+// the auth.AnteOptions.CheckTx of an application.
+func (vm *VMKeeper) CheckTx(ctx sdk.Context, tx std.Tx) error {
+	added := make(map[string]bool)
+	for _, msg := range tx.GetMsgs() {
+		var memPkg *std.MemPackage
+		switch msg := msg.(type) {
+		case MsgAddPackage:
+			memPkg = msg.Package
+		case MsgUpgradePackage:
+			memPkg = msg.Package
+		default:
+			continue
+		}
+		if err := vm.typeCheck(ctx, memPkg, added); err != nil {
+			return err
+		}
+		added[memPkg.Path] = true
+	}
+	return nil
+}
+
+// Type-checks memPkg, unless it imports a package of added.
+func (vm *VMKeeper) typeCheck(ctx sdk.Context, memPkg *std.MemPackage, added map[string]bool) error {
+	if err := vm.packageLimits.Check(memPkg); err != nil {
+		return err
+	}
+	imports, err := gno.MemPackageImports(memPkg)
+	if err != nil {
+		return ErrInvalidPkgPath(err.Error())
+	}
+	for _, path := range imports {
+		if added[path] {
+			return nil
+		}
+	}
+	size := 0
+	for _, mfile := range memPkg.Files {
+		size += len(mfile.Body)
+	}
+	ctx.GasMeter().ConsumeGas(getParams(ctx).TypeCheckGasPerByte*store.Gas(size), "TypeCheckMemPackage")
+	store := vm.getGnoStore(ctx)
+	memPkg, _, err = resolveImports(ctx.Store(vm.iavlKey), store, memPkg)
+	if err != nil {
+		return err
+	}
+	if err := gno.TypeCheckMemPackage(memPkg, store, vm.fileChecks()...); err != nil {
+		return ErrTypeCheck(err.Error())
+	}
+	return nil
+}
+
+// Returns an error if formatting is required and memPkg is not formatted.
+func (vm *VMKeeper) checkFormat(memPkg *std.MemPackage) error {
+	if !vm.requireFormatted {
+		return nil
+	}
+	if err := gno.CheckFormatMemPackage(memPkg); err != nil {
+		return ErrUnformattedPackage(err.Error())
+	}
 	return nil
 }
 
+// UpgradePackage adds a new version of a realm, and migrates the state of
+// the previous version into it.  The new version is a new package at the
+// next versioned path (e.g. "gno.land/r/foo/v2" for "gno.land/r/foo"); the
+// variables of the previous version are carried over into the variables of
+// the new version with the same names and types, and its migrate() function
+// if any then runs over them to migrate the rest, e.g. with the exported API
+// of the previous version, which it may import.  The previous version is
+// then paused for good, so that it and the realms calling it can no longer
+// be called, and its coins are moved to the new version.  Only the creator
+// of the original realm (or the upgrade authority) may upgrade it.
+func (vm *VMKeeper) UpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) (err error) {
+	creator := msg.Creator
+	pkgPath := msg.PkgPath
+	memPkg := msg.Package
+	deposit := msg.Deposit
+	store := vm.getGnoStore(ctx)
+	iavlStore := ctx.Store(vm.iavlKey)
+
+	// Validate arguments.
+	if creator.IsZero() {
+		return std.ErrInvalidAddress("missing creator address")
+	}
+	creatorAcc := vm.acck.GetAccount(ctx, creator)
+	if creatorAcc == nil {
+		return std.ErrUnknownAddress(fmt.Sprintf("account %s does not exist", creator))
+	}
+	if !gno.IsRealmPath(pkgPath) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package is not realm: %s", pkgPath))
+	}
+	if _, version := splitVersion(pkgPath); version > 1 {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package is not the original realm: %s", pkgPath))
+	}
+	if pv := store.GetPackage(pkgPath, false); pv == nil {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+	}
+	if err := memPkg.Validate(); err != nil {
+		return ErrInvalidPkgPath(err.Error())
+	}
+	if err := vm.packageLimits.Check(memPkg); err != nil {
+		return err
+	}
+	if err := vm.checkFormat(memPkg); err != nil {
+		return err
+	}
+	// Check permission.
+	owner := getPackageCreator(iavlStore, pkgPath)
+	if creator != owner && (vm.upgradeAuthority.IsZero() || creator != vm.upgradeAuthority) {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"%s may not upgrade package %s", creator, pkgPath))
+	}
+	// Check the new versioned path.
+	versions := getPackageVersions(iavlStore, pkgPath)
+	newPkgPath := fmt.Sprintf("%s/v%d", pkgPath, len(versions)+2)
+	if memPkg.Path != newPkgPath {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"expected new package path %s but got %s", newPkgPath, memPkg.Path))
+	}
+	if pv := store.GetPackage(newPkgPath, false); pv != nil {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package already exists: %s", newPkgPath))
+	}
+	if err := vm.checkDeployPolicy(ctx, creator, newPkgPath); err != nil {
+		return err
+	}
+	// Resolve imports to their pinned versions.
+	memPkg, deps, err := resolveImports(iavlStore, store, memPkg)
+	if err != nil {
+		return err
+	}
+	// Pay deposit from creator.
+	pkgAddr := gno.DerivePkgAddr(newPkgPath)
+	err = vm.bank.SendCoins(ctx, creator, pkgAddr, deposit)
+	if err != nil {
+		return err
+	}
+	// Parse and run the files, construct *PV.
+	msgCtx := stdlibs.ExecContext{
+		ChainID:      ctx.ChainID(),
+		Height:       ctx.BlockHeight(),
+		Timestamp:    ctx.BlockTime().Unix(),
+		BlockEntropy: lastBlockHash(ctx),
+		Msg:          msg,
+		OrigCaller:   creator.Bech32(),
+		OrigPkgAddr:  pkgAddr.Bech32(),
+		Banker:       NewSDKBanker(vm, ctx),
+		Scheduler:    NewSDKScheduler(vm, ctx),
+		EventEmitter: NewSDKEventEmitter(ctx),
+	}
+	params := getParams(ctx)
+	m := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:    "",
+			Output:     os.Stdout, // XXX
+			Store:      store,
+			Context:    msgCtx,
+			Alloc:      store.GetAllocator(),
+			MaxCycles:  10 * 1000 * 1000, // 10M cycles // XXX
+			MaxFrames:  int(params.MaxFrames),
+			MaxValues:  int(params.MaxValues),
+			MaxExprs:   int(params.MaxExprs),
+			CallTracer: newCallTracer(ctx),
+			RealmGuard: vm.newRealmGuard(ctx),
+			GasMeter:   ctx.GasMeter(),
+			GasConfig:  params.MachineGasConfig(),
+			FileChecks: vm.machineFileChecks(),
+		})
+	defer func() {
+		if r := recover(); r != nil {
+			err = vmPanicError(m, r, "VM upgrade panic")
+			return
+		}
+	}()
+	_, pv := m.RunMemPackage(memPkg, true)
+	// Carry over the state of the previous version, and run migrate() if
+	// declared.
+	prevPkgPath := pkgPath
+	if len(versions) > 0 {
+		prevPkgPath = versions[len(versions)-1]
+	}
+	prev := store.GetPackage(prevPkgPath, false)
+	if _, err := m.CarryOverVars(prev, pv); err != nil {
+		return ErrMigration(err.Error())
+	}
+	if hasMigrateFunc(store, pv) {
+		m.Eval(gno.Call("migrate"))
+	}
+	// Freeze the previous version, so that its state is not forked, and
+	// move its coins to the new version.
+	setPackagePaused(iavlStore, prevPkgPath, true)
+	prevPkgAddr := gno.DerivePkgAddr(prevPkgPath)
+	if coins := vm.bank.GetCoins(ctx, prevPkgAddr); !coins.IsZero() {
+		if err := vm.bank.SendCoins(ctx, prevPkgAddr, pkgAddr, coins); err != nil {
+			return err
+		}
+	}
+	// Record creator, new version and dependencies.
+	setPackageCreator(iavlStore, newPkgPath, owner)
+	setPackageVersions(iavlStore, pkgPath, append(versions, newPkgPath))
+	setPackageHeight(iavlStore, newPkgPath, ctx.BlockHeight())
+	setPackageDeps(iavlStore, newPkgPath, deps)
+	// Account for and charge storage.
+	return vm.processStorageDiffs(ctx, store, creator)
+}
+
 // Calls calls a public Gno function (for delivertx).
 func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	pkgPath := msg.PkgPath // to import
 	fnc := msg.Func
 	store := vm.getGnoStore(ctx)
+	if isPackagePaused(ctx.Store(vm.iavlKey), pkgPath) {
+		return "", ErrPackagePaused(fmt.Sprintf(
+			"package %s is paused", pkgPath))
+	}
 	// Get the package and function type.
 	pv := store.GetPackage(pkgPath, false)
 	pl := gno.PackageNodeLocation(pkgPath)
@@ -208,28 +592,41 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 		ChainID:       ctx.ChainID(),
 		Height:        ctx.BlockHeight(),
 		Timestamp:     ctx.BlockTime().Unix(),
+		BlockEntropy:  lastBlockHash(ctx),
 		Msg:           msg,
 		OrigCaller:    caller.Bech32(),
 		OrigSend:      send,
 		OrigSendSpent: new(std.Coins),
 		OrigPkgAddr:   pkgAddr.Bech32(),
 		Banker:        NewSDKBanker(vm, ctx),
+		Scheduler:     NewSDKScheduler(vm, ctx),
+		EventEmitter:  NewSDKEventEmitter(ctx),
 	}
 	// Construct machine and evaluate.
+	opCounts := newOpCounts(ctx)
+	params := getParams(ctx)
 	m := gno.NewMachineWithOptions(
 		gno.MachineOptions{
-			PkgPath:   "",
-			Output:    os.Stdout, // XXX
-			Store:     store,
-			Context:   msgCtx,
-			Alloc:     store.GetAllocator(),
-			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			PkgPath:    "",
+			Output:     os.Stdout, // XXX
+			Store:      store,
+			Context:    msgCtx,
+			Alloc:      store.GetAllocator(),
+			MaxCycles:  10 * 1000 * 1000, // 10M cycles // XXX
+			MaxFrames:  int(params.MaxFrames),
+			MaxValues:  int(params.MaxValues),
+			MaxExprs:   int(params.MaxExprs),
+			OpCounts:   opCounts,
+			CallTracer: newCallTracer(ctx),
+			RealmGuard: vm.newRealmGuard(ctx),
+			GasMeter:   ctx.GasMeter(),
+			GasConfig:  params.MachineGasConfig(),
 		})
 	m.SetActivePackage(mpv)
 	defer func() {
+		recordOpCounts(ctx, opCounts)
 		if r := recover(); r != nil {
-			err = errors.New("VM call panic: %v\n%s\n",
-				r, m.String())
+			err = vmPanicError(m, r, "VM call panic")
 			return
 		}
 	}()
@@ -241,6 +638,10 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 			res += "\n"
 		}
 	}
+	// Account for and charge storage.
+	if err := vm.processStorageDiffs(ctx, store, caller); err != nil {
+		return "", err
+	}
 	return res, nil
 	// TODO pay for gas? TODO see context?
 }
@@ -326,9 +727,10 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 	}
 	// Construct new machine.
 	msgCtx := stdlibs.ExecContext{
-		ChainID:   ctx.ChainID(),
-		Height:    ctx.BlockHeight(),
-		Timestamp: ctx.BlockTime().Unix(),
+		ChainID:      ctx.ChainID(),
+		Height:       ctx.BlockHeight(),
+		Timestamp:    ctx.BlockTime().Unix(),
+		BlockEntropy: lastBlockHash(ctx),
 		// Msg:           msg,
 		// OrigCaller:    caller,
 		// OrigSend:      send,
@@ -336,6 +738,7 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 		OrigPkgAddr: pkgAddr.Bech32(),
 		Banker:      NewSDKBanker(vm, ctx), // safe as long as ctx is a fork to be discarded.
 	}
+	params := getParams(ctx)
 	m := gno.NewMachineWithOptions(
 		gno.MachineOptions{
 			PkgPath:   pkgPath,
@@ -344,6 +747,9 @@ func (vm *VMKeeper) QueryEval(ctx sdk.Context, pkgPath string, expr string) (res
 			Context:   msgCtx,
 			Alloc:     alloc,
 			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			MaxFrames: int(params.MaxFrames),
+			MaxValues: int(params.MaxValues),
+			MaxExprs:  int(params.MaxExprs),
 		})
 	rtvs := m.Eval(xx)
 	res = ""
@@ -378,9 +784,10 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 	}
 	// Construct new machine.
 	msgCtx := stdlibs.ExecContext{
-		ChainID:   ctx.ChainID(),
-		Height:    ctx.BlockHeight(),
-		Timestamp: ctx.BlockTime().Unix(),
+		ChainID:      ctx.ChainID(),
+		Height:       ctx.BlockHeight(),
+		Timestamp:    ctx.BlockTime().Unix(),
+		BlockEntropy: lastBlockHash(ctx),
 		// Msg:           msg,
 		// OrigCaller:    caller,
 		// OrigSend:      jsend,
@@ -388,6 +795,7 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 		OrigPkgAddr: pkgAddr.Bech32(),
 		Banker:      NewSDKBanker(vm, ctx), // safe as long as ctx is a fork to be discarded.
 	}
+	params := getParams(ctx)
 	m := gno.NewMachineWithOptions(
 		gno.MachineOptions{
 			PkgPath:   pkgPath,
@@ -396,6 +804,9 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 			Context:   msgCtx,
 			Alloc:     alloc,
 			MaxCycles: 10 * 1000 * 1000, // 10M cycles // XXX
+			MaxFrames: int(params.MaxFrames),
+			MaxValues: int(params.MaxValues),
+			MaxExprs:  int(params.MaxExprs),
 		})
 	rtvs := m.Eval(xx)
 	if len(rtvs) != 1 {
@@ -407,9 +818,39 @@ func (vm *VMKeeper) QueryEvalString(ctx sdk.Context, pkgPath string, expr string
 	return res, nil
 }
 
+// QueryVersions returns all versions of the package at pkgPath, starting
+// with pkgPath itself.
+func (vm *VMKeeper) QueryVersions(ctx sdk.Context, pkgPath string) (versions []string, err error) {
+	store := vm.getGnoStore(ctx)
+	if pv := store.GetPackage(pkgPath, false); pv == nil {
+		err = ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+		return nil, err
+	}
+	versions = getPackageVersions(ctx.Store(vm.iavlKey), pkgPath)
+	return append([]string{pkgPath}, versions...), nil
+}
+
+// QueryDeps returns the paths of the on-chain packages imported by the
+// package at pkgPath, as resolved when it was added.
+func (vm *VMKeeper) QueryDeps(ctx sdk.Context, pkgPath string) (deps []string, err error) {
+	store := vm.getGnoStore(ctx)
+	if pv := store.GetPackage(pkgPath, false); pv == nil {
+		err = ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+		return nil, err
+	}
+	return getPackageDeps(ctx.Store(vm.iavlKey), pkgPath), nil
+}
+
 func (vm *VMKeeper) QueryFile(ctx sdk.Context, filepath string) (res string, err error) {
 	store := vm.getGnoStore(ctx)
 	dirpath, filename := std.SplitFilepath(filepath)
+	if pv := store.GetPackage(dirpath, false); pv == nil {
+		err = ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", dirpath))
+		return "", err
+	}
 	if filename != "" {
 		memFile := store.GetMemFile(dirpath, filename)
 		if memFile == nil {
@@ -427,3 +868,105 @@ func (vm *VMKeeper) QueryFile(ctx sdk.Context, filepath string) (res string, err
 		return res, nil
 	}
 }
+
+// fileChecks returns the checks of the files of added and upgraded
+// packages, per the policies of the keeper.
+func (vm *VMKeeper) fileChecks() []gno.FileCheck {
+	var checks []gno.FileCheck
+	if vm.rejectFloats {
+		checks = append(checks, gno.CheckNoFloats)
+	}
+	return checks
+}
+
+// Panicked by the file checks of the machines of txs, and returned as a
+// type check error by the keeper (see vmPanicError).
+type fileCheckPanic struct {
+	err error
+}
+
+// machineFileChecks returns the fileChecks run by the machines of txs as
+// they preprocess the files, which panic a fileCheckPanic.
+func (vm *VMKeeper) machineFileChecks() []gno.FileCheck {
+	checks := vm.fileChecks()
+	for i, check := range checks {
+		check := check
+		checks[i] = func(fn *gno.FileNode) error {
+			if err := check(fn); err != nil {
+				panic(fileCheckPanic{err})
+			}
+			return nil
+		}
+	}
+	return checks
+}
+
+// gasStore returns the store for key, wrapped for gas calculation per the
+// vm params of ctx.
+func gasStore(ctx sdk.Context, key store.StoreKey) store.Store {
+	return gas.New(ctx.MultiStore().GetStore(key), ctx.GasMeter(), getParams(ctx).StoreGasConfig())
+}
+
+// lastBlockHash returns the hash of the last block, from the header of ctx,
+// or nil before the first block.
+func lastBlockHash(ctx sdk.Context) []byte {
+	if header, ok := ctx.BlockHeader().(*bft.Header); ok {
+		return header.LastBlockID.Hash
+	}
+	return nil
+}
+
+// isOutOfGas returns true if r, recovered from a panic, is due to running out
+// of gas.
+// Returns the error of the panic r of a VM call on m, with the gno call
+// stack of the panic.  Out of gas panics are raised again, with the stack
+// in their descriptor, as they are handled by baseapp.
+func vmPanicError(m *gno.Machine, r interface{}, msg string) error {
+	if ex, ok := r.(realmGuardPanic); ok {
+		return ex.err
+	}
+	if ex, ok := r.(fileCheckPanic); ok {
+		return ErrTypeCheck(ex.err.Error())
+	}
+	exception := fmt.Sprintf("%v", r)
+	st := m.PanicStacktrace()
+	if st != nil {
+		exception = m.Exception.Sprint(m)
+	} else {
+		st = m.Stacktrace()
+	}
+	if ex, ok := r.(store.OutOfGasException); ok {
+		ex.Descriptor += " in\n" + st.String()
+		panic(ex)
+	}
+	return ErrVMPanic(exception, st, msg)
+}
+
+func isOutOfGas(r interface{}) bool {
+	_, ok := r.(store.OutOfGasException)
+	return ok
+}
+
+// newOpCounts returns op counters for the machine if the gas meter of ctx
+// is an audit gas meter (e.g. for simulated txs), otherwise nil.
+func newOpCounts(ctx sdk.Context) *[256]int64 {
+	if _, ok := ctx.GasMeter().(*store.AuditGasMeter); ok {
+		return new([256]int64)
+	}
+	return nil
+}
+
+// recordOpCounts records the op counts of a machine into the audit gas meter
+// of ctx.  Gas for ops is recorded in aggregate as "CPUCycles" by the
+// machine, so no gas is recorded here.
+func recordOpCounts(ctx sdk.Context, opCounts *[256]int64) {
+	if opCounts == nil {
+		return
+	}
+	agm := ctx.GasMeter().(*store.AuditGasMeter)
+	for op, count := range opCounts {
+		if count > 0 {
+			agm.Record("VM:"+gno.Op(op).String(), count, 0)
+		}
+	}
+}
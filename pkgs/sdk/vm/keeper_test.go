@@ -6,9 +6,17 @@ import (
 	"testing"
 
 	"github.com/jaekwon/testify/assert"
+	"github.com/jaekwon/testify/require"
 
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/amino"
+	bft "github.com/gnolang/gno/pkgs/bft/types"
 	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/sdk"
 	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/stdlibs"
 )
 
 // Sending total send amount succeeds.
@@ -233,3 +241,2069 @@ func Echo(msg string) string {
 	_, err = env.vmk.Call(ctx, msg2)
 	assert.Error(t, err)
 }
+
+// Storage usage is tracked, and growth is charged if a price is set.
+func TestVMKeeperStorageUsage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+	env.vmk.SetStoragePrice(std.NewCoin("ugnot", 1))
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+var words []string
+
+func Add(word string) {
+	words = append(words, word)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+	usage1, err := env.vmk.QueryStorage(ctx, pkgPath)
+	assert.NoError(t, err)
+	assert.True(t, usage1 > 0)
+	balance1 := env.bank.GetCoins(ctx, addr).AmountOf("ugnot")
+	assert.Equal(t, int64(10000000)-usage1, balance1)
+
+	// Grow the realm.
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Add", []string{"hello world"})
+	_, err = env.vmk.Call(ctx, msg2)
+	assert.NoError(t, err)
+	usage2, err := env.vmk.QueryStorage(ctx, pkgPath)
+	assert.NoError(t, err)
+	assert.True(t, usage2 > usage1)
+	balance2 := env.bank.GetCoins(ctx, addr).AmountOf("ugnot")
+	assert.Equal(t, balance1-(usage2-usage1), balance2)
+
+	// Unknown packages have no usage.
+	_, err = env.vmk.QueryStorage(ctx, "gno.land/r/unknown")
+	assert.Error(t, err)
+}
+
+// Unreachable reference cycles are deleted, and the storage freed is
+// refunded as gas.
+func TestVMKeeperStorageCycles(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+type Node struct {
+	Word string
+	Next *Node
+}
+
+var root *Node
+
+func Add(word string) {
+	a := &Node{Word: word}
+	b := &Node{Word: word, Next: a}
+	a.Next = b
+	root = a
+}
+
+func Clear() {
+	root = nil
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+	usage1, err := env.vmk.QueryStorage(ctx, pkgPath)
+	assert.NoError(t, err)
+
+	// Grow the realm with a cycle.
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)})
+	_, err = env.vmk.Call(ctx, msg2)
+	assert.NoError(t, err)
+	usage2, err := env.vmk.QueryStorage(ctx, pkgPath)
+	assert.NoError(t, err)
+	assert.True(t, usage2 > usage1+2000)
+
+	// Drop the cycle.
+	gasMeter := store.NewAuditGasMeter(store.NewInfiniteGasMeter())
+	gctx := ctx.WithGasMeter(gasMeter)
+	msg3 := NewMsgCall(addr, nil, pkgPath, "Clear", nil)
+	_, err = env.vmk.Call(gctx, msg3)
+	assert.NoError(t, err)
+	usage3, err := env.vmk.QueryStorage(ctx, pkgPath)
+	assert.NoError(t, err)
+	assert.True(t, usage3 < usage1+100)
+	found := false
+	for _, entry := range gasMeter.Entries() {
+		if entry.Descriptor == "StorageRefund" {
+			found = true
+			assert.True(t, entry.Gas < -20000)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestVMKeeperExportImportRealm(t *testing.T) {
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+var list []*int
+
+func Add(n int) {
+	list = append(list, &n)
+}
+
+func Sum() int {
+	sum := 0
+	for _, n := range list {
+		sum += *n
+	}
+	return sum
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	newEnv := func() testEnv {
+		env := setupTestEnv()
+		acc := env.acck.NewAccountWithAddress(env.ctx, addr)
+		env.acck.SetAccount(env.ctx, acc)
+		env.bank.SetCoins(env.ctx, addr, std.MustParseCoins("10000000ugnot"))
+		msg := NewMsgAddPackage(addr, pkgPath, files)
+		err := env.vmk.AddPackage(env.ctx, msg)
+		assert.NoError(t, err)
+		return env
+	}
+
+	// Export the realm after some calls.
+	env1 := newEnv()
+	for _, n := range []string{"1", "2", "3"} {
+		msg := NewMsgCall(addr, nil, pkgPath, "Add", []string{n})
+		_, err := env1.vmk.Call(env1.ctx, msg)
+		assert.NoError(t, err)
+	}
+	res, err := env1.vmk.QueryRealmState(env1.ctx, pkgPath)
+	assert.NoError(t, err)
+	var state gno.RealmState
+	amino.MustUnmarshalJSON([]byte(res), &state)
+	_, err = env1.vmk.QueryRealmState(env1.ctx, "gno.land/r/none")
+	assert.Error(t, err)
+
+	// Import it into another chain, whose realm state differs.
+	env2 := newEnv()
+	msg := NewMsgCall(addr, nil, pkgPath, "Add", []string{"10"})
+	_, err = env2.vmk.Call(env2.ctx, msg)
+	assert.NoError(t, err)
+	err = env2.vmk.ImportRealm(env2.ctx, &state)
+	assert.NoError(t, err)
+	res, err = env2.vmk.QueryEval(env2.ctx, pkgPath, "Sum()")
+	assert.NoError(t, err)
+	assert.Equal(t, `(6 int)`, res)
+	usage1, err := env1.vmk.QueryStorage(env1.ctx, pkgPath)
+	assert.NoError(t, err)
+	usage2, err := env2.vmk.QueryStorage(env2.ctx, pkgPath)
+	assert.NoError(t, err)
+	assert.Equal(t, usage1, usage2)
+
+	// The imported realm keeps working.
+	msg = NewMsgCall(addr, nil, pkgPath, "Add", []string{"4"})
+	_, err = env2.vmk.Call(env2.ctx, msg)
+	assert.NoError(t, err)
+	res, err = env2.vmk.QueryEval(env2.ctx, pkgPath, "Sum()")
+	assert.NoError(t, err)
+	assert.Equal(t, `(10 int)`, res)
+
+	// The realm must exist.
+	state.Realm = gno.NewRealm("gno.land/r/other")
+	err = env2.vmk.ImportRealm(env2.ctx, &state)
+	assert.Error(t, err)
+}
+
+func TestVMKeeperUpgradePackage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" and "addr2" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	env.acck.SetAccount(ctx, acc2)
+	env.bank.SetCoins(ctx, addr2, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+type User struct {
+	Name   string
+	Scores []int
+	next   *User
+}
+
+var count int
+var names []string
+var scores = map[string]int{}
+var label string = "v1"
+var users map[string]*User = map[string]*User{}
+var last *User
+
+func Inc(name string) int {
+	count++
+	names = append(names, name)
+	scores[name] += count
+	users[name] = &User{Name: name, Scores: []int{count}, next: last}
+	last = users[name]
+	return count
+}
+
+func Count() int {
+	return count
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	newPkgPath := pkgPath + "/v2"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+	for _, name := range []string{"alice", "bob"} {
+		msg2 := NewMsgCall(addr, std.MustParseCoins("1000ugnot"), pkgPath, "Inc", []string{name})
+		_, err = env.vmk.Call(ctx, msg2)
+		assert.NoError(t, err)
+	}
+	// Versioned paths are reserved for upgrades.
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, newPkgPath, files))
+	assert.Error(t, err)
+	assert.Equal(t, errors.Cause(err), InvalidPkgPathError{})
+
+	// The types of variables may not change, including the types declared
+	// in the realm.
+	for i, decls := range []string{
+		"var count int\nvar label int",
+		"type User struct {\n\tName string\n}\nvar users map[string]*User",
+		"var count int\nfunc label() {}",
+	} {
+		path := fmt.Sprintf("gno.land/r/types%d", i)
+		err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, path, files))
+		assert.NoError(t, err)
+		files := []*std.MemFile{{"init.gno", "package test\n\n" + decls}}
+		msg := NewMsgUpgradePackage(addr, path, path+"/v2", files)
+		err = env.vmk.UpgradePackage(ctx, msg)
+		assert.Equal(t, errors.Cause(err), MigrationError{}, decls)
+	}
+
+	// Upgrade with migration from previous version: the variables with the
+	// same names and types are carried over, and migrate() runs over them.
+	files2 := []*std.MemFile{
+		{"init.gno", `
+package test
+
+import (
+	"strings"
+
+	"gno.land/r/test"
+)
+
+type User struct {
+	Name   string
+	Scores []int
+	next   *User
+}
+
+var count int
+var names []string
+var scores map[string]int
+var label string
+var users map[string]*User
+var last *User
+var total int
+
+func migrate() {
+	total = test.Count() * 10
+	for _, score := range scores {
+		total += score
+	}
+}
+
+func Inc(name string) int {
+	count++
+	names = append(names, name)
+	scores[name] += count
+	return count
+}
+
+func Info() string {
+	return strings.Join(names, ",") + " " + label + " " + strconv(total) + " " + last.next.Name
+}
+
+func Score(name string) int {
+	return scores[name]
+}
+
+func strconv(i int) string {
+	if i < 10 {
+		return string(rune('0' + i))
+	}
+	return strconv(i/10) + strconv(i%10)
+}`},
+	}
+	// Only the creator may upgrade.
+	msg3 := NewMsgUpgradePackage(addr2, pkgPath, newPkgPath, files2)
+	err = env.vmk.UpgradePackage(ctx, msg3)
+	assert.Error(t, err)
+	// Versions must be sequential.
+	msg4 := NewMsgUpgradePackage(addr, pkgPath, pkgPath+"/v3", files2)
+	err = env.vmk.UpgradePackage(ctx, msg4)
+	assert.Error(t, err)
+	msg5 := NewMsgUpgradePackage(addr, pkgPath, newPkgPath, files2)
+	err = env.vmk.UpgradePackage(ctx, msg5)
+	assert.NoError(t, err)
+	res, err := env.vmk.QueryEval(ctx, newPkgPath, "Info()")
+	assert.NoError(t, err)
+	assert.Equal(t, `("alice,bob v1 23 alice" string)`, res)
+	res, err = env.vmk.QueryEval(ctx, newPkgPath, "users[\"bob\"].Scores[0]")
+	assert.NoError(t, err)
+	assert.Equal(t, `(2 int)`, res)
+	// The previous version is frozen, and its coins moved.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", []string{"dave"}))
+	assert.Equal(t, errors.Cause(err), PackagePausedError{})
+	err = env.vmk.PausePackage(ctx, NewMsgPausePackage(addr, pkgPath, false))
+	assert.Equal(t, errors.Cause(err), PackagePausedError{})
+	assert.True(t, env.bank.GetCoins(ctx, gno.DerivePkgAddr(pkgPath)).IsZero())
+	assert.True(t, env.bank.GetCoins(ctx, gno.DerivePkgAddr(newPkgPath)).IsAllGTE(std.MustParseCoins("2000ugnot")))
+	// The state of the new version is its own.
+	msg6 := NewMsgCall(addr, nil, newPkgPath, "Inc", []string{"carol"})
+	_, err = env.vmk.Call(ctx, msg6)
+	assert.NoError(t, err)
+	res, err = env.vmk.QueryEval(ctx, newPkgPath, "Score(\"carol\")")
+	assert.NoError(t, err)
+	assert.Equal(t, `(3 int)`, res)
+	res, err = env.vmk.QueryEval(ctx, pkgPath, "Count()")
+	assert.NoError(t, err)
+	assert.Equal(t, `(2 int)`, res)
+
+	// Versions are recorded.
+	versions, err := env.vmk.QueryVersions(ctx, pkgPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{pkgPath, newPkgPath}, versions)
+	// Only the original realm is upgraded.
+	msg7 := NewMsgUpgradePackage(addr, newPkgPath, pkgPath+"/v3", files2)
+	err = env.vmk.UpgradePackage(ctx, msg7)
+	assert.Equal(t, errors.Cause(err), InvalidPkgPathError{})
+
+	// Pointers to variables can't be carried over.
+	files3 := []*std.MemFile{
+		{"init.gno", `
+package ptr
+
+var count int
+var ptr = &count`},
+	}
+	ptrPath := "gno.land/r/ptr"
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, ptrPath, files3))
+	assert.NoError(t, err)
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, ptrPath, ptrPath+"/v2", files3))
+	assert.Equal(t, errors.Cause(err), MigrationError{})
+}
+
+// Realms are versioned at any depth, e.g. in namespaces.
+func TestVMKeeperUpgradeNamespacedPackage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" and "addr2" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	env.acck.SetAccount(ctx, acc2)
+	env.bank.SetCoins(ctx, addr2, std.MustParseCoins("10000000ugnot"))
+
+	files := []*std.MemFile{
+		{"init.gno", `
+package foo
+
+var count int
+
+func Inc() int {
+	count++
+	return count
+}
+
+func Count() int {
+	return count
+}`},
+	}
+	pkgPath := "gno.land/r/alice/foo"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	assert.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", nil))
+	assert.NoError(t, err)
+
+	// The versioned paths of the realm may not be squatted.
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, pkgPath+"/v2", files))
+	assert.Equal(t, errors.Cause(err), InvalidPkgPathError{})
+	// Nor do they need to be: the first version is the realm itself.
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, "gno.land/r/bob/foo/v1", files))
+	assert.NoError(t, err)
+
+	// Upgrade to v2, then v3.
+	v2Path := pkgPath + "/v2"
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, v2Path, files))
+	assert.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, v2Path, "Inc", nil))
+	assert.NoError(t, err)
+	// Only the original realm is upgraded.
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, v2Path, v2Path+"/v2", files))
+	assert.Equal(t, errors.Cause(err), InvalidPkgPathError{})
+	v3Path := pkgPath + "/v3"
+	files3 := []*std.MemFile{
+		{"init.gno", `
+package foo
+
+import (
+	prev "gno.land/r/alice/foo/v2"
+)
+
+var count int
+
+func Prev() int {
+	return prev.Count()
+}`},
+	}
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, v3Path, files3))
+	assert.NoError(t, err)
+	versions, err := env.vmk.QueryVersions(ctx, pkgPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{pkgPath, v2Path, v3Path}, versions)
+
+	// The paused previous version may only be called by the later one.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, v2Path, "Inc", nil))
+	assert.Equal(t, errors.Cause(err), PackagePausedError{})
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, v3Path, "Prev", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, `(2 int)`, res)
+}
+
+func TestVMKeeperPausePackage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" and "addr2" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	env.acck.SetAccount(ctx, acc2)
+	env.bank.SetCoins(ctx, addr2, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+var count int
+
+func Inc() int {
+	count++
+	return count
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+	files2 := []*std.MemFile{
+		{"init.gno", `
+package proxy
+
+import "gno.land/r/test"
+
+func Inc() int {
+	return test.Inc()
+}`},
+	}
+	proxyPath := "gno.land/r/proxy"
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, proxyPath, files2))
+	assert.NoError(t, err)
+
+	// Only the creator or the upgrade authority may pause.
+	err = env.vmk.PausePackage(ctx, NewMsgPausePackage(addr2, pkgPath, true))
+	assert.Error(t, err)
+	err = env.vmk.PausePackage(ctx, NewMsgPausePackage(addr, pkgPath, true))
+	assert.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", nil))
+	assert.Equal(t, errors.Cause(err), PackagePausedError{})
+
+	// Nor through another realm, deployed before or after the pause.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, proxyPath, "Inc", nil))
+	assert.Equal(t, errors.Cause(err), PackagePausedError{})
+	files3 := []*std.MemFile{
+		{"init.gno", `
+package proxy2
+
+import "gno.land/r/test"
+
+var count = test.Inc()`},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/proxy2", files3))
+	assert.Equal(t, errors.Cause(err), PackagePausedError{})
+
+	// Unpause by the upgrade authority.
+	env.vmk.SetUpgradeAuthority(addr2)
+	err = env.vmk.PausePackage(ctx, NewMsgPausePackage(addr2, pkgPath, false))
+	assert.NoError(t, err)
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(1 int)")
+	res, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, proxyPath, "Inc", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(2 int)")
+
+	// A paused realm may still be entered by its later versions.
+	err = env.vmk.PausePackage(ctx, NewMsgPausePackage(addr, pkgPath, true))
+	assert.NoError(t, err)
+	files4 := []*std.MemFile{
+		{"init.gno", `
+package test
+
+import "gno.land/r/test"
+
+var count int
+
+func migrate() {
+	count = test.Inc()
+}`},
+	}
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, pkgPath+"/v2", files4))
+	assert.NoError(t, err)
+	res, err = env.vmk.QueryEval(ctx, pkgPath+"/v2", "count")
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(3 int)")
+}
+
+func TestVMKeeperDeployPolicy(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" and "addr2" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	env.acck.SetAccount(ctx, acc2)
+	env.bank.SetCoins(ctx, addr2, std.MustParseCoins("10000000ugnot"))
+
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+func Echo() string {
+	return "hello"
+}`},
+	}
+
+	// Only the owner may deploy in a registered namespace.
+	err := env.vmk.RegisterNamespace(ctx, NewMsgRegisterNamespace(addr, "alice"))
+	assert.NoError(t, err)
+	err = env.vmk.RegisterNamespace(ctx, NewMsgRegisterNamespace(addr2, "alice"))
+	assert.Error(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, "gno.land/r/alice", files))
+	assert.Error(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/alice", files))
+	assert.NoError(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, "gno.land/p/bob/test", files))
+	assert.NoError(t, err)
+
+	// Namespaces holding packages of others may not be registered.
+	err = env.vmk.RegisterNamespace(ctx, NewMsgRegisterNamespace(addr, "bob"))
+	assert.Error(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, "gno.land/r/bobby", files))
+	assert.NoError(t, err)
+	err = env.vmk.RegisterNamespace(ctx, NewMsgRegisterNamespace(addr, "bob"))
+	assert.Error(t, err)
+	err = env.vmk.RegisterNamespace(ctx, NewMsgRegisterNamespace(addr2, "bob"))
+	assert.NoError(t, err)
+	err = env.vmk.RegisterNamespace(ctx, NewMsgRegisterNamespace(addr, "bo"))
+	assert.NoError(t, err)
+
+	// Registrations are charged by the handler, and free by default.
+	assert.True(t, env.vmk.NamespaceFee().IsZero())
+	h := NewHandler(env.vmk)
+	env.vmk.SetNamespaceFee(std.NewCoin("ugnot", 1000))
+	before := env.bank.GetCoins(ctx, addr)
+	res := h.Process(ctx, NewMsgRegisterNamespace(addr, "carol"))
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, before.Sub(std.MustParseCoins("1000ugnot")), env.bank.GetCoins(ctx, addr))
+	env.vmk.SetNamespaceFee(std.NewCoin("ugnot", 1000000000))
+	res = h.Process(ctx, NewMsgRegisterNamespace(addr, "dave"))
+	assert.False(t, res.IsOK())
+	assert.True(t, env.vmk.QueryNamespaceOwner(ctx, "dave").IsZero())
+
+	// Only whitelisted accounts may deploy in whitelist mode.
+	env.vmk.SetDeployWhitelist([]crypto.Address{addr})
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr2, "gno.land/r/bob", files))
+	assert.Error(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/p/alice/test", files))
+	assert.NoError(t, err)
+
+	// The same policy applies to upgrades.
+	upgrade := NewMsgUpgradePackage(addr2, "gno.land/r/bobby", "gno.land/r/bobby/v2", files)
+	err = env.vmk.UpgradePackage(ctx, upgrade)
+	assert.Error(t, err)
+	env.vmk.SetDeployWhitelist([]crypto.Address{addr, addr2})
+	err = env.vmk.UpgradePackage(ctx, upgrade)
+	assert.NoError(t, err)
+
+	// Deployment fees, none by default.
+	fee, err := env.vmk.DeployFee(&std.MemPackage{Files: files})
+	assert.NoError(t, err)
+	assert.True(t, fee.IsZero())
+	env.vmk.SetDeployFee(std.NewCoin("ugnot", 1000), std.NewCoin("ugnot", 10))
+	fee, err = env.vmk.DeployFee(&std.MemPackage{Files: files})
+	assert.NoError(t, err)
+	size := int64(len(files[0].Body))
+	assert.Equal(t, fee, std.Coins{std.NewCoin("ugnot", 1000+10*size)})
+}
+
+func TestVMKeeperCallEvents(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test packages.
+	files1 := []*std.MemFile{
+		{"bar.gno", `
+package bar
+
+func helper() string {
+	return "bar"
+}
+
+func Bar() string {
+	return helper()
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/bar", files1))
+	assert.NoError(t, err)
+	files2 := []*std.MemFile{
+		{"foo.gno", `
+package foo
+
+import "gno.land/r/bar"
+
+func Foo() string {
+	return "foo" + bar.Bar()
+}`},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/foo", files2))
+	assert.NoError(t, err)
+
+	// Call Foo, which calls Bar.
+	ctx = ctx.WithEventLogger(sdk.NewEventLogger())
+	msg := NewMsgCall(addr, nil, "gno.land/r/foo", "Foo", nil)
+	_, err = env.vmk.Call(ctx, msg)
+	assert.NoError(t, err)
+	events := ctx.EventLogger().Events()
+	if assert.Equal(t, 2, len(events)) {
+		ev1 := events[0].(RealmCallEvent)
+		assert.Equal(t, "gno.land/r/foo", ev1.Caller)
+		assert.Equal(t, "gno.land/r/bar", ev1.PkgPath)
+		assert.Equal(t, "Bar", ev1.Func)
+		assert.Equal(t, 1, ev1.Depth)
+		assert.True(t, ev1.IsCrossRealm())
+		ev2 := events[1].(RealmCallEvent)
+		assert.Equal(t, "", ev2.Caller)
+		assert.Equal(t, "gno.land/r/foo", ev2.PkgPath)
+		assert.Equal(t, "Foo", ev2.Func)
+		assert.Equal(t, 0, ev2.Depth)
+		assert.False(t, ev2.IsCrossRealm())
+		assert.True(t, ev2.Cycles > ev1.Cycles)
+	}
+}
+
+// Calls which change the objects of a realm emit a storage event.
+func TestVMKeeperStorageEvents(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+type Item struct {
+	Name string
+}
+
+var items []*Item
+
+func Add(name string) {
+	items = append(items, &Item{Name: name})
+}
+
+func Clear() {
+	items = nil
+}
+
+func Count() int {
+	return len(items)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	assert.NoError(t, err)
+	call := func(fn string, args ...string) []RealmStorageEvent {
+		ctx := ctx.WithEventLogger(sdk.NewEventLogger())
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, fn, args))
+		assert.NoError(t, err)
+		events := []RealmStorageEvent{}
+		for _, event := range ctx.EventLogger().Events() {
+			if event, ok := event.(RealmStorageEvent); ok {
+				events = append(events, event)
+			}
+		}
+		return events
+	}
+
+	// Adding creates objects, and updates the package block.
+	events := call("Add", "a")
+	if assert.Equal(t, 1, len(events)) {
+		assert.Equal(t, pkgPath, events[0].PkgPath)
+		assert.True(t, len(events[0].Created) > 0)
+		assert.True(t, len(events[0].Updated) > 0)
+		assert.Equal(t, 0, len(events[0].Deleted))
+	}
+	created := events[0].Created
+
+	// Reading changes nothing.
+	events = call("Count")
+	assert.Equal(t, 0, len(events))
+
+	// Clearing deletes the objects created.
+	events = call("Clear")
+	if assert.Equal(t, 1, len(events)) {
+		assert.Equal(t, 0, len(events[0].Created))
+		assert.Equal(t, created, events[0].Deleted)
+	}
+}
+
+// Realms emit events with std.Emit.
+func TestVMKeeperEmit(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"emit.gno", `
+package emit
+
+import "std"
+
+func Transfer(to string) string {
+	std.Emit("Transfer", "to", to, "amount", "100")
+	return to
+}
+
+func Invalid(typ string) {
+	std.Emit("Invalid")
+	std.Emit(typ, "key")
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/emit", files))
+	assert.NoError(t, err)
+
+	// Call Transfer, which emits an event before its call event.
+	ctx = ctx.WithEventLogger(sdk.NewEventLogger())
+	msg := NewMsgCall(addr, nil, "gno.land/r/emit", "Transfer", []string{"bob"})
+	_, err = env.vmk.Call(ctx, msg)
+	assert.NoError(t, err)
+	events := ctx.EventLogger().Events()
+	if assert.Equal(t, 2, len(events)) {
+		ev := events[0].(RealmEvent)
+		assert.Equal(t, "gno.land/r/emit", ev.PkgPath)
+		assert.Equal(t, "Transfer", ev.Type)
+		assert.Equal(t, []RealmEventAttr{{"to", "bob"}, {"amount", "100"}}, ev.Attrs)
+		to, ok := ev.GetAttr("to")
+		assert.True(t, ok)
+		assert.Equal(t, "bob", to)
+		_, ok = ev.GetAttr("from")
+		assert.False(t, ok)
+		assert.IsType(t, RealmCallEvent{}, events[1])
+	}
+
+	// Invalid events panic.
+	msg = NewMsgCall(addr, nil, "gno.land/r/emit", "Invalid", []string{"Transfer"})
+	_, err = env.vmk.Call(ctx, msg)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "odd number of attributes")
+	}
+}
+
+// Calls consume gas, and abort when out of gas.
+func TestVMKeeperGasMetering(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+func Loop(n int) int {
+	sum := 0
+	for i := 0; i < n; i++ {
+		sum += i
+	}
+	return sum
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	// Gas grows with the number of iterations.
+	gasUsed := func(n int) int64 {
+		gctx := ctx.WithGasMeter(store.NewInfiniteGasMeter())
+		msg := NewMsgCall(addr, nil, pkgPath, "Loop", []string{fmt.Sprintf("%d", n)})
+		_, err := env.vmk.Call(gctx, msg)
+		assert.NoError(t, err)
+		return gctx.GasMeter().GasConsumed()
+	}
+	gas10, gas100 := gasUsed(10), gasUsed(100)
+	assert.True(t, gas10 > 0)
+	assert.True(t, gas100 > gas10)
+
+	// Running out of gas aborts with an out of gas panic.
+	func() {
+		defer func() {
+			r := recover()
+			assert.True(t, isOutOfGas(r))
+		}()
+		gctx := ctx.WithGasMeter(store.NewGasMeter(gas100))
+		msg := NewMsgCall(addr, nil, pkgPath, "Loop", []string{"1000"})
+		env.vmk.Call(gctx, msg)
+	}()
+}
+
+// Panics return the gno call stack, innermost call first.
+func TestVMKeeperPanicStacktrace(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test packages.
+	files1 := []*std.MemFile{
+		{"bar.gno", `package bar
+
+type Counter struct{ n int }
+
+func (c *Counter) Incr() {
+	c.n++
+	if c.n > 1 {
+		panic("too many")
+	}
+}
+
+func Bar() {
+	c := &Counter{}
+	c.Incr()
+	c.Incr()
+}
+
+func Spin() {
+	for {
+	}
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/bar", files1))
+	assert.NoError(t, err)
+	files2 := []*std.MemFile{
+		{"foo.gno", `package foo
+
+import "gno.land/r/bar"
+
+func Foo() {
+	bar.Bar()
+}
+
+func Spin() {
+	bar.Spin()
+}`},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/foo", files2))
+	assert.NoError(t, err)
+
+	msg := NewMsgCall(addr, nil, "gno.land/r/foo", "Foo", nil)
+	_, err = env.vmk.Call(ctx, msg)
+	require.Error(t, err)
+	perr, ok := errors.Cause(err).(VMPanicError)
+	require.True(t, ok)
+	assert.Equal(t, "too many", perr.Exception)
+	assert.Equal(t, []StackFrame{
+		{"gno.land/r/bar", "Counter.Incr", "bar.gno", 8},
+		{"gno.land/r/bar", "Bar", "bar.gno", 15},
+		{"gno.land/r/foo", "Foo", "foo.gno", 6},
+	}, perr.Stacktrace)
+	assert.Contains(t, err.Error(), "gno.land/r/bar.Counter.Incr()\n\tgno.land/r/bar/bar.gno:8")
+
+	// Out of gas panics show where the gas ran out.
+	func() {
+		defer func() {
+			r := recover()
+			require.True(t, isOutOfGas(r))
+			desc := r.(store.OutOfGasException).Descriptor
+			assert.Contains(t, desc, "gno.land/r/bar.Spin()\n\tgno.land/r/bar/bar.gno\n")
+			assert.Contains(t, desc, "gno.land/r/foo.Spin()\n\tgno.land/r/foo/foo.gno:10")
+		}()
+		gctx := ctx.WithGasMeter(store.NewGasMeter(100000))
+		msg := NewMsgCall(addr, nil, "gno.land/r/foo", "Spin", nil)
+		env.vmk.Call(gctx, msg)
+	}()
+}
+
+// A realm can spend coins another realm approved for it, up to the
+// allowance.
+func TestVMKeeperAllowance(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create owner and spender packages.
+	ownerFiles := []*std.MemFile{
+		{"owner.gno", `
+package owner
+
+import "std"
+
+func Approve(spender std.Address) {
+	pkgAddr := std.GetOrigPkgAddr()
+	banker := std.GetBanker(std.BankerTypeRealmSend)
+	banker.Approve(pkgAddr, spender, std.Coins{{"ugnot", 3000000}})
+}`},
+	}
+	spenderFiles := []*std.MemFile{
+		{"spender.gno", `
+package spender
+
+import "std"
+
+func Pull(owner std.Address) string {
+	caller := std.GetOrigCaller()
+	pkgAddr := std.GetOrigPkgAddr()
+	banker := std.GetBanker(std.BankerTypeRealmSend)
+	banker.TransferFrom(pkgAddr, owner, caller, std.Coins{{"ugnot", 2000000}})
+	return banker.Allowance(owner, pkgAddr).String()
+}`},
+	}
+	ownerPath, spenderPath := "gno.land/r/owner", "gno.land/r/spender"
+	ownerAddr, spenderAddr := gno.DerivePkgAddr(ownerPath), gno.DerivePkgAddr(spenderPath)
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, ownerPath, ownerFiles))
+	assert.NoError(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, spenderPath, spenderFiles))
+	assert.NoError(t, err)
+
+	// Fund the owner realm and approve the spender realm.
+	coins := std.MustParseCoins("5000000ugnot")
+	msg1 := NewMsgCall(addr, coins, ownerPath, "Approve", []string{spenderAddr.String()})
+	_, err = env.vmk.Call(ctx, msg1)
+	assert.NoError(t, err)
+	assert.True(t, env.bank.GetCoins(ctx, ownerAddr).IsEqual(coins))
+
+	// Spend from the allowance.
+	msg2 := NewMsgCall(addr, nil, spenderPath, "Pull", []string{ownerAddr.String()})
+	res, err := env.vmk.Call(ctx, msg2)
+	assert.NoError(t, err)
+	assert.Equal(t, res, `("1000000ugnot" string)`)
+	assert.True(t, env.bank.GetCoins(ctx, ownerAddr).IsEqual(std.MustParseCoins("3000000ugnot")))
+
+	// Exceeding the remaining allowance fails.
+	_, err = env.vmk.Call(ctx, msg2)
+	assert.Error(t, err)
+	assert.True(t, env.bank.GetCoins(ctx, ownerAddr).IsEqual(std.MustParseCoins("3000000ugnot")))
+
+	// Users approve realms with MsgApprove.
+	msg3 := NewMsgApprove(addr, spenderAddr, std.MustParseCoins("2500000ugnot"))
+	err = env.vmk.Approve(ctx, msg3)
+	assert.NoError(t, err)
+	msg4 := NewMsgCall(addr, nil, spenderPath, "Pull", []string{addr.String()})
+	res, err = env.vmk.Call(ctx, msg4)
+	assert.NoError(t, err)
+	assert.Equal(t, res, `("500000ugnot" string)`)
+}
+
+// Only the realm that first issued a denom manages it.
+func TestVMKeeperDenomIssuer(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create two issuing packages.
+	files := []*std.MemFile{
+		{"issuer.gno", `
+package issuer
+
+import "std"
+
+func Issue(denom string) {
+	pkgAddr := std.GetOrigPkgAddr()
+	banker := std.GetBanker(std.BankerTypeRealmIssue)
+	banker.IssueCoin(pkgAddr, denom, 1000)
+}
+
+func SetName(denom string, name string) string {
+	banker := std.GetBanker(std.BankerTypeRealmIssue)
+	banker.SetDenomMetadata(std.DenomMetadata{denom, name, "SYM", 6})
+	return banker.GetDenomMetadata(denom).Name
+}`},
+	}
+	path1, path2 := "gno.land/r/issuer1", "gno.land/r/issuer2"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, path1, files))
+	assert.NoError(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, path2, files))
+	assert.NoError(t, err)
+
+	// The first realm issuing a denom manages its metadata.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, path1, "Issue", []string{"ufoo"}))
+	assert.NoError(t, err)
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, path1, "SetName", []string{"ufoo", "Foo"}))
+	assert.NoError(t, err)
+	assert.Equal(t, res, `("Foo" string)`)
+
+	// Other realms can neither issue it nor set its metadata.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, path2, "Issue", []string{"ufoo"}))
+	assert.Error(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, path2, "SetName", []string{"ufoo", "Fake"}))
+	assert.Error(t, err)
+
+	// Native denoms cannot be claimed.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, path2, "Issue", []string{"ugnot"}))
+	assert.Error(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, path2, "SetName", []string{"ugnot", "Fake"}))
+	assert.Error(t, err)
+}
+
+// Native bindings are charged their declared gas cost.
+func TestVMKeeperNativeBindingGas(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+import "crypto/sha256"
+
+func Hash(msg string) byte {
+	sum := sha256.Sum256([]byte(msg))
+	return sum[0]
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	// Sum256 costs 1000 gas plus 10 per byte hashed.
+	gasMeter := store.NewAuditGasMeter(store.NewInfiniteGasMeter())
+	gctx := ctx.WithGasMeter(gasMeter)
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Hash", []string{"hello"})
+	res, err := env.vmk.Call(gctx, msg2)
+	assert.NoError(t, err)
+	assert.Equal(t, res, `(44 uint8)`)
+	found := false
+	for _, entry := range gasMeter.Entries() {
+		if entry.Descriptor == "NativeCall" {
+			found = true
+			assert.Equal(t, entry.Count, int64(1))
+			assert.Equal(t, entry.Gas, store.Gas(1050))
+		}
+	}
+	assert.True(t, found)
+}
+
+// The stack limits of machines are set by the vm params.
+func TestVMKeeperStackLimits(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+func Depth(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return Depth(n-1) + 1
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Depth", []string{"100"})
+	res, err := env.vmk.Call(ctx, msg2)
+	assert.NoError(t, err)
+	assert.Equal(t, res, `(100 int)`)
+	params := DefaultParams()
+	params.MaxFrames = 50
+	_, err = env.vmk.Call(ctx.WithValue(VMParamsContextKey{}, params), msg2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "frame limit exceeded")
+}
+
+// Realms get a deterministic seed from the last block hash, the height,
+// the realm path and a salt.
+func TestVMKeeperRandSeed(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+import "std"
+
+func Seed(salt string) int64 {
+	return std.RandSeed([]byte(salt))
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	seed := func(lastBlockHash []byte, salt string) string {
+		header := &bft.Header{ChainID: "test-chain-id", Height: 5, LastBlockID: bft.BlockID{Hash: lastBlockHash}}
+		msg2 := NewMsgCall(addr, nil, pkgPath, "Seed", []string{salt})
+		res, err := env.vmk.Call(ctx.WithBlockHeader(header), msg2)
+		assert.NoError(t, err)
+		return res
+	}
+	execCtx := stdlibs.ExecContext{Height: 5, BlockEntropy: []byte("hash1")}
+	assert.Equal(t, fmt.Sprintf("(%d int64)", execCtx.RandSeed(pkgPath, []byte("a"))), seed([]byte("hash1"), "a"))
+	assert.Equal(t, seed([]byte("hash1"), "a"), seed([]byte("hash1"), "a"))
+	assert.NotEqual(t, seed([]byte("hash1"), "a"), seed([]byte("hash1"), "b"))
+	assert.NotEqual(t, seed([]byte("hash1"), "a"), seed([]byte("hash2"), "a"))
+}
+
+// Realms schedule calls to their functions at the end of future blocks.
+func TestVMKeeperScheduleCall(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 1})
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"init.gno", `
+package test
+
+import "std"
+
+var expired string
+
+func Expire(id string) {
+	if std.GetOrigCaller() != std.GetOrigPkgAddr() {
+		panic("not scheduled")
+	}
+	expired += id
+}
+
+func Loop(id string) {
+	for {
+	}
+}
+
+func Schedule(height int64, fn string, id string, gas int64) {
+	std.ScheduleCall(height, fn, id, gas)
+}
+
+func Expired() string {
+	return expired
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	err := env.vmk.AddPackage(ctx, msg1)
+	assert.NoError(t, err)
+
+	call := func(ctx sdk.Context, fn string, args ...string) (string, error) {
+		return env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, fn, args))
+	}
+	atHeight := func(height int64) sdk.Context {
+		return ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: height})
+	}
+
+	// the gas of scheduled calls is prepaid.
+	gctx := ctx.WithGasMeter(store.NewInfiniteGasMeter())
+	_, err = call(gctx, "Schedule", "3", "Expire", "b", "1000000")
+	assert.NoError(t, err)
+	assert.True(t, gctx.GasMeter().GasConsumed() > 1000000)
+	_, err = call(ctx, "Schedule", "2", "Expire", "a", "1000000")
+	assert.NoError(t, err)
+	_, err = call(ctx, "Schedule", "2", "Loop", "c", "100000")
+	assert.NoError(t, err)
+
+	// invalid calls are not scheduled.
+	_, err = call(ctx, "Schedule", "1", "Expire", "x", "1000000")
+	assert.Error(t, err)
+	_, err = call(ctx, "Schedule", "2", "expire", "x", "1000000")
+	assert.Error(t, err)
+	_, err = call(ctx, "Schedule", "2", "Expire", "x", fmt.Sprint(DefaultScheduledGasPerBlock+1))
+	assert.Error(t, err)
+
+	// scheduled calls are only made by the scheduler.
+	_, err = call(ctx, "Expire", "x")
+	assert.Error(t, err)
+
+	// the calls are run in order at their heights, and failed calls are
+	// discarded.
+	events := env.vmk.RunScheduledCalls(atHeight(1))
+	assert.Empty(t, events)
+	events = env.vmk.RunScheduledCalls(atHeight(2))
+	assert.Equal(t, 2, len(events))
+	event := events[0].(ScheduledCallEvent)
+	assert.Equal(t, "Expire", event.Func)
+	assert.Equal(t, "", event.Error)
+	assert.True(t, event.GasUsed > 0)
+	event = events[1].(ScheduledCallEvent)
+	assert.Equal(t, "Loop", event.Func)
+	assert.Equal(t, "out of gas", event.Error)
+	assert.Equal(t, int64(100000), event.GasUsed)
+	res, err := call(ctx, "Expired")
+	assert.NoError(t, err)
+	assert.Equal(t, `("a" string)`, res)
+
+	// calls over the budget of a block are left over to the next blocks.
+	for i := 0; i < 10; i++ {
+		_, err = call(atHeight(2), "Schedule", "3", "Expire", fmt.Sprint(i), fmt.Sprint(DefaultScheduledGasPerBlock/10))
+		assert.NoError(t, err)
+	}
+	events = env.vmk.RunScheduledCalls(atHeight(3))
+	assert.Equal(t, 10, len(events))
+	events = env.vmk.RunScheduledCalls(atHeight(4))
+	assert.Equal(t, 1, len(events))
+	events = env.vmk.RunScheduledCalls(atHeight(5))
+	assert.Empty(t, events)
+	res, err = call(ctx, "Expired")
+	assert.NoError(t, err)
+	assert.Equal(t, `("ab0123456789" string)`, res)
+
+	// calls left over hold back the calls after them.
+	for _, c := range []struct{ id, gas string }{{"A", "6000000"}, {"B", "5000000"}, {"C", "4000000"}} {
+		_, err = call(atHeight(5), "Schedule", "6", "Expire", c.id, c.gas)
+		assert.NoError(t, err)
+	}
+	events = env.vmk.RunScheduledCalls(atHeight(6))
+	assert.Equal(t, 1, len(events))
+	events = env.vmk.RunScheduledCalls(atHeight(7))
+	assert.Equal(t, 2, len(events))
+	res, err = call(ctx, "Expired")
+	assert.NoError(t, err)
+	assert.Equal(t, `("ab0123456789ABC" string)`, res)
+
+	// calls over the gas of a block, e.g. after the params were lowered,
+	// expire.
+	_, err = call(atHeight(7), "Schedule", "8", "Expire", "D", "2000000")
+	assert.NoError(t, err)
+	params := DefaultParams()
+	params.ScheduledGasPerBlock = 1000000
+	events = env.vmk.RunScheduledCalls(atHeight(8).WithValue(VMParamsContextKey{}, params))
+	assert.Equal(t, 1, len(events))
+	event = events[0].(ScheduledCallEvent)
+	assert.Contains(t, event.Error, "expired")
+	assert.Equal(t, int64(0), event.GasUsed)
+	events = env.vmk.RunScheduledCalls(atHeight(9))
+	assert.Empty(t, events)
+	res, err = call(ctx, "Expired")
+	assert.NoError(t, err)
+	assert.Equal(t, `("ab0123456789ABC" string)`, res)
+}
+
+// Realms send messages to other realms, delivered in order at the end of
+// blocks.
+func TestVMKeeperSendMessage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 1})
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test packages.
+	recvFiles := []*std.MemFile{
+		{"recv.gno", `
+package recv
+
+import "std"
+
+var received string
+var sender std.Address
+
+func Receive(arg string) {
+	received += arg
+	sender = std.GetOrigCaller()
+}
+
+func Forward(arg string) {
+	received += arg
+	std.SendMessage("gno.land/r/recv", "Receive", arg+arg, 1000000)
+}
+
+func Sender() string {
+	return sender.String()
+}
+
+func Loop(arg string) {
+	for {
+	}
+}
+
+func Received() string {
+	return received
+}`},
+	}
+	sendFiles := []*std.MemFile{
+		{"send.gno", `
+package send
+
+import "std"
+
+func Send(pkgPath string, fn string, arg string, gas int64) {
+	std.SendMessage(pkgPath, fn, arg, gas)
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/recv", recvFiles))
+	assert.NoError(t, err)
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/send", sendFiles))
+	assert.NoError(t, err)
+
+	send := func(ctx sdk.Context, fn, arg string, gas int64) error {
+		msg := NewMsgCall(addr, nil, "gno.land/r/send", "Send",
+			[]string{"gno.land/r/recv", fn, arg, fmt.Sprint(gas)})
+		_, err := env.vmk.Call(ctx, msg)
+		return err
+	}
+	received := func() string {
+		res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, "gno.land/r/recv", "Received", nil))
+		assert.NoError(t, err)
+		return res
+	}
+
+	// the gas of messages is prepaid.
+	gctx := ctx.WithGasMeter(store.NewInfiniteGasMeter())
+	err = send(gctx, "Receive", "a", 1000000)
+	assert.NoError(t, err)
+	assert.True(t, gctx.GasMeter().GasConsumed() > 1000000)
+	err = send(ctx, "Loop", "x", 100000)
+	assert.NoError(t, err)
+	err = send(ctx, "Forward", "b", 3000000)
+	assert.NoError(t, err)
+	err = send(ctx, "Receive", "c", 1000000)
+	assert.NoError(t, err)
+
+	// invalid messages are not sent.
+	err = send(ctx, "receive", "x", 1000000)
+	assert.Error(t, err)
+	err = send(ctx, "Receive", "x", DefaultMessageGasPerBlock+1)
+	assert.Error(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, "gno.land/r/send", "Send",
+		[]string{"gno.land/p/demo/avl", "NewTree", "x", "1000000"}))
+	assert.Error(t, err)
+
+	// the messages are delivered in order, including those sent by the
+	// messages delivered, and failed messages are discarded.
+	events := env.vmk.DeliverMessages(ctx)
+	assert.Equal(t, 5, len(events))
+	event := events[0].(RealmMessageEvent)
+	assert.Equal(t, "gno.land/r/send", event.From)
+	assert.Equal(t, "gno.land/r/recv", event.PkgPath)
+	assert.Equal(t, "Receive", event.Func)
+	assert.Equal(t, "", event.Error)
+	assert.True(t, event.GasUsed > 0)
+	event = events[1].(RealmMessageEvent)
+	assert.Equal(t, "Loop", event.Func)
+	assert.Equal(t, "out of gas", event.Error)
+	assert.Equal(t, int64(100000), event.GasUsed)
+	event = events[4].(RealmMessageEvent)
+	assert.Equal(t, "gno.land/r/recv", event.From)
+	assert.Equal(t, "", event.Error)
+	assert.True(t, event.Seq > events[3].(RealmMessageEvent).Seq)
+	assert.Equal(t, `("abcbb" string)`, received())
+
+	// messages are delivered from their sending realm.
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, "gno.land/r/recv", "Sender", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("(%q string)", gno.DerivePkgAddr("gno.land/r/recv")), res)
+
+	// messages over the budget of a block are left queued for the next
+	// blocks.
+	for i := 0; i < 11; i++ {
+		err = send(ctx, "Receive", fmt.Sprint(i), DefaultMessageGasPerBlock/10)
+		assert.NoError(t, err)
+	}
+	events = env.vmk.DeliverMessages(ctx)
+	assert.Equal(t, 10, len(events))
+	events = env.vmk.DeliverMessages(ctx)
+	assert.Equal(t, 1, len(events))
+	events = env.vmk.DeliverMessages(ctx)
+	assert.Empty(t, events)
+	assert.Equal(t, `("abcbb012345678910" string)`, received())
+}
+
+// Realm objects cached across transactions are not affected by
+// transactions that are not committed.
+func TestVMKeeperObjectCache(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	files := []*std.MemFile{
+		{"counter.gno", `
+package counter
+
+type Entry struct {
+	N    int
+	Keys []string
+}
+
+var entries = map[string]*Entry{}
+var counts = map[string]int{}
+
+func Incr(key string) int {
+	e, ok := entries[key]
+	if !ok {
+		e = &Entry{}
+		entries[key] = e
+	}
+	e.N++
+	e.Keys = append(e.Keys, key)
+	counts[key]++
+	return e.N * len(e.Keys) * counts[key]
+}
+
+func Get(key string) int {
+	e := entries[key]
+	return e.N * len(e.Keys) * counts[key]
+}`},
+	}
+	pkgPath := "gno.land/r/counter"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	assert.NoError(t, err)
+
+	msg := NewMsgCall(addr, nil, pkgPath, "Incr", []string{"a"})
+	for i, expected := range []string{"(1 int)", "(8 int)", "(27 int)"} {
+		res, err := env.vmk.Call(ctx, msg)
+		assert.NoError(t, err, i)
+		assert.Equal(t, res, expected)
+	}
+
+	// Writes to a discarded cache-wrapped store are not seen later,
+	// even after being loaded again from that store.
+	msCache := ctx.MultiStore().MultiCacheWrap()
+	res, err := env.vmk.Call(ctx.WithMultiStore(msCache), msg)
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(64 int)")
+	msgGet := NewMsgCall(addr, nil, pkgPath, "Get", []string{"a"})
+	res, err = env.vmk.Call(ctx.WithMultiStore(msCache), msgGet)
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(64 int)")
+	res, err = env.vmk.Call(ctx, msgGet)
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(27 int)")
+	res, err = env.vmk.Call(ctx, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(64 int)")
+	res, err = env.vmk.Call(ctx, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, res, "(125 int)")
+}
+
+// Unformatted packages are rejected when formatting is required.
+func TestVMKeeperRequireFormatted(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+	env.vmk.SetRequireFormatted(true)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	files := []*std.MemFile{
+		{"hello.gno", "package hello\n\nfunc Hello() string {\n\treturn \"hello\"\n}\n"},
+		{"ugly.gno", "package hello\nfunc  Ugly( ) string { return \"ugly\" }\n"},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/hello", files))
+	assert.Error(t, err)
+	assert.Equal(t, errors.Cause(err), UnformattedPackageError{})
+	assert.True(t, strings.Contains(fmt.Sprintf("%#v", err), "unformatted files in package gno.land/r/hello: ugly.gno"))
+
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/hello", files[:1]))
+	assert.NoError(t, err)
+}
+
+func TestVMKeeperRejectFloats(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+	env.vmk.SetRejectFloats(true)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	files := []*std.MemFile{
+		{"hello.gno", "package hello\n\nfunc Hello() string {\n\treturn \"hello\"\n}\n"},
+		{"half.gno", "package hello\n\nfunc Half(x int) float64 {\n\treturn float64(x) / 2\n}\n"},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/hello", files))
+	assert.Error(t, err)
+	assert.Equal(t, errors.Cause(err), TypeCheckError{})
+	assert.True(t, strings.Contains(fmt.Sprintf("%#v", err), "half.gno:3: floating-point type float64 not allowed"))
+
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/hello", files[:1]))
+	assert.NoError(t, err)
+}
+
+func TestVMKeeperPackageLimits(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+	env.vmk.SetPackageLimits(PackageLimits{
+		MaxSize:  1000,
+		MaxFiles: 2,
+		MaxDecls: 3,
+		MaxDepth: 20,
+	})
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	deep := "package hello\n\nvar X = " + strings.Repeat("(", 30) + "1" + strings.Repeat(")", 30) + "\n"
+	cases := []struct {
+		files []*std.MemFile
+		err   error
+	}{
+		{[]*std.MemFile{
+			{"a.gno", "package hello\n"},
+			{"b.gno", "package hello\n"},
+			{"c.gno", "package hello\n"},
+		}, TooManyFilesError{}},
+		{[]*std.MemFile{
+			{"a.gno", "package hello\n\n// " + strings.Repeat("x", 1000) + "\n"},
+		}, PackageTooLargeError{}},
+		{[]*std.MemFile{
+			{"a.gno", "package hello\n\nvar A, B int\n\nfunc C() {}\n"},
+			{"b.gno", "package hello\n\ntype D int\n"},
+		}, TooManyDeclsError{}},
+		{[]*std.MemFile{
+			{"a.gno", deep},
+		}, ASTTooDeepError{}},
+	}
+	for _, c := range cases {
+		err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/hello", c.files))
+		assert.Equal(t, errors.Cause(err), c.err)
+	}
+
+	files := []*std.MemFile{
+		{"a.gno", "package hello\n\nvar A, B int\n\nfunc C() {}\n"},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/hello", files))
+	assert.NoError(t, err)
+}
+
+func TestVMKeeperTypeCheck(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+	checkCtx := ctx.WithMode(sdk.RunTxModeCheck)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Ill-typed packages are rejected on CheckTx, with the error.
+	cases := []struct {
+		body string
+		msg  string
+	}{
+		{"package hello\n\nfunc F() int { return \"x\" }\n", "cannot convert StringKind to IntKind"},
+		{"package hello\n\nfunc F() { undefinedFn() }\n", "name undefinedFn not declared"},
+		{"package hello\n\nfunc F() {}\n\nfunc G() { F(1) }\n", "wrong argument count"},
+		{"package hello\n\nfunc F( {\n", "ugly.gno:3:9: expected ')'"},
+	}
+	for _, c := range cases {
+		files := []*std.MemFile{
+			{"hello.gno", "package hello\n"},
+			{"ugly.gno", c.body},
+		}
+		msg := NewMsgAddPackage(addr, "gno.land/r/hello", files)
+		tx := std.NewTx([]std.Msg{msg}, std.Fee{}, nil, "")
+		err := env.vmk.CheckTx(checkCtx, tx)
+		assert.Equal(t, errors.Cause(err), TypeCheckError{})
+		assert.True(t, strings.Contains(fmt.Sprintf("%#v", err), c.msg))
+		// on DeliverTx, they fail to preprocess.
+		assert.Panics(t, func() {
+			env.vmk.AddPackage(ctx, msg)
+		})
+	}
+	assert.Nil(t, env.vmk.getGnoStore(ctx).GetPackage("gno.land/r/hello", false))
+
+	// The type check is charged per byte.
+	files := []*std.MemFile{
+		{"hello.gno", "package hello\n\nfunc F() int { return G() }\n"},
+		{"world.gno", "package hello\n\nfunc G() int { return 1 }\n"},
+	}
+	msg := NewMsgAddPackage(addr, "gno.land/r/hello", files)
+	gasMeter := store.NewGasMeter(1000000)
+	tx := std.NewTx([]std.Msg{msg}, std.Fee{}, nil, "")
+	err := env.vmk.CheckTx(checkCtx.WithGasMeter(gasMeter), tx)
+	assert.NoError(t, err)
+	size := len(files[0].Body) + len(files[1].Body)
+	assert.True(t, gasMeter.GasConsumed() >= DefaultTypeCheckGasPerByte*int64(size))
+	assert.Panics(t, func() {
+		env.vmk.CheckTx(checkCtx.WithGasMeter(store.NewGasMeter(100)), tx)
+	})
+
+	err = env.vmk.AddPackage(ctx, msg)
+	assert.NoError(t, err)
+}
+
+// Imports resolve to the versions pinned by gno.mod.
+func TestVMKeeperPinnedImports(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 10})
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Add a dependency at height 10, and upgrade it at height 20.
+	depPath := "gno.land/r/dep"
+	files := []*std.MemFile{
+		{"dep.gno", "package dep\n\nfunc Version() int { return 1 }\n"},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, depPath, files))
+	assert.NoError(t, err)
+	ctx = ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 20})
+	files = []*std.MemFile{
+		{"dep.gno", "package dep\n\nfunc Version() int { return 2 }\n"},
+	}
+	err = env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, depPath, depPath+"/v2", files))
+	assert.NoError(t, err)
+
+	// Import by version, by height, or unpinned.
+	for _, tc := range []struct {
+		pkgPath  string
+		modFile  string
+		expected string
+	}{
+		{"gno.land/r/user1", "", "gno.land/r/dep"},
+		{"gno.land/r/user2", "module gno.land/r/user2\n\nrequire gno.land/r/dep v2\n", "gno.land/r/dep/v2"},
+		{"gno.land/r/user3", "require (\n\tgno.land/r/dep @15 // before v2\n)\n", "gno.land/r/dep"},
+		{"gno.land/r/user4", "require gno.land/r/dep @20\n", "gno.land/r/dep/v2"},
+	} {
+		files := []*std.MemFile{
+			{"user.gno", "package user\n\nimport \"gno.land/r/dep\"\n\nfunc Version() int { return dep.Version() }\n"},
+		}
+		if tc.modFile != "" {
+			files = append(files, &std.MemFile{"gno.mod", tc.modFile})
+		}
+		err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, tc.pkgPath, files))
+		assert.NoError(t, err, tc.pkgPath)
+		deps, err := env.vmk.QueryDeps(ctx, tc.pkgPath)
+		assert.NoError(t, err)
+		assert.Equal(t, deps, []string{tc.expected})
+		res, err := env.vmk.QueryEval(ctx, tc.pkgPath, "Version()")
+		assert.NoError(t, err)
+		if tc.expected == depPath {
+			assert.Equal(t, res, "(1 int)")
+		} else {
+			assert.Equal(t, res, "(2 int)")
+		}
+	}
+
+	// Pins must resolve.
+	files = []*std.MemFile{
+		{"user.gno", "package user\n\nimport \"gno.land/r/dep\"\n\nfunc Version() int { return dep.Version() }\n"},
+		{"gno.mod", "require gno.land/r/dep v3\n"},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/user5", files))
+	assert.Error(t, err)
+	files[1] = &std.MemFile{"gno.mod", "require gno.land/r/dep @5\n"}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/user5", files))
+	assert.Error(t, err)
+	files[1] = &std.MemFile{"gno.mod", "module gno.land/r/other\n"}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/r/user5", files))
+	assert.Error(t, err)
+}
+
+// Realm state imported in the stores of a new chain can be used after the
+// packages are loaded, as at genesis.
+func TestVMKeeperLoadPackages(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+
+	files := []*std.MemFile{
+		{"items.gno", `
+package test
+
+type Item struct {
+	Name  string
+	Count int
+}
+
+var items []*Item
+var byName = map[string]*Item{}
+
+func Add(name string) int {
+	item := byName[name]
+	if item == nil {
+		item = &Item{Name: name}
+		items = append(items, item)
+		byName[name] = item
+	}
+	item.Count++
+	return item.Count
+}
+
+func Len() int {
+	return len(items)
+}`},
+	}
+	pkgPath := "gno.land/r/test"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	assert.NoError(t, err)
+	for _, name := range []string{"foo", "bar", "foo"} {
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{name}))
+		assert.NoError(t, err)
+	}
+
+	// import the state in a new chain.
+	env2 := setupTestEnv()
+	ctx2 := env2.ctx
+	keys := [][2]store.StoreKey{
+		{env.vmk.baseKey, env2.vmk.baseKey},
+		{env.vmk.iavlKey, env2.vmk.iavlKey},
+	}
+	for _, key := range keys {
+		src, dst := ctx.Store(key[0]), ctx2.Store(key[1])
+		itr := src.Iterator(nil, nil)
+		for ; itr.Valid(); itr.Next() {
+			dst.Set(itr.Key(), itr.Value())
+		}
+		itr.Close()
+	}
+	env2.vmk.LoadPackages(ctx2)
+
+	// loading packages does not change the state.
+	for _, key := range keys {
+		diffs := store.DiffStoreKVs(ctx.Store(key[0]), ctx2.Store(key[1]), 1)
+		assert.Empty(t, diffs)
+	}
+
+	res, err := env2.vmk.Call(ctx2, NewMsgCall(addr, nil, pkgPath, "Add", []string{"foo"}))
+	assert.NoError(t, err)
+	assert.Equal(t, res, `(3 int)`)
+	res, err = env2.vmk.Call(ctx2, NewMsgCall(addr, nil, pkgPath, "Add", []string{"baz"}))
+	assert.NoError(t, err)
+	assert.Equal(t, res, `(1 int)`)
+	res, err = env2.vmk.Call(ctx2, NewMsgCall(addr, nil, pkgPath, "Len", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, res, `(3 int)`)
+}
+
+// An account's verification realm verifies the key signing its txs, and
+// the coins they spend.
+func TestVMKeeperVerifySigner(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	guardian := crypto.AddressFromPreimage([]byte("guardian"))
+	other := crypto.AddressFromPreimage([]byte("other"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	files := []*std.MemFile{
+		{"verifier.gno", fmt.Sprintf(`
+package verifier
+
+import "std"
+
+const guardian = std.Address(%q)
+
+var verified int
+
+func VerifyTx(key std.Address) bool {
+	if key != std.GetOrigCaller() && key != guardian {
+		return false
+	}
+	verified++
+	return std.GetOrigSend().AmountOf("ugnot") <= 100
+}
+
+func Verified() int {
+	return verified
+}`, guardian.String())},
+	}
+	pkgPath := "gno.land/r/verifier"
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	assert.NoError(t, err)
+	files = []*std.MemFile{
+		{"loop.gno", `
+package loop
+
+import "std"
+
+func VerifyTx(key std.Address) bool {
+	for {
+	}
+	return true
+}`},
+	}
+	loopPath := "gno.land/r/loop"
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, loopPath, files))
+	assert.NoError(t, err)
+	files = []*std.MemFile{
+		{"deny.gno", `
+package deny
+
+import "std"
+
+func VerifyTx(key std.Address) bool {
+	return false
+}`},
+	}
+	denyPath := "gno.land/r/deny"
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, denyPath, files))
+	assert.NoError(t, err)
+
+	newTx := func(send string) std.Tx {
+		msg := NewMsgCall(addr, std.MustParseCoins(send), pkgPath, "Verified", nil)
+		fee := std.NewFee(100000, std.MustParseCoin("10ugnot"))
+		return std.NewTx([]std.Msg{msg}, fee, nil, "")
+	}
+
+	// no verification realm.
+	verified, err := env.vmk.VerifySigner(ctx, newTx("50ugnot"), addr, other)
+	assert.False(t, verified)
+	assert.NoError(t, err)
+
+	// verification realms must declare VerifyTx.
+	err = env.vmk.SetVerifier(ctx, NewMsgSetVerifier(addr, "gno.land/r/none"))
+	assert.Error(t, err)
+	err = env.vmk.SetVerifier(ctx, NewMsgSetVerifier(addr, pkgPath))
+	assert.NoError(t, err)
+	assert.Equal(t, pkgPath, env.vmk.QueryVerifier(ctx, addr))
+
+	// keys and spending are verified.
+	verified, err = env.vmk.VerifySigner(ctx, newTx("50ugnot"), addr, addr)
+	assert.True(t, verified)
+	assert.NoError(t, err)
+	verified, err = env.vmk.VerifySigner(ctx, newTx("50ugnot"), addr, guardian)
+	assert.True(t, verified)
+	assert.NoError(t, err)
+	_, err = env.vmk.VerifySigner(ctx, newTx("50ugnot"), addr, other)
+	assert.Error(t, err)
+	_, err = env.vmk.VerifySigner(ctx, newTx("100ugnot"), addr, addr)
+	assert.Error(t, err)
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Verified", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, `(3 int)`, res)
+
+	// verification is limited by VerifyTxMaxGas.
+	err = env.vmk.SetVerifier(ctx, NewMsgSetVerifier(addr, loopPath))
+	assert.NoError(t, err)
+	gctx := ctx.WithGasMeter(store.NewInfiniteGasMeter())
+	_, err = env.vmk.VerifySigner(gctx, newTx("50ugnot"), addr, addr)
+	assert.Error(t, err)
+	assert.True(t, gctx.GasMeter().GasConsumed() > DefaultVerifyTxMaxGas)
+	assert.True(t, gctx.GasMeter().GasConsumed() < 2*DefaultVerifyTxMaxGas)
+
+	// the account may unset a faulty realm with a tx of its own key.
+	fee := std.NewFee(100000, std.MustParseCoin("10ugnot"))
+	unsetTx := std.NewTx([]std.Msg{NewMsgSetVerifier(addr, "")}, fee, nil, "")
+	verified, err = env.vmk.VerifySigner(ctx, unsetTx, addr, addr)
+	assert.True(t, verified)
+	assert.NoError(t, err)
+	_, err = env.vmk.VerifySigner(ctx, unsetTx, addr, guardian)
+	assert.Error(t, err)
+	unsetTx2 := unsetTx
+	unsetTx2.Msgs = append([]std.Msg{}, unsetTx.Msgs...)
+	unsetTx2.Msgs = append(unsetTx2.Msgs, newTx("50ugnot").Msgs...)
+	_, err = env.vmk.VerifySigner(ctx, unsetTx2, addr, addr)
+	assert.Error(t, err)
+
+	// but not a realm rejecting it.
+	err = env.vmk.SetVerifier(ctx, NewMsgSetVerifier(addr, denyPath))
+	assert.NoError(t, err)
+	_, err = env.vmk.VerifySigner(ctx, unsetTx, addr, addr)
+	assert.Error(t, err)
+
+	// unset.
+	err = env.vmk.SetVerifier(ctx, NewMsgSetVerifier(addr, ""))
+	assert.NoError(t, err)
+	verified, err = env.vmk.VerifySigner(ctx, newTx("50ugnot"), addr, other)
+	assert.False(t, verified)
+	assert.NoError(t, err)
+}
+
+// Packages can be listed by page, and their metadata queried.
+func TestVMKeeperQueryPackages(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: 10})
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bank.SetCoins(ctx, addr, std.MustParseCoins("100000000ugnot"))
+
+	for _, pkgPath := range []string{"gno.land/r/c", "gno.land/r/a", "gno.land/p/x/y", "gno.land/r/b"} {
+		files := []*std.MemFile{
+			{"init.gno", "package " + pkgPath[len(pkgPath)-1:] + "\n"},
+		}
+		err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+		assert.NoError(t, err)
+	}
+
+	list := env.vmk.QueryPackages(ctx, "gno.land/", "", 0)
+	assert.Equal(t, list, PackageList{Paths: []string{"gno.land/p/x/y", "gno.land/r/a", "gno.land/r/b", "gno.land/r/c"}})
+	list = env.vmk.QueryPackages(ctx, "gno.land/r/", "", 2)
+	assert.Equal(t, list, PackageList{Paths: []string{"gno.land/r/a", "gno.land/r/b"}, Next: "gno.land/r/c"})
+	list = env.vmk.QueryPackages(ctx, "gno.land/r/", list.Next, 2)
+	assert.Equal(t, list, PackageList{Paths: []string{"gno.land/r/c"}})
+	list = env.vmk.QueryPackages(ctx, "gno.land/r/", "gno.land/s", 2)
+	assert.Equal(t, list, PackageList{Paths: []string{}})
+
+	info, err := env.vmk.QueryPackageInfo(ctx, "gno.land/r/a")
+	assert.NoError(t, err)
+	assert.Equal(t, info.Path, "gno.land/r/a")
+	assert.Equal(t, info.Name, "a")
+	assert.Equal(t, info.Creator, addr)
+	assert.Equal(t, info.Height, int64(10))
+	assert.Equal(t, len(info.Hash), 64)
+	assert.Equal(t, info.Files, []string{"init.gno"})
+	_, err = env.vmk.QueryPackageInfo(ctx, "gno.land/r/missing")
+	assert.Equal(t, errors.Cause(err), InvalidPkgPathError{})
+
+	body, err := env.vmk.QueryFile(ctx, "gno.land/r/a/init.gno")
+	assert.NoError(t, err)
+	assert.Equal(t, body, "package a\n")
+	_, err = env.vmk.QueryFile(ctx, "gno.land/r/missing/init.gno")
+	assert.Equal(t, errors.Cause(err), InvalidPkgPathError{})
+}
@@ -0,0 +1,117 @@
+package vm
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Default package limits.
+const (
+	DefaultMaxPackageSize  int64 = 512 * 1024 // bytes
+	DefaultMaxPackageFiles int   = 100
+	DefaultMaxPackageDecls int   = 2000
+	DefaultMaxASTDepth     int   = 200
+)
+
+// PackageLimits bounds the packages deployed or upgraded, so that
+// pathological packages neither bloat state nor slow preprocessing.  A zero
+// limit is no limit.
+type PackageLimits struct {
+	MaxSize  int64 `json:"max_size" yaml:"max_size"`   // total bytes of files
+	MaxFiles int   `json:"max_files" yaml:"max_files"` // number of files
+	MaxDecls int   `json:"max_decls" yaml:"max_decls"` // number of declared top-level names
+	MaxDepth int   `json:"max_depth" yaml:"max_depth"` // depth of the AST of each .gno file
+}
+
+// DefaultPackageLimits returns the default package limits.
+func DefaultPackageLimits() PackageLimits {
+	return PackageLimits{
+		MaxSize:  DefaultMaxPackageSize,
+		MaxFiles: DefaultMaxPackageFiles,
+		MaxDecls: DefaultMaxPackageDecls,
+		MaxDepth: DefaultMaxASTDepth,
+	}
+}
+
+// Check returns an error if memPkg exceeds any of the limits.  Files which
+// cannot be parsed are left for the VM to report.
+func (pl PackageLimits) Check(memPkg *std.MemPackage) error {
+	if pl.MaxFiles > 0 && len(memPkg.Files) > pl.MaxFiles {
+		return ErrTooManyFiles(fmt.Sprintf(
+			"package %s has %d files, max %d", memPkg.Path, len(memPkg.Files), pl.MaxFiles))
+	}
+	size := int64(0)
+	for _, file := range memPkg.Files {
+		size += int64(len(file.Body))
+	}
+	if pl.MaxSize > 0 && size > pl.MaxSize {
+		return ErrPackageTooLarge(fmt.Sprintf(
+			"package %s has %d bytes, max %d", memPkg.Path, size, pl.MaxSize))
+	}
+	if pl.MaxDecls <= 0 && pl.MaxDepth <= 0 {
+		return nil
+	}
+	numDecls := 0
+	for _, file := range memPkg.Files {
+		if !strings.HasSuffix(file.Name, ".gno") {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, file.Name, file.Body, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		numDecls += countDecls(f)
+		if pl.MaxDecls > 0 && numDecls > pl.MaxDecls {
+			return ErrTooManyDecls(fmt.Sprintf(
+				"package %s declares more than %d names", memPkg.Path, pl.MaxDecls))
+		}
+		if pl.MaxDepth > 0 && astDepthExceeds(f, pl.MaxDepth) {
+			return ErrASTTooDeep(fmt.Sprintf(
+				"file %s is nested deeper than %d", file.Name, pl.MaxDepth))
+		}
+	}
+	return nil
+}
+
+// Returns the number of top-level names declared in f.
+func countDecls(f *ast.File) (n int) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			n++
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					n += len(spec.Names)
+				case *ast.TypeSpec:
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+// Returns true if the AST of f is deeper than max.
+func astDepthExceeds(f *ast.File, max int) bool {
+	depth, exceeds := 0, false
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil {
+			depth--
+			return false
+		}
+		depth++
+		if depth > max {
+			exceeds = true
+		}
+		return !exceeds
+	})
+	return exceeds
+}
@@ -77,6 +77,73 @@ func (msg MsgAddPackage) GetReceived() std.Coins {
 	return msg.Deposit
 }
 
+//----------------------------------------
+// MsgUpgradePackage
+
+// MsgUpgradePackage - upgrade a deployed realm with a new versioned package,
+// into which the state of the realm is carried over, and which may define a
+// migrate() function to run over it upon upgrade.
+type MsgUpgradePackage struct {
+	Creator crypto.Address  `json:"creator" yaml:"creator"`
+	PkgPath string          `json:"pkg_path" yaml:"pkg_path"` // path of original package
+	Package *std.MemPackage `json:"package" yaml:"package"`   // path must be the next version
+	Deposit std.Coins       `json:"deposit" yaml:"deposit"`
+}
+
+var _ std.Msg = MsgUpgradePackage{}
+
+// NewMsgUpgradePackage - upload a new version of package at pkgPath.
+// newPkgPath must be the next versioned package path, e.g. pkgPath+"/v2".
+func NewMsgUpgradePackage(creator crypto.Address, pkgPath string, newPkgPath string, files []*std.MemFile) MsgUpgradePackage {
+	msg := NewMsgAddPackage(creator, newPkgPath, files)
+	return MsgUpgradePackage{
+		Creator: creator,
+		PkgPath: pkgPath,
+		Package: msg.Package,
+	}
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Type() string { return "upgrade_package" }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) ValidateBasic() error {
+	if msg.Creator.IsZero() {
+		return std.ErrInvalidAddress("missing creator address")
+	}
+	if msg.PkgPath == "" { // XXX
+		return ErrInvalidPkgPath("missing package path")
+	}
+	if msg.Package == nil || msg.Package.Path == "" { // XXX
+		return ErrInvalidPkgPath("missing new package path")
+	}
+	if !strings.HasPrefix(msg.Package.Path, msg.PkgPath+"/v") {
+		return ErrInvalidPkgPath("new package path must be a version of package path")
+	}
+	if !msg.Deposit.IsValid() {
+		return std.ErrTxDecode("invalid deposit")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Creator}
+}
+
+// Implements ReceiveMsg.
+func (msg MsgUpgradePackage) GetReceived() std.Coins {
+	return msg.Deposit
+}
+
 //----------------------------------------
 // MsgCall
 
@@ -135,3 +202,195 @@ func (msg MsgCall) GetSigners() []crypto.Address {
 func (msg MsgCall) GetReceived() std.Coins {
 	return msg.Send
 }
+
+//----------------------------------------
+// MsgSetVerifier
+
+// MsgSetVerifier - set the verification realm of the account of Caller, or
+// unset it if PkgPath is empty.  See VMKeeper.VerifySigner.
+type MsgSetVerifier struct {
+	Caller  crypto.Address `json:"caller" yaml:"caller"`
+	PkgPath string         `json:"pkg_path" yaml:"pkg_path"`
+}
+
+var _ std.Msg = MsgSetVerifier{}
+
+func NewMsgSetVerifier(caller crypto.Address, pkgPath string) MsgSetVerifier {
+	return MsgSetVerifier{
+		Caller:  caller,
+		PkgPath: pkgPath,
+	}
+}
+
+// Implements Msg.
+func (msg MsgSetVerifier) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgSetVerifier) Type() string { return "set_verifier" }
+
+// Implements Msg.
+func (msg MsgSetVerifier) ValidateBasic() error {
+	if msg.Caller.IsZero() {
+		return std.ErrInvalidAddress("missing caller address")
+	}
+	if msg.PkgPath != "" && !gno.IsRealmPath(msg.PkgPath) {
+		return ErrInvalidPkgPath("package is not realm: " + msg.PkgPath)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgSetVerifier) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgSetVerifier) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Caller}
+}
+
+//----------------------------------------
+// MsgPausePackage
+
+// MsgPausePackage - pause the realm at PkgPath, or unpause it if Paused is
+// false.  See VMKeeper.PausePackage.
+type MsgPausePackage struct {
+	Caller  crypto.Address `json:"caller" yaml:"caller"`
+	PkgPath string         `json:"pkg_path" yaml:"pkg_path"`
+	Paused  bool           `json:"paused" yaml:"paused"`
+}
+
+var _ std.Msg = MsgPausePackage{}
+
+func NewMsgPausePackage(caller crypto.Address, pkgPath string, paused bool) MsgPausePackage {
+	return MsgPausePackage{
+		Caller:  caller,
+		PkgPath: pkgPath,
+		Paused:  paused,
+	}
+}
+
+// Implements Msg.
+func (msg MsgPausePackage) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgPausePackage) Type() string { return "pause_package" }
+
+// Implements Msg.
+func (msg MsgPausePackage) ValidateBasic() error {
+	if msg.Caller.IsZero() {
+		return std.ErrInvalidAddress("missing caller address")
+	}
+	if !gno.IsRealmPath(msg.PkgPath) {
+		return ErrInvalidPkgPath("package is not realm: " + msg.PkgPath)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgPausePackage) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgPausePackage) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Caller}
+}
+
+//----------------------------------------
+// MsgRegisterNamespace
+
+// MsgRegisterNamespace - register a namespace of packages, e.g. alice of
+// gno.land/r/alice and gno.land/p/alice/*, to Owner.  See VMKeeper.RegisterNamespace.
+type MsgRegisterNamespace struct {
+	Owner     crypto.Address `json:"owner" yaml:"owner"`
+	Namespace string         `json:"namespace" yaml:"namespace"`
+}
+
+var _ std.Msg = MsgRegisterNamespace{}
+
+func NewMsgRegisterNamespace(owner crypto.Address, namespace string) MsgRegisterNamespace {
+	return MsgRegisterNamespace{
+		Owner:     owner,
+		Namespace: namespace,
+	}
+}
+
+// Implements Msg.
+func (msg MsgRegisterNamespace) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgRegisterNamespace) Type() string { return "register_namespace" }
+
+// Implements Msg.
+func (msg MsgRegisterNamespace) ValidateBasic() error {
+	if msg.Owner.IsZero() {
+		return std.ErrInvalidAddress("missing owner address")
+	}
+	if !reNamespace.MatchString(msg.Namespace) {
+		return ErrInvalidPkgPath("invalid namespace: " + msg.Namespace)
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgRegisterNamespace) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgRegisterNamespace) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Owner}
+}
+
+//----------------------------------------
+// MsgApprove
+
+// MsgApprove - allow Spender, e.g. a realm, to transfer up to Amount of the
+// coins of Owner with std.Banker.TransferFrom, replacing any previous
+// allowance.  See VMKeeper.Approve.
+type MsgApprove struct {
+	Owner   crypto.Address `json:"owner" yaml:"owner"`
+	Spender crypto.Address `json:"spender" yaml:"spender"`
+	Amount  std.Coins      `json:"amount" yaml:"amount"`
+}
+
+var _ std.Msg = MsgApprove{}
+
+func NewMsgApprove(owner, spender crypto.Address, amount std.Coins) MsgApprove {
+	return MsgApprove{
+		Owner:   owner,
+		Spender: spender,
+		Amount:  amount,
+	}
+}
+
+// Implements Msg.
+func (msg MsgApprove) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgApprove) Type() string { return "approve" }
+
+// Implements Msg.
+func (msg MsgApprove) ValidateBasic() error {
+	if msg.Owner.IsZero() {
+		return std.ErrInvalidAddress("missing owner address")
+	}
+	if msg.Spender.IsZero() {
+		return std.ErrInvalidAddress("missing spender address")
+	}
+	if !msg.Amount.IsZero() && !msg.Amount.IsValid() {
+		return std.ErrInvalidCoins(msg.Amount.String())
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgApprove) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgApprove) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Owner}
+}
@@ -14,9 +14,37 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 ).WithTypes(
 	MsgCall{}, "m_call",
 	MsgAddPackage{}, "m_addpkg", // TODO rename both to MsgAddPkg?
+	MsgUpgradePackage{}, "m_upgradepkg",
+	MsgSetVerifier{}, "m_setverifier",
+	MsgPausePackage{}, "m_pausepkg",
+	MsgRegisterNamespace{}, "m_registerns",
+	MsgApprove{}, "m_approve",
+
+	// events
+	RealmCallEvent{}, "RealmCallEvent",
+	ScheduledCallEvent{}, "ScheduledCallEvent",
+	RealmMessageEvent{}, "RealmMessageEvent",
+	RealmEvent{}, "RealmEvent",
+	RealmEventAttr{}, "RealmEventAttr",
+	RealmStorageEvent{}, "RealmStorageEvent",
+
+	// state
+	GenesisState{}, "GenesisState",
+	ScheduledCall{}, "ScheduledCall",
+	RealmMessage{}, "RealmMessage",
 
 	// errors
 	InvalidPkgPathError{}, "InvalidPkgPathError",
 	InvalidStmtError{}, "InvalidStmtError",
 	InvalidExprError{}, "InvalidExprError",
+	UnformattedPackageError{}, "UnformattedPackageError",
+	PackagePausedError{}, "PackagePausedError",
+	PackageTooLargeError{}, "PackageTooLargeError",
+	TooManyFilesError{}, "TooManyFilesError",
+	TooManyDeclsError{}, "TooManyDeclsError",
+	ASTTooDeepError{}, "ASTTooDeepError",
+	TypeCheckError{}, "TypeCheckError",
+	MigrationError{}, "MigrationError",
+	VMPanicError{}, "VMPanicError",
+	StackFrame{}, "StackFrame",
 ))
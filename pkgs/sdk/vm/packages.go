@@ -0,0 +1,54 @@
+package vm
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+)
+
+// Maximum number of package paths returned by a vm/qpackages query, and
+// the default if the limit is unspecified.
+const MaxQueryPackagesLimit = 100
+
+// QueryPackages returns up to limit paths of the packages whose path starts
+// with prefix, in lexicographic order, starting at start if not empty.
+// The next path to start at is returned with the page, if any.
+func (vm *VMKeeper) QueryPackages(ctx sdk.Context, prefix string, start string, limit int) PackageList {
+	if limit <= 0 || limit > MaxQueryPackagesLimit {
+		limit = MaxQueryPackagesLimit
+	}
+	store := vm.getGnoStore(ctx)
+	// one more than limit, to get the start of the next page.
+	paths := store.ListMemPackagePaths(prefix, start, limit+1)
+	if len(paths) > limit {
+		return PackageList{Paths: paths[:limit], Next: paths[limit]}
+	}
+	return PackageList{Paths: paths}
+}
+
+// QueryPackageInfo returns the metadata of the package at pkgPath.
+func (vm *VMKeeper) QueryPackageInfo(ctx sdk.Context, pkgPath string) (info PackageInfo, err error) {
+	store := vm.getGnoStore(ctx)
+	if pv := store.GetPackage(pkgPath, false); pv == nil {
+		err = ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+		return PackageInfo{}, err
+	}
+	memPkg := store.GetMemPackage(pkgPath)
+	files := make([]string, len(memPkg.Files))
+	for i, mfile := range memPkg.Files {
+		files[i] = mfile.Name
+	}
+	iavlStore := ctx.Store(vm.iavlKey)
+	return PackageInfo{
+		Path:    memPkg.Path,
+		Name:    memPkg.Name,
+		Creator: getPackageCreator(iavlStore, pkgPath),
+		Height:  getPackageHeight(iavlStore, pkgPath),
+		Hash:    hex.EncodeToString(crypto.Sha256(amino.MustMarshal(memPkg))),
+		Files:   files,
+	}, nil
+}
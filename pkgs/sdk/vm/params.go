@@ -0,0 +1,147 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+type VMParamsContextKey struct{}
+
+// Default parameter values
+const (
+	DefaultGasPerCycle           int64 = 1
+	DefaultGasPerAllocByte       int64 = 1
+	DefaultStoreReadCostFlat     int64 = 1000
+	DefaultStoreReadCostPerByte  int64 = 3
+	DefaultStoreWriteCostFlat    int64 = 2000
+	DefaultStoreWriteCostPerByte int64 = 30
+	DefaultVerifyTxMaxGas        int64 = 1000000
+	DefaultScheduledGasPerBlock  int64 = 10000000
+	DefaultMessageGasPerBlock    int64 = 10000000
+	DefaultGasRefundPerFreedByte int64 = 10
+	DefaultTypeCheckGasPerByte   int64 = 10
+	DefaultMaxFrames             int64 = 10 * 1000
+	DefaultMaxValues             int64 = 100 * 1000
+	DefaultMaxExprs              int64 = 10 * 1000
+)
+
+// Params defines the gas parameters and the machine stack limits for the vm
+// module.
+type Params struct {
+	GasPerCycle           int64 `json:"gas_per_cycle" yaml:"gas_per_cycle"`
+	GasPerAllocByte       int64 `json:"gas_per_alloc_byte" yaml:"gas_per_alloc_byte"`
+	StoreReadCostFlat     int64 `json:"store_read_cost_flat" yaml:"store_read_cost_flat"`
+	StoreReadCostPerByte  int64 `json:"store_read_cost_per_byte" yaml:"store_read_cost_per_byte"`
+	StoreWriteCostFlat    int64 `json:"store_write_cost_flat" yaml:"store_write_cost_flat"`
+	StoreWriteCostPerByte int64 `json:"store_write_cost_per_byte" yaml:"store_write_cost_per_byte"`
+	VerifyTxMaxGas        int64 `json:"verify_tx_max_gas" yaml:"verify_tx_max_gas"`
+	ScheduledGasPerBlock  int64 `json:"scheduled_gas_per_block" yaml:"scheduled_gas_per_block"`
+	MessageGasPerBlock    int64 `json:"message_gas_per_block" yaml:"message_gas_per_block"`
+	GasRefundPerFreedByte int64 `json:"gas_refund_per_freed_byte" yaml:"gas_refund_per_freed_byte"`
+	TypeCheckGasPerByte   int64 `json:"type_check_gas_per_byte" yaml:"type_check_gas_per_byte"`
+	MaxFrames             int64 `json:"max_frames" yaml:"max_frames"`
+	MaxValues             int64 `json:"max_values" yaml:"max_values"`
+	MaxExprs              int64 `json:"max_exprs" yaml:"max_exprs"`
+}
+
+// NewParams creates a new Params object
+func NewParams(gasPerCycle, gasPerAllocByte, storeReadCostFlat,
+	storeReadCostPerByte, storeWriteCostFlat, storeWriteCostPerByte,
+	verifyTxMaxGas, scheduledGasPerBlock, messageGasPerBlock,
+	gasRefundPerFreedByte, typeCheckGasPerByte,
+	maxFrames, maxValues, maxExprs int64,
+) Params {
+	return Params{
+		GasPerCycle:           gasPerCycle,
+		GasPerAllocByte:       gasPerAllocByte,
+		StoreReadCostFlat:     storeReadCostFlat,
+		StoreReadCostPerByte:  storeReadCostPerByte,
+		StoreWriteCostFlat:    storeWriteCostFlat,
+		StoreWriteCostPerByte: storeWriteCostPerByte,
+		VerifyTxMaxGas:        verifyTxMaxGas,
+		ScheduledGasPerBlock:  scheduledGasPerBlock,
+		MessageGasPerBlock:    messageGasPerBlock,
+		GasRefundPerFreedByte: gasRefundPerFreedByte,
+		TypeCheckGasPerByte:   typeCheckGasPerByte,
+		MaxFrames:             maxFrames,
+		MaxValues:             maxValues,
+		MaxExprs:              maxExprs,
+	}
+}
+
+// Equals returns a boolean determining if two Params types are identical.
+func (p Params) Equals(p2 Params) bool {
+	return amino.DeepEqual(p, p2)
+}
+
+// DefaultParams returns a default set of parameters.
+func DefaultParams() Params {
+	return Params{
+		GasPerCycle:           DefaultGasPerCycle,
+		GasPerAllocByte:       DefaultGasPerAllocByte,
+		StoreReadCostFlat:     DefaultStoreReadCostFlat,
+		StoreReadCostPerByte:  DefaultStoreReadCostPerByte,
+		StoreWriteCostFlat:    DefaultStoreWriteCostFlat,
+		StoreWriteCostPerByte: DefaultStoreWriteCostPerByte,
+		VerifyTxMaxGas:        DefaultVerifyTxMaxGas,
+		ScheduledGasPerBlock:  DefaultScheduledGasPerBlock,
+		MessageGasPerBlock:    DefaultMessageGasPerBlock,
+		GasRefundPerFreedByte: DefaultGasRefundPerFreedByte,
+		TypeCheckGasPerByte:   DefaultTypeCheckGasPerByte,
+		MaxFrames:             DefaultMaxFrames,
+		MaxValues:             DefaultMaxValues,
+		MaxExprs:              DefaultMaxExprs,
+	}
+}
+
+// MachineGasConfig returns the gas config for GnoVM machines.
+func (p Params) MachineGasConfig() gno.GasConfig {
+	return gno.GasConfig{
+		CPUCycle:  p.GasPerCycle,
+		AllocByte: p.GasPerAllocByte,
+	}
+}
+
+// StoreGasConfig returns the gas config for the stores of the vm module.
+func (p Params) StoreGasConfig() store.GasConfig {
+	gc := store.DefaultGasConfig()
+	gc.ReadCostFlat = p.StoreReadCostFlat
+	gc.ReadCostPerByte = p.StoreReadCostPerByte
+	gc.WriteCostFlat = p.StoreWriteCostFlat
+	gc.WriteCostPerByte = p.StoreWriteCostPerByte
+	return gc
+}
+
+// String implements the stringer interface.
+func (p Params) String() string {
+	var sb strings.Builder
+	sb.WriteString("Params: \n")
+	sb.WriteString(fmt.Sprintf("GasPerCycle: %d\n", p.GasPerCycle))
+	sb.WriteString(fmt.Sprintf("GasPerAllocByte: %d\n", p.GasPerAllocByte))
+	sb.WriteString(fmt.Sprintf("StoreReadCostFlat: %d\n", p.StoreReadCostFlat))
+	sb.WriteString(fmt.Sprintf("StoreReadCostPerByte: %d\n", p.StoreReadCostPerByte))
+	sb.WriteString(fmt.Sprintf("StoreWriteCostFlat: %d\n", p.StoreWriteCostFlat))
+	sb.WriteString(fmt.Sprintf("StoreWriteCostPerByte: %d\n", p.StoreWriteCostPerByte))
+	sb.WriteString(fmt.Sprintf("VerifyTxMaxGas: %d\n", p.VerifyTxMaxGas))
+	sb.WriteString(fmt.Sprintf("ScheduledGasPerBlock: %d\n", p.ScheduledGasPerBlock))
+	sb.WriteString(fmt.Sprintf("MessageGasPerBlock: %d\n", p.MessageGasPerBlock))
+	sb.WriteString(fmt.Sprintf("GasRefundPerFreedByte: %d\n", p.GasRefundPerFreedByte))
+	sb.WriteString(fmt.Sprintf("TypeCheckGasPerByte: %d\n", p.TypeCheckGasPerByte))
+	sb.WriteString(fmt.Sprintf("MaxFrames: %d\n", p.MaxFrames))
+	sb.WriteString(fmt.Sprintf("MaxValues: %d\n", p.MaxValues))
+	sb.WriteString(fmt.Sprintf("MaxExprs: %d\n", p.MaxExprs))
+	return sb.String()
+}
+
+// Returns the params set in ctx, or the default params.
+func getParams(ctx sdk.Context) Params {
+	if params, ok := ctx.Value(VMParamsContextKey{}).(Params); ok {
+		return params
+	}
+	return DefaultParams()
+}
@@ -0,0 +1,85 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// A realm may be paused by its creator or the upgrade authority, e.g. when
+// compromised, until it is upgraded.  Paused realms are kept in the iavl
+// store, so that they are part of consensus state.
+func packagePausedKey(pkgPath string) []byte {
+	return []byte("pkgpaused:" + pkgPath)
+}
+
+func isPackagePaused(iavlStore store.Store, pkgPath string) bool {
+	return iavlStore.Has(packagePausedKey(pkgPath))
+}
+
+func setPackagePaused(iavlStore store.Store, pkgPath string, paused bool) {
+	if paused {
+		iavlStore.Set(packagePausedKey(pkgPath), []byte{1})
+	} else {
+		iavlStore.Delete(packagePausedKey(pkgPath))
+	}
+}
+
+// Panicked by the realm guard of the machines of txs, and returned as an
+// error by the keeper (see vmPanicError).
+type realmGuardPanic struct {
+	err error
+}
+
+// Returns the realm guard of the machines of txs, which prevents them from
+// entering a paused realm, whether called by a tx, by another realm, or by
+// a package being added.  Only later versions of a paused realm may enter
+// it, e.g. to migrate its state.
+func (vm *VMKeeper) newRealmGuard(ctx sdk.Context) gno.RealmGuard {
+	iavlStore := ctx.Store(vm.iavlKey)
+	return func(from string, to string) {
+		if isPackagePaused(iavlStore, to) && !isLaterVersion(from, to) {
+			panic(realmGuardPanic{ErrPackagePaused(fmt.Sprintf(
+				"package %s is paused", to))})
+		}
+	}
+}
+
+// PausePackage pauses or unpauses the realm of msg.  The functions of a
+// paused realm may not be called by txs, including scheduled calls, realm
+// messages and calls from other realms, until it is unpaused; its state may
+// still be queried and it may still be upgraded, the new version being
+// unpaused.  The previous versions of upgraded realms may not be unpaused.
+func (vm *VMKeeper) PausePackage(ctx sdk.Context, msg MsgPausePackage) error {
+	caller := msg.Caller
+	pkgPath := msg.PkgPath
+	store := vm.getGnoStore(ctx)
+	iavlStore := ctx.Store(vm.iavlKey)
+
+	if !gno.IsRealmPath(pkgPath) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package is not realm: %s", pkgPath))
+	}
+	if pv := store.GetPackage(pkgPath, false); pv == nil {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+	}
+	// Check permission, as for upgrades.
+	owner := getPackageCreator(iavlStore, pkgPath)
+	if caller != owner && (vm.upgradeAuthority.IsZero() || caller != vm.upgradeAuthority) {
+		return std.ErrUnauthorized(fmt.Sprintf(
+			"%s may not pause package %s", caller, pkgPath))
+	}
+	// The previous versions of upgraded realms stay paused.
+	origPath, _ := splitVersion(pkgPath)
+	if versions := getPackageVersions(iavlStore, origPath); len(versions) > 0 &&
+		pkgPath != versions[len(versions)-1] {
+		return ErrPackagePaused(fmt.Sprintf(
+			"package %s was upgraded", pkgPath))
+	}
+	setPackagePaused(iavlStore, pkgPath, msg.Paused)
+	return nil
+}
@@ -0,0 +1,121 @@
+package vm
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// RealmMessage is a message sent by the realm at From with std.SendMessage:
+// a call of Func(Arg), a function of the realm at PkgPath, with Gas prepaid
+// by the tx which sent it.
+type RealmMessage struct {
+	From    string `json:"from" yaml:"from"`
+	PkgPath string `json:"pkg_path" yaml:"pkg_path"`
+	Func    string `json:"func" yaml:"func"`
+	Arg     string `json:"arg" yaml:"arg"`
+	Gas     int64  `json:"gas" yaml:"gas"`
+}
+
+// RealmMessageEvent is emitted at the end of a block for each message
+// delivered, with the gas it used, and its error if it failed, in which case
+// its changes are discarded.
+type RealmMessageEvent struct {
+	From    string `json:"from" yaml:"from"`
+	PkgPath string `json:"pkg_path" yaml:"pkg_path"`
+	Func    string `json:"func" yaml:"func"`
+	Seq     uint64 `json:"seq" yaml:"seq"`
+	GasUsed int64  `json:"gas_used" yaml:"gas_used"`
+	Error   string `json:"error" yaml:"error"`
+}
+
+// Implements abci.Event.
+func (RealmMessageEvent) AssertABCIEvent() {}
+
+// The messages are queued in the iavl store, so that they are part of
+// consensus state, by sequence number, which is the order in which they
+// are sent and delivered.
+var realmMessagePrefix = []byte("realmmsg:")
+
+func realmMessageKey(seq uint64) []byte {
+	key := make([]byte, len(realmMessagePrefix)+8)
+	copy(key, realmMessagePrefix)
+	binary.BigEndian.PutUint64(key[len(realmMessagePrefix):], seq)
+	return key
+}
+
+var realmMessageSeqKey = []byte("realmmsgseq")
+
+// SendMessage queues msg, after charging its gas to the gas meter of ctx.
+// The message must be to a realm, of an exported function, and its gas at
+// most the MessageGasPerBlock of the vm params.
+func (vm *VMKeeper) SendMessage(ctx sdk.Context, msg RealmMessage) error {
+	if !gno.IsRealmPath(msg.PkgPath) {
+		return errors.New("cannot send a message to %s, not a realm", msg.PkgPath)
+	}
+	if msg.Func == "" || strings.ToUpper(msg.Func[0:1]) != msg.Func[0:1] {
+		return errors.New("cannot send a message to unexported function %s", msg.Func)
+	}
+	maxGas := getParams(ctx).MessageGasPerBlock
+	if msg.Gas <= 0 || msg.Gas > maxGas {
+		return errors.New("cannot send a message with %d gas, not in [1, %d]", msg.Gas, maxGas)
+	}
+	ctx.GasMeter().ConsumeGas(msg.Gas, "SendMessage")
+	iavlStore := ctx.Store(vm.iavlKey)
+	seq := nextSeq(iavlStore, realmMessageSeqKey)
+	iavlStore.Set(realmMessageKey(seq), amino.MustMarshal(msg))
+	return nil
+}
+
+// DeliverMessages delivers the queued messages in the order in which they
+// were sent, including those sent while delivering them, as long as their
+// total gas is at most the MessageGasPerBlock of the vm params; the others
+// are left queued for the next blocks.  Each message is delivered with its
+// sending realm as the original caller, and may use at most its gas.  It
+// returns the events of the messages delivered.
+func (vm *VMKeeper) DeliverMessages(ctx sdk.Context) (events []abci.Event) {
+	iavlStore := ctx.Store(vm.iavlKey)
+	budget := getParams(ctx).MessageGasPerBlock
+	for {
+		seq, msg, ok := firstRealmMessage(iavlStore)
+		if !ok || msg.Gas > budget {
+			break
+		}
+		budget -= msg.Gas
+		iavlStore.Delete(realmMessageKey(seq))
+		events = append(events, vm.deliverMessage(ctx, seq, msg))
+	}
+	return events
+}
+
+// Returns the first queued message, and its sequence number, if any.
+func firstRealmMessage(iavlStore store.Store) (seq uint64, msg RealmMessage, ok bool) {
+	itr := store.PrefixIterator(iavlStore, realmMessagePrefix)
+	defer itr.Close()
+	if !itr.Valid() {
+		return 0, msg, false
+	}
+	seq = binary.BigEndian.Uint64(itr.Key()[len(realmMessagePrefix):])
+	amino.MustUnmarshal(itr.Value(), &msg)
+	return seq, msg, true
+}
+
+// Delivers msg, and returns its event.
+func (vm *VMKeeper) deliverMessage(ctx sdk.Context, seq uint64, msg RealmMessage) RealmMessageEvent {
+	caller := gno.DerivePkgAddr(msg.From)
+	gasUsed, err := vm.runPrepaidCall(ctx, caller, msg.PkgPath, msg.Func, msg.Arg, msg.Gas)
+	return RealmMessageEvent{
+		From:    msg.From,
+		PkgPath: msg.PkgPath,
+		Func:    msg.Func,
+		Seq:     seq,
+		GasUsed: gasUsed,
+		Error:   err,
+	}
+}
@@ -0,0 +1,59 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/sdk"
+)
+
+// ExportRealm returns the persisted state of the realm at pkgPath, as of
+// the state of ctx, e.g. to fork it on another chain with ImportRealm.
+func (vm *VMKeeper) ExportRealm(ctx sdk.Context, pkgPath string) (*gno.RealmState, error) {
+	store := vm.getGnoStore(ctx)
+	if pv := store.GetPackage(pkgPath, false); pv == nil || !pv.IsRealm() {
+		return nil, ErrInvalidPkgPath(fmt.Sprintf(
+			"realm not found: %s", pkgPath))
+	}
+	return store.ExportRealm(pkgPath), nil
+}
+
+// ImportRealm replaces the state of the realm of state, which must exist
+// with the same package as the exported realm, e.g. at genesis or in a
+// development chain.  The storage usage of the realm is updated, but not
+// charged for.
+func (vm *VMKeeper) ImportRealm(ctx sdk.Context, state *gno.RealmState) error {
+	if state.Realm == nil {
+		return ErrInvalidPkgPath("missing realm of state")
+	}
+	pkgPath := state.Realm.Path
+	if state.Realm.ID != gno.PkgIDFromPkgPath(pkgPath) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"unexpected id of realm %s", pkgPath))
+	}
+	store := vm.getGnoStore(ctx)
+	if pv := store.GetPackage(pkgPath, false); pv == nil || !pv.IsRealm() {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"realm not found: %s", pkgPath))
+	}
+	for _, oo := range state.Objects {
+		if oo.GetObjectID().PkgID != state.Realm.ID {
+			return ErrInvalidPkgPath(fmt.Sprintf(
+				"object %v is not of realm %s", oo.GetObjectID(), pkgPath))
+		}
+	}
+	store.ImportRealm(state)
+	vm.updateStorageUsage(ctx, store)
+	return nil
+}
+
+// QueryRealmState returns the persisted state of the realm at pkgPath as
+// amino JSON, which may be imported at genesis.
+func (vm *VMKeeper) QueryRealmState(ctx sdk.Context, pkgPath string) (res string, err error) {
+	state, err := vm.ExportRealm(ctx, pkgPath)
+	if err != nil {
+		return "", err
+	}
+	return string(amino.MustMarshalJSON(state)), nil
+}
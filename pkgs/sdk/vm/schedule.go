@@ -0,0 +1,184 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// ScheduledCall is a call of Func(Arg), a function of the realm at PkgPath,
+// scheduled by the realm with std.ScheduleCall at the end of the block of
+// Height, with Gas prepaid by the tx which scheduled it.
+type ScheduledCall struct {
+	PkgPath string `json:"pkg_path" yaml:"pkg_path"`
+	Func    string `json:"func" yaml:"func"`
+	Arg     string `json:"arg" yaml:"arg"`
+	Height  int64  `json:"height" yaml:"height"`
+	Gas     int64  `json:"gas" yaml:"gas"`
+}
+
+// ScheduledCallEvent is emitted at the end of a block for each scheduled
+// call run, with the gas it used, and its error if it failed, in which case
+// its changes are discarded, or expired without running.
+type ScheduledCallEvent struct {
+	PkgPath string `json:"pkg_path" yaml:"pkg_path"`
+	Func    string `json:"func" yaml:"func"`
+	Height  int64  `json:"height" yaml:"height"` // scheduled height
+	GasUsed int64  `json:"gas_used" yaml:"gas_used"`
+	Error   string `json:"error" yaml:"error"`
+}
+
+// Implements abci.Event.
+func (ScheduledCallEvent) AssertABCIEvent() {}
+
+// The scheduled calls are kept in the iavl store, so that they are part of
+// consensus state, by height then by sequence number, which is the order in
+// which they are run.
+var scheduledCallPrefix = []byte("schedcall:")
+
+func scheduledCallKey(height int64, seq uint64) []byte {
+	key := make([]byte, len(scheduledCallPrefix)+16)
+	copy(key, scheduledCallPrefix)
+	binary.BigEndian.PutUint64(key[len(scheduledCallPrefix):], uint64(height))
+	binary.BigEndian.PutUint64(key[len(scheduledCallPrefix)+8:], seq)
+	return key
+}
+
+var scheduledCallSeqKey = []byte("schedcallseq")
+
+// Returns the next sequence number of the counter at key.
+func nextSeq(iavlStore store.Store, key []byte) uint64 {
+	seq := uint64(0)
+	if bz := iavlStore.Get(key); bz != nil {
+		seq = binary.BigEndian.Uint64(bz)
+	}
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, seq+1)
+	iavlStore.Set(key, bz)
+	return seq
+}
+
+// ScheduleCall schedules call, after charging its gas to the gas meter of
+// ctx.  The call must be at a later height than ctx, of an exported
+// function, and its gas at most the ScheduledGasPerBlock of the vm params.
+func (vm *VMKeeper) ScheduleCall(ctx sdk.Context, call ScheduledCall) error {
+	if call.Height <= ctx.BlockHeight() {
+		return errors.New("cannot schedule a call at height %d, not after the current height %d",
+			call.Height, ctx.BlockHeight())
+	}
+	if call.Func == "" || strings.ToUpper(call.Func[0:1]) != call.Func[0:1] {
+		return errors.New("cannot schedule a call of unexported function %s", call.Func)
+	}
+	maxGas := getParams(ctx).ScheduledGasPerBlock
+	if call.Gas <= 0 || call.Gas > maxGas {
+		return errors.New("cannot schedule a call with %d gas, not in [1, %d]", call.Gas, maxGas)
+	}
+	ctx.GasMeter().ConsumeGas(call.Gas, "ScheduleCall")
+	iavlStore := ctx.Store(vm.iavlKey)
+	seq := nextSeq(iavlStore, scheduledCallSeqKey)
+	iavlStore.Set(scheduledCallKey(call.Height, seq), amino.MustMarshal(call))
+	return nil
+}
+
+// RunScheduledCalls runs the calls scheduled at the height of ctx, and those
+// left over from the previous blocks, in order, as long as their total gas
+// is at most the ScheduledGasPerBlock of the vm params.  The first call over
+// the remaining gas is left over to the next blocks, where it comes first,
+// with all the calls after it, so that each block only reads the calls it
+// runs; a call over all the gas of a block, e.g. after the params were
+// lowered, could never run, and expires.
+// Each call is made with its realm as the original caller, and may use at
+// most its gas.  It returns the events of the calls run or expired.
+func (vm *VMKeeper) RunScheduledCalls(ctx sdk.Context) (events []abci.Event) {
+	iavlStore := ctx.Store(vm.iavlKey)
+	keys := [][]byte{}
+	calls := []ScheduledCall{}
+	maxGas := getParams(ctx).ScheduledGasPerBlock
+	budget := maxGas
+	end := scheduledCallKey(ctx.BlockHeight()+1, 0)
+	itr := iavlStore.Iterator(scheduledCallPrefix, end)
+	for ; itr.Valid() && budget > 0; itr.Next() {
+		var call ScheduledCall
+		amino.MustUnmarshal(itr.Value(), &call)
+		if call.Gas > maxGas {
+			keys = append(keys, itr.Key())
+			calls = append(calls, call)
+			continue
+		}
+		if call.Gas > budget {
+			break
+		}
+		budget -= call.Gas
+		keys = append(keys, itr.Key())
+		calls = append(calls, call)
+	}
+	itr.Close()
+	for i, call := range calls {
+		iavlStore.Delete(keys[i])
+		if call.Gas > maxGas {
+			events = append(events, ScheduledCallEvent{
+				PkgPath: call.PkgPath,
+				Func:    call.Func,
+				Height:  call.Height,
+				Error: fmt.Sprintf("expired: gas %d over the scheduled gas per block %d",
+					call.Gas, maxGas),
+			})
+			continue
+		}
+		events = append(events, vm.runScheduledCall(ctx, call))
+	}
+	return events
+}
+
+// Runs call, and returns its event.
+func (vm *VMKeeper) runScheduledCall(ctx sdk.Context, call ScheduledCall) ScheduledCallEvent {
+	caller := gno.DerivePkgAddr(call.PkgPath)
+	gasUsed, err := vm.runPrepaidCall(ctx, caller, call.PkgPath, call.Func, call.Arg, call.Gas)
+	return ScheduledCallEvent{
+		PkgPath: call.PkgPath,
+		Func:    call.Func,
+		Height:  call.Height,
+		GasUsed: gasUsed,
+		Error:   err,
+	}
+}
+
+// Calls fn(arg) of the realm at pkgPath, from caller, with at most gas, and
+// returns the gas used, and the error if it failed, in which case its
+// changes are discarded.
+func (vm *VMKeeper) runPrepaidCall(ctx sdk.Context, caller crypto.Address, pkgPath, fn, arg string, gas int64) (gasUsed int64, err string) {
+	cctx, writeCache := ctx.CacheContext()
+	gasMeter := store.NewGasMeter(gas)
+	cctx = cctx.WithGasMeter(gasMeter)
+	defer func() {
+		gasUsed = gasMeter.GasConsumedToLimit()
+		if r := recover(); r != nil {
+			if isOutOfGas(r) {
+				err = "out of gas"
+			} else {
+				err = fmt.Sprintf("%v", r)
+			}
+		}
+		if err == "" {
+			writeCache()
+		}
+	}()
+	msg := MsgCall{
+		Caller:  caller,
+		PkgPath: pkgPath,
+		Func:    fn,
+		Args:    []string{arg},
+	}
+	if _, cerr := vm.Call(cctx, msg); cerr != nil {
+		err = cerr.Error()
+	}
+	return
+}
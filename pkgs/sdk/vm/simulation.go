@@ -0,0 +1,34 @@
+package vm
+
+import (
+	"math/rand"
+
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/sdk/simulation"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// SimulateMsgCall returns an operation which calls fn of the realm at
+// pkgPath with random args, sending a random amount of the coins of the
+// signer, beyond the fee and the amount charged by the call, every other
+// call.
+func SimulateMsgCall(bank bank.ViewKeeperI, pkgPath, fn string, args func(r *rand.Rand) []string) simulation.Operation {
+	return func(r *rand.Rand, ctx sdk.Context, env *simulation.Env) (simulation.OperationMsg, error) {
+		caller := simulation.RandomAccount(r, env.Signers)
+		spendable := env.Spendable(bank.GetCoins(ctx, caller.Address))
+		charge := std.MustParseCoins(callFee)
+		if !spendable.IsAllGTE(charge) {
+			return simulation.NoOpMsg("vm/exec: no coins for the call"), nil
+		}
+		spendable = spendable.Sub(charge)
+		var send std.Coins
+		if r.Intn(2) == 0 {
+			if coin := simulation.RandomCoin(r, spendable); coin.IsPositive() {
+				send = std.Coins{coin}
+			}
+		}
+		msg := NewMsgCall(caller.Address, send, pkgPath, fn, args(r))
+		return simulation.NewOperationMsg(msg, caller), nil
+	}
+}
@@ -0,0 +1,123 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/auth"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/overflow"
+)
+
+// Storage usage is tracked per package (realm or not) in the iavl store,
+// so that it is part of consensus state.
+func storageUsageKey(pkgID gno.PkgID) []byte {
+	return []byte("storage:" + pkgID.String())
+}
+
+func getStorageUsage(iavlStore store.Store, pkgID gno.PkgID) int64 {
+	bz := iavlStore.Get(storageUsageKey(pkgID))
+	if bz == nil {
+		return 0
+	}
+	usage, err := strconv.ParseInt(string(bz), 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	return usage
+}
+
+func setStorageUsage(iavlStore store.Store, pkgID gno.PkgID, usage int64) {
+	bz := []byte(strconv.FormatInt(usage, 10))
+	iavlStore.Set(storageUsageKey(pkgID), bz)
+}
+
+// processStorageDiffs emits the storage events of the realms changed in the
+// gno store, applies its storage diffs to the storage usage of each
+// package, refunds gas for any storage freed, and charges payer for any
+// growth if a storage price is set.
+func (vm *VMKeeper) processStorageDiffs(ctx sdk.Context, gnoStore gno.Store, payer crypto.Address) error {
+	emitStorageEvents(ctx, gnoStore)
+	growth, freed := vm.updateStorageUsage(ctx, gnoStore)
+	// Refund gas for freed storage.
+	refundFreedStorage(ctx, freed)
+	// Charge for growth.
+	if growth == 0 || vm.storagePrice.IsZero() {
+		return nil
+	}
+	amount, ok := overflow.Mul64(growth, vm.storagePrice.Amount)
+	if !ok {
+		return std.ErrInsufficientFunds(fmt.Sprintf(
+			"storage fee overflow for %d bytes", growth))
+	}
+	fee := std.Coins{std.NewCoin(vm.storagePrice.Denom, amount)}
+	return vm.bank.SendCoins(ctx, payer, auth.FeeCollectorAddress(), fee)
+}
+
+// Applies the storage diffs of the gno store to the storage usage of each
+// package, and returns the total bytes of growth and of storage freed.
+func (vm *VMKeeper) updateStorageUsage(ctx sdk.Context, gnoStore gno.Store) (growth, freed int64) {
+	diffs := gnoStore.RealmStorageDiffs()
+	defer gnoStore.ResetRealmStorageDiffs()
+	// Iterate in deterministic order.
+	pkgIDs := make([]gno.PkgID, 0, len(diffs))
+	for pkgID := range diffs {
+		pkgIDs = append(pkgIDs, pkgID)
+	}
+	sort.Slice(pkgIDs, func(i, j int) bool {
+		return bytes.Compare(pkgIDs[i].Bytes(), pkgIDs[j].Bytes()) < 0
+	})
+	iavlStore := ctx.Store(vm.iavlKey)
+	for _, pkgID := range pkgIDs {
+		diff := diffs[pkgID]
+		if diff == 0 {
+			continue
+		}
+		usage := getStorageUsage(iavlStore, pkgID) + diff
+		if usage < 0 {
+			// e.g. objects stored before accounting.
+			usage = 0
+		}
+		setStorageUsage(iavlStore, pkgID, usage)
+		if diff > 0 {
+			growth += diff
+		} else {
+			freed -= diff
+		}
+	}
+	return growth, freed
+}
+
+// Refunds gas for freed bytes of storage, e.g. of deleted realm objects,
+// but at most half of the gas consumed, so that freeing storage can not
+// pay for the transaction entirely.
+func refundFreedStorage(ctx sdk.Context, freed int64) {
+	perByte := getParams(ctx).GasRefundPerFreedByte
+	if freed == 0 || perByte == 0 {
+		return
+	}
+	max := ctx.GasMeter().GasConsumed() / 2
+	refund, ok := overflow.Mul64(freed, perByte)
+	if !ok || refund > max {
+		refund = max
+	}
+	ctx.GasMeter().RefundGas(refund, "StorageRefund")
+}
+
+// QueryStorage returns the number of bytes stored by package at pkgPath.
+func (vm *VMKeeper) QueryStorage(ctx sdk.Context, pkgPath string) (usage int64, err error) {
+	store := vm.getGnoStore(ctx)
+	if pv := store.GetPackage(pkgPath, false); pv == nil {
+		err = ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+		return 0, err
+	}
+	iavlStore := ctx.Store(vm.iavlKey)
+	return getStorageUsage(iavlStore, gno.PkgIDFromPkgPath(pkgPath)), nil
+}
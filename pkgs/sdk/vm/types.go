@@ -1,6 +1,9 @@
 package vm
 
-import "github.com/gnolang/gno/pkgs/amino"
+import (
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+)
 
 // Public facing function signatures.
 // See convertArgToGno() for supported types.
@@ -22,3 +25,30 @@ func (fsigs FunctionSignatures) JSON() string {
 	bz := amino.MustMarshalJSON(fsigs)
 	return string(bz)
 }
+
+// A page of package paths, as returned by the vm/qpackages query.  Next
+// is the path to start the next page at, or empty if this is the last.
+type PackageList struct {
+	Paths []string
+	Next  string
+}
+
+func (pl PackageList) JSON() string {
+	bz := amino.MustMarshalJSON(pl)
+	return string(bz)
+}
+
+// Metadata of a package, as returned by the vm/qpkginfo query.
+type PackageInfo struct {
+	Path    string
+	Name    string
+	Creator crypto.Address // zero if added at genesis.
+	Height  int64          // zero if added at genesis.
+	Hash    string         // hex sha256 of the amino encoded package.
+	Files   []string
+}
+
+func (pi PackageInfo) JSON() string {
+	bz := amino.MustMarshalJSON(pi)
+	return string(bz)
+}
@@ -0,0 +1,93 @@
+package vm
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// The creator and versions of packages are kept in the iavl store, so that
+// they are part of consensus state.
+func packageCreatorKey(pkgPath string) []byte {
+	return []byte("pkgcreator:" + pkgPath)
+}
+
+func packageVersionsKey(pkgPath string) []byte {
+	return []byte("pkgversions:" + pkgPath)
+}
+
+// Returns the zero address for packages added before creators were
+// recorded, or added at genesis by other means.
+func getPackageCreator(iavlStore store.Store, pkgPath string) crypto.Address {
+	bz := iavlStore.Get(packageCreatorKey(pkgPath))
+	if bz == nil {
+		return crypto.Address{}
+	}
+	return crypto.AddressFromBytes(bz)
+}
+
+func setPackageCreator(iavlStore store.Store, pkgPath string, creator crypto.Address) {
+	iavlStore.Set(packageCreatorKey(pkgPath), creator.Bytes())
+}
+
+// Returns the upgraded versions of package at pkgPath, excluding pkgPath.
+func getPackageVersions(iavlStore store.Store, pkgPath string) (versions []string) {
+	bz := iavlStore.Get(packageVersionsKey(pkgPath))
+	if bz == nil {
+		return nil
+	}
+	amino.MustUnmarshal(bz, &versions)
+	return versions
+}
+
+func setPackageVersions(iavlStore store.Store, pkgPath string, versions []string) {
+	bz := amino.MustMarshal(versions)
+	iavlStore.Set(packageVersionsKey(pkgPath), bz)
+}
+
+// Returns the path of the original realm of the versioned realm path, and
+// its version: e.g. "gno.land/r/foo" and 2 for "gno.land/r/foo/v2", or
+// "gno.land/r/alice/foo" and 3 for "gno.land/r/alice/foo/v3"; or pkgPath and
+// 1 if it does not end with a version /vN, with N >= 2.
+func splitVersion(pkgPath string) (origPath string, version int) {
+	i := strings.LastIndex(pkgPath, "/v")
+	if i < 0 || !gno.IsRealmPath(pkgPath[:i]) || pkgPath[:i] == "gno.land/r" {
+		return pkgPath, 1
+	}
+	suffix := pkgPath[i+2:]
+	version, err := strconv.Atoi(suffix)
+	if err != nil || version < 2 || strconv.Itoa(version) != suffix {
+		return pkgPath, 1
+	}
+	return pkgPath[:i], version
+}
+
+// Returns true if the realm at pkgPath is a later version of the realm at
+// prevPath, e.g. "gno.land/r/foo/v2" of "gno.land/r/foo".
+func isLaterVersion(pkgPath string, prevPath string) bool {
+	origPath, version := splitVersion(pkgPath)
+	prevOrigPath, prevVersion := splitVersion(prevPath)
+	return origPath == prevOrigPath && version > prevVersion
+}
+
+// Returns true if package declares a func migrate() with no params or
+// results.
+func hasMigrateFunc(store gno.Store, pv *gno.PackageValue) bool {
+	pblock := pv.GetBlock(store)
+	for _, tv := range pblock.Values {
+		if tv.T == nil || tv.T.Kind() != gno.FuncKind {
+			continue
+		}
+		fv := tv.GetFunc()
+		if fv.IsMethod || fv.Name != "migrate" {
+			continue
+		}
+		ft := fv.Type.(*gno.FuncType)
+		return len(ft.Params) == 0 && len(ft.Results) == 0
+	}
+	return false
+}
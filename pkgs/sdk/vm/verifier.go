@@ -0,0 +1,249 @@
+package vm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gnolang/gno"
+	"github.com/gnolang/gno/pkgs/crypto"
+	"github.com/gnolang/gno/pkgs/sdk"
+	"github.com/gnolang/gno/pkgs/sdk/bank"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+	"github.com/gnolang/gno/stdlibs"
+)
+
+// An account may register a verification realm, whose VerifyTx function is
+// called by the ante handler for each tx signed for the account (see
+// VerifySigner).  The realm is kept in the iavl store, so that it is part of
+// consensus state.
+func verifierKey(addr crypto.Address) []byte {
+	return []byte("verifier:" + addr.String())
+}
+
+// Returns "" if the account at addr has no verification realm.
+func getVerifier(iavlStore store.Store, addr crypto.Address) string {
+	bz := iavlStore.Get(verifierKey(addr))
+	return string(bz)
+}
+
+func setVerifier(iavlStore store.Store, addr crypto.Address, pkgPath string) {
+	if pkgPath == "" {
+		iavlStore.Delete(verifierKey(addr))
+	} else {
+		iavlStore.Set(verifierKey(addr), []byte(pkgPath))
+	}
+}
+
+// Name of the function of verification realms.
+const verifyTxFunc = "VerifyTx"
+
+// Returns true if package declares a func VerifyTx(key std.Address) bool.
+func hasVerifyTxFunc(store gno.Store, pv *gno.PackageValue) bool {
+	pblock := pv.GetBlock(store)
+	for _, tv := range pblock.Values {
+		if tv.T == nil || tv.T.Kind() != gno.FuncKind {
+			continue
+		}
+		fv := tv.GetFunc()
+		if fv.IsMethod || fv.Name != verifyTxFunc {
+			continue
+		}
+		ft := fv.Type.(*gno.FuncType)
+		return len(ft.Params) == 1 && ft.Params[0].Type.Kind() == gno.StringKind &&
+			len(ft.Results) == 1 && ft.Results[0].Type.Kind() == gno.BoolKind
+	}
+	return false
+}
+
+// SetVerifier sets the verification realm of the account of the caller, or
+// unsets it if the package path of msg is empty.  Once set, the realm
+// verifies all the txs of the account, including those unsetting it (see
+// VerifySigner).
+func (vm *VMKeeper) SetVerifier(ctx sdk.Context, msg MsgSetVerifier) error {
+	iavlStore := ctx.Store(vm.iavlKey)
+	if msg.PkgPath == "" {
+		setVerifier(iavlStore, msg.Caller, "")
+		return nil
+	}
+	store := vm.getGnoStore(ctx)
+	pv := store.GetPackage(msg.PkgPath, false)
+	if pv == nil {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", msg.PkgPath))
+	}
+	if !hasVerifyTxFunc(store, pv) {
+		return ErrInvalidPkgPath(fmt.Sprintf(
+			"package %s does not declare func %s(key std.Address) bool",
+			msg.PkgPath, verifyTxFunc))
+	}
+	setVerifier(iavlStore, msg.Caller, msg.PkgPath)
+	return nil
+}
+
+// VerifySigner calls VerifyTx(key) of the verification realm of signer, if
+// any, for tx signed by key in place of signer, which may be signer itself.
+// The realm is called with signer as the original caller, and the coins
+// spent by signer in tx, i.e. the fees it pays and the coins it sends, as
+// the original send; the tx is rejected unless it returns true.  It may
+// consume at most the VerifyTxMaxGas of the vm params.
+//
+// A realm rejects a tx by returning false, and cannot be bypassed that way.
+// So that a faulty realm cannot lock the account out, a tx signed by the
+// own key of signer which only unsets its verification realm is accepted
+// if the realm cannot verify it, e.g. if VerifyTx panics or runs out of
+// gas.
+//
+// verified is false if signer has no verification realm.  It is the
+// auth.AnteOptions.VerifySigner of an application.
+func (vm *VMKeeper) VerifySigner(ctx sdk.Context, tx std.Tx, signer, key crypto.Address) (verified bool, err error) {
+	pkgPath := getVerifier(ctx.Store(vm.iavlKey), signer)
+	if pkgPath == "" {
+		return false, nil
+	}
+	ok, err := vm.callVerifyTx(ctx, tx, pkgPath, signer, key)
+	if err != nil {
+		if key == signer && isUnsetVerifierTx(tx, signer) {
+			return true, nil
+		}
+		return true, err
+	}
+	if !ok {
+		return true, std.ErrUnauthorized(fmt.Sprintf(
+			"tx of %s signed by %s rejected by verification realm %s",
+			signer, key, pkgPath))
+	}
+	return true, nil
+}
+
+// Calls VerifyTx(key) of the verification realm at pkgPath for tx of
+// signer, and returns its result, or an error if the realm cannot verify
+// tx.
+func (vm *VMKeeper) callVerifyTx(ctx sdk.Context, tx std.Tx, pkgPath string, signer, key crypto.Address) (ok bool, err error) {
+	gasMeter := store.NewPassthroughGasMeter(ctx.GasMeter(), getParams(ctx).VerifyTxMaxGas)
+	ctx = ctx.WithGasMeter(gasMeter)
+	store := vm.getGnoStore(ctx)
+	pv := store.GetPackage(pkgPath, false)
+	if pv == nil {
+		return false, ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+	}
+	// Make main Package with imports.
+	mpn := gno.NewPackageNode("main", "main", nil)
+	mpn.Define("pkg", gno.TypedValue{T: &gno.PackageType{}, V: pv})
+	mpv := mpn.NewPackage()
+	xn := gno.MustParseExpr(fmt.Sprintf(`pkg.%s(arg0)`, verifyTxFunc))
+	pl := gno.PackageNodeLocation(pkgPath)
+	pn := store.GetBlockNode(pl).(*gno.PackageNode)
+	ft := pn.GetStaticTypeOf(store, gno.Name(verifyTxFunc)).(*gno.FuncType)
+	xn.(*gno.CallExpr).Args[0] = &gno.ConstExpr{
+		TypedValue: convertArgToGno(key.String(), ft.Params[0].Type),
+	}
+	// Make context.
+	pkgAddr := gno.DerivePkgAddr(pkgPath)
+	msgCtx := stdlibs.ExecContext{
+		ChainID:       ctx.ChainID(),
+		Height:        ctx.BlockHeight(),
+		Timestamp:     ctx.BlockTime().Unix(),
+		BlockEntropy:  lastBlockHash(ctx),
+		OrigCaller:    signer.Bech32(),
+		OrigSend:      txSpent(tx, signer),
+		OrigSendSpent: new(std.Coins),
+		OrigPkgAddr:   pkgAddr.Bech32(),
+		Banker:        NewSDKBanker(vm, ctx),
+	}
+	params := getParams(ctx)
+	m := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:    "",
+			Output:     os.Stdout, // XXX
+			Store:      store,
+			Context:    msgCtx,
+			Alloc:      store.GetAllocator(),
+			MaxCycles:  10 * 1000 * 1000, // 10M cycles // XXX
+			MaxFrames:  int(params.MaxFrames),
+			MaxValues:  int(params.MaxValues),
+			MaxExprs:   int(params.MaxExprs),
+			RealmGuard: vm.newRealmGuard(ctx),
+			GasMeter:   ctx.GasMeter(),
+			GasConfig:  params.MachineGasConfig(),
+		})
+	m.SetActivePackage(mpv)
+	defer func() {
+		if r := recover(); r != nil {
+			if isOutOfGas(r) && !gasMeter.Head.IsOutOfGas() {
+				panic(r) // handled by baseapp.
+			}
+			if ex, ok := r.(realmGuardPanic); ok {
+				err = ex.err
+				return
+			}
+			err = std.ErrUnauthorized(fmt.Sprintf(
+				"verification realm %s panic: %v", pkgPath, r))
+			return
+		}
+	}()
+	rtvs := m.Eval(xn)
+	if !rtvs[0].GetBool() {
+		return false, nil
+	}
+	// Account for and charge storage.
+	if err := vm.processStorageDiffs(ctx, store, signer); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Returns true if all the msgs of tx unset the verification realm of
+// signer.
+func isUnsetVerifierTx(tx std.Tx, signer crypto.Address) bool {
+	for _, msg := range tx.GetMsgs() {
+		msg, ok := msg.(MsgSetVerifier)
+		if !ok || msg.Caller != signer || msg.PkgPath != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryVerifier returns the verification realm of the account at addr, or
+// "" if none.
+func (vm *VMKeeper) QueryVerifier(ctx sdk.Context, addr crypto.Address) string {
+	return getVerifier(ctx.Store(vm.iavlKey), addr)
+}
+
+// Returns the coins spent by signer in tx: the fees it pays, and the coins
+// it sends in its msgs.
+func txSpent(tx std.Tx, signer crypto.Address) std.Coins {
+	spent := std.Coins{}
+	if tx.GetSigners()[0] == signer && !tx.Fee.GasFee.IsZero() {
+		spent = spent.Add(std.Coins{tx.Fee.GasFee})
+	}
+	for _, msg := range tx.GetMsgs() {
+		switch msg := msg.(type) {
+		case bank.MsgSend:
+			if msg.FromAddress == signer {
+				spent = spent.Add(msg.Amount)
+			}
+		case bank.MsgMultiSend:
+			for _, in := range msg.Inputs {
+				if in.Address == signer {
+					spent = spent.Add(in.Coins)
+				}
+			}
+		case MsgAddPackage:
+			if msg.Creator == signer {
+				spent = spent.Add(msg.Deposit)
+			}
+		case MsgUpgradePackage:
+			if msg.Creator == signer {
+				spent = spent.Add(msg.Deposit)
+			}
+		case MsgCall:
+			if msg.Caller == signer {
+				spent = spent.Add(msg.Send)
+			}
+		}
+	}
+	return spent
+}
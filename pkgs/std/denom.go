@@ -0,0 +1,29 @@
+package std
+
+import (
+	"fmt"
+)
+
+// Coin amounts are integers; Decimals only affects how they are displayed.
+const MaxDenomDecimals = 18
+
+// DenomMetadata describes a coin denomination for display purposes.
+// It is set by the issuer of the denomination, and has no effect on
+// coin arithmetic.
+type DenomMetadata struct {
+	Denom    string `json:"denom"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int64  `json:"decimals"`
+}
+
+func (dm DenomMetadata) ValidateBasic() error {
+	if err := validateDenom(dm.Denom); err != nil {
+		return ErrInvalidCoins(err.Error())
+	}
+	if dm.Decimals < 0 || MaxDenomDecimals < dm.Decimals {
+		return ErrInvalidCoins(fmt.Sprintf(
+			"invalid decimals %d for denom %s", dm.Decimals, dm.Denom))
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package std
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gnolang/gno/pkgs/errors"
 )
 
@@ -31,8 +34,17 @@ type (
 	TooManySignaturesError struct{ abciError }
 	NoSignaturesError      struct{ abciError }
 	GasOverflowError       struct{ abciError }
+	DuplicateTxError       struct{ abciError }
 )
 
+// UnknownQueryPathError is the error of a query of a path without a route,
+// which lists the routes of the app.
+type UnknownQueryPathError struct {
+	abciError
+	Path   string
+	Routes []string
+}
+
 func (e InternalError) Error() string          { return "internal error" }
 func (e TxDecodeError) Error() string          { return "tx decode error" }
 func (e InvalidSequenceError) Error() string   { return "invalid sequence error" }
@@ -51,6 +63,12 @@ func (e InsufficientFeeError) Error() string   { return "insufficient fee error"
 func (e TooManySignaturesError) Error() string { return "too many signatures error" }
 func (e NoSignaturesError) Error() string      { return "no signatures error" }
 func (e GasOverflowError) Error() string       { return "gas overflow error" }
+func (e DuplicateTxError) Error() string       { return "duplicate tx error" }
+
+func (e UnknownQueryPathError) Error() string {
+	return fmt.Sprintf("unknown query path %q; available routes: %s",
+		e.Path, strings.Join(e.Routes, ", "))
+}
 
 // NOTE also update pkg/std/package.go registrations.
 
@@ -125,3 +143,11 @@ func ErrNoSignatures(msg string) error {
 func ErrGasOverflow(msg string) error {
 	return errors.Wrap(GasOverflowError{}, msg)
 }
+
+func ErrDuplicateTx(msg string) error {
+	return errors.Wrap(DuplicateTxError{}, msg)
+}
+
+func ErrUnknownQueryPath(path string, routes []string) error {
+	return errors.Wrap(UnknownQueryPathError{Path: path, Routes: routes}, "no route for query path %s", path)
+}
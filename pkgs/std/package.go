@@ -36,4 +36,6 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	TooManySignaturesError{}, "TooManySignaturesError",
 	NoSignaturesError{}, "NoSignaturesError",
 	GasOverflowError{}, "GasOverflowError",
+	DuplicateTxError{}, "DuplicateTxError",
+	UnknownQueryPathError{}, "UnknownQueryPathError",
 ))
@@ -6,6 +6,7 @@ import (
 	"github.com/gnolang/gno/pkgs/amino"
 	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/crypto/multisig"
+	"github.com/gnolang/gno/pkgs/crypto/tmhash"
 )
 
 var maxGasWanted = int64((1 << 60) - 1) // something smaller than math.MaxInt64
@@ -103,6 +104,17 @@ func (tx Tx) GetSignBytes(chainID string, accountNumber uint64, sequence uint64)
 	return SignBytes(chainID, accountNumber, sequence, tx.Fee, tx.Msgs, tx.Memo)
 }
 
+// Hash returns the canonical hash of the tx on chainID, that of its sign
+// bytes with zero account number and sequence.  Unlike the hash of its
+// encoding, it does not change with its signatures, which may be malleated,
+// nor with the encoding itself; and as it does not depend on the state of its
+// signers, a tx keeps its hash once signed, whatever sequence it was signed
+// at.  So the same msgs, fee and memo signed at other sequences have the same
+// hash: a memo tells them apart.
+func (tx Tx) Hash(chainID string) []byte {
+	return tmhash.Sum(tx.GetSignBytes(chainID, 0, 0))
+}
+
 //__________________________________________________________
 
 // Fee includes the amount of coins paid in fees and the maximum
@@ -15,6 +15,7 @@ type (
 	CommitMultiStore       = types.CommitMultiStore
 	CommitStoreConstructor = types.CommitStoreConstructor
 	KVPair                 = types.KVPair
+	KVDiff                 = types.KVDiff
 	Iterator               = types.Iterator
 	CommitID               = types.CommitID
 	StoreKey               = types.StoreKey
@@ -25,6 +26,8 @@ type (
 	GasConfig              = types.GasConfig
 	OutOfGasException      = types.OutOfGasException
 	GasOverflowException   = types.GasOverflowException
+	AuditGasMeter          = types.AuditGasMeter
+	GasAuditEntry          = types.GasAuditEntry
 )
 
 // nolint - reexport
@@ -32,11 +35,15 @@ var (
 	PruneNothing           = types.PruneNothing
 	PruneEverything        = types.PruneEverything
 	PruneSyncable          = types.PruneSyncable
+	NewPruningOptions      = types.NewPruningOptions
 	NewGasMeter            = types.NewGasMeter
 	NewInfiniteGasMeter    = types.NewInfiniteGasMeter
 	NewPassthroughGasMeter = types.NewPassthroughGasMeter
+	NewAuditGasMeter       = types.NewAuditGasMeter
 	DefaultGasConfig       = types.DefaultGasConfig
 	PrefixIterator         = types.PrefixIterator
+	PrefixEndBytes         = types.PrefixEndBytes
+	DiffStoreKVs           = types.DiffStoreKVs
 	ReversePrefixIterator  = types.ReversePrefixIterator
 	NewStoreKey            = types.NewStoreKey
 )
@@ -0,0 +1,507 @@
+// Package multiversion implements a block-level store in the style of
+// Block-STM, which keeps the values written by each tx of a block as
+// distinct versions, so that the txs may be executed optimistically, e.g. in
+// parallel, and validated afterwards.
+//
+// Each tx of the block executes on a TxStore, which reads the values
+// written by the txs before it in the block, or else those of the parent
+// store, and records its read set and its write set.  Once a tx is
+// executed, its TxStore is written to the Store, which publishes its write
+// set to the txs after it.  A tx is valid if the values it read are still
+// those of the txs before it; otherwise it must be executed again, as a new
+// incarnation.  Once all the txs are valid, their write sets are written to
+// the parent in the order of the block.
+//
+// The read and write sets of the txs are exported, e.g. for tracing.
+package multiversion
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store/cache"
+	"github.com/gnolang/gno/pkgs/store/types"
+)
+
+// Version is the version of a value written by a tx: the index of the tx in
+// the block, and its incarnation, i.e. the number of times it was executed
+// before.
+type Version struct {
+	TxIndex     int
+	Incarnation int
+}
+
+// ParentVersion is the version of the values of the parent store.
+var ParentVersion = Version{TxIndex: -1}
+
+func (v Version) String() string {
+	if v == ParentVersion {
+		return "parent"
+	}
+	return fmt.Sprintf("%d/%d", v.TxIndex, v.Incarnation)
+}
+
+// ReadEntry is the read of a key by a tx, and the version of the value
+// read, which may be nil.
+type ReadEntry struct {
+	Key     []byte
+	Version Version
+}
+
+// IterateEntry is an iteration by a tx over a domain of keys, and the keys
+// found, not counting the writes of the tx itself.
+type IterateEntry struct {
+	Start []byte
+	End   []byte
+	Keys  [][]byte
+}
+
+// ReadSet is what a tx read: its reads, sorted by key, and its iterations,
+// in order.  Keys written by the tx before being read are not in its reads.
+type ReadSet struct {
+	Reads    []ReadEntry
+	Iterates []IterateEntry
+}
+
+// WriteEntry is the write of a key by a tx.  Value is nil if the key is
+// deleted.
+type WriteEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// WriteSet is what a tx wrote, sorted by key.
+type WriteSet []WriteEntry
+
+//----------------------------------------
+// Store
+
+// A value written by a tx, nil if deleted.
+type mvValue struct {
+	incarnation int
+	value       []byte
+}
+
+// The last execution of a tx written to the store.
+type txRecord struct {
+	version Version
+	reads   ReadSet
+	writes  WriteSet
+}
+
+// Store is the multiversion store of a block, over a parent store which is
+// not written to until Write().  It is safe for concurrent use by the
+// TxStores of different txs, if the parent is safe for concurrent reads.
+type Store struct {
+	mtx    sync.RWMutex
+	parent types.Store
+	data   map[string]map[int]mvValue // key -> tx index -> value.
+	txs    map[int]*txRecord
+}
+
+// nolint
+func New(parent types.Store) *Store {
+	return &Store{
+		parent: parent,
+		data:   make(map[string]map[int]mvValue),
+		txs:    make(map[int]*txRecord),
+	}
+}
+
+// TxStore returns a new store for the execution of the tx at txIndex in the
+// block, its incarnation-th execution.
+func (mv *Store) TxStore(txIndex, incarnation int) *TxStore {
+	if txIndex < 0 || incarnation < 0 {
+		panic(fmt.Sprintf("invalid tx version %d/%d", txIndex, incarnation))
+	}
+	return &TxStore{
+		mv:      mv,
+		version: Version{TxIndex: txIndex, Incarnation: incarnation},
+		reads:   make(map[string]Version),
+		writes:  make(map[string][]byte),
+	}
+}
+
+// Returns the value of key for the tx at txIndex, i.e. that written by the
+// last tx before it, or else that of the parent, and its version.
+// CONTRACT: mv.mtx is locked.
+func (mv *Store) read(key []byte, txIndex int) ([]byte, Version) {
+	last := -1
+	for idx := range mv.data[string(key)] {
+		if idx < txIndex && idx > last {
+			last = idx
+		}
+	}
+	if last < 0 {
+		return mv.parent.Get(key), ParentVersion
+	}
+	mvv := mv.data[string(key)][last]
+	return mvv.value, Version{TxIndex: last, Incarnation: mvv.incarnation}
+}
+
+// Returns the sorted keys in the domain with a value for the tx at txIndex,
+// not counting its own writes.
+// CONTRACT: mv.mtx is locked.
+func (mv *Store) keys(start, end []byte, txIndex int) [][]byte {
+	found := make(map[string]struct{})
+	itr := mv.parent.Iterator(start, end)
+	for ; itr.Valid(); itr.Next() {
+		found[string(itr.Key())] = struct{}{}
+	}
+	itr.Close()
+	for key := range mv.data {
+		if dbm.IsKeyInDomain([]byte(key), start, end) {
+			found[key] = struct{}{}
+		}
+	}
+	keys := make([][]byte, 0, len(found))
+	for key := range found {
+		if value, _ := mv.read([]byte(key), txIndex); value != nil {
+			keys = append(keys, []byte(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	return keys
+}
+
+// Records the execution of a tx, replacing its previous one if any.
+func (mv *Store) record(ts *TxStore) {
+	mv.mtx.Lock()
+	defer mv.mtx.Unlock()
+
+	txIndex := ts.version.TxIndex
+	if prev, ok := mv.txs[txIndex]; ok {
+		if prev.version.Incarnation > ts.version.Incarnation {
+			panic(fmt.Sprintf("tx %d already written at incarnation %d",
+				txIndex, prev.version.Incarnation))
+		}
+		for _, w := range prev.writes {
+			delete(mv.data[string(w.Key)], txIndex)
+			if len(mv.data[string(w.Key)]) == 0 {
+				delete(mv.data, string(w.Key))
+			}
+		}
+	}
+	writes := ts.WriteSet()
+	for _, w := range writes {
+		values, ok := mv.data[string(w.Key)]
+		if !ok {
+			values = make(map[int]mvValue)
+			mv.data[string(w.Key)] = values
+		}
+		values[txIndex] = mvValue{
+			incarnation: ts.version.Incarnation,
+			value:       w.Value,
+		}
+	}
+	mv.txs[txIndex] = &txRecord{
+		version: ts.version,
+		reads:   ts.ReadSet(),
+		writes:  writes,
+	}
+}
+
+// Validate returns whether the values read by the last execution of the tx
+// at txIndex are still those of the txs before it.  It returns false if the
+// tx was not executed.
+func (mv *Store) Validate(txIndex int) bool {
+	mv.mtx.RLock()
+	defer mv.mtx.RUnlock()
+
+	rec, ok := mv.txs[txIndex]
+	if !ok {
+		return false
+	}
+	for _, r := range rec.reads.Reads {
+		if _, version := mv.read(r.Key, txIndex); version != r.Version {
+			return false
+		}
+	}
+	for _, it := range rec.reads.Iterates {
+		keys := mv.keys(it.Start, it.End, txIndex)
+		if len(keys) != len(it.Keys) {
+			return false
+		}
+		for i, key := range keys {
+			if !bytes.Equal(key, it.Keys[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Version returns the version of the last execution of the tx at txIndex.
+func (mv *Store) Version(txIndex int) (Version, bool) {
+	mv.mtx.RLock()
+	defer mv.mtx.RUnlock()
+
+	rec, ok := mv.txs[txIndex]
+	if !ok {
+		return Version{}, false
+	}
+	return rec.version, true
+}
+
+// ReadSet returns the read set of the last execution of the tx at txIndex.
+func (mv *Store) ReadSet(txIndex int) (ReadSet, bool) {
+	mv.mtx.RLock()
+	defer mv.mtx.RUnlock()
+
+	rec, ok := mv.txs[txIndex]
+	if !ok {
+		return ReadSet{}, false
+	}
+	return rec.reads, true
+}
+
+// WriteSet returns the write set of the last execution of the tx at
+// txIndex.
+func (mv *Store) WriteSet(txIndex int) (WriteSet, bool) {
+	mv.mtx.RLock()
+	defer mv.mtx.RUnlock()
+
+	rec, ok := mv.txs[txIndex]
+	if !ok {
+		return nil, false
+	}
+	return rec.writes, true
+}
+
+// Write writes the write sets of the txs to the parent, in the order of
+// the block, and clears the store.  All the txs should be valid.
+func (mv *Store) Write() {
+	mv.mtx.Lock()
+	defer mv.mtx.Unlock()
+
+	indices := make([]int, 0, len(mv.txs))
+	for idx := range mv.txs {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+	for _, idx := range indices {
+		for _, w := range mv.txs[idx].writes {
+			if w.Value == nil {
+				mv.parent.Delete(w.Key)
+			} else {
+				mv.parent.Set(w.Key, w.Value)
+			}
+		}
+	}
+	mv.data = make(map[string]map[int]mvValue)
+	mv.txs = make(map[int]*txRecord)
+}
+
+//----------------------------------------
+// TxStore
+
+var _ types.Store = (*TxStore)(nil)
+
+// TxStore is the store of an execution of a tx, which records its reads and
+// buffers its writes until Write().  It is not safe for concurrent use.
+type TxStore struct {
+	mv       *Store
+	version  Version
+	reads    map[string]Version
+	iterates []IterateEntry
+	writes   map[string][]byte // nil if deleted.
+}
+
+// Version returns the version of the values written by the tx.
+func (ts *TxStore) Version() Version {
+	return ts.version
+}
+
+// Implements types.Store.
+func (ts *TxStore) Get(key []byte) []byte {
+	types.AssertValidKey(key)
+	if value, ok := ts.writes[string(key)]; ok {
+		return value
+	}
+	ts.mv.mtx.RLock()
+	value, version := ts.mv.read(key, ts.version.TxIndex)
+	ts.mv.mtx.RUnlock()
+	if _, ok := ts.reads[string(key)]; !ok {
+		ts.reads[string(key)] = version
+	}
+	return value
+}
+
+// Implements types.Store.
+func (ts *TxStore) Has(key []byte) bool {
+	return ts.Get(key) != nil
+}
+
+// Implements types.Store.
+func (ts *TxStore) Set(key, value []byte) {
+	types.AssertValidKey(key)
+	types.AssertValidValue(value)
+	ts.writes[string(key)] = value
+}
+
+// Implements types.Store.
+func (ts *TxStore) Delete(key []byte) {
+	types.AssertValidKey(key)
+	ts.writes[string(key)] = nil
+}
+
+// Implements types.Store.
+func (ts *TxStore) Iterator(start, end []byte) types.Iterator {
+	return ts.iterator(start, end, true)
+}
+
+// Implements types.Store.
+func (ts *TxStore) ReverseIterator(start, end []byte) types.Iterator {
+	return ts.iterator(start, end, false)
+}
+
+// The iterator is over a copy of the items of the domain, so that the
+// store may be written to while iterating.
+func (ts *TxStore) iterator(start, end []byte, ascending bool) types.Iterator {
+	ts.mv.mtx.RLock()
+	keys := ts.mv.keys(start, end, ts.version.TxIndex)
+	ts.mv.mtx.RUnlock()
+	ts.iterates = append(ts.iterates, IterateEntry{
+		Start: cp(start),
+		End:   cp(end),
+		Keys:  keys,
+	})
+
+	found := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		found[string(key)] = struct{}{}
+	}
+	for key := range ts.writes {
+		if dbm.IsKeyInDomain([]byte(key), start, end) {
+			found[key] = struct{}{}
+		}
+	}
+	items := make([]std.KVPair, 0, len(found))
+	for key := range found {
+		if value := ts.Get([]byte(key)); value != nil {
+			items = append(items, std.KVPair{Key: []byte(key), Value: value})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return bytes.Compare(items[i].Key, items[j].Key) < 0
+	})
+	return newItemsIterator(start, end, items, ascending)
+}
+
+// Implements types.Store.
+func (ts *TxStore) CacheWrap() types.Store {
+	return cache.New(ts)
+}
+
+// Write writes the execution of the tx to the multiversion store, which
+// publishes its writes to the txs after it.  It replaces the previous
+// execution of the tx, whose incarnation must be lower.
+func (ts *TxStore) Write() {
+	ts.mv.record(ts)
+}
+
+// ReadSet returns the read set of the tx so far.
+func (ts *TxStore) ReadSet() ReadSet {
+	reads := make([]ReadEntry, 0, len(ts.reads))
+	for key, version := range ts.reads {
+		reads = append(reads, ReadEntry{Key: []byte(key), Version: version})
+	}
+	sort.Slice(reads, func(i, j int) bool {
+		return bytes.Compare(reads[i].Key, reads[j].Key) < 0
+	})
+	iterates := make([]IterateEntry, len(ts.iterates))
+	copy(iterates, ts.iterates)
+	return ReadSet{Reads: reads, Iterates: iterates}
+}
+
+// WriteSet returns the write set of the tx so far.
+func (ts *TxStore) WriteSet() WriteSet {
+	writes := make(WriteSet, 0, len(ts.writes))
+	for key, value := range ts.writes {
+		writes = append(writes, WriteEntry{Key: []byte(key), Value: value})
+	}
+	sort.Slice(writes, func(i, j int) bool {
+		return bytes.Compare(writes[i].Key, writes[j].Key) < 0
+	})
+	return writes
+}
+
+//----------------------------------------
+// etc
+
+func cp(bz []byte) []byte {
+	if bz == nil {
+		return nil
+	}
+	ret := make([]byte, len(bz))
+	copy(ret, bz)
+	return ret
+}
+
+// Iterates over sorted items.
+// Implements Iterator.
+type itemsIterator struct {
+	start, end []byte
+	items      []std.KVPair
+	ascending  bool
+}
+
+func newItemsIterator(start, end []byte, items []std.KVPair, ascending bool) *itemsIterator {
+	return &itemsIterator{
+		start:     start,
+		end:       end,
+		items:     items,
+		ascending: ascending,
+	}
+}
+
+func (ii *itemsIterator) Domain() ([]byte, []byte) {
+	return ii.start, ii.end
+}
+
+func (ii *itemsIterator) Valid() bool {
+	return len(ii.items) > 0
+}
+
+func (ii *itemsIterator) assertValid() {
+	if !ii.Valid() {
+		panic("itemsIterator is invalid")
+	}
+}
+
+func (ii *itemsIterator) Next() {
+	ii.assertValid()
+	if ii.ascending {
+		ii.items = ii.items[1:]
+	} else {
+		ii.items = ii.items[:len(ii.items)-1]
+	}
+}
+
+func (ii *itemsIterator) item() std.KVPair {
+	ii.assertValid()
+	if ii.ascending {
+		return ii.items[0]
+	}
+	return ii.items[len(ii.items)-1]
+}
+
+func (ii *itemsIterator) Key() []byte {
+	return ii.item().Key
+}
+
+func (ii *itemsIterator) Value() []byte {
+	return ii.item().Value
+}
+
+func (ii *itemsIterator) Close() {
+	ii.start = nil
+	ii.end = nil
+	ii.items = nil
+}
@@ -0,0 +1,193 @@
+package multiversion_test
+
+import (
+	"fmt"
+	"testing"
+
+	dbm "github.com/gnolang/gno/pkgs/db"
+
+	"github.com/gnolang/gno/pkgs/store/dbadapter"
+	"github.com/gnolang/gno/pkgs/store/multiversion"
+	"github.com/gnolang/gno/pkgs/store/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bz(s string) []byte { return []byte(s) }
+
+func keyFmt(i int) []byte { return bz(fmt.Sprintf("key%0.8d", i)) }
+func valFmt(i int) []byte { return bz(fmt.Sprintf("value%0.8d", i)) }
+
+func newParent() types.Store {
+	mem := dbadapter.Store{dbm.NewMemDB()}
+	mem.Set(keyFmt(1), valFmt(1))
+	mem.Set(keyFmt(2), valFmt(2))
+	return mem
+}
+
+func TestTxStoreReadWrite(t *testing.T) {
+	mv := multiversion.New(newParent())
+
+	tx0 := mv.TxStore(0, 0)
+	require.Equal(t, valFmt(1), tx0.Get(keyFmt(1)))
+	tx0.Set(keyFmt(1), valFmt(10))
+	tx0.Delete(keyFmt(2))
+	require.Equal(t, valFmt(10), tx0.Get(keyFmt(1)))
+	require.False(t, tx0.Has(keyFmt(2)))
+	tx0.Write()
+
+	// tx 1 reads the writes of tx 0, and its own.
+	tx1 := mv.TxStore(1, 0)
+	require.Equal(t, valFmt(10), tx1.Get(keyFmt(1)))
+	require.Nil(t, tx1.Get(keyFmt(2)))
+	require.Nil(t, tx1.Get(keyFmt(3)))
+	tx1.Set(keyFmt(3), valFmt(3))
+	require.Equal(t, valFmt(3), tx1.Get(keyFmt(3)))
+	tx1.Write()
+
+	// a tx does not read the writes of the txs after it.
+	tx0b := mv.TxStore(0, 1)
+	require.Nil(t, tx0b.Get(keyFmt(3)))
+
+	v0 := multiversion.Version{TxIndex: 0, Incarnation: 0}
+	rs, ok := mv.ReadSet(1)
+	require.True(t, ok)
+	require.Equal(t, []multiversion.ReadEntry{
+		{Key: keyFmt(1), Version: v0},
+		{Key: keyFmt(2), Version: v0},
+		{Key: keyFmt(3), Version: multiversion.ParentVersion},
+	}, rs.Reads)
+	ws, ok := mv.WriteSet(0)
+	require.True(t, ok)
+	require.Equal(t, multiversion.WriteSet{
+		{Key: keyFmt(1), Value: valFmt(10)},
+		{Key: keyFmt(2), Value: nil},
+	}, ws)
+	_, ok = mv.WriteSet(2)
+	require.False(t, ok)
+}
+
+func TestValidate(t *testing.T) {
+	mv := multiversion.New(newParent())
+
+	// tx 1 executes before tx 0 writes.
+	tx1 := mv.TxStore(1, 0)
+	require.Equal(t, valFmt(1), tx1.Get(keyFmt(1)))
+	tx1.Set(keyFmt(2), valFmt(20))
+	tx1.Write()
+	require.True(t, mv.Validate(1))
+	require.False(t, mv.Validate(0))
+
+	// a write of tx 0 to a key not read by tx 1 keeps it valid.
+	tx0 := mv.TxStore(0, 0)
+	tx0.Set(keyFmt(2), valFmt(200))
+	tx0.Write()
+	require.True(t, mv.Validate(0))
+	require.True(t, mv.Validate(1))
+
+	// a write of tx 0 to a key read by tx 1 invalidates it.
+	tx0 = mv.TxStore(0, 1)
+	tx0.Set(keyFmt(1), valFmt(100))
+	tx0.Write()
+	require.False(t, mv.Validate(1))
+
+	// so tx 1 is executed again.
+	tx1 = mv.TxStore(1, 1)
+	require.Equal(t, valFmt(100), tx1.Get(keyFmt(1)))
+	tx1.Set(keyFmt(2), valFmt(20))
+	tx1.Write()
+	require.True(t, mv.Validate(1))
+	version, ok := mv.Version(1)
+	require.True(t, ok)
+	require.Equal(t, multiversion.Version{TxIndex: 1, Incarnation: 1}, version)
+
+	// a tx cannot be written at a lower incarnation.
+	require.Panics(t, func() { mv.TxStore(1, 0).Write() })
+
+	// a new incarnation of tx 0 without its previous writes invalidates
+	// tx 1 too.
+	tx0 = mv.TxStore(0, 2)
+	tx0.Write()
+	require.False(t, mv.Validate(1))
+}
+
+func TestIterator(t *testing.T) {
+	mv := multiversion.New(newParent())
+
+	tx0 := mv.TxStore(0, 0)
+	tx0.Set(keyFmt(3), valFmt(3))
+	tx0.Delete(keyFmt(1))
+	tx0.Write()
+
+	tx2 := mv.TxStore(2, 0)
+	tx2.Set(keyFmt(4), valFmt(4))
+	itr := tx2.Iterator(nil, nil)
+	for i := 2; i <= 4; i++ {
+		require.True(t, itr.Valid())
+		require.Equal(t, keyFmt(i), itr.Key())
+		require.Equal(t, valFmt(i), itr.Value())
+		itr.Next()
+	}
+	require.False(t, itr.Valid())
+	require.Panics(t, itr.Next)
+	itr.Close()
+
+	itr = tx2.ReverseIterator(keyFmt(2), keyFmt(4))
+	require.Equal(t, keyFmt(3), itr.Key())
+	itr.Next()
+	require.Equal(t, keyFmt(2), itr.Key())
+	itr.Next()
+	require.False(t, itr.Valid())
+	itr.Close()
+	tx2.Write()
+
+	rs, ok := mv.ReadSet(2)
+	require.True(t, ok)
+	require.Equal(t, []multiversion.IterateEntry{
+		{Start: nil, End: nil, Keys: [][]byte{keyFmt(2), keyFmt(3)}},
+		{Start: keyFmt(2), End: keyFmt(4), Keys: [][]byte{keyFmt(2), keyFmt(3)}},
+	}, rs.Iterates)
+	require.True(t, mv.Validate(2))
+
+	// a key written by tx 1 in the domain iterated over by tx 2
+	// invalidates it, even though tx 2 did not read it.
+	tx1 := mv.TxStore(1, 0)
+	tx1.Set(keyFmt(5), valFmt(5))
+	tx1.Write()
+	require.False(t, mv.Validate(2))
+}
+
+func TestWrite(t *testing.T) {
+	parent := newParent()
+	mv := multiversion.New(parent)
+
+	tx1 := mv.TxStore(1, 0)
+	tx1.Set(keyFmt(1), valFmt(11))
+	tx1.Set(keyFmt(3), valFmt(3))
+	tx1.Write()
+	tx0 := mv.TxStore(0, 0)
+	tx0.Set(keyFmt(1), valFmt(10))
+	tx0.Delete(keyFmt(2))
+	tx0.Write()
+
+	// the parent is not written to before Write().
+	require.Equal(t, valFmt(1), parent.Get(keyFmt(1)))
+	require.True(t, mv.Validate(0))
+	require.True(t, mv.Validate(1))
+
+	// the writes of the last tx win.
+	mv.Write()
+	require.Equal(t, valFmt(11), parent.Get(keyFmt(1)))
+	require.Nil(t, parent.Get(keyFmt(2)))
+	require.Equal(t, valFmt(3), parent.Get(keyFmt(3)))
+	_, ok := mv.WriteSet(0)
+	require.False(t, ok)
+
+	// a cache-wrapped tx store writes through to it.
+	tx0 = mv.TxStore(0, 0)
+	cache := tx0.CacheWrap()
+	cache.Set(keyFmt(4), valFmt(4))
+	require.Nil(t, tx0.Get(keyFmt(4)))
+	cache.Write()
+	require.Equal(t, valFmt(4), tx0.Get(keyFmt(4)))
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"math"
+	"sort"
 
 	"github.com/gnolang/overflow"
 )
@@ -39,6 +40,7 @@ type GasMeter interface {
 	Limit() Gas
 	Remaining() Gas
 	ConsumeGas(amount Gas, descriptor string)
+	RefundGas(amount Gas, descriptor string)
 	IsPastLimit() bool
 	IsOutOfGas() bool
 }
@@ -98,6 +100,17 @@ func (g *basicGasMeter) ConsumeGas(amount Gas, descriptor string) {
 	}
 }
 
+// RefundGas deducts amount from the gas consumed, down to zero.
+func (g *basicGasMeter) RefundGas(amount Gas, descriptor string) {
+	if amount < 0 {
+		panic("gas must not be negative")
+	}
+	if amount > g.consumed {
+		amount = g.consumed
+	}
+	g.consumed -= amount
+}
+
 func (g *basicGasMeter) IsPastLimit() bool {
 	return g.consumed > g.limit
 }
@@ -144,6 +157,16 @@ func (g *infiniteGasMeter) ConsumeGas(amount Gas, descriptor string) {
 	g.consumed = consumed
 }
 
+func (g *infiniteGasMeter) RefundGas(amount Gas, descriptor string) {
+	if amount < 0 {
+		panic("gas must not be negative")
+	}
+	if amount > g.consumed {
+		amount = g.consumed
+	}
+	g.consumed -= amount
+}
+
 func (g *infiniteGasMeter) IsPastLimit() bool {
 	return false
 }
@@ -198,6 +221,11 @@ func (g passthroughGasMeter) ConsumeGas(amount Gas, descriptor string) {
 	g.Head.ConsumeGas(amount, descriptor)
 }
 
+func (g passthroughGasMeter) RefundGas(amount Gas, descriptor string) {
+	g.Base.RefundGas(amount, descriptor)
+	g.Head.RefundGas(amount, descriptor)
+}
+
 func (g passthroughGasMeter) IsPastLimit() bool {
 	return g.Head.IsPastLimit()
 }
@@ -231,3 +259,68 @@ func DefaultGasConfig() GasConfig {
 		IterNextCostFlat: 30,
 	}
 }
+
+//----------------------------------------
+// AuditGasMeter
+
+// GasAuditEntry is the total gas consumed for a given descriptor,
+// along with the number of times gas was consumed for it.
+type GasAuditEntry struct {
+	Descriptor string
+	Count      int64
+	Gas        Gas
+}
+
+// AuditGasMeter wraps a GasMeter and records gas consumption per
+// descriptor.  It is meant for simulation and debugging, as it is
+// slower than the meter it wraps.
+type AuditGasMeter struct {
+	GasMeter
+	entries map[string]*GasAuditEntry
+}
+
+// NewAuditGasMeter returns an AuditGasMeter wrapping base.
+func NewAuditGasMeter(base GasMeter) *AuditGasMeter {
+	return &AuditGasMeter{
+		GasMeter: base,
+		entries:  make(map[string]*GasAuditEntry),
+	}
+}
+
+// ConsumeGas records the consumption before passing it through, so
+// that the consumption that caused an out of gas panic is recorded too.
+func (g *AuditGasMeter) ConsumeGas(amount Gas, descriptor string) {
+	g.Record(descriptor, 1, amount)
+	g.GasMeter.ConsumeGas(amount, descriptor)
+}
+
+// RefundGas records the refund as negative gas before passing it through.
+func (g *AuditGasMeter) RefundGas(amount Gas, descriptor string) {
+	g.Record(descriptor, 1, -amount)
+	g.GasMeter.RefundGas(amount, descriptor)
+}
+
+// Record records count occurrences of descriptor with total gas amount
+// without consuming any gas.  It can be used to record information that
+// is not (yet) metered, such as VM cycle counts.
+func (g *AuditGasMeter) Record(descriptor string, count int64, amount Gas) {
+	entry, ok := g.entries[descriptor]
+	if !ok {
+		entry = &GasAuditEntry{Descriptor: descriptor}
+		g.entries[descriptor] = entry
+	}
+	entry.Count += count
+	entry.Gas += amount
+}
+
+// Entries returns all recorded entries sorted by descriptor.
+func (g *AuditGasMeter) Entries() []GasAuditEntry {
+	entries := make([]GasAuditEntry, 0, len(g.entries))
+	for _, entry := range g.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Descriptor < entries[j].Descriptor
+	})
+	return entries
+}
@@ -46,6 +46,19 @@ func TestGasMeter(t *testing.T) {
 	}
 }
 
+func TestGasMeterRefundGas(t *testing.T) {
+	meter := NewGasMeter(100)
+	meter.ConsumeGas(60, "")
+	meter.RefundGas(20, "")
+	require.Equal(t, Gas(40), meter.GasConsumed())
+	require.NotPanics(t, func() { meter.ConsumeGas(60, "") })
+
+	// refunds do not go below zero.
+	meter.RefundGas(200, "")
+	require.Equal(t, Gas(0), meter.GasConsumed())
+	require.Panics(t, func() { meter.RefundGas(-1, "") })
+}
+
 func TestAddUint64Overflow(t *testing.T) {
 	testCases := []struct {
 		a, b     int64
@@ -71,3 +84,26 @@ func TestAddUint64Overflow(t *testing.T) {
 		)
 	}
 }
+
+func TestAuditGasMeter(t *testing.T) {
+	meter := NewAuditGasMeter(NewGasMeter(100))
+	meter.ConsumeGas(10, "b")
+	meter.ConsumeGas(20, "a")
+	meter.ConsumeGas(5, "b")
+	meter.Record("c", 3, 0)
+	require.Equal(t, Gas(35), meter.GasConsumed())
+	require.Equal(t, []GasAuditEntry{
+		{Descriptor: "a", Count: 1, Gas: 20},
+		{Descriptor: "b", Count: 2, Gas: 15},
+		{Descriptor: "c", Count: 3, Gas: 0},
+	}, meter.Entries())
+
+	// refunds are recorded as negative gas.
+	meter.RefundGas(5, "r")
+	require.Equal(t, Gas(30), meter.GasConsumed())
+	require.Equal(t, GasAuditEntry{Descriptor: "r", Count: 1, Gas: -5}, meter.Entries()[3])
+
+	// consumption past the limit is recorded too.
+	require.Panics(t, func() { meter.ConsumeGas(100, "d") })
+	require.Equal(t, Gas(100), meter.Entries()[3].Gas)
+}
@@ -29,6 +29,18 @@ func NewPruningOptions(keepRecent, keepEvery int64) PruningOptions {
 	}
 }
 
+// IsKept returns whether the state at version is kept, i.e. not pruned, once
+// latest is committed.
+func (po PruningOptions) IsKept(version, latest int64) bool {
+	if version > latest || version < 1 {
+		return false
+	}
+	if version >= latest-po.KeepRecent {
+		return true
+	}
+	return po.KeepEvery != 0 && version%po.KeepEvery == 0
+}
+
 // default pruning strategies
 var (
 	// PruneEverything means all saved states will be deleted, storing only the current state
@@ -0,0 +1,22 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/store/types"
+)
+
+func TestPruningOptionsIsKept(t *testing.T) {
+	require.True(t, types.PruneEverything.IsKept(10, 10))
+	require.False(t, types.PruneEverything.IsKept(9, 10))
+	require.True(t, types.PruneNothing.IsKept(1, 10))
+	require.False(t, types.PruneNothing.IsKept(11, 10))
+	require.False(t, types.PruneNothing.IsKept(0, 10))
+
+	po := types.NewPruningOptions(2, 5)
+	require.True(t, po.IsKept(8, 10))
+	require.False(t, po.IsKept(7, 10))
+	require.True(t, po.IsKept(5, 10))
+}
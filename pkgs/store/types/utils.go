@@ -52,6 +52,58 @@ func DiffStores(a Store, b Store, prefixesToSkip [][]byte) (kvA KVPair, kvB KVPa
 	return KVPair{}, KVPair{}, count, true
 }
 
+// KVDiff is a difference between two stores at Key, where a nil value
+// means that the key is absent from that store.
+type KVDiff struct {
+	Key    []byte
+	ValueA []byte
+	ValueB []byte
+}
+
+// DiffStoreKVs returns the keys at which stores a and b differ, in
+// ascending order, with their values.  If limit > 0, at most limit
+// differences are returned.
+func DiffStoreKVs(a Store, b Store, limit int) []KVDiff {
+	iterA := a.Iterator(nil, nil)
+	defer iterA.Close()
+	iterB := b.Iterator(nil, nil)
+	defer iterB.Close()
+	diffs := []KVDiff{}
+	for iterA.Valid() || iterB.Valid() {
+		if limit > 0 && len(diffs) >= limit {
+			break
+		}
+		var cmp int
+		switch {
+		case !iterA.Valid():
+			cmp = 1
+		case !iterB.Valid():
+			cmp = -1
+		default:
+			cmp = bytes.Compare(iterA.Key(), iterB.Key())
+		}
+		switch {
+		case cmp < 0:
+			diffs = append(diffs, KVDiff{Key: Cp(iterA.Key()), ValueA: Cp(iterA.Value())})
+			iterA.Next()
+		case cmp > 0:
+			diffs = append(diffs, KVDiff{Key: Cp(iterB.Key()), ValueB: Cp(iterB.Value())})
+			iterB.Next()
+		default:
+			if !bytes.Equal(iterA.Value(), iterB.Value()) {
+				diffs = append(diffs, KVDiff{
+					Key:    Cp(iterA.Key()),
+					ValueA: Cp(iterA.Value()),
+					ValueB: Cp(iterB.Value()),
+				})
+			}
+			iterA.Next()
+			iterB.Next()
+		}
+	}
+	return diffs
+}
+
 // PrefixEndBytes returns the []byte that would end a
 // range query for all []byte with a certain prefix
 // Deals with last byte of prefix being FF without overflowing
@@ -0,0 +1,32 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/store/dbadapter"
+	"github.com/gnolang/gno/pkgs/store/types"
+)
+
+func TestDiffStoreKVs(t *testing.T) {
+	a := dbadapter.Store{DB: dbm.NewMemDB()}
+	b := dbadapter.Store{DB: dbm.NewMemDB()}
+	require.Empty(t, types.DiffStoreKVs(a, b, 0))
+
+	a.Set([]byte("k1"), []byte("v1"))
+	b.Set([]byte("k1"), []byte("v1"))
+	a.Set([]byte("k2"), []byte("v2"))
+	b.Set([]byte("k3"), []byte("v3"))
+	a.Set([]byte("k4"), []byte("v4"))
+	b.Set([]byte("k4"), []byte("v4b"))
+
+	diffs := types.DiffStoreKVs(a, b, 0)
+	require.Equal(t, []types.KVDiff{
+		{Key: []byte("k2"), ValueA: []byte("v2")},
+		{Key: []byte("k3"), ValueB: []byte("v3")},
+		{Key: []byte("k4"), ValueA: []byte("v4"), ValueB: []byte("v4b")},
+	}, diffs)
+	require.Equal(t, diffs[:2], types.DiffStoreKVs(a, b, 2))
+}
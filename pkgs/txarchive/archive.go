@@ -0,0 +1,145 @@
+// Package txarchive archives the txs committed by a node, in a file which
+// can be replayed against a new chain, e.g. to preserve the activity of
+// users across a reset of a testnet.
+//
+// An archive has a line of amino JSON per Record.  The amino types of the
+// msgs of the txs must be registered, by importing their packages.
+package txarchive
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Record is an archived tx, and its position in the chain.
+type Record struct {
+	Height int64  `json:"height"`
+	Index  int    `json:"index"` // in its block
+	Tx     std.Tx `json:"tx"`
+	// Whether the delivery of the tx failed.  Failed txs are archived, as
+	// they increment the sequence of their signers.
+	Failed bool `json:"failed,omitempty"`
+}
+
+// Writer writes the records of an archive.
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes recs, e.g. of a block, in a single write.
+func (w *Writer) Write(recs ...Record) error {
+	var buf bytes.Buffer
+	for _, rec := range recs {
+		bz, err := amino.MarshalJSON(rec)
+		if err != nil {
+			return err
+		}
+		buf.Write(bz)
+		buf.WriteByte('\n')
+	}
+	_, err := w.w.Write(buf.Bytes())
+	return err
+}
+
+// Reader reads the records of an archive.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // txs may add packages.
+	return &Reader{scanner: scanner}
+}
+
+// Next returns the next record, or io.EOF at the end of the archive.
+func (r *Reader) Next() (Record, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return Record{}, err
+		}
+		return Record{}, io.EOF
+	}
+	r.line++
+	var rec Record
+	if err := amino.UnmarshalJSON(r.scanner.Bytes(), &rec); err != nil {
+		return Record{}, errors.Wrap(err, "decoding record of line %d", r.line)
+	}
+	return rec, nil
+}
+
+// ReadAll returns the records of the archive at path.
+func ReadAll(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var recs []Record
+	r := NewReader(f)
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return recs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+}
+
+// Repair truncates the archive at path before the records of its last
+// height, which may be incomplete, e.g. if its archiver crashed while
+// writing, and returns the height of its last remaining record, or 0 if
+// none, from which it can be resumed.
+func Repair(path string) (height int64, err error) {
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	// Heights and offsets of the complete lines.
+	var heights, offsets []int64
+	for offset := 0; ; {
+		n := bytes.IndexByte(bz[offset:], '\n')
+		if n < 0 {
+			break
+		}
+		var rec Record
+		if err := amino.UnmarshalJSON(bz[offset:offset+n], &rec); err != nil {
+			return 0, errors.Wrap(err, "decoding record at offset %d", offset)
+		}
+		heights = append(heights, rec.Height)
+		offsets = append(offsets, int64(offset))
+		offset += n + 1
+	}
+	i := len(heights)
+	for i > 0 && heights[i-1] == heights[len(heights)-1] {
+		i--
+	}
+	end := int64(0) // if no complete line.
+	if i < len(offsets) {
+		end = offsets[i]
+	}
+	if end < int64(len(bz)) {
+		if err := os.Truncate(path, end); err != nil {
+			return 0, err
+		}
+	}
+	if i == 0 {
+		return 0, nil
+	}
+	return heights[i-1], nil
+}
@@ -0,0 +1,142 @@
+package txarchive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	abci "github.com/gnolang/gno/pkgs/bft/abci/types"
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	"github.com/gnolang/gno/pkgs/bft/state"
+	bfttypes "github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+func testRecord(height int64, index int) Record {
+	return Record{
+		Height: height,
+		Index:  index,
+		Tx:     std.Tx{Memo: "memo"},
+	}
+}
+
+func TestWriterReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	w := NewWriter(f)
+	failed := testRecord(1, 1)
+	failed.Failed = true
+	require.NoError(t, w.Write(testRecord(1, 0), failed))
+	require.NoError(t, w.Write(testRecord(3, 0)))
+	require.NoError(t, f.Close())
+
+	recs, err := ReadAll(path)
+	require.NoError(t, err)
+	require.Equal(t, []Record{testRecord(1, 0), failed, testRecord(3, 0)}, recs)
+}
+
+func TestRepair(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive")
+
+	// Missing archive.
+	height, err := Repair(path)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), height)
+
+	// Partial last line, and its height dropped.
+	var bz []byte
+	for _, rec := range []Record{testRecord(1, 0), testRecord(2, 0), testRecord(4, 0), testRecord(4, 1)} {
+		bz = append(bz, amino.MustMarshalJSON(rec)...)
+		bz = append(bz, '\n')
+	}
+	partial := amino.MustMarshalJSON(testRecord(4, 2))
+	require.NoError(t, os.WriteFile(path, append(bz, partial[:10]...), 0o644))
+	height, err = Repair(path)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), height)
+	recs, err := ReadAll(path)
+	require.NoError(t, err)
+	require.Equal(t, []Record{testRecord(1, 0), testRecord(2, 0)}, recs)
+
+	// Only one height.
+	require.NoError(t, os.WriteFile(path, amino.MustMarshalJSON(testRecord(1, 0)), 0o644))
+	height, err = Repair(path)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), height)
+	recs, err = ReadAll(path)
+	require.NoError(t, err)
+	require.Empty(t, recs)
+}
+
+// mockClient serves blocks of txs, failed if their memo is "fail".
+type mockClient struct {
+	blocks [][]std.Tx // from height 1
+}
+
+func (mc *mockClient) Status() (*ctypes.ResultStatus, error) {
+	res := &ctypes.ResultStatus{}
+	res.SyncInfo.LatestBlockHeight = int64(len(mc.blocks))
+	return res, nil
+}
+
+func (mc *mockClient) Block(height *int64) (*ctypes.ResultBlock, error) {
+	if *height > int64(len(mc.blocks)) {
+		return nil, errors.New("no block at height %d", *height)
+	}
+	block := &bfttypes.Block{}
+	for _, tx := range mc.blocks[*height-1] {
+		block.Data.Txs = append(block.Data.Txs, amino.MustMarshal(tx))
+	}
+	return &ctypes.ResultBlock{Block: block}, nil
+}
+
+func (mc *mockClient) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+	res := &ctypes.ResultBlockResults{Height: *height}
+	res.Results = &state.ABCIResponses{}
+	for _, tx := range mc.blocks[*height-1] {
+		var dtx abci.ResponseDeliverTx
+		if tx.Memo == "fail" {
+			dtx.Error = std.UnauthorizedError{}
+		}
+		res.Results.DeliverTxs = append(res.Results.DeliverTxs, dtx)
+	}
+	return res, nil
+}
+
+func TestArchiveTo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive")
+	mc := &mockClient{blocks: [][]std.Tx{
+		{{Memo: "a"}, {Memo: "fail"}},
+		nil,
+		{{Memo: "b"}},
+	}}
+	a, err := NewArchiver(mc, path, 0)
+	require.NoError(t, err)
+	require.NoError(t, a.ArchiveTo(3))
+	require.Error(t, a.ArchiveTo(4))
+	require.NoError(t, a.file.Close())
+
+	recs, err := ReadAll(path)
+	require.NoError(t, err)
+	require.Equal(t, []Record{
+		{Height: 1, Index: 0, Tx: std.Tx{Memo: "a"}},
+		{Height: 1, Index: 1, Tx: std.Tx{Memo: "fail"}, Failed: true},
+		{Height: 3, Index: 0, Tx: std.Tx{Memo: "b"}},
+	}, recs)
+
+	// Resumed after the last height, which is archived again.
+	mc.blocks = append(mc.blocks, []std.Tx{{Memo: "c"}})
+	a, err = NewArchiver(mc, path, 0)
+	require.NoError(t, err)
+	require.NoError(t, a.ArchiveTo(4))
+	require.NoError(t, a.file.Close())
+	recs, err = ReadAll(path)
+	require.NoError(t, err)
+	require.Len(t, recs, 4)
+	require.Equal(t, "c", recs[3].Tx.Memo)
+}
@@ -0,0 +1,124 @@
+package txarchive
+
+import (
+	"os"
+	"time"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/service"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Client is the part of the RPC client of a node used by an Archiver.
+type Client interface {
+	Status() (*ctypes.ResultStatus, error)
+	Block(height *int64) (*ctypes.ResultBlock, error)
+	BlockResults(height *int64) (*ctypes.ResultBlockResults, error)
+}
+
+// Archiver appends the txs committed by a node to an archive, polling the
+// node for new blocks.  It resumes the archive from its last height.
+type Archiver struct {
+	service.BaseService
+
+	cli      Client
+	file     *os.File
+	out      *Writer
+	height   int64 // last archived
+	interval time.Duration
+}
+
+// NewArchiver returns an archiver of the txs of cli to the archive at path,
+// which is repaired and resumed if it exists, polling every interval.
+func NewArchiver(cli Client, path string, interval time.Duration) (*Archiver, error) {
+	height, err := Repair(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "repairing archive")
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	a := &Archiver{
+		cli:      cli,
+		file:     file,
+		out:      NewWriter(file),
+		height:   height,
+		interval: interval,
+	}
+	a.BaseService = *service.NewBaseService(nil, "Archiver", a)
+	return a, nil
+}
+
+func (a *Archiver) OnStart() error {
+	a.BaseService.OnStart()
+	go a.routinePoll()
+	return nil
+}
+
+func (a *Archiver) OnStop() {
+	a.BaseService.OnStop()
+	a.file.Close()
+}
+
+func (a *Archiver) routinePoll() {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		status, err := a.cli.Status()
+		if err != nil {
+			a.Logger.Error("querying status", "err", err)
+		} else if err := a.ArchiveTo(status.SyncInfo.LatestBlockHeight); err != nil {
+			a.Logger.Error("archiving", "height", a.height+1, "err", err)
+		}
+		select {
+		case <-a.Quit():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ArchiveTo archives the txs of the blocks after the last archived height,
+// up to height.  It is not safe to call while the archiver is running.
+func (a *Archiver) ArchiveTo(height int64) error {
+	for a.height < height {
+		h := a.height + 1
+		block, err := a.cli.Block(&h)
+		if err != nil {
+			return err
+		}
+		txs := block.Block.Data.Txs
+		if len(txs) > 0 {
+			bres, err := a.cli.BlockResults(&h)
+			if err != nil {
+				return err
+			}
+			dtxs := bres.Results.DeliverTxs
+			if len(dtxs) != len(txs) {
+				return errors.New("expected %d results at height %d, got %d", len(txs), h, len(dtxs))
+			}
+			recs := make([]Record, len(txs))
+			for i, tx := range txs {
+				var stdtx std.Tx
+				if err := amino.Unmarshal(tx, &stdtx); err != nil {
+					return errors.Wrap(err, "decoding tx %d at height %d", i, h)
+				}
+				recs[i] = Record{
+					Height: h,
+					Index:  i,
+					Tx:     stdtx,
+					Failed: dtxs[i].IsErr(),
+				}
+			}
+			if err := a.out.Write(recs...); err != nil {
+				return err
+			}
+			a.Logger.Info("archived", "height", h, "txs", len(txs))
+		}
+		a.height = h
+	}
+	return nil
+}
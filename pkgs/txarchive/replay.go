@@ -0,0 +1,59 @@
+package txarchive
+
+import (
+	"io"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	ctypes "github.com/gnolang/gno/pkgs/bft/rpc/core/types"
+	"github.com/gnolang/gno/pkgs/bft/types"
+	"github.com/gnolang/gno/pkgs/errors"
+	"github.com/gnolang/gno/pkgs/std"
+)
+
+// Broadcaster is the part of the RPC client of a node used by Replay.
+type Broadcaster interface {
+	BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error)
+}
+
+// Replay broadcasts the txs of the records of r to cli, in order, each
+// waiting for its commit, and returns the number of replayed txs and the
+// records whose delivery failed or succeeded unlike the original.  The txs
+// keep their signatures, so the chain of cli must have the chain ID and the
+// accounts of the original chain.
+func Replay(cli Broadcaster, r *Reader) (replayed int, mismatches []Record, err error) {
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return replayed, mismatches, nil
+		} else if err != nil {
+			return replayed, mismatches, err
+		}
+		bres, err := cli.BroadcastTxCommit(amino.MustMarshal(rec.Tx))
+		if err != nil {
+			return replayed, mismatches, errors.Wrap(err, "broadcasting tx %d of height %d", rec.Index, rec.Height)
+		}
+		replayed++
+		failed := bres.CheckTx.IsErr() || bres.DeliverTx.IsErr()
+		if failed != rec.Failed {
+			mismatches = append(mismatches, rec)
+		}
+	}
+}
+
+// GenesisTxs returns the txs of the records of r which did not fail, to be
+// replayed as the genesis txs of a new chain, whose signatures are not
+// verified, e.g. for a new chain ID.
+func GenesisTxs(r *Reader) ([]std.Tx, error) {
+	var txs []std.Tx
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return txs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if !rec.Failed {
+			txs = append(txs, rec.Tx)
+		}
+	}
+}
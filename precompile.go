@@ -34,12 +34,16 @@ var stdlibWhitelist = []string{
 	"bytes",
 	"compress/gzip",
 	"context",
+	"crypto/ed25519",
 	"crypto/md5",
 	"crypto/sha1",
+	"crypto/sha256",
 	"encoding/json",
 	"encoding/base64",
 	"encoding/binary",
+	"encoding/hex",
 	"encoding/xml",
+	"errors",
 	"flag",
 	"fmt",
 	"io",
@@ -53,6 +57,7 @@ var stdlibWhitelist = []string{
 	"strings",
 	"text/template",
 	"time",
+	"unicode",
 	"unicode/utf8",
 
 	// gno
@@ -80,7 +85,7 @@ func PrecompileAndCheckMempkg(mempkg *std.MemPackage) error {
 		if !strings.HasSuffix(mfile.Name, ".gno") {
 			continue // skip spurious file.
 		}
-		translated, err := Precompile(string(mfile.Body), "gno,tmp", mfile.Name)
+		translated, sm, err := PrecompileWithSourceMap(string(mfile.Body), "gno,tmp", mfile.Name)
 		if err != nil {
 			errs = multierr.Append(errs, err)
 			continue
@@ -91,6 +96,13 @@ func PrecompileAndCheckMempkg(mempkg *std.MemPackage) error {
 			errs = multierr.Append(errs, err)
 			continue
 		}
+		if sm != nil {
+			err = WriteSourceMap(tmpFile, sm)
+			if err != nil {
+				errs = multierr.Append(errs, err)
+				continue
+			}
+		}
 		err = PrecompileVerifyFile(tmpFile, gofmt)
 		if err != nil {
 			errs = multierr.Append(errs, err)
@@ -105,12 +117,20 @@ func PrecompileAndCheckMempkg(mempkg *std.MemPackage) error {
 }
 
 func Precompile(source string, tags string, filename string) (string, error) {
+	out, _, err := PrecompileWithSourceMap(source, tags, filename)
+	return out, err
+}
+
+// PrecompileWithSourceMap is like Precompile, but also returns the source
+// map of the .go output back to the .gno source, or nil if it could not
+// be computed.
+func PrecompileWithSourceMap(source string, tags string, filename string) (string, *SourceMap, error) {
 	var out bytes.Buffer
 
 	fset := token.NewFileSet()
 	f, err := parser.ParseFile(fset, "tmp.gno", source, parser.ParseComments)
 	if err != nil {
-		return "", fmt.Errorf("parse: %w", err)
+		return "", nil, fmt.Errorf("parse: %w", err)
 	}
 
 	isTestFile := strings.HasSuffix(filename, "_test.gno") || strings.HasSuffix(filename, "_filetest.gno")
@@ -118,20 +138,21 @@ func Precompile(source string, tags string, filename string) (string, error) {
 
 	transformed, err := precompileAST(fset, f, shouldCheckWhitelist)
 	if err != nil {
-		return "", fmt.Errorf("%w", err)
+		return "", nil, fmt.Errorf("%w", err)
 	}
 
 	_, err = out.WriteString("// Code generated by github.com/gnolang/gno. DO NOT EDIT.\n\n//go:build " + tags + "\n// +build " + tags + "\n\n")
 	if err != nil {
-		return "", fmt.Errorf("write to buffer: %w", err)
+		return "", nil, fmt.Errorf("write to buffer: %w", err)
 	}
 	err = format.Node(&out, fset, transformed)
-	return out.String(), nil
+	return out.String(), newSourceMap(filename, fset, transformed, out.String()), nil
 }
 
 // PrecompileVerifyFile tries to run `go fmt` against a precompiled .go file.
 //
-// This is fast and won't look the imports.
+// This is fast and won't look the imports. The locations of the errors are
+// translated to .gno with the source map of the file, if any.
 func PrecompileVerifyFile(path string, gofmtBinary string) error {
 	// TODO: use cmd/parser instead of exec?
 
@@ -139,7 +160,7 @@ func PrecompileVerifyFile(path string, gofmtBinary string) error {
 	cmd := exec.Command(gofmtBinary, args...)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, string(out))
+		fmt.Fprintln(os.Stderr, NewSourceMaps().Translate(string(out)))
 		return fmt.Errorf("gofmt: %w", err)
 	}
 	return nil
@@ -148,7 +169,8 @@ func PrecompileVerifyFile(path string, gofmtBinary string) error {
 // PrecompileBuildPackage tries to run `go build` against the precompiled .go files.
 //
 // This method is the most efficient to detect errors but requires that
-// all the import are valid and available.
+// all the import are valid and available. The locations of the errors are
+// translated to .gno with the source maps of the files, if any.
 func PrecompileBuildPackage(fileOrPkg string, goBinary string) error {
 	// TODO: use cmd/compile instead of exec?
 	// TODO: find the nearest go.mod file, chdir in the same folder, rim prefix?
@@ -188,7 +210,7 @@ func PrecompileBuildPackage(fileOrPkg string, goBinary string) error {
 	cmd := exec.Command(goBinary, args...)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, string(out))
+		fmt.Fprintln(os.Stderr, NewSourceMaps().Translate(string(out)))
 		return fmt.Errorf("std go compiler: %w", err)
 	}
 
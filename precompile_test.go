@@ -46,6 +46,10 @@ func TestPrecompile(t *testing.T) {
 			name:           "whitelisted-package",
 			source:         "package foo\nimport \"regexp\"\nfunc foo() { _ = regexp.MatchString}",
 			expectedOutput: "package foo\nimport \"regexp\"\nfunc foo() { _ = regexp.MatchString}",
+		}, {
+			name:           "whitelisted-math-big",
+			source:         "package foo\nimport \"math/big\"\nfunc foo() { _ = big.NewInt}",
+			expectedOutput: "package foo\nimport \"math/big\"\nfunc foo() { _ = big.NewInt}",
 		},
 		// multiple files
 		// syntax error
@@ -230,6 +230,27 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					}
 				}
 
+			// TRANS_ENTER -----------------------
+			// Goroutines and channel operations are rejected, as
+			// there is no deterministic scheduler yet; only nil
+			// channels may be declared and passed around.
+			case *GoStmt:
+				panic("go statements are not supported")
+
+			// TRANS_ENTER -----------------------
+			case *SelectStmt:
+				panic("select statements are not supported")
+
+			// TRANS_ENTER -----------------------
+			case *SendStmt:
+				panic("channel sends are not supported")
+
+			// TRANS_ENTER -----------------------
+			case *UnaryExpr:
+				if n.Op == ARROW {
+					panic("channel receives are not supported")
+				}
+
 			// TRANS_ENTER -----------------------
 			case *FuncTypeExpr:
 				for i := range n.Params {
@@ -301,6 +322,8 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 					n.IsMap = true
 				case StringKind:
 					n.IsString = true
+				case ChanKind:
+					panic("range over channels is not supported")
 				case PointerKind:
 					if xt.Elem().Kind() != ArrayKind {
 						panic("range iteration over pointer requires array elem type")
@@ -1010,6 +1033,12 @@ func Preprocess(store Store, ctx BlockNode, n Node) Node {
 								n.Args[1] = args1
 							}
 						}
+					} else if fv.PkgPath == ".uverse" && fv.Name == "make" {
+						if len(n.Args) > 0 {
+							if evalStaticType(store, last, n.Args[0]).Kind() == ChanKind {
+								panic("making channels is not supported")
+							}
+						}
 					} else if fv.PkgPath == ".uverse" && fv.Name == "copy" {
 						if len(n.Args) == 2 {
 							// If the second argument is a string,
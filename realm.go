@@ -91,6 +91,7 @@ type Realm struct {
 	newCreated []Object
 	newEscaped []Object
 	newDeleted []Object
+	newDecRefd []Object // escaped objects whose ref-count decreased.
 
 	created []Object // about to become real.
 	updated []Object // real objects that were modified.
@@ -118,6 +119,22 @@ func (rlm *Realm) String() string {
 	}
 }
 
+// RealmState is the persisted state of a realm, as exported by
+// Store.ExportRealm(): its realm record, and all of its objects as stored,
+// i.e. with their children replaced by references, ordered by creation
+// time.  It may be imported into another store with the same package, e.g.
+// of another chain, to fork a realm or debug it against production state.
+type RealmState struct {
+	Realm   *Realm
+	Objects []Object
+}
+
+// RealmGuard is called by the machine before a call enters a realm, with the
+// path of the realm of the caller ("" if none) and of the realm entered.  It
+// may panic to prevent the call, e.g. into a paused realm; the panic is not
+// recoverable by Gno code.
+type RealmGuard func(from string, to string)
+
 //----------------------------------------
 // ownership hooks
 
@@ -172,6 +189,8 @@ func (rlm *Realm) DidUpdate(po, xo, co Object) {
 			if xo.GetIsReal() {
 				rlm.MarkNewDeleted(xo)
 			}
+		} else if xo.GetIsEscaped() {
+			rlm.MarkNewDecRefd(xo)
 		}
 	}
 }
@@ -250,6 +269,22 @@ func (rlm *Realm) MarkNewDeleted(oo Object) {
 	rlm.newDeleted = append(rlm.newDeleted, oo)
 }
 
+// Escaped objects whose ref-count decreased (but not to zero) may have
+// become garbage in a reference cycle.  Their ref-count must be
+// persisted, so they are also marked dirty.
+func (rlm *Realm) MarkNewDecRefd(oo Object) {
+	if oo.GetObjectID().PkgID != rlm.ID {
+		// external-realm objects are not modified.
+		return
+	}
+	rlm.MarkDirty(oo)
+	// append to .newDecRefd
+	if rlm.newDecRefd == nil {
+		rlm.newDecRefd = make([]Object, 0, 64)
+	}
+	rlm.newDecRefd = append(rlm.newDecRefd, oo)
+}
+
 func (rlm *Realm) MarkNewEscaped(oo Object) {
 	if debug {
 		if !oo.GetIsNewReal() && !oo.GetIsReal() {
@@ -314,6 +349,8 @@ func (rlm *Realm) FinalizeRealmTransaction(readonly bool, store Store) {
 	rlm.processNewCreatedMarks(store)
 	// decrement recursively for deleted descendants.
 	rlm.processNewDeletedMarks(store)
+	// delete unreachable reference cycles.
+	rlm.processNewDecRefdMarks(store)
 	// at this point, all ref-counts are final.
 	// demote any escaped if ref-count is 1.
 	rlm.processNewEscapedMarks(store)
@@ -494,13 +531,177 @@ func (rlm *Realm) decRefDeletedDescendants(store Store, oo Object) {
 		if rc == 0 {
 			rlm.decRefDeletedDescendants(store, child)
 		} else if rc > 0 {
-			// do nothing
+			if child.GetIsEscaped() {
+				rlm.MarkNewDecRefd(child)
+			}
 		} else {
 			panic("should not happen")
 		}
 	}
 }
 
+//----------------------------------------
+// processNewDecRefdMarks
+
+// Objects in a reference cycle keep each other's ref-count above zero,
+// so they are not deleted when the cycle becomes unreachable.  As only
+// escaped objects can be referenced more than once, such cycles are
+// found by trial deletion (Bacon & Rajan) from the escaped objects
+// whose ref-count decreased: references from within the objects
+// reachable from them are subtracted, and those left without
+// references are deleted.  Package blocks and objects of other realms
+// are never deleted, and not crawled.
+// Must run *after* processNewDeletedMarks().
+func (rlm *Realm) processNewDecRefdMarks(store Store) {
+	if len(rlm.newDecRefd) == 0 {
+		return
+	}
+	cc := newCycleCollector(rlm, store)
+	roots := make([]Object, 0, len(rlm.newDecRefd))
+	for _, oo := range rlm.newDecRefd {
+		if oo.GetRefCount() == 0 {
+			// deleted since.
+			continue
+		}
+		if !cc.isCollectable(oo) {
+			continue
+		}
+		if _, ok := cc.colors[oo.GetObjectID()]; ok {
+			continue
+		}
+		cc.markGray(oo)
+		roots = append(roots, oo)
+	}
+	for _, oo := range roots {
+		cc.scanGray(oo)
+	}
+	for _, oo := range roots {
+		cc.deleteWhite(oo)
+	}
+}
+
+type cycleColor int
+
+const (
+	cycleGray    cycleColor = iota + 1 // references from within subtracted.
+	cycleBlack                         // referenced from without.
+	cycleWhite                         // garbage.
+	cycleDeleted                       // garbage, deleted.
+)
+
+type cycleCollector struct {
+	rlm    *Realm
+	store  Store
+	blocks map[ObjectID]struct{} // package and file blocks.
+	colors map[ObjectID]cycleColor
+}
+
+func newCycleCollector(rlm *Realm, store Store) *cycleCollector {
+	cc := &cycleCollector{
+		rlm:    rlm,
+		store:  store,
+		blocks: make(map[ObjectID]struct{}),
+		colors: make(map[ObjectID]cycleColor),
+	}
+	pv := store.GetObject(ObjectIDFromPkgPath(rlm.Path)).(*PackageValue)
+	for _, bv := range append([]Value{pv.Block}, pv.FBlocks...) {
+		switch bv := bv.(type) {
+		case RefValue:
+			cc.blocks[bv.ObjectID] = struct{}{}
+		case *Block:
+			cc.blocks[bv.GetObjectID()] = struct{}{}
+		}
+	}
+	return cc
+}
+
+// Returns whether oo may be in a garbage cycle.
+func (cc *cycleCollector) isCollectable(oo Object) bool {
+	if _, ok := oo.(*PackageValue); ok {
+		return false
+	}
+	if oo.GetObjectID().PkgID != cc.rlm.ID {
+		return false
+	}
+	_, ok := cc.blocks[oo.GetObjectID()]
+	return !ok
+}
+
+// Returns the child objects of oo which may be in a garbage cycle.
+func (cc *cycleCollector) getChildObjects(oo Object) []Object {
+	more := getChildObjects2(cc.store, oo)
+	children := more[:0]
+	for _, child := range more {
+		if cc.isCollectable(child) {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func (cc *cycleCollector) markGray(oo Object) {
+	if cc.colors[oo.GetObjectID()] == cycleGray {
+		return
+	}
+	cc.colors[oo.GetObjectID()] = cycleGray
+	for _, child := range cc.getChildObjects(oo) {
+		child.DecRefCount()
+		cc.markGray(child)
+	}
+}
+
+func (cc *cycleCollector) scanGray(oo Object) {
+	if cc.colors[oo.GetObjectID()] != cycleGray {
+		return
+	}
+	if oo.GetRefCount() > 0 {
+		cc.scanBlack(oo)
+		return
+	}
+	cc.colors[oo.GetObjectID()] = cycleWhite
+	for _, child := range cc.getChildObjects(oo) {
+		cc.scanGray(child)
+	}
+}
+
+// Restores the ref-counts of the children of oo, which is referenced
+// from without.
+func (cc *cycleCollector) scanBlack(oo Object) {
+	cc.colors[oo.GetObjectID()] = cycleBlack
+	for _, child := range cc.getChildObjects(oo) {
+		child.IncRefCount()
+		if cc.colors[child.GetObjectID()] != cycleBlack {
+			cc.scanBlack(child)
+		}
+	}
+}
+
+// Like decRefDeletedDescendants but for garbage found by scanGray.
+// The references to children which are referenced from without were
+// already subtracted by markGray.
+func (cc *cycleCollector) deleteWhite(oo Object) {
+	if cc.colors[oo.GetObjectID()] != cycleWhite {
+		return
+	}
+	cc.colors[oo.GetObjectID()] = cycleDeleted
+	for _, child := range cc.getChildObjects(oo) {
+		switch cc.colors[child.GetObjectID()] {
+		case cycleWhite:
+			cc.deleteWhite(child)
+		case cycleBlack:
+			// persist the decreased ref-count.
+			cc.rlm.MarkDirty(child)
+		}
+	}
+	oo.SetIsNewDeleted(false)
+	oo.SetIsNewReal(false)
+	oo.SetIsNewEscaped(false)
+	oo.SetIsDeleted(true, cc.rlm.Time)
+	// not to be saved before deleted, if marked dirty.
+	oo.SetIsDirty(false, 0)
+	cc.rlm.deleted = append(cc.rlm.deleted, oo)
+}
+
 //----------------------------------------
 // processNewEscapedMarks
 
@@ -535,12 +736,16 @@ func (rlm *Realm) processNewEscapedMarks(store Store) {
 			// the hash index.
 			// eo.SetIsNewEscaped(false)
 			escaped = append(escaped, eo)
+			// persist the ref-count and escape if real.
+			rlm.MarkDirty(eo)
 
 			// add to escaped, and mark dirty previous owner.
 			po := getOwner(store, eo)
 			if po == nil {
 				// e.g. !eo.GetIsNewReal(),
-				// should have no parent.
+				// should have no parent,
+				// but clear the owner id if not loaded.
+				eo.SetOwner(nil)
 				continue
 			} else {
 				if po.GetRefCount() == 0 {
@@ -783,6 +988,7 @@ func (rlm *Realm) clearMarks() {
 	rlm.newCreated = nil
 	rlm.newEscaped = nil
 	rlm.newDeleted = nil
+	rlm.newDecRefd = nil
 	rlm.created = nil
 	rlm.updated = nil
 	rlm.deleted = nil
@@ -1339,10 +1545,15 @@ func fillTypesOfValue(store Store, val Value) Value {
 		fillTypesTV(store, &cv.Receiver)
 		return cv
 	case *MapValue:
-		cv.MakeMap(cv.List.Size) // TODO move out.
+		// rebuild the index of the decoded list.
+		if cv.List == nil {
+			cv.List = &MapList{}
+		}
+		cv.vmap = make(map[MapKey]*MapListItem, cv.List.Size)
 		for cur := cv.List.Head; cur != nil; cur = cur.Next {
 			fillTypesTV(store, &cur.Key)
 			fillTypesTV(store, &cur.Value)
+			cv.vmap[cur.Key.ComputeMapKey(store, false)] = cur
 		}
 		return cv
 	case TypeValue:
@@ -0,0 +1,165 @@
+package gno
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// SourceMapExt is the extension of the source map files, written next to
+// the precompiled .go files, e.g. foo.gno.gen.go.map.
+const SourceMapExt = ".map"
+
+// SourceMap maps the lines of a precompiled .go file back to the lines of
+// its .gno file.
+type SourceMap struct {
+	GnoFile string `json:"gno_file"` // base name, in the dir of the .go file
+	Lines   []int  `json:"lines"`    // gno line of each go line, or 0
+}
+
+// GnoLine returns the line of the .gno file of goLine, or 0 if goLine was
+// generated, e.g. in the header.  Lines with no node of their own, e.g.
+// within a raw string, follow the last mapped line.
+func (sm *SourceMap) GnoLine(goLine int) int {
+	for l := goLine; 0 < l && l <= len(sm.Lines); l-- {
+		if sm.Lines[l-1] != 0 {
+			return sm.Lines[l-1] + goLine - l
+		}
+	}
+	return 0
+}
+
+// ReadSourceMap reads the source map of the precompiled .go file of goPath.
+func ReadSourceMap(goPath string) (*SourceMap, error) {
+	bz, err := ioutil.ReadFile(goPath + SourceMapExt)
+	if err != nil {
+		return nil, err
+	}
+	sm := new(SourceMap)
+	if err := json.Unmarshal(bz, sm); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// WriteSourceMap writes sm next to the precompiled .go file of goPath.
+func WriteSourceMap(goPath string, sm *SourceMap) error {
+	bz, err := json.Marshal(sm)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(goPath+SourceMapExt, bz, 0o644)
+}
+
+// Returns the source map of the go source generated from the gno source
+// of fset and gnoNode, its AST, or nil if it cannot be parsed.  Since the
+// precompiler only rewrites imports, both have the same nodes in the same
+// order.
+func newSourceMap(gnoFile string, fset *token.FileSet, gnoNode ast.Node, goSource string) *SourceMap {
+	goFset := token.NewFileSet()
+	goNode, err := parser.ParseFile(goFset, "", goSource, parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	gnoNodes, goNodes := astNodes(gnoNode), astNodes(goNode)
+	if len(gnoNodes) != len(goNodes) {
+		return nil
+	}
+	lines := make([]int, goFset.File(goNode.Pos()).LineCount())
+	mapLine := func(goPos, gnoPos token.Pos) {
+		if !goPos.IsValid() || !gnoPos.IsValid() {
+			return
+		}
+		goLine := goFset.Position(goPos).Line
+		if 0 < goLine && goLine <= len(lines) && lines[goLine-1] == 0 {
+			lines[goLine-1] = fset.Position(gnoPos).Line
+		}
+	}
+	for i := range goNodes {
+		mapLine(goNodes[i].Pos(), gnoNodes[i].Pos())
+	}
+	// closing lines, e.g. of blocks.
+	for i := range goNodes {
+		mapLine(goNodes[i].End()-1, gnoNodes[i].End()-1)
+	}
+	return &SourceMap{
+		GnoFile: filepath.Base(gnoFile),
+		Lines:   lines,
+	}
+}
+
+// Returns the nodes of n in depth-first order.
+func astNodes(n ast.Node) (nodes []ast.Node) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+		return true
+	})
+	return nodes
+}
+
+//----------------------------------------
+// SourceMaps
+
+// The locations in go output, e.g. of compiler errors, panics and stack
+// traces: <path>.go:<line>, optionally followed by :<column>.
+var goLocationRe = regexp.MustCompile(`([^\s:"'()]+\.go):(\d+)(:\d+)?`)
+
+// SourceMaps translates the locations in precompiled .go files to their
+// .gno files, with the source map of each .go file, read next to it on
+// first use.
+type SourceMaps struct {
+	maps map[string]*SourceMap // by .go path; nil if none.
+}
+
+func NewSourceMaps() *SourceMaps {
+	return &SourceMaps{
+		maps: make(map[string]*SourceMap),
+	}
+}
+
+// Add sets the source map of the .go file of goPath.
+func (sms *SourceMaps) Add(goPath string, sm *SourceMap) {
+	sms.maps[goPath] = sm
+}
+
+// Get returns the source map of the .go file of goPath, if any.
+func (sms *SourceMaps) Get(goPath string) *SourceMap {
+	sm, ok := sms.maps[goPath]
+	if !ok {
+		sm, _ = ReadSourceMap(goPath)
+		sms.maps[goPath] = sm
+	}
+	return sm
+}
+
+// Translate returns out with the locations in .go files which have a
+// source map replaced by their .gno locations, e.g.
+// "foo.gno.gen.go:12:3" by "foo.gno:6".  The columns are dropped, as the
+// rewritten imports may shift them.
+func (sms *SourceMaps) Translate(out string) string {
+	return goLocationRe.ReplaceAllStringFunc(out, func(loc string) string {
+		m := goLocationRe.FindStringSubmatch(loc)
+		goPath := m[1]
+		sm := sms.Get(goPath)
+		if sm == nil {
+			return loc
+		}
+		goLine, err := strconv.Atoi(m[2])
+		if err != nil {
+			return loc
+		}
+		gnoLine := sm.GnoLine(goLine)
+		if gnoLine == 0 {
+			return loc
+		}
+		gnoPath := filepath.Join(filepath.Dir(goPath), sm.GnoFile)
+		return gnoPath + ":" + strconv.Itoa(gnoLine)
+	})
+}
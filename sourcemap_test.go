@@ -0,0 +1,43 @@
+package gno
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecompileSourceMap(t *testing.T) {
+	source := "package foo\n\nimport \"std\"\n\n\n\nfunc Foo() string {\n\t_ = std.GetHeight\n\ts := `a\nb`\n\tpanic(\"foo\" + s)\n}\n"
+	out, sm, err := PrecompileWithSourceMap(source, "gno", "dir/foo.gno")
+	require.NoError(t, err)
+	require.NotNil(t, sm)
+	assert.Equal(t, "foo.gno", sm.GnoFile)
+
+	// the header is generated, and the blank lines collapsed.
+	assert.Contains(t, out, "\tpanic(\"foo\" + s)\n")
+	assert.Equal(t, 0, sm.GnoLine(1))
+	assert.Equal(t, 1, sm.GnoLine(6))  // package foo
+	assert.Equal(t, 7, sm.GnoLine(10)) // func Foo() string {
+	assert.Equal(t, 10, sm.GnoLine(13))
+	assert.Equal(t, 11, sm.GnoLine(14)) // panic
+	assert.Equal(t, 12, sm.GnoLine(15))
+	assert.Equal(t, 0, sm.GnoLine(100))
+
+	// translate go output.
+	goPath := filepath.Join(t.TempDir(), "foo.gno.gen.go")
+	require.NoError(t, WriteSourceMap(goPath, sm))
+	sms := NewSourceMaps()
+	gnoPath := filepath.Join(filepath.Dir(goPath), "foo.gno")
+	assert.Equal(t,
+		"panic: foo\n\t"+gnoPath+":11 +0x1d\n\t/go/src/testing.go:14 +0x2e\n",
+		sms.Translate("panic: foo\n\t"+goPath+":14 +0x1d\n\t/go/src/testing.go:14 +0x2e\n"))
+	assert.Equal(t, gnoPath+":8: undefined: std.GetHeight",
+		sms.Translate(goPath+":11:6: undefined: std.GetHeight"))
+
+	// added maps.
+	sms.Add("bar.gno.gen.go", &SourceMap{GnoFile: "bar.gno", Lines: []int{0, 3}})
+	assert.Equal(t, "bar.gno:3: x", sms.Translate("bar.gno.gen.go:2:1: x"))
+	assert.Equal(t, "bar.gno.gen.go:1:1: x", sms.Translate("bar.gno.gen.go:1:1: x"))
+}
@@ -0,0 +1,118 @@
+package gno
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StackFrame is a call frame of a Stacktrace.
+type StackFrame struct {
+	PkgPath string // package of the function
+	Func    string // name of the function, or Type.Method
+	File    string // file of the function, or "" if native
+	Line    int    // current line in File, or 0 if unknown, e.g. bytecode
+}
+
+func (sf StackFrame) String() string {
+	switch {
+	case sf.File == "":
+		return fmt.Sprintf("%s.%s()", sf.PkgPath, sf.Func)
+	case sf.Line == 0:
+		return fmt.Sprintf("%s.%s()\n\t%s/%s", sf.PkgPath, sf.Func, sf.PkgPath, sf.File)
+	default:
+		return fmt.Sprintf("%s.%s()\n\t%s/%s:%d", sf.PkgPath, sf.Func, sf.PkgPath, sf.File, sf.Line)
+	}
+}
+
+// Stacktrace is a gno call stack, innermost call first.
+type Stacktrace []StackFrame
+
+func (st Stacktrace) String() string {
+	lines := make([]string, len(st))
+	for i, sf := range st {
+		lines[i] = sf.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Stacktrace returns the current call stack of m.  When an unrecovered gno
+// panic has unwound the stack, see PanicStacktrace instead.
+func (m *Machine) Stacktrace() Stacktrace {
+	st := Stacktrace{}
+	line := m.lastLine()
+	for i := len(m.Frames) - 1; i >= 0; i-- {
+		fr := &m.Frames[i]
+		fv := fr.Func
+		if fv == nil {
+			continue
+		}
+		sf := StackFrame{
+			PkgPath: fv.PkgPath,
+			Func:    stackFuncName(fr),
+		}
+		// natives and bytecode have no current line of their own.
+		switch {
+		case fv.nativeBody != nil:
+		case m.getBytecode(fv) != nil:
+			sf.File = string(fv.FileName)
+		default:
+			sf.File = string(fv.FileName)
+			sf.Line = line
+		}
+		st = append(st, sf)
+		line = fr.Source.GetLine() // the call, in the caller.
+	}
+	return st
+}
+
+// PanicStacktrace returns the call stack of m when the unrecovered gno
+// panic of m.Exception was raised, or nil if none.
+func (m *Machine) PanicStacktrace() Stacktrace {
+	if m.Exception == nil {
+		return nil
+	}
+	return m.panicStack
+}
+
+// Returns the line of the last expression or statement being run, or 0.
+func (m *Machine) lastLine() int {
+	for i := len(m.Exprs) - 1; i >= 0; i-- {
+		if line := m.Exprs[i].GetLine(); line > 0 {
+			return line
+		}
+	}
+	for i := len(m.Stmts) - 1; i >= 0; i-- {
+		s := m.Stmts[i]
+		if bs, ok := s.(*bodyStmt); ok {
+			// the active statement, or the last one run, e.g. a
+			// panic statement, which pops itself.
+			if bs.NextBodyIndex <= 0 || bs.NextBodyIndex > len(bs.Body) {
+				continue
+			}
+			s = bs.Body[bs.NextBodyIndex-1]
+		}
+		if line := s.GetLine(); line > 0 {
+			return line
+		}
+	}
+	return 0
+}
+
+// Returns the name of the function of fr, prefixed by the type of its
+// receiver if a method.
+func stackFuncName(fr *Frame) string {
+	name := string(fr.Func.Name)
+	if name == "" {
+		name = "func"
+	}
+	if fr.Func.IsMethod && fr.Receiver.T != nil {
+		rt := fr.Receiver.T
+		if pt, ok := rt.(*PointerType); ok {
+			rt = pt.Elt
+		}
+		if dt, ok := rt.(*DeclaredType); ok {
+			name = string(dt.Name) + "." + name
+		}
+	}
+	return name
+}
@@ -18,6 +18,16 @@ type Banker interface {
 	TotalCoin(denom string) int64
 	IssueCoin(addr crypto.Bech32Address, denom string, amount int64)
 	RemoveCoin(addr crypto.Bech32Address, denom string, amount int64)
+	Allowance(owner, spender crypto.Bech32Address) (allowance std.Coins)
+	Approve(owner, spender crypto.Bech32Address, amt std.Coins)
+	TransferFrom(spender, from, to crypto.Bech32Address, amt std.Coins)
+	GetDenomMetadata(denom string) (meta std.DenomMetadata)
+	SetDenomMetadata(meta std.DenomMetadata)
+
+	// Not in stdlibs/std.Banker: the realm issuing each denom, which
+	// alone may issue and remove its coins and set its metadata.
+	GetDenomIssuer(denom string) crypto.Bech32Address
+	SetDenomIssuer(denom string, issuer crypto.Bech32Address)
 }
 
 // Used in std.GetBanker(options).
@@ -67,6 +77,34 @@ func (rb ReadonlyBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amo
 	panic("ReadonlyBanker cannot remove coins")
 }
 
+func (rb ReadonlyBanker) Allowance(owner, spender crypto.Bech32Address) (allowance std.Coins) {
+	return rb.banker.Allowance(owner, spender)
+}
+
+func (rb ReadonlyBanker) Approve(owner, spender crypto.Bech32Address, amt std.Coins) {
+	panic("ReadonlyBanker cannot approve allowances")
+}
+
+func (rb ReadonlyBanker) TransferFrom(spender, from, to crypto.Bech32Address, amt std.Coins) {
+	panic("ReadonlyBanker cannot transfer coins")
+}
+
+func (rb ReadonlyBanker) GetDenomMetadata(denom string) (meta std.DenomMetadata) {
+	return rb.banker.GetDenomMetadata(denom)
+}
+
+func (rb ReadonlyBanker) SetDenomMetadata(meta std.DenomMetadata) {
+	panic("ReadonlyBanker cannot set denom metadata")
+}
+
+func (rb ReadonlyBanker) GetDenomIssuer(denom string) crypto.Bech32Address {
+	return rb.banker.GetDenomIssuer(denom)
+}
+
+func (rb ReadonlyBanker) SetDenomIssuer(denom string, issuer crypto.Bech32Address) {
+	panic("ReadonlyBanker cannot set denom issuers")
+}
+
 //----------------------------------------
 // OrigSendBanker
 
@@ -121,6 +159,43 @@ func (osb OrigSendBanker) RemoveCoin(addr crypto.Bech32Address, denom string, am
 	panic("OrigSendBanker cannot remove coins")
 }
 
+func (osb OrigSendBanker) Allowance(owner, spender crypto.Bech32Address) (allowance std.Coins) {
+	return osb.banker.Allowance(owner, spender)
+}
+
+// Approving would let the spender take realm coins beyond what was
+// sent in the transaction.
+func (osb OrigSendBanker) Approve(owner, spender crypto.Bech32Address, amt std.Coins) {
+	panic("OrigSendBanker cannot approve allowances")
+}
+
+// The coins come from the approving owner, so unlike SendCoins this is
+// not limited by the transaction send.
+func (osb OrigSendBanker) TransferFrom(spender, from, to crypto.Bech32Address, amt std.Coins) {
+	if spender != osb.pkgAddr {
+		panic(fmt.Sprintf(
+			"OrigSendBanker can only spend allowances of the realm package address %q, but got %q",
+			osb.pkgAddr, spender))
+	}
+	osb.banker.TransferFrom(spender, from, to, amt)
+}
+
+func (osb OrigSendBanker) GetDenomMetadata(denom string) (meta std.DenomMetadata) {
+	return osb.banker.GetDenomMetadata(denom)
+}
+
+func (osb OrigSendBanker) SetDenomMetadata(meta std.DenomMetadata) {
+	panic("OrigSendBanker cannot set denom metadata")
+}
+
+func (osb OrigSendBanker) GetDenomIssuer(denom string) crypto.Bech32Address {
+	return osb.banker.GetDenomIssuer(denom)
+}
+
+func (osb OrigSendBanker) SetDenomIssuer(denom string, issuer crypto.Bech32Address) {
+	panic("OrigSendBanker cannot set denom issuers")
+}
+
 //----------------------------------------
 // RealmSendBanker
 
@@ -160,3 +235,136 @@ func (rsb RealmSendBanker) IssueCoin(addr crypto.Bech32Address, denom string, am
 func (rsb RealmSendBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amount int64) {
 	panic("RealmSendBanker cannot remove coins")
 }
+
+func (rsb RealmSendBanker) Allowance(owner, spender crypto.Bech32Address) (allowance std.Coins) {
+	return rsb.banker.Allowance(owner, spender)
+}
+
+func (rsb RealmSendBanker) Approve(owner, spender crypto.Bech32Address, amt std.Coins) {
+	if owner != rsb.pkgAddr {
+		panic(fmt.Sprintf(
+			"RealmSendBanker can only approve from the realm package address %q, but got %q",
+			rsb.pkgAddr, owner))
+	}
+	rsb.banker.Approve(owner, spender, amt)
+}
+
+func (rsb RealmSendBanker) TransferFrom(spender, from, to crypto.Bech32Address, amt std.Coins) {
+	if spender != rsb.pkgAddr {
+		panic(fmt.Sprintf(
+			"RealmSendBanker can only spend allowances of the realm package address %q, but got %q",
+			rsb.pkgAddr, spender))
+	}
+	rsb.banker.TransferFrom(spender, from, to, amt)
+}
+
+func (rsb RealmSendBanker) GetDenomMetadata(denom string) (meta std.DenomMetadata) {
+	return rsb.banker.GetDenomMetadata(denom)
+}
+
+func (rsb RealmSendBanker) SetDenomMetadata(meta std.DenomMetadata) {
+	panic("RealmSendBanker cannot set denom metadata")
+}
+
+func (rsb RealmSendBanker) GetDenomIssuer(denom string) crypto.Bech32Address {
+	return rsb.banker.GetDenomIssuer(denom)
+}
+
+func (rsb RealmSendBanker) SetDenomIssuer(denom string, issuer crypto.Bech32Address) {
+	panic("RealmSendBanker cannot set denom issuers")
+}
+
+//----------------------------------------
+// RealmIssueBanker
+
+// Like the RealmSendBanker, but may also issue and remove the coins of the
+// denoms issued by the realm, and set their metadata.  The realm becomes
+// the issuer of a denom by issuing it first.
+type RealmIssueBanker struct {
+	banker  Banker
+	pkgAddr crypto.Bech32Address
+}
+
+func NewRealmIssueBanker(banker Banker, pkgAddr crypto.Bech32Address) RealmIssueBanker {
+	return RealmIssueBanker{
+		banker:  banker,
+		pkgAddr: pkgAddr,
+	}
+}
+
+func (rib RealmIssueBanker) GetCoins(addr crypto.Bech32Address) (dst std.Coins) {
+	return rib.banker.GetCoins(addr)
+}
+
+func (rib RealmIssueBanker) SendCoins(from, to crypto.Bech32Address, amt std.Coins) {
+	if from != rib.pkgAddr {
+		panic(fmt.Sprintf(
+			"RealmIssueBanker can only send from the realm package address %q, but got %q",
+			rib.pkgAddr, from))
+	}
+	rib.banker.SendCoins(from, to, amt)
+}
+
+func (rib RealmIssueBanker) TotalCoin(denom string) int64 {
+	return rib.banker.TotalCoin(denom)
+}
+
+func (rib RealmIssueBanker) IssueCoin(addr crypto.Bech32Address, denom string, amount int64) {
+	if rib.banker.GetDenomIssuer(denom) == "" {
+		rib.banker.SetDenomIssuer(denom, rib.pkgAddr)
+	}
+	rib.checkIssuer(denom)
+	rib.banker.IssueCoin(addr, denom, amount)
+}
+
+func (rib RealmIssueBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amount int64) {
+	rib.checkIssuer(denom)
+	rib.banker.RemoveCoin(addr, denom, amount)
+}
+
+func (rib RealmIssueBanker) Allowance(owner, spender crypto.Bech32Address) (allowance std.Coins) {
+	return rib.banker.Allowance(owner, spender)
+}
+
+func (rib RealmIssueBanker) Approve(owner, spender crypto.Bech32Address, amt std.Coins) {
+	if owner != rib.pkgAddr {
+		panic(fmt.Sprintf(
+			"RealmIssueBanker can only approve from the realm package address %q, but got %q",
+			rib.pkgAddr, owner))
+	}
+	rib.banker.Approve(owner, spender, amt)
+}
+
+func (rib RealmIssueBanker) TransferFrom(spender, from, to crypto.Bech32Address, amt std.Coins) {
+	if spender != rib.pkgAddr {
+		panic(fmt.Sprintf(
+			"RealmIssueBanker can only spend allowances of the realm package address %q, but got %q",
+			rib.pkgAddr, spender))
+	}
+	rib.banker.TransferFrom(spender, from, to, amt)
+}
+
+func (rib RealmIssueBanker) GetDenomMetadata(denom string) (meta std.DenomMetadata) {
+	return rib.banker.GetDenomMetadata(denom)
+}
+
+func (rib RealmIssueBanker) SetDenomMetadata(meta std.DenomMetadata) {
+	rib.checkIssuer(meta.Denom)
+	rib.banker.SetDenomMetadata(meta)
+}
+
+func (rib RealmIssueBanker) GetDenomIssuer(denom string) crypto.Bech32Address {
+	return rib.banker.GetDenomIssuer(denom)
+}
+
+func (rib RealmIssueBanker) SetDenomIssuer(denom string, issuer crypto.Bech32Address) {
+	panic("RealmIssueBanker cannot set denom issuers")
+}
+
+func (rib RealmIssueBanker) checkIssuer(denom string) {
+	if issuer := rib.banker.GetDenomIssuer(denom); issuer != rib.pkgAddr {
+		panic(fmt.Sprintf(
+			"RealmIssueBanker can only manage the denoms issued by the realm package address %q, but %s is issued by %q",
+			rib.pkgAddr, denom, issuer))
+	}
+}
@@ -1,6 +1,9 @@
 package stdlibs
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+
 	"github.com/gnolang/gno/pkgs/crypto"
 	"github.com/gnolang/gno/pkgs/sdk"
 	"github.com/gnolang/gno/pkgs/std"
@@ -10,10 +13,30 @@ type ExecContext struct {
 	ChainID       string
 	Height        int64
 	Timestamp     int64
+	BlockEntropy  []byte // hash of the last block, or of a future entropy beacon
 	Msg           sdk.Msg
 	OrigCaller    crypto.Bech32Address
 	OrigPkgAddr   crypto.Bech32Address
 	OrigSend      std.Coins
 	OrigSendSpent *std.Coins // mutable
 	Banker        Banker
+	Scheduler     Scheduler    // nil if calls cannot be scheduled
+	EventEmitter  EventEmitter // nil if events are dropped, e.g. in queries
+}
+
+// RandSeed returns the seed of std.RandSeed(salt) for the realm realmPath:
+// the hash of the block entropy, the height, the realm path and the salt.
+// The seed is deterministic, hence predictable by the validators, and by
+// anyone once the last block is committed.
+func (ctx ExecContext) RandSeed(realmPath string, salt []byte) int64 {
+	h := sha256.New()
+	var bz [8]byte
+	for _, part := range [][]byte{ctx.BlockEntropy, []byte(realmPath), salt} {
+		binary.BigEndian.PutUint64(bz[:], uint64(len(part)))
+		h.Write(bz[:])
+		h.Write(part)
+	}
+	binary.BigEndian.PutUint64(bz[:], uint64(ctx.Height))
+	h.Write(bz[:])
+	return int64(binary.BigEndian.Uint64(h.Sum(nil)))
 }
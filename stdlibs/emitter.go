@@ -0,0 +1,80 @@
+package stdlibs
+
+import (
+	"fmt"
+)
+
+// EventEmitter emits the events of realms, with std.Emit, as events of the
+// tx, which the node indexes and notifies to its subscribers.  The events
+// are validated and charged for before they are emitted; see
+// ValidateEvent.
+type EventEmitter interface {
+	Emit(pkgPath string, typ string, attrs []string)
+}
+
+// Limits of the events of std.Emit.
+const (
+	MaxEventTypeLen   = 64
+	MaxEventAttrs     = 32   // key/value pairs
+	MaxEventAttrsSize = 4096 // bytes, of the keys and values
+
+	GasEventFlat    int64 = 1000
+	GasEventPerByte int64 = 30
+)
+
+// ValidateEvent returns an error if the event of type typ and attributes
+// attrs, as key/value pairs, is not a valid event of std.Emit.  The type
+// and keys may only contain ASCII letters, digits, '_', '-' and '.'.
+func ValidateEvent(typ string, attrs []string) error {
+	if typ == "" {
+		return fmt.Errorf("event type cannot be empty")
+	}
+	if len(typ) > MaxEventTypeLen {
+		return fmt.Errorf("event type %q is longer than %d bytes", typ, MaxEventTypeLen)
+	}
+	if !isEventName(typ) {
+		return fmt.Errorf("invalid event type %q", typ)
+	}
+	if len(attrs)%2 != 0 {
+		return fmt.Errorf("event %s: odd number of attributes, expected key/value pairs", typ)
+	}
+	if len(attrs)/2 > MaxEventAttrs {
+		return fmt.Errorf("event %s: more than %d attributes", typ, MaxEventAttrs)
+	}
+	size := 0
+	for i := 0; i < len(attrs); i += 2 {
+		if !isEventName(attrs[i]) {
+			return fmt.Errorf("event %s: invalid attribute key %q", typ, attrs[i])
+		}
+		size += len(attrs[i]) + len(attrs[i+1])
+	}
+	if size > MaxEventAttrsSize {
+		return fmt.Errorf("event %s: attributes are larger than %d bytes", typ, MaxEventAttrsSize)
+	}
+	return nil
+}
+
+// EventGas returns the gas charged for the event of type typ and
+// attributes attrs.
+func EventGas(typ string, attrs []string) int64 {
+	size := len(typ)
+	for _, attr := range attrs {
+		size += len(attr)
+	}
+	return GasEventFlat + GasEventPerByte*int64(size)
+}
+
+func isEventName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range []byte(s) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '_' || c == '-' || c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package stdlibs
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/bn256"
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/gnolang/gno"
+)
+
+// A NativeBinding makes a whitelisted Go function callable from Gno as
+// PkgPath.Name, for primitives that would be too slow or too costly to
+// implement in Gno. The Gno signature is derived from the type of Func,
+// which may only use bool, string, integer types, and arrays and slices
+// of these, so that arguments and results convert deterministically.
+//
+// Each call is charged GasFlat plus GasPerByte for every byte of its
+// arguments, before Func runs. Func must not panic on any input.
+type NativeBinding struct {
+	PkgPath    string
+	Name       gno.Name
+	Func       interface{}
+	GasFlat    int64
+	GasPerByte int64
+}
+
+// NOTE: adding a binding changes the state transition function; gas
+// costs must be deterministic and roughly proportional to cpu time.
+var nativeBindings = []NativeBinding{
+	{"crypto/sha256", "Sum256", sha256.Sum256, 1000, 10},
+	{"crypto/keccak256", "Sum256", keccak256Sum256, 1000, 10},
+	{"crypto/ripemd160", "Sum160", ripemd160Sum160, 1000, 20},
+	{"crypto/ed25519", "Verify", ed25519Verify, 50000, 10},
+	{"crypto/secp256k1", "RecoverPubKey", secp256k1RecoverPubKey, 50000, 10},
+	{"crypto/bn256", "PairingCheck", bn256PairingCheck, 100000, 250},
+}
+
+// injectNativeBindings defines the natives bound to pn.PkgPath.
+func injectNativeBindings(pn *gno.PackageNode) {
+	for _, nb := range nativeBindings {
+		if nb.PkgPath == pn.PkgPath {
+			nb.define(pn)
+		}
+	}
+}
+
+func (nb NativeBinding) define(pn *gno.PackageNode) {
+	rv := reflect.ValueOf(nb.Func)
+	ft := rv.Type()
+	if ft.Kind() != reflect.Func || ft.IsVariadic() {
+		panic(fmt.Sprintf("native binding %s.%s: expected non-variadic func but got %v",
+			nb.PkgPath, nb.Name, ft))
+	}
+	params := make(gno.FieldTypeExprs, ft.NumIn())
+	for i := 0; i < ft.NumIn(); i++ {
+		params[i] = gno.FieldTypeExpr{
+			Name: gno.Name(fmt.Sprintf("p%d", i)),
+			Type: gno.X(nb.gnoTypeExpr(ft.In(i), false)),
+		}
+	}
+	results := make(gno.FieldTypeExprs, ft.NumOut())
+	for i := 0; i < ft.NumOut(); i++ {
+		results[i] = gno.FieldTypeExpr{
+			Name: gno.Name(fmt.Sprintf("r%d", i)),
+			Type: gno.X(nb.gnoTypeExpr(ft.Out(i), true)),
+		}
+	}
+	pn.DefineNative(nb.Name, params, results, func(m *gno.Machine) {
+		blk := m.LastBlock()
+		args := make([]reflect.Value, ft.NumIn())
+		size := int64(0)
+		for i := range args {
+			ptr := blk.GetPointerTo(nil, gno.NewValuePathBlock(1, uint16(i), ""))
+			args[i] = reflect.New(ft.In(i)).Elem()
+			gno.Gno2GoValue(ptr.TV, args[i])
+			size += nativeSize(args[i])
+		}
+		m.ConsumeGas(nb.GasFlat+nb.GasPerByte*size, "NativeCall")
+		for _, res := range rv.Call(args) {
+			m.PushValue(gno.Go2GnoValue(m.Alloc, m.Store, res))
+		}
+	})
+}
+
+// Returns the Gno type expression for rt, or panics if rt is not
+// supported. Results must be unnamed, as they are converted to Gno
+// values rather than wrapped as native values.
+func (nb NativeBinding) gnoTypeExpr(rt reflect.Type, result bool) string {
+	switch rt.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rt.Kind().String()
+	case reflect.Array, reflect.Slice:
+		if result && rt.PkgPath() != "" {
+			break
+		}
+		elem := nb.gnoTypeExpr(rt.Elem(), result)
+		if rt.Kind() == reflect.Array {
+			return fmt.Sprintf("[%d]%s", rt.Len(), elem)
+		}
+		return "[]" + elem
+	}
+	panic(fmt.Sprintf("native binding %s.%s: unsupported type %v",
+		nb.PkgPath, nb.Name, rt))
+}
+
+// Returns the size in bytes of rv for gas purposes.
+func nativeSize(rv reflect.Value) int64 {
+	switch rv.Kind() {
+	case reflect.String:
+		return int64(rv.Len())
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return int64(rv.Len())
+		}
+		size := int64(0)
+		for i := 0; i < rv.Len(); i++ {
+			size += nativeSize(rv.Index(i))
+		}
+		return size
+	default:
+		return int64(rv.Type().Size())
+	}
+}
+
+//----------------------------------------
+// bound functions
+
+// Returns the legacy Keccak-256 hash of data, as used by Ethereum, which
+// differs from the standard SHA3-256 by its padding.
+func keccak256Sum256(data []byte) (sum [32]byte) {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// Returns the RIPEMD-160 hash of data.
+func ripemd160Sum160(data []byte) (sum [20]byte) {
+	h := ripemd160.New()
+	h.Write(data)
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// Unlike ed25519.Verify, does not panic on a malformed public key.
+func ed25519Verify(publicKey, message, sig []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(publicKey, message, sig)
+}
+
+// Returns the compressed public key which made the secp256k1 signature sig
+// of hash, in the 65 bytes form [R || S || V] of Ethereum, with V 0 or 1
+// (or 27 or 28). Returns false if no key can be recovered.
+func secp256k1RecoverPubKey(hash [32]byte, sig []byte) ([]byte, bool) {
+	if len(sig) != 65 {
+		return nil, false
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, false
+	}
+	n := btcec.S256().N
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 {
+		return nil, false
+	}
+	// btcec expects the compact form [27+V || R || S].
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+	pub, _, err := btcec.RecoverCompact(btcec.S256(), compact, hash[:])
+	if err != nil {
+		return nil, false
+	}
+	return pub.SerializeCompressed(), true
+}
+
+// Returns true if the product of the pairings of the marshalled points
+// g1s[i] and g2s[i] is one. Malformed points make the check fail.
+func bn256PairingCheck(g1s, g2s [][]byte) bool {
+	if len(g1s) != len(g2s) {
+		return false
+	}
+	acc := new(bn256.GT)
+	for i := range g1s {
+		g1, ok := new(bn256.G1).Unmarshal(g1s[i])
+		if !ok {
+			return false
+		}
+		g2, ok := new(bn256.G2).Unmarshal(g2s[i])
+		if !ok {
+			return false
+		}
+		if i == 0 {
+			acc = bn256.Pair(g1, g2)
+		} else {
+			acc.Add(acc, bn256.Pair(g1, g2))
+		}
+	}
+	if len(g1s) == 0 {
+		return true
+	}
+	one := new(bn256.GT).ScalarMult(acc, big.NewInt(0))
+	return bytes.Equal(acc.Marshal(), one.Marshal())
+}
@@ -0,0 +1,11 @@
+package stdlibs
+
+// Scheduler schedules the calls of realms to their own functions at the
+// end of future blocks, with std.ScheduleCall, and queues the messages of
+// realms to other realms, delivered in order at the end of the block, with
+// std.SendMessage.  The native implementations panic if the call cannot be
+// scheduled, or the message cannot be sent.
+type Scheduler interface {
+	ScheduleCall(pkgPath string, height int64, fn string, arg string, gas int64)
+	SendMessage(from string, pkgPath string, fn string, arg string, gas int64)
+}
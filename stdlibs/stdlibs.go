@@ -16,9 +16,11 @@ func InjectNativeMappings(store gno.Store) {
 	store.AddGo2GnoMapping(reflect.TypeOf(crypto.Bech32Address("")), "std", "Address")
 	store.AddGo2GnoMapping(reflect.TypeOf(std.Coins{}), "std", "Coins")
 	store.AddGo2GnoMapping(reflect.TypeOf(std.Coin{}), "std", "Coin")
+	store.AddGo2GnoMapping(reflect.TypeOf(std.DenomMetadata{}), "std", "DenomMetadata")
 }
 
 func InjectPackage(store gno.Store, pn *gno.PackageNode) {
+	injectNativeBindings(pn)
 	switch pn.PkgPath {
 	case "internal/math":
 		pn.DefineNative("Float32bits",
@@ -101,6 +103,18 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 		pn.DefineGoNativeValue("QuoteToASCII", strconv.QuoteToASCII)
 		pn.DefineGoNativeValue("CanBackquote", strconv.CanBackquote)
 		pn.DefineGoNativeValue("IntSize", strconv.IntSize)
+		pn.DefineGoNativeValue("ParseBool", strconv.ParseBool)
+		pn.DefineGoNativeValue("FormatBool", strconv.FormatBool)
+		pn.DefineGoNativeValue("ParseInt", strconv.ParseInt)
+		pn.DefineGoNativeValue("ParseUint", strconv.ParseUint)
+		pn.DefineGoNativeValue("Unquote", strconv.Unquote)
+		pn.DefineGoNativeValue("QuoteRune", strconv.QuoteRune)
+		pn.DefineGoNativeValue("QuoteRuneToASCII", strconv.QuoteRuneToASCII)
+		pn.DefineGoNativeValue("ErrRange", strconv.ErrRange)
+		pn.DefineGoNativeValue("ErrSyntax", strconv.ErrSyntax)
+		pn.DefineGoNativeType(reflect.TypeOf(strconv.NumError{}))
+		// NOTE: ParseFloat and FormatFloat are intentionally omitted;
+		// realms should not depend on floating point formatting.
 	case "std":
 		// NOTE: some of these are overridden in tests/imports_test.go
 		// Also see stdlibs/InjectPackage.
@@ -204,6 +218,29 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 				m.PushValue(res0)
 			},
 		)
+		pn.DefineNative("RandSeed",
+			gno.Flds( // params
+				"salt", "[]byte",
+			),
+			gno.Flds( // results
+				"", "int64",
+			),
+			func(m *gno.Machine) {
+				arg0 := m.LastBlock().GetParams1().TV
+				salt := []byte(nil)
+				if arg0.V != nil {
+					slice := arg0.V.(*gno.SliceValue)
+					array := slice.GetBase(m.Store)
+					salt = array.GetReadonlyBytes()[slice.Offset : slice.Offset+slice.Length]
+				}
+				realmPath := ""
+				if m.Realm != nil {
+					realmPath = m.Realm.Path
+				}
+				ctx := m.Context.(ExecContext)
+				m.PushValue(typedInt64(ctx.RandSeed(realmPath, salt)))
+			},
+		)
 		pn.DefineNative("GetOrigSend",
 			gno.Flds( // params
 			),
@@ -320,7 +357,7 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 				case BankerTypeRealmSend:
 					banker = NewRealmSendBanker(banker, ctx.OrigPkgAddr)
 				case BankerTypeRealmIssue:
-					banker = banker
+					banker = NewRealmIssueBanker(banker, ctx.OrigPkgAddr)
 				default:
 					panic("should not happen") // defensive
 				}
@@ -336,6 +373,88 @@ func InjectPackage(store gno.Store, pn *gno.PackageNode) {
 				m.PushValue(res0)
 			},
 		)
+		pn.DefineNative("ScheduleCall",
+			gno.Flds( // params
+				"height", "int64",
+				"fn", "string",
+				"arg", "string",
+				"gas", "int64",
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				ctx := m.Context.(ExecContext)
+				if ctx.Scheduler == nil {
+					panic("calls cannot be scheduled here")
+				}
+				if m.Realm == nil {
+					panic("only realms can schedule calls")
+				}
+				arg0, arg1, arg2, arg3 := m.LastBlock().GetParams4()
+				ctx.Scheduler.ScheduleCall(
+					m.Realm.Path,
+					arg0.TV.GetInt64(),
+					arg1.TV.GetString(),
+					arg2.TV.GetString(),
+					arg3.TV.GetInt64(),
+				)
+			},
+		)
+		pn.DefineNative("SendMessage",
+			gno.Flds( // params
+				"pkgPath", "string",
+				"fn", "string",
+				"arg", "string",
+				"gas", "int64",
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				ctx := m.Context.(ExecContext)
+				if ctx.Scheduler == nil {
+					panic("messages cannot be sent here")
+				}
+				if m.Realm == nil {
+					panic("only realms can send messages")
+				}
+				arg0, arg1, arg2, arg3 := m.LastBlock().GetParams4()
+				ctx.Scheduler.SendMessage(
+					m.Realm.Path,
+					arg0.TV.GetString(),
+					arg1.TV.GetString(),
+					arg2.TV.GetString(),
+					arg3.TV.GetInt64(),
+				)
+			},
+		)
+		// Emit emits an event of the calling realm, of type typ and
+		// attributes attrs as key/value pairs, e.g.
+		// std.Emit("Transfer", "to", to.String()). See ValidateEvent.
+		pn.DefineNative("Emit",
+			gno.Flds( // params
+				"typ", "string",
+				"attrs", gno.Vrd("string"),
+			),
+			gno.Flds( // results
+			),
+			func(m *gno.Machine) {
+				if m.Realm == nil {
+					panic("only realms can emit events")
+				}
+				arg0, arg1 := m.LastBlock().GetParams2()
+				typ := arg0.TV.GetString()
+				var attrs []string
+				gno.Gno2GoValue(arg1.TV, reflect.ValueOf(&attrs).Elem())
+				if err := ValidateEvent(typ, attrs); err != nil {
+					panic(err.Error())
+				}
+				m.ConsumeGas(EventGas(typ, attrs), "Emit")
+				ctx := m.Context.(ExecContext)
+				if ctx.EventEmitter != nil {
+					ctx.EventEmitter.Emit(m.Realm.Path, typ, attrs)
+				}
+			},
+		)
 		// XXX DEPRECATED, use stdlibs/time instead
 		pn.DefineNative("GetTimestamp",
 			gno.Flds( // params
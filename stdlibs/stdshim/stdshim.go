@@ -34,6 +34,30 @@ func GetHeight() int64 {
 	return -1
 }
 
+// RandSeed returns a deterministic seed, e.g. for math/rand, from the hash
+// of the last block, the height, the realm path and salt. It is predictable
+// by the validators, and by anyone once the last block is committed: it
+// must not guard anything of value.
+func RandSeed(salt []byte) int64 {
+	panic(shimWarn)
+	return 0
+}
+
+// ScheduleCall schedules a call of fn(arg), a function of the calling realm,
+// at the end of the block of height, with gas prepaid by the current tx.
+// The call is made with the realm as the original caller.
+func ScheduleCall(height int64, fn string, arg string, gas int64) {
+	panic(shimWarn)
+}
+
+// SendMessage queues a message to the realm at pkgPath, a call of fn(arg),
+// delivered at the end of the current block, or of a later one, after the
+// messages sent before it, with gas prepaid by the current tx.  The call is
+// made with the sending realm as the original caller.
+func SendMessage(pkgPath string, fn string, arg string, gas int64) {
+	panic(shimWarn)
+}
+
 func GetOrigSend() Coins {
 	panic(shimWarn)
 	return Coins{}
@@ -3,6 +3,7 @@ package gno
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -49,6 +50,7 @@ type Store interface {
 	AddMemPackage(memPkg *std.MemPackage)
 	GetMemPackage(path string) *std.MemPackage
 	GetMemFile(path string, name string) *std.MemFile
+	ListMemPackagePaths(prefix string, start string, limit int) []string
 	IterMemPackage() <-chan *std.MemPackage
 	ClearObjectCache()                           // for each delivertx.
 	Fork() Store                                 // for checktx, simulate, and queries.
@@ -56,8 +58,15 @@ type Store interface {
 	SetPackageInjector(PackageInjector)          // for natives
 	SetLogStoreOps(enabled bool)
 	SprintStoreOps() string
-	LogSwitchRealm(rlmpath string) // to mark change of realm boundaries
+	LogSwitchRealm(rlmpath string)      // to mark change of realm boundaries
+	RealmStorageDiffs() map[PkgID]int64 // bytes added (or removed) per package
+	RealmObjectChanges() []RealmChanges // objects changed per realm
+	ResetRealmStorageDiffs()            // for each delivertx.
 	ClearCache()
+	Snapshot() *StoreSnapshot // for test isolation and dev resets.
+	Restore(*StoreSnapshot)
+	ExportRealm(pkgPath string) *RealmState // nil if not a realm.
+	ImportRealm(*RealmState)
 	Print()
 }
 
@@ -66,6 +75,8 @@ type defaultStore struct {
 	alloc            *Allocator    // for accounting for cached items
 	pkgGetter        PackageGetter // non-realm packages
 	cacheObjects     map[ObjectID]Object
+	cacheSizes       map[ObjectID]int64 // persisted sizes of cached objects.
+	objectCache      *objectCache       // across transactions.
 	cacheTypes       map[TypeID]Type
 	cacheNodes       map[Location]BlockNode
 	cacheNativeTypes map[reflect.Type]Type // go spec: reflect.Type are comparable
@@ -76,8 +87,10 @@ type defaultStore struct {
 	go2gnoStrict     bool                  // if true, native->gno type conversion must be registered.
 
 	// transient
-	opslog  []StoreOp           // for debugging and testing.
-	current map[string]struct{} // for detecting import cycles.
+	opslog    []StoreOp           // for debugging and testing.
+	current   map[string]struct{} // for detecting import cycles.
+	sizeDiffs map[PkgID]int64     // for storage accounting.
+	changes   objectChanges       // since the last reset of diffs.
 }
 
 func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore {
@@ -85,6 +98,8 @@ func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore
 		alloc:            alloc,
 		pkgGetter:        nil,
 		cacheObjects:     make(map[ObjectID]Object),
+		cacheSizes:       make(map[ObjectID]int64),
+		objectCache:      newObjectCache(objectCacheSize),
 		cacheTypes:       make(map[TypeID]Type),
 		cacheNodes:       make(map[Location]BlockNode),
 		cacheNativeTypes: make(map[reflect.Type]Type),
@@ -93,6 +108,8 @@ func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore
 		go2gnoMap:        make(map[string]string),
 		go2gnoStrict:     true,
 		current:          make(map[string]struct{}),
+		sizeDiffs:        make(map[PkgID]int64),
+		changes:          newObjectChanges(),
 	}
 	InitStoreCaches(ds)
 	return ds
@@ -275,17 +292,23 @@ func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
 	if hashbz != nil {
 		hash := hashbz[:HashSize]
 		bz := hashbz[HashSize:]
-		var oo Object
+		// NOTE: allocate even if cached, as allocations must
+		// not depend on the state of the cache.
 		ds.alloc.AllocateAmino(int64(len(bz)))
-		amino.MustUnmarshal(bz, &oo)
-		if debug {
-			if oo.GetObjectID() != oid {
-				panic(fmt.Sprintf("unexpected object id: expected %v but got %v",
-					oid, oo.GetObjectID()))
+		oo := ds.objectCache.get(oid, hash)
+		if oo == nil {
+			amino.MustUnmarshal(bz, &oo)
+			if debug {
+				if oo.GetObjectID() != oid {
+					panic(fmt.Sprintf("unexpected object id: expected %v but got %v",
+						oid, oo.GetObjectID()))
+				}
 			}
+			ds.objectCache.add(oid, hash, oo)
 		}
 		oo.SetHash(ValueHash{NewHashlet(hash)})
 		ds.cacheObjects[oid] = oo
+		ds.cacheSizes[oid] = int64(len(hashbz))
 		_ = fillTypesOfValue(ds, oo)
 		return oo
 	}
@@ -306,14 +329,23 @@ func (ds *defaultStore) SetObject(oo Object) {
 		panic("should not happen")
 	}
 	oo.SetHash(ValueHash{hash})
+	// invalidate persistent cache.
+	ds.objectCache.remove(oid)
 	// save bytes to backend.
 	if ds.baseStore != nil {
 		key := backendObjectKey(oid)
 		hashbz := make([]byte, len(hash)+len(bz))
 		copy(hashbz, hash.Bytes())
 		copy(hashbz[HashSize:], bz)
+		ds.sizeDiffs[oid.PkgID] += int64(len(hashbz)) - ds.objectSize(oo)
+		ds.cacheSizes[oid] = int64(len(hashbz))
 		ds.baseStore.Set([]byte(key), hashbz)
 	}
+	if oo.GetIsNewReal() {
+		ds.changes.add(oid, StoreOpNew)
+	} else {
+		ds.changes.add(oid, StoreOpMod)
+	}
 	// save object to cache.
 	if debug {
 		if oid.IsZero() {
@@ -350,13 +382,17 @@ func (ds *defaultStore) SetObject(oo Object) {
 
 func (ds *defaultStore) DelObject(oo Object) {
 	oid := oo.GetObjectID()
-	// delete from cache.
-	delete(ds.cacheObjects, oid)
 	// delete from backend.
 	if ds.baseStore != nil {
 		key := backendObjectKey(oid)
+		ds.sizeDiffs[oid.PkgID] -= ds.objectSize(oo)
 		ds.baseStore.Delete([]byte(key))
 	}
+	// delete from caches.
+	delete(ds.cacheObjects, oid)
+	delete(ds.cacheSizes, oid)
+	ds.objectCache.remove(oid)
+	ds.changes.add(oid, StoreOpDel)
 	// make realm op log entry
 	if ds.opslog != nil {
 		ds.opslog = append(ds.opslog,
@@ -364,6 +400,23 @@ func (ds *defaultStore) DelObject(oo Object) {
 	}
 }
 
+// Returns the persisted size of oo, for storage accounting.  Sizes are
+// recorded as objects are loaded and saved, so that realm writes are not
+// charged for an extra read of the backend; objects never persisted have
+// none.
+func (ds *defaultStore) objectSize(oo Object) int64 {
+	oid := oo.GetObjectID()
+	if size, exists := ds.cacheSizes[oid]; exists {
+		return size
+	}
+	if oo.GetIsNewReal() {
+		return 0
+	}
+	// e.g. package values gotten from the package getter.
+	bz := ds.baseStore.Get([]byte(backendObjectKey(oid)))
+	return int64(len(bz))
+}
+
 // NOTE: not used quite yet.
 // NOTE: The implementation matches that of GetObject() in anticipation of what
 // the persistent type system might work like.
@@ -529,6 +582,7 @@ func (ds *defaultStore) AddMemPackage(memPkg *std.MemPackage) {
 	ds.baseStore.Set(idxkey, []byte(memPkg.Path))
 	pathkey := []byte(backendPackagePathKey(memPkg.Path))
 	ds.iavlStore.Set(pathkey, bz)
+	ds.sizeDiffs[PkgIDFromPkgPath(memPkg.Path)] += int64(len(bz))
 }
 
 func (ds *defaultStore) GetMemPackage(path string) *std.MemPackage {
@@ -549,6 +603,31 @@ func (ds *defaultStore) GetMemFile(path string, name string) *std.MemFile {
 	return memFile
 }
 
+// Returns up to limit paths of mem packages starting with prefix, in
+// lexicographic order, starting at start if not empty.  A limit of zero
+// or less means no limit.
+func (ds *defaultStore) ListMemPackagePaths(prefix string, start string, limit int) []string {
+	startkey := []byte(backendPackagePathKey(prefix))
+	endkey := store.PrefixEndBytes(startkey)
+	if start > prefix {
+		if !strings.HasPrefix(start, prefix) {
+			return []string{} // past all paths with prefix.
+		}
+		startkey = []byte(backendPackagePathKey(start))
+	}
+	iter := ds.iavlStore.Iterator(startkey, endkey)
+	defer iter.Close()
+	paths := []string{}
+	for ; iter.Valid(); iter.Next() {
+		if limit > 0 && len(paths) >= limit {
+			break
+		}
+		path := strings.TrimPrefix(string(iter.Key()), backendPackagePathKey(""))
+		paths = append(paths, path)
+	}
+	return paths
+}
+
 func (ds *defaultStore) IterMemPackage() <-chan *std.MemPackage {
 	ctrkey := []byte(backendPackageIndexCtrKey())
 	ctrbz := ds.baseStore.Get(ctrkey)
@@ -583,7 +662,8 @@ func (ds *defaultStore) IterMemPackage() <-chan *std.MemPackage {
 func (ds *defaultStore) ClearObjectCache() {
 	ds.alloc.Reset()
 	ds.cacheObjects = make(map[ObjectID]Object) // new cache.
-	ds.opslog = nil                             // new ops log.
+	ds.cacheSizes = make(map[ObjectID]int64)
+	ds.opslog = nil // new ops log.
 	if len(ds.current) > 0 {
 		ds.current = make(map[string]struct{})
 	}
@@ -597,6 +677,8 @@ func (ds *defaultStore) Fork() Store {
 		alloc:            ds.alloc.Fork().Reset(),
 		pkgGetter:        ds.pkgGetter,
 		cacheObjects:     make(map[ObjectID]Object), // new cache.
+		cacheSizes:       make(map[ObjectID]int64),
+		objectCache:      ds.objectCache,
 		cacheTypes:       ds.cacheTypes,
 		cacheNodes:       ds.cacheNodes,
 		cacheNativeTypes: ds.cacheNativeTypes,
@@ -607,6 +689,8 @@ func (ds *defaultStore) Fork() Store {
 		go2gnoStrict:     ds.go2gnoStrict,
 		opslog:           nil, // new ops log.
 		current:          make(map[string]struct{}),
+		sizeDiffs:        make(map[PkgID]int64),
+		changes:          newObjectChanges(),
 	}
 	ds2.SetCachePackage(Uverse())
 	return ds2
@@ -692,10 +776,116 @@ func (ds *defaultStore) SprintStoreOps() string {
 func (ds *defaultStore) LogSwitchRealm(rlmpath string) {
 	ds.opslog = append(ds.opslog,
 		StoreOp{Type: StoreOpSwitchRealm, RlmPath: rlmpath})
+	ds.changes.setPath(rlmpath)
+}
+
+// Returns the number of bytes stored (or removed, if negative) per package
+// since the last reset.  Only objects and mem packages are accounted for.
+func (ds *defaultStore) RealmStorageDiffs() map[PkgID]int64 {
+	return ds.sizeDiffs
+}
+
+// Returns the objects created, updated and deleted per realm since the last
+// reset of diffs, in order of the first change to each realm.
+func (ds *defaultStore) RealmObjectChanges() []RealmChanges {
+	return ds.changes.list()
+}
+
+func (ds *defaultStore) ResetRealmStorageDiffs() {
+	ds.sizeDiffs = make(map[PkgID]int64)
+	ds.changes = newObjectChanges()
+}
+
+// RealmChanges are the IDs of the objects of the realm at Path created,
+// updated and deleted, e.g. by a transaction.  Objects created and then
+// deleted are omitted, and objects created are not also updated.  Each list
+// is ordered by creation time.
+type RealmChanges struct {
+	Path    string
+	Created []ObjectID
+	Updated []ObjectID
+	Deleted []ObjectID
+}
+
+// Changes to persisted objects, as StoreOpNew, StoreOpMod or StoreOpDel.
+type objectChanges struct {
+	ops   map[ObjectID]StoreOpType
+	order []ObjectID       // of first change.
+	paths map[PkgID]string // of the realms switched to.
+}
+
+func newObjectChanges() objectChanges {
+	return objectChanges{
+		ops:   make(map[ObjectID]StoreOpType),
+		paths: make(map[PkgID]string),
+	}
+}
+
+func (oc objectChanges) setPath(rlmpath string) {
+	pkgID := PkgIDFromPkgPath(rlmpath)
+	if _, ok := oc.paths[pkgID]; !ok {
+		oc.paths[pkgID] = rlmpath
+	}
+}
+
+func (oc *objectChanges) add(oid ObjectID, op StoreOpType) {
+	old, exists := oc.ops[oid]
+	switch {
+	case !exists:
+		oc.order = append(oc.order, oid)
+		oc.ops[oid] = op
+	case old == StoreOpNew && op == StoreOpDel:
+		delete(oc.ops, oid)
+	case old == StoreOpNew:
+		// still new.
+	default:
+		oc.ops[oid] = op
+	}
+}
+
+func (oc objectChanges) list() []RealmChanges {
+	changes := []RealmChanges{}
+	index := make(map[PkgID]int)
+	for _, oid := range oc.order {
+		op, ok := oc.ops[oid]
+		if !ok {
+			continue // created and deleted.
+		}
+		i, ok := index[oid.PkgID]
+		if !ok {
+			i = len(changes)
+			index[oid.PkgID] = i
+			changes = append(changes, RealmChanges{Path: oc.paths[oid.PkgID]})
+		}
+		rc := &changes[i]
+		switch op {
+		case StoreOpNew:
+			rc.Created = append(rc.Created, oid)
+		case StoreOpMod:
+			rc.Updated = append(rc.Updated, oid)
+		case StoreOpDel:
+			rc.Deleted = append(rc.Deleted, oid)
+		}
+	}
+	for _, rc := range changes {
+		sortObjectIDs(rc.Created)
+		sortObjectIDs(rc.Updated)
+		sortObjectIDs(rc.Deleted)
+	}
+	return changes
+}
+
+// Sorts oids of the same realm by creation time.
+func sortObjectIDs(oids []ObjectID) {
+	sort.Slice(oids, func(i, j int) bool {
+		return oids[i].NewTime < oids[j].NewTime
+	})
 }
 
 func (ds *defaultStore) ClearCache() {
 	ds.cacheObjects = make(map[ObjectID]Object)
+	ds.cacheSizes = make(map[ObjectID]int64)
+	ds.objectCache.clear()
 	ds.cacheTypes = make(map[TypeID]Type)
 	ds.cacheNodes = make(map[Location]BlockNode)
 	ds.cacheNativeTypes = make(map[reflect.Type]Type)
@@ -703,6 +893,203 @@ func (ds *defaultStore) ClearCache() {
 	InitStoreCaches(ds)
 }
 
+// StoreSnapshot is the state of a store at the time of Snapshot(), to which
+// it may be restored any number of times with Restore().
+type StoreSnapshot struct {
+	baseStore        store.Store         // never written to after the snapshot.
+	iavlStore        store.Store         // ditto.
+	cacheObjects     map[ObjectID]Object // objects not persisted, e.g. natives.
+	cacheTypes       map[TypeID]Type
+	cacheNodes       map[Location]BlockNode
+	cacheNativeTypes map[reflect.Type]Type
+	go2gnoMap        map[string]string
+}
+
+// Unstable.
+// Snapshot returns a snapshot of the store, for tests to be isolated from
+// each other and for gnodev to reset its state, without rebuilding the
+// store.  From then on, writes to the backend go to a cache wrapping it,
+// which Restore() discards; real objects are reloaded from the backend, so
+// the store must be persisted, i.e. there must be no unsaved changes to
+// real objects.  Block nodes are not copied, so those of the snapshot must
+// not be modified afterwards.
+func (ds *defaultStore) Snapshot() *StoreSnapshot {
+	snap := &StoreSnapshot{
+		baseStore:        ds.baseStore,
+		iavlStore:        ds.iavlStore,
+		cacheObjects:     make(map[ObjectID]Object),
+		cacheTypes:       copyTypesMap(ds.cacheTypes),
+		cacheNodes:       copyNodesMap(ds.cacheNodes),
+		cacheNativeTypes: copyNativeTypesMap(ds.cacheNativeTypes),
+		go2gnoMap:        copyStringsMap(ds.go2gnoMap),
+	}
+	for oid, oo := range ds.cacheObjects {
+		if !oo.GetIsReal() {
+			snap.cacheObjects[oid] = oo
+		}
+	}
+	ds.Restore(snap)
+	return snap
+}
+
+// Unstable.
+// Restore restores the store to the state of snap, which must be a snapshot
+// of it.  Objects gotten before are stale, and must be gotten again.
+func (ds *defaultStore) Restore(snap *StoreSnapshot) {
+	ds.baseStore = cacheWrap(snap.baseStore)
+	ds.iavlStore = cacheWrap(snap.iavlStore)
+	ds.cacheObjects = make(map[ObjectID]Object, len(snap.cacheObjects))
+	for oid, oo := range snap.cacheObjects {
+		ds.cacheObjects[oid] = oo
+	}
+	ds.cacheSizes = make(map[ObjectID]int64)
+	ds.cacheTypes = copyTypesMap(snap.cacheTypes)
+	ds.cacheNodes = copyNodesMap(snap.cacheNodes)
+	ds.cacheNativeTypes = copyNativeTypesMap(snap.cacheNativeTypes)
+	ds.go2gnoMap = copyStringsMap(snap.go2gnoMap)
+	ds.alloc.Reset()
+	if ds.opslog != nil {
+		ds.ResetStoreOps() // still enabled.
+	}
+	ds.current = make(map[string]struct{})
+	ds.sizeDiffs = make(map[PkgID]int64)
+	ds.changes = newObjectChanges()
+}
+
+func cacheWrap(st store.Store) store.Store {
+	if st == nil {
+		return nil
+	}
+	return st.CacheWrap()
+}
+
+func copyTypesMap(m map[TypeID]Type) map[TypeID]Type {
+	m2 := make(map[TypeID]Type, len(m))
+	for k, v := range m {
+		m2[k] = v
+	}
+	return m2
+}
+
+func copyNodesMap(m map[Location]BlockNode) map[Location]BlockNode {
+	m2 := make(map[Location]BlockNode, len(m))
+	for k, v := range m {
+		m2[k] = v
+	}
+	return m2
+}
+
+func copyNativeTypesMap(m map[reflect.Type]Type) map[reflect.Type]Type {
+	m2 := make(map[reflect.Type]Type, len(m))
+	for k, v := range m {
+		m2[k] = v
+	}
+	return m2
+}
+
+func copyStringsMap(m map[string]string) map[string]string {
+	m2 := make(map[string]string, len(m))
+	for k, v := range m {
+		m2[k] = v
+	}
+	return m2
+}
+
+// Unstable.
+// ExportRealm returns the persisted state of the realm at pkgPath, or nil
+// if there is no such realm.  Objects are read from the backend, so unsaved
+// changes are not exported.
+func (ds *defaultStore) ExportRealm(pkgPath string) *RealmState {
+	rlm := ds.GetPackageRealm(pkgPath)
+	if rlm == nil {
+		return nil
+	}
+	state := &RealmState{Realm: rlm, Objects: []Object{}}
+	prefix := []byte(backendRealmObjectsPrefix(rlm.ID))
+	iter := store.PrefixIterator(ds.baseStore, prefix)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if strings.HasSuffix(string(iter.Key()), "#realm") {
+			continue
+		}
+		var oo Object
+		amino.MustUnmarshal(iter.Value()[HashSize:], &oo)
+		state.Objects = append(state.Objects, oo)
+	}
+	// keys are ordered lexicographically, e.g. 10 before 2.
+	sort.Slice(state.Objects, func(i, j int) bool {
+		return state.Objects[i].GetObjectID().NewTime <
+			state.Objects[j].GetObjectID().NewTime
+	})
+	return state
+}
+
+// Unstable.
+// ImportRealm replaces the persisted state of the realm of state with it,
+// deleting all of its other objects.  The package of the realm must be the
+// same as that of the exported state, for objects refer to its types and
+// nodes.  Objects of the realm gotten before are stale, and must be gotten
+// again.
+func (ds *defaultStore) ImportRealm(state *RealmState) {
+	rlm := state.Realm
+	if rlm.ID != PkgIDFromPkgPath(rlm.Path) {
+		panic(fmt.Sprintf("unexpected realm id %v of realm %s",
+			rlm.ID, rlm.Path))
+	}
+	// delete the objects of the realm, with their hashes.
+	for _, key := range prefixKeys(ds.baseStore, backendRealmObjectsPrefix(rlm.ID)) {
+		if strings.HasSuffix(key, "#realm") {
+			continue
+		}
+		oldbz := ds.baseStore.Get([]byte(key))
+		ds.sizeDiffs[rlm.ID] -= int64(len(oldbz))
+		ds.baseStore.Delete([]byte(key))
+	}
+	if ds.iavlStore != nil {
+		for _, key := range prefixKeys(ds.iavlStore, escapedRealmObjectsPrefix(rlm.ID)) {
+			ds.iavlStore.Delete([]byte(key))
+		}
+	}
+	for oid := range ds.cacheObjects {
+		if oid.PkgID == rlm.ID {
+			delete(ds.cacheObjects, oid)
+			delete(ds.cacheSizes, oid)
+			ds.objectCache.remove(oid)
+		}
+	}
+	// set the objects of state, as SetObject() does.
+	for _, oo := range state.Objects {
+		oid := oo.GetObjectID()
+		if oid.PkgID != rlm.ID {
+			panic(fmt.Sprintf("unexpected object %v of realm %s",
+				oid, rlm.Path))
+		}
+		bz := amino.MustMarshalAny(oo)
+		hash := HashBytes(bz)
+		hashbz := make([]byte, len(hash)+len(bz))
+		copy(hashbz, hash.Bytes())
+		copy(hashbz[HashSize:], bz)
+		ds.objectCache.remove(oid)
+		ds.sizeDiffs[rlm.ID] += int64(len(hashbz))
+		ds.baseStore.Set([]byte(backendObjectKey(oid)), hashbz)
+		if oo.GetIsEscaped() && ds.iavlStore != nil {
+			ds.iavlStore.Set([]byte(oid.String()), hash.Bytes())
+		}
+	}
+	ds.SetPackageRealm(rlm)
+}
+
+// Returns the keys of st with prefix.
+func prefixKeys(st store.Store, prefix string) []string {
+	iter := store.PrefixIterator(st, []byte(prefix))
+	defer iter.Close()
+	keys := []string{}
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	return keys
+}
+
 // for debugging
 func (ds *defaultStore) Print() {
 	fmt.Println("//----------------------------------------")
@@ -730,6 +1117,18 @@ func backendObjectKey(oid ObjectID) string {
 	return "oid:" + oid.String()
 }
 
+// prefix of the keys of the objects of a realm, and of its realm key.
+func backendRealmObjectsPrefix(pkgID PkgID) string {
+	pid, _ := pkgID.MarshalAmino()
+	return "oid:" + pid + ":"
+}
+
+// prefix of the keys of the hashes of the escaped objects of a realm.
+func escapedRealmObjectsPrefix(pkgID PkgID) string {
+	pid, _ := pkgID.MarshalAmino()
+	return pid + ":"
+}
+
 // oid: associated package value object id.
 func backendRealmKey(oid ObjectID) string {
 	return "oid:" + oid.String() + "#realm"
@@ -0,0 +1,119 @@
+package gno
+
+import (
+	"testing"
+
+	"github.com/jaekwon/testify/assert"
+
+	"github.com/gnolang/gno/pkgs/amino"
+	dbm "github.com/gnolang/gno/pkgs/db"
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store/dbadapter"
+	"github.com/gnolang/gno/pkgs/store/iavl"
+	stypes "github.com/gnolang/gno/pkgs/store/types"
+)
+
+func TestMachineSnapshot(t *testing.T) {
+	db := dbm.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	store := NewStore(nil, baseStore, iavlStore)
+	m := NewMachine("gno.land/r/snap", store)
+	m.RunFiles(MustParseFile("snap.gno", `package snap
+
+var counter int
+var list []int
+
+func Inc() int {
+	counter++
+	list = append(list, counter)
+	return counter
+}
+
+func Len() int {
+	return len(list)
+}`))
+	snap := m.Snapshot()
+	assert.True(t, m.Package.GetIsReal())
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, m.Eval(Call("Inc"))[0].GetInt(), 1)
+		assert.Equal(t, m.Eval(Call("Inc"))[0].GetInt(), 2)
+		// persist the changes, as a realm call would.
+		m.Realm.FinalizeRealmTransaction(false, store)
+		assert.Equal(t, m.Eval(Call("Len"))[0].GetInt(), 2)
+		m.Restore(snap)
+		assert.Equal(t, m.Eval(Call("Len"))[0].GetInt(), 0)
+	}
+
+	// the backend is unchanged since the snapshot.
+	m.Eval(Call("Inc"))
+	m.Realm.FinalizeRealmTransaction(false, store)
+	m2 := NewMachine("", NewStore(nil, baseStore, iavlStore))
+	pv := m2.Store.GetPackage("gno.land/r/snap", false)
+	assert.Equal(t, pv.GetBlock(m2.Store).Values[0].GetInt(), 0)
+}
+
+func TestRealmExportImport(t *testing.T) {
+	const file = `package fork
+
+var counter int
+var list []*int
+
+func Inc() int {
+	counter++
+	c := counter
+	list = append(list, &c)
+	return counter
+}
+
+func Sum() int {
+	sum := 0
+	for _, c := range list {
+		sum += *c
+	}
+	return sum
+}`
+	memPkg := &std.MemPackage{
+		Name:  "fork",
+		Path:  "gno.land/r/fork",
+		Files: []*std.MemFile{{Name: "fork.gno", Body: file}},
+	}
+	newStore := func() Store {
+		db := dbm.NewMemDB()
+		baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+		iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+		return NewStore(nil, baseStore, iavlStore)
+	}
+
+	store := newStore()
+	m := NewMachine("gno.land/r/fork", store)
+	m.RunMemPackage(memPkg, true)
+	for i := 0; i < 3; i++ {
+		m.Eval(Call("Inc"))
+		m.Realm.FinalizeRealmTransaction(false, store)
+	}
+	assert.Nil(t, store.ExportRealm("gno.land/r/none"))
+	state := store.ExportRealm("gno.land/r/fork")
+	assert.Equal(t, state.Realm.Path, "gno.land/r/fork")
+	for i := 1; i < len(state.Objects); i++ {
+		assert.True(t, state.Objects[i-1].GetObjectID().NewTime <
+			state.Objects[i].GetObjectID().NewTime)
+	}
+	// the state survives a round trip through JSON.
+	var state2 RealmState
+	amino.MustUnmarshalJSON(amino.MustMarshalJSON(state), &state2)
+
+	// import into a store with the same package, whose state differs.
+	store2 := newStore()
+	m2 := NewMachine("gno.land/r/fork", store2)
+	m2.RunMemPackage(memPkg, true)
+	m2.Eval(Call("Inc"))
+	m2.Realm.FinalizeRealmTransaction(false, store2)
+	store2.ImportRealm(&state2)
+	m3 := NewMachine("gno.land/r/fork", store2)
+	assert.Equal(t, m3.Eval(Call("Sum"))[0].GetInt(), 6)
+	assert.Equal(t, m3.Eval(Call("Inc"))[0].GetInt(), 4)
+	m3.Realm.FinalizeRealmTransaction(false, store2)
+	assert.Equal(t, store2.GetPackageRealm("gno.land/r/fork").Time, state.Realm.Time+2)
+}
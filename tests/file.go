@@ -408,7 +408,10 @@ func trimTrailingSpaces(result string) string {
 // testBanker
 
 type testBanker struct {
-	coinTable map[crypto.Bech32Address]std.Coins
+	coinTable  map[crypto.Bech32Address]std.Coins
+	allowances map[[2]crypto.Bech32Address]std.Coins // [owner, spender]
+	denomMetas map[string]std.DenomMetadata
+	issuers    map[string]crypto.Bech32Address
 }
 
 func newTestBanker(args ...interface{}) *testBanker {
@@ -422,7 +425,10 @@ func newTestBanker(args ...interface{}) *testBanker {
 		coinTable[addr] = amount
 	}
 	return &testBanker{
-		coinTable: coinTable,
+		coinTable:  coinTable,
+		allowances: make(map[[2]crypto.Bech32Address]std.Coins),
+		denomMetas: make(map[string]std.DenomMetadata),
+		issuers:    make(map[string]crypto.Bech32Address),
 	}
 }
 
@@ -467,3 +473,46 @@ func (tb *testBanker) RemoveCoin(addr crypto.Bech32Address, denom string, amt in
 	rest := coins.Sub(std.Coins{{denom, amt}})
 	tb.coinTable[addr] = rest
 }
+
+func (tb *testBanker) Allowance(owner, spender crypto.Bech32Address) (allowance std.Coins) {
+	return tb.allowances[[2]crypto.Bech32Address{owner, spender}]
+}
+
+func (tb *testBanker) Approve(owner, spender crypto.Bech32Address, amt std.Coins) {
+	tb.allowances[[2]crypto.Bech32Address{owner, spender}] = amt
+}
+
+func (tb *testBanker) TransferFrom(spender, from, to crypto.Bech32Address, amt std.Coins) {
+	key := [2]crypto.Bech32Address{from, spender}
+	allowance := tb.allowances[key]
+	if !allowance.IsAllGTE(amt) {
+		panic(fmt.Sprintf(
+			"allowance %s of %s for %s is less than %s",
+			allowance, from, spender, amt))
+	}
+	tb.SendCoins(from, to, amt)
+	tb.allowances[key] = allowance.Sub(amt)
+}
+
+func (tb *testBanker) GetDenomMetadata(denom string) (meta std.DenomMetadata) {
+	meta, ok := tb.denomMetas[denom]
+	if !ok {
+		return std.DenomMetadata{Denom: denom}
+	}
+	return meta
+}
+
+func (tb *testBanker) SetDenomMetadata(meta std.DenomMetadata) {
+	if err := meta.ValidateBasic(); err != nil {
+		panic(err)
+	}
+	tb.denomMetas[meta.Denom] = meta
+}
+
+func (tb *testBanker) GetDenomIssuer(denom string) crypto.Bech32Address {
+	return tb.issuers[denom]
+}
+
+func (tb *testBanker) SetDenomIssuer(denom string, issuer crypto.Bech32Address) {
+	tb.issuers[denom] = issuer
+}
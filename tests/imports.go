@@ -30,7 +30,6 @@ import (
 	"path/filepath"
 	"reflect"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -432,11 +431,6 @@ func testPackageInjector(store gno.Store, pn *gno.PackageNode) {
 	stdlibs.InjectPackage(store, pn)
 	// Test specific injections:
 	switch pn.PkgPath {
-	case "strconv":
-		// NOTE: Itoa and Atoi are already injected
-		// from stdlibs.InjectNatives.
-		pn.DefineGoNativeType(reflect.TypeOf(strconv.NumError{}))
-		pn.DefineGoNativeValue("ParseInt", strconv.ParseInt)
 	case "std":
 		// NOTE: some of these are overrides.
 		// Also see stdlibs/InjectPackage.
@@ -0,0 +1,3 @@
+module gno.land/r/mod
+
+require gno.land/r/users v2
@@ -0,0 +1,5 @@
+module gno.land/r/mod2
+
+require gno.land/r/users v2
+
+replace gno.land/p/avl => ../../../examples/gno.land/p/avl
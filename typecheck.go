@@ -0,0 +1,45 @@
+package gno
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/pkgs/std"
+	"github.com/gnolang/gno/pkgs/store"
+)
+
+// FileCheck checks a preprocessed file, e.g. for the policies of a chain,
+// and returns an error prefixed with the file name and line if it fails.
+type FileCheck func(fn *FileNode) error
+
+// TypeCheckMemPackage type-checks the files of memPkg against the packages
+// of gstore, as done by the preprocessor, and returns the first error, e.g.
+// of parsing, of an undeclared name or of mismatched types, or of checks.
+// The package is preprocessed in a fork of gstore, and neither run nor
+// saved.  Running out of gas on the reads of gstore still panics.
+func TypeCheckMemPackage(memPkg *std.MemPackage, gstore Store, checks ...FileCheck) (err error) {
+	gstore = gstore.Fork()
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case store.OutOfGasException:
+				panic(r)
+			case error:
+				err = r
+			default:
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	fset := ParseMemPackage(memPkg)
+	pn := NewPackageNode(Name(memPkg.Name), memPkg.Path, fset)
+	PredefineFileSet(gstore, pn, fset)
+	for _, fn := range fset.Files {
+		fn = Preprocess(gstore, pn, fn).(*FileNode)
+		for _, check := range checks {
+			if err := check(fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
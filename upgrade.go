@@ -0,0 +1,397 @@
+package gno
+
+import (
+	"github.com/gnolang/gno/pkgs/errors"
+)
+
+// CarryOverVars carries the state of the package prev over to the package
+// pv, e.g. of a realm over to its next version before its migrate() runs.
+// Each variable of prev is copied into the variable of pv declared with the
+// same name, which must have the same type; the types declared in prev are
+// those declared in pv with the same names, which must be structurally
+// identical.  To change the type of a variable, pv must rename it, and
+// migrate it otherwise.  The variables of prev not declared in pv are
+// dropped, and the other variables of pv are left as initialized.  The
+// values are deep-copied, so that pv owns all of its state and prev is left
+// unchanged.  Functions and pointers to variables can't be copied, and
+// return an error.  Returns the names of the variables carried over.
+func (m *Machine) CarryOverVars(prev *PackageValue, pv *PackageValue) (names []Name, err error) {
+	store := m.Store
+	ppn := prev.GetPackageNode(store)
+	pn := pv.GetPackageNode(store)
+	pblock := prev.GetBlock(store)
+	block := pv.GetBlock(store)
+	vc := &varCopier{
+		store: store,
+		alloc: m.Alloc,
+		types: &typeMapper{
+			store:    store,
+			prevPath: prev.PkgPath,
+			pv:       pv,
+			mapped:   make(map[TypeID]Type),
+		},
+		copies: make(map[Object]Value),
+	}
+	for _, n := range ppn.GetBlockNames() {
+		if !isCarriedVar(store, ppn, n) {
+			continue
+		}
+		if _, ok := pn.GetLocalIndex(n); !ok {
+			continue // dropped.
+		}
+		if !isCarriedVar(store, pn, n) {
+			return nil, errors.New("cannot carry over var %s: not a var of %s", n, pv.PkgPath)
+		}
+		pt, err := vc.types.mapType(ppn.GetStaticTypeOf(store, n))
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot carry over var %s", n)
+		}
+		if t := pn.GetStaticTypeOf(store, n); pt.TypeID() != t.TypeID() {
+			return nil, errors.New("cannot carry over var %s: type changed from %s to %s",
+				n, pt.String(), t.String())
+		}
+		pidx, _ := ppn.GetLocalIndex(n)
+		idx, _ := pn.GetLocalIndex(n)
+		ptv := pblock.GetPointerToInt(store, int(pidx)).Deref()
+		tv, err := vc.copyValue(ptv)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot carry over var %s", n)
+		}
+		ptr := block.GetPointerToInt(store, int(idx))
+		ptr.Assign2(m.Alloc, store, pv.GetRealm(), tv, false)
+		names = append(names, n)
+	}
+	if rlm := pv.GetRealm(); rlm != nil {
+		rlm.FinalizeRealmTransaction(m.ReadOnly, store)
+	}
+	return names, nil
+}
+
+// Returns true if n is declared as a variable (rather than a const, func or
+// type) in the package block of pn.
+func isCarriedVar(store Store, pn *PackageNode, n Name) bool {
+	if _, ok := pn.GetLocalIndex(n); !ok || n == "_" {
+		return false
+	}
+	if pn.GetIsConst(store, n) {
+		return false
+	}
+	switch pn.GetStaticTypeOf(store, n).Kind() {
+	case FuncKind, TypeKind:
+		return false
+	default:
+		return true
+	}
+}
+
+// typeMapper maps the types of a package to those of its next version: the
+// types declared in the package to the types declared in the next version
+// with the same names, and the types composed of them accordingly.  Other
+// types are left as is.
+type typeMapper struct {
+	store    Store
+	prevPath string
+	pv       *PackageValue   // of the next version
+	mapped   map[TypeID]Type // declared type -> that of the next version
+}
+
+func (tm *typeMapper) mapType(t Type) (Type, error) {
+	switch ct := t.(type) {
+	case nil, PrimitiveType, *NativeType, *TypeType, *PackageType:
+		return t, nil
+	case RefType:
+		return tm.mapType(tm.store.GetType(ct.ID))
+	case *DeclaredType:
+		if ct.PkgPath != tm.prevPath {
+			return ct, nil
+		}
+		if mt, ok := tm.mapped[ct.TypeID()]; ok {
+			return mt, nil
+		}
+		dt := tm.declaredType(ct.Name)
+		if dt == nil {
+			return nil, errors.New("type %s is not declared in %s", ct.Name, tm.pv.PkgPath)
+		}
+		// map before the base, for recursive types.
+		tm.mapped[ct.TypeID()] = dt
+		base, err := tm.mapType(ct.Base)
+		if err != nil {
+			return nil, err
+		}
+		if base.TypeID() != dt.Base.TypeID() {
+			return nil, errors.New("type %s changed from %s to %s",
+				ct.Name, base.String(), dt.Base.String())
+		}
+		return dt, nil
+	case *PointerType:
+		elt, err := tm.mapType(ct.Elt)
+		if err != nil || elt == ct.Elt {
+			return ct, err
+		}
+		return &PointerType{Elt: elt}, nil
+	case *ArrayType:
+		elt, err := tm.mapType(ct.Elt)
+		if err != nil || elt == ct.Elt {
+			return ct, err
+		}
+		return &ArrayType{Len: ct.Len, Elt: elt, Vrd: ct.Vrd}, nil
+	case *SliceType:
+		elt, err := tm.mapType(ct.Elt)
+		if err != nil || elt == ct.Elt {
+			return ct, err
+		}
+		return &SliceType{Elt: elt, Vrd: ct.Vrd}, nil
+	case *ChanType:
+		elt, err := tm.mapType(ct.Elt)
+		if err != nil || elt == ct.Elt {
+			return ct, err
+		}
+		return &ChanType{Dir: ct.Dir, Elt: elt}, nil
+	case *MapType:
+		key, err := tm.mapType(ct.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := tm.mapType(ct.Value)
+		if err != nil {
+			return nil, err
+		}
+		if key == ct.Key && value == ct.Value {
+			return ct, nil
+		}
+		return &MapType{Key: key, Value: value}, nil
+	case *StructType:
+		fields, changed, err := tm.mapFields(ct.Fields)
+		if err != nil {
+			return nil, err
+		}
+		if !changed && ct.PkgPath != tm.prevPath {
+			return ct, nil
+		}
+		return &StructType{PkgPath: tm.mapPkgPath(ct.PkgPath), Fields: fields}, nil
+	case *InterfaceType:
+		methods, changed, err := tm.mapFields(ct.Methods)
+		if err != nil {
+			return nil, err
+		}
+		if !changed && ct.PkgPath != tm.prevPath {
+			return ct, nil
+		}
+		return &InterfaceType{PkgPath: tm.mapPkgPath(ct.PkgPath), Methods: methods, Generic: ct.Generic}, nil
+	case *FuncType:
+		params, pchanged, err := tm.mapFields(ct.Params)
+		if err != nil {
+			return nil, err
+		}
+		results, rchanged, err := tm.mapFields(ct.Results)
+		if err != nil {
+			return nil, err
+		}
+		if !pchanged && !rchanged {
+			return ct, nil
+		}
+		return &FuncType{Params: params, Results: results}, nil
+	default:
+		return nil, errors.New("cannot map type %s", t.String())
+	}
+}
+
+// Returns the fields with their types mapped, and whether any changed.
+func (tm *typeMapper) mapFields(fields []FieldType) ([]FieldType, bool, error) {
+	mapped := make([]FieldType, len(fields))
+	changed := false
+	for i, ft := range fields {
+		t, err := tm.mapType(ft.Type)
+		if err != nil {
+			return nil, false, err
+		}
+		if t != ft.Type {
+			changed = true
+		}
+		ft.Type = t
+		mapped[i] = ft
+	}
+	return mapped, changed, nil
+}
+
+func (tm *typeMapper) mapPkgPath(pkgPath string) string {
+	if pkgPath == tm.prevPath {
+		return tm.pv.PkgPath
+	}
+	return pkgPath
+}
+
+// Returns the type declared with name n in the package block of the next
+// version, or nil.
+func (tm *typeMapper) declaredType(n Name) *DeclaredType {
+	pn := tm.pv.GetPackageNode(tm.store)
+	idx, ok := pn.GetLocalIndex(n)
+	if !ok {
+		return nil
+	}
+	tv := tm.pv.GetBlock(tm.store).GetPointerToInt(tm.store, int(idx)).Deref()
+	if tv.T == nil || tv.T.Kind() != TypeKind {
+		return nil
+	}
+	dt, _ := tv.GetType().(*DeclaredType)
+	return dt
+}
+
+// varCopier deep-copies values into new (unreal) objects, with their types
+// mapped to those of the next version.  Objects referenced more than once
+// are copied once, so that the copy has the same shape as the original,
+// cycles included.
+type varCopier struct {
+	store  Store
+	alloc  *Allocator
+	types  *typeMapper
+	copies map[Object]Value // original -> copy
+}
+
+func (vc *varCopier) copyValue(tv TypedValue) (TypedValue, error) {
+	fillValueTV(vc.store, &tv)
+	t, err := vc.types.mapType(tv.T)
+	if err != nil {
+		return TypedValue{}, err
+	}
+	tv.T = t
+	switch cv := tv.V.(type) {
+	case nil, StringValue, BigintValue, BigdecValue:
+		return tv, nil
+	case TypeValue:
+		vt, err := vc.types.mapType(cv.Type)
+		if err != nil {
+			return TypedValue{}, err
+		}
+		tv.V = toTypeValue(vt)
+		return tv, nil
+	case *ArrayValue, *StructValue, *MapValue:
+		cp, err := vc.copyObject(cv.(Object))
+		if err != nil {
+			return TypedValue{}, err
+		}
+		tv.V = cp
+		return tv, nil
+	case *SliceValue:
+		base := cv.GetBase(vc.store)
+		if base == nil {
+			return tv, nil
+		}
+		cp, err := vc.copyObject(base)
+		if err != nil {
+			return TypedValue{}, err
+		}
+		tv.V = &SliceValue{
+			Base:   cp,
+			Offset: cv.Offset,
+			Length: cv.Length,
+			Maxcap: cv.Maxcap,
+		}
+		return tv, nil
+	case PointerValue:
+		ptr, err := vc.copyPointer(cv)
+		if err != nil {
+			return TypedValue{}, err
+		}
+		tv.V = ptr
+		return tv, nil
+	default:
+		return TypedValue{}, errors.New("cannot copy value of type %s", tv.T.String())
+	}
+}
+
+func (vc *varCopier) copyPointer(pv PointerValue) (PointerValue, error) {
+	if pv.Base == nil { // e.g. &Struct{}
+		etv, err := vc.copyValue(*pv.TV)
+		if err != nil {
+			return PointerValue{}, err
+		}
+		return PointerValue{TV: &etv}, nil
+	}
+	base, ok := pv.Base.(Object)
+	if !ok {
+		return PointerValue{}, errors.New("cannot copy pointer to %s", pv.TV.T.String())
+	}
+	if _, ok := base.(*Block); ok {
+		return PointerValue{}, errors.New("cannot copy pointer to variable")
+	}
+	cp, err := vc.copyObject(base)
+	if err != nil {
+		return PointerValue{}, err
+	}
+	switch cb := cp.(type) {
+	case *ArrayValue:
+		var et Type
+		if dbv, ok := pv.TV.V.(DataByteValue); ok {
+			var err error
+			if et, err = vc.types.mapType(dbv.ElemType); err != nil {
+				return PointerValue{}, err
+			}
+		}
+		return cb.GetPointerAtIndexInt2(vc.store, pv.Index, et), nil
+	case *StructValue:
+		return cb.GetPointerToInt(vc.store, pv.Index), nil
+	case *MapValue:
+		key, err := vc.copyValue(*pv.Key)
+		if err != nil {
+			return PointerValue{}, err
+		}
+		return cb.GetPointerForKey(vc.alloc, vc.store, &key), nil
+	default:
+		panic("should not happen")
+	}
+}
+
+func (vc *varCopier) copyObject(oo Object) (Value, error) {
+	if cp, ok := vc.copies[oo]; ok {
+		return cp, nil
+	}
+	switch cv := oo.(type) {
+	case *ArrayValue:
+		if cv.Data != nil {
+			av := vc.alloc.NewDataArray(len(cv.Data))
+			copy(av.Data, cv.Data)
+			vc.copies[oo] = av
+			return av, nil
+		}
+		av := vc.alloc.NewListArray(len(cv.List))
+		vc.copies[oo] = av
+		for i := range cv.List {
+			etv, err := vc.copyValue(cv.List[i])
+			if err != nil {
+				return nil, err
+			}
+			av.List[i] = etv
+		}
+		return av, nil
+	case *StructValue:
+		sv := vc.alloc.NewStruct(vc.alloc.NewStructFields(len(cv.Fields)))
+		vc.copies[oo] = sv
+		for i := range cv.Fields {
+			ftv, err := vc.copyValue(cv.Fields[i])
+			if err != nil {
+				return nil, err
+			}
+			sv.Fields[i] = ftv
+		}
+		return sv, nil
+	case *MapValue:
+		mv := vc.alloc.NewMap(cv.GetLength())
+		vc.copies[oo] = mv
+		for cur := cv.List.Head; cur != nil; cur = cur.Next {
+			key, err := vc.copyValue(cur.Key)
+			if err != nil {
+				return nil, err
+			}
+			val, err := vc.copyValue(cur.Value)
+			if err != nil {
+				return nil, err
+			}
+			ptr := mv.GetPointerForKey(vc.alloc, vc.store, &key)
+			*ptr.TV = val
+		}
+		return mv, nil
+	default:
+		return nil, errors.New("cannot copy object of type %T", oo)
+	}
+}
@@ -640,6 +640,12 @@ type MapValue struct {
 
 type MapKey string
 
+// MapList holds the items of a map in insertion order, which is the order of
+// iteration for range statements.  Unlike Go, map iteration in Gno is
+// deterministic, as it must be for consensus.  A key that is deleted and
+// inserted again is appended to the end.  Items deleted during iteration
+// that have not yet been reached are not produced, and items inserted during
+// iteration are produced unless the last item was already reached.
 type MapList struct {
 	Head *MapListItem
 	Tail *MapListItem
@@ -663,9 +669,10 @@ func (ml *MapList) UnmarshalAmino(mlimg MapListImage) error {
 		if i == 0 {
 			// init case
 			ml.Head = item
+		} else {
+			ml.Tail.Next = item
 		}
 		item.Prev = ml.Tail
-		ml.Tail.Next = item
 		ml.Tail = item
 		ml.Size++
 	}
@@ -707,6 +714,7 @@ func (ml *MapList) Remove(mli *MapListItem) {
 		next.Prev = prev
 	}
 	ml.Size--
+	mli.removed = true
 }
 
 type MapListItem struct {
@@ -714,6 +722,19 @@ type MapListItem struct {
 	Next  *MapListItem `json:"-"`
 	Key   TypedValue
 	Value TypedValue
+
+	removed bool // true if removed from list
+}
+
+// Returns the next item in the list that has not been removed.  Since
+// removed items keep their Next pointer, this is safe to call on a removed
+// item, e.g. when the current item of a range statement is deleted.
+func (mli *MapListItem) NextValid() *MapListItem {
+	next := mli.Next
+	for next != nil && next.removed {
+		next = next.Next
+	}
+	return next
 }
 
 func (mv *MapValue) MakeMap(c int) {
@@ -2364,6 +2385,15 @@ func (b *Block) GetParams3() (pv1, pv2, pv3 PointerValue) {
 	return
 }
 
+// Convenience for implementing nativeBody functions.
+func (b *Block) GetParams4() (pv1, pv2, pv3, pv4 PointerValue) {
+	pv1 = b.GetPointerTo(nil, NewValuePathBlock(1, 0, ""))
+	pv2 = b.GetPointerTo(nil, NewValuePathBlock(1, 1, ""))
+	pv3 = b.GetPointerTo(nil, NewValuePathBlock(1, 2, ""))
+	pv4 = b.GetPointerTo(nil, NewValuePathBlock(1, 3, ""))
+	return
+}
+
 func (b *Block) GetBodyStmt() *bodyStmt {
 	return &b.bodyStmt
 }